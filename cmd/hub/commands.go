@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/artifacthub/hub/internal/backup"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/pkg"
+	"github.com/artifacthub/hub/internal/secrets"
+	"github.com/artifacthub/hub/internal/user"
+	"github.com/rs/zerolog/log"
+)
+
+// runCommand runs the hub command provided in the arguments. The supported
+// commands are backup and restore, which accept a -file flag to set the
+// backup file to use (standard output or input are used when not provided),
+// rotate-secrets, which encrypts again the sensitive data stored using the
+// current secrets key, passwords-report, which reports how many passwords
+// are still hashed using legacy parameters, and restore-packages, which
+// registers again the package versions unregistered from the repository set
+// in the -repository flag that have not been pruned yet (optionally only the
+// ones of the package set in the -package flag).
+func runCommand(db hub.DB, sc *secrets.Cipher, um *user.Manager, pm *pkg.Manager, args []string) error {
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	file := fs.String("file", "", "backup file path (defaults to standard output or input)")
+	repoName := fs.String("repository", "", "name of the repository whose packages will be restored")
+	pkgName := fs.String("package", "", "name of the package to restore (defaults to all)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "backup":
+		if *file == "" {
+			if err := backup.Backup(ctx, db, os.Stdout); err != nil {
+				return err
+			}
+		} else {
+			f, err := os.OpenFile(*file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+			if err != nil {
+				return err
+			}
+			err = backup.Backup(ctx, db, f)
+			if cErr := f.Close(); err == nil {
+				err = cErr
+			}
+			if err != nil {
+				os.Remove(*file)
+				return err
+			}
+		}
+		log.Info().Str("file", *file).Msg("backup completed")
+	case "restore":
+		var r io.Reader = os.Stdin
+		if *file != "" {
+			f, err := os.Open(*file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+		if err := backup.Restore(ctx, db, r); err != nil {
+			return err
+		}
+		log.Info().Str("file", *file).Msg("restore completed")
+	case "rotate-secrets":
+		if sc == nil {
+			return errors.New("secrets encryption is not enabled")
+		}
+		n, err := secrets.Rotate(ctx, db, sc)
+		if err != nil {
+			return err
+		}
+		log.Info().Int64("updated", n).Msg("secrets rotation completed")
+	case "passwords-report":
+		n, err := um.CountLegacyPasswords(ctx)
+		if err != nil {
+			return err
+		}
+		log.Info().Int64("legacy", n).Msg("passwords hashed using legacy parameters")
+	case "restore-packages":
+		n, err := pm.RestoreUnregistered(ctx, *repoName, *pkgName)
+		if err != nil {
+			return err
+		}
+		log.Info().Str("repository", *repoName).Int64("restored", n).Msg("packages versions restored")
+	default:
+		return fmt.Errorf("unknown command: %s", args[0])
+	}
+	return nil
+}
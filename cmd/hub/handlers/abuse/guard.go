@@ -0,0 +1,274 @@
+package abuse
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/ulule/limiter/v3"
+	"github.com/ulule/limiter/v3/drivers/store/memory"
+)
+
+// Actions that can be protected by the guard.
+const (
+	Signup               = "signup"
+	OrganizationCreation = "organizationCreation"
+	RepositoryAddition   = "repositoryAddition"
+)
+
+// CaptchaTokenHeader represents the header used to provide the CAPTCHA
+// response token obtained by the client.
+const CaptchaTokenHeader = "X-Captcha-Token"
+
+// actions represents the list of actions that can be protected.
+var actions = []string{Signup, OrganizationCreation, RepositoryAddition}
+
+var (
+	// errLimitReached indicates that the velocity limit of the action has
+	// been reached.
+	errLimitReached = errors.New("too many requests, please try again later")
+
+	// errVerificationFailed indicates that the request could not be verified
+	// by the CAPTCHA or webhook hooks.
+	errVerificationFailed = errors.New("verification failed")
+)
+
+// HTTPClient defines the methods an HTTPClient implementation must provide.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// hook represents the configuration of a verification hook.
+type hook struct {
+	url     string
+	secret  string
+	actions map[string]bool
+}
+
+// WebhookPayload represents the payload sent to the verification webhook.
+type WebhookPayload struct {
+	Action    string `json:"action"`
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+	UserID    string `json:"user_id,omitempty"`
+}
+
+// Guard provides a middleware to protect some actions (like signing up or
+// creating organizations) from automated abuse. Requests can be subject to
+// velocity limits per IP and user, and optionally have to be verified by a
+// CAPTCHA provider and/or an external webhook.
+type Guard struct {
+	hc       HTTPClient
+	logger   zerolog.Logger
+	limiters map[string]*limiter.Limiter
+	captcha  *hook
+	webhook  *hook
+}
+
+// NewGuard creates a new Guard instance using the configuration provided.
+func NewGuard(cfg *viper.Viper, hc HTTPClient) (*Guard, error) {
+	g := &Guard{
+		hc:       hc,
+		logger:   util.LogWith("handlers").Str("handlers", "abuse").Logger(),
+		limiters: make(map[string]*limiter.Limiter),
+	}
+
+	// Velocity limits
+	for _, action := range actions {
+		formattedRate := cfg.GetString("server.abuse.limits." + action)
+		if formattedRate == "" {
+			continue
+		}
+		rate, err := limiter.NewRateFromFormatted(formattedRate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s limit: %w", action, err)
+		}
+		g.limiters[action] = limiter.New(memory.NewStore(), rate)
+	}
+
+	// Verification hooks
+	var err error
+	if cfg.GetBool("server.abuse.captcha.enabled") {
+		g.captcha, err = newHook(cfg, "server.abuse.captcha", "verifyURL")
+		if err != nil {
+			return nil, fmt.Errorf("invalid captcha configuration: %w", err)
+		}
+	}
+	if cfg.GetBool("server.abuse.webhook.enabled") {
+		g.webhook, err = newHook(cfg, "server.abuse.webhook", "url")
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook configuration: %w", err)
+		}
+	}
+
+	return g, nil
+}
+
+// newHook creates a new hook instance from the configuration provided. Hooks
+// apply to all actions when no actions are configured.
+func newHook(cfg *viper.Viper, prefix, urlKey string) (*hook, error) {
+	h := &hook{
+		url:     cfg.GetString(prefix + "." + urlKey),
+		secret:  cfg.GetString(prefix + ".secret"),
+		actions: make(map[string]bool),
+	}
+	u, err := url.Parse(h.url)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid url: %s", h.url)
+	}
+	configuredActions := cfg.GetStringSlice(prefix + ".actions")
+	if len(configuredActions) == 0 {
+		configuredActions = actions
+	}
+	for _, action := range configuredActions {
+		if !isValidAction(action) {
+			return nil, fmt.Errorf("invalid action: %s", action)
+		}
+		h.actions[action] = true
+	}
+	return h, nil
+}
+
+// Protect is an http middleware that protects the action provided. Requests
+// exceeding the action's velocity limits, or that cannot be verified by the
+// hooks configured, are rejected.
+func (g *Guard) Protect(action string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := helpers.GetRemoteIP(r)
+			userID, _ := r.Context().Value(hub.UserIDKey).(string)
+
+			// Check velocity limits
+			if l, ok := g.limiters[action]; ok {
+				keys := []string{"ip:" + ip}
+				if userID != "" {
+					keys = append(keys, "user:"+userID)
+				}
+				for _, key := range keys {
+					lctx, err := l.Get(r.Context(), key)
+					if err != nil {
+						g.logger.Error().Err(err).Str("method", "Protect").Str("action", action).Send()
+						helpers.RenderErrorWithCodeJSON(w, nil, http.StatusInternalServerError)
+						return
+					}
+					if lctx.Reached {
+						retryAfter := lctx.Reset - time.Now().Unix()
+						w.Header().Set("Retry-After", strconv.FormatInt(retryAfter, 10))
+						helpers.RenderErrorWithCodeJSON(w, errLimitReached, http.StatusTooManyRequests)
+						return
+					}
+				}
+			}
+
+			// Run verification hooks
+			if g.captcha != nil && g.captcha.actions[action] {
+				verified, err := g.verifyCaptcha(r, ip)
+				if err != nil {
+					g.logger.Error().Err(err).Str("method", "verifyCaptcha").Str("action", action).Send()
+					helpers.RenderErrorWithCodeJSON(w, nil, http.StatusInternalServerError)
+					return
+				}
+				if !verified {
+					helpers.RenderErrorWithCodeJSON(w, errVerificationFailed, http.StatusForbidden)
+					return
+				}
+			}
+			if g.webhook != nil && g.webhook.actions[action] {
+				payload := &WebhookPayload{
+					Action:    action,
+					IP:        ip,
+					UserAgent: r.UserAgent(),
+					UserID:    userID,
+				}
+				verified, err := g.verifyWebhook(r, payload)
+				if err != nil {
+					g.logger.Error().Err(err).Str("method", "verifyWebhook").Str("action", action).Send()
+					helpers.RenderErrorWithCodeJSON(w, nil, http.StatusInternalServerError)
+					return
+				}
+				if !verified {
+					helpers.RenderErrorWithCodeJSON(w, errVerificationFailed, http.StatusForbidden)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// verifyCaptcha verifies the CAPTCHA token provided in the request using the
+// provider's verification endpoint. The reCAPTCHA siteverify protocol, which
+// is also supported by alternatives like hCaptcha, is used.
+func (g *Guard) verifyCaptcha(r *http.Request, ip string) (bool, error) {
+	token := r.Header.Get(CaptchaTokenHeader)
+	if token == "" {
+		return false, nil
+	}
+	data := url.Values{}
+	data.Set("secret", g.captcha.secret)
+	data.Set("response", token)
+	data.Set("remoteip", ip)
+	req, _ := http.NewRequestWithContext(r.Context(), http.MethodPost, g.captcha.url, strings.NewReader(data.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := g.hc.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	var result struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, err
+	}
+	return result.Success, nil
+}
+
+// verifyWebhook asks the verification webhook whether the request described
+// in the payload provided should be allowed. Requests are allowed when the
+// webhook replies with a 2xx status code and denied when it replies with a
+// 4xx one.
+func (g *Guard) verifyWebhook(r *http.Request, payload *WebhookPayload) (bool, error) {
+	payloadJSON, _ := json.Marshal(payload)
+	req, _ := http.NewRequestWithContext(r.Context(), http.MethodPost, g.webhook.url, bytes.NewReader(payloadJSON))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-ArtifactHub-Secret", g.webhook.secret)
+	resp, err := g.hc.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return true, nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+}
+
+// isValidAction checks if the action provided is valid.
+func isValidAction(action string) bool {
+	for _, a := range actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,293 @@
+package abuse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var errFake = errors.New("fake error for tests")
+
+func TestMain(m *testing.M) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	os.Exit(m.Run())
+}
+
+func TestNewGuard(t *testing.T) {
+	testCases := []struct {
+		description string
+		cfg         map[string]interface{}
+		errMsg      string
+	}{
+		{
+			"invalid limit",
+			map[string]interface{}{
+				"server.abuse.limits.signup": "invalid",
+			},
+			"invalid signup limit",
+		},
+		{
+			"invalid captcha url",
+			map[string]interface{}{
+				"server.abuse.captcha.enabled": true,
+			},
+			"invalid captcha configuration",
+		},
+		{
+			"invalid webhook action",
+			map[string]interface{}{
+				"server.abuse.webhook.enabled": true,
+				"server.abuse.webhook.url":     "https://webhook.url",
+				"server.abuse.webhook.actions": []string{"invalid"},
+			},
+			"invalid webhook configuration: invalid action",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			cfg := viper.New()
+			for k, v := range tc.cfg {
+				cfg.Set(k, v)
+			}
+			_, err := NewGuard(cfg, nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errMsg)
+		})
+	}
+}
+
+func TestProtect(t *testing.T) {
+	t.Run("nothing configured, request allowed", func(t *testing.T) {
+		g, err := NewGuard(viper.New(), nil)
+		require.NoError(t, err)
+
+		resp := doRequest(g, Signup, "", nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("velocity limit reached", func(t *testing.T) {
+		cfg := viper.New()
+		cfg.Set("server.abuse.limits.organizationCreation", "2-H")
+		g, err := NewGuard(cfg, nil)
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			resp := doRequest(g, OrganizationCreation, "", nil)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+		resp := doRequest(g, OrganizationCreation, "", nil)
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.NotEmpty(t, resp.Header.Get("Retry-After"))
+
+		// Other actions are not affected
+		resp = doRequest(g, RepositoryAddition, "", nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("velocity limit reached by user from different ips", func(t *testing.T) {
+		cfg := viper.New()
+		cfg.Set("server.abuse.limits.repositoryAddition", "1-H")
+		g, err := NewGuard(cfg, nil)
+		require.NoError(t, err)
+
+		ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+		r, _ := http.NewRequest("POST", "/", nil)
+		r.RemoteAddr = "1.1.1.1:"
+		resp := serve(g, RepositoryAddition, r.WithContext(ctx))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		r, _ = http.NewRequest("POST", "/", nil)
+		r.RemoteAddr = "2.2.2.2:"
+		resp = serve(g, RepositoryAddition, r.WithContext(ctx))
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	})
+
+	t.Run("captcha", func(t *testing.T) {
+		cfg := viper.New()
+		cfg.Set("server.abuse.captcha.enabled", true)
+		cfg.Set("server.abuse.captcha.verifyURL", "https://captcha.url/siteverify")
+		cfg.Set("server.abuse.captcha.secret", "secret")
+		cfg.Set("server.abuse.captcha.actions", []string{Signup})
+
+		t.Run("token not provided", func(t *testing.T) {
+			hc := &httpClientMock{}
+			g, err := NewGuard(cfg, hc)
+			require.NoError(t, err)
+
+			resp := doRequest(g, Signup, "", nil)
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+			hc.AssertExpectations(t)
+		})
+
+		t.Run("action not protected by captcha", func(t *testing.T) {
+			hc := &httpClientMock{}
+			g, err := NewGuard(cfg, hc)
+			require.NoError(t, err)
+
+			resp := doRequest(g, OrganizationCreation, "", nil)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			hc.AssertExpectations(t)
+		})
+
+		testCases := []struct {
+			description        string
+			providerResp       *http.Response
+			providerErr        error
+			expectedStatusCode int
+		}{
+			{
+				"token verified",
+				newResponse(http.StatusOK, `{"success": true}`),
+				nil,
+				http.StatusOK,
+			},
+			{
+				"token not verified",
+				newResponse(http.StatusOK, `{"success": false}`),
+				nil,
+				http.StatusForbidden,
+			},
+			{
+				"error calling provider",
+				nil,
+				errFake,
+				http.StatusInternalServerError,
+			},
+			{
+				"unexpected provider status code",
+				newResponse(http.StatusServiceUnavailable, ""),
+				nil,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.description, func(t *testing.T) {
+				hc := &httpClientMock{}
+				hc.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+					_ = req.ParseForm()
+					return req.URL.String() == "https://captcha.url/siteverify" &&
+						req.PostForm.Get("secret") == "secret" &&
+						req.PostForm.Get("response") == "token" &&
+						req.PostForm.Get("remoteip") == "1.1.1.1"
+				})).Return(tc.providerResp, tc.providerErr)
+				g, err := NewGuard(cfg, hc)
+				require.NoError(t, err)
+
+				resp := doRequest(g, Signup, "token", nil)
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hc.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("webhook", func(t *testing.T) {
+		cfg := viper.New()
+		cfg.Set("server.abuse.webhook.enabled", true)
+		cfg.Set("server.abuse.webhook.url", "https://webhook.url")
+		cfg.Set("server.abuse.webhook.secret", "secret")
+
+		testCases := []struct {
+			description        string
+			webhookResp        *http.Response
+			webhookErr         error
+			expectedStatusCode int
+		}{
+			{
+				"request allowed",
+				newResponse(http.StatusNoContent, ""),
+				nil,
+				http.StatusOK,
+			},
+			{
+				"request denied",
+				newResponse(http.StatusForbidden, ""),
+				nil,
+				http.StatusForbidden,
+			},
+			{
+				"error calling webhook",
+				nil,
+				errFake,
+				http.StatusInternalServerError,
+			},
+			{
+				"unexpected webhook status code",
+				newResponse(http.StatusInternalServerError, ""),
+				nil,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.description, func(t *testing.T) {
+				hc := &httpClientMock{}
+				hc.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+					var payload *WebhookPayload
+					_ = json.NewDecoder(req.Body).Decode(&payload)
+					return req.URL.String() == "https://webhook.url" &&
+						req.Header.Get("X-ArtifactHub-Secret") == "secret" &&
+						payload.Action == RepositoryAddition &&
+						payload.IP == "1.1.1.1" &&
+						payload.UserAgent == "test"
+				})).Return(tc.webhookResp, tc.webhookErr)
+				g, err := NewGuard(cfg, hc)
+				require.NoError(t, err)
+
+				resp := doRequest(g, RepositoryAddition, "", map[string]string{"User-Agent": "test"})
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hc.AssertExpectations(t)
+			})
+		}
+	})
+}
+
+func doRequest(g *Guard, action, captchaToken string, headers map[string]string) *http.Response {
+	r, _ := http.NewRequest("POST", "/", nil)
+	r.RemoteAddr = "1.1.1.1:"
+	if captchaToken != "" {
+		r.Header.Set(CaptchaTokenHeader, captchaToken)
+	}
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+	return serve(g, action, r)
+}
+
+func serve(g *Guard, action string, r *http.Request) *http.Response {
+	w := httptest.NewRecorder()
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	g.Protect(action)(next).ServeHTTP(w, r)
+	return w.Result()
+}
+
+func newResponse(code int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: code,
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}
+}
+
+type httpClientMock struct {
+	mock.Mock
+}
+
+func (m *httpClientMock) Do(req *http.Request) (*http.Response, error) {
+	args := m.Called(req)
+	resp, _ := args.Get(0).(*http.Response)
+	return resp, args.Error(1)
+}
@@ -0,0 +1,199 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/rs/zerolog"
+)
+
+// Handlers represents a group of http handlers in charge of handling the
+// administration and diagnostics operations. They are served on a separate
+// address, which should not be publicly accessible, and require
+// authentication.
+type Handlers struct {
+	username        string
+	password        string
+	adoptionManager hub.AdoptionManager
+	repoManager     hub.RepositoryManager
+	startedAt       time.Time
+	logger          zerolog.Logger
+	Router          http.Handler
+}
+
+// NewHandlers creates a new Handlers instance that requires the admin
+// credentials provided.
+func NewHandlers(username, password string, opts ...func(h *Handlers)) (*Handlers, error) {
+	if username == "" || password == "" {
+		return nil, errors.New("admin credentials not provided")
+	}
+	h := &Handlers{
+		username:  username,
+		password:  password,
+		startedAt: time.Now(),
+		logger:    util.LogWith("handlers").Str("handlers", "admin").Logger(),
+	}
+	for _, o := range opts {
+		o(h)
+	}
+	h.setupRouter()
+	return h, nil
+}
+
+// WithAdoptionManager allows providing an AdoptionManager instance, which
+// enables the packages adoption related admin handlers.
+func WithAdoptionManager(am hub.AdoptionManager) func(h *Handlers) {
+	return func(h *Handlers) {
+		h.adoptionManager = am
+	}
+}
+
+// WithRepositoryManager allows providing a RepositoryManager instance, which
+// enables the repositories related admin handlers.
+func WithRepositoryManager(rm hub.RepositoryManager) func(h *Handlers) {
+	return func(h *Handlers) {
+		h.repoManager = rm
+	}
+}
+
+// setupRouter initializes the admin handlers router.
+func (h *Handlers) setupRouter() {
+	r := chi.NewRouter()
+	r.Use(h.BasicAuth)
+	r.Get("/build-info", h.GetBuildInfo)
+	r.Get("/log-levels", h.GetLogLevels)
+	r.Put("/log-levels/{component}", h.SetLogLevel)
+	if h.adoptionManager != nil {
+		r.Get("/adoption-requests", h.GetAdoptionRequests)
+	}
+	if h.repoManager != nil {
+		r.Get("/repositories", h.GetRepositories)
+	}
+	r.Mount("/debug", middleware.Profiler())
+	h.Router = r
+}
+
+// BasicAuth is a middleware that requires the admin credentials to be
+// provided using http basic authentication.
+func (h *Handlers) BasicAuth(next http.Handler) http.Handler {
+	validUser := []byte(h.username)
+	validPass := []byte(h.password)
+	realm := "Artifact Hub admin"
+
+	areCredentialsValid := func(user, pass []byte) bool {
+		if subtle.ConstantTimeCompare(user, validUser) != 1 {
+			return false
+		}
+		if subtle.ConstantTimeCompare(pass, validPass) != 1 {
+			return false
+		}
+		return true
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || !areCredentialsValid([]byte(user), []byte(pass)) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="`+realm+`"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte("Unauthorized\n"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// GetAdoptionRequests is an http handler that returns all the pending packages
+// adoption requests.
+func (h *Handlers) GetAdoptionRequests(w http.ResponseWriter, r *http.Request) {
+	dataJSON, err := h.adoptionManager.GetAllJSON(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetAdoptionRequests").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
+// GetBuildInfo is an http handler that returns some information about the
+// binary running and the runtime.
+func (h *Handlers) GetBuildInfo(w http.ResponseWriter, r *http.Request) {
+	info := map[string]interface{}{
+		"go_version": runtime.Version(),
+		"os":         runtime.GOOS,
+		"arch":       runtime.GOARCH,
+		"cpus":       runtime.NumCPU(),
+		"goroutines": runtime.NumGoroutine(),
+		"started_at": h.startedAt.Unix(),
+		"uptime":     time.Since(h.startedAt).Round(time.Second).String(),
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info["path"] = bi.Path
+		info["version"] = bi.Main.Version
+		deps := make(map[string]string, len(bi.Deps))
+		for _, dep := range bi.Deps {
+			deps[dep.Path] = dep.Version
+		}
+		info["deps"] = deps
+	}
+	dataJSON, _ := json.Marshal(info)
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
+// GetLogLevels is an http handler that returns the current log level of each
+// of the components.
+func (h *Handlers) GetLogLevels(w http.ResponseWriter, r *http.Request) {
+	dataJSON, _ := json.Marshal(util.GetLogLevels())
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
+// GetRepositories is an http handler that returns the repositories whose
+// metadata contains all the key/values provided in the query string.
+func (h *Handlers) GetRepositories(w http.ResponseWriter, r *http.Request) {
+	metadata := make(map[string]string)
+	for k, v := range r.URL.Query() {
+		metadata[k] = v[0]
+	}
+	dataJSON, err := h.repoManager.GetByMetadataJSON(r.Context(), metadata)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetRepositories").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
+// SetLogLevel is an http handler that sets the log level of the component
+// provided.
+func (h *Handlers) SetLogLevel(w http.ResponseWriter, r *http.Request) {
+	component := chi.URLParam(r, "component")
+	input := &struct {
+		Level string `json:"level"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Error().Err(err).Str("method", "SetLogLevel").Msg("invalid input")
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
+		return
+	}
+	level, err := zerolog.ParseLevel(input.Level)
+	if err != nil || input.Level == "" {
+		helpers.RenderErrorJSON(w, r, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid level"))
+		return
+	}
+	if err := util.SetLogLevel(component, level); err != nil {
+		helpers.RenderErrorWithCodeJSON(w, err, http.StatusNotFound)
+		return
+	}
+	h.logger.Info().Str("log_component", component).Str("level", level.String()).Msg("log level changed")
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,265 @@
+package admin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/adoption"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/repo"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	os.Exit(m.Run())
+}
+
+func TestNewHandlers(t *testing.T) {
+	_, err := NewHandlers("admin", "")
+	assert.Error(t, err)
+}
+
+func TestBasicAuth(t *testing.T) {
+	h := newHandlers(t)
+
+	testCases := []struct {
+		description        string
+		username           string
+		password           string
+		expectedStatusCode int
+	}{
+		{"credentials not provided", "", "", http.StatusUnauthorized},
+		{"invalid credentials", "admin", "invalid", http.StatusUnauthorized},
+		{"valid credentials", "admin", "pass", http.StatusOK},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest("GET", "/log-levels", nil)
+			if tc.username != "" {
+				r.SetBasicAuth(tc.username, tc.password)
+			}
+			h.Router.ServeHTTP(w, r)
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestGetAdoptionRequests(t *testing.T) {
+	t.Run("adoption manager not provided", func(t *testing.T) {
+		h := newHandlers(t)
+
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/adoption-requests", nil)
+		r.SetBasicAuth("admin", "pass")
+		h.Router.ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("error getting adoption requests", func(t *testing.T) {
+		am := &adoption.ManagerMock{}
+		am.On("GetAllJSON", mock.Anything).Return(nil, tests.ErrFakeDatabaseFailure)
+		h := newHandlers(t, WithAdoptionManager(am))
+
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/adoption-requests", nil)
+		r.SetBasicAuth("admin", "pass")
+		h.Router.ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		am.AssertExpectations(t)
+	})
+
+	t.Run("adoption requests returned successfully", func(t *testing.T) {
+		am := &adoption.ManagerMock{}
+		am.On("GetAllJSON", mock.Anything).Return([]byte("dataJSON"), nil)
+		h := newHandlers(t, WithAdoptionManager(am))
+
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/adoption-requests", nil)
+		r.SetBasicAuth("admin", "pass")
+		h.Router.ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		am.AssertExpectations(t)
+	})
+}
+
+func TestGetBuildInfo(t *testing.T) {
+	h := newHandlers(t)
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/build-info", nil)
+	r.SetBasicAuth("admin", "pass")
+	h.Router.ServeHTTP(w, r)
+	resp := w.Result()
+	defer resp.Body.Close()
+	data, _ := ioutil.ReadAll(resp.Body)
+	var info map[string]interface{}
+	err := json.Unmarshal(data, &info)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, runtime.Version(), info["go_version"])
+	assert.Contains(t, info, "goroutines")
+}
+
+func TestProfiler(t *testing.T) {
+	h := newHandlers(t)
+
+	t.Run("credentials required", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/debug/pprof/", nil)
+		h.Router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+	})
+
+	t.Run("goroutines dump", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/debug/pprof/goroutine?debug=2", nil)
+		r.SetBasicAuth("admin", "pass")
+		h.Router.ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, string(data), "goroutine")
+	})
+}
+
+func TestGetLogLevels(t *testing.T) {
+	h := newHandlers(t)
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/log-levels", nil)
+	r.SetBasicAuth("admin", "pass")
+	h.Router.ServeHTTP(w, r)
+	resp := w.Result()
+	defer resp.Body.Close()
+	data, _ := ioutil.ReadAll(resp.Body)
+	var levels map[string]string
+	err := json.Unmarshal(data, &levels)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.Contains(t, levels, util.DefaultLogComponent)
+	assert.Contains(t, levels, "handlers")
+}
+
+func TestGetRepositories(t *testing.T) {
+	t.Run("repository manager not provided", func(t *testing.T) {
+		h := newHandlers(t)
+
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/repositories?team=team1", nil)
+		r.SetBasicAuth("admin", "pass")
+		h.Router.ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		rm := &repo.ManagerMock{}
+		rm.On("GetByMetadataJSON", mock.Anything, map[string]string{}).Return(nil, hub.ErrInvalidInput)
+		h := newHandlers(t, WithRepositoryManager(rm))
+
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/repositories", nil)
+		r.SetBasicAuth("admin", "pass")
+		h.Router.ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		rm.AssertExpectations(t)
+	})
+
+	t.Run("repositories data returned successfully", func(t *testing.T) {
+		metadata := map[string]string{"team": "team1", "tier": "gold"}
+		rm := &repo.ManagerMock{}
+		rm.On("GetByMetadataJSON", mock.Anything, metadata).Return([]byte("dataJSON"), nil)
+		h := newHandlers(t, WithRepositoryManager(rm))
+
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/repositories?team=team1&tier=gold", nil)
+		r.SetBasicAuth("admin", "pass")
+		h.Router.ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		rm.AssertExpectations(t)
+	})
+}
+
+func TestSetLogLevel(t *testing.T) {
+	h := newHandlers(t)
+	defer zerolog.SetGlobalLevel(zerolog.Disabled)
+
+	testCases := []struct {
+		description        string
+		component          string
+		body               string
+		expectedStatusCode int
+	}{
+		{"invalid input", "handlers", "-", http.StatusBadRequest},
+		{"invalid level", "handlers", `{"level": "invalid"}`, http.StatusBadRequest},
+		{"level not provided", "handlers", `{}`, http.StatusBadRequest},
+		{"unknown component", "unknown", `{"level": "debug"}`, http.StatusNotFound},
+		{"log level set", "handlers", `{"level": "debug"}`, http.StatusNoContent},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest("PUT", "/log-levels/"+tc.component, strings.NewReader(tc.body))
+			r.SetBasicAuth("admin", "pass")
+			h.Router.ServeHTTP(w, r)
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+		})
+	}
+	assert.Equal(t, "debug", util.GetLogLevels()["handlers"])
+}
+
+func newHandlers(t *testing.T, opts ...func(h *Handlers)) *Handlers {
+	h, err := NewHandlers("admin", "pass", opts...)
+	require.NoError(t, err)
+	return h
+}
@@ -0,0 +1,83 @@
+package adoption
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/go-chi/chi"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+)
+
+// Handlers represents a group of http handlers in charge of handling packages
+// adoption operations.
+type Handlers struct {
+	adoptionManager hub.AdoptionManager
+	cfg             *viper.Viper
+	logger          zerolog.Logger
+}
+
+// NewHandlers creates a new Handlers instance.
+func NewHandlers(adoptionManager hub.AdoptionManager, cfg *viper.Viper) *Handlers {
+	return &Handlers{
+		adoptionManager: adoptionManager,
+		cfg:             cfg,
+		logger:          util.LogWith("handlers").Str("handlers", "adoption").Logger(),
+	}
+}
+
+// Add is an http handler that registers a request to adopt the provided
+// package.
+func (h *Handlers) Add(w http.ResponseWriter, r *http.Request) {
+	packageID := chi.URLParam(r, "packageID")
+	ar := &hub.AdoptionRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&ar); err != nil {
+		h.logger.Error().Err(err).Str("method", "Add").Msg("invalid adoption request")
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
+		return
+	}
+	baseURL := h.cfg.GetString("server.baseURL")
+	if err := h.adoptionManager.Add(r.Context(), packageID, ar, baseURL); err != nil {
+		h.logger.Error().Err(err).Str("method", "Add").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+// GetByPackage is an http handler that returns the adoption requests of the
+// provided package.
+func (h *Handlers) GetByPackage(w http.ResponseWriter, r *http.Request) {
+	packageID := chi.URLParam(r, "packageID")
+	dataJSON, err := h.adoptionManager.GetByPackageJSON(r.Context(), packageID)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetByPackage").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
+// SetSeekingMaintainers is an http handler that sets whether the provided
+// package is seeking new maintainers or not.
+func (h *Handlers) SetSeekingMaintainers(w http.ResponseWriter, r *http.Request) {
+	packageID := chi.URLParam(r, "packageID")
+	input := &struct {
+		SeekingMaintainers *bool `json:"seeking_maintainers"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.SeekingMaintainers == nil {
+		h.logger.Error().Err(err).Str("method", "SetSeekingMaintainers").Msg("invalid input")
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
+		return
+	}
+	err := h.adoptionManager.SetSeekingMaintainers(r.Context(), packageID, *input.SeekingMaintainers)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "SetSeekingMaintainers").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -5,14 +5,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/go-chi/chi"
 	"github.com/rs/zerolog"
-	"github.com/rs/zerolog/log"
 )
 
+// defaultUsageDays represents the default number of days the api keys usage
+// summary covers.
+const defaultUsageDays = 30
+
 // Handlers represents a group of http handlers in charge of handling api keys
 // operations.
 type Handlers struct {
@@ -24,7 +29,7 @@ type Handlers struct {
 func NewHandlers(apiKeyManager hub.APIKeyManager) *Handlers {
 	return &Handlers{
 		apiKeyManager: apiKeyManager,
-		logger:        log.With().Str("handlers", "apikey").Logger(),
+		logger:        util.LogWith("handlers").Str("handlers", "apikey").Logger(),
 	}
 }
 
@@ -33,13 +38,13 @@ func (h *Handlers) Add(w http.ResponseWriter, r *http.Request) {
 	ak := &hub.APIKey{}
 	if err := json.NewDecoder(r.Body).Decode(&ak); err != nil {
 		h.logger.Error().Err(err).Str("method", "Add").Msg(hub.ErrInvalidInput.Error())
-		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
 		return
 	}
 	key, err := h.apiKeyManager.Add(r.Context(), ak)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "Add").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	keyB64 := base64.StdEncoding.EncodeToString(key)
@@ -52,7 +57,7 @@ func (h *Handlers) Delete(w http.ResponseWriter, r *http.Request) {
 	apiKeyID := chi.URLParam(r, "apiKeyID")
 	if err := h.apiKeyManager.Delete(r.Context(), apiKeyID); err != nil {
 		h.logger.Error().Err(err).Str("method", "Delete").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -64,7 +69,33 @@ func (h *Handlers) Get(w http.ResponseWriter, r *http.Request) {
 	dataJSON, err := h.apiKeyManager.GetJSON(r.Context(), apiKeyID)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "Get").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
+// GetOrganizationUsage is an http handler that returns a summary of the usage
+// of the api keys owned by the members of the provided organization, like the
+// requests per day, the top endpoints or the requests rejected by the rate
+// limiter.
+func (h *Handlers) GetOrganizationUsage(w http.ResponseWriter, r *http.Request) {
+	orgName := chi.URLParam(r, "orgName")
+	days := defaultUsageDays
+	if v := r.URL.Query().Get("days"); v != "" {
+		var err error
+		days, err = strconv.Atoi(v)
+		if err != nil {
+			err = fmt.Errorf("%w: invalid days: %s", hub.ErrInvalidInput, v)
+			h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "GetOrganizationUsage").Msg("invalid query")
+			helpers.RenderErrorJSON(w, r, err)
+			return
+		}
+	}
+	dataJSON, err := h.apiKeyManager.GetOrganizationUsageJSON(r.Context(), orgName, days)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetOrganizationUsage").Send()
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
@@ -76,7 +107,7 @@ func (h *Handlers) GetOwnedByUser(w http.ResponseWriter, r *http.Request) {
 	dataJSON, err := h.apiKeyManager.GetOwnedByUserJSON(r.Context())
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "GetOwnedByUser").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
@@ -87,13 +118,13 @@ func (h *Handlers) Update(w http.ResponseWriter, r *http.Request) {
 	ak := &hub.APIKey{}
 	if err := json.NewDecoder(r.Body).Decode(&ak); err != nil {
 		h.logger.Error().Err(err).Str("method", "Update").Msg(hub.ErrInvalidInput.Error())
-		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
 		return
 	}
 	ak.APIKeyID = chi.URLParam(r, "apiKeyID")
 	if err := h.apiKeyManager.Update(r.Context(), ak); err != nil {
 		h.logger.Error().Err(err).Str("method", "Update").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/go-chi/chi"
+	"github.com/rs/zerolog"
+)
+
+// Handlers represents a group of http handlers in charge of handling
+// organizations email domains operations.
+type Handlers struct {
+	domainManager hub.DomainManager
+	logger        zerolog.Logger
+}
+
+// NewHandlers creates a new Handlers instance.
+func NewHandlers(domainManager hub.DomainManager) *Handlers {
+	return &Handlers{
+		domainManager: domainManager,
+		logger:        util.LogWith("handlers").Str("handlers", "domain").Logger(),
+	}
+}
+
+// Add is an http handler that adds the provided email domain to the
+// organization given.
+func (h *Handlers) Add(w http.ResponseWriter, r *http.Request) {
+	orgName := chi.URLParam(r, "orgName")
+	d := &hub.OrganizationDomain{}
+	if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
+		h.logger.Error().Err(err).Str("method", "Add").Msg("invalid domain")
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
+		return
+	}
+	if err := h.domainManager.Add(r.Context(), orgName, d); err != nil {
+		h.logger.Error().Err(err).Str("method", "Add").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+// Delete is an http handler that deletes the provided email domain from the
+// organization given.
+func (h *Handlers) Delete(w http.ResponseWriter, r *http.Request) {
+	orgName := chi.URLParam(r, "orgName")
+	domain := chi.URLParam(r, "domain")
+	if err := h.domainManager.Delete(r.Context(), orgName, domain); err != nil {
+		h.logger.Error().Err(err).Str("method", "Delete").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetByOrg is an http handler that returns the email domains of the provided
+// organization.
+func (h *Handlers) GetByOrg(w http.ResponseWriter, r *http.Request) {
+	orgName := chi.URLParam(r, "orgName")
+	dataJSON, err := h.domainManager.GetByOrgJSON(r.Context(), orgName)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetByOrg").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
+// Verify is an http handler that checks the ownership of the provided email
+// domain of the organization given, returning its verification status.
+func (h *Handlers) Verify(w http.ResponseWriter, r *http.Request) {
+	orgName := chi.URLParam(r, "orgName")
+	domain := chi.URLParam(r, "domain")
+	verified, err := h.domainManager.Verify(r.Context(), orgName, domain)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "Verify").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	dataJSON, _ := json.Marshal(map[string]bool{"verified": verified})
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
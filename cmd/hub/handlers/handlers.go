@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/http"
@@ -8,16 +9,24 @@ import (
 	"strings"
 	"time"
 
+	"github.com/artifacthub/hub/cmd/hub/handlers/abuse"
+	"github.com/artifacthub/hub/cmd/hub/handlers/adoption"
 	"github.com/artifacthub/hub/cmd/hub/handlers/apikey"
+	"github.com/artifacthub/hub/cmd/hub/handlers/domain"
+	"github.com/artifacthub/hub/cmd/hub/handlers/inventory"
 	"github.com/artifacthub/hub/cmd/hub/handlers/org"
 	"github.com/artifacthub/hub/cmd/hub/handlers/pkg"
 	"github.com/artifacthub/hub/cmd/hub/handlers/repo"
+	"github.com/artifacthub/hub/cmd/hub/handlers/statement"
 	"github.com/artifacthub/hub/cmd/hub/handlers/static"
 	"github.com/artifacthub/hub/cmd/hub/handlers/subscription"
+	"github.com/artifacthub/hub/cmd/hub/handlers/suppression"
 	"github.com/artifacthub/hub/cmd/hub/handlers/user"
 	"github.com/artifacthub/hub/cmd/hub/handlers/webhook"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/img"
+	"github.com/artifacthub/hub/internal/ipfilter"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
 	"github.com/prometheus/client_golang/prometheus"
@@ -29,7 +38,10 @@ import (
 	"github.com/ulule/limiter/v3/drivers/store/memory"
 )
 
-var xForwardedFor = http.CanonicalHeaderKey("X-Forwarded-For")
+var (
+	xForwardedFor = http.CanonicalHeaderKey("X-Forwarded-For")
+	apiKeyHeader  = http.CanonicalHeaderKey("X-API-KEY")
+)
 
 // Services is a wrapper around several internal services used by the handlers.
 type Services struct {
@@ -40,22 +52,31 @@ type Services struct {
 	SubscriptionManager hub.SubscriptionManager
 	WebhookManager      hub.WebhookManager
 	APIKeyManager       hub.APIKeyManager
+	DomainManager       hub.DomainManager
+	StatementManager    hub.StatementManager
+	AdoptionManager     hub.AdoptionManager
+	SuppressionManager  hub.EmailSuppressionManager
 	ImageStore          img.Store
+	APIKeyUsageTracker  hub.APIKeyUsageTracker
+	InventoryManager    hub.ClusterInventoryManager
 }
 
 // Metrics groups some metrics collected from a Handlers instance.
 type Metrics struct {
-	duration *prometheus.HistogramVec
+	duration   *prometheus.HistogramVec
+	dbDuration *prometheus.HistogramVec
 }
 
 // Handlers groups all the http handlers defined for the hub, including the
 // router in charge of sending requests to the right handler.
 type Handlers struct {
-	cfg     *viper.Viper
-	svc     *Services
-	metrics *Metrics
-	logger  zerolog.Logger
-	Router  http.Handler
+	cfg       *viper.Viper
+	svc       *Services
+	metrics   *Metrics
+	logger    zerolog.Logger
+	ipFilters map[string]*ipfilter.Filter
+	slowReq   time.Duration
+	Router    http.Handler
 
 	Organizations *org.Handlers
 	Users         *user.Handlers
@@ -64,51 +85,119 @@ type Handlers struct {
 	Subscriptions *subscription.Handlers
 	Webhooks      *webhook.Handlers
 	APIKeys       *apikey.Handlers
+	Domains       *domain.Handlers
+	Statements    *statement.Handlers
+	Adoptions     *adoption.Handlers
+	Suppressions  *suppression.Handlers
+	Inventories   *inventory.Handlers
 	Static        *static.Handlers
+	AbuseGuard    *abuse.Guard
 }
 
 // Setup creates a new Handlers instance.
-func Setup(cfg *viper.Viper, svc *Services) *Handlers {
+func Setup(cfg *viper.Viper, svc *Services) (*Handlers, error) {
+	abuseGuard, err := abuse.NewGuard(cfg, &http.Client{Timeout: 10 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	ipFilters := make(map[string]*ipfilter.Filter)
+	for _, group := range []string{"api", "write"} {
+		f, err := ipfilter.New(
+			cfg.GetStringSlice(fmt.Sprintf("server.ipFilter.%s.allow", group)),
+			cfg.GetStringSlice(fmt.Sprintf("server.ipFilter.%s.deny", group)),
+			cfg.GetInt("server.ipFilter.trustedProxies"),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s ip filter: %w", group, err)
+		}
+		ipFilters[group] = f
+	}
+	pkgHandlers, err := pkg.NewHandlers(svc.PackageManager, cfg)
+	if err != nil {
+		return nil, err
+	}
+	suppressionHandlers := suppression.NewHandlers(
+		svc.SuppressionManager,
+		cfg.GetString("email.webhooks.secret"),
+		&http.Client{Timeout: 10 * time.Second},
+	)
 	h := &Handlers{
-		cfg:     cfg,
-		svc:     svc,
-		metrics: setupMetrics(),
-		logger:  log.With().Str("handlers", "root").Logger(),
+		cfg:       cfg,
+		svc:       svc,
+		metrics:   setupMetrics(),
+		logger:    util.LogWith("handlers").Str("handlers", "root").Logger(),
+		ipFilters: ipFilters,
+		slowReq:   cfg.GetDuration("server.slowRequestThreshold"),
 
 		Organizations: org.NewHandlers(svc.OrganizationManager, cfg),
 		Users:         user.NewHandlers(svc.UserManager, cfg),
 		Repositories:  repo.NewHandlers(svc.RepositoryManager),
-		Packages:      pkg.NewHandlers(svc.PackageManager, cfg),
+		Packages:      pkgHandlers,
 		Subscriptions: subscription.NewHandlers(svc.SubscriptionManager),
 		Webhooks:      webhook.NewHandlers(svc.WebhookManager),
 		APIKeys:       apikey.NewHandlers(svc.APIKeyManager),
+		Domains:       domain.NewHandlers(svc.DomainManager),
+		Statements:    statement.NewHandlers(svc.StatementManager),
+		Adoptions:     adoption.NewHandlers(svc.AdoptionManager, cfg),
+		Suppressions:  suppressionHandlers,
+		Inventories:   inventory.NewHandlers(svc.InventoryManager),
 		Static:        static.NewHandlers(cfg, svc.ImageStore),
+		AbuseGuard:    abuseGuard,
 	}
 	h.setupRouter()
-	return h
+	return h, nil
 }
 
 // setupMetrics creates and registers some metrics
 func setupMetrics() *Metrics {
 	// Requests duration
+	buckets := []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30}
 	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
-		Name: "http_request_duration",
-		Help: "Duration of the http requests processed.",
+		Name:    "http_request_duration",
+		Help:    "Duration of the http requests processed.",
+		Buckets: buckets,
 	},
 		[]string{"status", "method", "path"},
 	)
 	prometheus.MustRegister(duration)
 
+	// Time spent in the database while processing requests
+	dbDuration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_db_duration",
+		Help:    "Time spent running database queries while processing the http requests.",
+		Buckets: buckets,
+	},
+		[]string{"method", "path"},
+	)
+	prometheus.MustRegister(dbDuration)
+
 	return &Metrics{
-		duration: duration,
+		duration:   duration,
+		dbDuration: dbDuration,
 	}
 }
 
+// Close stops the background tasks started by the handlers, like the packages
+// events broker, disconnecting the events streams clients. It must be called
+// before shutting down the server, as otherwise the events streams would keep
+// it waiting until they expire.
+func (h *Handlers) Close() {
+	h.Packages.Close()
+}
+
 // setupRouter initializes the handlers router, defining all routes used within
 // the hub, as well as some essential middleware to handle panics, logging, etc.
 func (h *Handlers) setupRouter() {
 	r := chi.NewRouter()
 
+	// In the internal catalog mode signup is disabled, all content requires
+	// authentication and only the configured publishers can add repositories
+	internalCatalog := h.cfg.GetBool("server.internalCatalog.enabled")
+	repoAddition := []func(http.Handler) http.Handler{h.AbuseGuard.Protect(abuse.RepositoryAddition)}
+	if internalCatalog {
+		repoAddition = append([]func(http.Handler) http.Handler{h.Users.RequirePublisher}, repoAddition...)
+	}
+
 	// Setup middleware and special handlers
 	r.Use(middleware.Recoverer)
 	r.Use(RealIP(h.cfg.GetInt("server.xffIndex")))
@@ -121,6 +210,19 @@ func (h *Handlers) setupRouter() {
 
 	// API
 	r.Route("/api/v1", func(r chi.Router) {
+		// Setup IP filters middleware
+		if f := h.ipFilters["api"]; f != nil {
+			r.Use(f.Handler)
+		}
+		if f := h.ipFilters["write"]; f != nil {
+			r.Use(OnlyWrites(f.Handler))
+		}
+
+		// Setup api keys usage collector middleware
+		if h.svc.APIKeyUsageTracker != nil {
+			r.Use(h.APIKeyUsageCollector)
+		}
+
 		// Setup rate limiter middleware
 		if h.cfg.GetBool("server.limiter.enabled") {
 			limiterRate := limiter.Rate{
@@ -129,14 +231,24 @@ func (h *Handlers) setupRouter() {
 			}
 			limiterStore := memory.NewStore()
 			rateLimiter := limiter.New(limiterStore, limiterRate)
-			r.Use(stdlib.NewMiddleware(rateLimiter).Handler)
+			r.Use(stdlib.NewMiddleware(rateLimiter, stdlib.WithLimitReachedHandler(h.LimitReached)).Handler)
+		}
+
+		// Require login in all routes in the internal catalog mode, except in
+		// the ones that authenticate requests by other means, which are
+		// registered at the end using the api router
+		api := r
+		if internalCatalog {
+			r = r.With(h.Users.RequireLogin)
 		}
 
 		// Users
 		r.Route("/users", func(r chi.Router) {
-			r.Post("/", h.Users.RegisterUser)
-			r.Post("/login", h.Users.Login)
-			r.Post("/verify-email", h.Users.VerifyEmail)
+			if !internalCatalog {
+				r.With(h.AbuseGuard.Protect(abuse.Signup)).Post("/", h.Users.RegisterUser)
+				r.Post("/login", h.Users.Login)
+				r.Post("/verify-email", h.Users.VerifyEmail)
+			}
 			r.Group(func(r chi.Router) {
 				r.Use(h.Users.RequireLogin)
 				r.Get("/logout", h.Users.Logout)
@@ -150,7 +262,7 @@ func (h *Handlers) setupRouter() {
 		r.Route("/orgs", func(r chi.Router) {
 			r.Group(func(r chi.Router) {
 				r.Use(h.Users.RequireLogin)
-				r.Post("/", h.Organizations.Add)
+				r.With(h.AbuseGuard.Protect(abuse.OrganizationCreation)).Post("/", h.Organizations.Add)
 				r.Get("/user", h.Organizations.GetByUser)
 			})
 			r.Route("/{orgName}", func(r chi.Router) {
@@ -159,58 +271,121 @@ func (h *Handlers) setupRouter() {
 					r.Use(h.Users.RequireLogin)
 					r.Put("/", h.Organizations.Update)
 					r.Get("/accept-invitation", h.Organizations.ConfirmMembership)
+					r.Put("/featured-packages", h.Organizations.UpdateFeaturedPackages)
 					r.Get("/members", h.Organizations.GetMembers)
 					r.Route("/member/{userAlias}", func(r chi.Router) {
 						r.Post("/", h.Organizations.AddMember)
 						r.Delete("/", h.Organizations.DeleteMember)
 					})
+					r.Get("/shares", h.Organizations.GetShares)
+					r.Get("/api-usage", h.APIKeys.GetOrganizationUsage)
+					r.Route("/domains", func(r chi.Router) {
+						r.Get("/", h.Domains.GetByOrg)
+						r.Post("/", h.Domains.Add)
+					})
+					r.Route("/domain/{domain}", func(r chi.Router) {
+						r.Delete("/", h.Domains.Delete)
+						r.Put("/verify", h.Domains.Verify)
+					})
+					r.Route("/share/{targetOrgName}", func(r chi.Router) {
+						r.Post("/", h.Organizations.AddShare)
+						r.Delete("/", h.Organizations.DeleteShare)
+					})
+					r.Route("/validation-webhook", func(r chi.Router) {
+						r.Get("/", h.Organizations.GetValidationWebhook)
+						r.Put("/", h.Organizations.UpdateValidationWebhook)
+					})
 				})
 			})
 		})
 
 		// Repositories
 		r.Route("/repositories", func(r chi.Router) {
-			r.Use(h.Users.RequireLogin)
-			r.Route("/user", func(r chi.Router) {
-				r.Get("/", h.Repositories.GetOwnedByUser)
-				r.Post("/", h.Repositories.Add)
-				r.Route("/{repoName}", func(r chi.Router) {
-					r.Put("/transfer", h.Repositories.Transfer)
-					r.Put("/", h.Repositories.Update)
-					r.Delete("/", h.Repositories.Delete)
+			r.Get("/health/{repoName}", h.Repositories.GetHealth)
+			r.Group(func(r chi.Router) {
+				r.Use(h.Users.RequireLogin)
+				r.Route("/user", func(r chi.Router) {
+					r.Get("/", h.Repositories.GetOwnedByUser)
+					r.With(repoAddition...).Post("/", h.Repositories.Add)
+					r.Route("/{repoName}", func(r chi.Router) {
+						r.Put("/transfer", h.Repositories.Transfer)
+						r.Put("/tracking-secret", h.Repositories.RegenerateTrackingSecret)
+						r.Put("/", h.Repositories.Update)
+						r.Delete("/", h.Repositories.Delete)
+						r.Get("/collaborators", h.Repositories.GetCollaborators)
+						r.Route("/collaborator/{userAlias}", func(r chi.Router) {
+							r.Post("/", h.Repositories.AddCollaborator)
+							r.Delete("/", h.Repositories.DeleteCollaborator)
+						})
+					})
 				})
-			})
-			r.Route("/org/{orgName}", func(r chi.Router) {
-				r.Get("/", h.Repositories.GetOwnedByOrg)
-				r.Post("/", h.Repositories.Add)
-				r.Route("/{repoName}", func(r chi.Router) {
-					r.Put("/transfer", h.Repositories.Transfer)
-					r.Put("/", h.Repositories.Update)
-					r.Delete("/", h.Repositories.Delete)
+				r.Route("/org/{orgName}", func(r chi.Router) {
+					r.Get("/", h.Repositories.GetOwnedByOrg)
+					r.With(repoAddition...).Post("/", h.Repositories.Add)
+					r.Route("/{repoName}", func(r chi.Router) {
+						r.Put("/transfer", h.Repositories.Transfer)
+						r.Put("/tracking-secret", h.Repositories.RegenerateTrackingSecret)
+						r.Put("/", h.Repositories.Update)
+						r.Delete("/", h.Repositories.Delete)
+						r.Get("/collaborators", h.Repositories.GetCollaborators)
+						r.Route("/collaborator/{userAlias}", func(r chi.Router) {
+							r.Post("/", h.Repositories.AddCollaborator)
+							r.Delete("/", h.Repositories.DeleteCollaborator)
+						})
+					})
 				})
 			})
 		})
 
 		// Packages
 		r.Route("/packages", func(r chi.Router) {
+			r.Get("/all", h.Packages.GetAll)
+			r.With(h.Users.InjectUserID).Get("/backstage", h.Packages.GetBackstageEntities)
+			r.Get("/changes", h.Packages.GetChanges)
+			r.Get("/events", h.Packages.Events)
+			r.With(h.Users.InjectUserID).Post("/installed", h.Packages.MatchInstalled)
 			r.Get("/random", h.Packages.GetRandom)
+			r.With(h.Users.InjectUserID).Get("/resolve", h.Packages.Resolve)
 			r.Get("/stats", h.Packages.GetStats)
-			r.Get("/search", h.Packages.Search)
+			r.With(h.Users.InjectUserID).Get("/search", h.Packages.Search)
+			r.Get("/tags", h.Packages.GetTags)
 			r.With(h.Users.RequireLogin).Get("/starred", h.Packages.GetStarredByUser)
 			r.Route("/{^helm$|^falco$|^opa$|^olm$}/{repoName}/{packageName}", func(r chi.Router) {
+				r.Use(h.Users.InjectUserID)
 				r.Get("/feed/rss", h.Packages.RssFeed)
+				r.Get("/versions", h.Packages.GetVersions)
+				r.Get("/latest", h.Packages.GetLatestVersions)
+				r.Get("/{version}/dependencies", h.Packages.GetDependenciesGraph)
+				r.Get("/{version}/docs", h.Packages.GetVersionDocs)
+				r.Get("/{version}/install", h.Packages.GetInstallInstructions)
+				r.Get("/{version}/snippets/{tool}", h.Packages.GetSnippet)
+				r.Get("/{version}/values", h.Packages.GetValues)
+				r.Get("/{version}/values-schema", h.Packages.GetValuesSchema)
 				r.Get("/{version}", h.Packages.Get)
 				r.Get("/", h.Packages.Get)
 			})
+			r.With(h.Users.InjectUserID).Get("/{packageID}/project", h.Packages.GetProject)
 			r.Route("/{packageID}/stars", func(r chi.Router) {
 				r.With(h.Users.InjectUserID).Get("/", h.Packages.GetStars)
 				r.With(h.Users.RequireLogin).Put("/", h.Packages.ToggleStar)
 			})
+			r.Route("/{packageID}/statements", func(r chi.Router) {
+				r.With(h.Users.InjectUserID).Get("/", h.Statements.GetByPackage)
+				r.With(h.Users.RequireLogin).Put("/{kind}", h.Statements.Add)
+				r.With(h.Users.RequireLogin).Delete("/{kind}", h.Statements.Delete)
+			})
+			r.Route("/{packageID}/adoption-requests", func(r chi.Router) {
+				r.Use(h.Users.RequireLogin)
+				r.Get("/", h.Adoptions.GetByPackage)
+				r.Post("/", h.Adoptions.Add)
+			})
+			r.With(h.Users.RequireLogin).Put("/{packageID}/seeking-maintainers", h.Adoptions.SetSeekingMaintainers)
 		})
 
 		// Subscriptions
 		r.Route("/subscriptions", func(r chi.Router) {
 			r.Use(h.Users.RequireLogin)
+			r.Get("/changes", h.Subscriptions.GetUserChanges)
 			r.Get("/{packageID}", h.Subscriptions.GetByPackage)
 			r.Get("/", h.Subscriptions.GetByUser)
 			r.Post("/", h.Subscriptions.Add)
@@ -241,6 +416,17 @@ func (h *Handlers) setupRouter() {
 			r.Post("/test", h.Webhooks.TriggerTest)
 		})
 
+		// Cluster inventories
+		r.Route("/cluster-inventories", func(r chi.Router) {
+			r.Use(h.Users.RequireLogin)
+			r.Get("/", h.Inventories.GetOwnedByUser)
+			r.Post("/", h.Inventories.Add)
+			r.Route("/{clusterInventoryID}", func(r chi.Router) {
+				r.Put("/", h.Inventories.Update)
+				r.Delete("/", h.Inventories.Delete)
+			})
+		})
+
 		// API keys
 		r.Route("/api-keys", func(r chi.Router) {
 			r.Use(h.Users.RequireLogin)
@@ -262,6 +448,12 @@ func (h *Handlers) setupRouter() {
 
 		// Images
 		r.With(h.Users.RequireLogin).Post("/images", h.Static.SaveImage)
+
+		// Packages push (repository scoped api keys) and repositories tracking
+		// requests (tracking secret). They must be registered after the
+		// packages and repositories routes to take precedence over them.
+		api.With(h.Users.RequireRepositoryAPIKey).Post("/packages", h.Packages.Push)
+		api.Post("/repositories/track/{repoName}", h.Repositories.RequestTracking)
 	})
 
 	// Oauth
@@ -276,48 +468,147 @@ func (h *Handlers) setupRouter() {
 		})
 	}
 
-	// Index special entry points
-	r.Route("/packages", func(r chi.Router) {
-		r.Route("/{^helm$|^falco$|^opa$|^olm$}/{repoName}/{packageName}", func(r chi.Router) {
-			r.With(h.Packages.InjectIndexMeta).Get("/{version}", h.Static.ServeIndex)
-			r.With(h.Packages.InjectIndexMeta).Get("/", h.Static.ServeIndex)
+	// Index special entry points (packages details are not disclosed in the
+	// index metadata in the internal catalog mode)
+	if !internalCatalog {
+		r.Route("/packages", func(r chi.Router) {
+			r.Route("/{^helm$|^falco$|^opa$|^olm$}/{repoName}/{packageName}", func(r chi.Router) {
+				r.With(h.Packages.InjectIndexMeta).Get("/{version}", h.Static.ServeIndex)
+				r.With(h.Packages.InjectIndexMeta).Get("/", h.Static.ServeIndex)
+			})
+		})
+	}
+
+	// Email delivery providers bounces and complaints notifications
+	if h.cfg.GetString("email.webhooks.secret") != "" && h.svc.SuppressionManager != nil {
+		r.Route("/email-events", func(r chi.Router) {
+			r.Use(h.Suppressions.RequireSecret)
+			r.Post("/ses", h.Suppressions.SES)
+			r.Post("/sendgrid", h.Suppressions.SendGrid)
 		})
-	})
+	}
 
 	// Static files and index
 	staticFilesPath := path.Join(h.cfg.GetString("server.webBuildPath"), "static")
 	static.FileServer(r, "/static", http.Dir(staticFilesPath))
-	r.Get("/image/{image}", h.Static.Image)
+	if internalCatalog {
+		r.With(h.Users.RequireLogin).Get("/image/{image}", h.Static.Image)
+	} else {
+		r.Get("/image/{image}", h.Static.Image)
+	}
 	r.Get("/", h.Static.ServeIndex)
 
 	h.Router = r
 }
 
 // MetricsCollector is an http middleware that collects some metrics about
-// requests processed.
+// requests processed, including the time spent running database queries. When
+// a slow request threshold has been configured, the requests that take longer
+// are logged along with a breakdown of their database usage.
 func (h *Handlers) MetricsCollector(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		ctx, dbStats := util.WithDBStats(r.Context())
+		r = r.WithContext(ctx)
 		defer func() {
-			rctx := chi.RouteContext(r.Context())
+			took := time.Since(start)
+			route := chi.RouteContext(r.Context()).RoutePattern()
 			h.metrics.duration.WithLabelValues(
 				http.StatusText(ww.Status()),
 				r.Method,
-				rctx.RoutePattern(),
-			).Observe(time.Since(start).Seconds())
+				route,
+			).Observe(took.Seconds())
+			h.metrics.dbDuration.WithLabelValues(
+				r.Method,
+				route,
+			).Observe(dbStats.Duration().Seconds())
+			if h.slowReq > 0 && took > h.slowReq {
+				slowestQuery, slowestQueryTook := dbStats.SlowestQuery()
+				h.logger.Warn().
+					Fields(map[string]interface{}{
+						"method":                r.Method,
+						"route":                 route,
+						"status":                ww.Status(),
+						"took":                  float64(took) / 1e6,
+						"db_queries":            dbStats.Queries(),
+						"db_took":               float64(dbStats.Duration()) / 1e6,
+						"db_slowest_query":      truncate(strings.Join(strings.Fields(slowestQuery), " "), 200),
+						"db_slowest_query_took": float64(slowestQueryTook) / 1e6,
+					}).
+					Msg("slow request: " + r.URL.Path)
+			}
 		}()
 		next.ServeHTTP(ww, r)
 	})
 }
 
+// APIKeyUsageCollector is an http middleware that records the requests
+// authenticated using an api key in the api keys usage tracker.
+func (h *Handlers) APIKeyUsageCollector(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(apiKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx, usage := util.WithRequestUsage(r.Context())
+		r = r.WithContext(ctx)
+		defer func() {
+			if apiKeyID := usage.APIKeyID(); apiKeyID != "" {
+				route := chi.RouteContext(r.Context()).RoutePattern()
+				h.svc.APIKeyUsageTracker.Track(apiKeyID, []byte(key), r.Method+" "+route)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// LimitReached is an http handler that replies to the requests rejected by the
+// rate limiter, recording them in the api keys usage tracker when they were
+// sent using an api key.
+func (h *Handlers) LimitReached(w http.ResponseWriter, r *http.Request) {
+	if key := r.Header.Get(apiKeyHeader); key != "" && h.svc.APIKeyUsageTracker != nil {
+		h.svc.APIKeyUsageTracker.TrackRateLimited([]byte(key), r.Method+" "+h.limitedRoute(r))
+	}
+	stdlib.DefaultLimitReachedHandler(w, r)
+}
+
+// limitedRoute returns the route pattern a request rejected by the rate
+// limiter is recorded under. The rate limiter runs before the request is
+// routed, so the route it would have been dispatched to is looked up in the
+// router. This way rate limited requests are recorded in the same endpoints
+// as the ones processed. Requests that don't match any route are recorded
+// under the pattern of the router the rate limiter is mounted on.
+func (h *Handlers) limitedRoute(r *http.Request) string {
+	route := chi.RouteContext(r.Context()).RoutePattern()
+	if routes, ok := h.Router.(chi.Routes); ok {
+		rctx := chi.NewRouteContext()
+		if routes.Match(rctx, r.Method, r.URL.Path) {
+			route = rctx.RoutePattern()
+		}
+	}
+	return route
+}
+
+// truncate truncates the string provided to the maximum length given.
+func truncate(s string, maxLen int) string {
+	if len(s) > maxLen {
+		return s[:maxLen] + "..."
+	}
+	return s
+}
+
 // RealIP is an http middleware that sets the request remote addr to the result
 // of extracting the IP in the requested index from the X-Forwarded-For header.
 // Positives indexes start by 0 and work like usual slice indexes. Negative
 // indexes are allowed being -1 the last entry in the slice, -2 the next, etc.
+// The original remote addr is kept in the request context, as the header can
+// be set at will by clients and it must not be used for access control.
 func RealIP(i int) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = r.WithContext(context.WithValue(r.Context(), ipfilter.PeerAddrKey, r.RemoteAddr))
 			if xff := r.Header.Get(xForwardedFor); xff != "" {
 				ips := strings.Split(xff, ",")
 				if i >= 0 && len(ips) > i {
@@ -332,6 +623,23 @@ func RealIP(i int) func(next http.Handler) http.Handler {
 	}
 }
 
+// OnlyWrites returns an http middleware that applies the middleware provided
+// only to the requests that may modify data (all except GET, HEAD and OPTIONS
+// ones).
+func OnlyWrites(mw func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		wrapped := mw(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+			default:
+				wrapped.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
 // Logger is an http middleware that logs some information about requests
 // processed using zerolog.
 func Logger(next http.Handler) http.Handler {
@@ -1,18 +1,31 @@
 package handlers
 
 import (
+	"encoding/base64"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/ipfilter"
+	"github.com/artifacthub/hub/internal/pkg"
+	"github.com/artifacthub/hub/internal/repo"
+	"github.com/artifacthub/hub/internal/user"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/go-chi/chi"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRealIP(t *testing.T) {
 	checkRemoteAddr := func(expectedRemoteAddr string) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
 			assert.Equal(t, expectedRemoteAddr, r.RemoteAddr)
+			assert.Equal(t, "1.1.1.1:", r.Context().Value(ipfilter.PeerAddrKey))
 		}
 	}
 
@@ -107,3 +120,187 @@ func TestRealIP(t *testing.T) {
 		})
 	}
 }
+
+func TestOnlyWrites(t *testing.T) {
+	deny := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		})
+	}
+	testCases := []struct {
+		method             string
+		expectedStatusCode int
+	}{
+		{http.MethodGet, http.StatusOK},
+		{http.MethodHead, http.StatusOK},
+		{http.MethodOptions, http.StatusOK},
+		{http.MethodPost, http.StatusForbidden},
+		{http.MethodPut, http.StatusForbidden},
+		{http.MethodDelete, http.StatusForbidden},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.method, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := &http.Request{Method: tc.method}
+			OnlyWrites(deny)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(w, r)
+			assert.Equal(t, tc.expectedStatusCode, w.Result().StatusCode)
+		})
+	}
+}
+
+func TestAPIKeyUsageCollector(t *testing.T) {
+	setupRouter := func(ut *usageTrackerFake) http.Handler {
+		h := &Handlers{svc: &Services{APIKeyUsageTracker: ut}}
+		r := chi.NewRouter()
+		r.Use(h.APIKeyUsageCollector)
+		r.Get("/packages/{packageID}", func(w http.ResponseWriter, r *http.Request) {
+			util.SetRequestAPIKey(r.Context(), "apiKeyID")
+		})
+		r.Get("/unauthenticated", func(w http.ResponseWriter, r *http.Request) {})
+		r.Get("/limited", h.LimitReached)
+		r.Route("/api", func(r chi.Router) {
+			r.Use(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(h.LimitReached)
+			})
+			r.Get("/repositories/{repositoryID}", func(w http.ResponseWriter, r *http.Request) {})
+		})
+		h.Router = r
+		return r
+	}
+
+	t.Run("requests without an api key are not tracked", func(t *testing.T) {
+		ut := &usageTrackerFake{}
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/packages/1", nil)
+		setupRouter(ut).ServeHTTP(w, r)
+		assert.Empty(t, ut.tracked)
+	})
+
+	t.Run("requests not authenticated using the api key are not tracked", func(t *testing.T) {
+		ut := &usageTrackerFake{}
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/unauthenticated", nil)
+		r.Header.Set(apiKeyHeader, "key")
+		setupRouter(ut).ServeHTTP(w, r)
+		assert.Empty(t, ut.tracked)
+	})
+
+	t.Run("request authenticated using an api key tracked", func(t *testing.T) {
+		ut := &usageTrackerFake{}
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/packages/1", nil)
+		r.Header.Set(apiKeyHeader, "key")
+		setupRouter(ut).ServeHTTP(w, r)
+		assert.Equal(t, []string{"apiKeyID key GET /packages/{packageID}"}, ut.tracked)
+	})
+
+	t.Run("rate limited request tracked", func(t *testing.T) {
+		ut := &usageTrackerFake{}
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/limited", nil)
+		r.Header.Set(apiKeyHeader, "key")
+		setupRouter(ut).ServeHTTP(w, r)
+		assert.Equal(t, http.StatusTooManyRequests, w.Result().StatusCode)
+		assert.Equal(t, []string{"rate-limited key GET /limited"}, ut.tracked)
+	})
+
+	t.Run("rate limited request tracked using the route it would have been dispatched to", func(t *testing.T) {
+		ut := &usageTrackerFake{}
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/api/repositories/1", nil)
+		r.Header.Set(apiKeyHeader, "key")
+		setupRouter(ut).ServeHTTP(w, r)
+		assert.Equal(t, http.StatusTooManyRequests, w.Result().StatusCode)
+		assert.Equal(t, []string{"rate-limited key GET /api/repositories/{repositoryID}"}, ut.tracked)
+	})
+
+	t.Run("rate limited request not matching any route tracked using the mount pattern", func(t *testing.T) {
+		ut := &usageTrackerFake{}
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/api/unknown", nil)
+		r.Header.Set(apiKeyHeader, "key")
+		setupRouter(ut).ServeHTTP(w, r)
+		assert.Equal(t, http.StatusTooManyRequests, w.Result().StatusCode)
+		assert.Equal(t, []string{"rate-limited key GET /api/*"}, ut.tracked)
+	})
+}
+
+func TestInternalCatalog(t *testing.T) {
+	cfg := viper.New()
+	cfg.Set("server.internalCatalog.enabled", true)
+	cfg.Set("server.webBuildPath", "static/testdata")
+	um := &user.ManagerMock{}
+	pm := &pkg.ManagerMock{}
+	rm := &repo.ManagerMock{}
+	h, err := Setup(cfg, &Services{UserManager: um, PackageManager: pm, RepositoryManager: rm})
+	require.NoError(t, err)
+
+	repoKey := base64.StdEncoding.EncodeToString([]byte("repoKey"))
+	um.On("CheckAPIKey", mock.Anything, []byte("repoKey")).Return(&hub.CheckAPIKeyOutput{
+		Valid:        true,
+		UserID:       "userID",
+		RepositoryID: "repositoryID",
+	}, nil)
+
+	t.Run("login is required to search packages", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/api/v1/packages/search", nil)
+		h.Router.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+	})
+
+	t.Run("repository scoped api keys cannot be used to search packages", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/api/v1/packages/search", nil)
+		r.Header.Set(apiKeyHeader, repoKey)
+		h.Router.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+	})
+
+	t.Run("packages can be pushed using repository scoped api keys", func(t *testing.T) {
+		pm.On("Push", mock.Anything, mock.Anything).Return(nil).Once()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/api/v1/packages", strings.NewReader("name: pkg1\nversion: 1.0.0\n"))
+		r.Header.Set(apiKeyHeader, repoKey)
+		h.Router.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusCreated, w.Result().StatusCode)
+		pm.AssertExpectations(t)
+	})
+
+	t.Run("packages cannot be pushed without an api key", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/api/v1/packages", strings.NewReader("name: pkg1\nversion: 1.0.0\n"))
+		h.Router.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+	})
+
+	t.Run("repositories tracking can be requested using the tracking secret", func(t *testing.T) {
+		rm.On("RequestTracking", mock.Anything, "repo1", "secret").Return(nil).Once()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/api/v1/repositories/track/repo1", nil)
+		r.Header.Set("X-Tracking-Secret", "secret")
+		h.Router.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusAccepted, w.Result().StatusCode)
+		rm.AssertExpectations(t)
+	})
+
+	t.Run("login is required to get the user repositories", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/api/v1/repositories/user", nil)
+		h.Router.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+	})
+}
+
+type usageTrackerFake struct {
+	tracked []string
+}
+
+func (ut *usageTrackerFake) Track(apiKeyID string, key []byte, endpoint string) {
+	ut.tracked = append(ut.tracked, fmt.Sprintf("%s %s %s", apiKeyID, key, endpoint))
+}
+
+func (ut *usageTrackerFake) TrackRateLimited(key []byte, endpoint string) {
+	ut.tracked = append(ut.tracked, fmt.Sprintf("rate-limited %s %s", key, endpoint))
+}
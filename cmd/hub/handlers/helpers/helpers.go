@@ -1,20 +1,91 @@
 package helpers
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/i18n"
 )
 
 const (
 	DefaultAPICacheMaxAge = 5 * time.Minute
 )
 
+// Output formats supported by some of the endpoints, negotiated using the
+// request's Accept header.
+const (
+	FormatJSON   = "json"
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// GetOutputFormat returns the output format requested in the Accept header of
+// the request provided. JSON is used when no supported alternative format has
+// been requested.
+func GetOutputFormat(r *http.Request) string {
+	for _, mediaRange := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType := strings.TrimSpace(strings.Split(mediaRange, ";")[0])
+		switch strings.ToLower(mediaType) {
+		case "application/json":
+			return FormatJSON
+		case "text/csv":
+			return FormatCSV
+		case "application/x-ndjson", "application/ndjson":
+			return FormatNDJSON
+		}
+	}
+	return FormatJSON
+}
+
+// GetRemoteIP returns the IP address of the client that sent the request
+// provided, as set in the request's remote address.
+func GetRemoteIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return strings.TrimSuffix(r.RemoteAddr, ":")
+	}
+	return ip
+}
+
+// IsDryRun checks if the request provided asks for a dry run using the
+// dry_run query parameter. Destructive operations supporting it report what
+// would be removed instead of deleting anything.
+func IsDryRun(r *http.Request) (bool, error) {
+	v := r.URL.Query().Get("dry_run")
+	if v == "" {
+		return false, nil
+	}
+	dryRun, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("%w: invalid dry_run: %s", hub.ErrInvalidInput, v)
+	}
+	return dryRun, nil
+}
+
+// GetCacheMaxAge returns the cache max age to use in the response to the
+// request provided when it may include packages in private repositories. Those
+// packages are only returned to logged in users, so responses are not cached
+// when the request has been made by one. Otherwise the max age provided is
+// used.
+func GetCacheMaxAge(r *http.Request, cacheMaxAge time.Duration) time.Duration {
+	if _, ok := r.Context().Value(hub.UserIDKey).(string); ok {
+		return 0
+	}
+	return cacheMaxAge
+}
+
 // BuildCacheControlHeader builds an http cache header using the max age
 // duration provided.
 func BuildCacheControlHeader(cacheMaxAge time.Duration) string {
@@ -30,19 +101,90 @@ func RenderJSON(w http.ResponseWriter, dataJSON []byte, cacheMaxAge time.Duratio
 	_, _ = w.Write(dataJSON)
 }
 
+// RenderJSONWithETag is a helper to write the json data provided to the given
+// http response writer like RenderJSON does, adding an ETag header built from
+// the data. When the request's If-None-Match header matches the ETag, a Not
+// Modified response is written instead, so that clients polling frequently
+// can revalidate the data they already have cheaply.
+func RenderJSONWithETag(w http.ResponseWriter, r *http.Request, dataJSON []byte, cacheMaxAge time.Duration) {
+	hash := sha256.Sum256(dataJSON)
+	etag := `"` + hex.EncodeToString(hash[:]) + `"`
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.Header().Set("Cache-Control", BuildCacheControlHeader(cacheMaxAge))
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	RenderJSON(w, dataJSON, cacheMaxAge, http.StatusOK)
+}
+
+// RenderCSV is a helper to write the records provided to the given http
+// response writer as csv, setting the appropriate content type, cache and
+// status code. The header provided will be written as the first record. The
+// fields of the records are escaped so that spreadsheet applications don't
+// evaluate them as formulas.
+func RenderCSV(w http.ResponseWriter, header []string, records [][]string, cacheMaxAge time.Duration, code int) {
+	w.Header().Set("Cache-Control", BuildCacheControlHeader(cacheMaxAge))
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.WriteHeader(code)
+	cw := csv.NewWriter(w)
+	_ = cw.Write(header)
+	for _, record := range records {
+		escapedRecord := make([]string, 0, len(record))
+		for _, field := range record {
+			escapedRecord = append(escapedRecord, escapeCSVField(field))
+		}
+		_ = cw.Write(escapedRecord)
+	}
+	cw.Flush()
+}
+
+// escapeCSVField prefixes the csv field provided with a single quote when it
+// starts with a character that spreadsheet applications may interpret as the
+// beginning of a formula, preventing csv injection attacks.
+func escapeCSVField(field string) string {
+	if field != "" && strings.ContainsAny(field[:1], "=+-@\t\r") {
+		return "'" + field
+	}
+	return field
+}
+
+// RenderNDJSON is a helper to write the items provided to the given http
+// response writer as newline delimited json, setting the appropriate content
+// type, cache and status code. Items are compacted so that each one of them
+// takes exactly one line.
+func RenderNDJSON(w http.ResponseWriter, items []json.RawMessage, cacheMaxAge time.Duration, code int) {
+	w.Header().Set("Cache-Control", BuildCacheControlHeader(cacheMaxAge))
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(code)
+	var buf bytes.Buffer
+	for _, item := range items {
+		buf.Reset()
+		if err := json.Compact(&buf, item); err != nil {
+			continue
+		}
+		buf.WriteByte('\n')
+		_, _ = w.Write(buf.Bytes())
+	}
+}
+
 // RenderErrorJSON is a helper to write the error provided to the given http
-// response writer as json setting the appropriate content type.
-func RenderErrorJSON(w http.ResponseWriter, err error) {
+// response writer as json setting the appropriate content type. Invalid input
+// errors messages are translated to the locale requested in the request's
+// Accept-Language header when possible. Limit exceeded errors messages are
+// sent as is, so that requesters know which limit was reached.
+func RenderErrorJSON(w http.ResponseWriter, r *http.Request, err error) {
 	w.Header().Set("Content-Type", "application/json")
 	var errMsg string
 	switch {
 	case errors.Is(err, hub.ErrInvalidInput):
 		w.WriteHeader(http.StatusBadRequest)
-		if err != nil {
-			errMsg = err.Error()
-		}
+		errMsg = translateInvalidInputError(i18n.MatchLocale(r.Header.Get("Accept-Language")), err)
 	case errors.Is(err, hub.ErrInsufficientPrivilege):
 		w.WriteHeader(http.StatusForbidden)
+	case errors.Is(err, hub.ErrLimitExceeded):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		errMsg = err.Error()
 	case errors.Is(err, hub.ErrNotFound):
 		w.WriteHeader(http.StatusNotFound)
 	default:
@@ -66,6 +208,23 @@ func RenderErrorWithCodeJSON(w http.ResponseWriter, err error, code int) {
 	writeErrorJSON(w, errMsg)
 }
 
+// translateInvalidInputError translates the invalid input error provided to
+// the given locale. Only errors that consist of hub.ErrInvalidInput optionally
+// followed by a detail message are translated.
+func translateInvalidInputError(locale string, err error) string {
+	prefix := hub.ErrInvalidInput.Error()
+	errMsg := err.Error()
+	switch {
+	case errMsg == prefix:
+		return i18n.T(locale, prefix)
+	case strings.HasPrefix(errMsg, prefix+": "):
+		detail := strings.TrimPrefix(errMsg, prefix+": ")
+		return i18n.T(locale, prefix) + ": " + i18n.T(locale, detail)
+	default:
+		return errMsg
+	}
+}
+
 // writeErrorJSON buids the error payload and writes it to the writer provided.
 func writeErrorJSON(w io.Writer, msg string) {
 	data := map[string]interface{}{
@@ -2,11 +2,14 @@ package helpers
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -42,6 +45,55 @@ func TestBuildCacheControlHeader(t *testing.T) {
 	}
 }
 
+func TestGetRemoteIP(t *testing.T) {
+	testCases := []struct {
+		remoteAddr string
+		expectedIP string
+	}{
+		{"1.1.1.1:1234", "1.1.1.1"},
+		{"1.1.1.1:", "1.1.1.1"},
+		{"[2001:db8::1]:1234", "2001:db8::1"},
+		{"2001:db8::1:", "2001:db8::1"},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.remoteAddr, func(t *testing.T) {
+			r := &http.Request{RemoteAddr: tc.remoteAddr}
+			assert.Equal(t, tc.expectedIP, GetRemoteIP(r))
+		})
+	}
+}
+
+func TestIsDryRun(t *testing.T) {
+	t.Run("invalid dry_run value", func(t *testing.T) {
+		r, _ := http.NewRequest("DELETE", "/?dry_run=maybe", nil)
+		_, err := IsDryRun(r)
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("valid dry_run values", func(t *testing.T) {
+		testCases := []struct {
+			query          string
+			expectedDryRun bool
+		}{
+			{"", false},
+			{"?dry_run=false", false},
+			{"?dry_run=0", false},
+			{"?dry_run=true", true},
+			{"?dry_run=1", true},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.query, func(t *testing.T) {
+				r, _ := http.NewRequest("DELETE", "/"+tc.query, nil)
+				dryRun, err := IsDryRun(r)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedDryRun, dryRun)
+			})
+		}
+	})
+}
+
 func TestRenderJSON(t *testing.T) {
 	testCases := []struct {
 		data        []byte
@@ -86,34 +138,192 @@ func TestRenderJSON(t *testing.T) {
 	}
 }
 
+func TestRenderJSONWithETag(t *testing.T) {
+	dataJSON := []byte("dataJSON")
+	etag := `"4bd8446d186a1b95e8a807f50e2b0620f9d0de582382cc274a462fe0e8065ff5"`
+
+	t.Run("etag not provided", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		RenderJSONWithETag(w, r, dataJSON, DefaultAPICacheMaxAge)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, BuildCacheControlHeader(DefaultAPICacheMaxAge), h.Get("Cache-Control"))
+		assert.Equal(t, etag, h.Get("ETag"))
+		assert.Equal(t, dataJSON, data)
+	})
+
+	t.Run("etag provided does not match", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r.Header.Set("If-None-Match", `"outdated"`)
+		RenderJSONWithETag(w, r, dataJSON, DefaultAPICacheMaxAge)
+		resp := w.Result()
+		defer resp.Body.Close()
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, etag, resp.Header.Get("ETag"))
+		assert.Equal(t, dataJSON, data)
+	})
+
+	t.Run("etag provided matches", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r.Header.Set("If-None-Match", etag)
+		RenderJSONWithETag(w, r, dataJSON, DefaultAPICacheMaxAge)
+		resp := w.Result()
+		defer resp.Body.Close()
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+		assert.Equal(t, BuildCacheControlHeader(DefaultAPICacheMaxAge), resp.Header.Get("Cache-Control"))
+		assert.Equal(t, etag, resp.Header.Get("ETag"))
+		assert.Empty(t, data)
+	})
+}
+
+func TestGetOutputFormat(t *testing.T) {
+	testCases := []struct {
+		accept         string
+		expectedFormat string
+	}{
+		{"", FormatJSON},
+		{"*/*", FormatJSON},
+		{"application/json", FormatJSON},
+		{"text/csv", FormatCSV},
+		{"text/csv; charset=utf-8", FormatCSV},
+		{"application/x-ndjson", FormatNDJSON},
+		{"text/html, application/ndjson;q=0.9", FormatNDJSON},
+		{"application/json, text/csv", FormatJSON},
+	}
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.Header.Set("Accept", tc.accept)
+			assert.Equal(t, tc.expectedFormat, GetOutputFormat(r))
+		})
+	}
+}
+
+func TestRenderCSV(t *testing.T) {
+	w := httptest.NewRecorder()
+	RenderCSV(w, []string{"name", "version"}, [][]string{
+		{"pkg1", "1.0.0"},
+		{"pkg2", "2.0.0"},
+	}, DefaultAPICacheMaxAge, http.StatusOK)
+	resp := w.Result()
+	defer resp.Body.Close()
+	h := resp.Header
+	data, _ := ioutil.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/csv; charset=utf-8", h.Get("Content-Type"))
+	assert.Equal(t, BuildCacheControlHeader(DefaultAPICacheMaxAge), h.Get("Cache-Control"))
+	assert.Equal(t, []byte("name,version\npkg1,1.0.0\npkg2,2.0.0\n"), data)
+}
+
+func TestRenderCSVEscapesFormulas(t *testing.T) {
+	w := httptest.NewRecorder()
+	RenderCSV(w, []string{"name", "description"}, [][]string{
+		{"pkg1", "=HYPERLINK(\"http://evil.com\")"},
+		{"pkg2", "+1"},
+		{"pkg3", "-1"},
+		{"pkg4", "@SUM(A1)"},
+		{"pkg5", "\tdescription"},
+		{"pkg6", "description = 1"},
+	}, DefaultAPICacheMaxAge, http.StatusOK)
+	resp := w.Result()
+	defer resp.Body.Close()
+	data, _ := ioutil.ReadAll(resp.Body)
+
+	assert.Equal(t, strings.Join([]string{
+		"name,description",
+		`pkg1,"'=HYPERLINK(""http://evil.com"")"`,
+		"pkg2,'+1",
+		"pkg3,'-1",
+		"pkg4,'@SUM(A1)",
+		"pkg5,'\tdescription",
+		"pkg6,description = 1",
+		"",
+	}, "\n"), string(data))
+}
+
+func TestRenderNDJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+	RenderNDJSON(w, []json.RawMessage{
+		json.RawMessage(`{"name":"pkg1"}`),
+		json.RawMessage(`{"name":"pkg2"}`),
+	}, DefaultAPICacheMaxAge, http.StatusOK)
+	resp := w.Result()
+	defer resp.Body.Close()
+	h := resp.Header
+	data, _ := ioutil.ReadAll(resp.Body)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/x-ndjson", h.Get("Content-Type"))
+	assert.Equal(t, BuildCacheControlHeader(DefaultAPICacheMaxAge), h.Get("Cache-Control"))
+	assert.Equal(t, []byte("{\"name\":\"pkg1\"}\n{\"name\":\"pkg2\"}\n"), data)
+}
+
 func TestRenderErrorJSON(t *testing.T) {
 	testCases := []struct {
 		err                error
+		acceptLanguage     string
 		expectedStatusCode int
 		expectedErrorMsg   string
 	}{
 		{
 			hub.ErrInvalidInput,
+			"",
 			http.StatusBadRequest,
 			"invalid input",
 		},
 		{
 			fmt.Errorf("%w: test error", hub.ErrInvalidInput),
+			"",
 			http.StatusBadRequest,
 			"invalid input: test error",
 		},
+		{
+			fmt.Errorf("%w: name not provided", hub.ErrInvalidInput),
+			"es-ES,es;q=0.9,en;q=0.8",
+			http.StatusBadRequest,
+			"entrada no válida: nombre no proporcionado",
+		},
+		{
+			fmt.Errorf("%w: test error", hub.ErrInvalidInput),
+			"es",
+			http.StatusBadRequest,
+			"entrada no válida: test error",
+		},
 		{
 			hub.ErrInsufficientPrivilege,
+			"",
 			http.StatusForbidden,
 			"",
 		},
+		{
+			&hub.LimitExceededError{Limit: "webhooks per user", Max: 5},
+			"",
+			http.StatusUnprocessableEntity,
+			"limit exceeded: webhooks per user (max 5)",
+		},
 		{
 			hub.ErrNotFound,
+			"",
 			http.StatusNotFound,
 			"",
 		},
 		{
 			tests.ErrFakeDatabaseFailure,
+			"",
 			http.StatusInternalServerError,
 			"",
 		},
@@ -122,7 +332,9 @@ func TestRenderErrorJSON(t *testing.T) {
 		tc := tc
 		t.Run(strconv.Itoa(i), func(t *testing.T) {
 			w := httptest.NewRecorder()
-			RenderErrorJSON(w, tc.err)
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.Header.Set("Accept-Language", tc.acceptLanguage)
+			RenderErrorJSON(w, r, tc.err)
 			resp := w.Result()
 			defer resp.Body.Close()
 			h := resp.Header
@@ -0,0 +1,86 @@
+package inventory
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/go-chi/chi"
+	"github.com/rs/zerolog"
+)
+
+// Handlers represents a group of http handlers in charge of handling cluster
+// inventories operations.
+type Handlers struct {
+	inventoryManager hub.ClusterInventoryManager
+	logger           zerolog.Logger
+}
+
+// NewHandlers creates a new Handlers instance.
+func NewHandlers(inventoryManager hub.ClusterInventoryManager) *Handlers {
+	return &Handlers{
+		inventoryManager: inventoryManager,
+		logger:           util.LogWith("handlers").Str("handlers", "inventory").Logger(),
+	}
+}
+
+// Add is an http handler that adds the provided cluster inventory to the
+// database.
+func (h *Handlers) Add(w http.ResponseWriter, r *http.Request) {
+	inv := &hub.ClusterInventory{}
+	if err := json.NewDecoder(r.Body).Decode(&inv); err != nil {
+		h.logger.Error().Err(err).Str("method", "Add").Msg(hub.ErrInvalidInput.Error())
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
+		return
+	}
+	if err := h.inventoryManager.Add(r.Context(), inv); err != nil {
+		h.logger.Error().Err(err).Str("method", "Add").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+// Delete is an http handler that deletes the provided cluster inventory from
+// the database.
+func (h *Handlers) Delete(w http.ResponseWriter, r *http.Request) {
+	clusterInventoryID := chi.URLParam(r, "clusterInventoryID")
+	if err := h.inventoryManager.Delete(r.Context(), clusterInventoryID); err != nil {
+		h.logger.Error().Err(err).Str("method", "Delete").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetOwnedByUser is an http handler that returns the cluster inventories owned
+// by the user doing the request.
+func (h *Handlers) GetOwnedByUser(w http.ResponseWriter, r *http.Request) {
+	dataJSON, err := h.inventoryManager.GetOwnedByUserJSON(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetOwnedByUser").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
+// Update is an http handler that updates the provided cluster inventory in the
+// database.
+func (h *Handlers) Update(w http.ResponseWriter, r *http.Request) {
+	inv := &hub.ClusterInventory{}
+	if err := json.NewDecoder(r.Body).Decode(&inv); err != nil {
+		h.logger.Error().Err(err).Str("method", "Update").Msg(hub.ErrInvalidInput.Error())
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
+		return
+	}
+	inv.ClusterInventoryID = chi.URLParam(r, "clusterInventoryID")
+	if err := h.inventoryManager.Update(r.Context(), inv); err != nil {
+		h.logger.Error().Err(err).Str("method", "Update").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
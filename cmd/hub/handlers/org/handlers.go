@@ -6,9 +6,9 @@ import (
 
 	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/go-chi/chi"
 	"github.com/rs/zerolog"
-	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
 )
 
@@ -25,7 +25,7 @@ func NewHandlers(orgManager hub.OrganizationManager, cfg *viper.Viper) *Handlers
 	return &Handlers{
 		orgManager: orgManager,
 		cfg:        cfg,
-		logger:     log.With().Str("handlers", "org").Logger(),
+		logger:     util.LogWith("handlers").Str("handlers", "org").Logger(),
 	}
 }
 
@@ -34,12 +34,12 @@ func (h *Handlers) Add(w http.ResponseWriter, r *http.Request) {
 	o := &hub.Organization{}
 	if err := json.NewDecoder(r.Body).Decode(&o); err != nil {
 		h.logger.Error().Err(err).Str("method", "Add").Msg("invalid organization")
-		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
 		return
 	}
 	if err := h.orgManager.Add(r.Context(), o); err != nil {
 		h.logger.Error().Err(err).Str("method", "Add").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusCreated)
@@ -53,7 +53,20 @@ func (h *Handlers) AddMember(w http.ResponseWriter, r *http.Request) {
 	err := h.orgManager.AddMember(r.Context(), orgName, userAlias, baseURL)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "AddMember").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+// AddShare is an http handler that grants the target organization provided
+// read access to the repositories of the organization provided.
+func (h *Handlers) AddShare(w http.ResponseWriter, r *http.Request) {
+	orgName := chi.URLParam(r, "orgName")
+	targetOrgName := chi.URLParam(r, "targetOrgName")
+	if err := h.orgManager.AddShare(r.Context(), orgName, targetOrgName); err != nil {
+		h.logger.Error().Err(err).Str("method", "AddShare").Send()
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusCreated)
@@ -68,7 +81,7 @@ func (h *Handlers) CheckAvailability(w http.ResponseWriter, r *http.Request) {
 	available, err := h.orgManager.CheckAvailability(r.Context(), resourceKind, value)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "CheckAvailability").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	if available {
@@ -84,20 +97,50 @@ func (h *Handlers) ConfirmMembership(w http.ResponseWriter, r *http.Request) {
 	orgName := chi.URLParam(r, "orgName")
 	if err := h.orgManager.ConfirmMembership(r.Context(), orgName); err != nil {
 		h.logger.Error().Err(err).Str("method", "ConfirmMembership").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
 // DeleteMember is an http handler that deletes a member from the provided
-// organization.
+// organization. When a dry run is requested, the impact of removing the member
+// is returned instead and the membership is left untouched.
 func (h *Handlers) DeleteMember(w http.ResponseWriter, r *http.Request) {
 	orgName := chi.URLParam(r, "orgName")
 	userAlias := chi.URLParam(r, "userAlias")
+	dryRun, err := helpers.IsDryRun(r)
+	if err != nil {
+		h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "DeleteMember").Msg("invalid query")
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	if dryRun {
+		dataJSON, err := h.orgManager.GetMemberDeletionImpactJSON(r.Context(), orgName, userAlias)
+		if err != nil {
+			h.logger.Error().Err(err).Str("method", "DeleteMember").Send()
+			helpers.RenderErrorJSON(w, r, err)
+			return
+		}
+		helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+		return
+	}
 	if err := h.orgManager.DeleteMember(r.Context(), orgName, userAlias); err != nil {
 		h.logger.Error().Err(err).Str("method", "DeleteMember").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteShare is an http handler that revokes the read access to the
+// repositories of the organization provided granted to the target organization.
+func (h *Handlers) DeleteShare(w http.ResponseWriter, r *http.Request) {
+	orgName := chi.URLParam(r, "orgName")
+	targetOrgName := chi.URLParam(r, "targetOrgName")
+	if err := h.orgManager.DeleteShare(r.Context(), orgName, targetOrgName); err != nil {
+		h.logger.Error().Err(err).Str("method", "DeleteShare").Send()
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -109,7 +152,7 @@ func (h *Handlers) Get(w http.ResponseWriter, r *http.Request) {
 	dataJSON, err := h.orgManager.GetJSON(r.Context(), orgName)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "Get").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
@@ -121,7 +164,7 @@ func (h *Handlers) GetByUser(w http.ResponseWriter, r *http.Request) {
 	dataJSON, err := h.orgManager.GetByUserJSON(r.Context())
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "GetByUser").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
@@ -134,7 +177,33 @@ func (h *Handlers) GetMembers(w http.ResponseWriter, r *http.Request) {
 	dataJSON, err := h.orgManager.GetMembersJSON(r.Context(), orgName)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "GetMembers").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
+// GetShares is an http handler that returns the organizations the repositories
+// of the provided organization have been shared with.
+func (h *Handlers) GetShares(w http.ResponseWriter, r *http.Request) {
+	orgName := chi.URLParam(r, "orgName")
+	dataJSON, err := h.orgManager.GetSharesJSON(r.Context(), orgName)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetShares").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
+// GetValidationWebhook is an http handler that returns the validation webhook
+// of the provided organization.
+func (h *Handlers) GetValidationWebhook(w http.ResponseWriter, r *http.Request) {
+	orgName := chi.URLParam(r, "orgName")
+	dataJSON, err := h.orgManager.GetValidationWebhookJSON(r.Context(), orgName)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetValidationWebhook").Send()
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
@@ -146,13 +215,52 @@ func (h *Handlers) Update(w http.ResponseWriter, r *http.Request) {
 	o := &hub.Organization{}
 	if err := json.NewDecoder(r.Body).Decode(&o); err != nil {
 		h.logger.Error().Err(err).Str("method", "Update").Msg("invalid organization")
-		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
 		return
 	}
 	o.Name = chi.URLParam(r, "orgName")
 	if err := h.orgManager.Update(r.Context(), o); err != nil {
 		h.logger.Error().Err(err).Str("method", "Update").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UpdateFeaturedPackages is an http handler that replaces the packages
+// featured in the profile of the provided organization.
+func (h *Handlers) UpdateFeaturedPackages(w http.ResponseWriter, r *http.Request) {
+	orgName := chi.URLParam(r, "orgName")
+	input := &struct {
+		PackagesIDs []string `json:"packages_ids"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Error().Err(err).Str("method", "UpdateFeaturedPackages").Msg("invalid input")
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
+		return
+	}
+	err := h.orgManager.UpdateFeaturedPackages(r.Context(), orgName, input.PackagesIDs)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "UpdateFeaturedPackages").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UpdateValidationWebhook is an http handler that updates the validation
+// webhook of the provided organization.
+func (h *Handlers) UpdateValidationWebhook(w http.ResponseWriter, r *http.Request) {
+	orgName := chi.URLParam(r, "orgName")
+	vw := &hub.ValidationWebhook{}
+	if err := json.NewDecoder(r.Body).Decode(&vw); err != nil {
+		h.logger.Error().Err(err).Str("method", "UpdateValidationWebhook").Msg("invalid validation webhook")
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
+		return
+	}
+	if err := h.orgManager.UpdateValidationWebhook(r.Context(), orgName, vw); err != nil {
+		h.logger.Error().Err(err).Str("method", "UpdateValidationWebhook").Send()
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
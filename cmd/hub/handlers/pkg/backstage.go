@@ -0,0 +1,153 @@
+package pkg
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+const (
+	// backstageAPIVersion represents the version of the Backstage catalog
+	// entities format used.
+	backstageAPIVersion = "backstage.io/v1alpha1"
+
+	// backstageMaxNameLength represents the maximum length of the Backstage
+	// entities names and tags.
+	backstageMaxNameLength = 63
+)
+
+var (
+	// backstageInvalidNameCharsRE is a regexp used to find the characters not
+	// allowed in the Backstage entities names.
+	backstageInvalidNameCharsRE = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+	// backstageTagRE is a regexp used to validate the Backstage entities tags.
+	backstageTagRE = regexp.MustCompile(`^[a-z0-9+#]+(-[a-z0-9+#]+)*$`)
+)
+
+// backstageEntity represents a Backstage catalog entity.
+type backstageEntity struct {
+	APIVersion string                  `yaml:"apiVersion"`
+	Kind       string                  `yaml:"kind"`
+	Metadata   backstageEntityMetadata `yaml:"metadata"`
+	Spec       backstageEntitySpec     `yaml:"spec"`
+}
+
+// backstageEntityMetadata represents the metadata of a Backstage entity.
+type backstageEntityMetadata struct {
+	Name        string            `yaml:"name"`
+	Title       string            `yaml:"title,omitempty"`
+	Description string            `yaml:"description,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+	Tags        []string          `yaml:"tags,omitempty"`
+	Links       []*backstageLink  `yaml:"links,omitempty"`
+}
+
+// backstageLink represents a link of a Backstage entity.
+type backstageLink struct {
+	URL   string `yaml:"url"`
+	Title string `yaml:"title,omitempty"`
+}
+
+// backstageEntitySpec represents the spec of a Backstage entity.
+type backstageEntitySpec struct {
+	Type      string   `yaml:"type"`
+	Lifecycle string   `yaml:"lifecycle,omitempty"`
+	Owner     string   `yaml:"owner"`
+	DependsOn []string `yaml:"dependsOn,omitempty"`
+}
+
+// buildBackstageEntities builds the Backstage catalog entities for the
+// packages provided. Each repository is exported as a Resource and each
+// package as a Component that depends on the repository it belongs to.
+func buildBackstageEntities(baseURL string, packages []*hub.Package) []interface{} {
+	var entities []interface{}
+	repositories := make(map[string]struct{})
+	for _, p := range packages {
+		r := p.Repository
+		owner := "user:" + backstageName(r.UserAlias)
+		if r.OrganizationName != "" {
+			owner = "group:" + backstageName(r.OrganizationName)
+		}
+		repoEntityName := backstageName(r.Name)
+
+		// Repository
+		if _, ok := repositories[r.RepositoryID]; !ok {
+			repositories[r.RepositoryID] = struct{}{}
+			entities = append(entities, &backstageEntity{
+				APIVersion: backstageAPIVersion,
+				Kind:       "Resource",
+				Metadata: backstageEntityMetadata{
+					Name:  repoEntityName,
+					Title: r.DisplayName,
+					Annotations: map[string]string{
+						"artifacthub.io/repository-id": r.RepositoryID,
+					},
+					Links: []*backstageLink{{URL: r.URL, Title: "Repository"}},
+				},
+				Spec: backstageEntitySpec{
+					Type:  hub.GetKindName(r.Kind) + "-repository",
+					Owner: owner,
+				},
+			})
+		}
+
+		// Package
+		lifecycle := "production"
+		if p.Deprecated {
+			lifecycle = "deprecated"
+		}
+		links := []*backstageLink{{URL: BuildPackageURL(baseURL, p, ""), Title: "Artifact Hub"}}
+		if p.HomeURL != "" {
+			links = append(links, &backstageLink{URL: p.HomeURL, Title: "Home"})
+		}
+		entities = append(entities, &backstageEntity{
+			APIVersion: backstageAPIVersion,
+			Kind:       "Component",
+			Metadata: backstageEntityMetadata{
+				Name:        backstageName(r.Name + "-" + p.NormalizedName),
+				Title:       p.DisplayName,
+				Description: p.Description,
+				Annotations: map[string]string{
+					"artifacthub.io/package-id": p.PackageID,
+					"artifacthub.io/version":    p.Version,
+				},
+				Tags:  backstageTags(p.Keywords),
+				Links: links,
+			},
+			Spec: backstageEntitySpec{
+				Type:      hub.GetKindName(r.Kind) + "-package",
+				Lifecycle: lifecycle,
+				Owner:     owner,
+				DependsOn: []string{fmt.Sprintf("resource:%s", repoEntityName)},
+			},
+		})
+	}
+	return entities
+}
+
+// backstageName returns a valid Backstage entity name from the value
+// provided, replacing the characters not allowed.
+func backstageName(v string) string {
+	name := backstageInvalidNameCharsRE.ReplaceAllString(v, "-")
+	if len(name) > backstageMaxNameLength {
+		name = name[:backstageMaxNameLength]
+	}
+	return strings.Trim(name, "-_.")
+}
+
+// backstageTags returns the keywords provided that can be used as Backstage
+// entity tags, normalized as required.
+func backstageTags(keywords []string) []string {
+	var tags []string
+	for _, kw := range keywords {
+		tag := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(kw)), " ", "-")
+		if len(tag) > backstageMaxNameLength || !backstageTagRE.MatchString(tag) {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
@@ -0,0 +1,199 @@
+package pkg
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// maxEventsReplay represents the maximum number of recent packages changes
+	// kept to be replayed to the clients resuming an events stream using the
+	// Last-Event-ID header. Older changes are not replayed.
+	maxEventsReplay = 1000
+
+	// eventsIdleTimeout represents how long the events broker keeps tracking
+	// the changes registered once there are no clients subscribed. Clients
+	// are expected to reconnect within this period to not miss any changes.
+	eventsIdleTimeout = time.Minute
+
+	// subscriberBufferSize represents the number of changes batches that can
+	// be queued for a subscriber before it is considered too slow and it is
+	// disconnected.
+	subscriberBufferSize = 16
+)
+
+// eventsBroker polls periodically the packages changes registered and
+// broadcasts them to all the events streams subscribed, so that the number of
+// queries performed does not depend on the number of clients connected.
+type eventsBroker struct {
+	pkgManager hub.PackageManager
+	interval   time.Duration
+	logger     zerolog.Logger
+	start      sync.Once
+	ctx        context.Context
+	cancel     context.CancelFunc
+
+	// Only accessed from the poller goroutine
+	since     int64
+	cursor    int64
+	idleSince time.Time
+
+	mu          sync.Mutex
+	stopped     bool
+	subscribers map[chan []*hub.PackageChange]struct{}
+	recent      []*hub.PackageChange
+}
+
+// newEventsBroker creates a new eventsBroker instance.
+func newEventsBroker(pm hub.PackageManager, interval time.Duration, logger zerolog.Logger) *eventsBroker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &eventsBroker{
+		pkgManager:  pm,
+		interval:    interval,
+		logger:      logger,
+		ctx:         ctx,
+		cancel:      cancel,
+		subscribers: make(map[chan []*hub.PackageChange]struct{}),
+	}
+}
+
+// subscribe registers a new subscriber, returning the channel where the new
+// changes will be delivered. When a last event id is provided, the recent
+// changes registered after it are returned as well so that they can be
+// replayed. The poller is started on the first subscription. Once the broker
+// has been stopped, the channel returned is already closed.
+func (b *eventsBroker) subscribe(lastEventID int64) (chan []*hub.PackageChange, []*hub.PackageChange) {
+	b.start.Do(func() {
+		go b.run(b.ctx)
+	})
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ch := make(chan []*hub.PackageChange, subscriberBufferSize)
+	if b.stopped {
+		close(ch)
+		return ch, nil
+	}
+	b.subscribers[ch] = struct{}{}
+	var replay []*hub.PackageChange
+	if lastEventID > 0 {
+		for _, c := range b.recent {
+			if c.PackageChangeID > lastEventID {
+				replay = append(replay, c)
+			}
+		}
+	}
+	return ch, replay
+}
+
+// unsubscribe removes the subscriber provided.
+func (b *eventsBroker) unsubscribe(ch chan []*hub.PackageChange) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subscribers[ch]; ok {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
+
+// stop stops the poller and disconnects all the subscribers.
+func (b *eventsBroker) stop() {
+	b.cancel()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stopped = true
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
+
+// run polls the packages changes registered every interval until the context
+// provided is cancelled.
+func (b *eventsBroker) run(ctx context.Context) {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		b.poll(ctx)
+	}
+}
+
+// poll gets the packages changes registered since the last poll and
+// broadcasts them to the subscribers. Changes are only tracked while there
+// are clients subscribed (or have been recently).
+func (b *eventsBroker) poll(ctx context.Context) {
+	b.mu.Lock()
+	subscribers := len(b.subscribers)
+	b.mu.Unlock()
+	if subscribers == 0 {
+		if b.idleSince.IsZero() {
+			b.idleSince = time.Now()
+		}
+		if time.Since(b.idleSince) > eventsIdleTimeout {
+			b.since, b.cursor = 0, 0
+			return
+		}
+	} else {
+		b.idleSince = time.Time{}
+	}
+	if b.since == 0 {
+		b.since = time.Now().Unix() - 1
+	}
+
+	for {
+		input := &hub.GetPackageChangesInput{
+			Since:  b.since,
+			Cursor: b.cursor,
+			Limit:  defaultChangesLimit,
+		}
+		dataJSON, err := b.pkgManager.GetChangesJSON(ctx, input)
+		if err != nil {
+			if ctx.Err() == nil {
+				b.logger.Error().Err(err).Str("method", "Events").Send()
+			}
+			return
+		}
+		var page *hub.PackageChangesPage
+		if err := json.Unmarshal(dataJSON, &page); err != nil {
+			b.logger.Error().Err(err).Str("method", "Events").Send()
+			return
+		}
+		if len(page.Changes) > 0 {
+			b.cursor = page.Changes[len(page.Changes)-1].PackageChangeID
+			b.broadcast(page.Changes)
+		}
+		if page.NextCursor == nil {
+			return
+		}
+	}
+}
+
+// broadcast delivers the changes provided to all the subscribers, keeping the
+// latest ones to be replayed. Subscribers that are not keeping up are
+// disconnected, they can resume the stream using the Last-Event-ID header.
+func (b *eventsBroker) broadcast(changes []*hub.PackageChange) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.recent = append(b.recent, changes...)
+	if len(b.recent) > maxEventsReplay {
+		b.recent = append([]*hub.PackageChange(nil), b.recent[len(b.recent)-maxEventsReplay:]...)
+	}
+	for ch := range b.subscribers {
+		select {
+		case ch <- changes:
+		default:
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
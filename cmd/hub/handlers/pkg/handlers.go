@@ -2,38 +2,211 @@ package pkg
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"sort"
 	"strconv"
+	"strings"
+	"text/template"
 	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/go-chi/chi"
 	"github.com/gorilla/feeds"
 	"github.com/rs/zerolog"
-	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// defaultEventsPollInterval represents how often new changes are checked
+	// when streaming events, if no interval has been configured.
+	defaultEventsPollInterval = 5 * time.Second
+
+	// eventsStreamDuration represents the maximum duration of an events
+	// stream. It must be lower than the server's write timeout. Clients are
+	// expected to reconnect (EventSource does it automatically), using the
+	// Last-Event-ID header to resume the stream.
+	eventsStreamDuration = 25 * time.Second
+
+	// eolWarning represents the value of the Warning header added to the
+	// responses of requests for package versions that reached their EOL.
+	eolWarning = `299 - "This package version has reached its end of life"`
+
+	// latestVersionsCacheMaxAge represents the cache max age used for the
+	// packages latest versions responses. They are expected to be requested
+	// at high frequency by automated dependency update tools.
+	latestVersionsCacheMaxAge = 15 * time.Minute
+
+	// defaultChangesLimit represents the maximum number of packages versions
+	// changes returned when no limit is provided.
+	defaultChangesLimit = 1000
+
+	// defaultTagsLimit represents the maximum number of tags returned when no
+	// limit is provided.
+	defaultTagsLimit = 100
+
+	// maxPushPayloadSize represents the maximum size of the package metadata
+	// that can be pushed in a single request.
+	maxPushPayloadSize = 1 << 20
+
+	// maxInstalledPayloadSize represents the maximum size of the installed
+	// releases list that can be matched in a single request.
+	maxInstalledPayloadSize = 1 << 20
 )
 
 // Handlers represents a group of http handlers in charge of handling packages
 // operations.
 type Handlers struct {
-	pkgManager hub.PackageManager
-	cfg        *viper.Viper
-	logger     zerolog.Logger
+	pkgManager           hub.PackageManager
+	cfg                  *viper.Viper
+	events               *eventsBroker
+	eventsStreamDuration time.Duration
+	installTmpls         map[hub.RepositoryKind]*template.Template
+	logger               zerolog.Logger
 }
 
-// NewHandlers creates a new Handlers instance.
-func NewHandlers(pkgManager hub.PackageManager, cfg *viper.Viper) *Handlers {
+// NewHandlers creates a new Handlers instance. An error is returned when the
+// custom install instructions templates configured cannot be loaded.
+func NewHandlers(pkgManager hub.PackageManager, cfg *viper.Viper) (*Handlers, error) {
+	eventsPollInterval := cfg.GetDuration("server.eventsPollInterval")
+	if eventsPollInterval <= 0 {
+		eventsPollInterval = defaultEventsPollInterval
+	}
+	installTmpls, err := loadInstallTemplates(cfg)
+	if err != nil {
+		return nil, err
+	}
+	logger := util.LogWith("handlers").Str("handlers", "pkg").Logger()
 	return &Handlers{
-		pkgManager: pkgManager,
-		cfg:        cfg,
-		logger:     log.With().Str("handlers", "pkg").Logger(),
+		pkgManager:           pkgManager,
+		cfg:                  cfg,
+		events:               newEventsBroker(pkgManager, eventsPollInterval, logger),
+		eventsStreamDuration: eventsStreamDuration,
+		installTmpls:         installTmpls,
+		logger:               logger,
+	}, nil
+}
+
+// Close stops the events broker, disconnecting the events streams clients.
+func (h *Handlers) Close() {
+	h.events.stop()
+}
+
+// Events is an http handler that streams, as server-sent events, the new
+// packages versions registered in the hub. Events can be filtered by
+// repository kind (kind) and repository name (repo). The changes are polled
+// by a single events broker shared by all the streams.
+func (h *Handlers) Events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		helpers.RenderErrorJSON(w, r, errors.New("streaming not supported"))
+		return
+	}
+	filter, err := buildEventsFilter(r.URL.Query())
+	if err != nil {
+		err = fmt.Errorf("%w: %s", hub.ErrInvalidInput, err.Error())
+		h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "Events").Msg("invalid query")
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	lastEventID, _ := strconv.ParseInt(r.Header.Get("Last-Event-ID"), 10, 64)
+	changes, replay := h.events.subscribe(lastEventID)
+	defer h.events.unsubscribe(changes)
+
+	// Stream events until the client goes away or the stream duration expires
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	writeEvents(w, replay, filter)
+	flusher.Flush()
+	ctx, cancel := context.WithTimeout(r.Context(), h.eventsStreamDuration)
+	defer cancel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case batch, ok := <-changes:
+			if !ok {
+				return
+			}
+			writeEvents(w, batch, filter)
+			flusher.Flush()
+		}
+	}
+}
+
+// writeEvents writes the new packages versions in the changes provided that
+// match the filter given as server-sent events.
+func writeEvents(w io.Writer, changes []*hub.PackageChange, filter *eventsFilter) {
+	for _, c := range changes {
+		if c.ChangeKind != "created" || !filter.matches(c) {
+			continue
+		}
+		cJSON, _ := json.Marshal(c)
+		fmt.Fprintf(w, "id: %d\nevent: new-release\ndata: %s\n\n", c.PackageChangeID, cJSON)
+	}
+}
+
+// eventsFilter represents the filter applied to the events streamed.
+type eventsFilter struct {
+	kinds        []hub.RepositoryKind
+	repositories []string
+}
+
+// matches checks if the package change provided matches the filter.
+func (f *eventsFilter) matches(c *hub.PackageChange) bool {
+	if len(f.kinds) > 0 {
+		var found bool
+		for _, kind := range f.kinds {
+			if c.RepositoryKind == kind {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.repositories) > 0 {
+		var found bool
+		for _, name := range f.repositories {
+			if c.RepositoryName == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// buildEventsFilter builds an events filter from a map of query string values,
+// validating them as they are extracted.
+func buildEventsFilter(qs url.Values) (*eventsFilter, error) {
+	kinds := make([]hub.RepositoryKind, 0, len(qs["kind"]))
+	for _, kindStr := range qs["kind"] {
+		kind, err := strconv.Atoi(kindStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kind: %s", kindStr)
+		}
+		kinds = append(kinds, hub.RepositoryKind(kind))
 	}
+	return &eventsFilter{
+		kinds:        kinds,
+		repositories: qs["repo"],
+	}, nil
 }
 
 // Get is an http handler used to get a package details.
@@ -46,34 +219,324 @@ func (h *Handlers) Get(w http.ResponseWriter, r *http.Request) {
 	if repoName != "" {
 		input.RepositoryName = repoName
 	}
+	fields, omit, err := buildFieldsSelection(r.URL.Query())
+	if err != nil {
+		h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "Get").Msg("invalid query")
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
 	dataJSON, err := h.pkgManager.GetJSON(r.Context(), input)
 	if err != nil {
 		h.logger.Error().Err(err).Interface("input", input).Str("method", "Get").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	if isEOL(dataJSON) {
+		w.Header().Set("Warning", eolWarning)
+	}
+	cacheMaxAge := helpers.DefaultAPICacheMaxAge
+	if isPrivate(dataJSON) {
+		cacheMaxAge = 0
+	}
+	dataJSON, err = selectFields(dataJSON, fields, omit)
+	if err != nil {
+		h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "Get").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, cacheMaxAge, http.StatusOK)
+}
+
+// GetAll is an http handler used to iterate over all the packages available,
+// sorted by id. The next_cursor value returned must be used as the cursor to
+// get the next page.
+func (h *Handlers) GetAll(w http.ResponseWriter, r *http.Request) {
+	input, err := buildGetAllInput(r.URL.Query())
+	if err != nil {
+		err = fmt.Errorf("%w: %s", hub.ErrInvalidInput, err.Error())
+		h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "GetAll").Msg("invalid query")
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	dataJSON, err := h.pkgManager.GetAllJSON(r.Context(), input)
+	if err != nil {
+		h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "GetAll").Send()
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	helpers.RenderJSON(w, dataJSON, helpers.DefaultAPICacheMaxAge, http.StatusOK)
 }
 
+// GetBackstageEntities is an http handler used to export the packages of the
+// given owner (user alias or organization name) as Backstage catalog entities.
+func (h *Handlers) GetBackstageEntities(w http.ResponseWriter, r *http.Request) {
+	owner := r.URL.Query().Get("owner")
+	packages, err := h.pkgManager.GetByOwner(r.Context(), owner)
+	if err != nil {
+		h.logger.Error().Err(err).Str("owner", owner).Str("method", "GetBackstageEntities").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	entities := buildBackstageEntities(h.cfg.GetString("server.baseURL"), packages)
+	data, err := marshalYAMLDocuments(entities)
+	if err != nil {
+		h.logger.Error().Err(err).Str("owner", owner).Str("method", "GetBackstageEntities").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-yaml")
+	w.Header().Set("Cache-Control", helpers.BuildCacheControlHeader(helpers.GetCacheMaxAge(r, helpers.DefaultAPICacheMaxAge)))
+	_, _ = w.Write(data)
+}
+
+// GetChanges is an http handler used to get a page of the packages versions
+// created, updated or deleted since the time provided in the since query
+// parameter. The next_cursor value returned can be provided in the cursor
+// query parameter to get the next page.
+func (h *Handlers) GetChanges(w http.ResponseWriter, r *http.Request) {
+	input, err := buildGetChangesInput(r.URL.Query())
+	if err == nil {
+		err = h.checkChangesRetention(input.Since)
+	}
+	if err != nil {
+		err = fmt.Errorf("%w: %s", hub.ErrInvalidInput, err.Error())
+		h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "GetChanges").Msg("invalid query")
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	dataJSON, err := h.pkgManager.GetChangesJSON(r.Context(), input)
+	if err != nil {
+		h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "GetChanges").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
+// checkChangesRetention checks that the packages versions changes registered
+// since the time provided have not been pruned yet, based on the retention
+// period configured for them (if any). Requests for older changes are
+// rejected, so that clients can detect they may have missed some of them.
+// A since value of zero requests all the changes available, so it is always
+// accepted (i.e. when a mirror is bootstrapped).
+func (h *Handlers) checkChangesRetention(since int64) error {
+	maxAge := h.cfg.GetDuration("server.retention.policies.packages_changes")
+	if since > 0 && maxAge > 0 && since < time.Now().Add(-maxAge).Unix() {
+		return fmt.Errorf("since is older than the packages changes retention period (%s)", maxAge)
+	}
+	return nil
+}
+
+// GetDependenciesGraph is an http handler used to get the dependencies graph
+// of a given package version, linking its dependencies to the packages
+// available in the hub.
+func (h *Handlers) GetDependenciesGraph(w http.ResponseWriter, r *http.Request) {
+	input := &hub.GetPackageInput{
+		PackageName:    chi.URLParam(r, "packageName"),
+		RepositoryName: chi.URLParam(r, "repoName"),
+		Version:        chi.URLParam(r, "version"),
+	}
+	dataJSON, err := h.pkgManager.GetDependenciesGraphJSON(r.Context(), input)
+	if err != nil {
+		h.logger.Error().Err(err).Interface("input", input).Str("method", "GetDependenciesGraph").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, helpers.GetCacheMaxAge(r, helpers.DefaultAPICacheMaxAge), http.StatusOK)
+}
+
+// GetVersions is an http handler used to get the versions available of a
+// given package. Versions can be rendered as json, csv or ndjson, depending on
+// the format requested in the Accept header. A semver range can be provided
+// using the range query parameter to get only the versions satisfying it.
+func (h *Handlers) GetVersions(w http.ResponseWriter, r *http.Request) {
+	// Parse versions range, if provided
+	var c *semver.Constraints
+	if v := r.FormValue("range"); v != "" {
+		var err error
+		c, err = semver.NewConstraint(v)
+		if err != nil {
+			helpers.RenderErrorJSON(w, r, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid range"))
+			return
+		}
+	}
+
+	// Get package details
+	input := &hub.GetPackageInput{
+		PackageName: chi.URLParam(r, "packageName"),
+	}
+	repoName := chi.URLParam(r, "repoName")
+	if repoName != "" {
+		input.RepositoryName = repoName
+	}
+	p, err := h.pkgManager.Get(r.Context(), input)
+	if err != nil {
+		h.logger.Error().Err(err).Interface("input", input).Str("method", "GetVersions").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+
+	// Filter versions by range and sort them, newest first
+	versions := p.AvailableVersions
+	if c != nil {
+		versions = filterVersionsByRange(versions, c)
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		vi, errI := semver.NewVersion(versions[i].Version)
+		vj, errJ := semver.NewVersion(versions[j].Version)
+		if errI != nil || errJ != nil {
+			return false
+		}
+		return vj.LessThan(vi)
+	})
+
+	// Render versions using the format requested
+	w.Header().Set("Vary", "Accept")
+	switch helpers.GetOutputFormat(r) {
+	case helpers.FormatCSV:
+		records := make([][]string, 0, len(versions))
+		for _, v := range versions {
+			records = append(records, []string{v.Version, strconv.FormatInt(v.CreatedAt, 10)})
+		}
+		helpers.RenderCSV(w, []string{"version", "created_at"}, records, helpers.DefaultAPICacheMaxAge, http.StatusOK)
+	case helpers.FormatNDJSON:
+		items := make([]json.RawMessage, 0, len(versions))
+		for _, v := range versions {
+			item, _ := json.Marshal(v)
+			items = append(items, item)
+		}
+		helpers.RenderNDJSON(w, items, helpers.DefaultAPICacheMaxAge, http.StatusOK)
+	default:
+		if versions == nil {
+			versions = []*hub.Version{}
+		}
+		dataJSON, _ := json.Marshal(versions)
+		helpers.RenderJSON(w, dataJSON, helpers.DefaultAPICacheMaxAge, http.StatusOK)
+	}
+}
+
+// filterVersionsByRange returns the versions provided that satisfy the range
+// constraints given. Versions that are not valid semver are discarded. As in
+// other tools supporting ranges, prereleases only satisfy the range when it
+// includes a prerelease comparison.
+func filterVersionsByRange(versions []*hub.Version, c *semver.Constraints) []*hub.Version {
+	var filtered []*hub.Version
+	for _, v := range versions {
+		sv, err := semver.NewVersion(v.Version)
+		if err != nil {
+			continue
+		}
+		if c.Check(sv) {
+			filtered = append(filtered, v)
+		}
+	}
+	return filtered
+}
+
+// GetLatestVersions is an http handler used to get the latest stable and
+// prerelease versions of a given package. It's a lightweight alternative to
+// the package details endpoint, meant to be used by automated dependency
+// update tools. Responses include an ETag so that they can be revalidated.
+func (h *Handlers) GetLatestVersions(w http.ResponseWriter, r *http.Request) {
+	input := &hub.GetPackageInput{
+		PackageName:    chi.URLParam(r, "packageName"),
+		RepositoryName: chi.URLParam(r, "repoName"),
+	}
+	dataJSON, err := h.pkgManager.GetLatestVersionsJSON(r.Context(), input)
+	if err != nil {
+		h.logger.Error().Err(err).Interface("input", input).Str("method", "GetLatestVersions").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	helpers.RenderJSONWithETag(w, r, dataJSON, helpers.GetCacheMaxAge(r, latestVersionsCacheMaxAge))
+}
+
+// GetProject is an http handler used to get the packages of other kinds that
+// belong to the same project as the package provided.
+func (h *Handlers) GetProject(w http.ResponseWriter, r *http.Request) {
+	packageID := chi.URLParam(r, "packageID")
+	dataJSON, err := h.pkgManager.GetProjectJSON(r.Context(), packageID)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetProject").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, helpers.GetCacheMaxAge(r, helpers.DefaultAPICacheMaxAge), http.StatusOK)
+}
+
 // GetRandom is an http handler used to get some random packages from the hub
 // database.
 func (h *Handlers) GetRandom(w http.ResponseWriter, r *http.Request) {
 	dataJSON, err := h.pkgManager.GetRandomJSON(r.Context())
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "GetRandom").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	helpers.RenderJSON(w, dataJSON, helpers.DefaultAPICacheMaxAge, http.StatusOK)
 }
 
+// GetInstallInstructions is an http handler used to get the install
+// instructions of the given package version in markdown format. They are
+// rendered using the install template of the package's repository kind, which
+// can be customized by the operators.
+func (h *Handlers) GetInstallInstructions(w http.ResponseWriter, r *http.Request) {
+	input := &hub.GetPackageInput{
+		PackageName:    chi.URLParam(r, "packageName"),
+		Version:        chi.URLParam(r, "version"),
+		RepositoryName: chi.URLParam(r, "repoName"),
+	}
+	p, err := h.pkgManager.Get(r.Context(), input)
+	if err != nil {
+		h.logger.Error().Err(err).Interface("input", input).Str("method", "GetInstallInstructions").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	instructions, err := h.buildInstallInstructions(p)
+	if err != nil {
+		h.logger.Error().Err(err).Interface("input", input).Str("method", "GetInstallInstructions").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	w.Header().Set("Cache-Control", helpers.BuildCacheControlHeader(helpers.DefaultAPICacheMaxAge))
+	_, _ = w.Write(instructions)
+}
+
+// GetSnippet is an http handler used to get a snippet with the manifests
+// required to install the given package version using a GitOps tool (Flux or
+// Argo CD), optionally with the values of one of its values presets.
+func (h *Handlers) GetSnippet(w http.ResponseWriter, r *http.Request) {
+	input := &hub.GetPackageInput{
+		PackageName:    chi.URLParam(r, "packageName"),
+		Version:        chi.URLParam(r, "version"),
+		RepositoryName: chi.URLParam(r, "repoName"),
+	}
+	p, err := h.pkgManager.Get(r.Context(), input)
+	if err != nil {
+		h.logger.Error().Err(err).Interface("input", input).Str("method", "GetSnippet").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	qs := r.URL.Query()
+	snippet, err := buildSnippet(chi.URLParam(r, "tool"), p, qs.Get("preset"), qs.Get("namespace"))
+	if err != nil {
+		h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "GetSnippet").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-yaml")
+	w.Header().Set("Cache-Control", helpers.BuildCacheControlHeader(helpers.DefaultAPICacheMaxAge))
+	_, _ = w.Write(snippet)
+}
+
 // GetStarredByUser is an http handler used to get the packages starred by the
 // user doing the request.
 func (h *Handlers) GetStarredByUser(w http.ResponseWriter, r *http.Request) {
 	dataJSON, err := h.pkgManager.GetStarredByUserJSON(r.Context())
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "GetStarredByUser").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
@@ -86,7 +549,7 @@ func (h *Handlers) GetStars(w http.ResponseWriter, r *http.Request) {
 	dataJSON, err := h.pkgManager.GetStarsJSON(r.Context(), packageID)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "GetStars").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
@@ -98,12 +561,100 @@ func (h *Handlers) GetStats(w http.ResponseWriter, r *http.Request) {
 	dataJSON, err := h.pkgManager.GetStatsJSON(r.Context())
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "GetStats").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, helpers.DefaultAPICacheMaxAge, http.StatusOK)
+}
+
+// GetTags is an http handler used to get the tags used by the packages
+// registered in the hub, along with the number of packages using each of
+// them. It's meant to be used to build tag clouds and tags facets.
+func (h *Handlers) GetTags(w http.ResponseWriter, r *http.Request) {
+	limit := defaultTagsLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		var err error
+		limit, err = strconv.Atoi(v)
+		if err != nil {
+			err = fmt.Errorf("%w: invalid limit: %s", hub.ErrInvalidInput, v)
+			h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "GetTags").Msg("invalid query")
+			helpers.RenderErrorJSON(w, r, err)
+			return
+		}
+	}
+	dataJSON, err := h.pkgManager.GetTagsJSON(r.Context(), limit)
+	if err != nil {
+		h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "GetTags").Send()
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	helpers.RenderJSON(w, dataJSON, helpers.DefaultAPICacheMaxAge, http.StatusOK)
 }
 
+// GetValues is an http handler used to get the default values of the provided
+// package version.
+func (h *Handlers) GetValues(w http.ResponseWriter, r *http.Request) {
+	input := &hub.GetPackageInput{
+		PackageName:    chi.URLParam(r, "packageName"),
+		Version:        chi.URLParam(r, "version"),
+		RepositoryName: chi.URLParam(r, "repoName"),
+	}
+	p, err := h.pkgManager.Get(r.Context(), input)
+	if err != nil {
+		h.logger.Error().Err(err).Interface("input", input).Str("method", "GetValues").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	values, ok := p.Data["default_values"].(string)
+	if !ok {
+		helpers.RenderErrorJSON(w, r, hub.ErrNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-yaml")
+	w.Header().Set("Cache-Control", helpers.BuildCacheControlHeader(packageCacheMaxAge(p)))
+	_, _ = w.Write([]byte(values))
+}
+
+// GetValuesSchema is an http handler used to get the values JSON schema of the
+// provided package version.
+func (h *Handlers) GetValuesSchema(w http.ResponseWriter, r *http.Request) {
+	input := &hub.GetPackageInput{
+		PackageName:    chi.URLParam(r, "packageName"),
+		Version:        chi.URLParam(r, "version"),
+		RepositoryName: chi.URLParam(r, "repoName"),
+	}
+	p, err := h.pkgManager.Get(r.Context(), input)
+	if err != nil {
+		h.logger.Error().Err(err).Interface("input", input).Str("method", "GetValuesSchema").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	schema, ok := p.Data["values_schema"]
+	if !ok {
+		helpers.RenderErrorJSON(w, r, hub.ErrNotFound)
+		return
+	}
+	dataJSON, _ := json.Marshal(schema)
+	helpers.RenderJSON(w, dataJSON, packageCacheMaxAge(p), http.StatusOK)
+}
+
+// GetVersionDocs is an http handler used to get the readme and the changelog
+// of a given package version.
+func (h *Handlers) GetVersionDocs(w http.ResponseWriter, r *http.Request) {
+	input := &hub.GetPackageInput{
+		PackageName:    chi.URLParam(r, "packageName"),
+		Version:        chi.URLParam(r, "version"),
+		RepositoryName: chi.URLParam(r, "repoName"),
+	}
+	dataJSON, err := h.pkgManager.GetVersionDocsJSON(r.Context(), input)
+	if err != nil {
+		h.logger.Error().Err(err).Interface("input", input).Str("method", "GetVersionDocs").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, helpers.GetCacheMaxAge(r, helpers.DefaultAPICacheMaxAge), http.StatusOK)
+}
+
 // InjectIndexMeta is a middleware that injects the some index metadata related
 // to a given package,
 func (h *Handlers) InjectIndexMeta(next http.Handler) http.Handler {
@@ -120,7 +671,7 @@ func (h *Handlers) InjectIndexMeta(next http.Handler) http.Handler {
 		p, err := h.pkgManager.Get(r.Context(), input)
 		if err != nil {
 			h.logger.Error().Err(err).Interface("input", input).Str("method", "InjectIndexMeta").Send()
-			helpers.RenderErrorJSON(w, err)
+			helpers.RenderErrorJSON(w, r, err)
 			return
 		}
 		publisher := p.Repository.OrganizationName
@@ -137,6 +688,63 @@ func (h *Handlers) InjectIndexMeta(next http.Handler) http.Handler {
 	})
 }
 
+// MatchInstalled is an http handler used to match the releases installed in a
+// cluster (chart name, version and repository url) against the packages
+// available in the hub, providing the status of the versions installed.
+func (h *Handlers) MatchInstalled(w http.ResponseWriter, r *http.Request) {
+	data, err := ioutil.ReadAll(io.LimitReader(r.Body, maxInstalledPayloadSize+1))
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "MatchInstalled").Msg("error reading request body")
+		helpers.RenderErrorWithCodeJSON(w, nil, http.StatusInternalServerError)
+		return
+	}
+	if len(data) > maxInstalledPayloadSize {
+		helpers.RenderErrorWithCodeJSON(w, nil, http.StatusRequestEntityTooLarge)
+		return
+	}
+	var releases []*hub.InstalledRelease
+	if err := json.Unmarshal(data, &releases); err != nil {
+		h.logger.Error().Err(err).Str("method", "MatchInstalled").Msg(hub.ErrInvalidInput.Error())
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
+		return
+	}
+	dataJSON, err := h.pkgManager.MatchInstalledJSON(r.Context(), releases)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "MatchInstalled").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
+// Push is an http handler used to register a package version in the
+// repository the api key used is scoped to. The package metadata is expected
+// in the same format used by the artifacthub-pkg.yml files (YAML or JSON).
+func (h *Handlers) Push(w http.ResponseWriter, r *http.Request) {
+	data, err := ioutil.ReadAll(io.LimitReader(r.Body, maxPushPayloadSize+1))
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "Push").Msg("error reading request body")
+		helpers.RenderErrorWithCodeJSON(w, nil, http.StatusInternalServerError)
+		return
+	}
+	if len(data) > maxPushPayloadSize {
+		helpers.RenderErrorWithCodeJSON(w, nil, http.StatusRequestEntityTooLarge)
+		return
+	}
+	var md *hub.PackageMetadata
+	if err := yaml.Unmarshal(data, &md); err != nil {
+		h.logger.Error().Err(err).Str("method", "Push").Msg(hub.ErrInvalidInput.Error())
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
+		return
+	}
+	if err := h.pkgManager.Push(r.Context(), md); err != nil {
+		h.logger.Error().Err(err).Str("method", "Push").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
 // RssFeed is an http handler used to get the RSS feed of a given package.
 func (h *Handlers) RssFeed(w http.ResponseWriter, r *http.Request) {
 	// Get package details
@@ -150,7 +758,7 @@ func (h *Handlers) RssFeed(w http.ResponseWriter, r *http.Request) {
 	p, err := h.pkgManager.Get(r.Context(), input)
 	if err != nil {
 		h.logger.Error().Err(err).Interface("input", input).Str("method", "RssFeed").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 
@@ -191,10 +799,31 @@ func (h *Handlers) RssFeed(w http.ResponseWriter, r *http.Request) {
 		return vj.LessThan(vi)
 	})
 
-	w.Header().Set("Cache-Control", helpers.BuildCacheControlHeader(helpers.DefaultAPICacheMaxAge))
+	w.Header().Set("Cache-Control", helpers.BuildCacheControlHeader(packageCacheMaxAge(p)))
 	_ = feed.WriteRss(w)
 }
 
+// Resolve is an http handler used to resolve the hub packages matching some
+// external identifiers: the repository kind and url along with the package
+// name, or the source url of the package.
+func (h *Handlers) Resolve(w http.ResponseWriter, r *http.Request) {
+	input, err := buildResolveInput(r.URL.Query())
+	if err != nil {
+		err = fmt.Errorf("%w: %s", hub.ErrInvalidInput, err.Error())
+		h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "Resolve").Msg("invalid query")
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	packages, err := h.pkgManager.Resolve(r.Context(), input)
+	if err != nil {
+		h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "Resolve").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	dataJSON, _ := json.Marshal(buildResolvedPackages(h.cfg.GetString("server.baseURL"), packages))
+	helpers.RenderJSON(w, dataJSON, helpers.GetCacheMaxAge(r, helpers.DefaultAPICacheMaxAge), http.StatusOK)
+}
+
 // Search is an http handler used to searchPackages for packages in the hub
 // database.
 func (h *Handlers) Search(w http.ResponseWriter, r *http.Request) {
@@ -202,16 +831,115 @@ func (h *Handlers) Search(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		err = fmt.Errorf("%w: %s", hub.ErrInvalidInput, err.Error())
 		h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "Search").Msg("invalid query")
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	dataJSON, err := h.pkgManager.SearchJSON(r.Context(), input)
 	if err != nil {
 		h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "Search").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
-	helpers.RenderJSON(w, dataJSON, helpers.DefaultAPICacheMaxAge, http.StatusOK)
+
+	// Results may include packages in private repositories when the user is
+	// logged in, so they are not cached in that case
+	cacheMaxAge := helpers.GetCacheMaxAge(r, helpers.DefaultAPICacheMaxAge)
+
+	// Render search results using the format requested
+	w.Header().Set("Vary", "Accept")
+	format := helpers.GetOutputFormat(r)
+	if format == helpers.FormatJSON {
+		helpers.RenderJSON(w, dataJSON, cacheMaxAge, http.StatusOK)
+		return
+	}
+	var results *searchResults
+	if err := json.Unmarshal(dataJSON, &results); err != nil {
+		h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "Search").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(results.Metadata.Total))
+	if format == helpers.FormatNDJSON {
+		helpers.RenderNDJSON(w, results.Data.Packages, cacheMaxAge, http.StatusOK)
+		return
+	}
+	records := make([][]string, 0, len(results.Data.Packages))
+	for _, pkgJSON := range results.Data.Packages {
+		var p *searchResultsPackage
+		if err := json.Unmarshal(pkgJSON, &p); err != nil {
+			h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "Search").Send()
+			helpers.RenderErrorJSON(w, r, err)
+			return
+		}
+		records = append(records, p.csvRecord())
+	}
+	helpers.RenderCSV(w, searchResultsCSVHeader, records, cacheMaxAge, http.StatusOK)
+}
+
+// searchResults represents the search results returned by the database, as
+// required to render them in formats other than json.
+type searchResults struct {
+	Data struct {
+		Packages []json.RawMessage `json:"packages"`
+	} `json:"data"`
+	Metadata struct {
+		Total int `json:"total"`
+	} `json:"metadata"`
+}
+
+// searchResultsCSVHeader represents the header used when rendering search
+// results as csv.
+var searchResultsCSVHeader = []string{
+	"package_id",
+	"name",
+	"normalized_name",
+	"display_name",
+	"version",
+	"app_version",
+	"description",
+	"stars",
+	"deprecated",
+	"signed",
+	"kind",
+	"repository_name",
+	"publisher",
+	"created_at",
+}
+
+// searchResultsPackage represents a package in the search results.
+type searchResultsPackage struct {
+	hub.Package
+	Stars int `json:"stars"`
+}
+
+// csvRecord returns the package as a csv record, following the fields order
+// defined in searchResultsCSVHeader.
+func (p *searchResultsPackage) csvRecord() []string {
+	var kind, repositoryName, publisher string
+	if p.Repository != nil {
+		kind = hub.GetKindName(p.Repository.Kind)
+		repositoryName = p.Repository.Name
+		publisher = p.Repository.OrganizationName
+		if publisher == "" {
+			publisher = p.Repository.UserAlias
+		}
+	}
+	return []string{
+		p.PackageID,
+		p.Name,
+		p.NormalizedName,
+		p.DisplayName,
+		p.Version,
+		p.AppVersion,
+		p.Description,
+		strconv.Itoa(p.Stars),
+		strconv.FormatBool(p.Deprecated),
+		strconv.FormatBool(p.Signed),
+		kind,
+		repositoryName,
+		publisher,
+		strconv.FormatInt(p.CreatedAt, 10),
+	}
 }
 
 // ToggleStar is an http handler used to toggle the star on a given package.
@@ -220,12 +948,77 @@ func (h *Handlers) ToggleStar(w http.ResponseWriter, r *http.Request) {
 	err := h.pkgManager.ToggleStar(r.Context(), packageID)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "ToggleStar").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// buildGetAllInput builds the input used to iterate over all packages from a
+// map of query string values, validating them as they are extracted.
+func buildGetAllInput(qs url.Values) (*hub.GetAllPackagesInput, error) {
+	// Limit
+	limit := 100
+	if qs.Get("limit") != "" {
+		var err error
+		limit, err = strconv.Atoi(qs.Get("limit"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit: %s", qs.Get("limit"))
+		}
+	}
+
+	// Kinds
+	kinds := make([]hub.RepositoryKind, 0, len(qs["kind"]))
+	for _, kindStr := range qs["kind"] {
+		kind, err := strconv.Atoi(kindStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kind: %s", kindStr)
+		}
+		kinds = append(kinds, hub.RepositoryKind(kind))
+	}
+
+	return &hub.GetAllPackagesInput{
+		Cursor:          qs.Get("cursor"),
+		Limit:           limit,
+		RepositoryKinds: kinds,
+	}, nil
+}
+
+// buildGetChangesInput builds the input used to get a page of the packages
+// versions changes from a map of query string values, validating them as they
+// are extracted.
+func buildGetChangesInput(qs url.Values) (*hub.GetPackageChangesInput, error) {
+	// Since
+	since, err := strconv.ParseInt(qs.Get("since"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid since: %s", qs.Get("since"))
+	}
+
+	// Cursor
+	var cursor int64
+	if qs.Get("cursor") != "" {
+		cursor, err = strconv.ParseInt(qs.Get("cursor"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor: %s", qs.Get("cursor"))
+		}
+	}
+
+	// Limit
+	limit := defaultChangesLimit
+	if qs.Get("limit") != "" {
+		limit, err = strconv.Atoi(qs.Get("limit"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit: %s", qs.Get("limit"))
+		}
+	}
+
+	return &hub.GetPackageChangesInput{
+		Since:  since,
+		Cursor: cursor,
+		Limit:  limit,
+	}, nil
+}
+
 // buildSearchInput builds a packages search query from a map of query string
 // values, validating them as they are extracted.
 func buildSearchInput(qs url.Values) (*hub.SearchPackageInput, error) {
@@ -289,21 +1082,127 @@ func buildSearchInput(qs url.Values) (*hub.SearchPackageInput, error) {
 		}
 	}
 
+	// Only display packages whose latest version is still supported
+	var supportedOnly bool
+	if qs.Get("supported_only") != "" {
+		var err error
+		supportedOnly, err = strconv.ParseBool(qs.Get("supported_only"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid supported_only: %s", qs.Get("supported_only"))
+		}
+	}
+
 	return &hub.SearchPackageInput{
-		Limit:           limit,
-		Offset:          offset,
-		Facets:          facets,
-		TsQueryWeb:      qs.Get("ts_query_web"),
-		TsQuery:         qs.Get("ts_query"),
-		Users:           qs["user"],
-		Orgs:            qs["org"],
-		Repositories:    qs["repo"],
-		RepositoryKinds: kinds,
-		Operators:       operators,
-		Deprecated:      deprecated,
+		Limit:             limit,
+		Offset:            offset,
+		Facets:            facets,
+		TsQueryWeb:        qs.Get("ts_query_web"),
+		TsQuery:           qs.Get("ts_query"),
+		Users:             qs["user"],
+		Orgs:              qs["org"],
+		Repositories:      qs["repo"],
+		ScopeUser:         qs.Get("scope_user"),
+		ScopeOrg:          qs.Get("scope_org"),
+		ScopeRepository:   qs.Get("scope_repo"),
+		RepositoryKinds:   kinds,
+		Operators:         operators,
+		Deprecated:        deprecated,
+		Capabilities:      qs["capabilities"],
+		ChartTypes:        qs["chart_type"],
+		Tags:              qs["tag"],
+		SupportedOnly:     supportedOnly,
+		KubernetesVersion: qs.Get("kubernetes_version"),
+		Sort:              qs.Get("sort"),
 	}, nil
 }
 
+// buildFieldsSelection extracts the fields to include and omit from the fields
+// and omit query string values. Both accept a comma separated list of fields
+// and can be repeated.
+func buildFieldsSelection(qs url.Values) (fields, omit []string, err error) {
+	fields = splitFieldsList(qs["fields"])
+	omit = splitFieldsList(qs["omit"])
+	for _, f := range append(fields, omit...) {
+		if f == "" {
+			return nil, nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid field name")
+		}
+	}
+	return fields, omit, nil
+}
+
+// selectFields filters the top level fields of the json object provided. When
+// some fields are provided, only those will be kept. The fields in omit will
+// be removed.
+func selectFields(dataJSON []byte, fields, omit []string) ([]byte, error) {
+	if len(fields) == 0 && len(omit) == 0 {
+		return dataJSON, nil
+	}
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal(dataJSON, &data); err != nil {
+		return nil, err
+	}
+	if len(fields) > 0 {
+		selected := make(map[string]json.RawMessage, len(fields))
+		for _, f := range fields {
+			if v, ok := data[f]; ok {
+				selected[f] = v
+			}
+		}
+		data = selected
+	}
+	for _, f := range omit {
+		delete(data, f)
+	}
+	return json.Marshal(data)
+}
+
+// isEOL checks if the package json data provided belongs to a package version
+// that has reached its end of life.
+func isEOL(dataJSON []byte) bool {
+	var p struct {
+		EOL bool `json:"eol"`
+	}
+	if err := json.Unmarshal(dataJSON, &p); err != nil {
+		return false
+	}
+	return p.EOL
+}
+
+// isPrivate checks if the package json data provided belongs to a package in
+// a private repository.
+func isPrivate(dataJSON []byte) bool {
+	var p struct {
+		Repository struct {
+			Private bool `json:"private"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(dataJSON, &p); err != nil {
+		return false
+	}
+	return p.Repository.Private
+}
+
+// packageCacheMaxAge returns the cache max age to use in the responses that
+// include data of the package provided. Packages in private repositories are
+// not cached.
+func packageCacheMaxAge(p *hub.Package) time.Duration {
+	if p.Repository != nil && p.Repository.Private {
+		return 0
+	}
+	return helpers.DefaultAPICacheMaxAge
+}
+
+// splitFieldsList splits the comma separated lists of fields provided.
+func splitFieldsList(values []string) []string {
+	var fields []string
+	for _, v := range values {
+		for _, f := range strings.Split(v, ",") {
+			fields = append(fields, strings.TrimSpace(f))
+		}
+	}
+	return fields
+}
+
 // BuildPackageURL builds the url of a given package.
 func BuildPackageURL(baseURL string, p *hub.Package, version string) string {
 	pkgPath := fmt.Sprintf("/packages/%s/%s/%s",
@@ -6,9 +6,13 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
 	"github.com/artifacthub/hub/internal/hub"
@@ -19,6 +23,7 @@ import (
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMain(m *testing.M) {
@@ -26,6 +31,175 @@ func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
 
+func TestEvents(t *testing.T) {
+	t.Run("invalid request params", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/?kind=z", nil)
+
+		hw := newHandlersWrapper()
+		hw.h.Events(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("events streamed", func(t *testing.T) {
+		changesJSON := []byte(`{"changes": [
+			{
+				"package_change_id": 1,
+				"package_id": "00000000-0000-0000-0000-000000000001",
+				"package_name": "pkg1",
+				"version": "1.0.0",
+				"repository_name": "repo1",
+				"repository_kind": 0,
+				"change_kind": "created",
+				"ts": 1592299234
+			},
+			{
+				"package_change_id": 2,
+				"package_id": "00000000-0000-0000-0000-000000000001",
+				"package_name": "pkg1",
+				"version": "0.9.0",
+				"repository_name": "repo1",
+				"repository_kind": 0,
+				"change_kind": "deleted",
+				"ts": 1592299235
+			},
+			{
+				"package_change_id": 3,
+				"package_id": "00000000-0000-0000-0000-000000000002",
+				"package_name": "pkg2",
+				"version": "1.0.0",
+				"repository_name": "repo2",
+				"repository_kind": 0,
+				"change_kind": "created",
+				"ts": 1592299235
+			},
+			{
+				"package_change_id": 4,
+				"package_id": "00000000-0000-0000-0000-000000000003",
+				"package_name": "pkg3",
+				"version": "1.0.0",
+				"repository_name": "repo1",
+				"repository_kind": 1,
+				"change_kind": "created",
+				"ts": 1592299235
+			}
+		], "next_cursor": null}`)
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/?kind=0&repo=repo1", nil)
+
+		hw := newHandlersWrapper()
+		hw.h.events.interval = 10 * time.Millisecond
+		hw.h.eventsStreamDuration = 100 * time.Millisecond
+		hw.pm.On("GetChangesJSON", mock.Anything, mock.MatchedBy(func(input *hub.GetPackageChangesInput) bool {
+			return input.Cursor == 0 && input.Limit == defaultChangesLimit
+		})).Return(changesJSON, nil)
+		hw.pm.On("GetChangesJSON", mock.Anything, mock.MatchedBy(func(input *hub.GetPackageChangesInput) bool {
+			return input.Cursor == 4
+		})).Return([]byte(`{"changes": [], "next_cursor": null}`), nil)
+		hw.h.Events(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", h.Get("Content-Type"))
+		assert.Equal(t, "no-cache", h.Get("Cache-Control"))
+		expectedData := `id: 1
+event: new-release
+data: {"package_change_id":1,"package_id":"00000000-0000-0000-0000-000000000001","package_name":"pkg1","version":"1.0.0","repository_id":"","repository_name":"repo1","repository_kind":0,"change_kind":"created","ts":1592299234}
+
+`
+		assert.Equal(t, expectedData, string(data))
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("recent events replayed when resuming the stream", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r.Header.Set("Last-Event-ID", "1")
+
+		hw := newHandlersWrapper()
+		hw.h.events.interval = time.Hour
+		hw.h.events.recent = []*hub.PackageChange{
+			{PackageChangeID: 1, PackageName: "pkg1", ChangeKind: "created"},
+			{PackageChangeID: 2, PackageName: "pkg2", ChangeKind: "created"},
+		}
+		hw.h.eventsStreamDuration = 10 * time.Millisecond
+		hw.h.Events(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, string(data), "id: 2\n")
+		assert.NotContains(t, string(data), "id: 1\n")
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("changes are polled once for all subscribers", func(t *testing.T) {
+		pm := &pkg.ManagerMock{}
+		pm.On("GetChangesJSON", mock.Anything, mock.Anything).Return([]byte(`{"changes": [
+			{"package_change_id": 1, "change_kind": "created"},
+			{"package_change_id": 2, "change_kind": "created"}
+		], "next_cursor": null}`), nil).Once()
+		b := newEventsBroker(pm, time.Hour, zerolog.Nop())
+		b.start.Do(func() {})
+		ch1, _ := b.subscribe(0)
+		ch2, _ := b.subscribe(0)
+		b.poll(context.Background())
+
+		assert.Len(t, <-ch1, 2)
+		assert.Len(t, <-ch2, 2)
+		assert.Equal(t, int64(2), b.cursor)
+		pm.AssertNumberOfCalls(t, "GetChangesJSON", 1)
+	})
+
+	t.Run("replayed events are limited to the most recent ones", func(t *testing.T) {
+		b := newEventsBroker(&pkg.ManagerMock{}, time.Hour, zerolog.Nop())
+		b.start.Do(func() {})
+		changes := make([]*hub.PackageChange, 0, maxEventsReplay+10)
+		for i := 1; i <= maxEventsReplay+10; i++ {
+			changes = append(changes, &hub.PackageChange{PackageChangeID: int64(i)})
+		}
+		b.broadcast(changes)
+		_, replay := b.subscribe(1)
+
+		assert.Len(t, replay, maxEventsReplay)
+		assert.Equal(t, int64(11), replay[0].PackageChangeID)
+	})
+
+	t.Run("subscribers are disconnected when the broker is stopped", func(t *testing.T) {
+		b := newEventsBroker(&pkg.ManagerMock{}, time.Hour, zerolog.Nop())
+		ch1, _ := b.subscribe(0)
+		b.stop()
+		_, ok := <-ch1
+		assert.False(t, ok)
+		assert.Empty(t, b.subscribers)
+		assert.Error(t, b.ctx.Err())
+
+		ch2, _ := b.subscribe(0)
+		_, ok = <-ch2
+		assert.False(t, ok)
+		assert.Empty(t, b.subscribers)
+	})
+
+	t.Run("subscribers not keeping up are disconnected", func(t *testing.T) {
+		b := newEventsBroker(&pkg.ManagerMock{}, time.Hour, zerolog.Nop())
+		b.start.Do(func() {})
+		ch, _ := b.subscribe(0)
+		for i := 0; i <= subscriberBufferSize; i++ {
+			b.broadcast([]*hub.PackageChange{{PackageChangeID: int64(i + 1)}})
+		}
+		for range ch {
+		}
+		assert.Empty(t, b.subscribers)
+	})
+}
+
 func TestGet(t *testing.T) {
 	t.Run("get package failed", func(t *testing.T) {
 		testCases := []struct {
@@ -83,16 +257,146 @@ func TestGet(t *testing.T) {
 		assert.Equal(t, []byte("dataJSON"), data)
 		hw.pm.AssertExpectations(t)
 	})
+
+	t.Run("get package succeeded, eol version", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+
+		hw := newHandlersWrapper()
+		hw.pm.On("GetJSON", r.Context(), mock.Anything).Return([]byte(`{"name": "pkg1", "eol": true}`), nil)
+		hw.h.Get(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, eolWarning, resp.Header.Get("Warning"))
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("get package succeeded, private repository", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.pm.On("GetJSON", r.Context(), mock.Anything).
+			Return([]byte(`{"name": "pkg1", "repository": {"private": true}}`), nil)
+		hw.h.Get(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), resp.Header.Get("Cache-Control"))
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("get package succeeded, fields selection", func(t *testing.T) {
+		dataJSON := []byte(`{"name": "pkg1", "version": "1.0.0", "readme": "readme", "data": {"key": "value"}}`)
+		testCases := []struct {
+			params       string
+			expectedData []byte
+		}{
+			{
+				"fields=name,version",
+				[]byte(`{"name":"pkg1","version":"1.0.0"}`),
+			},
+			{
+				"fields=name&fields=unknown",
+				[]byte(`{"name":"pkg1"}`),
+			},
+			{
+				"omit=readme,data",
+				[]byte(`{"name":"pkg1","version":"1.0.0"}`),
+			},
+			{
+				"fields=name,readme&omit=readme",
+				[]byte(`{"name":"pkg1"}`),
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.params, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("GET", "/?"+tc.params, nil)
+
+				hw := newHandlersWrapper()
+				hw.pm.On("GetJSON", r.Context(), mock.Anything).Return(dataJSON, nil)
+				hw.h.Get(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+				data, _ := ioutil.ReadAll(resp.Body)
+
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, tc.expectedData, data)
+				hw.pm.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("invalid fields selection", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/?fields=name,", nil)
+
+		hw := newHandlersWrapper()
+		hw.h.Get(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		hw.pm.AssertExpectations(t)
+	})
 }
 
-func TestGetRandom(t *testing.T) {
-	t.Run("get random packages succeeded", func(t *testing.T) {
+func TestGetAll(t *testing.T) {
+	t.Run("invalid request params", func(t *testing.T) {
+		testCases := []struct {
+			desc   string
+			params string
+		}{
+			{"invalid limit", "limit=z"},
+			{"invalid kind", "kind=z"},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(fmt.Sprintf("%s: %s", tc.desc, tc.params), func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("GET", "/?"+tc.params, nil)
+
+				hw := newHandlersWrapper()
+				hw.h.GetAll(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			})
+		}
+	})
+
+	t.Run("error getting packages", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		r, _ := http.NewRequest("GET", "/", nil)
 
 		hw := newHandlersWrapper()
-		hw.pm.On("GetRandomJSON", r.Context()).Return([]byte("dataJSON"), nil)
-		hw.h.GetRandom(w, r)
+		hw.pm.On("GetAllJSON", r.Context(), mock.Anything).Return(nil, tests.ErrFakeDatabaseFailure)
+		hw.h.GetAll(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("get packages succeeded", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/?cursor=00000000-0000-0000-0000-000000000001&kind=0&limit=10", nil)
+
+		hw := newHandlersWrapper()
+		hw.pm.On("GetAllJSON", r.Context(), &hub.GetAllPackagesInput{
+			Cursor:          "00000000-0000-0000-0000-000000000001",
+			Limit:           10,
+			RepositoryKinds: []hub.RepositoryKind{hub.Helm},
+		}).Return([]byte("dataJSON"), nil)
+		hw.h.GetAll(w, r)
 		resp := w.Result()
 		defer resp.Body.Close()
 		h := resp.Header
@@ -104,68 +408,1512 @@ func TestGetRandom(t *testing.T) {
 		assert.Equal(t, []byte("dataJSON"), data)
 		hw.pm.AssertExpectations(t)
 	})
+}
 
-	t.Run("error getting random packages", func(t *testing.T) {
+func TestGetBackstageEntities(t *testing.T) {
+	t.Run("get packages failed", func(t *testing.T) {
+		testCases := []struct {
+			pmErr              error
+			expectedStatusCode int
+		}{
+			{
+				hub.ErrInvalidInput,
+				http.StatusBadRequest,
+			},
+			{
+				tests.ErrFakeDatabaseFailure,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.pmErr.Error(), func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("GET", "/?owner=org1", nil)
+
+				hw := newHandlersWrapper()
+				hw.pm.On("GetByOwner", r.Context(), "org1").Return(nil, tc.pmErr)
+				hw.h.GetBackstageEntities(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hw.pm.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("entities exported successfully", func(t *testing.T) {
 		w := httptest.NewRecorder()
-		r, _ := http.NewRequest("GET", "/", nil)
+		r, _ := http.NewRequest("GET", "/?owner=org1", nil)
+
+		repo := &hub.Repository{
+			RepositoryID:     "00000000-0000-0000-0000-000000000001",
+			Kind:             hub.Helm,
+			Name:             "repo1",
+			DisplayName:      "Repo 1",
+			URL:              "https://repo1.url",
+			OrganizationName: "org1",
+		}
+		hw := newHandlersWrapper()
+		hw.pm.On("GetByOwner", r.Context(), "org1").Return([]*hub.Package{
+			{
+				PackageID:      "00000000-0000-0000-0000-000000000001",
+				NormalizedName: "pkg1",
+				DisplayName:    "Package 1",
+				Description:    "description",
+				Keywords:       []string{"kw1", "Key Word", "invalid_kw"},
+				HomeURL:        "https://home.url",
+				Version:        "1.0.0",
+				Repository:     repo,
+			},
+			{
+				PackageID:      "00000000-0000-0000-0000-000000000002",
+				NormalizedName: "pkg2",
+				Version:        "2.0.0",
+				Deprecated:     true,
+				Repository:     repo,
+			},
+		}, nil)
+		hw.h.GetBackstageEntities(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/x-yaml", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(helpers.DefaultAPICacheMaxAge), h.Get("Cache-Control"))
+		assert.Equal(t, `apiVersion: backstage.io/v1alpha1
+kind: Resource
+metadata:
+  name: repo1
+  title: Repo 1
+  annotations:
+    artifacthub.io/repository-id: 00000000-0000-0000-0000-000000000001
+  links:
+  - url: https://repo1.url
+    title: Repository
+spec:
+  type: helm-repository
+  owner: group:org1
+---
+apiVersion: backstage.io/v1alpha1
+kind: Component
+metadata:
+  name: repo1-pkg1
+  title: Package 1
+  description: description
+  annotations:
+    artifacthub.io/package-id: 00000000-0000-0000-0000-000000000001
+    artifacthub.io/version: 1.0.0
+  tags:
+  - kw1
+  - key-word
+  links:
+  - url: baseURL/packages/helm/repo1/pkg1
+    title: Artifact Hub
+  - url: https://home.url
+    title: Home
+spec:
+  type: helm-package
+  lifecycle: production
+  owner: group:org1
+  dependsOn:
+  - resource:repo1
+---
+apiVersion: backstage.io/v1alpha1
+kind: Component
+metadata:
+  name: repo1-pkg2
+  annotations:
+    artifacthub.io/package-id: 00000000-0000-0000-0000-000000000002
+    artifacthub.io/version: 2.0.0
+  links:
+  - url: baseURL/packages/helm/repo1/pkg2
+    title: Artifact Hub
+spec:
+  type: helm-package
+  lifecycle: deprecated
+  owner: group:org1
+  dependsOn:
+  - resource:repo1
+`, string(data))
+		hw.pm.AssertExpectations(t)
+	})
+}
+
+func TestGetChanges(t *testing.T) {
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []string{
+			"",
+			"since=z",
+			"since=1592299234&cursor=z",
+			"since=1592299234&limit=z",
+			"since=1592299234",
+		}
+		for _, params := range testCases {
+			params := params
+			t.Run(params, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("GET", "/?"+params, nil)
+
+				hw := newHandlersWrapper()
+				hw.h.cfg.Set("server.retention.policies.packages_changes", "720h")
+				hw.h.GetChanges(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			})
+		}
+	})
+
+	t.Run("error getting changes", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/?since=1592299234", nil)
 
 		hw := newHandlersWrapper()
-		hw.pm.On("GetRandomJSON", r.Context()).Return(nil, tests.ErrFakeDatabaseFailure)
-		hw.h.GetRandom(w, r)
+		hw.pm.On("GetChangesJSON", r.Context(), &hub.GetPackageChangesInput{
+			Since: 1592299234,
+			Limit: defaultChangesLimit,
+		}).Return(nil, tests.ErrFakeDatabaseFailure)
+		hw.h.GetChanges(w, r)
 		resp := w.Result()
 		defer resp.Body.Close()
 
 		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 		hw.pm.AssertExpectations(t)
 	})
+
+	t.Run("get changes succeeded", func(t *testing.T) {
+		since := time.Now().Add(-1 * time.Hour).Unix()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", fmt.Sprintf("/?since=%d&cursor=10&limit=50", since), nil)
+
+		hw := newHandlersWrapper()
+		hw.h.cfg.Set("server.retention.policies.packages_changes", "720h")
+		hw.pm.On("GetChangesJSON", r.Context(), &hub.GetPackageChangesInput{
+			Since:  since,
+			Cursor: 10,
+			Limit:  50,
+		}).Return([]byte("dataJSON"), nil)
+		hw.h.GetChanges(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), h.Get("Cache-Control"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("all changes available can be requested despite retention", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/?since=0", nil)
+
+		hw := newHandlersWrapper()
+		hw.h.cfg.Set("server.retention.policies.packages_changes", "720h")
+		hw.pm.On("GetChangesJSON", r.Context(), &hub.GetPackageChangesInput{
+			Since: 0,
+			Limit: defaultChangesLimit,
+		}).Return([]byte("dataJSON"), nil)
+		hw.h.GetChanges(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		hw.pm.AssertExpectations(t)
+	})
+}
+
+func TestGetDependenciesGraph(t *testing.T) {
+	rctx := &chi.Context{
+		URLParams: chi.RouteParams{
+			Keys:   []string{"repoName", "packageName", "version"},
+			Values: []string{"repo1", "pkg1", "1.0.0"},
+		},
+	}
+	input := &hub.GetPackageInput{
+		PackageName:    "pkg1",
+		RepositoryName: "repo1",
+		Version:        "1.0.0",
+	}
+
+	t.Run("get dependencies graph failed", func(t *testing.T) {
+		testCases := []struct {
+			pmErr              error
+			expectedStatusCode int
+		}{
+			{
+				hub.ErrInvalidInput,
+				http.StatusBadRequest,
+			},
+			{
+				hub.ErrNotFound,
+				http.StatusNotFound,
+			},
+			{
+				tests.ErrFakeDatabaseFailure,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.pmErr.Error(), func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("GET", "/", nil)
+				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+				hw := newHandlersWrapper()
+				hw.pm.On("GetDependenciesGraphJSON", r.Context(), input).Return(nil, tc.pmErr)
+				hw.h.GetDependenciesGraph(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hw.pm.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("get dependencies graph succeeded", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.pm.On("GetDependenciesGraphJSON", r.Context(), input).Return([]byte("dataJSON"), nil)
+		hw.h.GetDependenciesGraph(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(helpers.DefaultAPICacheMaxAge), h.Get("Cache-Control"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		hw.pm.AssertExpectations(t)
+	})
+}
+
+func TestGetVersions(t *testing.T) {
+	p := &hub.Package{
+		AvailableVersions: []*hub.Version{
+			{
+				Version:   "0.0.9",
+				CreatedAt: 1592299233,
+			},
+			{
+				Version:   "1.0.0",
+				CreatedAt: 1592299234,
+			},
+		},
+	}
+
+	t.Run("error getting package", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+
+		hw := newHandlersWrapper()
+		hw.pm.On("Get", r.Context(), mock.Anything).Return(nil, hub.ErrNotFound)
+		hw.h.GetVersions(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("get versions succeeded", func(t *testing.T) {
+		testCases := []struct {
+			accept              string
+			expectedContentType string
+			expectedData        []byte
+		}{
+			{
+				"",
+				"application/json",
+				[]byte(`[{"version":"1.0.0","created_at":1592299234},{"version":"0.0.9","created_at":1592299233}]`),
+			},
+			{
+				"text/csv",
+				"text/csv; charset=utf-8",
+				[]byte("version,created_at\n1.0.0,1592299234\n0.0.9,1592299233\n"),
+			},
+			{
+				"application/x-ndjson",
+				"application/x-ndjson",
+				[]byte(`{"version":"1.0.0","created_at":1592299234}` + "\n" + `{"version":"0.0.9","created_at":1592299233}` + "\n"),
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.expectedContentType, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("GET", "/", nil)
+				r.Header.Set("Accept", tc.accept)
+
+				hw := newHandlersWrapper()
+				hw.pm.On("Get", r.Context(), mock.Anything).Return(p, nil)
+				hw.h.GetVersions(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+				h := resp.Header
+				data, _ := ioutil.ReadAll(resp.Body)
+
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, tc.expectedContentType, h.Get("Content-Type"))
+				assert.Equal(t, helpers.BuildCacheControlHeader(helpers.DefaultAPICacheMaxAge), h.Get("Cache-Control"))
+				assert.Equal(t, tc.expectedData, data)
+				hw.pm.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("invalid range provided", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/?range=invalid", nil)
+
+		hw := newHandlersWrapper()
+		hw.h.GetVersions(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("get versions in range succeeded", func(t *testing.T) {
+		p := &hub.Package{
+			AvailableVersions: []*hub.Version{
+				{Version: "1.1.0", CreatedAt: 1592299231},
+				{Version: "1.2.0", CreatedAt: 1592299232},
+				{Version: "1.3.0-beta.1", CreatedAt: 1592299233},
+				{Version: "1.3.0", CreatedAt: 1592299234},
+				{Version: "2.0.0", CreatedAt: 1592299235},
+			},
+		}
+		testCases := []struct {
+			versionsRange string
+			expectedData  []byte
+		}{
+			{
+				">=1.2 <2.0",
+				[]byte(`[{"version":"1.3.0","created_at":1592299234},{"version":"1.2.0","created_at":1592299232}]`),
+			},
+			{
+				">=1.3.0-0 <2.0",
+				[]byte(`[{"version":"1.3.0","created_at":1592299234},{"version":"1.3.0-beta.1","created_at":1592299233}]`),
+			},
+			{
+				">3",
+				[]byte(`[]`),
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.versionsRange, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("GET", "/?range="+url.QueryEscape(tc.versionsRange), nil)
+
+				hw := newHandlersWrapper()
+				hw.pm.On("Get", r.Context(), mock.Anything).Return(p, nil)
+				hw.h.GetVersions(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+				data, _ := ioutil.ReadAll(resp.Body)
+
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, tc.expectedData, data)
+				hw.pm.AssertExpectations(t)
+			})
+		}
+	})
+}
+
+func TestGetLatestVersions(t *testing.T) {
+	rctx := &chi.Context{
+		URLParams: chi.RouteParams{
+			Keys:   []string{"repoName", "packageName"},
+			Values: []string{"repo1", "pkg1"},
+		},
+	}
+	input := &hub.GetPackageInput{
+		PackageName:    "pkg1",
+		RepositoryName: "repo1",
+	}
+
+	t.Run("get latest versions failed", func(t *testing.T) {
+		testCases := []struct {
+			pmErr              error
+			expectedStatusCode int
+		}{
+			{
+				hub.ErrInvalidInput,
+				http.StatusBadRequest,
+			},
+			{
+				hub.ErrNotFound,
+				http.StatusNotFound,
+			},
+			{
+				tests.ErrFakeDatabaseFailure,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.pmErr.Error(), func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("GET", "/", nil)
+				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+				hw := newHandlersWrapper()
+				hw.pm.On("GetLatestVersionsJSON", r.Context(), input).Return(nil, tc.pmErr)
+				hw.h.GetLatestVersions(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hw.pm.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("get latest versions succeeded", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.pm.On("GetLatestVersionsJSON", r.Context(), input).Return([]byte("dataJSON"), nil)
+		hw.h.GetLatestVersions(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(latestVersionsCacheMaxAge), h.Get("Cache-Control"))
+		assert.NotEmpty(t, h.Get("ETag"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("get latest versions succeeded, logged in user", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		ctx := context.WithValue(r.Context(), chi.RouteCtxKey, rctx)
+		r = r.WithContext(context.WithValue(ctx, hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.pm.On("GetLatestVersionsJSON", r.Context(), input).Return([]byte("dataJSON"), nil)
+		hw.h.GetLatestVersions(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), resp.Header.Get("Cache-Control"))
+		hw.pm.AssertExpectations(t)
+	})
+}
+
+func TestGetProject(t *testing.T) {
+	rctx := &chi.Context{
+		URLParams: chi.RouteParams{
+			Keys:   []string{"packageID"},
+			Values: []string{"packageID"},
+		},
+	}
+
+	t.Run("get project failed", func(t *testing.T) {
+		testCases := []struct {
+			err            error
+			expectedStatus int
+		}{
+			{
+				hub.ErrInvalidInput,
+				http.StatusBadRequest,
+			},
+			{
+				tests.ErrFakeDatabaseFailure,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.err.Error(), func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("GET", "/", nil)
+				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+				hw := newHandlersWrapper()
+				hw.pm.On("GetProjectJSON", r.Context(), "packageID").Return(nil, tc.err)
+				hw.h.GetProject(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatus, resp.StatusCode)
+				hw.pm.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("get project succeeded", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.pm.On("GetProjectJSON", r.Context(), "packageID").Return([]byte("dataJSON"), nil)
+		hw.h.GetProject(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(helpers.DefaultAPICacheMaxAge), h.Get("Cache-Control"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		hw.pm.AssertExpectations(t)
+	})
+}
+
+func TestGetRandom(t *testing.T) {
+	t.Run("get random packages succeeded", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+
+		hw := newHandlersWrapper()
+		hw.pm.On("GetRandomJSON", r.Context()).Return([]byte("dataJSON"), nil)
+		hw.h.GetRandom(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(helpers.DefaultAPICacheMaxAge), h.Get("Cache-Control"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("error getting random packages", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+
+		hw := newHandlersWrapper()
+		hw.pm.On("GetRandomJSON", r.Context()).Return(nil, tests.ErrFakeDatabaseFailure)
+		hw.h.GetRandom(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.pm.AssertExpectations(t)
+	})
+}
+
+func TestGetInstallInstructions(t *testing.T) {
+	newRequest := func() *http.Request {
+		r, _ := http.NewRequest("GET", "/", nil)
+		rctx := &chi.Context{
+			URLParams: chi.RouteParams{
+				Keys:   []string{"repoName", "packageName", "version"},
+				Values: []string{"repo1", "pkg1", "1.0.0"},
+			},
+		}
+		return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+	}
+	p := &hub.Package{
+		Name:    "pkg1",
+		Version: "1.0.0",
+		Repository: &hub.Repository{
+			Kind: hub.Helm,
+			Name: "repo1",
+			URL:  "https://repo1.url",
+		},
+	}
+
+	t.Run("get package failed", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := newRequest()
+
+		hw := newHandlersWrapper()
+		hw.pm.On("Get", r.Context(), mock.Anything).Return(nil, hub.ErrNotFound)
+		hw.h.GetInstallInstructions(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("no install instructions available", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := newRequest()
+
+		hw := newHandlersWrapper()
+		hw.pm.On("Get", r.Context(), mock.Anything).Return(&hub.Package{
+			Name:       "pkg1",
+			Repository: &hub.Repository{Kind: hub.OPA},
+		}, nil)
+		hw.h.GetInstallInstructions(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("default install instructions", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := newRequest()
+
+		hw := newHandlersWrapper()
+		hw.pm.On("Get", r.Context(), mock.Anything).Return(p, nil)
+		hw.h.GetInstallInstructions(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/markdown; charset=utf-8", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(helpers.DefaultAPICacheMaxAge), h.Get("Cache-Control"))
+		assert.Contains(t, string(data), "helm repo add repo1 https://repo1.url")
+		assert.Contains(t, string(data), "helm install my-pkg1 repo1/pkg1 --version 1.0.0")
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("custom install instructions", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "artifact-hub-test")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		tmpl := "helm repo add {{ .Package.Repository.Name }} {{ .Vars.mirror }}\n{{ template \"default\" . }}"
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "helm.tmpl"), []byte(tmpl), 0644))
+		cfg := viper.New()
+		cfg.Set("server.installTemplates.path", dir)
+		cfg.Set("server.installTemplates.vars", map[string]string{"mirror": "https://mirror.internal"})
+		pm := &pkg.ManagerMock{}
+		h, err := NewHandlers(pm, cfg)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		r := newRequest()
+		pm.On("Get", r.Context(), mock.Anything).Return(p, nil)
+		h.GetInstallInstructions(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.True(t, strings.HasPrefix(string(data), "helm repo add repo1 https://mirror.internal\n"))
+		assert.Contains(t, string(data), "helm install my-pkg1 repo1/pkg1 --version 1.0.0")
+		pm.AssertExpectations(t)
+	})
+
+	t.Run("invalid custom install template", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "artifact-hub-test")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "olm.tmpl"), []byte("{{ .Package.Name"), 0644))
+		cfg := viper.New()
+		cfg.Set("server.installTemplates.path", dir)
+
+		_, err = NewHandlers(&pkg.ManagerMock{}, cfg)
+		assert.Error(t, err)
+	})
+}
+
+func TestGetSnippet(t *testing.T) {
+	newRequest := func(tool, qs string) *http.Request {
+		r, _ := http.NewRequest("GET", "/?"+qs, nil)
+		rctx := &chi.Context{
+			URLParams: chi.RouteParams{
+				Keys:   []string{"repoName", "packageName", "version", "tool"},
+				Values: []string{"repo1", "pkg1", "1.0.0", tool},
+			},
+		}
+		return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+	}
+	p := &hub.Package{
+		Name:    "pkg1",
+		Version: "1.0.0",
+		Data: map[string]interface{}{
+			"values_presets": []interface{}{
+				map[string]interface{}{
+					"name":   "ha",
+					"values": "replicaCount: 3\n",
+				},
+			},
+		},
+		Repository: &hub.Repository{
+			Kind: hub.Helm,
+			Name: "repo1",
+			URL:  "https://repo1.url",
+		},
+	}
+
+	t.Run("get package failed", func(t *testing.T) {
+		testCases := []struct {
+			pmErr              error
+			expectedStatusCode int
+		}{
+			{
+				hub.ErrNotFound,
+				http.StatusNotFound,
+			},
+			{
+				tests.ErrFakeDatabaseFailure,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.pmErr.Error(), func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := newRequest("flux", "")
+
+				hw := newHandlersWrapper()
+				hw.pm.On("Get", r.Context(), mock.Anything).Return(nil, tc.pmErr)
+				hw.h.GetSnippet(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hw.pm.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		falcoPkg := &hub.Package{
+			Name:       "pkg1",
+			Repository: &hub.Repository{Kind: hub.Falco},
+		}
+		testCases := []struct {
+			desc string
+			tool string
+			qs   string
+			p    *hub.Package
+		}{
+			{"invalid tool", "invalid", "", p},
+			{"preset not found", "flux", "preset=minimal", p},
+			{"not a helm chart", "flux", "", falcoPkg},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.desc, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := newRequest(tc.tool, tc.qs)
+
+				hw := newHandlersWrapper()
+				hw.pm.On("Get", r.Context(), mock.Anything).Return(tc.p, nil)
+				hw.h.GetSnippet(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				hw.pm.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("flux snippet built successfully", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := newRequest("flux", "preset=ha&namespace=ns1")
+
+		hw := newHandlersWrapper()
+		hw.pm.On("Get", r.Context(), &hub.GetPackageInput{
+			RepositoryName: "repo1",
+			PackageName:    "pkg1",
+			Version:        "1.0.0",
+		}).Return(p, nil)
+		hw.h.GetSnippet(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/x-yaml", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(helpers.DefaultAPICacheMaxAge), h.Get("Cache-Control"))
+		assert.Equal(t, `apiVersion: source.toolkit.fluxcd.io/v1beta1
+kind: HelmRepository
+metadata:
+  name: repo1
+  namespace: flux-system
+spec:
+  interval: 10m
+  url: https://repo1.url
+---
+apiVersion: helm.toolkit.fluxcd.io/v2beta1
+kind: HelmRelease
+metadata:
+  name: pkg1
+  namespace: flux-system
+spec:
+  interval: 10m
+  targetNamespace: ns1
+  chart:
+    spec:
+      chart: pkg1
+      version: 1.0.0
+      sourceRef:
+        kind: HelmRepository
+        name: repo1
+        namespace: flux-system
+  values:
+    replicaCount: 3
+`, string(data))
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("argocd snippet built successfully", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := newRequest("argocd", "")
+
+		hw := newHandlersWrapper()
+		hw.pm.On("Get", r.Context(), mock.Anything).Return(p, nil)
+		hw.h.GetSnippet(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, `apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: pkg1
+  namespace: argocd
+spec:
+  project: default
+  source:
+    repoURL: https://repo1.url
+    chart: pkg1
+    targetRevision: 1.0.0
+  destination:
+    server: https://kubernetes.default.svc
+    namespace: default
+`, string(data))
+		hw.pm.AssertExpectations(t)
+	})
+}
+
+func TestGetStarredByUser(t *testing.T) {
+	t.Run("get packages starred by user succeeded", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.pm.On("GetStarredByUserJSON", r.Context()).Return([]byte("dataJSON"), nil)
+		hw.h.GetStarredByUser(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), h.Get("Cache-Control"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("error getting packages starred by user", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.pm.On("GetStarredByUserJSON", r.Context()).Return(nil, tests.ErrFakeDatabaseFailure)
+		hw.h.GetStarredByUser(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.pm.AssertExpectations(t)
+	})
+}
+
+func TestGetStars(t *testing.T) {
+	rctx := &chi.Context{
+		URLParams: chi.RouteParams{
+			Keys:   []string{"packageID"},
+			Values: []string{"packageID"},
+		},
+	}
+
+	t.Run("get stars failed", func(t *testing.T) {
+		testCases := []struct {
+			err            error
+			expectedStatus int
+		}{
+			{
+				hub.ErrInvalidInput,
+				http.StatusBadRequest,
+			},
+			{
+				tests.ErrFakeDatabaseFailure,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.err.Error(), func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("GET", "/", nil)
+				r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+				hw := newHandlersWrapper()
+				hw.pm.On("GetStarsJSON", r.Context(), "packageID").Return(nil, tc.err)
+				hw.h.GetStars(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatus, resp.StatusCode)
+				hw.pm.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("get stars succeeded", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.pm.On("GetStarsJSON", r.Context(), "packageID").Return([]byte("dataJSON"), nil)
+		hw.h.GetStars(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), h.Get("Cache-Control"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		hw.pm.AssertExpectations(t)
+	})
+}
+
+func TestGetStats(t *testing.T) {
+	t.Run("get stats succeeded", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+
+		hw := newHandlersWrapper()
+		hw.pm.On("GetStatsJSON", r.Context()).Return([]byte("dataJSON"), nil)
+		hw.h.GetStats(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(helpers.DefaultAPICacheMaxAge), h.Get("Cache-Control"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("error getting stats", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+
+		hw := newHandlersWrapper()
+		hw.pm.On("GetStatsJSON", r.Context()).Return(nil, tests.ErrFakeDatabaseFailure)
+		hw.h.GetStats(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.pm.AssertExpectations(t)
+	})
+}
+
+func TestGetTags(t *testing.T) {
+	t.Run("invalid limit", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/?limit=invalid", nil)
+
+		hw := newHandlersWrapper()
+		hw.h.GetTags(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("get tags succeeded", func(t *testing.T) {
+		testCases := []struct {
+			qs            string
+			expectedLimit int
+		}{
+			{"", defaultTagsLimit},
+			{"limit=10", 10},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.qs, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("GET", "/?"+tc.qs, nil)
+
+				hw := newHandlersWrapper()
+				hw.pm.On("GetTagsJSON", r.Context(), tc.expectedLimit).Return([]byte("dataJSON"), nil)
+				hw.h.GetTags(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+				h := resp.Header
+				data, _ := ioutil.ReadAll(resp.Body)
+
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, "application/json", h.Get("Content-Type"))
+				assert.Equal(t, helpers.BuildCacheControlHeader(helpers.DefaultAPICacheMaxAge), h.Get("Cache-Control"))
+				assert.Equal(t, []byte("dataJSON"), data)
+				hw.pm.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("error getting tags", func(t *testing.T) {
+		testCases := []struct {
+			pmErr              error
+			expectedStatusCode int
+		}{
+			{
+				hub.ErrInvalidInput,
+				http.StatusBadRequest,
+			},
+			{
+				tests.ErrFakeDatabaseFailure,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.pmErr.Error(), func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("GET", "/", nil)
+
+				hw := newHandlersWrapper()
+				hw.pm.On("GetTagsJSON", r.Context(), defaultTagsLimit).Return(nil, tc.pmErr)
+				hw.h.GetTags(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hw.pm.AssertExpectations(t)
+			})
+		}
+	})
+}
+
+func TestGetValues(t *testing.T) {
+	rctx := &chi.Context{
+		URLParams: chi.RouteParams{
+			Keys:   []string{"repoName", "packageName", "version"},
+			Values: []string{"repo1", "pkg1", "1.0.0"},
+		},
+	}
+
+	t.Run("get package failed", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.pm.On("Get", r.Context(), mock.Anything).Return(nil, tests.ErrFakeDatabaseFailure)
+		hw.h.GetValues(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("package has no default values", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.pm.On("Get", r.Context(), mock.Anything).Return(&hub.Package{}, nil)
+		hw.h.GetValues(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("default values returned", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+		p := &hub.Package{
+			Data: map[string]interface{}{
+				"default_values": "# Number of replicas\nreplicaCount: 1\n",
+			},
+		}
+
+		hw := newHandlersWrapper()
+		hw.pm.On("Get", r.Context(), &hub.GetPackageInput{
+			PackageName:    "pkg1",
+			Version:        "1.0.0",
+			RepositoryName: "repo1",
+		}).Return(p, nil)
+		hw.h.GetValues(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/x-yaml", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(helpers.DefaultAPICacheMaxAge), h.Get("Cache-Control"))
+		assert.Equal(t, "# Number of replicas\nreplicaCount: 1\n", string(data))
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("default values returned, private repository", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+		p := &hub.Package{
+			Data: map[string]interface{}{
+				"default_values": "replicaCount: 1\n",
+			},
+			Repository: &hub.Repository{Private: true},
+		}
+
+		hw := newHandlersWrapper()
+		hw.pm.On("Get", r.Context(), mock.Anything).Return(p, nil)
+		hw.h.GetValues(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), resp.Header.Get("Cache-Control"))
+		hw.pm.AssertExpectations(t)
+	})
+}
+
+func TestGetValuesSchema(t *testing.T) {
+	rctx := &chi.Context{
+		URLParams: chi.RouteParams{
+			Keys:   []string{"repoName", "packageName", "version"},
+			Values: []string{"repo1", "pkg1", "1.0.0"},
+		},
+	}
+
+	t.Run("get package failed", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.pm.On("Get", r.Context(), mock.Anything).Return(nil, hub.ErrNotFound)
+		hw.h.GetValuesSchema(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("package has no values schema", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.pm.On("Get", r.Context(), mock.Anything).Return(&hub.Package{}, nil)
+		hw.h.GetValuesSchema(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("values schema returned", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+		p := &hub.Package{
+			Data: map[string]interface{}{
+				"values_schema": map[string]interface{}{"type": "object"},
+			},
+		}
+
+		hw := newHandlersWrapper()
+		hw.pm.On("Get", r.Context(), mock.Anything).Return(p, nil)
+		hw.h.GetValuesSchema(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(helpers.DefaultAPICacheMaxAge), h.Get("Cache-Control"))
+		assert.Equal(t, `{"type":"object"}`, string(data))
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("values schema returned, private repository", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+		p := &hub.Package{
+			Data: map[string]interface{}{
+				"values_schema": map[string]interface{}{"type": "object"},
+			},
+			Repository: &hub.Repository{Private: true},
+		}
+
+		hw := newHandlersWrapper()
+		hw.pm.On("Get", r.Context(), mock.Anything).Return(p, nil)
+		hw.h.GetValuesSchema(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), resp.Header.Get("Cache-Control"))
+		hw.pm.AssertExpectations(t)
+	})
+}
+
+func TestGetVersionDocs(t *testing.T) {
+	rctx := &chi.Context{
+		URLParams: chi.RouteParams{
+			Keys:   []string{"repoName", "packageName", "version"},
+			Values: []string{"repo1", "pkg1", "1.0.0"},
+		},
+	}
+	input := &hub.GetPackageInput{
+		PackageName:    "pkg1",
+		Version:        "1.0.0",
+		RepositoryName: "repo1",
+	}
+
+	t.Run("get version docs failed", func(t *testing.T) {
+		testCases := []struct {
+			pmErr              error
+			expectedStatusCode int
+		}{
+			{
+				hub.ErrInvalidInput,
+				http.StatusBadRequest,
+			},
+			{
+				hub.ErrNotFound,
+				http.StatusNotFound,
+			},
+			{
+				tests.ErrFakeDatabaseFailure,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.pmErr.Error(), func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("GET", "/", nil)
+				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+				hw := newHandlersWrapper()
+				hw.pm.On("GetVersionDocsJSON", r.Context(), input).Return(nil, tc.pmErr)
+				hw.h.GetVersionDocs(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hw.pm.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("get version docs succeeded", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.pm.On("GetVersionDocsJSON", r.Context(), input).Return([]byte("dataJSON"), nil)
+		hw.h.GetVersionDocs(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(helpers.DefaultAPICacheMaxAge), h.Get("Cache-Control"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("get version docs succeeded, logged in user", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		ctx := context.WithValue(r.Context(), chi.RouteCtxKey, rctx)
+		r = r.WithContext(context.WithValue(ctx, hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.pm.On("GetVersionDocsJSON", r.Context(), input).Return([]byte("dataJSON"), nil)
+		hw.h.GetVersionDocs(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), resp.Header.Get("Cache-Control"))
+		hw.pm.AssertExpectations(t)
+	})
+}
+
+func TestInjectIndexMeta(t *testing.T) {
+	t.Run("get package failed", func(t *testing.T) {
+		testCases := []struct {
+			pmErr              error
+			expectedStatusCode int
+		}{
+			{
+				hub.ErrInvalidInput,
+				http.StatusBadRequest,
+			},
+			{
+				hub.ErrNotFound,
+				http.StatusNotFound,
+			},
+			{
+				tests.ErrFakeDatabaseFailure,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.pmErr.Error(), func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("GET", "/", nil)
+
+				hw := newHandlersWrapper()
+				hw.pm.On("Get", r.Context(), mock.Anything).Return(nil, tc.pmErr)
+				hw.h.InjectIndexMeta(http.HandlerFunc(testsOK)).ServeHTTP(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hw.pm.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("get package succeeded", func(t *testing.T) {
+		checkIndexMeta := func(expectedTitle, expectedDescription interface{}) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, expectedTitle, r.Context().Value(hub.IndexMetaTitleKey).(string))
+				assert.Equal(t, expectedDescription, r.Context().Value(hub.IndexMetaDescriptionKey).(string))
+			}
+		}
+		testCases := []struct {
+			p                   *hub.Package
+			expectedTitle       string
+			expectedDescription string
+			expectedStatusCode  int
+		}{
+			{
+				&hub.Package{
+					NormalizedName: "pkg1",
+					Version:        "1.0.0",
+					Description:    "description",
+					Repository: &hub.Repository{
+						Name:             "repo1",
+						OrganizationName: "org1",
+					},
+				},
+				"pkg1 1.0.0 · org1/repo1",
+				"description",
+				http.StatusOK,
+			},
+			{
+				&hub.Package{
+					NormalizedName: "pkg1",
+					Version:        "1.0.0",
+					Repository: &hub.Repository{
+						Name:      "repo1",
+						UserAlias: "user1",
+					},
+				},
+				"pkg1 1.0.0 · user1/repo1",
+				"",
+				http.StatusOK,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.expectedDescription, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("GET", "/", nil)
+
+				hw := newHandlersWrapper()
+				hw.pm.On("Get", r.Context(), mock.Anything).Return(tc.p, nil)
+				hw.h.InjectIndexMeta(checkIndexMeta(tc.expectedTitle, tc.expectedDescription)).ServeHTTP(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hw.pm.AssertExpectations(t)
+			})
+		}
+	})
 }
 
-func TestGetStarredByUser(t *testing.T) {
-	t.Run("get packages starred by user succeeded", func(t *testing.T) {
+func TestMatchInstalled(t *testing.T) {
+	body := `[{"name": "pkg1", "version": "1.0.0", "repository_url": "https://repo1.com"}]`
+	releases := []*hub.InstalledRelease{
+		{Name: "pkg1", Version: "1.0.0", RepositoryURL: "https://repo1.com"},
+	}
+
+	t.Run("invalid releases provided", func(t *testing.T) {
 		w := httptest.NewRecorder()
-		r, _ := http.NewRequest("GET", "/", nil)
-		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+		r, _ := http.NewRequest("POST", "/", strings.NewReader("{invalid"))
 
 		hw := newHandlersWrapper()
-		hw.pm.On("GetStarredByUserJSON", r.Context()).Return([]byte("dataJSON"), nil)
-		hw.h.GetStarredByUser(w, r)
+		hw.h.MatchInstalled(w, r)
 		resp := w.Result()
 		defer resp.Body.Close()
-		h := resp.Header
-		data, _ := ioutil.ReadAll(resp.Body)
 
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
-		assert.Equal(t, "application/json", h.Get("Content-Type"))
-		assert.Equal(t, helpers.BuildCacheControlHeader(0), h.Get("Cache-Control"))
-		assert.Equal(t, []byte("dataJSON"), data)
-		hw.pm.AssertExpectations(t)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	})
 
-	t.Run("error getting packages starred by user", func(t *testing.T) {
+	t.Run("releases too large", func(t *testing.T) {
 		w := httptest.NewRecorder()
-		r, _ := http.NewRequest("GET", "/", nil)
-		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+		r, _ := http.NewRequest("POST", "/", strings.NewReader(strings.Repeat("a", maxInstalledPayloadSize+1)))
 
 		hw := newHandlersWrapper()
-		hw.pm.On("GetStarredByUserJSON", r.Context()).Return(nil, tests.ErrFakeDatabaseFailure)
-		hw.h.GetStarredByUser(w, r)
+		hw.h.MatchInstalled(w, r)
 		resp := w.Result()
 		defer resp.Body.Close()
 
-		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
-		hw.pm.AssertExpectations(t)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
 	})
-}
-
-func TestGetStars(t *testing.T) {
-	rctx := &chi.Context{
-		URLParams: chi.RouteParams{
-			Keys:   []string{"packageID"},
-			Values: []string{"packageID"},
-		},
-	}
 
-	t.Run("get stars failed", func(t *testing.T) {
+	t.Run("error matching releases", func(t *testing.T) {
 		testCases := []struct {
 			err            error
 			expectedStatus int
@@ -183,13 +1931,11 @@ func TestGetStars(t *testing.T) {
 			tc := tc
 			t.Run(tc.err.Error(), func(t *testing.T) {
 				w := httptest.NewRecorder()
-				r, _ := http.NewRequest("GET", "/", nil)
-				r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
-				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+				r, _ := http.NewRequest("POST", "/", strings.NewReader(body))
 
 				hw := newHandlersWrapper()
-				hw.pm.On("GetStarsJSON", r.Context(), "packageID").Return(nil, tc.err)
-				hw.h.GetStars(w, r)
+				hw.pm.On("MatchInstalledJSON", r.Context(), releases).Return(nil, tc.err)
+				hw.h.MatchInstalled(w, r)
 				resp := w.Result()
 				defer resp.Body.Close()
 
@@ -199,15 +1945,13 @@ func TestGetStars(t *testing.T) {
 		}
 	})
 
-	t.Run("get stars succeeded", func(t *testing.T) {
+	t.Run("releases matched", func(t *testing.T) {
 		w := httptest.NewRecorder()
-		r, _ := http.NewRequest("GET", "/", nil)
-		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
-		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+		r, _ := http.NewRequest("POST", "/", strings.NewReader(body))
 
 		hw := newHandlersWrapper()
-		hw.pm.On("GetStarsJSON", r.Context(), "packageID").Return([]byte("dataJSON"), nil)
-		hw.h.GetStars(w, r)
+		hw.pm.On("MatchInstalledJSON", r.Context(), releases).Return([]byte("dataJSON"), nil)
+		hw.h.MatchInstalled(w, r)
 		resp := w.Result()
 		defer resp.Body.Close()
 		h := resp.Header
@@ -215,60 +1959,63 @@ func TestGetStars(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, resp.StatusCode)
 		assert.Equal(t, "application/json", h.Get("Content-Type"))
-		assert.Equal(t, helpers.BuildCacheControlHeader(0), h.Get("Cache-Control"))
 		assert.Equal(t, []byte("dataJSON"), data)
 		hw.pm.AssertExpectations(t)
 	})
 }
 
-func TestGetStats(t *testing.T) {
-	t.Run("get stats succeeded", func(t *testing.T) {
+func TestPush(t *testing.T) {
+	mdYAML := `
+version: 1.0.0
+name: package1
+displayName: Package 1
+createdAt: "2020-06-16T11:20:34Z"
+description: description
+`
+	md := &hub.PackageMetadata{
+		Version:     "1.0.0",
+		Name:        "package1",
+		DisplayName: "Package 1",
+		CreatedAt:   "2020-06-16T11:20:34Z",
+		Description: "description",
+	}
+
+	t.Run("invalid metadata provided", func(t *testing.T) {
 		w := httptest.NewRecorder()
-		r, _ := http.NewRequest("GET", "/", nil)
+		r, _ := http.NewRequest("POST", "/", strings.NewReader("{invalid"))
 
 		hw := newHandlersWrapper()
-		hw.pm.On("GetStatsJSON", r.Context()).Return([]byte("dataJSON"), nil)
-		hw.h.GetStats(w, r)
+		hw.h.Push(w, r)
 		resp := w.Result()
 		defer resp.Body.Close()
-		h := resp.Header
-		data, _ := ioutil.ReadAll(resp.Body)
 
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
-		assert.Equal(t, "application/json", h.Get("Content-Type"))
-		assert.Equal(t, helpers.BuildCacheControlHeader(helpers.DefaultAPICacheMaxAge), h.Get("Cache-Control"))
-		assert.Equal(t, []byte("dataJSON"), data)
-		hw.pm.AssertExpectations(t)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	})
 
-	t.Run("error getting stats", func(t *testing.T) {
+	t.Run("metadata too large", func(t *testing.T) {
 		w := httptest.NewRecorder()
-		r, _ := http.NewRequest("GET", "/", nil)
+		r, _ := http.NewRequest("POST", "/", strings.NewReader(strings.Repeat("a", maxPushPayloadSize+1)))
 
 		hw := newHandlersWrapper()
-		hw.pm.On("GetStatsJSON", r.Context()).Return(nil, tests.ErrFakeDatabaseFailure)
-		hw.h.GetStats(w, r)
+		hw.h.Push(w, r)
 		resp := w.Result()
 		defer resp.Body.Close()
 
-		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
-		hw.pm.AssertExpectations(t)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
 	})
-}
 
-func TestInjectIndexMeta(t *testing.T) {
-	t.Run("get package failed", func(t *testing.T) {
+	t.Run("push failed", func(t *testing.T) {
 		testCases := []struct {
-			pmErr              error
-			expectedStatusCode int
+			err            error
+			expectedStatus int
 		}{
 			{
 				hub.ErrInvalidInput,
 				http.StatusBadRequest,
 			},
 			{
-				hub.ErrNotFound,
-				http.StatusNotFound,
+				hub.ErrInsufficientPrivilege,
+				http.StatusForbidden,
 			},
 			{
 				tests.ErrFakeDatabaseFailure,
@@ -277,76 +2024,55 @@ func TestInjectIndexMeta(t *testing.T) {
 		}
 		for _, tc := range testCases {
 			tc := tc
-			t.Run(tc.pmErr.Error(), func(t *testing.T) {
+			t.Run(tc.err.Error(), func(t *testing.T) {
 				w := httptest.NewRecorder()
-				r, _ := http.NewRequest("GET", "/", nil)
+				r, _ := http.NewRequest("POST", "/", strings.NewReader(mdYAML))
 
 				hw := newHandlersWrapper()
-				hw.pm.On("Get", r.Context(), mock.Anything).Return(nil, tc.pmErr)
-				hw.h.InjectIndexMeta(http.HandlerFunc(testsOK)).ServeHTTP(w, r)
+				hw.pm.On("Push", r.Context(), md).Return(tc.err)
+				hw.h.Push(w, r)
 				resp := w.Result()
 				defer resp.Body.Close()
 
-				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				assert.Equal(t, tc.expectedStatus, resp.StatusCode)
 				hw.pm.AssertExpectations(t)
 			})
 		}
 	})
 
-	t.Run("get package succeeded", func(t *testing.T) {
-		checkIndexMeta := func(expectedTitle, expectedDescription interface{}) http.HandlerFunc {
-			return func(w http.ResponseWriter, r *http.Request) {
-				assert.Equal(t, expectedTitle, r.Context().Value(hub.IndexMetaTitleKey).(string))
-				assert.Equal(t, expectedDescription, r.Context().Value(hub.IndexMetaDescriptionKey).(string))
-			}
-		}
+	t.Run("push succeeded", func(t *testing.T) {
 		testCases := []struct {
-			p                   *hub.Package
-			expectedTitle       string
-			expectedDescription string
-			expectedStatusCode  int
+			format string
+			body   string
 		}{
 			{
-				&hub.Package{
-					NormalizedName: "pkg1",
-					Version:        "1.0.0",
-					Description:    "description",
-					Repository: &hub.Repository{
-						Name:             "repo1",
-						OrganizationName: "org1",
-					},
-				},
-				"pkg1 1.0.0 · org1/repo1",
-				"description",
-				http.StatusOK,
+				"yaml",
+				mdYAML,
 			},
 			{
-				&hub.Package{
-					NormalizedName: "pkg1",
-					Version:        "1.0.0",
-					Repository: &hub.Repository{
-						Name:      "repo1",
-						UserAlias: "user1",
-					},
-				},
-				"pkg1 1.0.0 · user1/repo1",
-				"",
-				http.StatusOK,
+				"json",
+				`{
+					"version": "1.0.0",
+					"name": "package1",
+					"displayName": "Package 1",
+					"createdAt": "2020-06-16T11:20:34Z",
+					"description": "description"
+				}`,
 			},
 		}
 		for _, tc := range testCases {
 			tc := tc
-			t.Run(tc.expectedDescription, func(t *testing.T) {
+			t.Run(tc.format, func(t *testing.T) {
 				w := httptest.NewRecorder()
-				r, _ := http.NewRequest("GET", "/", nil)
+				r, _ := http.NewRequest("POST", "/", strings.NewReader(tc.body))
 
 				hw := newHandlersWrapper()
-				hw.pm.On("Get", r.Context(), mock.Anything).Return(tc.p, nil)
-				hw.h.InjectIndexMeta(checkIndexMeta(tc.expectedTitle, tc.expectedDescription)).ServeHTTP(w, r)
+				hw.pm.On("Push", r.Context(), md).Return(nil)
+				hw.h.Push(w, r)
 				resp := w.Result()
 				defer resp.Body.Close()
 
-				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				assert.Equal(t, http.StatusCreated, resp.StatusCode)
 				hw.pm.AssertExpectations(t)
 			})
 		}
@@ -477,6 +2203,142 @@ func TestRssFeed(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("rss feed built successfully, private repository", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		p := &hub.Package{
+			PackageID:      "0001",
+			NormalizedName: "pkg1",
+			Version:        "1.0.0",
+			Repository: &hub.Repository{
+				Name:      "repo1",
+				UserAlias: "user1",
+				Private:   true,
+			},
+		}
+
+		hw := newHandlersWrapper()
+		hw.pm.On("Get", r.Context(), mock.Anything).Return(p, nil)
+		hw.h.RssFeed(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), resp.Header.Get("Cache-Control"))
+		hw.pm.AssertExpectations(t)
+	})
+}
+
+func TestResolve(t *testing.T) {
+	t.Run("invalid kind", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/?kind=z&source_url=https://github.com/org1/pkg1", nil)
+
+		hw := newHandlersWrapper()
+		hw.h.Resolve(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("resolve failed", func(t *testing.T) {
+		testCases := []struct {
+			pmErr              error
+			expectedStatusCode int
+		}{
+			{
+				hub.ErrInvalidInput,
+				http.StatusBadRequest,
+			},
+			{
+				tests.ErrFakeDatabaseFailure,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.pmErr.Error(), func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("GET", "/?source_url=https://github.com/org1/pkg1", nil)
+
+				hw := newHandlersWrapper()
+				hw.pm.On("Resolve", r.Context(), mock.Anything).Return(nil, tc.pmErr)
+				hw.h.Resolve(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hw.pm.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("packages resolved successfully", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/?kind=helm&repo_url=https://repo1.url&name=pkg1", nil)
+
+		kind := hub.Helm
+		hw := newHandlersWrapper()
+		hw.pm.On("Resolve", r.Context(), &hub.ResolvePackageInput{
+			RepositoryKind: &kind,
+			RepositoryURL:  "https://repo1.url",
+			PackageName:    "pkg1",
+		}).Return([]*hub.Package{
+			{
+				PackageID:      "00000000-0000-0000-0000-000000000001",
+				Name:           "pkg1",
+				NormalizedName: "pkg1",
+				Version:        "1.0.0",
+				Repository: &hub.Repository{
+					Kind:             hub.Helm,
+					Name:             "repo1",
+					URL:              "https://repo1.url",
+					OrganizationName: "org1",
+				},
+			},
+		}, nil)
+		hw.h.Resolve(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(helpers.DefaultAPICacheMaxAge), h.Get("Cache-Control"))
+		assert.JSONEq(t, `[{
+			"package_id": "00000000-0000-0000-0000-000000000001",
+			"name": "pkg1",
+			"normalized_name": "pkg1",
+			"version": "1.0.0",
+			"url": "baseURL/packages/helm/repo1/pkg1",
+			"repository": {
+				"kind": 0,
+				"name": "repo1",
+				"url": "https://repo1.url",
+				"organization_name": "org1"
+			}
+		}]`, string(data))
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("no packages resolved", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/?source_url=https://github.com/org1/pkg1", nil)
+
+		hw := newHandlersWrapper()
+		hw.pm.On("Resolve", r.Context(), mock.Anything).Return(nil, nil)
+		hw.h.Resolve(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "[]", string(data))
+		hw.pm.AssertExpectations(t)
+	})
 }
 
 func TestSearch(t *testing.T) {
@@ -492,6 +2354,7 @@ func TestSearch(t *testing.T) {
 			{"invalid kind (one of them)", "kind=0&kind=z"},
 			{"invalid operators", "operators=z"},
 			{"invalid deprecated", "deprecated=z"},
+			{"invalid supported_only", "supported_only=z"},
 		}
 		for _, tc := range testCases {
 			tc := tc
@@ -541,6 +2404,117 @@ func TestSearch(t *testing.T) {
 		hw.pm.AssertExpectations(t)
 	})
 
+	t.Run("valid request with scope, search succeeded", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/?scope_user=user1&scope_org=org1&scope_repo=repo1", nil)
+
+		hw := newHandlersWrapper()
+		hw.pm.On("SearchJSON", r.Context(), mock.MatchedBy(func(input *hub.SearchPackageInput) bool {
+			return input.ScopeUser == "user1" && input.ScopeOrg == "org1" && input.ScopeRepository == "repo1"
+		})).Return([]byte("dataJSON"), nil)
+		hw.h.Search(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("valid request with tags, search succeeded", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/?tag=monitoring&tag=database", nil)
+
+		hw := newHandlersWrapper()
+		hw.pm.On("SearchJSON", r.Context(), mock.MatchedBy(func(input *hub.SearchPackageInput) bool {
+			return assert.ObjectsAreEqual([]string{"monitoring", "database"}, input.Tags)
+		})).Return([]byte("dataJSON"), nil)
+		hw.h.Search(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		hw.pm.AssertExpectations(t)
+	})
+
+	t.Run("valid request, search succeeded, alternative formats", func(t *testing.T) {
+		dataJSON := []byte(`{
+			"data": {
+				"packages": [{
+					"package_id": "00000000-0000-0000-0000-000000000001",
+					"name": "package1",
+					"normalized_name": "package1",
+					"stars": 10,
+					"display_name": "Package 1",
+					"description": "description",
+					"version": "1.0.0",
+					"app_version": "12.1.0",
+					"deprecated": null,
+					"signed": true,
+					"created_at": 1592299234,
+					"repository": {
+						"kind": 0,
+						"name": "repo1",
+						"user_alias": "user1",
+						"organization_name": null
+					}
+				}]
+			},
+			"metadata": {
+				"limit": 10,
+				"offset": 0,
+				"total": 1
+			}
+		}`)
+		testCases := []struct {
+			accept              string
+			expectedContentType string
+			expectedData        []byte
+		}{
+			{
+				"text/csv",
+				"text/csv; charset=utf-8",
+				[]byte(strings.Join([]string{
+					"package_id,name,normalized_name,display_name,version,app_version,description,stars,deprecated,signed,kind,repository_name,publisher,created_at",
+					"00000000-0000-0000-0000-000000000001,package1,package1,Package 1,1.0.0,12.1.0,description,10,false,true,helm,repo1,user1,1592299234",
+					"",
+				}, "\n")),
+			},
+			{
+				"application/x-ndjson",
+				"application/x-ndjson",
+				nil,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.expectedContentType, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("GET", "/", nil)
+				r.Header.Set("Accept", tc.accept)
+
+				hw := newHandlersWrapper()
+				hw.pm.On("SearchJSON", r.Context(), mock.Anything).Return(dataJSON, nil)
+				hw.h.Search(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+				h := resp.Header
+				data, _ := ioutil.ReadAll(resp.Body)
+
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, tc.expectedContentType, h.Get("Content-Type"))
+				assert.Equal(t, "1", h.Get("X-Total-Count"))
+				if tc.expectedData != nil {
+					assert.Equal(t, tc.expectedData, data)
+				} else {
+					lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+					assert.Len(t, lines, 1)
+					assert.Contains(t, lines[0], `"name":"package1"`)
+				}
+				hw.pm.AssertExpectations(t)
+			})
+		}
+	})
+
 	t.Run("error searching packages", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		r, _ := http.NewRequest("GET", "/", nil)
@@ -626,10 +2600,11 @@ func newHandlersWrapper() *handlersWrapper {
 	cfg := viper.New()
 	cfg.Set("server.baseURL", "baseURL")
 	pm := &pkg.ManagerMock{}
+	h, _ := NewHandlers(pm, cfg)
 
 	return &handlersWrapper{
 		pm: pm,
-		h:  NewHandlers(pm, cfg),
+		h:  h,
 	}
 }
 
@@ -0,0 +1,125 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/spf13/viper"
+)
+
+// defaultInstallTemplateName represents the name of the built-in install
+// instructions template of each kind. Custom templates can include it to
+// extend it instead of replacing it: {{ template "default" . }}
+const defaultInstallTemplateName = "default"
+
+// defaultInstallTemplates represents the built-in install instructions
+// templates, indexed by the repository kind they apply to.
+var defaultInstallTemplates = map[hub.RepositoryKind]string{
+	hub.Helm: `Add the repository:
+
+` + "```" + `
+helm repo add {{ .Package.Repository.Name }} {{ .Package.Repository.URL }}
+` + "```" + `
+
+Install the chart:
+
+` + "```" + `
+helm install my-{{ .Package.Name }} {{ .Package.Repository.Name }}/{{ .Package.Name }} --version {{ .Package.Version }}
+` + "```" + `
+`,
+	hub.Falco: `Install the rules:
+
+` + "```" + `
+helm upgrade falco -f https://api.securityhub.dev/resources/falco-rules/{{ .Package.NormalizedName }}/custom-rules.yaml stable/falco
+` + "```" + `
+`,
+	hub.OPA: `{{ .Package.Install }}
+`,
+	hub.OLM: `Install Operator Lifecycle Manager (OLM):
+
+` + "```" + `
+curl -sL https://github.com/operator-framework/operator-lifecycle-manager/releases/download/0.15.1/install.sh | bash -s 0.15.1
+` + "```" + `
+
+Install the operator:
+
+` + "```" + `
+kubectl create -f https://operatorhub.io/install/{{ .Package.DefaultChannel }}/{{ .Package.Name }}.yaml
+` + "```" + `
+
+Check the operator is running:
+
+` + "```" + `
+kubectl get csv -n {{ if .Package.Data.isGlobalOperator }}operators{{ else }}my-{{ .Package.Name }}{{ end }}
+` + "```" + `
+`,
+}
+
+// installTemplateData represents the data available to the install
+// instructions templates.
+type installTemplateData struct {
+	Package *hub.Package
+	BaseURL string
+	Vars    map[string]string
+}
+
+// loadInstallTemplates loads the install instructions templates of each
+// kind. Operators can override the built-in ones providing a directory
+// (server.installTemplates.path) with templates named after the kind they
+// apply to (i.e. helm.tmpl). Kinds without a custom template use the
+// built-in one.
+func loadInstallTemplates(cfg *viper.Viper) (map[hub.RepositoryKind]*template.Template, error) {
+	dir := cfg.GetString("server.installTemplates.path")
+	tmpls := make(map[hub.RepositoryKind]*template.Template, len(defaultInstallTemplates))
+	for kind, text := range defaultInstallTemplates {
+		tmpl, err := template.New(defaultInstallTemplateName).Parse(text)
+		if err != nil {
+			return nil, err
+		}
+		if dir != "" {
+			customText, err := ioutil.ReadFile(filepath.Join(dir, hub.GetKindName(kind)+".tmpl"))
+			switch {
+			case err == nil:
+				tmpl, err = tmpl.New(hub.GetKindName(kind)).Parse(string(customText))
+				if err != nil {
+					return nil, fmt.Errorf("error parsing %s install template: %w", hub.GetKindName(kind), err)
+				}
+			case !os.IsNotExist(err):
+				return nil, fmt.Errorf("error reading %s install template: %w", hub.GetKindName(kind), err)
+			}
+		}
+		tmpls[kind] = tmpl
+	}
+	return tmpls, nil
+}
+
+// buildInstallInstructions renders the install instructions of the package
+// provided using the template of its repository kind.
+func (h *Handlers) buildInstallInstructions(p *hub.Package) ([]byte, error) {
+	if p.Repository == nil {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "package repository not available")
+	}
+	tmpl, ok := h.installTmpls[p.Repository.Kind]
+	if !ok {
+		return nil, hub.ErrNotFound
+	}
+	data := &installTemplateData{
+		Package: p,
+		BaseURL: h.cfg.GetString("server.baseURL"),
+		Vars:    h.cfg.GetStringMapString("server.installTemplates.vars"),
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(buf.String()) == "" {
+		return nil, hub.ErrNotFound
+	}
+	return buf.Bytes(), nil
+}
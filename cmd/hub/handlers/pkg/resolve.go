@@ -0,0 +1,69 @@
+package pkg
+
+import (
+	"net/url"
+
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+// resolvedPackage represents a package resolved from some external
+// identifiers, including the url of the package in the hub so that third
+// party tools can link to it.
+type resolvedPackage struct {
+	PackageID      string              `json:"package_id"`
+	Name           string              `json:"name"`
+	NormalizedName string              `json:"normalized_name"`
+	Version        string              `json:"version"`
+	URL            string              `json:"url"`
+	Repository     *resolvedRepository `json:"repository"`
+}
+
+// resolvedRepository represents the repository of a resolved package.
+type resolvedRepository struct {
+	Kind             hub.RepositoryKind `json:"kind"`
+	Name             string             `json:"name"`
+	URL              string             `json:"url"`
+	UserAlias        string             `json:"user_alias,omitempty"`
+	OrganizationName string             `json:"organization_name,omitempty"`
+}
+
+// buildResolveInput builds a ResolvePackageInput instance from the query
+// string provided.
+func buildResolveInput(qs url.Values) (*hub.ResolvePackageInput, error) {
+	input := &hub.ResolvePackageInput{
+		RepositoryURL: qs.Get("repo_url"),
+		PackageName:   qs.Get("name"),
+		SourceURL:     qs.Get("source_url"),
+	}
+	if kindName := qs.Get("kind"); kindName != "" {
+		kind, err := hub.GetKindFromName(kindName)
+		if err != nil {
+			return nil, err
+		}
+		input.RepositoryKind = &kind
+	}
+	return input, nil
+}
+
+// buildResolvedPackages builds the list of resolved packages returned to the
+// clients from the packages provided.
+func buildResolvedPackages(baseURL string, packages []*hub.Package) []*resolvedPackage {
+	resolved := make([]*resolvedPackage, 0, len(packages))
+	for _, p := range packages {
+		resolved = append(resolved, &resolvedPackage{
+			PackageID:      p.PackageID,
+			Name:           p.Name,
+			NormalizedName: p.NormalizedName,
+			Version:        p.Version,
+			URL:            BuildPackageURL(baseURL, p, ""),
+			Repository: &resolvedRepository{
+				Kind:             p.Repository.Kind,
+				Name:             p.Repository.Name,
+				URL:              p.Repository.URL,
+				UserAlias:        p.Repository.UserAlias,
+				OrganizationName: p.Repository.OrganizationName,
+			},
+		})
+	}
+	return resolved
+}
@@ -0,0 +1,199 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// snippetsInterval represents the reconciliation interval used in the
+	// Flux snippets.
+	snippetsInterval = "10m"
+
+	// fluxNamespace represents the namespace where the Flux resources are
+	// expected to be created.
+	fluxNamespace = "flux-system"
+
+	// argoCDNamespace represents the namespace where the Argo CD resources
+	// are expected to be created.
+	argoCDNamespace = "argocd"
+
+	// defaultSnippetNamespace represents the namespace where the package will
+	// be installed when none is provided.
+	defaultSnippetNamespace = "default"
+)
+
+// snippetsBuilders represents the snippets builders available, indexed by
+// the name of the tool they generate manifests for.
+var snippetsBuilders = map[string]func(in *snippetInput) ([]interface{}, error){
+	"argocd": buildArgoCDSnippet,
+	"flux":   buildFluxSnippet,
+}
+
+// snippetInput represents the information used to build a snippet.
+type snippetInput struct {
+	p         *hub.Package
+	namespace string
+	values    yaml.MapSlice
+}
+
+// buildSnippet builds the snippet for the tool provided, returning the
+// resulting manifests as a multi-document yaml.
+func buildSnippet(tool string, p *hub.Package, preset, namespace string) ([]byte, error) {
+	builder, ok := snippetsBuilders[tool]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid tool")
+	}
+	if p.Repository == nil || p.Repository.Kind != hub.Helm {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "snippets are only available for helm charts")
+	}
+	if namespace == "" {
+		namespace = defaultSnippetNamespace
+	}
+	in := &snippetInput{
+		p:         p,
+		namespace: namespace,
+	}
+	if preset != "" {
+		values, err := getPresetValues(p, preset)
+		if err != nil {
+			return nil, err
+		}
+		in.values = values
+	}
+	docs, err := builder(in)
+	if err != nil {
+		return nil, err
+	}
+	return marshalYAMLDocuments(docs)
+}
+
+// marshalYAMLDocuments marshals the documents provided as a multi-document
+// yaml.
+func marshalYAMLDocuments(docs []interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	for i, doc := range docs {
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		data, err := yaml.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// getPresetValues returns the values of the package's values preset
+// provided.
+func getPresetValues(p *hub.Package, preset string) (yaml.MapSlice, error) {
+	var presets []*struct {
+		Name   string `json:"name"`
+		Values string `json:"values"`
+	}
+	if v, ok := p.Data["values_presets"]; ok {
+		data, _ := json.Marshal(v)
+		if err := json.Unmarshal(data, &presets); err != nil {
+			return nil, err
+		}
+	}
+	for _, vp := range presets {
+		if vp.Name != preset {
+			continue
+		}
+		var values yaml.MapSlice
+		if err := yaml.Unmarshal([]byte(vp.Values), &values); err != nil {
+			return nil, err
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "values preset not found")
+}
+
+// buildFluxSnippet builds the Flux HelmRepository and HelmRelease manifests
+// required to install the package provided.
+func buildFluxSnippet(in *snippetInput) ([]interface{}, error) {
+	p := in.p
+	helmRepository := yaml.MapSlice{
+		{Key: "apiVersion", Value: "source.toolkit.fluxcd.io/v1beta1"},
+		{Key: "kind", Value: "HelmRepository"},
+		{Key: "metadata", Value: yaml.MapSlice{
+			{Key: "name", Value: p.Repository.Name},
+			{Key: "namespace", Value: fluxNamespace},
+		}},
+		{Key: "spec", Value: yaml.MapSlice{
+			{Key: "interval", Value: snippetsInterval},
+			{Key: "url", Value: p.Repository.URL},
+		}},
+	}
+	spec := yaml.MapSlice{
+		{Key: "interval", Value: snippetsInterval},
+		{Key: "targetNamespace", Value: in.namespace},
+		{Key: "chart", Value: yaml.MapSlice{
+			{Key: "spec", Value: yaml.MapSlice{
+				{Key: "chart", Value: p.Name},
+				{Key: "version", Value: p.Version},
+				{Key: "sourceRef", Value: yaml.MapSlice{
+					{Key: "kind", Value: "HelmRepository"},
+					{Key: "name", Value: p.Repository.Name},
+					{Key: "namespace", Value: fluxNamespace},
+				}},
+			}},
+		}},
+	}
+	if len(in.values) > 0 {
+		spec = append(spec, yaml.MapItem{Key: "values", Value: in.values})
+	}
+	helmRelease := yaml.MapSlice{
+		{Key: "apiVersion", Value: "helm.toolkit.fluxcd.io/v2beta1"},
+		{Key: "kind", Value: "HelmRelease"},
+		{Key: "metadata", Value: yaml.MapSlice{
+			{Key: "name", Value: p.Name},
+			{Key: "namespace", Value: fluxNamespace},
+		}},
+		{Key: "spec", Value: spec},
+	}
+	return []interface{}{helmRepository, helmRelease}, nil
+}
+
+// buildArgoCDSnippet builds the Argo CD Application manifest required to
+// install the package provided.
+func buildArgoCDSnippet(in *snippetInput) ([]interface{}, error) {
+	p := in.p
+	source := yaml.MapSlice{
+		{Key: "repoURL", Value: p.Repository.URL},
+		{Key: "chart", Value: p.Name},
+		{Key: "targetRevision", Value: p.Version},
+	}
+	if len(in.values) > 0 {
+		values, err := yaml.Marshal(in.values)
+		if err != nil {
+			return nil, err
+		}
+		source = append(source, yaml.MapItem{Key: "helm", Value: yaml.MapSlice{
+			{Key: "values", Value: string(values)},
+		}})
+	}
+	application := yaml.MapSlice{
+		{Key: "apiVersion", Value: "argoproj.io/v1alpha1"},
+		{Key: "kind", Value: "Application"},
+		{Key: "metadata", Value: yaml.MapSlice{
+			{Key: "name", Value: p.Name},
+			{Key: "namespace", Value: argoCDNamespace},
+		}},
+		{Key: "spec", Value: yaml.MapSlice{
+			{Key: "project", Value: "default"},
+			{Key: "source", Value: source},
+			{Key: "destination", Value: yaml.MapSlice{
+				{Key: "server", Value: "https://kubernetes.default.svc"},
+				{Key: "namespace", Value: in.namespace},
+			}},
+		}},
+	}
+	return []interface{}{application}, nil
+}
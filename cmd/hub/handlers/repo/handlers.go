@@ -2,15 +2,20 @@ package repo
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/go-chi/chi"
 	"github.com/rs/zerolog"
-	"github.com/rs/zerolog/log"
 )
 
+// trackingSecretHeader represents the header used to provide the secret when
+// requesting the tracking of a repository.
+const trackingSecretHeader = "X-Tracking-Secret"
+
 // Handlers represents a group of http handlers in charge of handling
 // repositories operations.
 type Handlers struct {
@@ -22,7 +27,7 @@ type Handlers struct {
 func NewHandlers(repoManager hub.RepositoryManager) *Handlers {
 	return &Handlers{
 		repoManager: repoManager,
-		logger:      log.With().Str("handlers", "repo").Logger(),
+		logger:      util.LogWith("handlers").Str("handlers", "repo").Logger(),
 	}
 }
 
@@ -32,12 +37,26 @@ func (h *Handlers) Add(w http.ResponseWriter, r *http.Request) {
 	repo := &hub.Repository{}
 	if err := json.NewDecoder(r.Body).Decode(&repo); err != nil {
 		h.logger.Error().Err(err).Str("method", "Add").Msg(hub.ErrInvalidInput.Error())
-		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
 		return
 	}
 	if err := h.repoManager.Add(r.Context(), orgName, repo); err != nil {
 		h.logger.Error().Err(err).Str("method", "Add").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+// AddCollaborator is an http handler that adds a collaborator to the provided
+// repository with the role provided.
+func (h *Handlers) AddCollaborator(w http.ResponseWriter, r *http.Request) {
+	repoName := chi.URLParam(r, "repoName")
+	userAlias := chi.URLParam(r, "userAlias")
+	role := r.FormValue("role")
+	if err := h.repoManager.AddCollaborator(r.Context(), repoName, userAlias, role); err != nil {
+		h.logger.Error().Err(err).Str("method", "AddCollaborator").Send()
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusCreated)
@@ -52,7 +71,7 @@ func (h *Handlers) CheckAvailability(w http.ResponseWriter, r *http.Request) {
 	available, err := h.repoManager.CheckAvailability(r.Context(), resourceKind, value)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "CheckAvailability").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	if available {
@@ -63,17 +82,73 @@ func (h *Handlers) CheckAvailability(w http.ResponseWriter, r *http.Request) {
 }
 
 // Delete is an http handler that deletes the provided repository from the
-// database.
+// database. When a dry run is requested, what would be removed is returned
+// instead and the repository is left untouched.
 func (h *Handlers) Delete(w http.ResponseWriter, r *http.Request) {
 	repoName := chi.URLParam(r, "repoName")
+	dryRun, err := helpers.IsDryRun(r)
+	if err != nil {
+		h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "Delete").Msg("invalid query")
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	if dryRun {
+		dataJSON, err := h.repoManager.GetDeletionImpactJSON(r.Context(), repoName)
+		if err != nil {
+			h.logger.Error().Err(err).Str("method", "Delete").Send()
+			helpers.RenderErrorJSON(w, r, err)
+			return
+		}
+		helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+		return
+	}
 	if err := h.repoManager.Delete(r.Context(), repoName); err != nil {
 		h.logger.Error().Err(err).Str("method", "Delete").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// DeleteCollaborator is an http handler that removes a collaborator from the
+// provided repository.
+func (h *Handlers) DeleteCollaborator(w http.ResponseWriter, r *http.Request) {
+	repoName := chi.URLParam(r, "repoName")
+	userAlias := chi.URLParam(r, "userAlias")
+	if err := h.repoManager.DeleteCollaborator(r.Context(), repoName, userAlias); err != nil {
+		h.logger.Error().Err(err).Str("method", "DeleteCollaborator").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetCollaborators is an http handler that returns the collaborators of the
+// provided repository.
+func (h *Handlers) GetCollaborators(w http.ResponseWriter, r *http.Request) {
+	repoName := chi.URLParam(r, "repoName")
+	dataJSON, err := h.repoManager.GetCollaboratorsJSON(r.Context(), repoName)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetCollaborators").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
+// GetHealth is an http handler that returns some indicators about the health
+// of the provided repository.
+func (h *Handlers) GetHealth(w http.ResponseWriter, r *http.Request) {
+	repoName := chi.URLParam(r, "repoName")
+	dataJSON, err := h.repoManager.GetHealthJSON(r.Context(), repoName)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetHealth").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, helpers.DefaultAPICacheMaxAge, http.StatusOK)
+}
+
 // GetOwnedByOrg is an http handler that returns the repositories owned by the
 // organization provided. The user doing the request must belong to the
 // organization.
@@ -82,7 +157,7 @@ func (h *Handlers) GetOwnedByOrg(w http.ResponseWriter, r *http.Request) {
 	dataJSON, err := h.repoManager.GetOwnedByOrgJSON(r.Context(), orgName)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "GetOwnedByOrg").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
@@ -94,12 +169,42 @@ func (h *Handlers) GetOwnedByUser(w http.ResponseWriter, r *http.Request) {
 	dataJSON, err := h.repoManager.GetOwnedByUserJSON(r.Context())
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "GetOwnedByUser").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
 }
 
+// RegenerateTrackingSecret is an http handler that generates a new secret to
+// request the tracking of the provided repository. The secret is only returned
+// once, as just a hash of it is stored.
+func (h *Handlers) RegenerateTrackingSecret(w http.ResponseWriter, r *http.Request) {
+	repoName := chi.URLParam(r, "repoName")
+	secret, err := h.repoManager.RegenerateTrackingSecret(r.Context(), repoName)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "RegenerateTrackingSecret").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	dataJSON := []byte(fmt.Sprintf(`{"secret": "%s"}`, secret))
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusCreated)
+}
+
+// RequestTracking is an http handler that requests the tracking of the
+// provided repository, so that it's tracked right away. It's
+// meant to be called by registries or CI pipelines when something new is
+// published, providing the repository's tracking secret in a header.
+func (h *Handlers) RequestTracking(w http.ResponseWriter, r *http.Request) {
+	repoName := chi.URLParam(r, "repoName")
+	secret := r.Header.Get(trackingSecretHeader)
+	if err := h.repoManager.RequestTracking(r.Context(), repoName, secret); err != nil {
+		h.logger.Error().Err(err).Str("method", "RequestTracking").Str("repo", repoName).Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
 // Transfer is an http handler that transfers the provided repository to a
 // different owner.
 func (h *Handlers) Transfer(w http.ResponseWriter, r *http.Request) {
@@ -107,7 +212,7 @@ func (h *Handlers) Transfer(w http.ResponseWriter, r *http.Request) {
 	orgName := r.FormValue("org")
 	if err := h.repoManager.Transfer(r.Context(), repoName, orgName); err != nil {
 		h.logger.Error().Err(err).Str("method", "Transfer").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -119,13 +224,13 @@ func (h *Handlers) Update(w http.ResponseWriter, r *http.Request) {
 	repo := &hub.Repository{}
 	if err := json.NewDecoder(r.Body).Decode(&repo); err != nil {
 		h.logger.Error().Err(err).Str("method", "Update").Msg("invalid repository")
-		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
 		return
 	}
 	repo.Name = chi.URLParam(r, "repoName")
 	if err := h.repoManager.Update(r.Context(), repo); err != nil {
 		h.logger.Error().Err(err).Str("method", "Update").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -0,0 +1,72 @@
+package statement
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/go-chi/chi"
+	"github.com/rs/zerolog"
+)
+
+// Handlers represents a group of http handlers in charge of handling packages
+// statements operations.
+type Handlers struct {
+	statementManager hub.StatementManager
+	logger           zerolog.Logger
+}
+
+// NewHandlers creates a new Handlers instance.
+func NewHandlers(statementManager hub.StatementManager) *Handlers {
+	return &Handlers{
+		statementManager: statementManager,
+		logger:           util.LogWith("handlers").Str("handlers", "statement").Logger(),
+	}
+}
+
+// Add is an http handler that attaches the provided statement to the given
+// package, replacing any existing statement of the same kind.
+func (h *Handlers) Add(w http.ResponseWriter, r *http.Request) {
+	packageID := chi.URLParam(r, "packageID")
+	s := &hub.PackageStatement{}
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		h.logger.Error().Err(err).Str("method", "Add").Msg("invalid statement")
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
+		return
+	}
+	s.Kind = chi.URLParam(r, "kind")
+	if err := h.statementManager.Add(r.Context(), packageID, s); err != nil {
+		h.logger.Error().Err(err).Str("method", "Add").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Delete is an http handler that removes the statement of the kind provided
+// from the given package.
+func (h *Handlers) Delete(w http.ResponseWriter, r *http.Request) {
+	packageID := chi.URLParam(r, "packageID")
+	kind := chi.URLParam(r, "kind")
+	if err := h.statementManager.Delete(r.Context(), packageID, kind); err != nil {
+		h.logger.Error().Err(err).Str("method", "Delete").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetByPackage is an http handler that returns the statements attached to the
+// provided package.
+func (h *Handlers) GetByPackage(w http.ResponseWriter, r *http.Request) {
+	packageID := chi.URLParam(r, "packageID")
+	dataJSON, err := h.statementManager.GetByPackageJSON(r.Context(), packageID)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetByPackage").Send()
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, helpers.GetCacheMaxAge(r, helpers.DefaultAPICacheMaxAge), http.StatusOK)
+}
@@ -4,9 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"html/template"
+	"image"
 	"io/ioutil"
 	"net/http"
 	"path"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -14,6 +16,7 @@ import (
 	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/img"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/go-chi/chi"
 	svg "github.com/h2non/go-is-svg"
 	"github.com/rs/zerolog"
@@ -44,7 +47,7 @@ func NewHandlers(cfg *viper.Viper, imageStore img.Store) *Handlers {
 		cfg:         cfg,
 		imageStore:  imageStore,
 		imagesCache: make(map[string][]byte),
-		logger:      log.With().Str("handlers", "static").Logger(),
+		logger:      util.LogWith("handlers").Str("handlers", "static").Logger(),
 	}
 	h.setupIndexTemplate()
 	return h
@@ -112,13 +115,16 @@ func (h *Handlers) SaveImage(w http.ResponseWriter, r *http.Request) {
 	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "SaveImage").Msg("error reading body data")
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	imageID, err := h.imageStore.SaveImage(r.Context(), data)
 	if err != nil {
+		if errors.Is(err, image.ErrFormat) {
+			err = fmt.Errorf("%w: %s", hub.ErrInvalidInput, "unsupported image format")
+		}
 		h.logger.Error().Err(err).Str("method", "SaveImage").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	dataJSON := []byte(fmt.Sprintf(`{"image_id": "%s"}`, imageID))
@@ -139,10 +145,11 @@ func (h *Handlers) ServeIndex(w http.ResponseWriter, r *http.Request) {
 		description = "Find, install and publish Kubernetes packages"
 	}
 	data := map[string]string{
-		"baseURL":      h.cfg.GetString("server.baseURL"),
-		"title":        title,
-		"description":  description,
-		"gaTrackingID": h.cfg.GetString("analytics.gaTrackingID"),
+		"baseURL":         h.cfg.GetString("server.baseURL"),
+		"title":           title,
+		"description":     description,
+		"gaTrackingID":    h.cfg.GetString("analytics.gaTrackingID"),
+		"internalCatalog": strconv.FormatBool(h.cfg.GetBool("server.internalCatalog.enabled")),
 	}
 	if err := h.indexTmpl.Execute(w, data); err != nil {
 		h.logger.Error().Err(err).Msg("Error executing index template")
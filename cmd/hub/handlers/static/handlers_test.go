@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"image"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -118,6 +119,20 @@ func TestSaveImage(t *testing.T) {
 		hw.is.AssertExpectations(t)
 	})
 
+	t.Run("imageStore.SaveImage failed: unsupported image format", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", strings.NewReader("imageData"))
+
+		hw := newHandlersWrapper()
+		hw.is.On("SaveImage", r.Context(), []byte("imageData")).Return("", image.ErrFormat)
+		hw.h.SaveImage(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		hw.is.AssertExpectations(t)
+	})
+
 	t.Run("imageStore.SaveImage succeeded", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		r, _ := http.NewRequest("POST", "/", strings.NewReader("imageData"))
@@ -151,7 +166,7 @@ func TestServeIndex(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 	assert.Equal(t, helpers.BuildCacheControlHeader(indexCacheMaxAge), h.Get("Cache-Control"))
-	assert.Equal(t, []byte("title:Artifact Hub\ndescription:Find, install and publish Kubernetes packages\ngaTrackingID:1234\n"), data)
+	assert.Equal(t, []byte("title:Artifact Hub\ndescription:Find, install and publish Kubernetes packages\ngaTrackingID:1234\ninternalCatalog:false\n"), data)
 }
 
 func TestServeStaticFile(t *testing.T) {
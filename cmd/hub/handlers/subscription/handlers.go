@@ -8,11 +8,15 @@ import (
 
 	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/go-chi/chi"
 	"github.com/rs/zerolog"
-	"github.com/rs/zerolog/log"
 )
 
+// defaultChangesLimit represents the maximum number of changes entries
+// returned when no limit is provided.
+const defaultChangesLimit = 20
+
 // Handlers represents a group of http handlers in charge of handling
 // subscriptions operations.
 type Handlers struct {
@@ -24,7 +28,7 @@ type Handlers struct {
 func NewHandlers(subscriptionManager hub.SubscriptionManager) *Handlers {
 	return &Handlers{
 		subscriptionManager: subscriptionManager,
-		logger:              log.With().Str("handlers", "subscription").Logger(),
+		logger:              util.LogWith("handlers").Str("handlers", "subscription").Logger(),
 	}
 }
 
@@ -33,12 +37,12 @@ func (h *Handlers) Add(w http.ResponseWriter, r *http.Request) {
 	s := &hub.Subscription{}
 	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
 		h.logger.Error().Err(err).Str("method", "Add").Msg("invalid subscription")
-		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
 		return
 	}
 	if err := h.subscriptionManager.Add(r.Context(), s); err != nil {
 		h.logger.Error().Err(err).Str("method", "Add").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusCreated)
@@ -51,7 +55,7 @@ func (h *Handlers) Delete(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		errMsg := "invalid event kind"
 		h.logger.Error().Err(err).Str("method", "Delete").Msg(errMsg)
-		helpers.RenderErrorJSON(w, fmt.Errorf("%w: %s", hub.ErrInvalidInput, errMsg))
+		helpers.RenderErrorJSON(w, r, fmt.Errorf("%w: %s", hub.ErrInvalidInput, errMsg))
 		return
 	}
 	s := &hub.Subscription{
@@ -60,7 +64,7 @@ func (h *Handlers) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 	if err := h.subscriptionManager.Delete(r.Context(), s); err != nil {
 		h.logger.Error().Err(err).Str("method", "Delete").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -73,7 +77,7 @@ func (h *Handlers) GetByPackage(w http.ResponseWriter, r *http.Request) {
 	dataJSON, err := h.subscriptionManager.GetByPackageJSON(r.Context(), packageID)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "GetByPackage").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
@@ -85,7 +89,43 @@ func (h *Handlers) GetByUser(w http.ResponseWriter, r *http.Request) {
 	dataJSON, err := h.subscriptionManager.GetByUserJSON(r.Context())
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "GetByUser").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
+// GetUserChanges is an http handler that returns the changes introduced in the
+// versions of the packages the user doing the request is subscribed to, newest
+// first. It's meant to be used to build a timeline of what's new for the user.
+func (h *Handlers) GetUserChanges(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	limit := defaultChangesLimit
+	if v := qs.Get("limit"); v != "" {
+		var err error
+		limit, err = strconv.Atoi(v)
+		if err != nil {
+			err = fmt.Errorf("%w: invalid limit: %s", hub.ErrInvalidInput, v)
+			h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "GetUserChanges").Msg("invalid query")
+			helpers.RenderErrorJSON(w, r, err)
+			return
+		}
+	}
+	var offset int
+	if v := qs.Get("offset"); v != "" {
+		var err error
+		offset, err = strconv.Atoi(v)
+		if err != nil {
+			err = fmt.Errorf("%w: invalid offset: %s", hub.ErrInvalidInput, v)
+			h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "GetUserChanges").Msg("invalid query")
+			helpers.RenderErrorJSON(w, r, err)
+			return
+		}
+	}
+	dataJSON, err := h.subscriptionManager.GetUserChangesJSON(r.Context(), limit, offset)
+	if err != nil {
+		h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "GetUserChanges").Send()
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
@@ -313,6 +313,58 @@ func TestGetByUser(t *testing.T) {
 	})
 }
 
+func TestGetUserChanges(t *testing.T) {
+	t.Run("invalid query", func(t *testing.T) {
+		for _, qs := range []string{"limit=invalid", "offset=invalid"} {
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest("GET", "/changes?"+qs, nil)
+			r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+			hw := newHandlersWrapper()
+			hw.h.GetUserChanges(w, r)
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, qs)
+		}
+	})
+
+	t.Run("error getting user changes", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/changes", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.sm.On("GetUserChangesJSON", r.Context(), defaultChangesLimit, 0).Return(nil, tests.ErrFakeDatabaseFailure)
+		hw.h.GetUserChanges(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.sm.AssertExpectations(t)
+	})
+
+	t.Run("get user changes succeeded", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/changes?limit=10&offset=20", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.sm.On("GetUserChangesJSON", r.Context(), 10, 20).Return([]byte("dataJSON"), nil)
+		hw.h.GetUserChanges(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := ioutil.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), h.Get("Cache-Control"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		hw.sm.AssertExpectations(t)
+	})
+}
+
 type handlersWrapper struct {
 	sm *subscription.ManagerMock
 	h  *Handlers
@@ -0,0 +1,200 @@
+package suppression
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/rs/zerolog"
+)
+
+// HTTPClient defines the methods an HTTPClient implementation must provide.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Handlers represents a group of http handlers in charge of receiving the
+// bounces and complaints notifications sent by the email delivery providers,
+// suppressing the addresses affected.
+type Handlers struct {
+	suppressionManager hub.EmailSuppressionManager
+	secret             string
+	hc                 HTTPClient
+	logger             zerolog.Logger
+}
+
+// NewHandlers creates a new Handlers instance. The notifications received
+// must provide the secret given in the secret query parameter.
+func NewHandlers(suppressionManager hub.EmailSuppressionManager, secret string, hc HTTPClient) *Handlers {
+	return &Handlers{
+		suppressionManager: suppressionManager,
+		secret:             secret,
+		hc:                 hc,
+		logger:             util.LogWith("handlers").Str("handlers", "suppression").Logger(),
+	}
+}
+
+// RequireSecret is a middleware that requires the notifications received to
+// provide a valid secret.
+func (h *Handlers) RequireSecret(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secret := r.URL.Query().Get("secret")
+		if h.secret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(h.secret)) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// snsMessage represents a message delivered by Amazon SNS.
+type snsMessage struct {
+	Type         string `json:"Type"`
+	Message      string `json:"Message"`
+	SubscribeURL string `json:"SubscribeURL"`
+}
+
+// sesRecipient represents a recipient affected by an Amazon SES notification.
+type sesRecipient struct {
+	EmailAddress string `json:"emailAddress"`
+}
+
+// sesNotification represents a bounce or complaint notification published by
+// Amazon SES, either as a notification or as an event of a configuration set.
+type sesNotification struct {
+	NotificationType string `json:"notificationType"`
+	EventType        string `json:"eventType"`
+	Bounce           struct {
+		BounceType        string          `json:"bounceType"`
+		BouncedRecipients []*sesRecipient `json:"bouncedRecipients"`
+	} `json:"bounce"`
+	Complaint struct {
+		ComplainedRecipients []*sesRecipient `json:"complainedRecipients"`
+	} `json:"complaint"`
+}
+
+// SES is an http handler that processes the Amazon SES bounces and complaints
+// notifications delivered by Amazon SNS. Only permanent bounces suppress the
+// recipients affected. SNS subscriptions confirmation requests are confirmed
+// automatically.
+func (h *Handlers) SES(w http.ResponseWriter, r *http.Request) {
+	var m *snsMessage
+	if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+		h.logger.Error().Err(err).Str("method", "SES").Msg("invalid sns message")
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
+		return
+	}
+	switch m.Type {
+	case "SubscriptionConfirmation":
+		if err := h.confirmSNSSubscription(r, m.SubscribeURL); err != nil {
+			h.logger.Error().Err(err).Str("method", "SES").Msg("error confirming sns subscription")
+			helpers.RenderErrorJSON(w, r, err)
+			return
+		}
+	case "Notification":
+		var n *sesNotification
+		if err := json.Unmarshal([]byte(m.Message), &n); err != nil {
+			h.logger.Error().Err(err).Str("method", "SES").Msg("invalid ses notification")
+			helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
+			return
+		}
+		var recipients []*sesRecipient
+		var reason string
+		notificationType := n.NotificationType
+		if notificationType == "" {
+			notificationType = n.EventType
+		}
+		switch notificationType {
+		case "Bounce":
+			if n.Bounce.BounceType == "Permanent" {
+				recipients, reason = n.Bounce.BouncedRecipients, hub.EmailBounce
+			}
+		case "Complaint":
+			recipients, reason = n.Complaint.ComplainedRecipients, hub.EmailComplaint
+		}
+		for _, rcpt := range recipients {
+			if err := h.suppress(r, rcpt.EmailAddress, reason); err != nil {
+				h.logger.Error().Err(err).Str("method", "SES").Send()
+				helpers.RenderErrorJSON(w, r, err)
+				return
+			}
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// confirmSNSSubscription confirms the Amazon SNS subscription using the url
+// provided in the confirmation request.
+func (h *Handlers) confirmSNSSubscription(r *http.Request, subscribeURL string) error {
+	u, err := url.Parse(subscribeURL)
+	if err != nil || u.Scheme != "https" || !strings.HasSuffix(u.Hostname(), ".amazonaws.com") {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid subscribe url")
+	}
+	req, _ := http.NewRequest("GET", u.String(), nil)
+	req = req.WithContext(r.Context())
+	resp, err := h.hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code received: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sendGridEvent represents an event delivered by the SendGrid event webhook.
+type sendGridEvent struct {
+	Email string `json:"email"`
+	Event string `json:"event"`
+	Type  string `json:"type"`
+}
+
+// SendGrid is an http handler that processes the events delivered by the
+// SendGrid event webhook. Bounces (excluding the blocked ones, which are
+// temporary) and spam reports suppress the recipients affected. The rest of
+// the events are ignored.
+func (h *Handlers) SendGrid(w http.ResponseWriter, r *http.Request) {
+	var events []*sendGridEvent
+	if err := json.NewDecoder(r.Body).Decode(&events); err != nil {
+		h.logger.Error().Err(err).Str("method", "SendGrid").Msg("invalid events")
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
+		return
+	}
+	for _, e := range events {
+		var reason string
+		switch {
+		case e.Event == "bounce" && e.Type != "blocked":
+			reason = hub.EmailBounce
+		case e.Event == "spamreport":
+			reason = hub.EmailComplaint
+		default:
+			continue
+		}
+		if err := h.suppress(r, e.Email, reason); err != nil {
+			h.logger.Error().Err(err).Str("method", "SendGrid").Send()
+			helpers.RenderErrorJSON(w, r, err)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// suppress adds the email address provided to the suppression list. Invalid
+// addresses are ignored, as the providers would keep retrying the delivery of
+// the notification otherwise.
+func (h *Handlers) suppress(r *http.Request, email, reason string) error {
+	err := h.suppressionManager.Suppress(r.Context(), email, reason)
+	if errors.Is(err, hub.ErrInvalidInput) {
+		h.logger.Warn().Err(err).Str("email", email).Msg("invalid recipient ignored")
+		return nil
+	}
+	return err
+}
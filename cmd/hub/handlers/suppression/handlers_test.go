@@ -0,0 +1,305 @@
+package suppression
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/suppression"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const secret = "secret"
+
+func TestMain(m *testing.M) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	os.Exit(m.Run())
+}
+
+func TestRequireSecret(t *testing.T) {
+	checkNextCalled := func(called *bool) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*called = true
+		})
+	}
+
+	t.Run("invalid secret", func(t *testing.T) {
+		for _, qs := range []string{"", "?secret=", "?secret=invalid"} {
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest("POST", "/"+qs, nil)
+
+			hw := newHandlersWrapper()
+			var nextCalled bool
+			hw.h.RequireSecret(checkNextCalled(&nextCalled)).ServeHTTP(w, r)
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, qs)
+			assert.False(t, nextCalled, qs)
+		}
+	})
+
+	t.Run("secret not configured", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/?secret=", nil)
+
+		h := NewHandlers(&suppression.ManagerMock{}, "", &httpClientMock{})
+		var nextCalled bool
+		h.RequireSecret(checkNextCalled(&nextCalled)).ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.False(t, nextCalled)
+	})
+
+	t.Run("valid secret", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/?secret="+secret, nil)
+
+		hw := newHandlersWrapper()
+		var nextCalled bool
+		hw.h.RequireSecret(checkNextCalled(&nextCalled)).ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.True(t, nextCalled)
+	})
+}
+
+func TestSES(t *testing.T) {
+	t.Run("invalid sns message", func(t *testing.T) {
+		for _, body := range []string{
+			"{invalid",
+			`{"Type": "Notification", "Message": "{invalid"}`,
+		} {
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+
+			hw := newHandlersWrapper()
+			hw.h.SES(w, r)
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, body)
+		}
+	})
+
+	t.Run("subscription confirmation", func(t *testing.T) {
+		t.Run("invalid subscribe url", func(t *testing.T) {
+			for _, u := range []string{
+				"",
+				"http://sns.us-east-1.amazonaws.com/?Action=ConfirmSubscription",
+				"https://attacker.com/?Action=ConfirmSubscription",
+			} {
+				body := fmt.Sprintf(`{"Type": "SubscriptionConfirmation", "SubscribeURL": "%s"}`, u)
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+
+				hw := newHandlersWrapper()
+				hw.h.SES(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode, u)
+				hw.hc.AssertExpectations(t)
+			}
+		})
+
+		subscribeURL := "https://sns.us-east-1.amazonaws.com/?Action=ConfirmSubscription"
+		body := fmt.Sprintf(`{"Type": "SubscriptionConfirmation", "SubscribeURL": "%s"}`, subscribeURL)
+
+		t.Run("confirmation failed", func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+
+			hw := newHandlersWrapper()
+			hw.hc.On("Do", mock.Anything).Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusForbidden,
+			}, nil)
+			hw.h.SES(w, r)
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+			hw.hc.AssertExpectations(t)
+		})
+
+		t.Run("confirmation succeeded", func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+
+			hw := newHandlersWrapper()
+			hw.hc.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+				return req.Method == "GET" && req.URL.String() == subscribeURL
+			})).Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusOK,
+			}, nil)
+			hw.h.SES(w, r)
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+			hw.hc.AssertExpectations(t)
+		})
+	})
+
+	t.Run("notifications", func(t *testing.T) {
+		testCases := []struct {
+			description        string
+			message            string
+			expectedSuppressed map[string]string
+		}{
+			{
+				"permanent bounce",
+				`{"notificationType": "Bounce", "bounce": {"bounceType": "Permanent", "bouncedRecipients": [{"emailAddress": "user1@email.com"}, {"emailAddress": "user2@email.com"}]}}`,
+				map[string]string{
+					"user1@email.com": hub.EmailBounce,
+					"user2@email.com": hub.EmailBounce,
+				},
+			},
+			{
+				"transient bounce",
+				`{"notificationType": "Bounce", "bounce": {"bounceType": "Transient", "bouncedRecipients": [{"emailAddress": "user1@email.com"}]}}`,
+				nil,
+			},
+			{
+				"complaint event",
+				`{"eventType": "Complaint", "complaint": {"complainedRecipients": [{"emailAddress": "user1@email.com"}]}}`,
+				map[string]string{
+					"user1@email.com": hub.EmailComplaint,
+				},
+			},
+			{
+				"delivery",
+				`{"notificationType": "Delivery"}`,
+				nil,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.description, func(t *testing.T) {
+				body := fmt.Sprintf(`{"Type": "Notification", "Message": %q}`, tc.message)
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+
+				hw := newHandlersWrapper()
+				for email, reason := range tc.expectedSuppressed {
+					hw.sm.On("Suppress", r.Context(), email, reason).Return(nil)
+				}
+				hw.h.SES(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+				hw.sm.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("error suppressing email", func(t *testing.T) {
+		message := `{"notificationType": "Complaint", "complaint": {"complainedRecipients": [{"emailAddress": "user1@email.com"}]}}`
+		body := fmt.Sprintf(`{"Type": "Notification", "Message": %q}`, message)
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+
+		hw := newHandlersWrapper()
+		hw.sm.On("Suppress", r.Context(), "user1@email.com", hub.EmailComplaint).Return(tests.ErrFakeDatabaseFailure)
+		hw.h.SES(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.sm.AssertExpectations(t)
+	})
+}
+
+func TestSendGrid(t *testing.T) {
+	t.Run("invalid events", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", strings.NewReader(`{"email": "user1@email.com"}`))
+
+		hw := newHandlersWrapper()
+		hw.h.SendGrid(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("error suppressing email", func(t *testing.T) {
+		body := `[{"email": "user1@email.com", "event": "bounce", "type": "bounce"}]`
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+
+		hw := newHandlersWrapper()
+		hw.sm.On("Suppress", r.Context(), "user1@email.com", hub.EmailBounce).Return(tests.ErrFakeDatabaseFailure)
+		hw.h.SendGrid(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.sm.AssertExpectations(t)
+	})
+
+	t.Run("events processed", func(t *testing.T) {
+		body := `[
+			{"email": "user1@email.com", "event": "bounce", "type": "bounce"},
+			{"email": "user2@email.com", "event": "bounce", "type": "blocked"},
+			{"email": "user3@email.com", "event": "spamreport"},
+			{"email": "user4@email.com", "event": "delivered"},
+			{"email": "", "event": "spamreport"}
+		]`
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", strings.NewReader(body))
+
+		hw := newHandlersWrapper()
+		hw.sm.On("Suppress", r.Context(), "user1@email.com", hub.EmailBounce).Return(nil)
+		hw.sm.On("Suppress", r.Context(), "user3@email.com", hub.EmailComplaint).Return(nil)
+		hw.sm.On("Suppress", r.Context(), "", hub.EmailComplaint).Return(hub.ErrInvalidInput)
+		hw.h.SendGrid(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		hw.sm.AssertExpectations(t)
+	})
+}
+
+type handlersWrapper struct {
+	sm *suppression.ManagerMock
+	hc *httpClientMock
+	h  *Handlers
+}
+
+func newHandlersWrapper() *handlersWrapper {
+	sm := &suppression.ManagerMock{}
+	hc := &httpClientMock{}
+
+	return &handlersWrapper{
+		sm: sm,
+		hc: hc,
+		h:  NewHandlers(sm, secret, hc),
+	}
+}
+
+type httpClientMock struct {
+	mock.Mock
+}
+
+func (m *httpClientMock) Do(req *http.Request) (*http.Response, error) {
+	args := m.Called(req)
+	resp, _ := args.Get(0).(*http.Response)
+	return resp, args.Error(1)
+}
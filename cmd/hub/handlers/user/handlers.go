@@ -17,11 +17,11 @@ import (
 	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/user"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/go-chi/chi"
 	"github.com/google/go-github/github"
 	"github.com/gorilla/securecookie"
 	"github.com/rs/zerolog"
-	"github.com/rs/zerolog/log"
 	"github.com/satori/uuid"
 	"github.com/spf13/viper"
 	"golang.org/x/oauth2"
@@ -39,6 +39,9 @@ const (
 	apiKeyHeader         = "X-API-KEY"
 )
 
+// errSignupNotAllowed indicates that the user is not allowed to sign up.
+var errSignupNotAllowed = errors.New("user not allowed to sign up")
+
 // Handlers represents a group of http handlers in charge of handling
 // users operations.
 type Handlers struct {
@@ -84,7 +87,7 @@ func NewHandlers(userManager hub.UserManager, cfg *viper.Viper) *Handlers {
 		cfg:         cfg,
 		sc:          sc,
 		oauthConfig: oauthConfig,
-		logger:      log.With().Str("handlers", "user").Logger(),
+		logger:      util.LogWith("handlers").Str("handlers", "user").Logger(),
 	}
 }
 
@@ -125,7 +128,7 @@ func (h *Handlers) CheckAvailability(w http.ResponseWriter, r *http.Request) {
 	available, err := h.userManager.CheckAvailability(r.Context(), resourceKind, value)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "CheckAvailability").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	if available {
@@ -140,7 +143,7 @@ func (h *Handlers) GetProfile(w http.ResponseWriter, r *http.Request) {
 	dataJSON, err := h.userManager.GetProfileJSON(r.Context())
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "GetProfile").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
@@ -150,6 +153,11 @@ func (h *Handlers) GetProfile(w http.ResponseWriter, r *http.Request) {
 // request into the request context when a valid session id is provided.
 func (h *Handlers) InjectUserID(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Skip if the userID has already been injected by a previous middleware
+		if _, ok := r.Context().Value(hub.UserIDKey).(string); ok {
+			next.ServeHTTP(w, r)
+			return
+		}
 		var userID string
 
 		// Inject userID in context if available and call next handler
@@ -191,7 +199,7 @@ func (h *Handlers) Login(w http.ResponseWriter, r *http.Request) {
 	var input map[string]string
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		h.logger.Error().Err(err).Str("method", "Add").Msg(hub.ErrInvalidInput.Error())
-		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
 		return
 	}
 
@@ -199,7 +207,7 @@ func (h *Handlers) Login(w http.ResponseWriter, r *http.Request) {
 	checkCredentialsOutput, err := h.userManager.CheckCredentials(r.Context(), input["email"], input["password"])
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "Login").Msg("checkCredentials failed")
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	if !checkCredentialsOutput.Valid {
@@ -217,7 +225,7 @@ func (h *Handlers) Login(w http.ResponseWriter, r *http.Request) {
 	sessionID, err := h.userManager.RegisterSession(r.Context(), session)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "Login").Msg("registerSession failed")
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 
@@ -225,7 +233,7 @@ func (h *Handlers) Login(w http.ResponseWriter, r *http.Request) {
 	encodedSessionID, err := h.sc.Encode(sessionCookieName, sessionID)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "Login").Msg("sessionID encoding failed")
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	cookie := &http.Cookie{
@@ -409,7 +417,7 @@ func (h *Handlers) OauthCallback(w http.ResponseWriter, r *http.Request) {
 	}
 	userID, err := h.registerUserWithOauth(r.Context(), provider, providerConfig, oauthToken)
 	if err != nil {
-		logger.Error().Err(err).Msg("oauth code exchange failed")
+		logger.Error().Err(err).Msg("oauth user registration failed")
 		http.Redirect(w, r, oauthFailedURL, http.StatusSeeOther)
 		return
 	}
@@ -487,20 +495,20 @@ func (h *Handlers) RegisterUser(w http.ResponseWriter, r *http.Request) {
 	err := json.NewDecoder(r.Body).Decode(&u)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "RegisterUser").Msg("invalid user")
-		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
 		return
 	}
 	u.EmailVerified = false
 	if u.Password == "" {
 		errMsg := "password not provided"
 		h.logger.Error().Err(err).Str("method", "RegisterUser").Msg(errMsg)
-		helpers.RenderErrorJSON(w, fmt.Errorf("%w: %s", hub.ErrInvalidInput, errMsg))
+		helpers.RenderErrorJSON(w, r, fmt.Errorf("%w: %s", hub.ErrInvalidInput, errMsg))
 		return
 	}
 	err = h.userManager.RegisterUser(r.Context(), u, h.cfg.GetString("server.baseURL"))
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "RegisterUser").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusCreated)
@@ -527,7 +535,13 @@ func (h *Handlers) registerUserWithOauth(
 	if err != nil {
 		return "", err
 	}
+	return h.registerOauthUser(ctx, u)
+}
 
+// registerOauthUser registers the user built from the oauth provider profile
+// if he's not already registered, returning the user id. In the internal
+// catalog mode only the publishers and the allowed users can be registered.
+func (h *Handlers) registerOauthUser(ctx context.Context, u *hub.User) (string, error) {
 	// Check if user exists
 	userID, err := h.userManager.GetUserID(ctx, u.Email)
 	if err != nil && !errors.Is(err, user.ErrNotFound) {
@@ -536,6 +550,15 @@ func (h *Handlers) registerUserWithOauth(
 
 	// Register user if needed
 	if userID == "" {
+		if h.cfg.GetBool("server.internalCatalog.enabled") {
+			allowed := append(
+				h.cfg.GetStringSlice("server.internalCatalog.publishers"),
+				h.cfg.GetStringSlice("server.internalCatalog.allowedUsers")...,
+			)
+			if !newEmailMatcher(allowed)(u.Email) {
+				return "", errSignupNotAllowed
+			}
+		}
 		u.EmailVerified = true
 		err := h.userManager.RegisterUser(ctx, u, "")
 		if err != nil {
@@ -553,6 +576,12 @@ func (h *Handlers) registerUserWithOauth(
 // RequireLogin is a middleware that verifies if a user is logged in.
 func (h *Handlers) RequireLogin(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Skip authentication if it has already been done by a previous
+		// middleware (i.e. when all routes require login)
+		if _, ok := r.Context().Value(hub.UserIDKey).(string); ok {
+			next.ServeHTTP(w, r)
+			return
+		}
 		var userID string
 
 		// Try cookie based authentication
@@ -604,7 +633,14 @@ func (h *Handlers) RequireLogin(next http.Handler) http.Handler {
 				return
 			}
 
+			// Keys scoped to a repository can only be used to push packages
+			if checkAPIKeyOutput.RepositoryID != "" {
+				helpers.RenderErrorWithCodeJSON(w, nil, http.StatusUnauthorized)
+				return
+			}
+
 			userID = checkAPIKeyOutput.UserID
+			util.SetRequestAPIKey(r.Context(), checkAPIKeyOutput.APIKeyID)
 		}
 
 		// Return if no authentication method succeeded
@@ -619,13 +655,79 @@ func (h *Handlers) RequireLogin(next http.Handler) http.Handler {
 	})
 }
 
+// RequireRepositoryAPIKey is a middleware that verifies if the request was
+// authenticated using an api key scoped to a repository. The user and the
+// repository the key belongs to are injected in the request context.
+func (h *Handlers) RequireRepositoryAPIKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Extract API key from header
+		keyB64 := r.Header.Get(apiKeyHeader)
+		if keyB64 == "" {
+			helpers.RenderErrorWithCodeJSON(w, nil, http.StatusUnauthorized)
+			return
+		}
+		key, err := base64.StdEncoding.DecodeString(keyB64)
+		if err != nil {
+			h.logger.Error().Err(err).Str("method", "RequireRepositoryAPIKey").Msg("key decoding failed")
+			helpers.RenderErrorWithCodeJSON(w, nil, http.StatusUnauthorized)
+			return
+		}
+
+		// Check the API key provided is valid and scoped to a repository
+		checkAPIKeyOutput, err := h.userManager.CheckAPIKey(r.Context(), key)
+		if err != nil {
+			h.logger.Error().Err(err).Str("method", "RequireRepositoryAPIKey").Msg("checkAPIKey failed")
+			helpers.RenderErrorWithCodeJSON(w, nil, http.StatusInternalServerError)
+			return
+		}
+		if !checkAPIKeyOutput.Valid || checkAPIKeyOutput.RepositoryID == "" {
+			helpers.RenderErrorWithCodeJSON(w, nil, http.StatusUnauthorized)
+			return
+		}
+
+		// Inject userID and repositoryID in context and call next handler
+		util.SetRequestAPIKey(r.Context(), checkAPIKeyOutput.APIKeyID)
+		ctx := context.WithValue(r.Context(), hub.UserIDKey, checkAPIKeyOutput.UserID)
+		ctx = context.WithValue(ctx, hub.RepositoryIDKey, checkAPIKeyOutput.RepositoryID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequirePublisher is a middleware that only allows the users configured as
+// publishers in the internal catalog mode to proceed. Publishers can be
+// configured using their email address or their domain (i.e. @example.com).
+// It must be used after RequireLogin.
+func (h *Handlers) RequirePublisher(next http.Handler) http.Handler {
+	isPublisher := newEmailMatcher(h.cfg.GetStringSlice("server.internalCatalog.publishers"))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dataJSON, err := h.userManager.GetProfileJSON(r.Context())
+		if err != nil {
+			h.logger.Error().Err(err).Str("method", "RequirePublisher").Send()
+			helpers.RenderErrorJSON(w, r, err)
+			return
+		}
+		var u *hub.User
+		if err := json.Unmarshal(dataJSON, &u); err != nil || u == nil {
+			h.logger.Error().Err(err).Str("method", "RequirePublisher").Msg("invalid profile")
+			helpers.RenderErrorWithCodeJSON(w, nil, http.StatusInternalServerError)
+			return
+		}
+		if !isPublisher(u.Email) {
+			helpers.RenderErrorWithCodeJSON(w, nil, http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // UpdatePassword is an http handler used to update the password in the hub
 // database.
 func (h *Handlers) UpdatePassword(w http.ResponseWriter, r *http.Request) {
 	var input map[string]string
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		h.logger.Error().Err(err).Str("method", "UpdatePassword").Msg(hub.ErrInvalidInput.Error())
-		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
 		return
 	}
 	err := h.userManager.UpdatePassword(r.Context(), input["old"], input["new"])
@@ -634,7 +736,7 @@ func (h *Handlers) UpdatePassword(w http.ResponseWriter, r *http.Request) {
 		if errors.Is(err, user.ErrInvalidPassword) {
 			helpers.RenderErrorWithCodeJSON(w, nil, http.StatusUnauthorized)
 		} else {
-			helpers.RenderErrorJSON(w, err)
+			helpers.RenderErrorJSON(w, r, err)
 		}
 		return
 	}
@@ -647,13 +749,13 @@ func (h *Handlers) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 	err := json.NewDecoder(r.Body).Decode(&u)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "UpdateUserProfile").Msg("invalid user")
-		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
 		return
 	}
 	err = h.userManager.UpdateProfile(r.Context(), u)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "UpdateUserProfile").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -664,13 +766,13 @@ func (h *Handlers) VerifyEmail(w http.ResponseWriter, r *http.Request) {
 	var input map[string]string
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		h.logger.Error().Err(err).Str("method", "VerifyEmail").Msg(hub.ErrInvalidInput.Error())
-		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
 		return
 	}
 	verified, err := h.userManager.VerifyEmail(r.Context(), input["code"])
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "VerifyEmail").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	if !verified {
@@ -707,3 +809,25 @@ func NewOauthState(s string) (*OauthState, error) {
 	}
 	return state, nil
 }
+
+// newEmailMatcher returns a function that checks if an email address matches
+// any of the entries provided, which can be email addresses or domains (i.e.
+// @example.com). The comparison is case insensitive.
+func newEmailMatcher(entries []string) func(email string) bool {
+	m := make(map[string]struct{}, len(entries))
+	for _, e := range entries {
+		m[strings.ToLower(e)] = struct{}{}
+	}
+	return func(email string) bool {
+		email = strings.ToLower(email)
+		if _, ok := m[email]; ok {
+			return true
+		}
+		if i := strings.LastIndex(email, "@"); i != -1 {
+			if _, ok := m[email[i:]]; ok {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/tests"
 	"github.com/artifacthub/hub/internal/user"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/go-chi/chi"
 	"github.com/rs/zerolog"
 	"github.com/spf13/viper"
@@ -584,6 +585,79 @@ func TestRegisterUser(t *testing.T) {
 	})
 }
 
+func TestRegisterOauthUser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("error checking if user exists", func(t *testing.T) {
+		hw := newHandlersWrapper()
+		hw.um.On("GetUserID", ctx).Return("", tests.ErrFakeDatabaseFailure)
+		userID, err := hw.h.registerOauthUser(ctx, &hub.User{Email: "user1@email.com"})
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.Empty(t, userID)
+		hw.um.AssertExpectations(t)
+	})
+
+	t.Run("user already registered", func(t *testing.T) {
+		hw := newHandlersWrapper()
+		hw.cfg.Set("server.internalCatalog.enabled", true)
+		hw.um.On("GetUserID", ctx).Return("userID", nil)
+		userID, err := hw.h.registerOauthUser(ctx, &hub.User{Email: "user1@email.com"})
+		assert.NoError(t, err)
+		assert.Equal(t, "userID", userID)
+		hw.um.AssertExpectations(t)
+	})
+
+	t.Run("user registered", func(t *testing.T) {
+		hw := newHandlersWrapper()
+		hw.um.On("GetUserID", ctx).Return("", user.ErrNotFound).Once()
+		hw.um.On("RegisterUser", ctx, mock.MatchedBy(func(u *hub.User) bool {
+			return u.Email == "user1@email.com" && u.EmailVerified
+		}), "").Return(nil)
+		hw.um.On("GetUserID", ctx).Return("userID", nil).Once()
+		userID, err := hw.h.registerOauthUser(ctx, &hub.User{Email: "user1@email.com"})
+		assert.NoError(t, err)
+		assert.Equal(t, "userID", userID)
+		hw.um.AssertExpectations(t)
+	})
+
+	t.Run("internal catalog mode", func(t *testing.T) {
+		testCases := []struct {
+			email         string
+			signupAllowed bool
+		}{
+			{"user1@email.com", false},
+			{"publisher@email.com", true},
+			{"allowed@email.com", true},
+			{"Allowed@Email.com", true},
+			{"user1@example.com", true},
+			{"user1@sub.example.com", false},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.email, func(t *testing.T) {
+				hw := newHandlersWrapper()
+				hw.cfg.Set("server.internalCatalog.enabled", true)
+				hw.cfg.Set("server.internalCatalog.publishers", []string{"publisher@email.com"})
+				hw.cfg.Set("server.internalCatalog.allowedUsers", []string{"allowed@email.com", "@example.com"})
+				hw.um.On("GetUserID", ctx).Return("", user.ErrNotFound).Once()
+				if tc.signupAllowed {
+					hw.um.On("RegisterUser", ctx, mock.Anything, "").Return(nil)
+					hw.um.On("GetUserID", ctx).Return("userID", nil).Once()
+				}
+				userID, err := hw.h.registerOauthUser(ctx, &hub.User{Email: tc.email})
+				if tc.signupAllowed {
+					assert.NoError(t, err)
+					assert.Equal(t, "userID", userID)
+				} else {
+					assert.Equal(t, errSignupNotAllowed, err)
+					assert.Empty(t, userID)
+				}
+				hw.um.AssertExpectations(t)
+			})
+		}
+	})
+}
+
 func TestRequireLogin(t *testing.T) {
 	sessionID := []byte("sessionID")
 
@@ -713,19 +787,38 @@ func TestRequireLogin(t *testing.T) {
 			hw.um.AssertExpectations(t)
 		})
 
+		t.Run("repository scoped api key provided", func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.Header.Add(apiKeyHeader, keyB64)
+
+			hw := newHandlersWrapper()
+			hw.um.On("CheckAPIKey", r.Context(), key).
+				Return(&hub.CheckAPIKeyOutput{UserID: "userID", RepositoryID: "repositoryID", Valid: true}, nil)
+			hw.h.RequireLogin(http.HandlerFunc(testsOK)).ServeHTTP(w, r)
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			hw.um.AssertExpectations(t)
+		})
+
 		t.Run("api key based authentication succeeded", func(t *testing.T) {
 			w := httptest.NewRecorder()
 			r, _ := http.NewRequest("GET", "/", nil)
 			r.Header.Add(apiKeyHeader, keyB64)
+			ctx, usage := util.WithRequestUsage(r.Context())
+			r = r.WithContext(ctx)
 
 			hw := newHandlersWrapper()
 			hw.um.On("CheckAPIKey", r.Context(), key).
-				Return(&hub.CheckAPIKeyOutput{UserID: "userID", Valid: true}, nil)
+				Return(&hub.CheckAPIKeyOutput{APIKeyID: "apiKeyID", UserID: "userID", Valid: true}, nil)
 			hw.h.RequireLogin(http.HandlerFunc(testsOK)).ServeHTTP(w, r)
 			resp := w.Result()
 			defer resp.Body.Close()
 
 			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "apiKeyID", usage.APIKeyID())
 			hw.um.AssertExpectations(t)
 		})
 	})
@@ -741,6 +834,150 @@ func TestRequireLogin(t *testing.T) {
 
 		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
 	})
+
+	t.Run("user already authenticated by a previous middleware", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.h.RequireLogin(http.HandlerFunc(testsOK)).ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		hw.um.AssertExpectations(t)
+	})
+}
+
+func TestRequireRepositoryAPIKey(t *testing.T) {
+	key := []byte("key")
+	keyB64 := base64.StdEncoding.EncodeToString(key)
+
+	t.Run("api key not provided", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", nil)
+
+		hw := newHandlersWrapper()
+		hw.h.RequireRepositoryAPIKey(http.HandlerFunc(testsOK)).ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("invalid api key provided", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", nil)
+		r.Header.Add(apiKeyHeader, "invalidB64")
+
+		hw := newHandlersWrapper()
+		hw.h.RequireRepositoryAPIKey(http.HandlerFunc(testsOK)).ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("error checking api key", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", nil)
+		r.Header.Add(apiKeyHeader, keyB64)
+
+		hw := newHandlersWrapper()
+		hw.um.On("CheckAPIKey", r.Context(), key).Return(nil, tests.ErrFakeDatabaseFailure)
+		hw.h.RequireRepositoryAPIKey(http.HandlerFunc(testsOK)).ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.um.AssertExpectations(t)
+	})
+
+	t.Run("api key not scoped to a repository", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", nil)
+		r.Header.Add(apiKeyHeader, keyB64)
+
+		hw := newHandlersWrapper()
+		hw.um.On("CheckAPIKey", r.Context(), key).
+			Return(&hub.CheckAPIKeyOutput{UserID: "userID", Valid: true}, nil)
+		hw.h.RequireRepositoryAPIKey(http.HandlerFunc(testsOK)).ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		hw.um.AssertExpectations(t)
+	})
+
+	t.Run("repository api key authentication succeeded", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", nil)
+		r.Header.Add(apiKeyHeader, keyB64)
+
+		hw := newHandlersWrapper()
+		hw.um.On("CheckAPIKey", r.Context(), key).
+			Return(&hub.CheckAPIKeyOutput{UserID: "userID", RepositoryID: "repositoryID", Valid: true}, nil)
+		var userID, repositoryID string
+		next := func(w http.ResponseWriter, r *http.Request) {
+			userID, _ = r.Context().Value(hub.UserIDKey).(string)
+			repositoryID, _ = r.Context().Value(hub.RepositoryIDKey).(string)
+		}
+		hw.h.RequireRepositoryAPIKey(http.HandlerFunc(next)).ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "userID", userID)
+		assert.Equal(t, "repositoryID", repositoryID)
+		hw.um.AssertExpectations(t)
+	})
+}
+
+func TestRequirePublisher(t *testing.T) {
+	t.Run("error getting profile", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.um.On("GetProfileJSON", r.Context()).Return(nil, tests.ErrFakeDatabaseFailure)
+		hw.h.RequirePublisher(http.HandlerFunc(testsOK)).ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.um.AssertExpectations(t)
+	})
+
+	testCases := []struct {
+		email              string
+		expectedStatusCode int
+	}{
+		{"user1@email.com", http.StatusForbidden},
+		{"publisher@email.com", http.StatusOK},
+		{"Publisher@Email.com", http.StatusOK},
+		{"user1@example.com", http.StatusOK},
+		{"user1@sub.example.com", http.StatusForbidden},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.email, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest("POST", "/", nil)
+			r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+			hw := newHandlersWrapper()
+			hw.cfg.Set("server.internalCatalog.publishers", []string{"publisher@email.com", "@example.com"})
+			hw.um.On("GetProfileJSON", r.Context()).Return([]byte(`{"email": "`+tc.email+`"}`), nil)
+			hw.h.RequirePublisher(http.HandlerFunc(testsOK)).ServeHTTP(w, r)
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+			hw.um.AssertExpectations(t)
+		})
+	}
 }
 
 func TestUpdatePassword(t *testing.T) {
@@ -10,9 +10,9 @@ import (
 	"github.com/artifacthub/hub/cmd/hub/handlers/helpers"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/notification"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/go-chi/chi"
 	"github.com/rs/zerolog"
-	"github.com/rs/zerolog/log"
 )
 
 // Handlers represents a group of http handlers in charge of handling webhooks
@@ -26,7 +26,7 @@ type Handlers struct {
 func NewHandlers(webhookManager hub.WebhookManager) *Handlers {
 	return &Handlers{
 		webhookManager: webhookManager,
-		logger:         log.With().Str("handlers", "webhook").Logger(),
+		logger:         util.LogWith("handlers").Str("handlers", "webhook").Logger(),
 	}
 }
 
@@ -36,12 +36,12 @@ func (h *Handlers) Add(w http.ResponseWriter, r *http.Request) {
 	wh := &hub.Webhook{}
 	if err := json.NewDecoder(r.Body).Decode(&wh); err != nil {
 		h.logger.Error().Err(err).Str("method", "Add").Msg(hub.ErrInvalidInput.Error())
-		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
 		return
 	}
 	if err := h.webhookManager.Add(r.Context(), orgName, wh); err != nil {
 		h.logger.Error().Err(err).Str("method", "Add").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusCreated)
@@ -52,7 +52,7 @@ func (h *Handlers) Delete(w http.ResponseWriter, r *http.Request) {
 	webhookID := chi.URLParam(r, "webhookID")
 	if err := h.webhookManager.Delete(r.Context(), webhookID); err != nil {
 		h.logger.Error().Err(err).Str("method", "Delete").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -64,7 +64,7 @@ func (h *Handlers) Get(w http.ResponseWriter, r *http.Request) {
 	dataJSON, err := h.webhookManager.GetJSON(r.Context(), webhookID)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "Get").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
@@ -78,7 +78,7 @@ func (h *Handlers) GetOwnedByOrg(w http.ResponseWriter, r *http.Request) {
 	dataJSON, err := h.webhookManager.GetOwnedByOrgJSON(r.Context(), orgName)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "GetOwnedByOrg").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
@@ -90,7 +90,7 @@ func (h *Handlers) GetOwnedByUser(w http.ResponseWriter, r *http.Request) {
 	dataJSON, err := h.webhookManager.GetOwnedByUserJSON(r.Context())
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "GetOwnedByUser").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
@@ -102,7 +102,7 @@ func (h *Handlers) TriggerTest(w http.ResponseWriter, r *http.Request) {
 	// Read webhook from request body
 	wh := &hub.Webhook{}
 	if err := json.NewDecoder(r.Body).Decode(&wh); err != nil {
-		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
 		return
 	}
 
@@ -155,13 +155,13 @@ func (h *Handlers) Update(w http.ResponseWriter, r *http.Request) {
 	wh := &hub.Webhook{}
 	if err := json.NewDecoder(r.Body).Decode(&wh); err != nil {
 		h.logger.Error().Err(err).Str("method", "Update").Msg(hub.ErrInvalidInput.Error())
-		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		helpers.RenderErrorJSON(w, r, hub.ErrInvalidInput)
 		return
 	}
 	wh.WebhookID = chi.URLParam(r, "webhookID")
 	if err := h.webhookManager.Update(r.Context(), wh); err != nil {
 		h.logger.Error().Err(err).Str("method", "Update").Send()
-		helpers.RenderErrorJSON(w, err)
+		helpers.RenderErrorJSON(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -10,21 +10,32 @@ import (
 	"time"
 
 	"github.com/artifacthub/hub/cmd/hub/handlers"
+	"github.com/artifacthub/hub/cmd/hub/handlers/admin"
+	"github.com/artifacthub/hub/internal/adoption"
 	"github.com/artifacthub/hub/internal/apikey"
+	"github.com/artifacthub/hub/internal/domain"
 	"github.com/artifacthub/hub/internal/email"
 	"github.com/artifacthub/hub/internal/event"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/img/pg"
+	"github.com/artifacthub/hub/internal/inventory"
+	"github.com/artifacthub/hub/internal/ipfilter"
+	"github.com/artifacthub/hub/internal/mirror"
 	"github.com/artifacthub/hub/internal/notification"
 	"github.com/artifacthub/hub/internal/org"
 	"github.com/artifacthub/hub/internal/pkg"
 	"github.com/artifacthub/hub/internal/repo"
+	"github.com/artifacthub/hub/internal/retention"
+	"github.com/artifacthub/hub/internal/secrets"
+	"github.com/artifacthub/hub/internal/statement"
 	"github.com/artifacthub/hub/internal/subscription"
+	"github.com/artifacthub/hub/internal/suppression"
 	"github.com/artifacthub/hub/internal/user"
 	"github.com/artifacthub/hub/internal/util"
 	"github.com/artifacthub/hub/internal/webhook"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func main() {
@@ -43,21 +54,85 @@ func main() {
 	if err != nil {
 		log.Fatal().Err(err).Msg("database setup failed")
 	}
+
+	sc, err := secrets.SetupCipher(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("secrets cipher setup failed")
+	}
 	var es hub.EmailSender
 	if s := email.NewSender(cfg); s != nil {
-		es = s
+		es = suppression.NewSender(s, suppression.NewManager(db))
+	}
+	var uOpts []func(m *user.Manager)
+	if cost := cfg.GetInt("server.passwords.bcryptCost"); cost != 0 {
+		if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+			log.Fatal().Int("cost", cost).Msg("invalid passwords bcrypt cost")
+		}
+		uOpts = append(uOpts, user.WithBcryptCost(cost))
+	}
+
+	// Run the command requested, if any, instead of launching the server
+	if len(os.Args) > 1 {
+		if err := runCommand(db, sc, user.NewManager(db, es, uOpts...), pkg.NewManager(db), os.Args[1:]); err != nil {
+			log.Fatal().Err(err).Str("command", os.Args[1]).Msg("command failed")
+		}
+		return
+	}
+
+	limits, err := util.SetupLimits(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("limits setup failed")
+	}
+	imgLimits, err := util.SetupImageLimits(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("image limits setup failed")
 	}
 
-	// Setup and launch http server
+	whOpts := []func(m *webhook.Manager){webhook.WithLimits(limits)}
+	var nOpts []func(m *notification.Manager)
+	var oOpts []func(m *org.Manager)
+	if sc != nil {
+		whOpts = append(whOpts, webhook.WithSecretsCipher(sc))
+		nOpts = append(nOpts, notification.WithSecretsCipher(sc))
+		oOpts = append(oOpts, org.WithSecretsCipher(sc))
+	}
+
+	var aOpts []func(m *adoption.Manager)
+	if emails := cfg.GetStringSlice("server.admin.emails"); len(emails) > 0 {
+		aOpts = append(aOpts, adoption.WithAdminsEmails(emails))
+	}
+
+	rOpts := []func(m *repo.Manager){repo.WithLimits(limits)}
+	if sc != nil {
+		rOpts = append(rOpts, repo.WithSecretsCipher(sc))
+	}
+	if paths := cfg.GetStringSlice("server.localRepositories.allowedPaths"); len(paths) > 0 {
+		rOpts = append(rOpts, repo.WithAllowedLocalPaths(paths))
+	}
+
+	// Setup and launch http server (the handlers database queries are timed to
+	// collect some metrics about them)
+	hdb := util.NewTimedDB(db)
+	apiKeyUsageTracker := apikey.NewUsageTracker(db)
 	hSvc := &handlers.Services{
-		OrganizationManager: org.NewManager(db, es),
-		UserManager:         user.NewManager(db, es),
-		RepositoryManager:   repo.NewManager(db),
-		PackageManager:      pkg.NewManager(db),
-		SubscriptionManager: subscription.NewManager(db),
-		WebhookManager:      webhook.NewManager(db),
-		APIKeyManager:       apikey.NewManager(db),
-		ImageStore:          pg.NewImageStore(db),
+		OrganizationManager: org.NewManager(hdb, es, oOpts...),
+		UserManager:         user.NewManager(hdb, es, uOpts...),
+		RepositoryManager:   repo.NewManager(hdb, rOpts...),
+		PackageManager:      pkg.NewManager(hdb, pkg.WithLimits(limits)),
+		SubscriptionManager: subscription.NewManager(hdb),
+		WebhookManager:      webhook.NewManager(hdb, whOpts...),
+		APIKeyManager:       apikey.NewManager(hdb),
+		DomainManager:       domain.NewManager(hdb),
+		StatementManager:    statement.NewManager(hdb),
+		AdoptionManager:     adoption.NewManager(hdb, es, aOpts...),
+		SuppressionManager:  suppression.NewManager(hdb),
+		ImageStore:          pg.NewImageStore(hdb, pg.WithLimits(imgLimits)),
+		APIKeyUsageTracker:  apiKeyUsageTracker,
+		InventoryManager:    inventory.NewManager(hdb),
+	}
+	h, err := handlers.Setup(cfg, hSvc)
+	if err != nil {
+		log.Fatal().Err(err).Msg("handlers setup failed")
 	}
 	addr := cfg.GetString("server.addr")
 	srv := &http.Server{
@@ -65,7 +140,7 @@ func main() {
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 30 * time.Second,
 		IdleTimeout:  1 * time.Minute,
-		Handler:      handlers.Setup(cfg, hSvc).Router,
+		Handler:      h.Router,
 	}
 	go func() {
 		if err := srv.ListenAndServe(); err != http.ErrServerClosed {
@@ -83,6 +158,45 @@ func main() {
 		}
 	}()
 
+	// Setup and launch admin server, if enabled
+	var adminSrv *http.Server
+	if adminAddr := cfg.GetString("server.admin.addr"); adminAddr != "" {
+		a, err := admin.NewHandlers(
+			cfg.GetString("server.admin.username"),
+			cfg.GetString("server.admin.password"),
+			admin.WithAdoptionManager(hSvc.AdoptionManager),
+			admin.WithRepositoryManager(hSvc.RepositoryManager),
+		)
+		if err != nil {
+			log.Fatal().Err(err).Msg("admin handlers setup failed")
+		}
+		var adminHandler http.Handler = a.Router
+		ipFilter, err := ipfilter.New(
+			cfg.GetStringSlice("server.ipFilter.admin.allow"),
+			cfg.GetStringSlice("server.ipFilter.admin.deny"),
+			cfg.GetInt("server.ipFilter.admin.trustedProxies"),
+		)
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid admin ip filter")
+		}
+		if ipFilter != nil {
+			adminHandler = ipFilter.Handler(adminHandler)
+		}
+		adminSrv = &http.Server{
+			Addr:        adminAddr,
+			ReadTimeout: 5 * time.Second,
+			// CPU profiles and traces may take a while to be collected
+			WriteTimeout: 5 * time.Minute,
+			IdleTimeout:  1 * time.Minute,
+			Handler:      adminHandler,
+		}
+		go func() {
+			if err := adminSrv.ListenAndServe(); err != http.ErrServerClosed {
+				log.Fatal().Err(err).Msg("admin server ListenAndServe failed")
+			}
+		}()
+	}
+
 	// Setup and launch events dispatcher
 	ctx, stop := context.WithCancel(context.Background())
 	var wg sync.WaitGroup
@@ -90,8 +204,8 @@ func main() {
 		DB:                  db,
 		EventManager:        event.NewManager(),
 		SubscriptionManager: subscription.NewManager(db),
-		WebhookManager:      webhook.NewManager(db),
-		NotificationManager: notification.NewManager(),
+		WebhookManager:      webhook.NewManager(db, whOpts...),
+		NotificationManager: notification.NewManager(nOpts...),
 	}
 	eventsDispatcher := event.NewDispatcher(eSvc)
 	wg.Add(1)
@@ -101,7 +215,7 @@ func main() {
 	nSvc := &notification.Services{
 		DB:                  db,
 		ES:                  es,
-		NotificationManager: notification.NewManager(),
+		NotificationManager: notification.NewManager(nOpts...),
 		SubscriptionManager: subscription.NewManager(db),
 		PackageManager:      pkg.NewManager(db),
 	}
@@ -109,11 +223,54 @@ func main() {
 	wg.Add(1)
 	go notificationsDispatcher.Run(ctx, &wg)
 
+	// Setup and launch organizations domains checker
+	domainsChecker := domain.NewChecker(cfg, domain.NewManager(db))
+	wg.Add(1)
+	go domainsChecker.Run(ctx, &wg)
+
+	// Setup and launch data retention pruner
+	pruner, err := retention.NewPruner(cfg, db)
+	if err != nil {
+		log.Fatal().Err(err).Msg("data retention pruner setup failed")
+	}
+	wg.Add(1)
+	go pruner.Run(ctx, &wg)
+
+	// Setup and launch mirror syncer
+	syncer, err := mirror.NewSyncer(cfg, db, pkg.NewManager(db), &http.Client{Timeout: 10 * time.Second})
+	if err != nil {
+		log.Fatal().Err(err).Msg("mirror syncer setup failed")
+	}
+	wg.Add(1)
+	go syncer.Run(ctx, &wg)
+
+	// Setup and launch cluster inventories reporter
+	inventoriesReporter := inventory.NewReporter(
+		cfg,
+		db,
+		inventory.NewManager(db),
+		pkg.NewManager(db),
+		es,
+		&http.Client{Timeout: 10 * time.Second},
+	)
+	wg.Add(1)
+	go inventoriesReporter.Run(ctx, &wg)
+
+	// Setup and launch subscriptions digester
+	subscriptionsDigester := subscription.NewDigester(cfg, db, subscription.NewManager(db), es)
+	wg.Add(1)
+	go subscriptionsDigester.Run(ctx, &wg)
+
+	// Launch api keys usage tracker
+	wg.Add(1)
+	go apiKeyUsageTracker.Run(ctx, &wg)
+
 	// Shutdown server gracefully when SIGINT or SIGTERM signal is received
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
 	<-shutdown
 	log.Info().Msg("hub server shutting down..")
+	h.Close()
 	stop()
 	wg.Wait()
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.GetDuration("server.shutdownTimeout"))
@@ -121,5 +278,10 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatal().Err(err).Msg("hub server shutdown failed")
 	}
+	if adminSrv != nil {
+		if err := adminSrv.Shutdown(ctx); err != nil {
+			log.Fatal().Err(err).Msg("admin server shutdown failed")
+		}
+	}
 	log.Info().Msg("hub server stopped")
 }
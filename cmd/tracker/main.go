@@ -3,14 +3,19 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"sync"
 	"syscall"
+	"time"
 
+	"github.com/artifacthub/hub/cmd/hub/handlers/admin"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/ipfilter"
 	"github.com/artifacthub/hub/internal/pkg"
 	"github.com/artifacthub/hub/internal/repo"
+	"github.com/artifacthub/hub/internal/secrets"
 	"github.com/artifacthub/hub/internal/tracker"
 	"github.com/artifacthub/hub/internal/tracker/falco"
 	"github.com/artifacthub/hub/internal/tracker/helm"
@@ -32,6 +37,35 @@ func main() {
 		log.Fatal().Err(err).Msg("logger setup failed")
 	}
 
+	// Setup and launch admin server, if enabled, so that the tracker can be
+	// profiled while it runs
+	if adminAddr := cfg.GetString("tracker.admin.addr"); adminAddr != "" {
+		a, err := admin.NewHandlers(
+			cfg.GetString("tracker.admin.username"),
+			cfg.GetString("tracker.admin.password"),
+		)
+		if err != nil {
+			log.Fatal().Err(err).Msg("admin handlers setup failed")
+		}
+		var adminHandler http.Handler = a.Router
+		ipFilter, err := ipfilter.New(
+			cfg.GetStringSlice("tracker.ipFilter.admin.allow"),
+			cfg.GetStringSlice("tracker.ipFilter.admin.deny"),
+			cfg.GetInt("tracker.ipFilter.admin.trustedProxies"),
+		)
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid admin ip filter")
+		}
+		if ipFilter != nil {
+			adminHandler = ipFilter.Handler(adminHandler)
+		}
+		go func() {
+			if err := http.ListenAndServe(adminAddr, adminHandler); err != nil {
+				log.Fatal().Err(err).Msg("admin server ListenAndServe failed")
+			}
+		}()
+	}
+
 	// Shutdown gracefully when SIGINT or SIGTERM signal is received
 	log.Info().Int("pid", os.Getpid()).Msg("tracker started")
 	ctx, cancel := context.WithCancel(context.Background())
@@ -48,8 +82,23 @@ func main() {
 	if err != nil {
 		log.Fatal().Err(err).Msg("database setup failed")
 	}
-	rm := repo.NewManager(db)
-	pm := pkg.NewManager(db)
+	sc, err := secrets.SetupCipher(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("secrets cipher setup failed")
+	}
+	var rOpts []func(m *repo.Manager)
+	if sc != nil {
+		rOpts = append(rOpts, repo.WithSecretsCipher(sc))
+	}
+	rm := repo.NewManager(db, rOpts...)
+	var pmOpts []func(m *pkg.Manager)
+	if cfg.GetBool("tracker.dualWrite") {
+		pmOpts = append(pmOpts, pkg.WithDualWrite())
+	}
+	if aliases := cfg.GetStringMapString("tracker.keywordsAliases"); len(aliases) > 0 {
+		pmOpts = append(pmOpts, pkg.WithKeywordsAliases(aliases))
+	}
+	pm := pkg.NewManager(db, pmOpts...)
 	is, err := util.SetupImageStore(cfg, db)
 	if err != nil {
 		log.Fatal().Err(err).Msg("image store setup failed")
@@ -58,6 +107,10 @@ func main() {
 	if err != nil {
 		log.Fatal().Err(err).Msg("error getting repositories")
 	}
+	rl, err := tracker.NewRateLimiter(cfg.GetStringMapString("tracker.rateLimits"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("rate limiter setup failed")
+	}
 	ec := tracker.NewDBErrorsCollector(ctx, rm, repos)
 	svc := &tracker.Services{
 		Ctx: ctx,
@@ -66,14 +119,41 @@ func main() {
 		Pm:  pm,
 		Is:  is,
 		Ec:  ec,
+		Hl:  tracker.NewHostsLimiter(cfg.GetInt("tracker.maxRequestsPerHost")),
+		Rl:  rl,
+		Sc:  sc,
 	}
 
+	// Set up the requests sent to load the Helm repositories index files and to
+	// clone the git based repositories like the rest of requests sent by the
+	// trackers (User-Agent, GitHub tokens and repositories credentials)
+	setupRequest := func(req *http.Request, r *hub.Repository) {
+		tracker.SetupRequest(req, cfg, r)
+	}
+	svc.Il = repo.NewHelmIndexLoader(repo.WithIndexLoaderRequestSetup(setupRequest))
+	clonerOpts := []func(c *repo.Cloner){repo.WithClonerRequestSetup(setupRequest)}
+
+	// Keep shallow clones of the git based repositories between runs, if a
+	// git cache path has been configured
+	if dir := cfg.GetString("tracker.gitCache.path"); dir != "" {
+		clonerOpts = append(clonerOpts, repo.WithGitCache(repo.NewGitCache(dir)))
+	}
+	svc.Rc = repo.NewCloner(clonerOpts...)
+
+	// Fetch packages logos asynchronously, out of the registration path
+	lf := tracker.NewLogosFetcher(svc, cfg.GetInt("tracker.logosWorkers"))
+	svc.Lq = lf
+
+	// Validate packages using the organizations validation webhooks, if any
+	svc.Pv = tracker.NewWebhookPackageValidator(svc, db, &http.Client{Timeout: 10 * time.Second})
+
 	// Track registered repositories
 	limiter := make(chan struct{}, cfg.GetInt("tracker.concurrency"))
 	var wg sync.WaitGroup
 	for _, r := range repos {
 		limiter <- struct{}{}
 		wg.Add(1)
+		r.ProxyURL = tracker.ProxyURL(cfg, r)
 		var t tracker.Tracker
 		switch r.Kind {
 		case hub.Falco:
@@ -95,6 +175,7 @@ func main() {
 		}(r)
 	}
 	wg.Wait()
+	lf.Wait()
 	ec.Flush()
 	log.Info().Msg("tracker finished")
 }
@@ -108,12 +189,23 @@ func main() {
 //   kinds will be returned.
 // - Otherwise, all the repositories will be returned.
 //
+// Disabled repositories will always be skipped. Unless they have been
+// explicitly requested by name, repositories which are not due to be tracked
+// yet, due to their tracking interval or quiet hours, will be skipped as well.
+// When the tracker runs in requested only mode, only the repositories with a
+// pending tracking request will be returned. On the other hand, when the
+// tracking requests are processed by a dedicated tracker (skip requested), the
+// repositories with a pending request will be skipped, so that they are not
+// processed by both trackers at the same time.
+//
 func getRepositories(
 	cfg *viper.Viper,
 	rm hub.RepositoryManager,
 ) ([]*hub.Repository, error) {
 	reposNames := cfg.GetStringSlice("tracker.repositoriesNames")
 	reposKinds := cfg.GetStringSlice("tracker.repositoriesKinds")
+	requestedOnly := cfg.GetBool("tracker.requestedOnly")
+	skipRequested := cfg.GetBool("tracker.skipRequested")
 
 	var repos []*hub.Repository
 	if len(reposNames) > 0 {
@@ -143,5 +235,28 @@ func getRepositories(
 			return nil, fmt.Errorf("error getting all repositories: %w", err)
 		}
 	}
-	return repos, nil
+
+	// Skip disabled repositories and the ones not due to be tracked yet
+	now := time.Now()
+	reposToTrack := make([]*hub.Repository, 0, len(repos))
+	for _, r := range repos {
+		if r.Disabled {
+			log.Debug().Str("repo", r.Name).Msg("repository disabled, skipping")
+			continue
+		}
+		if len(reposNames) == 0 && !tracker.IsDue(r, now) {
+			log.Debug().Str("repo", r.Name).Msg("repository not due to be tracked yet, skipping")
+			continue
+		}
+		if requestedOnly && !tracker.IsRequested(r) {
+			log.Debug().Str("repo", r.Name).Msg("repository tracking not requested, skipping")
+			continue
+		}
+		if skipRequested && len(reposNames) == 0 && tracker.IsRequested(r) {
+			log.Debug().Str("repo", r.Name).Msg("repository tracking requested, skipping")
+			continue
+		}
+		reposToTrack = append(reposToTrack, r)
+	}
+	return reposToTrack, nil
 }
@@ -1,58 +1,127 @@
 {{ template "api_keys/add_api_key.sql" }}
 {{ template "api_keys/delete_api_key.sql" }}
 {{ template "api_keys/get_api_key.sql" }}
+{{ template "api_keys/get_organization_api_usage.sql" }}
 {{ template "api_keys/get_user_api_keys.sql" }}
+{{ template "api_keys/register_api_keys_usage.sql" }}
 {{ template "api_keys/update_api_key.sql" }}
 
+{{ template "domains/add_organization_domain.sql" }}
+{{ template "domains/delete_organization_domain.sql" }}
+{{ template "domains/get_all_organizations_domains.sql" }}
+{{ template "domains/get_organization_domain.sql" }}
+{{ template "domains/get_organization_domains.sql" }}
+{{ template "domains/join_organizations_by_email_domain.sql" }}
+{{ template "domains/update_organization_domain_verification.sql" }}
+
+{{ template "emails/is_email_suppressed.sql" }}
+{{ template "emails/suppress_email.sql" }}
+
 {{ template "events/get_pending_event.sql" }}
 
 {{ template "images/get_image.sql" }}
 {{ template "images/register_image.sql" }}
 
+{{ template "inventories/add_cluster_inventory.sql" }}
+{{ template "inventories/delete_cluster_inventory.sql" }}
+{{ template "inventories/get_pending_cluster_inventory_report.sql" }}
+{{ template "inventories/get_user_cluster_inventories.sql" }}
+{{ template "inventories/update_cluster_inventory.sql" }}
+{{ template "inventories/update_cluster_inventory_last_report.sql" }}
+
+{{ template "mirror/register_mirror_repository.sql" }}
+
 {{ template "notifications/add_notification.sql" }}
 {{ template "notifications/get_pending_notification.sql" }}
 {{ template "notifications/update_notification_status.sql" }}
 
 {{ template "organizations/add_organization_member.sql" }}
 {{ template "organizations/add_organization.sql" }}
+{{ template "organizations/add_organization_share.sql" }}
 {{ template "organizations/confirm_organization_membership.sql" }}
 {{ template "organizations/delete_organization_member.sql" }}
+{{ template "organizations/delete_organization_share.sql" }}
 {{ template "organizations/get_organization.sql" }}
+{{ template "organizations/get_organization_member_deletion_impact.sql" }}
 {{ template "organizations/get_organization_members.sql" }}
+{{ template "organizations/get_organization_shares.sql" }}
+{{ template "organizations/get_organization_validation_webhook.sql" }}
 {{ template "organizations/get_user_organizations.sql" }}
 {{ template "organizations/update_organization.sql" }}
+{{ template "organizations/update_organization_featured_packages.sql" }}
+{{ template "organizations/update_organization_validation_webhook.sql" }}
 {{ template "organizations/user_belongs_to_organization.sql" }}
 
+{{ template "packages/add_package_adoption_request.sql" }}
+{{ template "packages/add_package_statement.sql" }}
+{{ template "packages/delete_package_statement.sql" }}
 {{ template "packages/generate_package_tsdoc.sql" }}
+{{ template "packages/get_all_package_adoption_requests.sql" }}
+{{ template "packages/get_all_packages.sql" }}
 {{ template "packages/get_package.sql" }}
+{{ template "packages/get_package_adoption_requests.sql" }}
+{{ template "packages/get_package_changes.sql" }}
+{{ template "packages/get_package_dependencies_graph.sql" }}
+{{ template "packages/get_package_latest_versions.sql" }}
+{{ template "packages/get_package_project.sql" }}
 {{ template "packages/get_package_summary.sql" }}
+{{ template "packages/get_package_version_docs.sql" }}
+{{ template "packages/get_packages_by_owner.sql" }}
 {{ template "packages/get_packages_starred_by_user.sql" }}
 {{ template "packages/get_package_stars.sql" }}
+{{ template "packages/get_package_statements.sql" }}
 {{ template "packages/get_packages_stats.sql" }}
+{{ template "packages/get_packages_tags.sql" }}
 {{ template "packages/get_random_packages.sql" }}
+{{ template "packages/kube_version_compatible.sql" }}
+{{ template "packages/match_installed_packages.sql" }}
+{{ template "packages/package_version_is_eol.sql" }}
+{{ template "packages/push_package.sql" }}
 {{ template "packages/register_package.sql" }}
+{{ template "packages/resolve_packages.sql" }}
+{{ template "packages/restore_unregistered_packages.sql" }}
 {{ template "packages/search_packages.sql" }}
 {{ template "packages/semver_gt.sql" }}
 {{ template "packages/semver_gte.sql" }}
+{{ template "packages/semver_satisfies.sql" }}
 {{ template "packages/toggle_star.sql" }}
 {{ template "packages/unregister_package.sql" }}
+{{ template "packages/update_package_logo_image.sql" }}
+{{ template "packages/update_package_seeking_maintainers.sql" }}
 
+{{ template "repositories/add_repository_collaborator.sql" }}
 {{ template "repositories/add_repository.sql" }}
+{{ template "repositories/count_org_repositories.sql" }}
+{{ template "repositories/delete_repository_collaborator.sql" }}
 {{ template "repositories/delete_repository.sql" }}
 {{ template "repositories/get_all_repositories.sql" }}
 {{ template "repositories/get_repositories_by_kind.sql" }}
+{{ template "repositories/get_repositories_by_metadata.sql" }}
 {{ template "repositories/get_repository_by_name.sql" }}
+{{ template "repositories/get_repository_collaborators.sql" }}
+{{ template "repositories/get_repository_deletion_impact.sql" }}
+{{ template "repositories/get_repository_health.sql" }}
 {{ template "repositories/get_repository_packages_digest.sql" }}
 {{ template "repositories/get_org_repositories.sql" }}
 {{ template "repositories/get_user_repositories.sql" }}
+{{ template "repositories/regenerate_repository_tracking_secret.sql" }}
+{{ template "repositories/request_repository_tracking.sql" }}
 {{ template "repositories/transfer_repository.sql" }}
 {{ template "repositories/update_repository.sql" }}
+{{ template "repositories/user_has_repository_read_access.sql" }}
+{{ template "repositories/user_has_repository_write_access.sql" }}
+{{ template "repositories/user_owns_repository.sql" }}
+
+{{ template "retention/prune_data.sql" }}
 
 {{ template "subscriptions/add_subscription.sql" }}
 {{ template "subscriptions/delete_subscription.sql" }}
 {{ template "subscriptions/get_package_subscriptions.sql" }}
+{{ template "subscriptions/get_pending_subscriptions_digest.sql" }}
 {{ template "subscriptions/get_subscriptors.sql" }}
 {{ template "subscriptions/get_user_subscriptions.sql" }}
+{{ template "subscriptions/get_user_subscriptions_changes.sql" }}
+{{ template "subscriptions/update_subscriptions_last_digest.sql" }}
 
 {{ template "users/get_user_profile.sql" }}
 {{ template "users/register_session.sql" }}
@@ -62,6 +131,7 @@
 {{ template "users/verify_email.sql" }}
 
 {{ template "webhooks/add_webhook.sql" }}
+{{ template "webhooks/count_user_webhooks.sql" }}
 {{ template "webhooks/delete_webhook.sql" }}
 {{ template "webhooks/get_webhook.sql" }}
 {{ template "webhooks/get_org_webhooks.sql" }}
@@ -1,12 +1,34 @@
--- add_api_key adds the provided api key to the database.
+-- add_api_key adds the provided api key to the database. When the api key is
+-- scoped to a repository, the user must have write access to it.
 create or replace function add_api_key(p_api_key jsonb)
 returns bytea as $$
+declare
+    v_user_id uuid := (p_api_key->>'user_id')::uuid;
+    v_repository_id uuid := nullif(p_api_key->>'repository_id', '')::uuid;
+    v_repository_name text;
+    v_key bytea;
+begin
+    if v_repository_id is not null then
+        select name into v_repository_name
+        from repository
+        where repository_id = v_repository_id;
+
+        if not user_has_repository_write_access(v_user_id, v_repository_name) then
+            raise insufficient_privilege;
+        end if;
+    end if;
+
     insert into api_key (
         name,
-        user_id
+        user_id,
+        repository_id
     ) values (
         p_api_key->>'name',
-        (p_api_key->>'user_id')::uuid
+        v_user_id,
+        v_repository_id
     )
-    returning key;
-$$ language sql;
+    returning key into v_key;
+
+    return v_key;
+end
+$$ language plpgsql;
@@ -2,11 +2,14 @@
 create or replace function get_api_key(p_user_id uuid, p_api_key_id uuid)
 returns setof json as $$
     select json_build_object(
-        'api_key_id', api_key_id,
-        'name', name,
-        'created_at', floor(extract(epoch from created_at))
+        'api_key_id', ak.api_key_id,
+        'name', ak.name,
+        'created_at', floor(extract(epoch from ak.created_at)),
+        'repository_id', ak.repository_id,
+        'repository_name', r.name
     )
-    from api_key
-    where api_key_id = p_api_key_id
-    and user_id = p_user_id
+    from api_key ak
+    left join repository r using (repository_id)
+    where ak.api_key_id = p_api_key_id
+    and ak.user_id = p_user_id
 $$ language sql;
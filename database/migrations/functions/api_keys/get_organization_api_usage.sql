@@ -0,0 +1,81 @@
+-- get_organization_api_usage returns a summary of the usage of the api keys
+-- owned by the members of the organization provided during the last days
+-- given as a json object. The user requesting it must belong to the
+-- organization.
+create or replace function get_organization_api_usage(
+    p_requesting_user_id uuid,
+    p_org_name text,
+    p_days int
+)
+returns setof json as $$
+begin
+    if not user_belongs_to_organization(p_requesting_user_id, p_org_name) then
+        raise insufficient_privilege;
+    end if;
+
+    return query
+    with org_usage as (
+        select aku.*, ak.name as api_key_name, u.alias as user_alias
+        from api_key_usage aku
+        join api_key ak using (api_key_id)
+        join "user" u using (user_id)
+        join user__organization uo using (user_id)
+        join organization o using (organization_id)
+        where o.name = p_org_name
+        and uo.confirmed = true
+        and aku.day > current_date - p_days
+    )
+    select json_build_object(
+        'requests_per_day', (
+            select coalesce(json_agg(json_build_object(
+                'day', day,
+                'requests', requests,
+                'rate_limited_requests', rate_limited_requests
+            )), '[]')
+            from (
+                select
+                    day,
+                    sum(requests) as requests,
+                    sum(rate_limited_requests) as rate_limited_requests
+                from org_usage
+                group by day
+                order by day asc
+            ) d
+        ),
+        'top_endpoints', (
+            select coalesce(json_agg(json_build_object(
+                'endpoint', endpoint,
+                'requests', requests
+            )), '[]')
+            from (
+                select endpoint, sum(requests) as requests
+                from org_usage
+                group by endpoint
+                having sum(requests) > 0
+                order by requests desc, endpoint asc
+                limit 10
+            ) e
+        ),
+        'api_keys', (
+            select coalesce(json_agg(json_build_object(
+                'api_key_id', api_key_id,
+                'name', api_key_name,
+                'user_alias', user_alias,
+                'requests', requests,
+                'rate_limited_requests', rate_limited_requests
+            )), '[]')
+            from (
+                select
+                    api_key_id,
+                    api_key_name,
+                    user_alias,
+                    sum(requests) as requests,
+                    sum(rate_limited_requests) as rate_limited_requests
+                from org_usage
+                group by api_key_id, api_key_name, user_alias
+                order by requests desc, api_key_name asc
+            ) k
+        )
+    );
+end
+$$ language plpgsql;
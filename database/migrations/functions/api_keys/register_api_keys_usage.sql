@@ -0,0 +1,20 @@
+-- register_api_keys_usage adds the usage provided to the api keys usage
+-- collected so far. Usage entries of keys that no longer exist are ignored.
+create or replace function register_api_keys_usage(p_usage jsonb)
+returns void as $$
+    insert into api_key_usage (api_key_id, day, endpoint, requests, rate_limited_requests)
+    select
+        (u->>'api_key_id')::uuid,
+        (u->>'day')::date,
+        u->>'endpoint',
+        coalesce((u->>'requests')::int, 0),
+        coalesce((u->>'rate_limited_requests')::int, 0)
+    from jsonb_array_elements(p_usage) as u
+    where exists (
+        select 1 from api_key where api_key_id = (u->>'api_key_id')::uuid
+    )
+    on conflict (api_key_id, day, endpoint) do update
+    set
+        requests = api_key_usage.requests + excluded.requests,
+        rate_limited_requests = api_key_usage.rate_limited_requests + excluded.rate_limited_requests;
+$$ language sql;
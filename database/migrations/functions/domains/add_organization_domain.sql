@@ -0,0 +1,20 @@
+-- add_organization_domain adds the provided email domain to the organization
+-- given. The domain will need to be verified before it is considered owned by
+-- the organization.
+create or replace function add_organization_domain(
+    p_requesting_user_id uuid,
+    p_org_name text,
+    p_domain jsonb
+) returns void as $$
+begin
+    if not user_belongs_to_organization(p_requesting_user_id, p_org_name) then
+        raise insufficient_privilege;
+    end if;
+
+    insert into organization_domain (organization_id, domain, auto_join)
+    select organization_id, lower(p_domain->>'domain'), coalesce((p_domain->>'auto_join')::boolean, false)
+    from organization
+    where name = p_org_name
+    on conflict (organization_id, domain) do update set auto_join = excluded.auto_join;
+end
+$$ language plpgsql;
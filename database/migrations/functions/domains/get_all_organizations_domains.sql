@@ -0,0 +1,13 @@
+-- get_all_organizations_domains returns all the email domains registered by
+-- the organizations as a json array.
+create or replace function get_all_organizations_domains()
+returns setof json as $$
+    select coalesce(json_agg(json_build_object(
+        'organization_domain_id', organization_domain_id,
+        'domain', domain,
+        'verification_token', verification_token,
+        'verified', verified,
+        'auto_join', auto_join
+    )), '[]')
+    from organization_domain;
+$$ language sql;
@@ -0,0 +1,26 @@
+-- get_organization_domain returns the provided email domain of the
+-- organization given as a json object.
+create or replace function get_organization_domain(
+    p_requesting_user_id uuid,
+    p_org_name text,
+    p_domain text
+) returns setof json as $$
+begin
+    if not user_belongs_to_organization(p_requesting_user_id, p_org_name) then
+        raise insufficient_privilege;
+    end if;
+
+    return query
+    select json_build_object(
+        'organization_domain_id', od.organization_domain_id,
+        'domain', od.domain,
+        'verification_token', od.verification_token,
+        'verified', od.verified,
+        'auto_join', od.auto_join
+    )
+    from organization_domain od
+    join organization o using (organization_id)
+    where o.name = p_org_name
+    and od.domain = lower(p_domain);
+end
+$$ language plpgsql;
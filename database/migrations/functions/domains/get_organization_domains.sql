@@ -0,0 +1,26 @@
+-- get_organization_domains returns the email domains of the organization
+-- provided as a json array.
+create or replace function get_organization_domains(p_requesting_user_id uuid, p_org_name text)
+returns setof json as $$
+begin
+    if not user_belongs_to_organization(p_requesting_user_id, p_org_name) then
+        raise insufficient_privilege;
+    end if;
+
+    return query
+    select json_agg(json_build_object(
+        'domain', d.domain,
+        'verification_token', d.verification_token,
+        'verified', d.verified,
+        'auto_join', d.auto_join,
+        'last_check_ts', floor(extract(epoch from d.last_check_ts))
+    ))
+    from (
+        select od.domain, od.verification_token, od.verified, od.auto_join, od.last_check_ts
+        from organization_domain od
+        join organization o using (organization_id)
+        where o.name = p_org_name
+        order by od.domain asc
+    ) d;
+end
+$$ language plpgsql;
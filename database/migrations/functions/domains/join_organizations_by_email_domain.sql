@@ -0,0 +1,15 @@
+-- join_organizations_by_email_domain adds the provided user to the
+-- organizations that have verified the domain of the user's email and have
+-- auto join enabled for it. The user's email must have been verified.
+create or replace function join_organizations_by_email_domain(p_user_id uuid)
+returns void as $$
+    insert into user__organization (user_id, organization_id, confirmed)
+    select u.user_id, od.organization_id, true
+    from "user" u
+    join organization_domain od on od.domain = lower(split_part(u.email, '@', 2))
+    where u.user_id = p_user_id
+    and u.email_verified = true
+    and od.verified = true
+    and od.auto_join = true
+    on conflict (user_id, organization_id) do update set confirmed = true;
+$$ language sql;
@@ -0,0 +1,25 @@
+-- update_organization_domain_verification updates the verification status of
+-- the provided organization domain. When a domain with auto join enabled gets
+-- verified, the users whose email belongs to it are added to the organization.
+create or replace function update_organization_domain_verification(
+    p_organization_domain_id uuid,
+    p_verified boolean
+) returns void as $$
+begin
+    update organization_domain set
+        verified = p_verified,
+        last_check_ts = current_timestamp
+    where organization_domain_id = p_organization_domain_id;
+
+    if p_verified then
+        insert into user__organization (user_id, organization_id, confirmed)
+        select u.user_id, od.organization_id, true
+        from organization_domain od
+        join "user" u on lower(split_part(u.email, '@', 2)) = od.domain
+        where od.organization_domain_id = p_organization_domain_id
+        and od.auto_join = true
+        and u.email_verified = true
+        on conflict (user_id, organization_id) do update set confirmed = true;
+    end if;
+end
+$$ language plpgsql;
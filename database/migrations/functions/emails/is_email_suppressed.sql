@@ -0,0 +1,8 @@
+-- is_email_suppressed checks if the provided email address is in the
+-- suppression list.
+create or replace function is_email_suppressed(p_email text)
+returns boolean as $$
+    select exists (
+        select 1 from email_suppression where email = lower(p_email)
+    );
+$$ language sql;
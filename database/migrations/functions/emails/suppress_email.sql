@@ -0,0 +1,14 @@
+-- suppress_email adds the provided email address to the suppression list, so
+-- that no more emails are sent to it. Addresses already suppressed keep the
+-- reason they were suppressed for in the first place, unless a complaint is
+-- received for them.
+create or replace function suppress_email(p_email text, p_reason text)
+returns void as $$
+    insert into email_suppression (email, reason)
+    values (lower(p_email), p_reason)
+    on conflict (email) do update
+    set reason = case
+        when excluded.reason = 'complaint' then excluded.reason
+        else email_suppression.reason
+    end;
+$$ language sql;
@@ -0,0 +1,17 @@
+-- add_cluster_inventory adds the provided cluster inventory to the database.
+create or replace function add_cluster_inventory(p_user_id uuid, p_inventory jsonb)
+returns void as $$
+    insert into cluster_inventory (
+        name,
+        releases,
+        email_report,
+        webhook_url,
+        user_id
+    ) values (
+        p_inventory->>'name',
+        p_inventory->'releases',
+        coalesce((p_inventory->>'email_report')::boolean, false),
+        nullif(p_inventory->>'webhook_url', ''),
+        p_user_id
+    );
+$$ language sql;
@@ -0,0 +1,14 @@
+-- delete_cluster_inventory deletes the provided cluster inventory from the
+-- database.
+create or replace function delete_cluster_inventory(p_user_id uuid, p_cluster_inventory_id uuid)
+returns void as $$
+begin
+    delete from cluster_inventory
+    where cluster_inventory_id = p_cluster_inventory_id
+    and user_id = p_user_id;
+
+    if not found then
+        raise insufficient_privilege;
+    end if;
+end
+$$ language plpgsql;
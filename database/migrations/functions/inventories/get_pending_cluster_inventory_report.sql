@@ -0,0 +1,33 @@
+-- get_pending_cluster_inventory_report returns a cluster inventory whose
+-- report is due if available. Reports are due when the inventory has been
+-- configured to deliver them and the interval provided has passed since the
+-- last one was generated. They are only delivered between the hours provided
+-- (from hour included, to hour excluded) of the owner's local time, using UTC
+-- when the owner has not set a timezone.
+create or replace function get_pending_cluster_inventory_report(
+    p_interval interval,
+    p_from_hour int,
+    p_to_hour int
+) returns setof json as $$
+    select json_build_object(
+        'cluster_inventory_id', ci.cluster_inventory_id,
+        'name', ci.name,
+        'releases', ci.releases,
+        'email_report', ci.email_report,
+        'webhook_url', ci.webhook_url,
+        'user', jsonb_strip_nulls(jsonb_build_object(
+            'user_id', u.user_id,
+            'email', u.email,
+            'locale', u.locale,
+            'timezone', u.timezone
+        ))
+    )
+    from cluster_inventory ci
+    join "user" u using (user_id)
+    where (ci.email_report = true or ci.webhook_url is not null)
+    and (ci.last_report_at is null or ci.last_report_at < current_timestamp - p_interval)
+    and extract(hour from current_timestamp at time zone coalesce(u.timezone, 'UTC')) >= p_from_hour
+    and extract(hour from current_timestamp at time zone coalesce(u.timezone, 'UTC')) < p_to_hour
+    for update of ci skip locked
+    limit 1;
+$$ language sql;
@@ -0,0 +1,15 @@
+-- get_user_cluster_inventories returns the cluster inventories that belong to
+-- the requesting user.
+create or replace function get_user_cluster_inventories(p_user_id uuid)
+returns setof json as $$
+    select coalesce(json_agg(json_build_object(
+        'cluster_inventory_id', ci.cluster_inventory_id,
+        'name', ci.name,
+        'releases', ci.releases,
+        'email_report', ci.email_report,
+        'webhook_url', ci.webhook_url,
+        'last_report_at', floor(extract(epoch from ci.last_report_at))
+    ) order by ci.name asc), '[]')
+    from cluster_inventory ci
+    where ci.user_id = p_user_id;
+$$ language sql;
@@ -0,0 +1,19 @@
+-- update_cluster_inventory updates the provided cluster inventory in the
+-- database.
+create or replace function update_cluster_inventory(p_user_id uuid, p_inventory jsonb)
+returns void as $$
+begin
+    update cluster_inventory set
+        name = p_inventory->>'name',
+        releases = p_inventory->'releases',
+        email_report = coalesce((p_inventory->>'email_report')::boolean, false),
+        webhook_url = nullif(p_inventory->>'webhook_url', ''),
+        updated_at = current_timestamp
+    where cluster_inventory_id = (p_inventory->>'cluster_inventory_id')::uuid
+    and user_id = p_user_id;
+
+    if not found then
+        raise insufficient_privilege;
+    end if;
+end
+$$ language plpgsql;
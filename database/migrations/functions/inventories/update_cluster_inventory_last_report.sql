@@ -0,0 +1,8 @@
+-- update_cluster_inventory_last_report records that the report of the
+-- provided cluster inventory has just been generated.
+create or replace function update_cluster_inventory_last_report(p_cluster_inventory_id uuid)
+returns void as $$
+    update cluster_inventory set
+        last_report_at = current_timestamp
+    where cluster_inventory_id = p_cluster_inventory_id;
+$$ language sql;
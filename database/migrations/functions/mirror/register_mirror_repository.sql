@@ -0,0 +1,52 @@
+-- register_mirror_repository registers the repository provided, mirrored from
+-- another hub instance, as a disabled repository owned by the organization
+-- given. The id of the repository is returned. Repositories with the same name
+-- that were not registered by the mirror cannot be replaced.
+create or replace function register_mirror_repository(
+    p_org_name text,
+    p_repository jsonb
+) returns uuid as $$
+declare
+    v_organization_id uuid;
+    v_repository_id uuid;
+begin
+    select organization_id into v_organization_id
+    from organization
+    where name = p_org_name;
+    if not found then
+        raise 'mirror organization not found: %', p_org_name;
+    end if;
+
+    insert into repository (
+        name,
+        display_name,
+        url,
+        repository_kind_id,
+        disabled,
+        metadata,
+        organization_id
+    ) values (
+        p_repository->>'name',
+        nullif(p_repository->>'display_name', ''),
+        p_repository->>'url',
+        (p_repository->>'kind')::int,
+        true,
+        nullif(p_repository->'metadata', 'null'::jsonb),
+        v_organization_id
+    )
+    on conflict (name) do update
+    set
+        display_name = excluded.display_name,
+        url = excluded.url,
+        metadata = excluded.metadata
+    where repository.organization_id = v_organization_id
+    and repository.repository_kind_id = excluded.repository_kind_id
+    and repository.disabled = true
+    returning repository_id into v_repository_id;
+    if v_repository_id is null then
+        raise 'repository name already in use: %', p_repository->>'name';
+    end if;
+
+    return v_repository_id;
+end
+$$ language plpgsql;
@@ -10,10 +10,11 @@ returns setof json as $$
             'package_version', e.package_version
         ),
         'user', (select nullif(
-            jsonb_build_object(
-                'email', u.email
-            ),
-            '{"email": null}'::jsonb
+            jsonb_strip_nulls(jsonb_build_object(
+                'email', u.email,
+                'locale', u.locale
+            )),
+            '{}'::jsonb
         )),
         'webhook', (select nullif(
             jsonb_build_object(
@@ -0,0 +1,26 @@
+-- add_organization_share grants the target organization provided read access
+-- to the repositories of the organization provided.
+create or replace function add_organization_share(
+    p_requesting_user_id uuid,
+    p_org_name text,
+    p_target_org_name text
+) returns void as $$
+declare
+    v_org_id uuid;
+    v_target_org_id uuid;
+begin
+    if not user_belongs_to_organization(p_requesting_user_id, p_org_name) then
+        raise insufficient_privilege;
+    end if;
+
+    select organization_id into v_org_id from organization where name = p_org_name;
+    select organization_id into v_target_org_id from organization where name = p_target_org_name;
+    if v_target_org_id is null then
+        raise 'target organization not found';
+    end if;
+
+    insert into organization_share (organization_id, shared_with_organization_id)
+    values (v_org_id, v_target_org_id)
+    on conflict do nothing;
+end
+$$ language plpgsql;
@@ -0,0 +1,17 @@
+-- delete_organization_share revokes the read access to the repositories of the
+-- organization provided previously granted to the target organization.
+create or replace function delete_organization_share(
+    p_requesting_user_id uuid,
+    p_org_name text,
+    p_target_org_name text
+) returns void as $$
+begin
+    if not user_belongs_to_organization(p_requesting_user_id, p_org_name) then
+        raise insufficient_privilege;
+    end if;
+
+    delete from organization_share
+    where organization_id = (select organization_id from organization where name = p_org_name)
+    and shared_with_organization_id = (select organization_id from organization where name = p_target_org_name);
+end
+$$ language plpgsql;
@@ -1,4 +1,5 @@
--- get_organization returns the organization requested as a json object.
+-- get_organization returns the organization requested as a json object,
+-- including its featured packages in the order defined by the organization.
 create or replace function get_organization(p_org_name text)
 returns setof json as $$
     select json_build_object(
@@ -6,7 +7,22 @@ returns setof json as $$
         'display_name', o.display_name,
         'description', o.description,
         'home_url', o.home_url,
-        'logo_image_id', o.logo_image_id
+        'logo_image_id', o.logo_image_id,
+        'verified_domains', (
+            select json_agg(od.domain order by od.domain)
+            from organization_domain od
+            where od.organization_id = o.organization_id
+            and od.verified = true
+        ),
+        'featured_packages', (
+            select json_agg(pkgJSON order by ofp.position)
+            from organization_featured_package ofp
+            join package p using (package_id)
+            join repository r using (repository_id)
+            cross join get_package_summary(ofp.package_id) as pkgJSON
+            where ofp.organization_id = o.organization_id
+            and r.organization_id = o.organization_id
+        )
     )
     from organization o
     where o.name = p_org_name;
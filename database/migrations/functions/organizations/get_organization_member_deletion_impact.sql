@@ -0,0 +1,40 @@
+-- get_organization_member_deletion_impact returns what would be affected by
+-- the deletion of the provided member from the organization as a json object,
+-- allowing users to review it before deleting the member. The user doing the
+-- request must belong to the organization.
+create or replace function get_organization_member_deletion_impact(
+    p_requesting_user_id uuid,
+    p_org_name text,
+    p_user_alias text
+) returns setof json as $$
+begin
+    if not user_belongs_to_organization(p_requesting_user_id, p_org_name) then
+        raise insufficient_privilege;
+    end if;
+
+    return query
+    select json_build_object(
+        'last_member', (
+            select count(*) = 1
+            from user__organization
+            where organization_id = o.organization_id
+        ),
+        'repositories', (
+            select count(*)
+            from repository
+            where organization_id = o.organization_id
+        ),
+        'packages', (
+            select count(*)
+            from package p
+            join repository r using (repository_id)
+            where r.organization_id = o.organization_id
+        )
+    )
+    from organization o
+    join user__organization uo using (organization_id)
+    join "user" u using (user_id)
+    where o.name = p_org_name
+    and u.alias = p_user_alias;
+end
+$$ language plpgsql;
@@ -0,0 +1,24 @@
+-- get_organization_shares returns the organizations that have been granted
+-- read access to the repositories of the organization provided as a json array.
+create or replace function get_organization_shares(p_requesting_user_id uuid, p_org_name text)
+returns setof json as $$
+begin
+    if not user_belongs_to_organization(p_requesting_user_id, p_org_name) then
+        raise insufficient_privilege;
+    end if;
+
+    return query
+    select json_agg(json_build_object(
+        'name', s.name,
+        'display_name', s.display_name
+    ))
+    from (
+        select so.name, so.display_name
+        from organization_share os
+        join organization o on o.organization_id = os.organization_id
+        join organization so on so.organization_id = os.shared_with_organization_id
+        where o.name = p_org_name
+        order by so.name asc
+    ) s;
+end
+$$ language plpgsql;
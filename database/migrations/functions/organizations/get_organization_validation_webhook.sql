@@ -0,0 +1,18 @@
+-- get_organization_validation_webhook returns the validation webhook of the
+-- organization provided as a json object if the user provided belongs to it.
+create or replace function get_organization_validation_webhook(p_requesting_user_id uuid, p_org_name text)
+returns setof json as $$
+begin
+    if not user_belongs_to_organization(p_requesting_user_id, p_org_name) then
+        raise insufficient_privilege;
+    end if;
+
+    return query
+    select json_build_object(
+        'url', o.validation_webhook_url,
+        'secret', o.validation_webhook_secret
+    )
+    from organization o
+    where o.name = p_org_name;
+end
+$$ language plpgsql;
@@ -0,0 +1,36 @@
+-- update_organization_featured_packages replaces the featured packages of the
+-- organization provided. Packages are featured in the order provided and they
+-- must belong to one of the organization's repositories.
+create or replace function update_organization_featured_packages(
+    p_requesting_user_id uuid,
+    p_org_name text,
+    p_packages_ids jsonb
+) returns void as $$
+declare
+    v_org_id uuid;
+begin
+    if not user_belongs_to_organization(p_requesting_user_id, p_org_name) then
+        raise insufficient_privilege;
+    end if;
+
+    select organization_id into v_org_id from organization where name = p_org_name;
+    if exists (
+        select 1
+        from jsonb_array_elements_text(p_packages_ids) as pid(package_id)
+        where not exists (
+            select 1
+            from package p
+            join repository r using (repository_id)
+            where p.package_id = pid.package_id::uuid
+            and r.organization_id = v_org_id
+        )
+    ) then
+        raise 'package not found in organization';
+    end if;
+
+    delete from organization_featured_package where organization_id = v_org_id;
+    insert into organization_featured_package (organization_id, package_id, position)
+    select v_org_id, pid.package_id::uuid, pid.position - 1
+    from jsonb_array_elements_text(p_packages_ids) with ordinality as pid(package_id, position);
+end
+$$ language plpgsql;
@@ -0,0 +1,23 @@
+-- update_organization_validation_webhook updates the validation webhook of the
+-- organization provided if the user provided belongs to it. Providing an empty
+-- url removes the validation webhook.
+create or replace function update_organization_validation_webhook(
+    p_requesting_user_id uuid,
+    p_org_name text,
+    p_webhook jsonb
+)
+returns void as $$
+begin
+    if not user_belongs_to_organization(p_requesting_user_id, p_org_name) then
+        raise insufficient_privilege;
+    end if;
+
+    update organization set
+        validation_webhook_url = nullif(p_webhook->>'url', ''),
+        validation_webhook_secret = case
+            when nullif(p_webhook->>'url', '') is null then null
+            else nullif(p_webhook->>'secret', '')
+        end
+    where name = p_org_name;
+end
+$$ language plpgsql;
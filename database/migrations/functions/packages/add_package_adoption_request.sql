@@ -0,0 +1,57 @@
+-- add_package_adoption_request registers a request from the user provided to
+-- adopt the given package, replacing any previous request from the same user.
+-- The package must be seeking maintainers. It returns as a json object the
+-- details needed to notify the package owners about the request.
+create or replace function add_package_adoption_request(
+    p_requesting_user_id uuid,
+    p_package_id uuid,
+    p_message text
+) returns setof json as $$
+begin
+    if not exists (
+        select 1 from package
+        where package_id = p_package_id
+        and seeking_maintainers = true
+    ) then
+        return;
+    end if;
+
+    insert into package_adoption_request (
+        package_id,
+        user_id,
+        message
+    ) values (
+        p_package_id,
+        p_requesting_user_id,
+        p_message
+    )
+    on conflict (package_id, user_id) do update set
+        message = excluded.message,
+        created_at = current_timestamp;
+
+    return query
+    select json_build_object(
+        'package_name', p.name,
+        'repository_kind', r.repository_kind_id,
+        'repository_name', r.name,
+        'user_alias', (select alias from "user" where user_id = p_requesting_user_id),
+        'owners', (
+            select json_agg(json_build_object(
+                'email', u.email,
+                'locale', u.locale
+            ) order by u.email asc)
+            from "user" u
+            where u.user_id = r.user_id
+            or u.user_id in (
+                select uo.user_id
+                from user__organization uo
+                where uo.organization_id = r.organization_id
+                and uo.confirmed = true
+            )
+        )
+    )
+    from package p
+    join repository r using (repository_id)
+    where p.package_id = p_package_id;
+end
+$$ language plpgsql;
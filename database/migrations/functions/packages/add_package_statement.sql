@@ -0,0 +1,60 @@
+-- add_package_statement adds the signed statement provided to the given
+-- package. Only packages from verified publishers repositories can have
+-- statements. If the package already has a statement of the same kind, it is
+-- replaced. A statement change event is registered so that the package
+-- subscribers are notified.
+create or replace function add_package_statement(
+    p_requesting_user_id uuid,
+    p_package_id uuid,
+    p_statement jsonb
+) returns void as $$
+declare
+    v_repository_name text;
+    v_latest_version text;
+    v_repository_private boolean;
+    v_repository_verified_publisher boolean;
+begin
+    select r.name, p.latest_version, r.private, r.verified_publisher
+    into v_repository_name, v_latest_version, v_repository_private, v_repository_verified_publisher
+    from package p
+    join repository r using (repository_id)
+    where p.package_id = p_package_id;
+
+    if not user_has_repository_write_access(p_requesting_user_id, v_repository_name) then
+        raise insufficient_privilege;
+    end if;
+    if not v_repository_verified_publisher then
+        raise insufficient_privilege;
+    end if;
+
+    insert into package_statement (
+        package_id,
+        kind,
+        content,
+        signature,
+        signature_algorithm,
+        signature_key_id,
+        user_id
+    ) values (
+        p_package_id,
+        p_statement->>'kind',
+        p_statement->>'content',
+        p_statement->>'signature',
+        p_statement->>'signature_algorithm',
+        nullif(p_statement->>'signature_key_id', ''),
+        p_requesting_user_id
+    )
+    on conflict (package_id, kind) do update set
+        content = excluded.content,
+        signature = excluded.signature,
+        signature_algorithm = excluded.signature_algorithm,
+        signature_key_id = excluded.signature_key_id,
+        user_id = excluded.user_id,
+        updated_at = current_timestamp;
+
+    if not v_repository_private then
+        insert into event (package_id, package_version, event_kind_id)
+        values (p_package_id, v_latest_version, 2);
+    end if;
+end
+$$ language plpgsql;
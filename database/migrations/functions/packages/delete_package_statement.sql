@@ -0,0 +1,32 @@
+-- delete_package_statement deletes the statement of the kind provided from
+-- the given package, registering a statement change event.
+create or replace function delete_package_statement(
+    p_requesting_user_id uuid,
+    p_package_id uuid,
+    p_kind text
+) returns void as $$
+declare
+    v_repository_name text;
+    v_latest_version text;
+    v_repository_private boolean;
+begin
+    select r.name, p.latest_version, r.private
+    into v_repository_name, v_latest_version, v_repository_private
+    from package p
+    join repository r using (repository_id)
+    where p.package_id = p_package_id;
+
+    if not user_has_repository_write_access(p_requesting_user_id, v_repository_name) then
+        raise insufficient_privilege;
+    end if;
+
+    delete from package_statement
+    where package_id = p_package_id
+    and kind = p_kind;
+
+    if found and not v_repository_private then
+        insert into event (package_id, package_version, event_kind_id)
+        values (p_package_id, v_latest_version, 2);
+    end if;
+end
+$$ language plpgsql;
@@ -0,0 +1,29 @@
+-- get_all_package_adoption_requests returns all the pending adoption requests
+-- as a json array. It's meant to be used by the hub admins.
+create or replace function get_all_package_adoption_requests()
+returns setof json as $$
+    select coalesce(json_agg(json_build_object(
+        'package_id', ar.package_id,
+        'package_name', ar.package_name,
+        'repository_kind', ar.repository_kind_id,
+        'repository_name', ar.repository_name,
+        'user_alias', ar.alias,
+        'message', ar.message,
+        'created_at', floor(extract(epoch from ar.created_at))
+    )), '[]')
+    from (
+        select
+            p.package_id,
+            p.name as package_name,
+            r.repository_kind_id,
+            r.name as repository_name,
+            u.alias,
+            par.message,
+            par.created_at
+        from package_adoption_request par
+        join package p using (package_id)
+        join repository r using (repository_id)
+        join "user" u on u.user_id = par.user_id
+        order by par.created_at desc
+    ) ar;
+$$ language sql;
@@ -0,0 +1,48 @@
+-- get_all_packages returns a page of packages, sorted by id, as a json object.
+-- The id of the last package returned is used as the cursor of the next page,
+-- so iterating over all packages remains stable even when packages are being
+-- registered concurrently.
+create or replace function get_all_packages(p_input jsonb)
+returns setof json as $$
+declare
+    v_cursor uuid := nullif(p_input->>'cursor', '')::uuid;
+    v_limit int := (p_input->>'limit')::int;
+    v_repository_kinds int[];
+begin
+    -- Prepare filters for later use
+    select array_agg(e::int) into v_repository_kinds
+    from jsonb_array_elements_text(p_input->'repository_kinds') e;
+
+    return query
+    with packages_page as (
+        select p.package_id
+        from package p
+        join repository r using (repository_id)
+        where r.private = false
+        and
+            case when v_cursor is not null
+            then p.package_id > v_cursor else true end
+        and
+            case when cardinality(v_repository_kinds) > 0
+            then r.repository_kind_id = any(v_repository_kinds) else true end
+        order by p.package_id asc
+        limit v_limit
+    )
+    select json_build_object(
+        'packages', (
+            select coalesce(json_agg(pkgJSON order by pp.package_id asc), '[]')
+            from packages_page pp
+            cross join get_package_summary(pp.package_id) as pkgJSON
+        ),
+        'next_cursor', (
+            select case when count(*) = v_limit then (
+                select package_id
+                from packages_page
+                order by package_id desc
+                limit 1
+            ) end
+            from packages_page
+        )
+    );
+end
+$$ language plpgsql;
@@ -1,5 +1,6 @@
 -- get_package returns the details as a json object of the package identified
--- by the input provided.
+-- by the input provided. Packages in private repositories are only returned
+-- when the requesting user (if any) has read access to them.
 create or replace function get_package(p_input jsonb)
 returns setof json as $$
 declare
@@ -26,6 +27,7 @@ begin
         'is_operator', p.is_operator,
         'channels', p.channels,
         'default_channel', p.default_channel,
+        'seeking_maintainers', p.seeking_maintainers,
         'display_name', s.display_name,
         'description', s.description,
         'keywords', s.keywords,
@@ -44,12 +46,21 @@ begin
             where package_id = v_package_id
         ),
         'app_version', s.app_version,
+        'kube_version', s.kube_version,
+        'chart_type', s.chart_type,
         'digest', s.digest,
         'deprecated', s.deprecated,
         'license', s.license,
         'signed', s.signed,
+        'sign_key', s.sign_key,
+        'signature_verified', s.signature_verified,
+        'changes', s.changes,
         'container_image', s.container_image,
+        'containers_images', s.containers_images,
         'provider', s.provider,
+        'capabilities', s.capabilities,
+        'maintenance', s.maintenance,
+        'eol', package_version_is_eol(p.package_id, s.version),
         'created_at', floor(extract(epoch from s.created_at)),
         'maintainers', (
             select json_agg(json_build_object(
@@ -66,6 +77,9 @@ begin
             'name', r.name,
             'display_name', r.display_name,
             'url', r.url,
+            'disabled', r.disabled,
+            'private', r.private,
+            'metadata', r.metadata,
             'user_alias', u.alias,
             'organization_name', o.name,
             'organization_display_name', o.display_name
@@ -77,6 +91,10 @@ begin
     left join "user" u using (user_id)
     left join organization o using (organization_id)
     where p.package_id = v_package_id
+    and (
+        r.private = false
+        or user_has_repository_read_access(nullif(p_input->>'user_id', '')::uuid, r.repository_id)
+    )
     and
         case when p_input->>'version' <> '' then
             s.version = p_input->>'version'
@@ -0,0 +1,31 @@
+-- get_package_adoption_requests returns the adoption requests of the provided
+-- package as a json array. Only the package owners can get them.
+create or replace function get_package_adoption_requests(p_requesting_user_id uuid, p_package_id uuid)
+returns setof json as $$
+declare
+    v_repository_name text;
+begin
+    select r.name into v_repository_name
+    from package p
+    join repository r using (repository_id)
+    where p.package_id = p_package_id;
+
+    if not user_owns_repository(p_requesting_user_id, v_repository_name) then
+        raise insufficient_privilege;
+    end if;
+
+    return query
+    select coalesce(json_agg(json_build_object(
+        'user_alias', ar.alias,
+        'message', ar.message,
+        'created_at', floor(extract(epoch from ar.created_at))
+    )), '[]')
+    from (
+        select u.alias, par.message, par.created_at
+        from package_adoption_request par
+        join "user" u using (user_id)
+        where par.package_id = p_package_id
+        order by par.created_at desc
+    ) ar;
+end
+$$ language plpgsql;
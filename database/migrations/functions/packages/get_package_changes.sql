@@ -0,0 +1,48 @@
+-- get_package_changes returns a page of the packages versions created, updated
+-- or deleted since the time provided, sorted by change id, as a json object.
+-- The id of the last change returned is used as the cursor of the next page,
+-- so iterating over all changes remains stable even when new changes are being
+-- registered concurrently.
+create or replace function get_package_changes(p_input jsonb)
+returns setof json as $$
+declare
+    v_since bigint := (p_input->>'since')::bigint;
+    v_cursor bigint := (p_input->>'cursor')::bigint;
+    v_limit int := (p_input->>'limit')::int;
+begin
+    return query
+    with changes_page as (
+        select *
+        from package_change
+        where created_at > to_timestamp(v_since)
+        and repository_id not in (
+            select repository_id from repository where private = true
+        )
+        and
+            case when v_cursor is not null
+            then package_change_id > v_cursor else true end
+        order by package_change_id asc
+        limit v_limit
+    )
+    select json_build_object(
+        'changes', (
+            select coalesce(json_agg(json_build_object(
+                'package_change_id', package_change_id,
+                'package_id', package_id,
+                'package_name', package_name,
+                'version', package_version,
+                'repository_id', repository_id,
+                'repository_name', repository_name,
+                'repository_kind', repository_kind_id,
+                'change_kind', change_kind,
+                'ts', floor(extract(epoch from created_at))
+            ) order by package_change_id asc), '[]')
+            from changes_page
+        ),
+        'next_cursor', (
+            select case when count(*) = v_limit then max(package_change_id) end
+            from changes_page
+        )
+    );
+end
+$$ language plpgsql;
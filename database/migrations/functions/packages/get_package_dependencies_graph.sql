@@ -0,0 +1,118 @@
+-- get_package_dependencies_graph returns the dependencies graph of the package
+-- version identified by the input provided as a json object. Dependencies
+-- resolved to packages in the hub are followed recursively (using their latest
+-- version) up to a maximum depth. The graph nodes are the packages found and
+-- its edges the dependencies between them. Dependencies that have not been
+-- resolved are included as edges without a target package. Packages in
+-- private repositories are only included for users with read access to them.
+create or replace function get_package_dependencies_graph(p_input jsonb)
+returns setof json as $$
+declare
+    v_user_id uuid := nullif(p_input->>'user_id', '')::uuid;
+    v_package_id uuid;
+    v_version text;
+begin
+    select p.package_id, coalesce(nullif(p_input->>'version', ''), p.latest_version)
+    into v_package_id, v_version
+    from package p
+    join repository r using (repository_id)
+    where p.normalized_name = p_input->>'package_name'
+    and r.name = p_input->>'repository_name'
+    and (
+        r.private = false
+        or user_has_repository_read_access(v_user_id, r.repository_id)
+    );
+    if not found then
+        return;
+    end if;
+    perform 1 from snapshot where package_id = v_package_id and version = v_version;
+    if not found then
+        return;
+    end if;
+
+    return query
+    with recursive dependencies as (
+        select
+            s.package_id as from_package_id,
+            d.dependency,
+            1 as depth,
+            array[s.package_id] as path
+        from snapshot s
+        cross join jsonb_array_elements(
+            case when jsonb_typeof(s.data->'dependencies') = 'array'
+            then s.data->'dependencies' else '[]' end
+        ) as d(dependency)
+        where s.package_id = v_package_id
+        and s.version = v_version
+        union all
+        select
+            s.package_id,
+            d.dependency,
+            dep.depth + 1,
+            dep.path || s.package_id
+        from dependencies dep
+        join package p on p.package_id = (dep.dependency->>'package_id')::uuid
+        join repository r on r.repository_id = p.repository_id
+        join snapshot s on s.package_id = p.package_id and s.version = p.latest_version
+        cross join jsonb_array_elements(
+            case when jsonb_typeof(s.data->'dependencies') = 'array'
+            then s.data->'dependencies' else '[]' end
+        ) as d(dependency)
+        where dep.depth < 10
+        and not s.package_id = any(dep.path)
+        and (
+            r.private = false
+            or user_has_repository_read_access(v_user_id, r.repository_id)
+        )
+    ), edges as (
+        select distinct
+            from_package_id,
+            (
+                select p.package_id
+                from package p
+                join repository r using (repository_id)
+                where p.package_id = (dependency->>'package_id')::uuid
+                and (
+                    r.private = false
+                    or user_has_repository_read_access(v_user_id, r.repository_id)
+                )
+            ) as to_package_id,
+            dependency->>'name' as name,
+            dependency->>'version' as version,
+            dependency->>'repository' as repository
+        from dependencies
+    )
+    select json_build_object(
+        'nodes', (
+            select json_agg(json_build_object(
+                'package_id', p.package_id,
+                'name', p.name,
+                'normalized_name', p.normalized_name,
+                'version', case when p.package_id = v_package_id then v_version else p.latest_version end,
+                'repository', json_build_object(
+                    'kind', r.repository_kind_id,
+                    'name', r.name,
+                    'user_alias', u.alias,
+                    'organization_name', o.name
+                )
+            ) order by p.package_id <> v_package_id, r.name asc, p.normalized_name asc)
+            from package p
+            join repository r using (repository_id)
+            left join "user" u using (user_id)
+            left join organization o using (organization_id)
+            where p.package_id = v_package_id
+            or p.package_id in (select to_package_id from edges where to_package_id is not null)
+        ),
+        'edges', (
+            select coalesce(json_agg(json_build_object(
+                'from', e.from_package_id,
+                'to', e.to_package_id,
+                'name', e.name,
+                'version', e.version,
+                'repository', e.repository
+            ) order by e.from_package_id, e.name, e.version), '[]')
+            from edges e
+        )
+    );
+end
+$$ language plpgsql;
@@ -0,0 +1,66 @@
+-- get_package_latest_versions returns the latest stable and prerelease
+-- versions of the package identified by the input provided as a json object.
+-- Packages in private repositories are only returned to users with read
+-- access to them.
+create or replace function get_package_latest_versions(p_input jsonb)
+returns setof json as $$
+declare
+    v_package_id uuid;
+    v_snapshot record;
+    v_stable_version text;
+    v_stable jsonb;
+    v_prerelease_version text;
+    v_prerelease jsonb;
+begin
+    select p.package_id into v_package_id
+    from package p
+    join repository r using (repository_id)
+    where p.normalized_name = p_input->>'package_name'
+    and r.name = p_input->>'repository_name'
+    and (
+        r.private = false
+        or user_has_repository_read_access(nullif(p_input->>'user_id', '')::uuid, r.repository_id)
+    );
+    if not found then
+        return;
+    end if;
+
+    for v_snapshot in
+        select s.version, s.digest, s.created_at
+        from snapshot s
+        where s.package_id = v_package_id
+    loop
+        -- Versions with a hyphen before the build metadata are prereleases
+        if v_snapshot.version ~ '^[^+]*-' then
+            if v_prerelease_version is null or semver_gt(v_snapshot.version, v_prerelease_version) then
+                v_prerelease_version := v_snapshot.version;
+                v_prerelease := jsonb_build_object(
+                    'version', v_snapshot.version,
+                    'digest', v_snapshot.digest,
+                    'created_at', floor(extract(epoch from v_snapshot.created_at))
+                );
+            end if;
+        else
+            if v_stable_version is null or semver_gt(v_snapshot.version, v_stable_version) then
+                v_stable_version := v_snapshot.version;
+                v_stable := jsonb_build_object(
+                    'version', v_snapshot.version,
+                    'digest', v_snapshot.digest,
+                    'created_at', floor(extract(epoch from v_snapshot.created_at))
+                );
+            end if;
+        end if;
+    end loop;
+
+    return query
+    select json_build_object(
+        'package_id', p.package_id,
+        'name', p.name,
+        'normalized_name', p.normalized_name,
+        'latest_stable', v_stable,
+        'latest_prerelease', v_prerelease
+    )
+    from package p
+    where p.package_id = v_package_id;
+end
+$$ language plpgsql;
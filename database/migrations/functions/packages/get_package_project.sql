@@ -0,0 +1,39 @@
+-- get_package_project returns the packages of other kinds that belong to the
+-- same project as the package provided as a json array. Packages belong to the
+-- same project when their latest versions share a source link url (ignoring
+-- the scheme, the www prefix, the .git suffix and any trailing slashes).
+-- Packages in private repositories are only considered for users with read
+-- access to them.
+create or replace function get_package_project(p_user_id uuid, p_package_id uuid)
+returns setof json as $$
+    with packages_sources as (
+        select
+            p.package_id,
+            r.repository_kind_id,
+            lower(regexp_replace(l->>'url', '^https?://(www\.)?|(\.git)?/*$', '', 'g')) as source_url
+        from package p
+        join snapshot s using (package_id)
+        join repository r using (repository_id)
+        cross join jsonb_array_elements(s.links) as l
+        where s.version = p.latest_version
+        and l->>'name' = 'source'
+        and (
+            r.private = false
+            or user_has_repository_read_access(p_user_id, r.repository_id)
+        )
+    )
+    select coalesce(json_agg(pkgJSON), '[]')
+    from (
+        select p.package_id
+        from package p
+        where p.package_id in (
+            select ps2.package_id
+            from packages_sources ps1
+            join packages_sources ps2 using (source_url)
+            where ps1.package_id = p_package_id
+            and ps2.repository_kind_id <> ps1.repository_kind_id
+        )
+        order by p.name asc
+    ) pp
+    cross join get_package_summary(pp.package_id) as pkgJSON;
+$$ language sql;
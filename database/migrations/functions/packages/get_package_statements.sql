@@ -0,0 +1,29 @@
+-- get_package_statements returns the statements attached to the provided
+-- package as a json array. The statements of packages in private repositories
+-- are only returned to users with read access to them.
+create or replace function get_package_statements(p_user_id uuid, p_package_id uuid)
+returns setof json as $$
+    select coalesce(json_agg(json_build_object(
+        'kind', s.kind,
+        'content', s.content,
+        'signature', s.signature,
+        'signature_algorithm', s.signature_algorithm,
+        'signature_key_id', s.signature_key_id,
+        'publisher', s.alias,
+        'created_at', floor(extract(epoch from s.created_at)),
+        'updated_at', floor(extract(epoch from s.updated_at))
+    )), '[]')
+    from (
+        select ps.*, u.alias
+        from package_statement ps
+        join package p using (package_id)
+        join repository r using (repository_id)
+        left join "user" u on u.user_id = ps.user_id
+        where ps.package_id = p_package_id
+        and (
+            r.private = false
+            or user_has_repository_read_access(p_user_id, r.repository_id)
+        )
+        order by ps.kind asc
+    ) s;
+$$ language sql;
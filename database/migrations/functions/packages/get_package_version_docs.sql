@@ -0,0 +1,26 @@
+-- get_package_version_docs returns the readme and the changelog of the
+-- package version identified by the input provided as a json object. The
+-- latest version of the package is used when no version is provided. Packages
+-- in private repositories are only returned to users with read access to them.
+create or replace function get_package_version_docs(p_input jsonb)
+returns setof json as $$
+    select json_build_object(
+        'package_id', p.package_id,
+        'name', p.name,
+        'normalized_name', p.normalized_name,
+        'version', s.version,
+        'readme', s.readme,
+        'changes', s.changes,
+        'changelog', s.changelog
+    )
+    from package p
+    join repository r using (repository_id)
+    join snapshot s on s.package_id = p.package_id
+    and s.version = coalesce(nullif(p_input->>'version', ''), p.latest_version)
+    where p.normalized_name = p_input->>'package_name'
+    and r.name = p_input->>'repository_name'
+    and (
+        r.private = false
+        or user_has_repository_read_access(nullif(p_input->>'user_id', '')::uuid, r.repository_id)
+    );
+$$ language sql;
@@ -0,0 +1,40 @@
+-- get_packages_by_owner returns the latest version of the packages in the
+-- repositories owned by the user alias or organization name provided as a json
+-- array. Packages in private repositories are only included for users with
+-- read access to them.
+create or replace function get_packages_by_owner(p_user_id uuid, p_owner text)
+returns setof json as $$
+    select coalesce(json_agg(json_build_object(
+        'package_id', p.package_id,
+        'name', p.name,
+        'normalized_name', p.normalized_name,
+        'display_name', s.display_name,
+        'description', s.description,
+        'keywords', s.keywords,
+        'home_url', s.home_url,
+        'version', s.version,
+        'app_version', s.app_version,
+        'deprecated', s.deprecated,
+        'repository', jsonb_build_object(
+            'repository_id', r.repository_id,
+            'kind', r.repository_kind_id,
+            'name', r.name,
+            'display_name', r.display_name,
+            'url', r.url,
+            'user_alias', u.alias,
+            'organization_name', o.name,
+            'organization_display_name', o.display_name
+        )
+    ) order by r.name asc, p.normalized_name asc), '[]')
+    from package p
+    join snapshot s using (package_id)
+    join repository r using (repository_id)
+    left join "user" u using (user_id)
+    left join organization o using (organization_id)
+    where s.version = p.latest_version
+    and (u.alias = p_owner or o.name = p_owner)
+    and (
+        r.private = false
+        or user_has_repository_read_access(p_user_id, r.repository_id)
+    );
+$$ language sql;
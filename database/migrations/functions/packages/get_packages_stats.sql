@@ -1,9 +1,21 @@
 -- get_packages_stats returns the number of packages and releases registered in
--- the database as a json object.
+-- the database as a json object. Packages in private repositories are not
+-- counted.
 create or replace function get_packages_stats()
 returns setof json as $$
     select json_build_object(
-        'packages', (select count(*) from package),
-        'releases', (select count(*) from snapshot)
+        'packages', (
+            select count(*)
+            from package p
+            join repository r using (repository_id)
+            where r.private = false
+        ),
+        'releases', (
+            select count(*)
+            from snapshot s
+            join package p using (package_id)
+            join repository r using (repository_id)
+            where r.private = false
+        )
     );
 $$ language sql;
@@ -0,0 +1,21 @@
+-- get_packages_tags returns the tags used by the packages registered in the
+-- database, along with the number of packages using each of them, as a json
+-- array. Tags are sorted by the number of packages (most used first). Packages
+-- in private repositories are not counted.
+create or replace function get_packages_tags(p_limit int)
+returns setof json as $$
+    select coalesce(json_agg(json_build_object(
+        'name', name,
+        'packages', packages
+    )), '[]')
+    from (
+        select pt.name, count(*) as packages
+        from package_tag pt
+        join package p using (package_id)
+        join repository r using (repository_id)
+        where r.private = false
+        group by pt.name
+        order by packages desc, pt.name asc
+        limit p_limit
+    ) t;
+$$ language sql;
@@ -6,7 +6,9 @@ returns setof json as $$
         select p.package_id
         from package p
         join snapshot s using (package_id)
+        join repository r using (repository_id)
         where s.version = p.latest_version
+        and r.private = false
         and (s.deprecated is null or s.deprecated = false)
         and p.logo_image_id is not null
         and s.readme is not null
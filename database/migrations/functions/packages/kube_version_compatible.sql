@@ -0,0 +1,30 @@
+-- kube_version_compatible checks if any release of the Kubernetes minor
+-- version provided (i.e. 1.19) satisfies the given kubeVersion constraint.
+-- The constraint must be normalized: groups of comma separated >= and <
+-- comparisons joined by ||, like '>=1.16.0, <1.20.0 || >=1.21.0'.
+create or replace function kube_version_compatible(p_constraint text, p_minor text)
+returns boolean as $$
+declare
+    v_minor_first text := p_minor || '.0';
+    v_minor_next text := split_part(p_minor, '.', 1) || '.' || (split_part(p_minor, '.', 2)::int + 1) || '.0';
+    v_group text;
+    v_comparison text;
+    v_compatible boolean;
+begin
+    foreach v_group in array string_to_array(p_constraint, '||') loop
+        v_compatible := true;
+        foreach v_comparison in array string_to_array(v_group, ',') loop
+            v_comparison := trim(v_comparison);
+            if v_comparison like '>=%' and semver_gte(substring(v_comparison from 3), v_minor_next) then
+                v_compatible := false;
+            elsif v_comparison like '<%' and semver_gte(v_minor_first, substring(v_comparison from 2)) then
+                v_compatible := false;
+            end if;
+        end loop;
+        if v_compatible then
+            return true;
+        end if;
+    end loop;
+    return false;
+end
+$$ language plpgsql;
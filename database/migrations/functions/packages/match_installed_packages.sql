@@ -0,0 +1,55 @@
+-- match_installed_packages returns the packages matching the installed
+-- releases provided as a json array. Releases are matched by the repository
+-- url and the name of the chart, comparing urls case insensitively and
+-- ignoring trailing slashes. The status of the installed version is returned
+-- along with the package matched, if any, in the same order the releases were
+-- provided. Packages in private repositories are only matched for users with
+-- read access to them.
+create or replace function match_installed_packages(p_user_id uuid, p_releases jsonb)
+returns setof json as $$
+    select coalesce(json_agg(json_build_object(
+        'name', i.release->>'name',
+        'version', i.release->>'version',
+        'repository_url', i.release->>'repository_url',
+        'package', m.package,
+        'version_registered', coalesce(m.version_registered, false),
+        'update_available', coalesce(m.update_available, false),
+        'deprecated', coalesce(m.deprecated, false),
+        'eol', coalesce(m.eol, false)
+    ) order by i.position), '[]')
+    from jsonb_array_elements(p_releases) with ordinality as i(release, position)
+    left join lateral (
+        select
+            json_build_object(
+                'package_id', p.package_id,
+                'name', p.name,
+                'normalized_name', p.normalized_name,
+                'latest_version', p.latest_version,
+                'repository', jsonb_build_object(
+                    'repository_id', r.repository_id,
+                    'kind', r.repository_kind_id,
+                    'name', r.name,
+                    'url', r.url,
+                    'user_alias', u.alias,
+                    'organization_name', o.name
+                )
+            ) as package,
+            s.version is not null as version_registered,
+            semver_gt(p.latest_version, i.release->>'version') as update_available,
+            s.deprecated as deprecated,
+            package_version_is_eol(p.package_id, i.release->>'version') as eol
+        from package p
+        join repository r using (repository_id)
+        left join "user" u using (user_id)
+        left join organization o using (organization_id)
+        left join snapshot s on s.package_id = p.package_id and s.version = i.release->>'version'
+        where r.repository_kind_id = 0
+        and regexp_replace(lower(r.url), '/*$', '') = regexp_replace(lower(i.release->>'repository_url'), '/*$', '')
+        and p.name = i.release->>'name'
+        and (
+            r.private = false
+            or user_has_repository_read_access(p_user_id, r.repository_id)
+        )
+        limit 1
+    ) m on true;
+$$ language sql;
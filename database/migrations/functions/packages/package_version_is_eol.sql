@@ -0,0 +1,29 @@
+-- package_version_is_eol checks if the provided package version has reached
+-- its end of life. This happens when the EOL date declared by the version has
+-- passed or when it is not in the supported versions range declared by the
+-- latest version of the package.
+create or replace function package_version_is_eol(p_package_id uuid, p_version text)
+returns boolean as $$
+declare
+    v_eol date;
+    v_supported_versions text;
+begin
+    select (s.maintenance->>'eol')::date into v_eol
+    from snapshot s
+    where s.package_id = p_package_id
+    and s.version = p_version;
+    if v_eol is not null and v_eol <= current_date then
+        return true;
+    end if;
+
+    select s.maintenance->>'supported_versions' into v_supported_versions
+    from package p
+    join snapshot s on s.package_id = p.package_id and s.version = p.latest_version
+    where p.package_id = p_package_id;
+    if v_supported_versions is not null then
+        return not semver_satisfies(p_version, v_supported_versions);
+    end if;
+
+    return false;
+end
+$$ language plpgsql;
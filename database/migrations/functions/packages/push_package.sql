@@ -0,0 +1,23 @@
+-- push_package registers the package provided in the given repository on
+-- behalf of the requesting user, who must have write access to it.
+create or replace function push_package(
+    p_requesting_user_id uuid,
+    p_repository_id uuid,
+    p_pkg jsonb
+) returns void as $$
+declare
+    v_repository_name text;
+begin
+    select name into v_repository_name
+    from repository
+    where repository_id = p_repository_id;
+
+    if not user_has_repository_write_access(p_requesting_user_id, v_repository_name) then
+        raise insufficient_privilege;
+    end if;
+
+    perform register_package(p_pkg || jsonb_build_object(
+        'repository', jsonb_build_object('repository_id', p_repository_id)
+    ));
+end
+$$ language plpgsql;
@@ -1,8 +1,8 @@
 -- register_package registers the provided package in the database. This
 -- involves registering or updating the package entity when needed, registering
 -- a snapshot for the package version and creating/updating/deleting the
--- package maintainers as needed depending on the ones present in the latest
--- package version.
+-- package maintainers and tags as needed depending on the ones present in the
+-- latest package version.
 create or replace function register_package(p_pkg jsonb)
 returns void as $$
 declare
@@ -12,6 +12,11 @@ declare
     v_display_name text := nullif(p_pkg->>'display_name', '');
     v_description text := nullif(p_pkg->>'description', '');
     v_keywords text[] := (select (array(select jsonb_array_elements_text(nullif(p_pkg->'keywords', 'null'::jsonb))))::text[]);
+    -- CRDs kinds and groups are indexed along with the keywords in the tsdoc
+    v_ts_keywords text[] := v_keywords || array(
+        select concat_ws(' ', crd->>'kind', crd->>'group')
+        from jsonb_array_elements(nullif(p_pkg->'data'->'crds', 'null'::jsonb)) as crd
+    );
     v_version text := p_pkg->>'version';
     v_repository_id uuid := ((p_pkg->'repository')->>'repository_id')::uuid;
     v_maintainer jsonb;
@@ -20,10 +25,19 @@ declare
     v_provider text := nullif(p_pkg->>'provider', '');
     v_ts_repository text[];
     v_ts_publisher text[];
+    v_repository_name text;
+    v_repository_kind_id int;
+    v_repository_private boolean;
+    v_snapshot_exists boolean;
 begin
     -- Get repository and publisher name for tsdoc
-    select array[r.name, r.display_name], array[u.alias, o.name, o.display_name, v_provider]
-    into v_ts_repository, v_ts_publisher
+    select
+        array[r.name, r.display_name],
+        array[u.alias, o.name, o.display_name, v_provider],
+        r.name,
+        r.repository_kind_id,
+        r.private
+    into v_ts_repository, v_ts_publisher, v_repository_name, v_repository_kind_id, v_repository_private
     from repository r
     left join "user" u using (user_id)
     left join organization o using (organization_id)
@@ -51,7 +65,7 @@ begin
         nullif(p_pkg->>'logo_url', ''),
         nullif(p_pkg->>'logo_image_id', '')::uuid,
         v_version,
-        generate_package_tsdoc(v_name, v_display_name, v_description, v_keywords, v_ts_repository, v_ts_publisher),
+        generate_package_tsdoc(v_name, v_display_name, v_description, v_ts_keywords, v_ts_repository, v_ts_publisher),
         (p_pkg->>'is_operator')::boolean,
         p_pkg->'channels',
         nullif(p_pkg->>'default_channel', ''),
@@ -61,9 +75,12 @@ begin
     set
         name = excluded.name,
         logo_url = excluded.logo_url,
-        logo_image_id = excluded.logo_image_id,
+        logo_image_id = case
+            when excluded.logo_image_id is null and excluded.logo_url = package.logo_url then package.logo_image_id
+            else excluded.logo_image_id
+        end,
         latest_version = excluded.latest_version,
-        tsdoc = generate_package_tsdoc(v_name, v_display_name, v_description, v_keywords, v_ts_repository, v_ts_publisher),
+        tsdoc = generate_package_tsdoc(v_name, v_display_name, v_description, v_ts_keywords, v_ts_repository, v_ts_publisher),
         is_operator = excluded.is_operator,
         channels = excluded.channels,
         default_channel = excluded.default_channel
@@ -108,6 +125,13 @@ begin
         delete from maintainer where maintainer_id not in (
             select maintainer_id from package__maintainer
         );
+
+        -- Tags (from the keywords of the latest version)
+        delete from package_tag where package_id = v_package_id;
+        insert into package_tag (package_id, name)
+        select distinct v_package_id, k
+        from unnest(v_keywords) as k
+        where k <> '';
     else
         -- Package record was not created or updated, get package id to insert snapshot
         select package_id into v_package_id
@@ -117,6 +141,8 @@ begin
     end if;
 
     -- Package snapshot
+    perform 1 from snapshot where package_id = v_package_id and version = v_version;
+    v_snapshot_exists := found;
     v_created_at := to_timestamp((p_pkg->>'created_at')::int);
     if v_created_at is null then
         v_created_at = current_timestamp;
@@ -129,6 +155,8 @@ begin
         keywords,
         home_url,
         app_version,
+        kube_version,
+        chart_type,
         digest,
         readme,
         install,
@@ -137,9 +165,16 @@ begin
         deprecated,
         license,
         signed,
+        sign_key,
+        signature_verified,
+        changes,
+        changelog,
         content_url,
         container_image,
+        containers_images,
         provider,
+        capabilities,
+        maintenance,
         created_at
     ) values (
         v_package_id,
@@ -149,6 +184,8 @@ begin
         v_keywords,
         nullif(p_pkg->>'home_url', ''),
         nullif(p_pkg->>'app_version', ''),
+        nullif(p_pkg->>'kube_version', ''),
+        nullif(p_pkg->>'chart_type', ''),
         nullif(p_pkg->>'digest', ''),
         nullif(p_pkg->>'readme', ''),
         nullif(p_pkg->>'install', ''),
@@ -157,9 +194,16 @@ begin
         (p_pkg->>'deprecated')::boolean,
         nullif(p_pkg->>'license', ''),
         (p_pkg->>'signed')::boolean,
+        nullif(p_pkg->'sign_key', 'null'::jsonb),
+        (p_pkg->>'signature_verified')::boolean,
+        nullif(p_pkg->'changes', 'null'::jsonb),
+        nullif(p_pkg->>'changelog', ''),
         nullif(p_pkg->>'content_url', ''),
         nullif(p_pkg->>'container_image', ''),
+        nullif(p_pkg->'containers_images', 'null'::jsonb),
         v_provider,
+        nullif(p_pkg->>'capabilities', ''),
+        nullif(p_pkg->'maintenance', 'null'::jsonb),
         v_created_at
     )
     on conflict (package_id, version) do update
@@ -169,6 +213,8 @@ begin
         keywords = excluded.keywords,
         home_url = excluded.home_url,
         app_version = excluded.app_version,
+        kube_version = excluded.kube_version,
+        chart_type = excluded.chart_type,
         digest = excluded.digest,
         readme = excluded.readme,
         install = excluded.install,
@@ -177,13 +223,40 @@ begin
         deprecated = excluded.deprecated,
         license = excluded.license,
         signed = excluded.signed,
+        sign_key = excluded.sign_key,
+        signature_verified = excluded.signature_verified,
+        changes = excluded.changes,
+        changelog = excluded.changelog,
         content_url = excluded.content_url,
         container_image = excluded.container_image,
+        containers_images = excluded.containers_images,
         provider = excluded.provider,
+        capabilities = excluded.capabilities,
+        maintenance = excluded.maintenance,
         created_at = v_created_at;
 
+    -- Track package version change
+    insert into package_change (
+        package_id,
+        package_name,
+        package_version,
+        repository_id,
+        repository_name,
+        repository_kind_id,
+        change_kind
+    ) values (
+        v_package_id,
+        v_name,
+        v_version,
+        v_repository_id,
+        v_repository_name,
+        v_repository_kind_id,
+        case when v_snapshot_exists then 'updated' else 'created' end
+    );
+
     -- Register new release event if package's latest version has been updated
-    if semver_gt(v_version, v_previous_latest_version) then
+    -- (notifications are not delivered for packages in private repositories)
+    if semver_gt(v_version, v_previous_latest_version) and not v_repository_private then
         insert into event (package_id, package_version, event_kind_id)
         values (v_package_id, v_version, 0)
         on conflict do nothing;
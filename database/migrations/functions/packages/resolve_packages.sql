@@ -0,0 +1,59 @@
+-- resolve_packages returns the packages matching the external identifiers
+-- provided as a json array. Packages can be resolved from their repository url
+-- and name or from the source url of their latest version, optionally
+-- filtering by the repository kind. Urls are compared case insensitively,
+-- ignoring trailing slashes and .git suffixes. Packages in private repositories
+-- are only returned to users with read access to them.
+create or replace function resolve_packages(p_input jsonb)
+returns setof json as $$
+declare
+    v_repository_url text := regexp_replace(lower(p_input->>'repository_url'), '(\.git)?/*$', '');
+    v_source_url text := regexp_replace(lower(p_input->>'source_url'), '(\.git)?/*$', '');
+begin
+    return query
+    select coalesce(json_agg(json_build_object(
+        'package_id', p.package_id,
+        'name', p.name,
+        'normalized_name', p.normalized_name,
+        'version', s.version,
+        'repository', jsonb_build_object(
+            'repository_id', r.repository_id,
+            'kind', r.repository_kind_id,
+            'name', r.name,
+            'url', r.url,
+            'user_alias', u.alias,
+            'organization_name', o.name
+        )
+    ) order by r.name asc, p.normalized_name asc), '[]')
+    from package p
+    join snapshot s using (package_id)
+    join repository r using (repository_id)
+    left join "user" u using (user_id)
+    left join organization o using (organization_id)
+    where s.version = p.latest_version
+    and (
+        r.private = false
+        or user_has_repository_read_access(nullif(p_input->>'user_id', '')::uuid, r.repository_id)
+    )
+    and
+        case when p_input ? 'repository_kind' then
+            r.repository_kind_id = (p_input->>'repository_kind')::int
+        else true end
+    and
+        case when v_repository_url is not null then
+            regexp_replace(lower(r.url), '(\.git)?/*$', '') = v_repository_url
+            and p.name = p_input->>'package_name'
+        else true end
+    and
+        case when v_source_url is not null then
+            exists (
+                select 1
+                from jsonb_array_elements(
+                    case when jsonb_typeof(s.links) = 'array' then s.links else '[]' end
+                ) as l
+                where l->>'name' = 'source'
+                and regexp_replace(lower(l->>'url'), '(\.git)?/*$', '') = v_source_url
+            )
+        else true end;
+end
+$$ language plpgsql;
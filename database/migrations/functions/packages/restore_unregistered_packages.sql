@@ -0,0 +1,113 @@
+-- restore_unregistered_packages registers again the package versions
+-- unregistered from the provided repository whose tombstones have not been
+-- pruned yet, returning the number of versions restored. When a package name
+-- is provided, only the versions of that package are restored. Versions that
+-- have been registered again since they were unregistered are skipped.
+create or replace function restore_unregistered_packages(p_repository_name text, p_package_name text)
+returns bigint as $$
+declare
+    v_repository_id uuid;
+    v_repository_kind_id int;
+    v_tombstone record;
+    v_package_id uuid;
+    v_latest_version text;
+    v_rows_affected bigint;
+    v_restored bigint := 0;
+begin
+    -- Get repository details
+    select repository_id, repository_kind_id
+    into v_repository_id, v_repository_kind_id
+    from repository
+    where name = p_repository_name;
+    if not found then
+        raise 'repository not found: %', p_repository_name;
+    end if;
+
+    for v_tombstone in
+        select *
+        from package_tombstone
+        where repository_id = v_repository_id
+        and (p_package_name is null or package_name = p_package_name)
+        order by created_at asc, package_tombstone_id asc
+    loop
+        -- Register the package again if it was deleted along with its last
+        -- version (stars are reset as they were deleted on cascade)
+        select package_id, latest_version into v_package_id, v_latest_version
+        from package
+        where repository_id = v_repository_id
+        and name = v_tombstone.package_name;
+        if not found then
+            insert into package (
+                package_id,
+                name,
+                latest_version,
+                logo_url,
+                logo_image_id,
+                tsdoc,
+                is_operator,
+                channels,
+                default_channel,
+                seeking_maintainers,
+                repository_id
+            )
+            select
+                package_id,
+                name,
+                v_tombstone.package_version,
+                logo_url,
+                logo_image_id,
+                tsdoc,
+                is_operator,
+                channels,
+                default_channel,
+                seeking_maintainers,
+                v_repository_id
+            from jsonb_populate_record(null::package, v_tombstone.package)
+            returning package_id, latest_version into v_package_id, v_latest_version;
+        end if;
+
+        -- Restore version snapshot
+        insert into snapshot
+        select (jsonb_populate_record(
+            null::snapshot,
+            v_tombstone.snapshot || jsonb_build_object('package_id', v_package_id)
+        )).*
+        on conflict do nothing;
+        get diagnostics v_rows_affected = row_count;
+
+        if v_rows_affected > 0 then
+            v_restored := v_restored + 1;
+
+            -- Update package's latest version if needed
+            if semver_gt(v_tombstone.package_version, v_latest_version) then
+                update package set latest_version = v_tombstone.package_version
+                where package_id = v_package_id;
+            end if;
+
+            -- Track package version change
+            insert into package_change (
+                package_id,
+                package_name,
+                package_version,
+                repository_id,
+                repository_name,
+                repository_kind_id,
+                change_kind
+            ) values (
+                v_package_id,
+                v_tombstone.package_name,
+                v_tombstone.package_version,
+                v_repository_id,
+                p_repository_name,
+                v_repository_kind_id,
+                'created'
+            );
+        end if;
+
+        delete from package_tombstone
+        where package_tombstone_id = v_tombstone.package_tombstone_id;
+    end loop;
+
+    return v_restored;
+end
+$$ language plpgsql;
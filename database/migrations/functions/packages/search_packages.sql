@@ -1,5 +1,9 @@
 -- search_packages searchs packages in the database that match the criteria in
--- the query provided.
+-- the query provided. When a scope (user, organization or repository) is
+-- provided, only the packages within it are considered, including when the
+-- facets are computed. When some tags are provided, only the packages tagged
+-- with any of them are returned. Packages in private repositories are only considered
+-- when the requesting user (if any) has read access to them.
 create or replace function search_packages(p_input jsonb)
 returns setof json as $$
 declare
@@ -7,6 +11,16 @@ declare
     v_users text[];
     v_orgs text[];
     v_repositories text[];
+    v_capabilities text[];
+    v_chart_types text[];
+    v_tags text[];
+    v_capabilities_levels text[] := array[
+        'Basic Install',
+        'Seamless Upgrades',
+        'Full Lifecycle',
+        'Deep Insights',
+        'Auto Pilot'
+    ];
     v_facets boolean := (p_input->>'facets')::boolean;
     v_tsquery_web tsquery := websearch_to_tsquery(p_input->>'ts_query_web');
     v_tsquery tsquery := to_tsquery(p_input->>'ts_query');
@@ -20,6 +34,12 @@ begin
     from jsonb_array_elements_text(p_input->'orgs') e;
     select array_agg(e::text) into v_repositories
     from jsonb_array_elements_text(p_input->'repositories') e;
+    select array_agg(e::text) into v_capabilities
+    from jsonb_array_elements_text(p_input->'capabilities') e;
+    select array_agg(e::text) into v_chart_types
+    from jsonb_array_elements_text(p_input->'chart_types') e;
+    select array_agg(e::text) into v_tags
+    from jsonb_array_elements_text(p_input->'tags') e;
 
     return query
     with packages_applying_minimum_filters as (
@@ -36,6 +56,8 @@ begin
             s.app_version,
             s.deprecated,
             s.signed,
+            s.capabilities,
+            s.chart_type,
             s.created_at,
             r.repository_id,
             r.repository_kind_id,
@@ -53,6 +75,10 @@ begin
         left join "user" u using (user_id)
         left join organization o using (organization_id)
         where s.version = p.latest_version
+        and (
+            r.private = false
+            or user_has_repository_read_access(nullif(p_input->>'user_id', '')::uuid, r.repository_id)
+        )
         and
             case when v_tsquery_web is not null then
                 v_tsquery_web @@ p.tsdoc
@@ -61,6 +87,18 @@ begin
             case when v_tsquery is not null then
                 v_tsquery @@ p.tsdoc
             else true end
+        and
+            case when p_input ? 'scope_user' then
+                u.alias = p_input->>'scope_user'
+            else true end
+        and
+            case when p_input ? 'scope_org' then
+                o.name = p_input->>'scope_org'
+            else true end
+        and
+            case when p_input ? 'scope_repository' then
+                r.name = p_input->>'scope_repository'
+            else true end
         and
             case when p_input ? 'operators' and (p_input->>'operators')::boolean = true then
                 p.is_operator = true
@@ -73,6 +111,18 @@ begin
             else
                 (s.deprecated is null or s.deprecated = false)
             end
+        and
+            case when p_input ? 'supported_only' and (p_input->>'supported_only')::boolean = true then
+                not package_version_is_eol(p.package_id, s.version)
+            else
+                true
+            end
+        and
+            case when p_input ? 'kubernetes_version' then
+                s.kube_version is null or kube_version_compatible(s.kube_version, p_input->>'kubernetes_version')
+            else
+                true
+            end
     ), packages_applying_all_filters as (
         select * from packages_applying_minimum_filters
         where
@@ -87,6 +137,19 @@ begin
         and
             case when cardinality(v_repositories) > 0
             then repository_name = any(v_repositories) else true end
+        and
+            case when cardinality(v_capabilities) > 0
+            then capabilities = any(v_capabilities) else true end
+        and
+            case when cardinality(v_chart_types) > 0
+            then chart_type = any(v_chart_types) else true end
+        and
+            case when cardinality(v_tags) > 0
+            then exists (
+                select 1 from package_tag pt
+                where pt.package_id = packages_applying_minimum_filters.package_id
+                and pt.name = any(v_tags)
+            ) else true end
     )
     select json_build_object(
         'data', (
@@ -104,6 +167,7 @@ begin
                         'app_version', app_version,
                         'deprecated', deprecated,
                         'signed', signed,
+                        'capabilities', capabilities,
                         'created_at', floor(extract(epoch from created_at)),
                         'repository', jsonb_build_object(
                             'repository_id', repository_id,
@@ -124,7 +188,12 @@ begin
                                 ts_rank('{0.1, 0.2, 0.2, 1.0}', ts_filter(tsdoc, '{b,c}'), v_tsquery_web)
                             else 1 end) as rank
                         from packages_applying_all_filters paaf
-                        order by rank desc, name asc
+                        order by
+                            case when p_input->>'sort' = 'capabilities' then
+                                array_position(v_capabilities_levels, capabilities)
+                            end desc nulls last,
+                            rank desc,
+                            name asc
                         limit (p_input->>'limit')::int
                         offset (p_input->>'offset')::int
                     ) packages_applying_all_filters_paginated
@@ -248,6 +317,26 @@ begin
                                     ) as repos_filtered
                                 )
                             )
+                        ),
+                        (
+                            select json_build_object(
+                                'title', 'Chart type',
+                                'filter_key', 'chart_type',
+                                'options', (
+                                    select coalesce(json_agg(json_build_object(
+                                        'id', chart_type,
+                                        'name', initcap(chart_type),
+                                        'total', total
+                                    )), '[]')
+                                    from (
+                                        select chart_type, count(*) as total
+                                        from packages_applying_minimum_filters
+                                        where chart_type is not null
+                                        group by chart_type
+                                        order by total desc, chart_type asc
+                                    ) as breakdown
+                                )
+                            )
                         )
                     )
                 ) else null end
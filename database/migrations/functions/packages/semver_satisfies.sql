@@ -0,0 +1,30 @@
+-- semver_satisfies checks if the semver provided satisfies the given
+-- constraint. The constraint is a comma separated list of comparisons (>=, >,
+-- <=, <, =) that must all be satisfied, like '>=1.0.0, <2.0.0'.
+create or replace function semver_satisfies(p_version text, p_constraint text)
+returns boolean as $$
+declare
+    v_comparison text;
+    v_parts text[];
+    v_operator text;
+    v_version text;
+begin
+    foreach v_comparison in array string_to_array(p_constraint, ',') loop
+        v_parts := regexp_match(trim(v_comparison), '^(>=|<=|>|<|=)?\s*v?(.+)$');
+        v_operator := coalesce(v_parts[1], '=');
+        v_version := v_parts[2];
+        if v_operator = '>=' and not semver_gte(p_version, v_version) then
+            return false;
+        elsif v_operator = '>' and not semver_gt(p_version, v_version) then
+            return false;
+        elsif v_operator = '<=' and semver_gt(p_version, v_version) then
+            return false;
+        elsif v_operator = '<' and semver_gte(p_version, v_version) then
+            return false;
+        elsif v_operator = '=' and not (semver_gte(p_version, v_version) and semver_gte(v_version, p_version)) then
+            return false;
+        end if;
+    end loop;
+    return true;
+end
+$$ language plpgsql;
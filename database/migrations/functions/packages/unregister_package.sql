@@ -1,4 +1,6 @@
--- unregister_package unregisters the provided package version from the database.
+-- unregister_package unregisters the provided package version from the
+-- database. A tombstone of the version is kept, so that it can be restored
+-- with restore_unregistered_packages until it is pruned.
 create or replace function unregister_package(p_pkg jsonb)
 returns void as $$
 declare
@@ -19,6 +21,41 @@ begin
         return;
     end if;
 
+    -- Track package version change
+    insert into package_change (
+        package_id,
+        package_name,
+        package_version,
+        repository_id,
+        repository_name,
+        repository_kind_id,
+        change_kind
+    )
+    select s.package_id, p.name, s.version, r.repository_id, r.name, r.repository_kind_id, 'deleted'
+    from snapshot s
+    join package p using (package_id)
+    join repository r using (repository_id)
+    where s.package_id = v_package_id
+    and s.version = p_pkg->>'version';
+
+    -- Keep a tombstone of the package version
+    insert into package_tombstone (
+        repository_id,
+        package_name,
+        package_version,
+        package,
+        snapshot
+    )
+    select p.repository_id, p.name, s.version, to_jsonb(p), to_jsonb(s)
+    from snapshot s
+    join package p using (package_id)
+    where s.package_id = v_package_id
+    and s.version = p_pkg->>'version'
+    on conflict (repository_id, package_name, package_version) do update set
+        package = excluded.package,
+        snapshot = excluded.snapshot,
+        created_at = current_timestamp;
+
     -- If the version to delete is the only one available we delete the package
     -- (some other elements will be deleted on cascade)
     if v_snapshots_count = 1 then
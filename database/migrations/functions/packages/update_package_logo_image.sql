@@ -0,0 +1,14 @@
+-- update_package_logo_image updates the logo image of the provided package,
+-- provided that its logo url has not changed since the image was requested.
+create or replace function update_package_logo_image(
+    p_repository_id uuid,
+    p_name text,
+    p_logo_url text,
+    p_logo_image_id uuid
+)
+returns void as $$
+    update package set logo_image_id = p_logo_image_id
+    where repository_id = p_repository_id
+    and name = p_name
+    and logo_url = p_logo_url;
+$$ language sql;
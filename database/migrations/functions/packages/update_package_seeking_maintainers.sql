@@ -0,0 +1,28 @@
+-- update_package_seeking_maintainers sets whether the provided package is
+-- seeking new maintainers or not. When the package stops seeking maintainers,
+-- its pending adoption requests are removed.
+create or replace function update_package_seeking_maintainers(
+    p_requesting_user_id uuid,
+    p_package_id uuid,
+    p_seeking_maintainers boolean
+) returns void as $$
+declare
+    v_repository_name text;
+begin
+    select r.name into v_repository_name
+    from package p
+    join repository r using (repository_id)
+    where p.package_id = p_package_id;
+
+    if not user_owns_repository(p_requesting_user_id, v_repository_name) then
+        raise insufficient_privilege;
+    end if;
+
+    update package set seeking_maintainers = p_seeking_maintainers
+    where package_id = p_package_id;
+
+    if not p_seeking_maintainers then
+        delete from package_adoption_request where package_id = p_package_id;
+    end if;
+end
+$$ language plpgsql;
@@ -22,6 +22,21 @@ begin
         display_name,
         url,
         repository_kind_id,
+        tracking_interval,
+        quiet_hours,
+        private,
+        disabled,
+        skip_prereleases,
+        skip_deprecated,
+        metadata,
+        branch,
+        path,
+        tag_pattern,
+        auth_user,
+        auth_pass,
+        tls_ca_cert,
+        tls_client_cert,
+        tls_client_key,
         user_id,
         organization_id
     ) values (
@@ -29,6 +44,21 @@ begin
         nullif(p_repository->>'display_name', ''),
         p_repository->>'url',
         (p_repository->>'kind')::int,
+        nullif((p_repository->>'tracking_interval')::int, 0),
+        nullif(p_repository->'quiet_hours', 'null'::jsonb),
+        coalesce((p_repository->>'private')::boolean, false),
+        coalesce((p_repository->>'disabled')::boolean, false),
+        coalesce((p_repository->>'skip_prereleases')::boolean, false),
+        coalesce((p_repository->>'skip_deprecated')::boolean, false),
+        nullif(p_repository->'metadata', 'null'::jsonb),
+        nullif(p_repository->>'branch', ''),
+        nullif(p_repository->>'path', ''),
+        nullif(p_repository->>'tag_pattern', ''),
+        nullif(p_repository->>'auth_user', ''),
+        nullif(p_repository->>'auth_pass', ''),
+        nullif(p_repository->>'tls_ca_cert', ''),
+        nullif(p_repository->>'tls_client_cert', ''),
+        nullif(p_repository->>'tls_client_key', ''),
         v_owner_user_id,
         v_owner_organization_id
     );
@@ -0,0 +1,28 @@
+-- add_repository_collaborator adds a collaborator with the role provided to
+-- the given repository. If the user is already a collaborator of the
+-- repository, the role is updated.
+create or replace function add_repository_collaborator(
+    p_requesting_user_id uuid,
+    p_repository_name text,
+    p_user_alias text,
+    p_role text
+) returns void as $$
+declare
+    v_user_id uuid;
+begin
+    if not user_owns_repository(p_requesting_user_id, p_repository_name) then
+        raise insufficient_privilege;
+    end if;
+
+    select user_id into v_user_id from "user" where alias = p_user_alias;
+    if v_user_id is null then
+        raise 'user not found';
+    end if;
+
+    insert into repository_collaborator (repository_id, user_id, role)
+    select repository_id, v_user_id, p_role
+    from repository
+    where name = p_repository_name
+    on conflict (repository_id, user_id) do update set role = excluded.role;
+end
+$$ language plpgsql;
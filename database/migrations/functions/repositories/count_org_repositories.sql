@@ -0,0 +1,9 @@
+-- count_org_repositories returns the number of repositories that belong to
+-- the provided organization.
+create or replace function count_org_repositories(p_org_name text)
+returns int as $$
+    select count(*)::int
+    from repository r
+    join organization o using (organization_id)
+    where o.name = p_org_name;
+$$ language sql;
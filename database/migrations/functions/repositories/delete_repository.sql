@@ -21,6 +21,22 @@ begin
         raise insufficient_privilege;
     end if;
 
+    -- Track the deletion of all the repository packages versions
+    insert into package_change (
+        package_id,
+        package_name,
+        package_version,
+        repository_id,
+        repository_name,
+        repository_kind_id,
+        change_kind
+    )
+    select s.package_id, p.name, s.version, r.repository_id, r.name, r.repository_kind_id, 'deleted'
+    from snapshot s
+    join package p using (package_id)
+    join repository r using (repository_id)
+    where r.name = p_repository_name;
+
     delete from repository where name = p_repository_name;
 end
 $$ language plpgsql;
@@ -0,0 +1,17 @@
+-- delete_repository_collaborator removes a collaborator from the provided
+-- repository.
+create or replace function delete_repository_collaborator(
+    p_requesting_user_id uuid,
+    p_repository_name text,
+    p_user_alias text
+) returns void as $$
+begin
+    if not user_owns_repository(p_requesting_user_id, p_repository_name) then
+        raise insufficient_privilege;
+    end if;
+
+    delete from repository_collaborator
+    where repository_id = (select repository_id from repository where name = p_repository_name)
+    and user_id = (select user_id from "user" where alias = p_user_alias);
+end
+$$ language plpgsql;
@@ -6,7 +6,24 @@ returns setof json as $$
         'name', name,
         'display_name', display_name,
         'url', url,
-        'kind', repository_kind_id
+        'kind', repository_kind_id,
+        'disabled', disabled,
+        'skip_prereleases', skip_prereleases,
+        'skip_deprecated', skip_deprecated,
+        'tracking_interval', tracking_interval,
+        'quiet_hours', quiet_hours,
+        'private', private,
+        'metadata', metadata,
+        'branch', branch,
+        'path', path,
+        'tag_pattern', tag_pattern,
+        'last_tracking_ts', floor(extract(epoch from last_tracking_ts)),
+        'tracking_requested_ts', floor(extract(epoch from tracking_requested_at)),
+        'auth_user', auth_user,
+        'auth_pass', auth_pass,
+        'tls_ca_cert', tls_ca_cert,
+        'tls_client_cert', tls_client_cert,
+        'tls_client_key', tls_client_key
     )), '[]')
     from repository;
 $$ language sql;
@@ -10,7 +10,17 @@ returns setof json as $$
         'url', r.url,
         'last_tracking_ts', floor(extract(epoch from r.last_tracking_ts)),
         'last_tracking_errors', r.last_tracking_errors,
-        'kind', r.repository_kind_id
+        'kind', r.repository_kind_id,
+        'disabled', r.disabled,
+        'skip_prereleases', r.skip_prereleases,
+        'skip_deprecated', r.skip_deprecated,
+        'tracking_interval', r.tracking_interval,
+        'quiet_hours', r.quiet_hours,
+        'private', r.private,
+        'metadata', r.metadata,
+        'branch', r.branch,
+        'path', r.path,
+        'tag_pattern', r.tag_pattern
     )), '[]')
     from repository r
     join organization o using (organization_id)
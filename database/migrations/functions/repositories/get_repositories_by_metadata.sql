@@ -0,0 +1,24 @@
+-- get_repositories_by_metadata returns all available repositories whose
+-- metadata contains all the key/values provided, as a json array.
+create or replace function get_repositories_by_metadata(p_metadata jsonb)
+returns setof json as $$
+    select coalesce(json_agg(json_build_object(
+        'repository_id', r.repository_id,
+        'name', r.name,
+        'display_name', r.display_name,
+        'url', r.url,
+        'kind', r.repository_kind_id,
+        'disabled', r.disabled,
+        'tracking_interval', r.tracking_interval,
+        'quiet_hours', r.quiet_hours,
+        'private', r.private,
+        'last_tracking_ts', floor(extract(epoch from r.last_tracking_ts)),
+        'metadata', r.metadata,
+        'user_alias', u.alias,
+        'organization_name', o.name
+    )), '[]')
+    from repository r
+    left join "user" u using (user_id)
+    left join organization o using (organization_id)
+    where r.metadata @> p_metadata;
+$$ language sql;
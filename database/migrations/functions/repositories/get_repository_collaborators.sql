@@ -0,0 +1,26 @@
+-- get_repository_collaborators returns the collaborators of the repository
+-- provided as a json array.
+create or replace function get_repository_collaborators(p_requesting_user_id uuid, p_repository_name text)
+returns setof json as $$
+begin
+    if not user_owns_repository(p_requesting_user_id, p_repository_name) then
+        raise insufficient_privilege;
+    end if;
+
+    return query
+    select json_agg(json_build_object(
+        'alias', c.alias,
+        'first_name', c.first_name,
+        'last_name', c.last_name,
+        'role', c.role
+    ))
+    from (
+        select u.alias, u.first_name, u.last_name, rc.role
+        from "user" u
+        join repository_collaborator rc using (user_id)
+        join repository r using (repository_id)
+        where r.name = p_repository_name
+        order by u.alias asc
+    ) c;
+end
+$$ language plpgsql;
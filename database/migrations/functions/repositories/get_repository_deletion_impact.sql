@@ -0,0 +1,64 @@
+-- get_repository_deletion_impact returns what would be affected by the
+-- deletion of the provided repository as a json object, allowing users to
+-- review it before deleting the repository. The user doing the request must
+-- be allowed to delete the repository.
+create or replace function get_repository_deletion_impact(p_user_id uuid, p_repository_name text)
+returns setof json as $$
+declare
+    v_repository_id uuid;
+    v_owner_user_id uuid;
+    v_owner_organization_name text;
+begin
+    -- Get user or organization owning the repository
+    select r.repository_id, r.user_id, o.name
+    into v_repository_id, v_owner_user_id, v_owner_organization_name
+    from repository r
+    left join organization o using (organization_id)
+    where r.name = p_repository_name;
+
+    -- Check if the user doing the request is the owner or belongs to the
+    -- organization which owns it
+    if v_owner_organization_name is not null then
+        if not user_belongs_to_organization(p_user_id, v_owner_organization_name) then
+            raise insufficient_privilege;
+        end if;
+    elsif v_owner_user_id <> p_user_id then
+        raise insufficient_privilege;
+    end if;
+
+    return query
+    select json_build_object(
+        'packages', (
+            select count(*)
+            from package
+            where repository_id = r.repository_id
+        ),
+        'packages_versions', (
+            select count(*)
+            from snapshot s
+            join package p using (package_id)
+            where p.repository_id = r.repository_id
+        ),
+        'subscribers', (
+            select count(distinct s.user_id)
+            from subscription s
+            join package p using (package_id)
+            where p.repository_id = r.repository_id
+        ),
+        'webhooks', (
+            select count(distinct wp.webhook_id)
+            from webhook__package wp
+            join package p using (package_id)
+            where p.repository_id = r.repository_id
+        ),
+        'stars', (
+            select count(*)
+            from user_starred_package usp
+            join package p using (package_id)
+            where p.repository_id = r.repository_id
+        )
+    )
+    from repository r
+    where r.repository_id = v_repository_id;
+end
+$$ language plpgsql;
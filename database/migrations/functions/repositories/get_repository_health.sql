@@ -0,0 +1,54 @@
+-- get_repository_health returns some indicators about the health of the
+-- repository provided as a json object, along with a score (0-100) combining
+-- them:
+--
+-- - tracking_success_rate: ratio of tracking runs that completed without errors
+-- - freshness: decays linearly from 1 to 0 during the week after the last
+--   tracking
+-- - broken_links_rate: ratio of packages whose logo could not be fetched
+-- - signed_rate: ratio of packages whose latest version is signed
+--
+create or replace function get_repository_health(p_repository_name text)
+returns setof json as $$
+    select json_build_object(
+        'tracking_runs', r.tracking_runs,
+        'tracking_success_rate', h.tracking_success_rate,
+        'last_tracking_ts', floor(extract(epoch from r.last_tracking_ts)),
+        'freshness', h.freshness,
+        'packages', h.packages,
+        'broken_links_rate', h.broken_links_rate,
+        'signed_rate', h.signed_rate,
+        'score', round(100 * (
+            0.4 * coalesce(h.tracking_success_rate, 0) +
+            0.3 * h.freshness +
+            0.15 * (1 - coalesce(h.broken_links_rate, 0)) +
+            0.15 * coalesce(h.signed_rate, 0)
+        ))
+    )
+    from repository r
+    cross join lateral (
+        select
+            round(
+                (r.tracking_runs - r.tracking_failed_runs)::numeric / nullif(r.tracking_runs, 0),
+                2
+            ) as tracking_success_rate,
+            round(
+                greatest(0, 1 - extract(epoch from current_timestamp - r.last_tracking_ts) / 604800)::numeric,
+                2
+            ) as freshness,
+            count(p.package_id) as packages,
+            round(
+                count(*) filter (where p.logo_url is not null and p.logo_image_id is null)::numeric /
+                nullif(count(p.package_id), 0),
+                2
+            ) as broken_links_rate,
+            round(
+                count(*) filter (where s.signed = true)::numeric / nullif(count(p.package_id), 0),
+                2
+            ) as signed_rate
+        from package p
+        join snapshot s on s.package_id = p.package_id and s.version = p.latest_version
+        where p.repository_id = r.repository_id
+    ) h
+    where r.name = p_repository_name;
+$$ language sql;
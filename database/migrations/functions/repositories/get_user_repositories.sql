@@ -8,6 +8,16 @@ returns setof json as $$
         'display_name', display_name,
         'url', url,
         'kind', repository_kind_id,
+        'disabled', disabled,
+        'skip_prereleases', skip_prereleases,
+        'skip_deprecated', skip_deprecated,
+        'tracking_interval', tracking_interval,
+        'quiet_hours', quiet_hours,
+        'private', private,
+        'metadata', metadata,
+        'branch', branch,
+        'path', path,
+        'tag_pattern', tag_pattern,
         'last_tracking_ts', floor(extract(epoch from last_tracking_ts)),
         'last_tracking_errors', last_tracking_errors
     )), '[]')
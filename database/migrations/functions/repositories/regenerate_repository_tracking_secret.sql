@@ -0,0 +1,19 @@
+-- regenerate_repository_tracking_secret generates a new secret that can be
+-- used to request the tracking of the provided repository, returning it. Only
+-- a hash of the secret is stored, so it must be regenerated if lost.
+create or replace function regenerate_repository_tracking_secret(p_user_id uuid, p_repository_name text)
+returns text as $$
+declare
+    v_secret text := encode(gen_random_bytes(32), 'hex');
+begin
+    if not user_has_repository_write_access(p_user_id, p_repository_name) then
+        raise insufficient_privilege;
+    end if;
+
+    update repository set
+        tracking_secret = digest(v_secret, 'sha256')
+    where name = p_repository_name;
+
+    return v_secret;
+end
+$$ language plpgsql;
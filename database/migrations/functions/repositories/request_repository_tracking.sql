@@ -0,0 +1,16 @@
+-- request_repository_tracking registers a tracking request for the provided
+-- repository, so that it's tracked by the next tracker run processing tracking
+-- requests regardless of its tracking interval. It returns false if the secret
+-- provided is not valid.
+create or replace function request_repository_tracking(p_repository_name text, p_secret text)
+returns boolean as $$
+begin
+    update repository set
+        tracking_requested_at = current_timestamp
+    where name = p_repository_name
+    and tracking_secret = digest(p_secret, 'sha256')
+    and disabled = false;
+
+    return found;
+end
+$$ language plpgsql;
@@ -1,29 +1,56 @@
--- updates_repository updates the provided repository in the database.
+-- updates_repository updates the provided repository in the database. The
+-- stored password and tls client key are kept when the special value = is
+-- provided as auth_pass or tls_client_key respectively. Only the repository
+-- owners can change its url, visibility or disabled status.
 create or replace function update_repository(p_user_id uuid, p_repository jsonb)
 returns void as $$
 declare
-    v_owner_user_id uuid;
-    v_owner_organization_name text;
+    v_url text;
+    v_private boolean;
+    v_disabled boolean;
 begin
-    -- Get user or organization owning the repository
-    select r.user_id, o.name into v_owner_user_id, v_owner_organization_name
-    from repository r
-    left join organization o using (organization_id)
-    where r.name = p_repository->>'name';
+    -- Check if the user doing the request is the owner, belongs to the
+    -- organization which owns it or is a collaborator with write access
+    if not user_has_repository_write_access(p_user_id, p_repository->>'name') then
+        raise insufficient_privilege;
+    end if;
 
-    -- Check if the user doing the request is the owner or belongs to the
-    -- organization which owns it
-    if v_owner_organization_name is not null then
-        if not user_belongs_to_organization(p_user_id, v_owner_organization_name) then
+    -- Collaborators cannot change the url, visibility or disabled status
+    if not user_owns_repository(p_user_id, p_repository->>'name') then
+        select url, private, disabled into v_url, v_private, v_disabled
+        from repository
+        where name = p_repository->>'name';
+        if p_repository->>'url' is distinct from v_url
+        or coalesce((p_repository->>'private')::boolean, false) <> v_private
+        or coalesce((p_repository->>'disabled')::boolean, false) <> v_disabled then
             raise insufficient_privilege;
         end if;
-    elsif v_owner_user_id <> p_user_id then
-        raise insufficient_privilege;
     end if;
 
     update repository set
         display_name = nullif(p_repository->>'display_name', ''),
-        url = p_repository->>'url'
+        url = p_repository->>'url',
+        tracking_interval = nullif((p_repository->>'tracking_interval')::int, 0),
+        quiet_hours = nullif(p_repository->'quiet_hours', 'null'::jsonb),
+        private = coalesce((p_repository->>'private')::boolean, false),
+        disabled = coalesce((p_repository->>'disabled')::boolean, false),
+        skip_prereleases = coalesce((p_repository->>'skip_prereleases')::boolean, false),
+        skip_deprecated = coalesce((p_repository->>'skip_deprecated')::boolean, false),
+        metadata = nullif(p_repository->'metadata', 'null'::jsonb),
+        branch = nullif(p_repository->>'branch', ''),
+        path = nullif(p_repository->>'path', ''),
+        tag_pattern = nullif(p_repository->>'tag_pattern', ''),
+        auth_user = nullif(p_repository->>'auth_user', ''),
+        auth_pass = case
+            when p_repository->>'auth_pass' = '=' then auth_pass
+            else nullif(p_repository->>'auth_pass', '')
+        end,
+        tls_ca_cert = nullif(p_repository->>'tls_ca_cert', ''),
+        tls_client_cert = nullif(p_repository->>'tls_client_cert', ''),
+        tls_client_key = case
+            when p_repository->>'tls_client_key' = '=' then tls_client_key
+            else nullif(p_repository->>'tls_client_key', '')
+        end
     where name = p_repository->>'name';
 end
 $$ language plpgsql;
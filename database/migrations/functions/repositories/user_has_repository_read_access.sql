@@ -0,0 +1,34 @@
+-- user_has_repository_read_access checks if a user has read access to the
+-- provided repository. Users have access to the repositories they own, to the
+-- ones owned by the organizations they belong to, to the ones owned by
+-- organizations that have shared their repositories with them and to the ones
+-- they collaborate on.
+create or replace function user_has_repository_read_access(p_user_id uuid, p_repository_id uuid)
+returns boolean as $$
+    select exists (
+        select 1
+        from repository r
+        where r.repository_id = p_repository_id
+        and (
+            r.user_id = p_user_id
+            or r.organization_id in (
+                select uo.organization_id
+                from user__organization uo
+                where uo.user_id = p_user_id
+                and uo.confirmed = true
+            )
+            or r.organization_id in (
+                select os.organization_id
+                from organization_share os
+                join user__organization uo on uo.organization_id = os.shared_with_organization_id
+                where uo.user_id = p_user_id
+                and uo.confirmed = true
+            )
+            or r.repository_id in (
+                select rc.repository_id
+                from repository_collaborator rc
+                where rc.user_id = p_user_id
+            )
+        )
+    );
+$$ language sql;
@@ -0,0 +1,15 @@
+-- user_has_repository_write_access checks if a user is allowed to update the
+-- provided repository. Owners and collaborators with the write role are.
+create or replace function user_has_repository_write_access(p_user_id uuid, p_repository_name text)
+returns boolean as $$
+begin
+    return user_owns_repository(p_user_id, p_repository_name) or exists (
+        select 1
+        from repository_collaborator rc
+        join repository r using (repository_id)
+        where r.name = p_repository_name
+        and rc.user_id = p_user_id
+        and rc.role = 'write'
+    );
+end
+$$ language plpgsql;
@@ -0,0 +1,19 @@
+-- user_owns_repository checks if a user owns the provided repository, either
+-- directly or by belonging to the organization which owns it.
+create or replace function user_owns_repository(p_user_id uuid, p_repository_name text)
+returns boolean as $$
+    select exists (
+        select 1
+        from repository r
+        where r.name = p_repository_name
+        and (
+            r.user_id = p_user_id
+            or r.organization_id in (
+                select uo.organization_id
+                from user__organization uo
+                where uo.user_id = p_user_id
+                and uo.confirmed = true
+            )
+        )
+    );
+$$ language sql;
@@ -0,0 +1,37 @@
+-- prune_data deletes the data of the category provided older than the maximum
+-- age given, returning the number of rows affected.
+create or replace function prune_data(p_category text, p_max_age interval)
+returns bigint as $$
+declare
+    v_rows_affected bigint;
+begin
+    case p_category
+    when 'notifications' then
+        delete from notification
+        where processed = true
+        and processed_at < current_timestamp - p_max_age;
+    when 'events' then
+        delete from event e
+        where e.processed = true
+        and e.processed_at < current_timestamp - p_max_age
+        and not exists (
+            select 1 from notification n where n.event_id = e.event_id
+        );
+    when 'packages_changes' then
+        delete from package_change
+        where created_at < current_timestamp - p_max_age;
+    when 'packages_tombstones' then
+        delete from package_tombstone
+        where created_at < current_timestamp - p_max_age;
+    when 'tracking_errors' then
+        update repository set last_tracking_errors = null
+        where last_tracking_errors is not null
+        and last_tracking_ts < current_timestamp - p_max_age;
+    else
+        raise 'invalid data category: %', p_category;
+    end case;
+
+    get diagnostics v_rows_affected = row_count;
+    return v_rows_affected;
+end
+$$ language plpgsql;
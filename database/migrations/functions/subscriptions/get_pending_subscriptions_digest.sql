@@ -0,0 +1,35 @@
+-- get_pending_subscriptions_digest returns a user whose subscriptions digest
+-- is due if available. Digests are due when the user has enabled them, is
+-- subscribed to some packages and the interval provided has passed since the
+-- last one was generated. They are only delivered between the hours provided
+-- (from hour included, to hour excluded) of the user's local time, using UTC
+-- when the user has not set a timezone. The digest covers the changes
+-- published since the last one, or during the interval provided when no digest
+-- has been generated yet.
+create or replace function get_pending_subscriptions_digest(
+    p_interval interval,
+    p_from_hour int,
+    p_to_hour int
+) returns setof json as $$
+    select json_build_object(
+        'user', jsonb_strip_nulls(jsonb_build_object(
+            'user_id', u.user_id,
+            'email', u.email,
+            'locale', u.locale,
+            'timezone', u.timezone
+        )),
+        'since', floor(extract(epoch from coalesce(
+            u.last_subscriptions_digest_at,
+            current_timestamp - p_interval
+        )))
+    )
+    from "user" u
+    where u.subscriptions_digest = true
+    and u.email_verified = true
+    and exists (select 1 from subscription s where s.user_id = u.user_id)
+    and (u.last_subscriptions_digest_at is null or u.last_subscriptions_digest_at < current_timestamp - p_interval)
+    and extract(hour from current_timestamp at time zone coalesce(u.timezone, 'UTC')) >= p_from_hour
+    and extract(hour from current_timestamp at time zone coalesce(u.timezone, 'UTC')) < p_to_hour
+    for update of u skip locked
+    limit 1;
+$$ language sql;
@@ -0,0 +1,63 @@
+-- get_user_subscriptions_changes returns the changes introduced in the
+-- versions of the packages the provided user is subscribed to as a json
+-- object, newest first. Only the versions which declare some changes are
+-- included. The total number of entries available is returned as well, so
+-- that clients can paginate over them.
+create or replace function get_user_subscriptions_changes(p_user_id uuid, p_limit int, p_offset int)
+returns setof json as $$
+    with user_changes as (
+        select
+            p.package_id,
+            p.name,
+            p.normalized_name,
+            p.logo_image_id,
+            s.version,
+            s.changes,
+            s.created_at,
+            r.repository_kind_id,
+            r.name as repository_name,
+            r.display_name as repository_display_name,
+            u.alias as user_alias,
+            o.name as organization_name,
+            o.display_name as organization_display_name
+        from snapshot s
+        join package p using (package_id)
+        join repository r using (repository_id)
+        left join "user" u using (user_id)
+        left join organization o using (organization_id)
+        where s.package_id in (
+            select distinct(package_id) from subscription where user_id = p_user_id
+        )
+        and jsonb_typeof(s.changes) = 'array'
+        and jsonb_array_length(s.changes) > 0
+    )
+    select json_build_object(
+        'changes', (
+            select coalesce(json_agg(json_build_object(
+                'package_id', package_id,
+                'name', name,
+                'normalized_name', normalized_name,
+                'logo_image_id', logo_image_id,
+                'version', version,
+                'changes', changes,
+                'created_at', floor(extract(epoch from created_at)),
+                'repository', jsonb_build_object(
+                    'kind', repository_kind_id,
+                    'name', repository_name,
+                    'display_name', repository_display_name,
+                    'user_alias', user_alias,
+                    'organization_name', organization_name,
+                    'organization_display_name', organization_display_name
+                )
+            )), '[]')
+            from (
+                select *
+                from user_changes
+                order by created_at desc, normalized_name asc, version desc
+                limit p_limit
+                offset p_offset
+            ) uc
+        ),
+        'total', (select count(*) from user_changes)
+    );
+$$ language sql;
@@ -0,0 +1,8 @@
+-- update_subscriptions_last_digest records that the subscriptions digest of
+-- the provided user has just been generated.
+create or replace function update_subscriptions_last_digest(p_user_id uuid)
+returns void as $$
+    update "user" set
+        last_subscriptions_digest_at = current_timestamp
+    where user_id = p_user_id;
+$$ language sql;
@@ -6,7 +6,11 @@ returns setof json as $$
         'first_name', u.first_name,
         'last_name', u.last_name,
         'email', u.email,
-        'profile_image_id', u.profile_image_id
+        'profile_image_id', u.profile_image_id,
+        'locale', u.locale,
+        'timezone', u.timezone,
+        'subscriptions_digest', u.subscriptions_digest,
+        'email_suppressed', is_email_suppressed(u.email)
     )
     from "user" u
     where u.user_id = p_user_id;
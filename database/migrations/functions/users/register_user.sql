@@ -25,7 +25,9 @@ begin
         email,
         email_verified,
         password,
-        profile_image_id
+        profile_image_id,
+        locale,
+        timezone
     ) values (
         p_user->>'alias',
         nullif(p_user->>'first_name', ''),
@@ -33,9 +35,17 @@ begin
         p_user->>'email',
         (p_user->>'email_verified')::boolean,
         nullif(p_user->>'password', ''),
-        nullif(p_user->>'profile_image_id', '')::uuid
+        nullif(p_user->>'profile_image_id', '')::uuid,
+        nullif(p_user->>'locale', ''),
+        nullif(p_user->>'timezone', '')
     ) returning user_id into v_user_id;
 
+    -- Join the organizations that have auto join enabled for the email domain
+    -- when the email has already been verified
+    if (p_user->>'email_verified')::boolean then
+        perform join_organizations_by_email_domain(v_user_id);
+    end if;
+
     -- Register email verification code
     insert into email_verification_code (user_id)
     values (v_user_id)
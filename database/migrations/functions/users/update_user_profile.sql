@@ -6,6 +6,9 @@ returns void as $$
         alias = p_user->>'alias',
         first_name = nullif(p_user->>'first_name', ''),
         last_name = nullif(p_user->>'last_name', ''),
-        profile_image_id = nullif(p_user->>'profile_image_id', '')::uuid
+        profile_image_id = nullif(p_user->>'profile_image_id', '')::uuid,
+        locale = nullif(p_user->>'locale', ''),
+        timezone = nullif(p_user->>'timezone', ''),
+        subscriptions_digest = coalesce((p_user->>'subscriptions_digest')::boolean, false)
     where user_id = p_requesting_user_id;
 $$ language sql;
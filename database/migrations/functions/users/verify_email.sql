@@ -2,6 +2,8 @@
 -- returning true if the email was verified successfully or false otherwise.
 create or replace function verify_email(p_code uuid)
 returns boolean as $$
+declare
+    v_user_id uuid;
 begin
     -- Check if email verification code exists and is not expired
     perform from email_verification_code
@@ -17,7 +19,11 @@ begin
     where user_id = (
         select user_id from email_verification_code
         where email_verification_code_id = p_code
-    );
+    )
+    returning user_id into v_user_id;
+
+    -- Join the organizations that have auto join enabled for the email domain
+    perform join_organizations_by_email_domain(v_user_id);
 
     -- Delete email verification code
     delete from email_verification_code
@@ -0,0 +1,8 @@
+-- count_user_webhooks returns the number of webhooks that belong to the
+-- provided user.
+create or replace function count_user_webhooks(p_user_id uuid)
+returns int as $$
+    select count(*)::int
+    from webhook
+    where user_id = p_user_id;
+$$ language sql;
@@ -0,0 +1,5 @@
+alter table repository add column tracking_interval integer check (tracking_interval > 0);
+
+---- create above / drop below ----
+
+alter table repository drop column tracking_interval;
@@ -0,0 +1,11 @@
+alter table snapshot add column capabilities text check (capabilities in (
+    'Basic Install',
+    'Seamless Upgrades',
+    'Full Lifecycle',
+    'Deep Insights',
+    'Auto Pilot'
+));
+
+---- create above / drop below ----
+
+alter table snapshot drop column capabilities;
@@ -0,0 +1,17 @@
+create table if not exists package_change (
+    package_change_id bigserial primary key,
+    package_id uuid not null,
+    package_name text not null check (package_name <> ''),
+    package_version text not null check (package_version <> ''),
+    repository_id uuid not null,
+    repository_name text not null check (repository_name <> ''),
+    repository_kind_id integer not null references repository_kind on delete restrict,
+    change_kind text not null check (change_kind in ('created', 'updated', 'deleted')),
+    created_at timestamptz default current_timestamp not null
+);
+
+create index package_change_created_at_idx on package_change (created_at);
+
+---- create above / drop below ----
+
+drop table if exists package_change;
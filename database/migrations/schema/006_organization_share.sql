@@ -0,0 +1,13 @@
+create table if not exists organization_share (
+    organization_id uuid not null references organization on delete cascade,
+    shared_with_organization_id uuid not null references organization on delete cascade,
+    created_at timestamptz default current_timestamp not null,
+    primary key (organization_id, shared_with_organization_id),
+    check (organization_id <> shared_with_organization_id)
+);
+
+create index organization_share_shared_with_organization_id_idx on organization_share (shared_with_organization_id);
+
+---- create above / drop below ----
+
+drop table if exists organization_share;
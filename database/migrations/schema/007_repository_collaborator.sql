@@ -0,0 +1,13 @@
+create table if not exists repository_collaborator (
+    repository_id uuid not null references repository on delete cascade,
+    user_id uuid not null references "user" on delete cascade,
+    role text not null check (role in ('read', 'write')),
+    created_at timestamptz default current_timestamp not null,
+    primary key (repository_id, user_id)
+);
+
+create index repository_collaborator_user_id_idx on repository_collaborator (user_id);
+
+---- create above / drop below ----
+
+drop table if exists repository_collaborator;
@@ -0,0 +1,17 @@
+create table if not exists organization_domain (
+    organization_domain_id uuid primary key default gen_random_uuid(),
+    organization_id uuid not null references organization on delete cascade,
+    domain text not null check (domain <> ''),
+    verification_token text not null default encode(gen_random_bytes(16), 'hex'),
+    verified boolean not null default false,
+    auto_join boolean not null default false,
+    last_check_ts timestamptz,
+    created_at timestamptz default current_timestamp not null,
+    unique (organization_id, domain)
+);
+
+create index organization_domain_domain_idx on organization_domain (domain);
+
+---- create above / drop below ----
+
+drop table if exists organization_domain;
@@ -0,0 +1,33 @@
+create table if not exists package_statement (
+    package_statement_id uuid primary key default gen_random_uuid(),
+    package_id uuid not null references package on delete cascade,
+    kind text not null check (kind <> ''),
+    content text not null check (content <> ''),
+    signature text not null check (signature <> ''),
+    signature_algorithm text not null check (signature_algorithm <> ''),
+    signature_key_id text,
+    user_id uuid references "user" on delete set null,
+    created_at timestamptz default current_timestamp not null,
+    updated_at timestamptz default current_timestamp not null,
+    unique (package_id, kind)
+);
+
+insert into event_kind values (2, 'Package statement change');
+
+-- Statements can change several times for the same package version, so the
+-- uniqueness of events only applies to the other kinds of events.
+alter table event drop constraint if exists event_package_id_package_version_event_kind_id_key;
+create unique index event_package_id_package_version_event_kind_id_key
+on event (package_id, package_version, event_kind_id)
+where event_kind_id <> 2;
+
+---- create above / drop below ----
+
+drop index if exists event_package_id_package_version_event_kind_id_key;
+delete from event where event_kind_id = 2;
+alter table event add constraint event_package_id_package_version_event_kind_id_key
+unique (package_id, package_version, event_kind_id);
+delete from subscription where event_kind_id = 2;
+delete from webhook__event_kind where event_kind_id = 2;
+delete from event_kind where event_kind_id = 2;
+drop table if exists package_statement;
@@ -0,0 +1,5 @@
+alter table "user" add column locale text check (locale <> '');
+
+---- create above / drop below ----
+
+alter table "user" drop column locale;
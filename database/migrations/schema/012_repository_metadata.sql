@@ -0,0 +1,7 @@
+alter table repository add column metadata jsonb check (jsonb_typeof(metadata) = 'object');
+create index repository_metadata_idx on repository using gin (metadata jsonb_path_ops);
+
+---- create above / drop below ----
+
+drop index repository_metadata_idx;
+alter table repository drop column metadata;
@@ -0,0 +1,15 @@
+alter table package add column seeking_maintainers boolean not null default false;
+
+create table if not exists package_adoption_request (
+    package_adoption_request_id uuid primary key default gen_random_uuid(),
+    package_id uuid not null references package on delete cascade,
+    user_id uuid not null references "user" on delete cascade,
+    message text not null check (message <> ''),
+    created_at timestamptz default current_timestamp not null,
+    unique (package_id, user_id)
+);
+
+---- create above / drop below ----
+
+drop table if exists package_adoption_request;
+alter table package drop column seeking_maintainers;
@@ -0,0 +1,7 @@
+alter table snapshot add column sign_key jsonb;
+alter table snapshot add column signature_verified boolean;
+
+---- create above / drop below ----
+
+alter table snapshot drop column signature_verified;
+alter table snapshot drop column sign_key;
@@ -0,0 +1,7 @@
+alter table snapshot add column changes jsonb;
+alter table snapshot add column containers_images jsonb;
+
+---- create above / drop below ----
+
+alter table snapshot drop column containers_images;
+alter table snapshot drop column changes;
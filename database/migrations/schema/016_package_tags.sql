@@ -0,0 +1,18 @@
+create table if not exists package_tag (
+    package_id uuid not null references package on delete cascade,
+    name text not null check (name <> ''),
+    primary key (package_id, name)
+);
+
+create index package_tag_name_idx on package_tag (name);
+
+insert into package_tag (package_id, name)
+select distinct p.package_id, lower(trim(k))
+from package p
+join snapshot s on s.package_id = p.package_id and s.version = p.latest_version
+cross join unnest(s.keywords) as k
+where trim(k) <> '';
+
+---- create above / drop below ----
+
+drop table if exists package_tag;
@@ -0,0 +1,5 @@
+alter table api_key add column repository_id uuid references repository on delete cascade;
+
+---- create above / drop below ----
+
+alter table api_key drop column repository_id;
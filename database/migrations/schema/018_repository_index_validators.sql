@@ -0,0 +1,7 @@
+alter table repository add column index_etag text;
+alter table repository add column index_last_modified text;
+
+---- create above / drop below ----
+
+alter table repository drop column index_last_modified;
+alter table repository drop column index_etag;
@@ -0,0 +1,7 @@
+alter table repository add column tracking_secret bytea;
+alter table repository add column tracking_requested_at timestamptz;
+
+---- create above / drop below ----
+
+alter table repository drop column tracking_requested_at;
+alter table repository drop column tracking_secret;
@@ -0,0 +1,7 @@
+alter table repository add column auth_user text;
+alter table repository add column auth_pass text;
+
+---- create above / drop below ----
+
+alter table repository drop column auth_pass;
+alter table repository drop column auth_user;
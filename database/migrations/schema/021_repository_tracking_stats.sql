@@ -0,0 +1,7 @@
+alter table repository add column tracking_runs integer not null default 0;
+alter table repository add column tracking_failed_runs integer not null default 0;
+
+---- create above / drop below ----
+
+alter table repository drop column tracking_failed_runs;
+alter table repository drop column tracking_runs;
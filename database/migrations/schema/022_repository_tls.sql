@@ -0,0 +1,9 @@
+alter table repository add column tls_ca_cert text;
+alter table repository add column tls_client_cert text;
+alter table repository add column tls_client_key text;
+
+---- create above / drop below ----
+
+alter table repository drop column tls_client_key;
+alter table repository drop column tls_client_cert;
+alter table repository drop column tls_ca_cert;
@@ -0,0 +1,12 @@
+create table if not exists organization_featured_package (
+    organization_id uuid not null references organization on delete cascade,
+    package_id uuid not null references package on delete cascade,
+    position integer not null check (position >= 0),
+    primary key (organization_id, package_id)
+);
+
+create index organization_featured_package_package_id_idx on organization_featured_package (package_id);
+
+---- create above / drop below ----
+
+drop table if exists organization_featured_package;
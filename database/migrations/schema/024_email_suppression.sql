@@ -0,0 +1,9 @@
+create table if not exists email_suppression (
+    email text primary key check (email <> ''),
+    reason text not null check (reason in ('bounce', 'complaint')),
+    created_at timestamptz default current_timestamp not null
+);
+
+---- create above / drop below ----
+
+drop table if exists email_suppression;
@@ -0,0 +1,12 @@
+create table if not exists api_key_usage (
+    api_key_id uuid not null references api_key on delete cascade,
+    day date not null,
+    endpoint text not null check (endpoint <> ''),
+    requests integer not null default 0,
+    rate_limited_requests integer not null default 0,
+    primary key (api_key_id, day, endpoint)
+);
+
+---- create above / drop below ----
+
+drop table if exists api_key_usage;
@@ -0,0 +1,5 @@
+alter table snapshot add column changelog text check (changelog <> '');
+
+---- create above / drop below ----
+
+alter table snapshot drop column changelog;
@@ -0,0 +1,7 @@
+alter table repository add column skip_prereleases boolean not null default false;
+alter table repository add column skip_deprecated boolean not null default false;
+
+---- create above / drop below ----
+
+alter table repository drop column skip_deprecated;
+alter table repository drop column skip_prereleases;
@@ -0,0 +1,18 @@
+create table if not exists cluster_inventory (
+    cluster_inventory_id uuid primary key default gen_random_uuid(),
+    name text not null check (name <> ''),
+    releases jsonb not null,
+    email_report boolean not null default false,
+    webhook_url text check (webhook_url <> ''),
+    last_report_at timestamptz,
+    created_at timestamptz default current_timestamp not null,
+    updated_at timestamptz default current_timestamp not null,
+    user_id uuid not null references "user" on delete cascade,
+    unique (user_id, name)
+);
+
+create index cluster_inventory_user_id_idx on cluster_inventory (user_id);
+
+---- create above / drop below ----
+
+drop table if exists cluster_inventory;
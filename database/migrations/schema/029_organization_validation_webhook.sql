@@ -0,0 +1,7 @@
+alter table organization add column validation_webhook_url text check (validation_webhook_url <> '');
+alter table organization add column validation_webhook_secret text check (validation_webhook_secret <> '');
+
+---- create above / drop below ----
+
+alter table organization drop column validation_webhook_secret;
+alter table organization drop column validation_webhook_url;
@@ -0,0 +1,9 @@
+alter table repository add column branch text;
+alter table repository add column path text;
+alter table repository add column tag_pattern text;
+
+---- create above / drop below ----
+
+alter table repository drop column tag_pattern;
+alter table repository drop column path;
+alter table repository drop column branch;
@@ -0,0 +1,5 @@
+alter table snapshot add column kube_version text;
+
+---- create above / drop below ----
+
+alter table snapshot drop column kube_version;
@@ -0,0 +1,5 @@
+alter table snapshot add column chart_type text;
+
+---- create above / drop below ----
+
+alter table snapshot drop column chart_type;
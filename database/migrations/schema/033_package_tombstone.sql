@@ -0,0 +1,16 @@
+create table if not exists package_tombstone (
+    package_tombstone_id bigserial primary key,
+    repository_id uuid not null references repository on delete cascade,
+    package_name text not null check (package_name <> ''),
+    package_version text not null check (package_version <> ''),
+    package jsonb not null,
+    snapshot jsonb not null,
+    created_at timestamptz default current_timestamp not null,
+    unique (repository_id, package_name, package_version)
+);
+
+create index package_tombstone_created_at_idx on package_tombstone (created_at);
+
+---- create above / drop below ----
+
+drop table if exists package_tombstone;
@@ -0,0 +1,5 @@
+alter table repository add column quiet_hours jsonb;
+
+---- create above / drop below ----
+
+alter table repository drop column quiet_hours;
@@ -0,0 +1,5 @@
+alter table repository add column private boolean not null default false;
+
+---- create above / drop below ----
+
+alter table repository drop column private;
@@ -0,0 +1,5 @@
+alter table "user" add column timezone text check (timezone <> '');
+
+---- create above / drop below ----
+
+alter table "user" drop column timezone;
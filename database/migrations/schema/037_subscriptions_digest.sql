@@ -0,0 +1,7 @@
+alter table "user" add column subscriptions_digest boolean not null default false;
+alter table "user" add column last_subscriptions_digest_at timestamptz;
+
+---- create above / drop below ----
+
+alter table "user" drop column last_subscriptions_digest_at;
+alter table "user" drop column subscriptions_digest;
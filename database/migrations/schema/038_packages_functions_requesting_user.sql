@@ -0,0 +1,8 @@
+drop function if exists get_package_project(uuid);
+drop function if exists get_package_statements(uuid);
+drop function if exists get_packages_by_owner(text);
+drop function if exists match_installed_packages(jsonb);
+
+---- create above / drop below ----
+
+-- Nothing to do
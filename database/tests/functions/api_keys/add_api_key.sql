@@ -1,13 +1,19 @@
 -- Start transaction and plan tests
 begin;
-select plan(2);
+select plan(4);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
 
 -- Seed some data
 insert into "user" (user_id, alias, email)
 values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email)
+values (:'user2ID', 'user2', 'user2@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
 
 -- Add api key
 select add_api_key('
@@ -39,6 +45,47 @@ select results_eq(
     'Api key should exist'
 );
 
+-- Add repository scoped api key
+select add_api_key('
+{
+    "name": "apikey2",
+    "user_id": "00000000-0000-0000-0000-000000000001",
+    "repository_id": "00000000-0000-0000-0000-000000000001"
+}
+'::jsonb);
+select results_eq(
+    $$
+        select
+            name,
+            user_id,
+            repository_id
+        from api_key
+        where name = 'apikey2'
+    $$,
+    $$
+        values (
+            'apikey2',
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            '00000000-0000-0000-0000-000000000001'::uuid
+        )
+    $$,
+    'Repository scoped api key should exist'
+);
+select throws_ok(
+    $$
+        select add_api_key('
+        {
+            "name": "apikey3",
+            "user_id": "00000000-0000-0000-0000-000000000002",
+            "repository_id": "00000000-0000-0000-0000-000000000001"
+        }
+        '::jsonb)
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Api key should not be scoped to a repository the user cannot write to'
+);
+
 -- Finish tests and rollback transaction
 select * from finish();
 rollback;
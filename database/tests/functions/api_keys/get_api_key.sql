@@ -1,16 +1,22 @@
 -- Start transaction and plan tests
 begin;
-select plan(2);
+select plan(3);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
 \set apikey1ID '00000000-0000-0000-0000-000000000001'
+\set apikey2ID '00000000-0000-0000-0000-000000000002'
 
 -- Seed some data
 insert into "user" (user_id, alias, email)
 values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
 insert into api_key (api_key_id, name, created_at, user_id)
 values (:'apikey1ID', 'apikey1', '2020-05-29 13:55:00', :'user1ID');
+insert into api_key (api_key_id, name, created_at, user_id, repository_id)
+values (:'apikey2ID', 'apikey2', '2020-05-29 13:55:00', :'user1ID', :'repo1ID');
 
 -- Run some tests
 select is(
@@ -21,10 +27,26 @@ select is(
     '{
         "api_key_id": "00000000-0000-0000-0000-000000000001",
         "name": "apikey1",
-        "created_at": 1590753300
+        "created_at": 1590753300,
+        "repository_id": null,
+        "repository_name": null
     }'::jsonb,
     'Api key should exist'
 );
+select is(
+    get_api_key(
+        '00000000-0000-0000-0000-000000000001',
+        '00000000-0000-0000-0000-000000000002'
+    )::jsonb,
+    '{
+        "api_key_id": "00000000-0000-0000-0000-000000000002",
+        "name": "apikey2",
+        "created_at": 1590753300,
+        "repository_id": "00000000-0000-0000-0000-000000000001",
+        "repository_name": "repo1"
+    }'::jsonb,
+    'Repository scoped api key should exist'
+);
 select is_empty(
     $$
         select get_api_key(
@@ -0,0 +1,94 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set org2ID '00000000-0000-0000-0000-000000000002'
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set user3ID '00000000-0000-0000-0000-000000000003'
+\set apikey1ID '00000000-0000-0000-0000-000000000001'
+\set apikey2ID '00000000-0000-0000-0000-000000000002'
+\set apikey3ID '00000000-0000-0000-0000-000000000003'
+
+-- Seed some data
+insert into organization (organization_id, name) values (:'org1ID', 'org1');
+insert into organization (organization_id, name) values (:'org2ID', 'org2');
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into "user" (user_id, alias, email) values (:'user3ID', 'user3', 'user3@email.com');
+insert into user__organization (user_id, organization_id, confirmed) values (:'user1ID', :'org1ID', true);
+insert into user__organization (user_id, organization_id, confirmed) values (:'user2ID', :'org1ID', true);
+insert into user__organization (user_id, organization_id, confirmed) values (:'user3ID', :'org1ID', false);
+insert into api_key (api_key_id, name, user_id) values (:'apikey1ID', 'apikey1', :'user1ID');
+insert into api_key (api_key_id, name, user_id) values (:'apikey2ID', 'apikey2', :'user2ID');
+insert into api_key (api_key_id, name, user_id) values (:'apikey3ID', 'apikey3', :'user3ID');
+insert into api_key_usage (api_key_id, day, endpoint, requests, rate_limited_requests)
+values (:'apikey1ID', current_date - 1, 'GET /api/v1/packages/search', 10, 0);
+insert into api_key_usage (api_key_id, day, endpoint, requests, rate_limited_requests)
+values (:'apikey1ID', current_date, 'GET /api/v1/packages/search', 5, 0);
+insert into api_key_usage (api_key_id, day, endpoint, requests, rate_limited_requests)
+values (:'apikey1ID', current_date, 'GET /api/v1/*', 0, 3);
+insert into api_key_usage (api_key_id, day, endpoint, requests, rate_limited_requests)
+values (:'apikey2ID', current_date, 'GET /api/v1/repositories/search', 7, 0);
+insert into api_key_usage (api_key_id, day, endpoint, requests, rate_limited_requests)
+values (:'apikey2ID', current_date - 30, 'GET /api/v1/repositories/search', 100, 0);
+insert into api_key_usage (api_key_id, day, endpoint, requests, rate_limited_requests)
+values (:'apikey3ID', current_date, 'GET /api/v1/packages/search', 50, 0);
+
+-- Run some tests
+select is(
+    get_organization_api_usage(:'user1ID', 'org1', 7)::jsonb,
+    jsonb_build_object(
+        'requests_per_day', jsonb_build_array(
+            jsonb_build_object(
+                'day', current_date - 1,
+                'requests', 10,
+                'rate_limited_requests', 0
+            ),
+            jsonb_build_object(
+                'day', current_date,
+                'requests', 12,
+                'rate_limited_requests', 3
+            )
+        ),
+        'top_endpoints', '[{
+            "endpoint": "GET /api/v1/packages/search",
+            "requests": 15
+        }, {
+            "endpoint": "GET /api/v1/repositories/search",
+            "requests": 7
+        }]'::jsonb,
+        'api_keys', '[{
+            "api_key_id": "00000000-0000-0000-0000-000000000001",
+            "name": "apikey1",
+            "user_alias": "user1",
+            "requests": 15,
+            "rate_limited_requests": 3
+        }, {
+            "api_key_id": "00000000-0000-0000-0000-000000000002",
+            "name": "apikey2",
+            "user_alias": "user2",
+            "requests": 7,
+            "rate_limited_requests": 0
+        }]'::jsonb
+    ),
+    'Usage of the org1 confirmed members keys during the last 7 days should be returned'
+);
+select throws_ok(
+    $$ select get_organization_api_usage('00000000-0000-0000-0000-000000000001', 'org2', 7) $$,
+    42501,
+    'insufficient_privilege',
+    'User1 should not be able to get org2 api usage'
+);
+select throws_ok(
+    $$ select get_organization_api_usage('00000000-0000-0000-0000-000000000003', 'org1', 7) $$,
+    42501,
+    'insufficient_privilege',
+    'User3 (not confirmed) should not be able to get org1 api usage'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -29,12 +29,16 @@ select is(
         {
             "api_key_id": "00000000-0000-0000-0000-000000000001",
             "name": "apikey1",
-            "created_at": 1590753300
+            "created_at": 1590753300,
+            "repository_id": null,
+            "repository_name": null
         },
         {
             "api_key_id": "00000000-0000-0000-0000-000000000002",
             "name": "apikey2",
-            "created_at": 1590753300
+            "created_at": 1590753300,
+            "repository_id": null,
+            "repository_name": null
         }
     ]'::jsonb,
     'Api keys 1 and 2 should be returned'
@@ -45,7 +49,9 @@ select is(
         {
             "api_key_id": "00000000-0000-0000-0000-000000000003",
             "name": "apikey3",
-            "created_at": 1590753300
+            "created_at": 1590753300,
+            "repository_id": null,
+            "repository_name": null
         }
     ]'::jsonb,
     'Api key 3 should be returned'
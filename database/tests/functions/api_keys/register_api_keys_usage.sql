@@ -0,0 +1,64 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set apikey1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into api_key (api_key_id, name, user_id)
+values (:'apikey1ID', 'apikey1', :'user1ID');
+
+-- Run some tests
+select register_api_keys_usage('[{
+    "api_key_id": "00000000-0000-0000-0000-000000000001",
+    "day": "2020-06-16",
+    "endpoint": "GET /api/v1/packages/search",
+    "requests": 5
+}, {
+    "api_key_id": "00000000-0000-0000-0000-000000000001",
+    "day": "2020-06-16",
+    "endpoint": "GET /api/v1/*",
+    "rate_limited_requests": 2
+}, {
+    "api_key_id": "00000000-0000-0000-0000-000000000002",
+    "day": "2020-06-16",
+    "endpoint": "GET /api/v1/packages/search",
+    "requests": 1
+}]');
+select results_eq(
+    $$
+        select api_key_id, day, endpoint, requests, rate_limited_requests
+        from api_key_usage
+        order by endpoint asc
+    $$,
+    $$
+        values
+        ('00000000-0000-0000-0000-000000000001'::uuid, '2020-06-16'::date, 'GET /api/v1/*', 0, 2),
+        ('00000000-0000-0000-0000-000000000001'::uuid, '2020-06-16'::date, 'GET /api/v1/packages/search', 5, 0)
+    $$,
+    'Usage should be registered, ignoring the one of the keys that do not exist'
+);
+select register_api_keys_usage('[{
+    "api_key_id": "00000000-0000-0000-0000-000000000001",
+    "day": "2020-06-16",
+    "endpoint": "GET /api/v1/packages/search",
+    "requests": 3,
+    "rate_limited_requests": 1
+}]');
+select results_eq(
+    $$
+        select requests, rate_limited_requests
+        from api_key_usage
+        where endpoint = 'GET /api/v1/packages/search'
+    $$,
+    $$ values (8, 1) $$,
+    'Usage should be added to the one already registered'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
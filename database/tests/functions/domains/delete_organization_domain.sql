@@ -0,0 +1,34 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set domain1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email, email_verified) values (:'user1ID', 'user1', 'user1@org1.com', true);
+insert into "user" (user_id, alias, email, email_verified) values (:'user2ID', 'user2', 'user2@org1.com', true);
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+insert into organization_domain (organization_id, domain) values (:'org1ID', 'org1.com');
+
+-- Run some tests
+select throws_ok(
+    $$ select delete_organization_domain('00000000-0000-0000-0000-000000000002', 'org1', 'org1.com') $$,
+    42501,
+    'insufficient_privilege',
+    'User2 should not be able to delete domains from org1 as they do not belong to it'
+);
+select delete_organization_domain(:'user1ID', 'org1', 'org1.com');
+select is_empty(
+    $$ select * from organization_domain $$,
+    'Domain should have been deleted'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,49 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set domain1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email, email_verified) values (:'user1ID', 'user1', 'user1@org1.com', true);
+insert into "user" (user_id, alias, email, email_verified) values (:'user2ID', 'user2', 'user2@org1.com', true);
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+insert into organization_domain (organization_id, domain, verification_token, verified, auto_join, last_check_ts)
+values (:'org1ID', 'org1.io', 'token2', false, false, null);
+insert into organization_domain (organization_id, domain, verification_token, verified, auto_join, last_check_ts)
+values (:'org1ID', 'org1.com', 'token1', true, true, '2020-06-16 11:20:34+02');
+
+-- Run some tests
+select is(
+    get_organization_domains(:'user1ID', 'org1')::jsonb,
+    '[{
+        "domain": "org1.com",
+        "verification_token": "token1",
+        "verified": true,
+        "auto_join": true,
+        "last_check_ts": 1592299234
+    },{
+        "domain": "org1.io",
+        "verification_token": "token2",
+        "verified": false,
+        "auto_join": false,
+        "last_check_ts": null
+    }]'::jsonb,
+    'Org1 domains should be returned as a json array of objects'
+);
+select throws_ok(
+    $$ select get_organization_domains('00000000-0000-0000-0000-000000000002', 'org1') $$,
+    42501,
+    'insufficient_privilege',
+    'User2 should not be able to get org1 domains'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,44 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set domain1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email, email_verified) values (:'user1ID', 'user1', 'user1@org1.com', true);
+insert into "user" (user_id, alias, email, email_verified) values (:'user2ID', 'user2', 'user2@org1.com', true);
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+insert into "user" (user_id, alias, email, email_verified)
+values ('00000000-0000-0000-0000-000000000003', 'user3', 'user3@org1.com', false);
+insert into organization_domain (organization_id, domain, verified, auto_join)
+values (:'org1ID', 'org1.com', true, true);
+
+-- Run some tests
+select join_organizations_by_email_domain(:'user2ID');
+select results_eq(
+    $$ select confirmed from user__organization where user_id = '00000000-0000-0000-0000-000000000002' $$,
+    $$ values (true) $$,
+    'User2 should have joined org1 as their email belongs to a verified domain'
+);
+select join_organizations_by_email_domain('00000000-0000-0000-0000-000000000003');
+select is_empty(
+    $$ select * from user__organization where user_id = '00000000-0000-0000-0000-000000000003' $$,
+    'User3 should not have joined org1 as their email has not been verified'
+);
+update organization_domain set auto_join = false;
+delete from user__organization where user_id = :'user2ID';
+select join_organizations_by_email_domain(:'user2ID');
+select is_empty(
+    $$ select * from user__organization where user_id = '00000000-0000-0000-0000-000000000002' $$,
+    'User2 should not have joined org1 as auto join is disabled'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
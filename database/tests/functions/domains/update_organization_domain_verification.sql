@@ -0,0 +1,46 @@
+-- Start transaction and plan tests
+begin;
+select plan(4);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set domain1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email, email_verified) values (:'user1ID', 'user1', 'user1@org1.com', true);
+insert into "user" (user_id, alias, email, email_verified) values (:'user2ID', 'user2', 'user2@org1.com', true);
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+insert into organization_domain (organization_domain_id, organization_id, domain, auto_join)
+values (:'domain1ID', :'org1ID', 'org1.com', true);
+
+-- Run some tests
+select update_organization_domain_verification(:'domain1ID', true);
+select results_eq(
+    $$ select verified, last_check_ts is not null from organization_domain $$,
+    $$ values (true, true) $$,
+    'Domain should have been verified'
+);
+select results_eq(
+    $$ select confirmed from user__organization where user_id = '00000000-0000-0000-0000-000000000002' $$,
+    $$ values (true) $$,
+    'User2 should have joined org1 once the domain was verified'
+);
+select update_organization_domain_verification(:'domain1ID', false);
+select results_eq(
+    $$ select verified from organization_domain $$,
+    $$ values (false) $$,
+    'Domain should not be verified anymore'
+);
+select results_eq(
+    $$ select count(*) from user__organization where organization_id = '00000000-0000-0000-0000-000000000001' $$,
+    $$ values (2::bigint) $$,
+    'Members should be kept when the domain is not verified anymore'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
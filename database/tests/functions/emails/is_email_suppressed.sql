@@ -0,0 +1,20 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Seed some data
+insert into email_suppression (email, reason) values ('user1@email.com', 'bounce');
+
+-- Run some tests
+select ok(
+    is_email_suppressed('User1@email.com'),
+    'Email user1@email.com should be suppressed'
+);
+select ok(
+    not is_email_suppressed('user2@email.com'),
+    'Email user2@email.com should not be suppressed'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
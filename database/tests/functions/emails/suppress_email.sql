@@ -0,0 +1,27 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Run some tests
+select suppress_email('User1@Email.com', 'bounce');
+select results_eq(
+    $$ select email, reason from email_suppression $$,
+    $$ values ('user1@email.com', 'bounce') $$,
+    'Email should be suppressed (lowercased) with the reason provided'
+);
+select suppress_email('user1@email.com', 'complaint');
+select results_eq(
+    $$ select email, reason from email_suppression $$,
+    $$ values ('user1@email.com', 'complaint') $$,
+    'Complaints should take precedence over bounces'
+);
+select suppress_email('user1@email.com', 'bounce');
+select results_eq(
+    $$ select email, reason from email_suppression $$,
+    $$ values ('user1@email.com', 'complaint') $$,
+    'Later bounces should not override complaints'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,83 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+
+-- Add cluster inventory
+select add_cluster_inventory(:'user1ID', '
+{
+    "name": "cluster1",
+    "releases": [
+        {"name": "pkg1", "version": "1.0.0", "repository_url": "https://repo1.com"}
+    ],
+    "email_report": true,
+    "webhook_url": "https://webhook1.url"
+}
+'::jsonb);
+
+-- Check if cluster inventory was added successfully
+select results_eq(
+    $$
+        select
+            name,
+            releases,
+            email_report,
+            webhook_url,
+            last_report_at
+        from cluster_inventory
+        where user_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values (
+            'cluster1',
+            '[{"name": "pkg1", "version": "1.0.0", "repository_url": "https://repo1.com"}]'::jsonb,
+            true,
+            'https://webhook1.url',
+            null::timestamptz
+        )
+    $$,
+    'Cluster inventory should exist'
+);
+
+-- Add cluster inventory without reports configured
+select add_cluster_inventory(:'user1ID', '
+{
+    "name": "cluster2",
+    "releases": []
+}
+'::jsonb);
+select results_eq(
+    $$
+        select email_report, webhook_url
+        from cluster_inventory
+        where name = 'cluster2'
+    $$,
+    $$
+        values (false, null)
+    $$,
+    'Cluster inventory without reports should exist'
+);
+
+-- Try to add a cluster inventory using the same name
+select throws_ok(
+    $$
+        select add_cluster_inventory('00000000-0000-0000-0000-000000000001', '
+        {
+            "name": "cluster1",
+            "releases": []
+        }
+        '::jsonb)
+    $$,
+    23505,
+    'duplicate key value violates unique constraint "cluster_inventory_user_id_name_key"',
+    'Cluster inventory names must be unique per user'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,40 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set inventory1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into cluster_inventory (cluster_inventory_id, name, releases, user_id)
+values (:'inventory1ID', 'cluster1', '[]', :'user1ID');
+
+-- Try to delete a cluster inventory owned by other user
+select throws_ok(
+    $$
+        select delete_cluster_inventory(
+            '00000000-0000-0000-0000-000000000002',
+            '00000000-0000-0000-0000-000000000001'
+        )
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Cluster inventory delete should fail because requesting user is not the owner'
+);
+
+-- Delete cluster inventory
+select delete_cluster_inventory(:'user1ID', :'inventory1ID');
+select is_empty(
+    $$
+        select * from cluster_inventory where name = 'cluster1'
+    $$,
+    'Cluster inventory should have been deleted by user who owns it'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
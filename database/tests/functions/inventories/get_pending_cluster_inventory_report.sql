@@ -0,0 +1,86 @@
+-- Start transaction and plan tests
+begin;
+select plan(5);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set inventory1ID '00000000-0000-0000-0000-000000000001'
+\set inventory2ID '00000000-0000-0000-0000-000000000002'
+\set inventory3ID '00000000-0000-0000-0000-000000000003'
+
+-- Seed some data
+insert into "user" (user_id, alias, email, locale)
+values (:'user1ID', 'user1', 'user1@email.com', 'es');
+insert into cluster_inventory (cluster_inventory_id, name, releases, email_report, last_report_at, user_id)
+values (:'inventory1ID', 'cluster1', '[]', true, current_timestamp - '1 day'::interval, :'user1ID');
+insert into cluster_inventory (cluster_inventory_id, name, releases, user_id)
+values (:'inventory2ID', 'cluster2', '[]', :'user1ID');
+
+-- Run some tests
+select is_empty(
+    $$ select get_pending_cluster_inventory_report('7 days'::interval, 0, 24) $$,
+    'No reports due: last report is recent or reports not configured'
+);
+insert into cluster_inventory (cluster_inventory_id, name, releases, webhook_url, user_id)
+values (
+    :'inventory3ID',
+    'cluster3',
+    '[{"name": "pkg1", "version": "1.0.0", "repository_url": "https://repo1.com"}]',
+    'https://webhook1.url',
+    :'user1ID'
+);
+select is(
+    get_pending_cluster_inventory_report('7 days'::interval, 0, 24)::jsonb,
+    '{
+        "cluster_inventory_id": "00000000-0000-0000-0000-000000000003",
+        "name": "cluster3",
+        "releases": [
+            {"name": "pkg1", "version": "1.0.0", "repository_url": "https://repo1.com"}
+        ],
+        "email_report": false,
+        "webhook_url": "https://webhook1.url",
+        "user": {
+            "user_id": "00000000-0000-0000-0000-000000000001",
+            "email": "user1@email.com",
+            "locale": "es"
+        }
+    }'::jsonb,
+    'Cluster inventory never reported should be returned'
+);
+update cluster_inventory set last_report_at = current_timestamp
+where cluster_inventory_id = :'inventory3ID';
+select is(
+    (get_pending_cluster_inventory_report('12 hours'::interval, 0, 24)::jsonb)->>'cluster_inventory_id',
+    '00000000-0000-0000-0000-000000000001',
+    'Cluster inventory whose last report is older than the interval should be returned'
+);
+
+-- Reports are only delivered during the hours provided, in the owner's local time
+select is_empty(
+    format(
+        'select get_pending_cluster_inventory_report(%L::interval, %s, %s)',
+        '12 hours',
+        extract(hour from current_timestamp at time zone 'UTC')::int + 1,
+        extract(hour from current_timestamp at time zone 'UTC')::int + 2
+    ),
+    'No reports due: current UTC hour is out of the delivery hours'
+);
+update "user" set timezone = 'Asia/Kolkata' where user_id = :'user1ID';
+select is(
+    (get_pending_cluster_inventory_report(
+        '12 hours'::interval,
+        extract(hour from current_timestamp at time zone 'Asia/Kolkata')::int,
+        extract(hour from current_timestamp at time zone 'Asia/Kolkata')::int + 1
+    )::jsonb)->'user',
+    '{
+        "user_id": "00000000-0000-0000-0000-000000000001",
+        "email": "user1@email.com",
+        "locale": "es",
+        "timezone": "Asia/Kolkata"
+    }'::jsonb,
+    'Cluster inventory should be returned when the owner local hour is within the delivery hours'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,59 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set inventory1ID '00000000-0000-0000-0000-000000000001'
+\set inventory2ID '00000000-0000-0000-0000-000000000002'
+
+-- No cluster inventories at this point
+select is(
+    get_user_cluster_inventories(:'user1ID')::jsonb,
+    '[]'::jsonb,
+    'No cluster inventories found'
+);
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into cluster_inventory (
+    cluster_inventory_id,
+    name,
+    releases,
+    email_report,
+    webhook_url,
+    last_report_at,
+    user_id
+) values (
+    :'inventory1ID',
+    'cluster1',
+    '[{"name": "pkg1", "version": "1.0.0", "repository_url": "https://repo1.com"}]',
+    true,
+    'https://webhook1.url',
+    '2020-06-16 11:20:34+02',
+    :'user1ID'
+);
+insert into cluster_inventory (cluster_inventory_id, name, releases, user_id)
+values (:'inventory2ID', 'cluster2', '[]', :'user2ID');
+
+-- Run some tests
+select is(
+    get_user_cluster_inventories(:'user1ID')::jsonb,
+    '[{
+        "cluster_inventory_id": "00000000-0000-0000-0000-000000000001",
+        "name": "cluster1",
+        "releases": [
+            {"name": "pkg1", "version": "1.0.0", "repository_url": "https://repo1.com"}
+        ],
+        "email_report": true,
+        "webhook_url": "https://webhook1.url",
+        "last_report_at": 1592299234
+    }]'::jsonb,
+    'Only cluster inventories owned by user1 should be returned'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,63 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set inventory1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into cluster_inventory (cluster_inventory_id, name, releases, email_report, user_id)
+values (:'inventory1ID', 'cluster1', '[]', true, :'user1ID');
+
+-- Try to update a cluster inventory owned by other user
+select throws_ok(
+    $$
+        select update_cluster_inventory('00000000-0000-0000-0000-000000000002', '
+        {
+            "cluster_inventory_id": "00000000-0000-0000-0000-000000000001",
+            "name": "cluster1-updated",
+            "releases": []
+        }
+        '::jsonb)
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Cluster inventory update should fail because requesting user is not the owner'
+);
+
+-- Update cluster inventory
+select update_cluster_inventory(:'user1ID', '
+{
+    "cluster_inventory_id": "00000000-0000-0000-0000-000000000001",
+    "name": "cluster1-updated",
+    "releases": [
+        {"name": "pkg1", "version": "1.0.0", "repository_url": "https://repo1.com"}
+    ],
+    "email_report": false,
+    "webhook_url": "https://webhook1.url"
+}
+'::jsonb);
+select results_eq(
+    $$
+        select name, releases, email_report, webhook_url
+        from cluster_inventory
+        where cluster_inventory_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values (
+            'cluster1-updated',
+            '[{"name": "pkg1", "version": "1.0.0", "repository_url": "https://repo1.com"}]'::jsonb,
+            false,
+            'https://webhook1.url'
+        )
+    $$,
+    'Cluster inventory should have been updated'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
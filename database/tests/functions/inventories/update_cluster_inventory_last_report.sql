@@ -0,0 +1,26 @@
+-- Start transaction and plan tests
+begin;
+select plan(1);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set inventory1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into cluster_inventory (cluster_inventory_id, name, releases, email_report, user_id)
+values (:'inventory1ID', 'cluster1', '[]', true, :'user1ID');
+
+-- Update last report
+select update_cluster_inventory_last_report(:'inventory1ID');
+
+-- Run some tests
+select isnt(
+    (select last_report_at from cluster_inventory where cluster_inventory_id = :'inventory1ID'),
+    null,
+    'Cluster inventory last report should have been updated'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
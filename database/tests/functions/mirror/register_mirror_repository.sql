@@ -0,0 +1,105 @@
+-- Start transaction and plan tests
+begin;
+select plan(6);
+
+-- Declare some variables
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set org2ID '00000000-0000-0000-0000-000000000002'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'mirror', 'Mirror', 'Description 1', 'https://org1.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org2ID', 'org2', 'Organization 2', 'Description 2', 'https://org2.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'org2ID');
+
+-- Run some tests
+select throws_ok(
+    $$
+        select register_mirror_repository('org3', '
+        {
+            "name": "repo1",
+            "url": "https://repo1.com",
+            "kind": 0
+        }
+        '::jsonb)
+    $$,
+    'mirror organization not found: org3',
+    'Organization must exist'
+);
+select lives_ok(
+    $$
+        select register_mirror_repository('mirror', '
+        {
+            "name": "repo1",
+            "display_name": "Repo 1",
+            "url": "https://repo1.com",
+            "kind": 0,
+            "metadata": {"mirror_source": "https://source.hub"}
+        }
+        '::jsonb)
+    $$,
+    'Mirror repository should be registered'
+);
+select results_eq(
+    $$
+        select name, display_name, url, repository_kind_id, disabled, metadata, organization_id
+        from repository
+        where name = 'repo1'
+    $$,
+    $$
+        values (
+            'repo1',
+            'Repo 1',
+            'https://repo1.com',
+            0,
+            true,
+            '{"mirror_source": "https://source.hub"}'::jsonb,
+            '00000000-0000-0000-0000-000000000001'::uuid
+        )
+    $$,
+    'Mirror repository should exist and be disabled'
+);
+select is(
+    register_mirror_repository('mirror', '
+    {
+        "name": "repo1",
+        "display_name": "Repo 1 updated",
+        "url": "https://repo1.com/updated",
+        "kind": 0,
+        "metadata": {"mirror_source": "https://source.hub"}
+    }
+    '::jsonb),
+    (select repository_id from repository where name = 'repo1'),
+    'Existing mirror repository id should be returned'
+);
+select results_eq(
+    $$
+        select display_name, url
+        from repository
+        where name = 'repo1'
+    $$,
+    $$
+        values ('Repo 1 updated', 'https://repo1.com/updated')
+    $$,
+    'Mirror repository should have been updated'
+);
+select throws_ok(
+    $$
+        select register_mirror_repository('mirror', '
+        {
+            "name": "repo2",
+            "url": "https://repo2.com/mirror",
+            "kind": 0
+        }
+        '::jsonb)
+    $$,
+    'repository name already in use: repo2',
+    'Repositories not registered by the mirror cannot be replaced'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
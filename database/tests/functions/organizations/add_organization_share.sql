@@ -0,0 +1,46 @@
+-- Start transaction and plan tests
+begin;
+select plan(4);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set org2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some users and organizations
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org2ID', 'org2', 'Organization 2', 'Description 2', 'https://org2.com');
+insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+insert into user__organization (user_id, organization_id, confirmed) values(:'user2ID', :'org2ID', true);
+
+-- Run some tests
+select throws_ok(
+    $$ select add_organization_share('00000000-0000-0000-0000-000000000002', 'org1', 'org2') $$,
+    42501,
+    'insufficient_privilege',
+    'User2 should not be able to share org1 repositories as they do not belong to it'
+);
+select throws_ok(
+    $$ select add_organization_share('00000000-0000-0000-0000-000000000001', 'org1', 'org3') $$,
+    'target organization not found',
+    'Repositories cannot be shared with an organization that does not exist'
+);
+select add_organization_share(:'user1ID', 'org1', 'org2');
+select results_eq(
+    $$ select organization_id, shared_with_organization_id from organization_share $$,
+    $$ values ('00000000-0000-0000-0000-000000000001'::uuid, '00000000-0000-0000-0000-000000000002'::uuid) $$,
+    'Org1 repositories should be shared with org2'
+);
+select lives_ok(
+    $$ select add_organization_share('00000000-0000-0000-0000-000000000001', 'org1', 'org2') $$,
+    'Sharing again with the same organization should not fail'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
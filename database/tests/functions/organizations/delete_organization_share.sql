@@ -0,0 +1,37 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set org2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some users and organizations
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org2ID', 'org2', 'Organization 2', 'Description 2', 'https://org2.com');
+insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+insert into user__organization (user_id, organization_id, confirmed) values(:'user2ID', :'org2ID', true);
+insert into organization_share (organization_id, shared_with_organization_id) values (:'org1ID', :'org2ID');
+
+-- Run some tests
+select throws_ok(
+    $$ select delete_organization_share('00000000-0000-0000-0000-000000000002', 'org1', 'org2') $$,
+    42501,
+    'insufficient_privilege',
+    'User2 should not be able to revoke org1 shares as they do not belong to it'
+);
+select delete_organization_share(:'user1ID', 'org1', 'org2');
+select is_empty(
+    $$ select * from organization_share $$,
+    'Org1 repositories should not be shared with org2 anymore'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -5,10 +5,29 @@ select plan(2);
 -- Declare some variables
 \set org1ID '00000000-0000-0000-0000-000000000001'
 \set image1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set package2ID '00000000-0000-0000-0000-000000000002'
 
 -- Seed some users and organizations
 insert into organization (organization_id, name, display_name, description, home_url, logo_image_id)
 values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com', :'image1ID');
+insert into organization_domain (organization_id, domain, verified) values (:'org1ID', 'org1.com', true);
+insert into organization_domain (organization_id, domain, verified) values (:'org1ID', 'org1.io', false);
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'org1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'package1', '1.0.0', :'repo1ID');
+insert into snapshot (package_id, version, display_name, description, created_at)
+values (:'package1ID', '1.0.0', 'Package 1', 'description', '2020-06-16 11:20:34+02');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package2ID', 'package2', '1.0.0', :'repo1ID');
+insert into snapshot (package_id, version, display_name, description, created_at)
+values (:'package2ID', '1.0.0', 'Package 2', 'description', '2020-06-16 11:20:35+02');
+insert into organization_featured_package (organization_id, package_id, position)
+values (:'org1ID', :'package2ID', 0);
+insert into organization_featured_package (organization_id, package_id, position)
+values (:'org1ID', :'package1ID', 1);
 
 -- Run some tests
 select is(
@@ -18,7 +37,58 @@ select is(
         "display_name": "Organization 1",
         "description": "Description 1",
         "home_url": "https://org1.com",
-        "logo_image_id": "00000000-0000-0000-0000-000000000001"
+        "logo_image_id": "00000000-0000-0000-0000-000000000001",
+        "verified_domains": ["org1.com"],
+        "featured_packages": [
+            {
+                "package_id": "00000000-0000-0000-0000-000000000002",
+                "name": "package2",
+                "normalized_name": "package2",
+                "logo_image_id": null,
+                "stars": 0,
+                "display_name": "Package 2",
+                "description": "description",
+                "version": "1.0.0",
+                "app_version": null,
+                "deprecated": null,
+                "signed": null,
+                "created_at": 1592299235,
+                "repository": {
+                    "repository_id": "00000000-0000-0000-0000-000000000001",
+                    "kind": 0,
+                    "name": "repo1",
+                    "display_name": "Repo 1",
+                    "url": "https://repo1.com",
+                    "user_alias": null,
+                    "organization_name": "org1",
+                    "organization_display_name": "Organization 1"
+                }
+            },
+            {
+                "package_id": "00000000-0000-0000-0000-000000000001",
+                "name": "package1",
+                "normalized_name": "package1",
+                "logo_image_id": null,
+                "stars": 0,
+                "display_name": "Package 1",
+                "description": "description",
+                "version": "1.0.0",
+                "app_version": null,
+                "deprecated": null,
+                "signed": null,
+                "created_at": 1592299234,
+                "repository": {
+                    "repository_id": "00000000-0000-0000-0000-000000000001",
+                    "kind": 0,
+                    "name": "repo1",
+                    "display_name": "Repo 1",
+                    "url": "https://repo1.com",
+                    "user_alias": null,
+                    "organization_name": "org1",
+                    "organization_display_name": "Organization 1"
+                }
+            }
+        ]
     }
     '::jsonb,
     'Organization1 should exist'
@@ -0,0 +1,62 @@
+-- Start transaction and plan tests
+begin;
+select plan(4);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set user3ID '00000000-0000-0000-0000-000000000003'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set org2ID '00000000-0000-0000-0000-000000000002'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into "user" (user_id, alias, email) values (:'user3ID', 'user3', 'user3@email.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org2ID', 'org2', 'Organization 2', 'Description 2', 'https://org2.com');
+insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+insert into user__organization (user_id, organization_id, confirmed) values(:'user2ID', :'org1ID', true);
+insert into user__organization (user_id, organization_id, confirmed) values(:'user3ID', :'org2ID', true);
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'org1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'package1', '1.0.0', :'repo1ID');
+
+-- Run some tests
+select is(
+    get_organization_member_deletion_impact(:'user1ID', 'org1', 'user2')::jsonb,
+    '{
+        "last_member": false,
+        "repositories": 1,
+        "packages": 1
+    }'::jsonb,
+    'Deletion impact of org1 member user2 should be returned'
+);
+select is(
+    get_organization_member_deletion_impact(:'user3ID', 'org2', 'user3')::jsonb,
+    '{
+        "last_member": true,
+        "repositories": 0,
+        "packages": 0
+    }'::jsonb,
+    'Deletion impact of org2 last member user3 should be returned'
+);
+select throws_ok(
+    $$ select get_organization_member_deletion_impact('00000000-0000-0000-0000-000000000003', 'org1', 'user2') $$,
+    42501,
+    'insufficient_privilege',
+    'User3 should not be able to get the deletion impact of an org1 member'
+);
+select is_empty(
+    $$ select get_organization_member_deletion_impact('00000000-0000-0000-0000-000000000001', 'org1', 'user3') $$,
+    'No rows expected for a user that does not belong to the organization'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,47 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set org2ID '00000000-0000-0000-0000-000000000002'
+\set org3ID '00000000-0000-0000-0000-000000000003'
+
+-- Seed some users and organizations
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org2ID', 'org2', 'Organization 2', 'Description 2', 'https://org2.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org3ID', 'org3', 'Organization 3', 'Description 3', 'https://org3.com');
+insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+insert into user__organization (user_id, organization_id, confirmed) values(:'user2ID', :'org2ID', true);
+insert into organization_share (organization_id, shared_with_organization_id) values (:'org1ID', :'org3ID');
+insert into organization_share (organization_id, shared_with_organization_id) values (:'org1ID', :'org2ID');
+
+-- Run some tests
+select is(
+    get_organization_shares(:'user1ID', 'org1')::jsonb,
+    '[{
+        "name": "org2",
+        "display_name": "Organization 2"
+    },{
+        "name": "org3",
+        "display_name": "Organization 3"
+    }]'::jsonb,
+    'Organizations org1 repositories are shared with are returned as a json array of objects'
+);
+select throws_ok(
+    $$ select get_organization_shares('00000000-0000-0000-0000-000000000002', 'org1') $$,
+    42501,
+    'insufficient_privilege',
+    'User2 should not be able to get org1 shares'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
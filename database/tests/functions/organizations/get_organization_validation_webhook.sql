@@ -0,0 +1,52 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into organization (
+    organization_id,
+    name,
+    display_name,
+    description,
+    home_url,
+    validation_webhook_url,
+    validation_webhook_secret
+) values (
+    :'org1ID',
+    'org1',
+    'Organization 1',
+    'Description 1',
+    'https://org1.com',
+    'https://validation.org1.com',
+    'secret'
+);
+insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+
+-- Run some tests
+select is(
+    get_organization_validation_webhook(:'user1ID', 'org1')::jsonb,
+    '{
+        "url": "https://validation.org1.com",
+        "secret": "secret"
+    }'::jsonb,
+    'Validation webhook should be returned'
+);
+select throws_ok(
+    $$
+        select get_organization_validation_webhook('00000000-0000-0000-0000-000000000002', 'org1')
+    $$,
+    42501,
+    'insufficient_privilege',
+    'User2 should not be able to get the validation webhook of org1'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,101 @@
+-- Start transaction and plan tests
+begin;
+select plan(5);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set org2ID '00000000-0000-0000-0000-000000000002'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set package2ID '00000000-0000-0000-0000-000000000002'
+\set package3ID '00000000-0000-0000-0000-000000000003'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org2ID', 'org2', 'Organization 2', 'Description 2', 'https://org2.com');
+insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+insert into user__organization (user_id, organization_id, confirmed) values(:'user2ID', :'org2ID', true);
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'org1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'org2ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'package1', '1.0.0', :'repo1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package2ID', 'package2', '1.0.0', :'repo1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package3ID', 'package3', '1.0.0', :'repo2ID');
+
+-- Run some tests
+select throws_ok(
+    $$
+        select update_organization_featured_packages(
+            '00000000-0000-0000-0000-000000000002',
+            'org1',
+            '["00000000-0000-0000-0000-000000000001"]'
+        )
+    $$,
+    42501,
+    'insufficient_privilege',
+    'User2 should not be able to update org1 featured packages as they do not belong to it'
+);
+select throws_ok(
+    $$
+        select update_organization_featured_packages(
+            '00000000-0000-0000-0000-000000000001',
+            'org1',
+            '["00000000-0000-0000-0000-000000000001", "00000000-0000-0000-0000-000000000003"]'
+        )
+    $$,
+    'package not found in organization',
+    'Packages that do not belong to the organization cannot be featured'
+);
+select update_organization_featured_packages(
+    :'user1ID',
+    'org1',
+    '["00000000-0000-0000-0000-000000000002", "00000000-0000-0000-0000-000000000001"]'
+);
+select results_eq(
+    $$
+        select package_id, position
+        from organization_featured_package
+        where organization_id = '00000000-0000-0000-0000-000000000001'
+        order by position
+    $$,
+    $$
+        values
+        ('00000000-0000-0000-0000-000000000002'::uuid, 0),
+        ('00000000-0000-0000-0000-000000000001'::uuid, 1)
+    $$,
+    'Org1 featured packages should be stored in the order provided'
+);
+select update_organization_featured_packages(
+    :'user1ID',
+    'org1',
+    '["00000000-0000-0000-0000-000000000001"]'
+);
+select results_eq(
+    $$
+        select package_id, position
+        from organization_featured_package
+        where organization_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$ values ('00000000-0000-0000-0000-000000000001'::uuid, 0) $$,
+    'Org1 featured packages should have been replaced'
+);
+select update_organization_featured_packages(:'user1ID', 'org1', '[]');
+select is_empty(
+    $$ select * from organization_featured_package $$,
+    'Org1 should not have featured packages'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
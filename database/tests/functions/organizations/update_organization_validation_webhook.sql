@@ -0,0 +1,69 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed user and organization
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+
+-- Update organization validation webhook
+select update_organization_validation_webhook(:'user1ID', 'org1', '
+{
+    "url": "https://validation.org1.com",
+    "secret": "secret"
+}
+'::jsonb);
+
+-- Check if organization validation webhook was updated successfully
+select results_eq(
+    $$
+        select validation_webhook_url, validation_webhook_secret
+        from organization
+    $$,
+    $$
+        values ('https://validation.org1.com', 'secret')
+    $$,
+    'Organization validation webhook should have been updated'
+);
+
+-- Remove organization validation webhook
+select update_organization_validation_webhook(:'user1ID', 'org1', '
+{
+    "url": "",
+    "secret": "secret"
+}
+'::jsonb);
+select results_eq(
+    $$
+        select validation_webhook_url, validation_webhook_secret
+        from organization
+    $$,
+    $$
+        values (null::text, null::text)
+    $$,
+    'Organization validation webhook should have been removed'
+);
+
+-- Try again using a user not belonging to the organization
+select throws_ok(
+    $$
+        select update_organization_validation_webhook('00000000-0000-0000-0000-000000000002', 'org1', '
+        {
+            "url": "https://validation.org1.com"
+        }
+        '::jsonb)
+    $$,
+    42501,
+    'insufficient_privilege',
+    'User2 should not be able to update the validation webhook of org1'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
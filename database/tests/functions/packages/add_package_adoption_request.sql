@@ -0,0 +1,85 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set user3ID '00000000-0000-0000-0000-000000000003'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email, locale) values (:'user2ID', 'user2', 'user2@email.com', 'es');
+insert into "user" (user_id, alias, email) values (:'user3ID', 'user3', 'user3@email.com');
+insert into user__organization (user_id, organization_id, confirmed) values (:'user1ID', :'org1ID', true);
+insert into user__organization (user_id, organization_id, confirmed) values (:'user2ID', :'org1ID', true);
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'org1ID');
+insert into package (
+    package_id,
+    name,
+    latest_version,
+    repository_id
+) values (
+    :'package1ID',
+    'package1',
+    '1.0.0',
+    :'repo1ID'
+);
+
+-- Run some tests
+select is_empty(
+    $$
+        select add_package_adoption_request(
+            '00000000-0000-0000-0000-000000000003',
+            '00000000-0000-0000-0000-000000000001',
+            'I would like to help'
+        )
+    $$,
+    'No rows should be returned if the package is not seeking maintainers'
+);
+update package set seeking_maintainers = true where package_id = :'package1ID';
+select add_package_adoption_request(:'user3ID', :'package1ID', 'I would like to help');
+select is(
+    add_package_adoption_request(:'user3ID', :'package1ID', 'I maintain a fork')::jsonb,
+    '{
+        "package_name": "package1",
+        "repository_kind": 0,
+        "repository_name": "repo1",
+        "user_alias": "user3",
+        "owners": [
+            {
+                "email": "user1@email.com",
+                "locale": null
+            },
+            {
+                "email": "user2@email.com",
+                "locale": "es"
+            }
+        ]
+    }'::jsonb,
+    'Package owners details should be returned'
+);
+select results_eq(
+    $$
+        select package_id, user_id, message
+        from package_adoption_request
+    $$,
+    $$
+        values (
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            '00000000-0000-0000-0000-000000000003'::uuid,
+            'I maintain a fork'
+        )
+    $$,
+    'User3 adoption request should have been replaced'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
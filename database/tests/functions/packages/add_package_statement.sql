@@ -0,0 +1,118 @@
+-- Start transaction and plan tests
+begin;
+select plan(4);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set package2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id, verified_publisher)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID', true);
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'user1ID');
+insert into package (
+    package_id,
+    name,
+    latest_version,
+    repository_id
+) values (
+    :'package1ID',
+    'Package 1',
+    '1.0.0',
+    :'repo1ID'
+);
+insert into package (
+    package_id,
+    name,
+    latest_version,
+    repository_id
+) values (
+    :'package2ID',
+    'Package 2',
+    '1.0.0',
+    :'repo2ID'
+);
+
+-- Run some tests
+select throws_ok(
+    $$
+        select add_package_statement(
+            '00000000-0000-0000-0000-000000000002',
+            '00000000-0000-0000-0000-000000000001',
+            '{"kind": "eol", "content": "EOL on 2021-01-01", "signature": "sig", "signature_algorithm": "ed25519"}'
+        )
+    $$,
+    42501,
+    'insufficient_privilege',
+    'User2 should not be able to add statements to package1 as they cannot update repo1'
+);
+select throws_ok(
+    $$
+        select add_package_statement(
+            '00000000-0000-0000-0000-000000000001',
+            '00000000-0000-0000-0000-000000000002',
+            '{"kind": "eol", "content": "EOL on 2021-01-01", "signature": "sig", "signature_algorithm": "ed25519"}'
+        )
+    $$,
+    42501,
+    'insufficient_privilege',
+    'User1 should not be able to add statements to package2 as repo2 is not from a verified publisher'
+);
+select add_package_statement(:'user1ID', :'package1ID', '
+{
+    "kind": "eol",
+    "content": "EOL on 2021-01-01",
+    "signature": "sig1",
+    "signature_algorithm": "ed25519",
+    "signature_key_id": "key1"
+}
+');
+select add_package_statement(:'user1ID', :'package1ID', '
+{
+    "kind": "eol",
+    "content": "EOL on 2022-01-01",
+    "signature": "sig2",
+    "signature_algorithm": "ed25519",
+    "signature_key_id": "key1"
+}
+');
+select results_eq(
+    $$
+        select kind, content, signature, signature_algorithm, signature_key_id, user_id
+        from package_statement
+    $$,
+    $$
+        values (
+            'eol',
+            'EOL on 2022-01-01',
+            'sig2',
+            'ed25519',
+            'key1',
+            '00000000-0000-0000-0000-000000000001'::uuid
+        )
+    $$,
+    'Package1 eol statement should have been replaced'
+);
+select results_eq(
+    $$
+        select package_id, package_version, event_kind_id
+        from event
+    $$,
+    $$
+        values
+            ('00000000-0000-0000-0000-000000000001'::uuid, '1.0.0', 2),
+            ('00000000-0000-0000-0000-000000000001'::uuid, '1.0.0', 2)
+    $$,
+    'A statement change event should have been registered for each change'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
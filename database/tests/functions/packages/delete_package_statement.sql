@@ -0,0 +1,61 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into package (
+    package_id,
+    name,
+    latest_version,
+    repository_id
+) values (
+    :'package1ID',
+    'Package 1',
+    '1.0.0',
+    :'repo1ID'
+);
+insert into package_statement (package_id, kind, content, signature, signature_algorithm, user_id)
+values (:'package1ID', 'eol', 'EOL on 2021-01-01', 'sig', 'ed25519', :'user1ID');
+
+-- Run some tests
+select throws_ok(
+    $$
+        select delete_package_statement(
+            '00000000-0000-0000-0000-000000000002',
+            '00000000-0000-0000-0000-000000000001',
+            'eol'
+        )
+    $$,
+    42501,
+    'insufficient_privilege',
+    'User2 should not be able to delete statements from package1 as they cannot update repo1'
+);
+select delete_package_statement(:'user1ID', :'package1ID', 'eol');
+select is_empty(
+    'select * from package_statement',
+    'Package1 eol statement should have been deleted'
+);
+select results_eq(
+    $$
+        select package_id, package_version, event_kind_id
+        from event
+    $$,
+    $$
+        values ('00000000-0000-0000-0000-000000000001'::uuid, '1.0.0', 2)
+    $$,
+    'A statement change event should have been registered'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
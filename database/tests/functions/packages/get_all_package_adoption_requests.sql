@@ -0,0 +1,58 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+
+-- No adoption requests at this point
+select is(
+    get_all_package_adoption_requests()::jsonb,
+    '[]'::jsonb,
+    'An empty json array should be returned if there are no adoption requests'
+);
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into package (
+    package_id,
+    name,
+    latest_version,
+    repository_id,
+    seeking_maintainers
+) values (
+    :'package1ID',
+    'package1',
+    '1.0.0',
+    :'repo1ID',
+    true
+);
+insert into package_adoption_request (package_id, user_id, message, created_at)
+values (:'package1ID', :'user2ID', 'message2', '2020-06-16 11:20:34+02');
+
+-- Run some tests
+select is(
+    get_all_package_adoption_requests()::jsonb,
+    '[
+        {
+            "package_id": "00000000-0000-0000-0000-000000000001",
+            "package_name": "package1",
+            "repository_kind": 0,
+            "repository_name": "repo1",
+            "user_alias": "user2",
+            "message": "message2",
+            "created_at": 1592299234
+        }
+    ]'::jsonb,
+    'All adoption requests should be returned'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
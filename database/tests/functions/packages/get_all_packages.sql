@@ -0,0 +1,148 @@
+-- Start transaction and plan tests
+begin;
+select plan(4);
+
+-- Declare some variables
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set package2ID '00000000-0000-0000-0000-000000000002'
+\set package3ID '00000000-0000-0000-0000-000000000003'
+
+-- No packages at this point
+select is(
+    get_all_packages('{"limit": 2}')::jsonb,
+    '{
+        "packages": [],
+        "next_cursor": null
+    }'::jsonb,
+    'No packages in db yet, no packages expected'
+);
+
+-- Seed some data
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'org1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 1, :'org1ID');
+insert into package (package_id, name, latest_version, stars, repository_id)
+values (:'package1ID', 'package1', '1.0.0', 10, :'repo1ID');
+insert into snapshot (package_id, version, display_name, description, created_at)
+values (:'package1ID', '1.0.0', 'Package 1', 'description', '2020-06-16 11:20:34+02');
+insert into package (package_id, name, latest_version, stars, repository_id)
+values (:'package2ID', 'package2', '1.0.0', 5, :'repo2ID');
+insert into snapshot (package_id, version, display_name, description, created_at)
+values (:'package2ID', '1.0.0', 'Package 2', 'description', '2020-06-16 11:20:34+02');
+insert into package (package_id, name, latest_version, stars, repository_id)
+values (:'package3ID', 'package3', '1.0.0', 0, :'repo1ID');
+insert into snapshot (package_id, version, display_name, description, created_at)
+values (:'package3ID', '1.0.0', 'Package 3', 'description', '2020-06-16 11:20:34+02');
+
+-- Run some tests
+select is(
+    get_all_packages('{"limit": 2}')::jsonb,
+    '{
+        "packages": [
+            {
+                "package_id": "00000000-0000-0000-0000-000000000001",
+                "name": "package1",
+                "normalized_name": "package1",
+                "logo_image_id": null,
+                "stars": 10,
+                "display_name": "Package 1",
+                "description": "description",
+                "version": "1.0.0",
+                "app_version": null,
+                "deprecated": null,
+                "signed": null,
+                "created_at": 1592299234,
+                "repository": {
+                    "repository_id": "00000000-0000-0000-0000-000000000001",
+                    "kind": 0,
+                    "name": "repo1",
+                    "display_name": "Repo 1",
+                    "url": "https://repo1.com",
+                    "user_alias": null,
+                    "organization_name": "org1",
+                    "organization_display_name": "Organization 1"
+                }
+            },
+            {
+                "package_id": "00000000-0000-0000-0000-000000000002",
+                "name": "package2",
+                "normalized_name": "package2",
+                "logo_image_id": null,
+                "stars": 5,
+                "display_name": "Package 2",
+                "description": "description",
+                "version": "1.0.0",
+                "app_version": null,
+                "deprecated": null,
+                "signed": null,
+                "created_at": 1592299234,
+                "repository": {
+                    "repository_id": "00000000-0000-0000-0000-000000000002",
+                    "kind": 1,
+                    "name": "repo2",
+                    "display_name": "Repo 2",
+                    "url": "https://repo2.com",
+                    "user_alias": null,
+                    "organization_name": "org1",
+                    "organization_display_name": "Organization 1"
+                }
+            }
+        ],
+        "next_cursor": "00000000-0000-0000-0000-000000000002"
+    }'::jsonb,
+    'First page expected (package1 and package2), next cursor is package2'
+);
+select is(
+    get_all_packages('{
+        "limit": 2,
+        "cursor": "00000000-0000-0000-0000-000000000002"
+    }')::jsonb,
+    '{
+        "packages": [
+            {
+                "package_id": "00000000-0000-0000-0000-000000000003",
+                "name": "package3",
+                "normalized_name": "package3",
+                "logo_image_id": null,
+                "stars": 0,
+                "display_name": "Package 3",
+                "description": "description",
+                "version": "1.0.0",
+                "app_version": null,
+                "deprecated": null,
+                "signed": null,
+                "created_at": 1592299234,
+                "repository": {
+                    "repository_id": "00000000-0000-0000-0000-000000000001",
+                    "kind": 0,
+                    "name": "repo1",
+                    "display_name": "Repo 1",
+                    "url": "https://repo1.com",
+                    "user_alias": null,
+                    "organization_name": "org1",
+                    "organization_display_name": "Organization 1"
+                }
+            }
+        ],
+        "next_cursor": null
+    }'::jsonb,
+    'Last page expected (package3), no next cursor'
+);
+select is(
+    (get_all_packages('{
+        "limit": 2,
+        "repository_kinds": [1]
+    }')::jsonb)->'packages'->0->>'name',
+    'package2',
+    'Only packages of the kind provided expected (package2)'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -1,10 +1,13 @@
 -- Start transaction and plan tests
 begin;
-select plan(5);
+select plan(10);
 
 -- Declare some variables
 \set org1ID '00000000-0000-0000-0000-000000000001'
+\set org2ID '00000000-0000-0000-0000-000000000002'
 \set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set user3ID '00000000-0000-0000-0000-000000000003'
 \set repo1ID '00000000-0000-0000-0000-000000000001'
 \set repo2ID '00000000-0000-0000-0000-000000000002'
 \set package1ID '00000000-0000-0000-0000-000000000001'
@@ -77,6 +80,8 @@ insert into snapshot (
     keywords,
     home_url,
     app_version,
+    kube_version,
+    chart_type,
     digest,
     readme,
     install,
@@ -85,8 +90,13 @@ insert into snapshot (
     deprecated,
     license,
     signed,
+    sign_key,
+    signature_verified,
+    changes,
     container_image,
+    containers_images,
     provider,
+    capabilities,
     created_at
 ) values (
     :'package1ID',
@@ -96,6 +106,8 @@ insert into snapshot (
     '{"kw1", "kw2"}',
     'home_url',
     '12.1.0',
+    '>=1.16.0, <1.20.0',
+    'application',
     'digest-package1-1.0.0',
     'readme-version-1.0.0',
     'install-version-1.0.0',
@@ -104,8 +116,13 @@ insert into snapshot (
     true,
     'Apache-2.0',
     true,
+    '{"key_id": "34365D9472D7468F", "fingerprint": "C874011F0AB405110D02105534365D9472D7468F", "url": "https://keybase.io/user1/pgp_keys.asc"}',
+    true,
+    '["Added feature 1", "Fixed bug 1"]',
     'quay.io/org/img:1.0.0',
+    '[{"image": "quay.io/org/img:1.0.0"}]',
     'Org Inc',
+    'Basic Install',
     '2020-06-16 11:20:34+02'
 );
 insert into snapshot (
@@ -194,6 +211,7 @@ select is(
             }
         ],
         "default_channel": "stable",
+        "seeking_maintainers": false,
         "display_name": "Package 1",
         "description": "description",
         "keywords": ["kw1", "kw2"],
@@ -225,12 +243,29 @@ select is(
             }
         ],
         "app_version": "12.1.0",
+        "kube_version": ">=1.16.0, <1.20.0",
+        "chart_type": "application",
         "digest": "digest-package1-1.0.0",
         "deprecated": true,
         "license": "Apache-2.0",
         "signed": true,
+        "sign_key": {
+            "key_id": "34365D9472D7468F",
+            "fingerprint": "C874011F0AB405110D02105534365D9472D7468F",
+            "url": "https://keybase.io/user1/pgp_keys.asc"
+        },
+        "signature_verified": true,
+        "changes": ["Added feature 1", "Fixed bug 1"],
         "container_image": "quay.io/org/img:1.0.0",
+        "containers_images": [
+            {
+                "image": "quay.io/org/img:1.0.0"
+            }
+        ],
         "provider": "Org Inc",
+        "capabilities": "Basic Install",
+        "maintenance": null,
+        "eol": false,
         "created_at": 1592299234,
         "maintainers": [
             {
@@ -248,6 +283,9 @@ select is(
             "name": "repo1",
             "display_name": "Repo 1",
             "url": "https://repo1.com",
+            "disabled": false,
+            "private": false,
+            "metadata": null,
             "user_alias": "user1",
             "organization_name": null,
             "organization_display_name": null
@@ -277,6 +315,7 @@ select is(
             }
         ],
         "default_channel": "stable",
+        "seeking_maintainers": false,
         "display_name": "Package 1",
         "description": "description",
         "keywords": ["kw1", "kw2"],
@@ -308,12 +347,29 @@ select is(
             }
         ],
         "app_version": "12.1.0",
+        "kube_version": ">=1.16.0, <1.20.0",
+        "chart_type": "application",
         "digest": "digest-package1-1.0.0",
         "deprecated": true,
         "license": "Apache-2.0",
         "signed": true,
+        "sign_key": {
+            "key_id": "34365D9472D7468F",
+            "fingerprint": "C874011F0AB405110D02105534365D9472D7468F",
+            "url": "https://keybase.io/user1/pgp_keys.asc"
+        },
+        "signature_verified": true,
+        "changes": ["Added feature 1", "Fixed bug 1"],
         "container_image": "quay.io/org/img:1.0.0",
+        "containers_images": [
+            {
+                "image": "quay.io/org/img:1.0.0"
+            }
+        ],
         "provider": "Org Inc",
+        "capabilities": "Basic Install",
+        "maintenance": null,
+        "eol": false,
         "created_at": 1592299234,
         "maintainers": [
             {
@@ -331,6 +387,9 @@ select is(
             "name": "repo1",
             "display_name": "Repo 1",
             "url": "https://repo1.com",
+            "disabled": false,
+            "private": false,
+            "metadata": null,
             "user_alias": "user1",
             "organization_name": null,
             "organization_display_name": null
@@ -361,6 +420,7 @@ select is(
             }
         ],
         "default_channel": "stable",
+        "seeking_maintainers": false,
         "display_name": "Package 1 (older)",
         "description": "description (older)",
         "keywords": ["kw1", "kw2", "older"],
@@ -392,12 +452,21 @@ select is(
             }
         ],
         "app_version": "12.0.0",
+        "kube_version": null,
+        "chart_type": null,
         "digest": "digest-package1-0.0.9",
         "deprecated": null,
         "license": null,
         "signed": null,
+        "sign_key": null,
+        "signature_verified": null,
+        "changes": null,
         "container_image": null,
+        "containers_images": null,
         "provider": null,
+        "capabilities": null,
+        "maintenance": null,
+        "eol": false,
         "created_at": 1592299233,
         "maintainers": [
             {
@@ -415,6 +484,9 @@ select is(
             "name": "repo1",
             "display_name": "Repo 1",
             "url": "https://repo1.com",
+            "disabled": false,
+            "private": false,
+            "metadata": null,
             "user_alias": "user1",
             "organization_name": null,
             "organization_display_name": null
@@ -435,6 +507,7 @@ select is(
         "is_operator": null,
         "channels": null,
         "default_channel": null,
+        "seeking_maintainers": false,
         "display_name": "Package 2",
         "description": "description",
         "keywords": ["kw1", "kw2"],
@@ -449,11 +522,20 @@ select is(
         "deprecated": null,
         "license": null,
         "signed": null,
+        "sign_key": null,
+        "signature_verified": null,
+        "changes": null,
         "container_image": null,
+        "containers_images": null,
         "provider": null,
+        "capabilities": null,
+        "maintenance": null,
+        "eol": false,
         "created_at": 1592299234,
         "version": "1.0.0",
         "app_version": null,
+        "kube_version": null,
+        "chart_type": null,
         "available_versions": [
             {
                 "version": "1.0.0",
@@ -467,6 +549,9 @@ select is(
             "name": "repo2",
             "display_name": "Repo 2",
             "url": "https://repo2.com",
+            "disabled": false,
+            "private": false,
+            "metadata": null,
             "user_alias": null,
             "organization_name": "org1",
             "organization_display_name": "Organization 1"
@@ -475,6 +560,66 @@ select is(
     'Last package2 version is returned as a json object'
 );
 
+-- Packages in private repositories are only returned to users with read access
+update repository set private = true where repository_id = :'repo2ID';
+insert into organization (organization_id, name) values (:'org2ID', 'org2');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into user__organization (user_id, organization_id, confirmed) values (:'user2ID', :'org2ID', true);
+select is_empty(
+    $$
+        select get_package('{
+            "package_name": "package2",
+            "repository_name": "repo2"
+        }')
+    $$,
+    'Package in private repository is not returned to anonymous users'
+);
+select is_empty(
+    $$
+        select get_package('{
+            "package_name": "package2",
+            "repository_name": "repo2",
+            "user_id": "00000000-0000-0000-0000-000000000002"
+        }')
+    $$,
+    'Package in private repository is not returned to users without read access'
+);
+insert into organization_share (organization_id, shared_with_organization_id) values (:'org1ID', :'org2ID');
+select isnt_empty(
+    $$
+        select get_package('{
+            "package_name": "package2",
+            "repository_name": "repo2",
+            "user_id": "00000000-0000-0000-0000-000000000002"
+        }')
+    $$,
+    'Package in private repository is returned to users of organizations it has been shared with'
+);
+
+-- Collaborators with the read role have access to packages in private repositories
+insert into "user" (user_id, alias, email) values (:'user3ID', 'user3', 'user3@email.com');
+select is_empty(
+    $$
+        select get_package('{
+            "package_name": "package2",
+            "repository_name": "repo2",
+            "user_id": "00000000-0000-0000-0000-000000000003"
+        }')
+    $$,
+    'Package in private repository is not returned to users who do not collaborate on it'
+);
+insert into repository_collaborator (repository_id, user_id, role) values (:'repo2ID', :'user3ID', 'read');
+select isnt_empty(
+    $$
+        select get_package('{
+            "package_name": "package2",
+            "repository_name": "repo2",
+            "user_id": "00000000-0000-0000-0000-000000000003"
+        }')
+    $$,
+    'Package in private repository is returned to its read collaborators'
+);
+
 -- Finish tests and rollback transaction
 select * from finish();
 rollback;
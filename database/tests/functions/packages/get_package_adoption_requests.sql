@@ -0,0 +1,72 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set user3ID '00000000-0000-0000-0000-000000000003'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into "user" (user_id, alias, email) values (:'user3ID', 'user3', 'user3@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into package (
+    package_id,
+    name,
+    latest_version,
+    repository_id,
+    seeking_maintainers
+) values (
+    :'package1ID',
+    'package1',
+    '1.0.0',
+    :'repo1ID',
+    true
+);
+
+-- Run some tests
+select throws_ok(
+    $$
+        select get_package_adoption_requests(
+            '00000000-0000-0000-0000-000000000002',
+            '00000000-0000-0000-0000-000000000001'
+        )
+    $$,
+    42501,
+    'insufficient_privilege',
+    'User2 should not be able to get package1 adoption requests as they do not own repo1'
+);
+select is(
+    get_package_adoption_requests(:'user1ID', :'package1ID')::jsonb,
+    '[]'::jsonb,
+    'An empty json array should be returned if the package has no adoption requests'
+);
+insert into package_adoption_request (package_id, user_id, message, created_at)
+values (:'package1ID', :'user2ID', 'message2', '2020-06-16 11:20:34+02');
+insert into package_adoption_request (package_id, user_id, message, created_at)
+values (:'package1ID', :'user3ID', 'message3', '2020-06-16 11:20:35+02');
+select is(
+    get_package_adoption_requests(:'user1ID', :'package1ID')::jsonb,
+    '[
+        {
+            "user_alias": "user3",
+            "message": "message3",
+            "created_at": 1592299235
+        },
+        {
+            "user_alias": "user2",
+            "message": "message2",
+            "created_at": 1592299234
+        }
+    ]'::jsonb,
+    'Package1 adoption requests should be returned, most recent first'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,98 @@
+-- Start transaction and plan tests
+begin;
+select plan(5);
+
+-- Declare some variables
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set package2ID '00000000-0000-0000-0000-000000000002'
+
+-- No changes at this point
+select is(
+    get_package_changes('{"since": 0, "limit": 10}')::jsonb,
+    '{"changes": [], "next_cursor": null}'::jsonb,
+    'No changes in db yet, no changes expected'
+);
+
+-- Seed some data
+insert into package_change (
+    package_change_id,
+    package_id,
+    package_name,
+    package_version,
+    repository_id,
+    repository_name,
+    repository_kind_id,
+    change_kind,
+    created_at
+) values
+    (1, :'package1ID', 'package1', '1.0.0', :'repo1ID', 'repo1', 0, 'created', '2020-06-16 11:20:34+02'),
+    (2, :'package2ID', 'package2', '1.0.0', :'repo1ID', 'repo1', 0, 'created', '2020-06-16 11:20:35+02'),
+    (3, :'package1ID', 'package1', '1.0.0', :'repo1ID', 'repo1', 0, 'deleted', '2020-06-16 11:20:36+02');
+
+-- Run some tests
+select is(
+    get_package_changes('{"since": 1592299234, "limit": 10}')::jsonb,
+    '{
+        "changes": [
+            {
+                "package_change_id": 2,
+                "package_id": "00000000-0000-0000-0000-000000000002",
+                "package_name": "package2",
+                "version": "1.0.0",
+                "repository_id": "00000000-0000-0000-0000-000000000001",
+                "repository_name": "repo1",
+                "repository_kind": 0,
+                "change_kind": "created",
+                "ts": 1592299235
+            },
+            {
+                "package_change_id": 3,
+                "package_id": "00000000-0000-0000-0000-000000000001",
+                "package_name": "package1",
+                "version": "1.0.0",
+                "repository_id": "00000000-0000-0000-0000-000000000001",
+                "repository_name": "repo1",
+                "repository_kind": 0,
+                "change_kind": "deleted",
+                "ts": 1592299236
+            }
+        ],
+        "next_cursor": null
+    }'::jsonb,
+    'Changes after 1592299234 expected'
+);
+select is(
+    get_package_changes('{"since": 0, "limit": 2}')::jsonb->'next_cursor',
+    '2'::jsonb,
+    'Page full, cursor of the next page expected'
+);
+select is(
+    get_package_changes('{"since": 0, "cursor": 2, "limit": 2}')::jsonb,
+    '{
+        "changes": [
+            {
+                "package_change_id": 3,
+                "package_id": "00000000-0000-0000-0000-000000000001",
+                "package_name": "package1",
+                "version": "1.0.0",
+                "repository_id": "00000000-0000-0000-0000-000000000001",
+                "repository_name": "repo1",
+                "repository_kind": 0,
+                "change_kind": "deleted",
+                "ts": 1592299236
+            }
+        ],
+        "next_cursor": null
+    }'::jsonb,
+    'Changes after cursor 2 expected'
+);
+select is(
+    get_package_changes('{"since": 1592299236, "limit": 10}')::jsonb,
+    '{"changes": [], "next_cursor": null}'::jsonb,
+    'No changes after 1592299236 expected'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,204 @@
+-- Start transaction and plan tests
+begin;
+select plan(7);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set package2ID '00000000-0000-0000-0000-000000000002'
+\set package3ID '00000000-0000-0000-0000-000000000003'
+
+-- No packages at this point
+select is_empty(
+    $$
+        select get_package_dependencies_graph('{
+            "repository_name": "repo1",
+            "package_name": "package1"
+        }')
+    $$,
+    'No graph expected when the package does not exist'
+);
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'package1', '2.0.0', :'repo1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package2ID', 'package2', '1.0.0', :'repo1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package3ID', 'package3', '1.0.0', :'repo1ID');
+insert into snapshot (package_id, version, data) values
+    (:'package1ID', '1.0.0', '{"dependencies": [
+        {"name": "package2", "version": "1.0.0", "repository": "https://repo1.com", "package_id": "00000000-0000-0000-0000-000000000002"}
+    ]}'),
+    (:'package1ID', '2.0.0', '{"dependencies": [
+        {"name": "package2", "version": "^1.0.0", "repository": "https://repo1.com", "package_id": "00000000-0000-0000-0000-000000000002"},
+        {"name": "external", "version": "3.0.0", "repository": "https://external.com"}
+    ]}'),
+    (:'package2ID', '1.0.0', '{"dependencies": [
+        {"name": "package3", "version": "1.0.0", "repository": "https://repo1.com", "package_id": "00000000-0000-0000-0000-000000000003"}
+    ]}'),
+    (:'package3ID', '1.0.0', '{"dependencies": [
+        {"name": "package1", "version": "2.0.0", "repository": "https://repo1.com", "package_id": "00000000-0000-0000-0000-000000000001"}
+    ]}');
+
+-- Run some tests
+select is(
+    get_package_dependencies_graph('{
+        "repository_name": "repo1",
+        "package_name": "package1"
+    }')::jsonb,
+    '{
+        "nodes": [
+            {
+                "package_id": "00000000-0000-0000-0000-000000000001",
+                "name": "package1",
+                "normalized_name": "package1",
+                "version": "2.0.0",
+                "repository": {
+                    "kind": 0,
+                    "name": "repo1",
+                    "user_alias": "user1",
+                    "organization_name": null
+                }
+            },
+            {
+                "package_id": "00000000-0000-0000-0000-000000000002",
+                "name": "package2",
+                "normalized_name": "package2",
+                "version": "1.0.0",
+                "repository": {
+                    "kind": 0,
+                    "name": "repo1",
+                    "user_alias": "user1",
+                    "organization_name": null
+                }
+            },
+            {
+                "package_id": "00000000-0000-0000-0000-000000000003",
+                "name": "package3",
+                "normalized_name": "package3",
+                "version": "1.0.0",
+                "repository": {
+                    "kind": 0,
+                    "name": "repo1",
+                    "user_alias": "user1",
+                    "organization_name": null
+                }
+            }
+        ],
+        "edges": [
+            {
+                "from": "00000000-0000-0000-0000-000000000001",
+                "to": null,
+                "name": "external",
+                "version": "3.0.0",
+                "repository": "https://external.com"
+            },
+            {
+                "from": "00000000-0000-0000-0000-000000000001",
+                "to": "00000000-0000-0000-0000-000000000002",
+                "name": "package2",
+                "version": "^1.0.0",
+                "repository": "https://repo1.com"
+            },
+            {
+                "from": "00000000-0000-0000-0000-000000000002",
+                "to": "00000000-0000-0000-0000-000000000003",
+                "name": "package3",
+                "version": "1.0.0",
+                "repository": "https://repo1.com"
+            },
+            {
+                "from": "00000000-0000-0000-0000-000000000003",
+                "to": "00000000-0000-0000-0000-000000000001",
+                "name": "package1",
+                "version": "2.0.0",
+                "repository": "https://repo1.com"
+            }
+        ]
+    }'::jsonb,
+    'Dependencies graph of package1 latest version expected (cycles are not followed)'
+);
+select is(
+    get_package_dependencies_graph('{
+        "repository_name": "repo1",
+        "package_name": "package3",
+        "version": "1.0.0"
+    }')::jsonb->'nodes'->0->>'package_id',
+    '00000000-0000-0000-0000-000000000003',
+    'Requested package expected to be the first node of the graph'
+);
+select is_empty(
+    $$
+        select get_package_dependencies_graph('{
+            "repository_name": "repo1",
+            "package_name": "package1",
+            "version": "3.0.0"
+        }')
+    $$,
+    'No graph expected when the version does not exist'
+);
+
+-- Packages in private repositories are only included for users with read access
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id, private)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'user1ID', true);
+update package set repository_id = :'repo2ID' where package_id = :'package3ID';
+select is(
+    get_package_dependencies_graph('{
+        "repository_name": "repo1",
+        "package_name": "package2"
+    }')::jsonb,
+    '{
+        "nodes": [
+            {
+                "package_id": "00000000-0000-0000-0000-000000000002",
+                "name": "package2",
+                "normalized_name": "package2",
+                "version": "1.0.0",
+                "repository": {
+                    "kind": 0,
+                    "name": "repo1",
+                    "user_alias": "user1",
+                    "organization_name": null
+                }
+            }
+        ],
+        "edges": [
+            {
+                "from": "00000000-0000-0000-0000-000000000002",
+                "to": null,
+                "name": "package3",
+                "version": "1.0.0",
+                "repository": "https://repo1.com"
+            }
+        ]
+    }'::jsonb,
+    'Dependencies in private repositories are not resolved for anonymous users'
+);
+select is(
+    jsonb_array_length(get_package_dependencies_graph('{
+        "repository_name": "repo1",
+        "package_name": "package2",
+        "user_id": "00000000-0000-0000-0000-000000000001"
+    }')::jsonb->'nodes'),
+    3,
+    'Dependencies in private repositories are resolved for their owner'
+);
+select is_empty(
+    $$
+        select get_package_dependencies_graph('{
+            "repository_name": "repo2",
+            "package_name": "package3"
+        }')
+    $$,
+    'No graph expected for a package in a private repository for anonymous users'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
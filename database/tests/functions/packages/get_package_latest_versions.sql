@@ -0,0 +1,115 @@
+-- Start transaction and plan tests
+begin;
+select plan(6);
+
+-- Declare some variables
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set package2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'org1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'package1', '1.1.0', :'repo1ID');
+insert into snapshot (package_id, version, digest, created_at) values
+    (:'package1ID', '1.0.0', 'digest-1.0.0', '2020-06-16 11:20:33+02'),
+    (:'package1ID', '1.1.0', 'digest-1.1.0', '2020-06-16 11:20:34+02'),
+    (:'package1ID', '1.0.1+build.1', 'digest-1.0.1', '2020-06-16 11:20:35+02'),
+    (:'package1ID', '2.0.0-beta.1', 'digest-2.0.0-beta.1', '2020-06-16 11:20:36+02'),
+    (:'package1ID', '2.0.0-alpha.1', 'digest-2.0.0-alpha.1', '2020-06-16 11:20:37+02');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package2ID', 'package2', '1.0.0', :'repo1ID');
+insert into snapshot (package_id, version, digest, created_at)
+values (:'package2ID', '1.0.0', 'digest-1.0.0', '2020-06-16 11:20:34+02');
+
+-- Run some tests
+select is(
+    get_package_latest_versions('{
+        "package_name": "package1",
+        "repository_name": "repo1"
+    }')::jsonb,
+    '{
+        "package_id": "00000000-0000-0000-0000-000000000001",
+        "name": "package1",
+        "normalized_name": "package1",
+        "latest_stable": {
+            "version": "1.1.0",
+            "digest": "digest-1.1.0",
+            "created_at": 1592299234
+        },
+        "latest_prerelease": {
+            "version": "2.0.0-beta.1",
+            "digest": "digest-2.0.0-beta.1",
+            "created_at": 1592299236
+        }
+    }'::jsonb,
+    'Latest stable and prerelease versions of package1 expected'
+);
+select is(
+    get_package_latest_versions('{
+        "package_name": "package2",
+        "repository_name": "repo1"
+    }')::jsonb,
+    '{
+        "package_id": "00000000-0000-0000-0000-000000000002",
+        "name": "package2",
+        "normalized_name": "package2",
+        "latest_stable": {
+            "version": "1.0.0",
+            "digest": "digest-1.0.0",
+            "created_at": 1592299234
+        },
+        "latest_prerelease": null
+    }'::jsonb,
+    'Only latest stable version of package2 expected'
+);
+select is_empty(
+    $$
+        select get_package_latest_versions('{
+            "package_name": "package1",
+            "repository_name": "repo2"
+        }')
+    $$,
+    'No rows expected for a package in a repository that does not exist'
+);
+select is_empty(
+    $$
+        select get_package_latest_versions('{
+            "package_name": "package3",
+            "repository_name": "repo1"
+        }')
+    $$,
+    'No rows expected for a package that does not exist'
+);
+
+-- Packages in private repositories are only returned to users with read access
+update repository set private = true where repository_id = :'repo1ID';
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into user__organization (user_id, organization_id, confirmed) values (:'user1ID', :'org1ID', true);
+select is_empty(
+    $$
+        select get_package_latest_versions('{
+            "package_name": "package2",
+            "repository_name": "repo1"
+        }')
+    $$,
+    'No rows expected for a package in a private repository for anonymous users'
+);
+select is(
+    get_package_latest_versions('{
+        "package_name": "package2",
+        "repository_name": "repo1",
+        "user_id": "00000000-0000-0000-0000-000000000001"
+    }')::jsonb->'latest_stable'->>'version',
+    '1.0.0',
+    'Package in a private repository expected for members of the owning organization'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
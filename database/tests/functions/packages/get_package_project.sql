@@ -0,0 +1,74 @@
+-- Start transaction and plan tests
+begin;
+select plan(5);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set repo3ID '00000000-0000-0000-0000-000000000003'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set package2ID '00000000-0000-0000-0000-000000000002'
+\set package3ID '00000000-0000-0000-0000-000000000003'
+\set package4ID '00000000-0000-0000-0000-000000000004'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, url, repository_kind_id, user_id)
+values (:'repo2ID', 'repo2', 'https://repo2.com', 3, :'user1ID');
+insert into repository (repository_id, name, url, repository_kind_id, user_id)
+values (:'repo3ID', 'repo3', 'https://repo3.com', 1, :'user1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'package1', '1.0.0', :'repo1ID');
+insert into snapshot (package_id, version, links)
+values (:'package1ID', '1.0.0', '[{"name": "source", "url": "https://github.com/org/project"}]');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package2ID', 'package2', '2.0.0', :'repo2ID');
+insert into snapshot (package_id, version, links)
+values (:'package2ID', '1.0.0', '[{"name": "source", "url": "https://github.com/org/other"}]');
+insert into snapshot (package_id, version, links)
+values (:'package2ID', '2.0.0', '[{"name": "docs", "url": "https://docs.org"}, {"name": "source", "url": "http://www.GitHub.com/org/project.git/"}]');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package3ID', 'package3', '1.0.0', :'repo1ID');
+insert into snapshot (package_id, version, links)
+values (:'package3ID', '1.0.0', '[{"name": "source", "url": "https://github.com/org/project"}]');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package4ID', 'package4', '1.0.0', :'repo3ID');
+insert into snapshot (package_id, version, links)
+values (:'package4ID', '1.0.0', '[{"name": "source", "url": "https://github.com/org/other"}]');
+
+-- Run some tests
+select is(
+    (select array_agg(p->>'name') from json_array_elements((select get_package_project(null, :'package1ID'))) as p),
+    array['package2'],
+    'Packages of other kinds sharing a source url should be returned'
+);
+select is(
+    (select array_agg(p->>'name') from json_array_elements((select get_package_project(null, :'package2ID'))) as p),
+    array['package1', 'package3'],
+    'Packages of other kinds should be returned sorted by name'
+);
+select is(
+    get_package_project(null, :'package4ID')::jsonb,
+    '[]'::jsonb,
+    'No packages should be returned when the latest version sources do not match'
+);
+
+-- Packages in private repositories are only returned to users with read access
+update repository set private = true where repository_id = :'repo2ID';
+select is(
+    get_package_project(null, :'package1ID')::jsonb,
+    '[]'::jsonb,
+    'Packages in private repositories are not returned to anonymous users'
+);
+select is(
+    (select array_agg(p->>'name') from json_array_elements((select get_package_project(:'user1ID', :'package1ID'))) as p),
+    array['package2'],
+    'Packages in private repositories are returned to their owner'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
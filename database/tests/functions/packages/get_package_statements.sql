@@ -0,0 +1,112 @@
+-- Start transaction and plan tests
+begin;
+select plan(4);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into package (
+    package_id,
+    name,
+    latest_version,
+    repository_id
+) values (
+    :'package1ID',
+    'Package 1',
+    '1.0.0',
+    :'repo1ID'
+);
+
+-- Run some tests
+select is(
+    get_package_statements(null, :'package1ID')::jsonb,
+    '[]'::jsonb,
+    'An empty json array should be returned when the package has no statements'
+);
+insert into package_statement (
+    package_id,
+    kind,
+    content,
+    signature,
+    signature_algorithm,
+    signature_key_id,
+    user_id,
+    created_at,
+    updated_at
+) values (
+    :'package1ID',
+    'support-policy',
+    'Supported for 12 months',
+    'sig2',
+    'ed25519',
+    'key1',
+    :'user1ID',
+    '2020-06-16 11:20:34+02',
+    '2020-06-16 11:20:34+02'
+);
+insert into package_statement (
+    package_id,
+    kind,
+    content,
+    signature,
+    signature_algorithm,
+    created_at,
+    updated_at
+) values (
+    :'package1ID',
+    'eol',
+    'EOL on 2021-01-01',
+    'sig1',
+    'pgp',
+    '2020-06-16 11:20:33+02',
+    '2020-06-16 11:20:35+02'
+);
+select is(
+    get_package_statements(null, :'package1ID')::jsonb,
+    '[
+        {
+            "kind": "eol",
+            "content": "EOL on 2021-01-01",
+            "signature": "sig1",
+            "signature_algorithm": "pgp",
+            "signature_key_id": null,
+            "publisher": null,
+            "created_at": 1592299233,
+            "updated_at": 1592299235
+        },
+        {
+            "kind": "support-policy",
+            "content": "Supported for 12 months",
+            "signature": "sig2",
+            "signature_algorithm": "ed25519",
+            "signature_key_id": "key1",
+            "publisher": "user1",
+            "created_at": 1592299234,
+            "updated_at": 1592299234
+        }
+    ]'::jsonb,
+    'Package1 statements should be returned as a json array'
+);
+
+-- Statements of packages in private repositories are only returned to users with read access
+update repository set private = true where repository_id = :'repo1ID';
+select is(
+    get_package_statements(null, :'package1ID')::jsonb,
+    '[]'::jsonb,
+    'Statements of a package in a private repository are not returned to anonymous users'
+);
+select is(
+    jsonb_array_length(get_package_statements(:'user1ID', :'package1ID')::jsonb),
+    2,
+    'Statements of a package in a private repository are returned to its owner'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
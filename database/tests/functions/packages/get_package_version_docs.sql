@@ -0,0 +1,101 @@
+-- Start transaction and plan tests
+begin;
+select plan(6);
+
+-- Declare some variables
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'org1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'package1', '1.1.0', :'repo1ID');
+insert into snapshot (package_id, version, readme, changes, changelog) values
+    (:'package1ID', '1.0.0', 'readme-1.0.0', null, null),
+    (:'package1ID', '1.1.0', 'readme-1.1.0', '["Added feature 1"]', 'changelog-1.1.0');
+
+-- Run some tests
+select is(
+    get_package_version_docs('{
+        "package_name": "package1",
+        "repository_name": "repo1",
+        "version": "1.0.0"
+    }')::jsonb,
+    '{
+        "package_id": "00000000-0000-0000-0000-000000000001",
+        "name": "package1",
+        "normalized_name": "package1",
+        "version": "1.0.0",
+        "readme": "readme-1.0.0",
+        "changes": null,
+        "changelog": null
+    }'::jsonb,
+    'Readme and changelog of package1 version 1.0.0 expected'
+);
+select is(
+    get_package_version_docs('{
+        "package_name": "package1",
+        "repository_name": "repo1"
+    }')::jsonb,
+    '{
+        "package_id": "00000000-0000-0000-0000-000000000001",
+        "name": "package1",
+        "normalized_name": "package1",
+        "version": "1.1.0",
+        "readme": "readme-1.1.0",
+        "changes": ["Added feature 1"],
+        "changelog": "changelog-1.1.0"
+    }'::jsonb,
+    'Readme and changelog of package1 latest version expected'
+);
+select is_empty(
+    $$
+        select get_package_version_docs('{
+            "package_name": "package1",
+            "repository_name": "repo1",
+            "version": "2.0.0"
+        }')
+    $$,
+    'No rows expected for a version that does not exist'
+);
+select is_empty(
+    $$
+        select get_package_version_docs('{
+            "package_name": "package1",
+            "repository_name": "repo2"
+        }')
+    $$,
+    'No rows expected for a package in a repository that does not exist'
+);
+
+-- Packages in private repositories are only returned to users with read access
+update repository set private = true where repository_id = :'repo1ID';
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into user__organization (user_id, organization_id, confirmed) values (:'user1ID', :'org1ID', true);
+select is_empty(
+    $$
+        select get_package_version_docs('{
+            "package_name": "package1",
+            "repository_name": "repo1"
+        }')
+    $$,
+    'No rows expected for a package in a private repository for anonymous users'
+);
+select is(
+    get_package_version_docs('{
+        "package_name": "package1",
+        "repository_name": "repo1",
+        "user_id": "00000000-0000-0000-0000-000000000001"
+    }')::jsonb->>'readme',
+    'readme-1.1.0',
+    'Package in a private repository expected for members of the owning organization'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
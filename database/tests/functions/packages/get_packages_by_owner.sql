@@ -0,0 +1,146 @@
+-- Start transaction and plan tests
+begin;
+select plan(5);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set package2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'org1ID');
+insert into package (
+    package_id,
+    name,
+    latest_version,
+    repository_id
+) values (
+    :'package1ID',
+    'package1',
+    '1.0.0',
+    :'repo1ID'
+);
+insert into snapshot (
+    package_id,
+    version,
+    display_name,
+    description,
+    keywords,
+    home_url,
+    app_version
+) values
+    (:'package1ID', '0.0.9', 'Package 1', 'description', '{"kw1"}', 'home_url', '12.0.0'),
+    (:'package1ID', '1.0.0', 'Package 1', 'description', '{"kw1", "kw2"}', 'home_url', '12.1.0');
+insert into package (
+    package_id,
+    name,
+    latest_version,
+    repository_id
+) values (
+    :'package2ID',
+    'package2',
+    '1.0.0',
+    :'repo2ID'
+);
+insert into snapshot (
+    package_id,
+    version,
+    display_name,
+    description,
+    deprecated
+) values (
+    :'package2ID',
+    '1.0.0',
+    'Package 2',
+    'description',
+    true
+);
+
+-- Run some tests
+select is(
+    get_packages_by_owner(null, 'user1')::jsonb,
+    '[
+        {
+            "package_id": "00000000-0000-0000-0000-000000000001",
+            "name": "package1",
+            "normalized_name": "package1",
+            "display_name": "Package 1",
+            "description": "description",
+            "keywords": ["kw1", "kw2"],
+            "home_url": "home_url",
+            "version": "1.0.0",
+            "app_version": "12.1.0",
+            "deprecated": null,
+            "repository": {
+                "repository_id": "00000000-0000-0000-0000-000000000001",
+                "kind": 0,
+                "name": "repo1",
+                "display_name": "Repo 1",
+                "url": "https://repo1.com",
+                "user_alias": "user1",
+                "organization_name": null,
+                "organization_display_name": null
+            }
+        }
+    ]'::jsonb,
+    'Latest version of package1 expected for user1'
+);
+select is(
+    get_packages_by_owner(null, 'org1')::jsonb,
+    '[
+        {
+            "package_id": "00000000-0000-0000-0000-000000000002",
+            "name": "package2",
+            "normalized_name": "package2",
+            "display_name": "Package 2",
+            "description": "description",
+            "keywords": null,
+            "home_url": null,
+            "version": "1.0.0",
+            "app_version": null,
+            "deprecated": true,
+            "repository": {
+                "repository_id": "00000000-0000-0000-0000-000000000002",
+                "kind": 0,
+                "name": "repo2",
+                "display_name": "Repo 2",
+                "url": "https://repo2.com",
+                "user_alias": null,
+                "organization_name": "org1",
+                "organization_display_name": "Organization 1"
+            }
+        }
+    ]'::jsonb,
+    'Package2 expected for org1'
+);
+select is(
+    get_packages_by_owner(null, 'owner2')::jsonb,
+    '[]'::jsonb,
+    'No packages expected for owner2'
+);
+
+-- Packages in private repositories are only returned to users with read access
+update repository set private = true where repository_id = :'repo1ID';
+select is(
+    get_packages_by_owner(null, 'user1')::jsonb,
+    '[]'::jsonb,
+    'Packages in private repositories are not returned to anonymous users'
+);
+select is(
+    jsonb_array_length(get_packages_by_owner(:'user1ID', 'user1')::jsonb),
+    1,
+    'Packages in private repositories are returned to their owner'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(2);
+select plan(3);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
@@ -138,6 +138,17 @@ select is(
     'Stats are returned as a json object'
 );
 
+-- Packages in private repositories are not counted
+update repository set private = true where repository_id = :'repo1ID';
+select is(
+    get_packages_stats()::jsonb,
+    '{
+        "packages": 0,
+        "releases": 0
+    }'::jsonb,
+    'Packages in private repositories are not counted'
+);
+
 -- Finish tests and rollback transaction
 select * from finish();
 rollback;
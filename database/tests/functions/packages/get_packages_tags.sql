@@ -0,0 +1,60 @@
+-- Start transaction and plan tests
+begin;
+select plan(4);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set package2ID '00000000-0000-0000-0000-000000000002'
+
+-- No tags at this point
+select is(
+    get_packages_tags(10)::jsonb,
+    '[]'::jsonb,
+    'Empty tags are returned as an empty json array'
+);
+
+-- Seed some packages and their tags
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'package1', '1.0.0', :'repo1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package2ID', 'package2', '1.0.0', :'repo1ID');
+insert into package_tag (package_id, name) values (:'package1ID', 'database');
+insert into package_tag (package_id, name) values (:'package1ID', 'monitoring');
+insert into package_tag (package_id, name) values (:'package2ID', 'monitoring');
+insert into package_tag (package_id, name) values (:'package2ID', 'alerting');
+
+-- Run some tests
+select is(
+    get_packages_tags(10)::jsonb,
+    '[
+        {"name": "monitoring", "packages": 2},
+        {"name": "alerting", "packages": 1},
+        {"name": "database", "packages": 1}
+    ]'::jsonb,
+    'Tags are returned sorted by the number of packages'
+);
+select is(
+    get_packages_tags(1)::jsonb,
+    '[
+        {"name": "monitoring", "packages": 2}
+    ]'::jsonb,
+    'Tags returned are limited'
+);
+
+-- Packages in private repositories are not counted
+update repository set private = true where repository_id = :'repo1ID';
+select is(
+    get_packages_tags(10)::jsonb,
+    '[]'::jsonb,
+    'Tags of packages in private repositories are not returned'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
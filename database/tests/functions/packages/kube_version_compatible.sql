@@ -0,0 +1,49 @@
+-- Start transaction and plan tests
+begin;
+select plan(8);
+
+-- Test function
+select is(
+    kube_version_compatible('>=1.16.0, <1.20.0', '1.19'),
+    true,
+    '1.19 is compatible with >=1.16.0, <1.20.0'
+);
+select is(
+    kube_version_compatible('>=1.16.0, <1.20.0', '1.20'),
+    false,
+    '1.20 is not compatible with >=1.16.0, <1.20.0'
+);
+select is(
+    kube_version_compatible('>=1.16.0, <1.20.0', '1.15'),
+    false,
+    '1.15 is not compatible with >=1.16.0, <1.20.0'
+);
+select is(
+    kube_version_compatible('>=1.19.5', '1.19'),
+    true,
+    '1.19 is compatible with >=1.19.5'
+);
+select is(
+    kube_version_compatible('<1.19.5', '1.19'),
+    true,
+    '1.19 is compatible with <1.19.5'
+);
+select is(
+    kube_version_compatible('>=1.19.3, <1.19.7', '1.19'),
+    true,
+    '1.19 is compatible with >=1.19.3, <1.19.7'
+);
+select is(
+    kube_version_compatible('>=1.16.0, <1.17.0 || >=1.19.0', '1.18'),
+    false,
+    '1.18 is not compatible with >=1.16.0, <1.17.0 || >=1.19.0'
+);
+select is(
+    kube_version_compatible('>=1.16.0, <1.17.0 || >=1.19.0', '1.22'),
+    true,
+    '1.22 is compatible with >=1.16.0, <1.17.0 || >=1.19.0'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,159 @@
+-- Start transaction and plan tests
+begin;
+select plan(5);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set package2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com/charts/', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 1, :'user1ID');
+insert into package (
+    package_id,
+    name,
+    latest_version,
+    repository_id
+) values (
+    :'package1ID',
+    'package1',
+    '1.0.0',
+    :'repo1ID'
+);
+insert into snapshot (
+    package_id,
+    version,
+    deprecated
+) values
+    (:'package1ID', '0.9.0', true),
+    (:'package1ID', '1.0.0', false);
+insert into package (
+    package_id,
+    name,
+    latest_version,
+    repository_id
+) values (
+    :'package2ID',
+    'package2',
+    '1.0.0',
+    :'repo2ID'
+);
+insert into snapshot (package_id, version) values (:'package2ID', '1.0.0');
+
+-- Run some tests
+select is(
+    match_installed_packages(null, '[]')::jsonb,
+    '[]'::jsonb,
+    'No releases provided: empty array is returned'
+);
+select is(
+    match_installed_packages(null, '[
+        {"name": "package1", "version": "0.9.0", "repository_url": "https://REPO1.com/charts"},
+        {"name": "package1", "version": "1.0.0", "repository_url": "https://repo1.com/charts/"}
+    ]')::jsonb,
+    '[
+        {
+            "name": "package1",
+            "version": "0.9.0",
+            "repository_url": "https://REPO1.com/charts",
+            "package": {
+                "package_id": "00000000-0000-0000-0000-000000000001",
+                "name": "package1",
+                "normalized_name": "package1",
+                "latest_version": "1.0.0",
+                "repository": {
+                    "repository_id": "00000000-0000-0000-0000-000000000001",
+                    "kind": 0,
+                    "name": "repo1",
+                    "url": "https://repo1.com/charts/",
+                    "user_alias": "user1",
+                    "organization_name": null
+                }
+            },
+            "version_registered": true,
+            "update_available": true,
+            "deprecated": true,
+            "eol": false
+        },
+        {
+            "name": "package1",
+            "version": "1.0.0",
+            "repository_url": "https://repo1.com/charts/",
+            "package": {
+                "package_id": "00000000-0000-0000-0000-000000000001",
+                "name": "package1",
+                "normalized_name": "package1",
+                "latest_version": "1.0.0",
+                "repository": {
+                    "repository_id": "00000000-0000-0000-0000-000000000001",
+                    "kind": 0,
+                    "name": "repo1",
+                    "url": "https://repo1.com/charts/",
+                    "user_alias": "user1",
+                    "organization_name": null
+                }
+            },
+            "version_registered": true,
+            "update_available": false,
+            "deprecated": false,
+            "eol": false
+        }
+    ]'::jsonb,
+    'Releases matched: status of the installed versions is returned'
+);
+select is(
+    match_installed_packages(null, '[
+        {"name": "package2", "version": "1.0.0", "repository_url": "https://repo2.com"},
+        {"name": "package3", "version": "1.0.0", "repository_url": "https://repo1.com/charts"}
+    ]')::jsonb,
+    '[
+        {
+            "name": "package2",
+            "version": "1.0.0",
+            "repository_url": "https://repo2.com",
+            "package": null,
+            "version_registered": false,
+            "update_available": false,
+            "deprecated": false,
+            "eol": false
+        },
+        {
+            "name": "package3",
+            "version": "1.0.0",
+            "repository_url": "https://repo1.com/charts",
+            "package": null,
+            "version_registered": false,
+            "update_available": false,
+            "deprecated": false,
+            "eol": false
+        }
+    ]'::jsonb,
+    'Releases not matched (only Helm repositories considered): no package returned'
+);
+
+-- Packages in private repositories are only matched for users with read access
+update repository set private = true where repository_id = :'repo1ID';
+select is(
+    match_installed_packages(null, '[
+        {"name": "package1", "version": "1.0.0", "repository_url": "https://repo1.com/charts"}
+    ]')::jsonb->0->'package',
+    'null'::jsonb,
+    'Package in private repository not matched for anonymous users'
+);
+select is(
+    match_installed_packages(:'user1ID', '[
+        {"name": "package1", "version": "1.0.0", "repository_url": "https://repo1.com/charts"}
+    ]')::jsonb->0->'package'->>'package_id',
+    '00000000-0000-0000-0000-000000000001',
+    'Package in private repository matched for its owner'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
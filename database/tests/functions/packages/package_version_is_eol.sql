@@ -0,0 +1,58 @@
+-- Start transaction and plan tests
+begin;
+select plan(4);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into package (
+    package_id,
+    name,
+    latest_version,
+    repository_id
+) values (
+    :'package1ID',
+    'Package 1',
+    '2.0.0',
+    :'repo1ID'
+);
+insert into snapshot (package_id, version, maintenance)
+values (:'package1ID', '0.9.0', null);
+insert into snapshot (package_id, version, maintenance)
+values (:'package1ID', '1.0.0', '{"eol": "2020-01-01"}');
+insert into snapshot (package_id, version, maintenance)
+values (:'package1ID', '1.1.0', '{"eol": "2999-01-01"}');
+insert into snapshot (package_id, version, maintenance)
+values (:'package1ID', '2.0.0', '{"supported_versions": ">=1.0.0"}');
+
+-- Run some tests
+select is(
+    package_version_is_eol(:'package1ID', '0.9.0'),
+    true,
+    'Version 0.9.0 is not in the supported versions range declared by the latest version'
+);
+select is(
+    package_version_is_eol(:'package1ID', '1.0.0'),
+    true,
+    'Version 1.0.0 EOL date has passed'
+);
+select is(
+    package_version_is_eol(:'package1ID', '1.1.0'),
+    false,
+    'Version 1.1.0 EOL date has not passed yet and it is supported'
+);
+select is(
+    package_version_is_eol(:'package1ID', '2.0.0'),
+    false,
+    'Version 2.0.0 does not declare an EOL date and it is supported'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
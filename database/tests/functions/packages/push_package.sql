@@ -0,0 +1,62 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 3, :'user1ID');
+
+-- Run some tests
+select throws_ok(
+    $$
+        select push_package(
+            '00000000-0000-0000-0000-000000000002',
+            '00000000-0000-0000-0000-000000000001',
+            '{
+                "name": "package1",
+                "display_name": "Package 1",
+                "description": "description",
+                "version": "1.0.0"
+            }'
+        )
+    $$,
+    42501,
+    'insufficient_privilege',
+    'User without write access to the repository should not be able to push packages'
+);
+select push_package(
+    '00000000-0000-0000-0000-000000000001',
+    '00000000-0000-0000-0000-000000000001',
+    '{
+        "name": "package1",
+        "display_name": "Package 1",
+        "description": "description",
+        "version": "1.0.0",
+        "repository": {
+            "repository_id": "00000000-0000-0000-0000-000000000002"
+        }
+    }'
+);
+select results_eq(
+    $$
+        select p.name, p.latest_version, s.display_name
+        from package p
+        join snapshot s using (package_id)
+        where p.repository_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values ('package1', '1.0.0', 'Package 1')
+    $$,
+    'Package should be registered in the repository the key is scoped to'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
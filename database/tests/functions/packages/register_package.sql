@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(13);
+select plan(18);
 
 -- Declare some variables
 \set org1ID '00000000-0000-0000-0000-000000000001'
@@ -52,6 +52,8 @@ select register_package('
     },
     "version": "1.0.0",
     "app_version": "12.1.0",
+    "kube_version": ">=1.16.0, <1.20.0",
+    "chart_type": "application",
     "digest": "digest-package1-1.0.0",
     "deprecated": false,
     "license": "Apache-2.0",
@@ -59,7 +61,17 @@ select register_package('
     "content_url": "https://package.content.url",
     "is_operator": true,
     "container_image": "quay.io/org/img:1.0.0",
+    "containers_images": [
+        {
+            "image": "quay.io/org/img:1.0.0"
+        }
+    ],
     "provider": "Org Inc",
+    "capabilities": "Basic Install",
+    "maintenance": {
+        "supported_versions": ">=1.0.0",
+        "eol": "2021-06-30"
+    },
     "created_at": 1592299234,
     "maintainers": [
         {
@@ -122,6 +134,8 @@ select results_eq(
             s.keywords,
             s.home_url,
             s.app_version,
+            s.kube_version,
+            s.chart_type,
             s.digest,
             s.readme,
             s.install,
@@ -132,7 +146,10 @@ select results_eq(
             s.signed,
             s.content_url,
             s.container_image,
+            s.containers_images,
             s.provider,
+            s.capabilities,
+            s.maintenance,
             s.created_at
         from snapshot s
         join package p using (package_id)
@@ -147,6 +164,8 @@ select results_eq(
             '{kw1,kw2}'::text[],
             'home_url',
             '12.1.0',
+            '>=1.16.0, <1.20.0',
+            'application',
             'digest-package1-1.0.0',
             'readme-version-1.0.0',
             'install-version-1.0.0',
@@ -157,7 +176,10 @@ select results_eq(
             false,
             'https://package.content.url',
             'quay.io/org/img:1.0.0',
+            '[{"image": "quay.io/org/img:1.0.0"}]'::jsonb,
             'Org Inc',
+            'Basic Install',
+            '{"supported_versions": ">=1.0.0", "eol": "2021-06-30"}'::jsonb,
             '2020-06-16 11:20:34+02'::timestamptz
         )
     $$,
@@ -181,6 +203,17 @@ select results_eq(
     $$,
     'Maintainers should exist'
 );
+select results_eq(
+    $$
+        select t.name
+        from package_tag t
+        join package p using (package_id)
+        where p.name = 'package1'
+        order by t.name asc
+    $$,
+    $$ values ('kw1'), ('kw2') $$,
+    'Tags should exist'
+);
 select is_empty(
     $$
         select *
@@ -199,7 +232,7 @@ select register_package('
     "logo_image_id": "00000000-0000-0000-0000-000000000001",
     "display_name": "Package 1 v2",
     "description": "description v2",
-    "keywords": ["kw1", "kw2"],
+    "keywords": ["kw1", "kw3"],
     "home_url": "home_url",
     "readme": "readme-version-2.0.0",
     "install": "install-version-2.0.0",
@@ -208,6 +241,13 @@ select register_package('
     "digest": "digest-package1-2.0.0",
     "deprecated": true,
     "signed": true,
+    "sign_key": {
+        "key_id": "34365D9472D7468F",
+        "url": "https://keybase.io/user1/pgp_keys.asc"
+    },
+    "signature_verified": true,
+    "changes": ["Added feature 1", "Fixed bug 1"],
+    "changelog": "changelog-version-2.0.0",
     "is_operator": false,
     "container_image": "quay.io/org/img:2.0.0",
     "provider": "Org Inc 2",
@@ -247,8 +287,13 @@ select results_eq(
             s.links,
             s.deprecated,
             s.signed,
+            s.sign_key,
+            s.signature_verified,
+            s.changes,
+            s.changelog,
             s.container_image,
             s.provider,
+            s.capabilities,
             s.created_at
         from snapshot s
         join package p using (package_id)
@@ -260,7 +305,7 @@ select results_eq(
             '2.0.0',
             'Package 1 v2',
             'description v2',
-            '{kw1,kw2}'::text[],
+            '{kw1,kw3}'::text[],
             'home_url',
             '13.0.0',
             'digest-package1-2.0.0',
@@ -269,8 +314,13 @@ select results_eq(
             null::jsonb,
             true,
             true,
+            '{"key_id": "34365D9472D7468F", "url": "https://keybase.io/user1/pgp_keys.asc"}'::jsonb,
+            true,
+            '["Added feature 1", "Fixed bug 1"]'::jsonb,
+            'changelog-version-2.0.0',
             'quay.io/org/img:2.0.0',
             'Org Inc 2',
+            null,
             '2020-06-16 11:20:35+02'::timestamptz
         )
     $$,
@@ -290,6 +340,17 @@ select results_eq(
     $$ values ('name1', 'email1') $$,
     'Package maintainers should have been updated'
 );
+select results_eq(
+    $$
+        select t.name
+        from package_tag t
+        join package p using (package_id)
+        where p.name = 'package1'
+        order by t.name asc
+    $$,
+    $$ values ('kw1'), ('kw3') $$,
+    'Package tags should have been updated'
+);
 select is_empty(
     $$
         select *
@@ -373,6 +434,7 @@ select results_eq(
             s.signed,
             s.container_image,
             s.provider,
+            s.capabilities,
             s.created_at
         from snapshot s
         join package p using (package_id)
@@ -395,6 +457,7 @@ select results_eq(
             true,
             'quay.io/org/img:0.0.9',
             'Org Inc',
+            null,
             '2020-06-16 11:20:33+02'::timestamptz
         )
     $$,
@@ -414,6 +477,17 @@ select results_eq(
     $$ values ('name1', 'email1') $$,
     'Package maintainers should not have been updated'
 );
+select results_eq(
+    $$
+        select t.name
+        from package_tag t
+        join package p using (package_id)
+        where p.name = 'package1'
+        order by t.name asc
+    $$,
+    $$ values ('kw1'), ('kw3') $$,
+    'Package tags should not have been updated'
+);
 select is_empty(
     $$
         select *
@@ -424,6 +498,44 @@ select is_empty(
     $$,
     'No new release event should exist for package1 version 0.0.9'
 );
+select results_eq(
+    $$
+        select package_name, package_version, repository_name, repository_kind_id, change_kind
+        from package_change
+        order by package_change_id asc
+    $$,
+    $$
+        values
+        ('package1', '1.0.0', 'repo1', 0, 'created'),
+        ('package1', '2.0.0', 'repo1', 0, 'created'),
+        ('package1', '0.0.9', 'repo1', 0, 'created')
+    $$,
+    'Package versions changes should have been tracked'
+);
+
+-- Register package shipping some CRDs
+select register_package('
+{
+    "name": "package2",
+    "version": "1.0.0",
+    "data": {
+        "crds": [
+            {
+                "group": "cert-manager.io",
+                "kind": "Certificate",
+                "versions": ["v1"]
+            }
+        ]
+    },
+    "repository": {
+        "repository_id": "00000000-0000-0000-0000-000000000001"
+    }
+}
+');
+select ok(
+    (select tsdoc @@ to_tsquery('Certificate') and tsdoc @@ to_tsquery('cert-manager.io') from package where name = 'package2'),
+    'Package CRDs kinds and groups should be searchable'
+);
 
 -- Finish tests and rollback transaction
 select * from finish();
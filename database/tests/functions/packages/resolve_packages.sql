@@ -0,0 +1,189 @@
+-- Start transaction and plan tests
+begin;
+select plan(8);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set package2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com/charts/', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://github.com/org1/repo2', 1, :'org1ID');
+insert into package (
+    package_id,
+    name,
+    latest_version,
+    repository_id
+) values (
+    :'package1ID',
+    'package1',
+    '1.0.0',
+    :'repo1ID'
+);
+insert into snapshot (
+    package_id,
+    version,
+    links
+) values
+    (:'package1ID', '0.0.9', '[{"name": "source", "url": "https://github.com/user1/old"}]'),
+    (:'package1ID', '1.0.0', '[{"name": "source", "url": "https://github.com/user1/package1"}]');
+insert into package (
+    package_id,
+    name,
+    latest_version,
+    repository_id
+) values (
+    :'package2ID',
+    'package2',
+    '1.0.0',
+    :'repo2ID'
+);
+insert into snapshot (
+    package_id,
+    version,
+    links
+) values (
+    :'package2ID',
+    '1.0.0',
+    '[{"name": "source", "url": "https://github.com/user1/package1.git"}]'
+);
+
+-- Run some tests
+select is(
+    resolve_packages('{
+        "repository_kind": 0,
+        "repository_url": "https://REPO1.com/charts",
+        "package_name": "package1"
+    }')::jsonb,
+    '[
+        {
+            "package_id": "00000000-0000-0000-0000-000000000001",
+            "name": "package1",
+            "normalized_name": "package1",
+            "version": "1.0.0",
+            "repository": {
+                "repository_id": "00000000-0000-0000-0000-000000000001",
+                "kind": 0,
+                "name": "repo1",
+                "url": "https://repo1.com/charts/",
+                "user_alias": "user1",
+                "organization_name": null
+            }
+        }
+    ]'::jsonb,
+    'Package1 expected when resolving by repository url and package name'
+);
+select is(
+    resolve_packages('{
+        "repository_kind": 1,
+        "repository_url": "https://repo1.com/charts",
+        "package_name": "package1"
+    }')::jsonb,
+    '[]'::jsonb,
+    'No packages expected when the repository kind does not match'
+);
+select is(
+    resolve_packages('{
+        "repository_url": "https://repo1.com/charts",
+        "package_name": "package2"
+    }')::jsonb,
+    '[]'::jsonb,
+    'No packages expected when the package name does not match'
+);
+select is(
+    resolve_packages('{
+        "source_url": "https://github.com/user1/package1/"
+    }')::jsonb,
+    '[
+        {
+            "package_id": "00000000-0000-0000-0000-000000000001",
+            "name": "package1",
+            "normalized_name": "package1",
+            "version": "1.0.0",
+            "repository": {
+                "repository_id": "00000000-0000-0000-0000-000000000001",
+                "kind": 0,
+                "name": "repo1",
+                "url": "https://repo1.com/charts/",
+                "user_alias": "user1",
+                "organization_name": null
+            }
+        },
+        {
+            "package_id": "00000000-0000-0000-0000-000000000002",
+            "name": "package2",
+            "normalized_name": "package2",
+            "version": "1.0.0",
+            "repository": {
+                "repository_id": "00000000-0000-0000-0000-000000000002",
+                "kind": 1,
+                "name": "repo2",
+                "url": "https://github.com/org1/repo2",
+                "user_alias": null,
+                "organization_name": "org1"
+            }
+        }
+    ]'::jsonb,
+    'Package1 and package2 expected when resolving by source url'
+);
+select is(
+    resolve_packages('{
+        "repository_kind": 1,
+        "source_url": "https://github.com/user1/package1"
+    }')::jsonb,
+    '[
+        {
+            "package_id": "00000000-0000-0000-0000-000000000002",
+            "name": "package2",
+            "normalized_name": "package2",
+            "version": "1.0.0",
+            "repository": {
+                "repository_id": "00000000-0000-0000-0000-000000000002",
+                "kind": 1,
+                "name": "repo2",
+                "url": "https://github.com/org1/repo2",
+                "user_alias": null,
+                "organization_name": "org1"
+            }
+        }
+    ]'::jsonb,
+    'Only package2 expected when resolving by source url and kind 1'
+);
+select is(
+    resolve_packages('{
+        "source_url": "https://github.com/user1/old"
+    }')::jsonb,
+    '[]'::jsonb,
+    'No packages expected when the source url only belongs to a previous version'
+);
+
+-- Packages in private repositories are only returned to users with read access
+update repository set private = true where repository_id = :'repo1ID';
+select is(
+    (select array_agg(p->>'name') from json_array_elements((select resolve_packages('{
+        "source_url": "https://github.com/user1/package1"
+    }'))) as p),
+    array['package2'],
+    'Packages in private repositories are not returned to anonymous users'
+);
+select is(
+    (select array_agg(p->>'name') from json_array_elements((select resolve_packages('{
+        "source_url": "https://github.com/user1/package1",
+        "user_id": "00000000-0000-0000-0000-000000000001"
+    }'))) as p),
+    array['package1', 'package2'],
+    'Packages in private repositories are returned to their owner'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
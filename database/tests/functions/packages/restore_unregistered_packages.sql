@@ -0,0 +1,99 @@
+-- Start transaction and plan tests
+begin;
+select plan(9);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set package2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'package1', '2.0.0', :'repo1ID');
+insert into snapshot (package_id, version, description) values (:'package1ID', '2.0.0', 'description 2.0.0');
+insert into snapshot (package_id, version, description) values (:'package1ID', '1.0.0', 'description 1.0.0');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package2ID', 'package2', '1.0.0', :'repo1ID');
+insert into snapshot (package_id, version, description) values (:'package2ID', '1.0.0', 'description 1.0.0');
+
+-- Unregister all packages versions
+select unregister_package('{"name": "package1", "version": "2.0.0", "repository": {"repository_id": "00000000-0000-0000-0000-000000000001"}}');
+select unregister_package('{"name": "package1", "version": "1.0.0", "repository": {"repository_id": "00000000-0000-0000-0000-000000000001"}}');
+select unregister_package('{"name": "package2", "version": "1.0.0", "repository": {"repository_id": "00000000-0000-0000-0000-000000000001"}}');
+
+-- Run some tests
+select throws_ok(
+    $$ select restore_unregistered_packages('repo2', null) $$,
+    'repository not found: repo2',
+    'Repositories that do not exist should be rejected'
+);
+select is(
+    restore_unregistered_packages('repo1', 'package1'),
+    2::bigint,
+    'Two versions of package1 should have been restored'
+);
+select results_eq(
+    $$
+        select p.package_id, p.latest_version, s.version, s.description
+        from package p
+        join snapshot s using (package_id)
+        order by s.version desc
+    $$,
+    $$
+        values
+        ('00000000-0000-0000-0000-000000000001'::uuid, '2.0.0', '2.0.0', 'description 2.0.0'),
+        ('00000000-0000-0000-0000-000000000001'::uuid, '2.0.0', '1.0.0', 'description 1.0.0')
+    $$,
+    'Package1 should have been registered again with all its versions'
+);
+select results_eq(
+    $$ select package_name, package_version from package_tombstone $$,
+    $$ values ('package2', '1.0.0') $$,
+    'Only the tombstone of package2 should be kept'
+);
+
+-- Register again a version of package2 before restoring it
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package2ID', 'package2', '1.0.0', :'repo1ID');
+insert into snapshot (package_id, version, description) values (:'package2ID', '1.0.0', 'new description');
+select is(
+    restore_unregistered_packages('repo1', null),
+    0::bigint,
+    'Versions registered again should not be restored'
+);
+select results_eq(
+    $$ select description from snapshot where package_id = '00000000-0000-0000-0000-000000000002' $$,
+    $$ values ('new description') $$,
+    'Versions registered again should be left untouched'
+);
+select is_empty(
+    $$ select * from package_tombstone $$,
+    'All tombstones should have been deleted'
+);
+select results_eq(
+    $$
+        select package_name, package_version, change_kind
+        from package_change
+        where change_kind = 'created'
+        order by package_change_id asc
+    $$,
+    $$
+        values
+        ('package1', '2.0.0', 'created'),
+        ('package1', '1.0.0', 'created')
+    $$,
+    'Restored versions should have been tracked'
+);
+select is(
+    restore_unregistered_packages('repo1', null),
+    0::bigint,
+    'Nothing should be restored when there are no tombstones'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
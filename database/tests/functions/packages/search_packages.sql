@@ -1,10 +1,12 @@
 -- Start transaction and plan tests
 begin;
-select plan(22);
+select plan(36);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
 \set org1ID '00000000-0000-0000-0000-000000000001'
+\set org2ID '00000000-0000-0000-0000-000000000002'
 \set repo1ID '00000000-0000-0000-0000-000000000001'
 \set repo2ID '00000000-0000-0000-0000-000000000002'
 \set repo3ID '00000000-0000-0000-0000-000000000003'
@@ -229,6 +231,7 @@ select is(
                 "app_version": "12.1.0",
                 "deprecated": null,
                 "signed": null,
+                "capabilities": null,
                 "created_at": 1592299234,
                 "repository": {
                     "repository_id": "00000000-0000-0000-0000-000000000001",
@@ -252,6 +255,7 @@ select is(
                 "app_version": "12.1.0",
                 "deprecated": true,
                 "signed": true,
+                "capabilities": null,
                 "created_at": 1592299234,
                 "repository": {
                     "repository_id": "00000000-0000-0000-0000-000000000002",
@@ -275,6 +279,7 @@ select is(
                 "app_version": null,
                 "deprecated": null,
                 "signed": null,
+                "capabilities": null,
                 "created_at": 1592299234,
                 "repository": {
                     "repository_id": "00000000-0000-0000-0000-000000000003",
@@ -331,6 +336,10 @@ select is(
                     "name": "Repo3",
                     "total": 1
                 }]
+            }, {
+                "title": "Chart type",
+                "filter_key": "chart_type",
+                "options": []
             }]
         },
         "metadata": {
@@ -360,6 +369,7 @@ select is(
                 "app_version": "12.1.0",
                 "deprecated": null,
                 "signed": null,
+                "capabilities": null,
                 "created_at": 1592299234,
                 "repository": {
                     "repository_id": "00000000-0000-0000-0000-000000000001",
@@ -383,6 +393,7 @@ select is(
                 "app_version": "12.1.0",
                 "deprecated": true,
                 "signed": true,
+                "capabilities": null,
                 "created_at": 1592299234,
                 "repository": {
                     "repository_id": "00000000-0000-0000-0000-000000000002",
@@ -406,6 +417,7 @@ select is(
                 "app_version": null,
                 "deprecated": null,
                 "signed": null,
+                "capabilities": null,
                 "created_at": 1592299234,
                 "repository": {
                     "repository_id": "00000000-0000-0000-0000-000000000003",
@@ -446,6 +458,7 @@ select is(
                 "app_version": "12.1.0",
                 "deprecated": null,
                 "signed": null,
+                "capabilities": null,
                 "created_at": 1592299234,
                 "repository": {
                     "repository_id": "00000000-0000-0000-0000-000000000001",
@@ -488,6 +501,7 @@ select is(
                 "app_version": "12.1.0",
                 "deprecated": null,
                 "signed": null,
+                "capabilities": null,
                 "created_at": 1592299234,
                 "repository": {
                     "repository_id": "00000000-0000-0000-0000-000000000001",
@@ -511,6 +525,7 @@ select is(
                 "app_version": "12.1.0",
                 "deprecated": true,
                 "signed": true,
+                "capabilities": null,
                 "created_at": 1592299234,
                 "repository": {
                     "repository_id": "00000000-0000-0000-0000-000000000002",
@@ -553,6 +568,7 @@ select is(
                 "app_version": "12.1.0",
                 "deprecated": null,
                 "signed": null,
+                "capabilities": null,
                 "created_at": 1592299234,
                 "repository": {
                     "repository_id": "00000000-0000-0000-0000-000000000001",
@@ -576,6 +592,7 @@ select is(
                 "app_version": "12.1.0",
                 "deprecated": true,
                 "signed": true,
+                "capabilities": null,
                 "created_at": 1592299234,
                 "repository": {
                     "repository_id": "00000000-0000-0000-0000-000000000002",
@@ -624,6 +641,10 @@ select is(
                     "name": "Repo2",
                     "total": 1
                 }]
+            }, {
+                "title": "Chart type",
+                "filter_key": "chart_type",
+                "options": []
             }]
         },
         "metadata": {
@@ -653,6 +674,7 @@ select is(
                 "app_version": "12.1.0",
                 "deprecated": null,
                 "signed": null,
+                "capabilities": null,
                 "created_at": 1592299234,
                 "repository": {
                     "repository_id": "00000000-0000-0000-0000-000000000001",
@@ -693,6 +715,10 @@ select is(
                     "name": "Repo1",
                     "total": 1
                 }]
+            }, {
+                "title": "Chart type",
+                "filter_key": "chart_type",
+                "options": []
             }]
         },
         "metadata": {
@@ -742,6 +768,7 @@ select is(
                 "app_version": "12.1.0",
                 "deprecated": null,
                 "signed": null,
+                "capabilities": null,
                 "created_at": 1592299234,
                 "repository": {
                     "repository_id": "00000000-0000-0000-0000-000000000001",
@@ -784,6 +811,7 @@ select is(
                 "app_version": null,
                 "deprecated": null,
                 "signed": null,
+                "capabilities": null,
                 "created_at": 1592299234,
                 "repository": {
                     "repository_id": "00000000-0000-0000-0000-000000000003",
@@ -826,6 +854,7 @@ select is(
                 "app_version": "12.1.0",
                 "deprecated": null,
                 "signed": null,
+                "capabilities": null,
                 "created_at": 1592299234,
                 "repository": {
                     "repository_id": "00000000-0000-0000-0000-000000000001",
@@ -872,6 +901,7 @@ select is(
                 "app_version": "12.1.0",
                 "deprecated": true,
                 "signed": true,
+                "capabilities": null,
                 "created_at": 1592299234,
                 "repository": {
                     "repository_id": "00000000-0000-0000-0000-000000000002",
@@ -920,6 +950,10 @@ select is(
                     "name": "Repo1",
                     "total": 1
                 }]
+            }, {
+                "title": "Chart type",
+                "filter_key": "chart_type",
+                "options": []
             }]
         },
         "metadata": {
@@ -971,6 +1005,10 @@ select is(
                     "name": "Repo1",
                     "total": 1
                 }]
+            }, {
+                "title": "Chart type",
+                "filter_key": "chart_type",
+                "options": []
             }]
         },
         "metadata": {
@@ -1021,6 +1059,10 @@ select is(
                     "name": "Repo1",
                     "total": 1
                 }]
+            }, {
+                "title": "Chart type",
+                "filter_key": "chart_type",
+                "options": []
             }]
         },
         "metadata": {
@@ -1070,6 +1112,10 @@ select is(
                     "name": "Repo1",
                     "total": 1
                 }]
+            }, {
+                "title": "Chart type",
+                "filter_key": "chart_type",
+                "options": []
             }]
         },
         "metadata": {
@@ -1122,6 +1168,7 @@ select is(
                 "app_version": "12.1.0",
                 "deprecated": null,
                 "signed": null,
+                "capabilities": null,
                 "created_at": 1592299234,
                 "repository": {
                     "repository_id": "00000000-0000-0000-0000-000000000001",
@@ -1145,6 +1192,7 @@ select is(
                 "app_version": "12.1.0",
                 "deprecated": true,
                 "signed": true,
+                "capabilities": null,
                 "created_at": 1592299234,
                 "repository": {
                     "repository_id": "00000000-0000-0000-0000-000000000002",
@@ -1188,6 +1236,7 @@ select is(
                 "app_version": "12.1.0",
                 "deprecated": null,
                 "signed": null,
+                "capabilities": null,
                 "created_at": 1592299234,
                 "repository": {
                     "repository_id": "00000000-0000-0000-0000-000000000001",
@@ -1251,6 +1300,7 @@ select is(
                 "app_version": "12.1.0",
                 "deprecated": true,
                 "signed": true,
+                "capabilities": null,
                 "created_at": 1592299234,
                 "repository": {
                     "repository_id": "00000000-0000-0000-0000-000000000002",
@@ -1340,6 +1390,10 @@ select is(
                     "name": "Repo2",
                     "total": 1
                 }]
+            }, {
+                "title": "Chart type",
+                "filter_key": "chart_type",
+                "options": []
             }]
         },
         "metadata": {
@@ -1351,6 +1405,340 @@ select is(
     'Limit: 1 Offset: 2 TsQueryWeb: kw1 | No packages expected - Facets expected'
 );
 
+-- Set some packages capabilities
+update snapshot set capabilities = 'Basic Install'
+where package_id = :'package1ID' and version = '1.0.0';
+update snapshot set capabilities = 'Full Lifecycle'
+where package_id = :'package2ID' and version = '1.0.0';
+
+select is(
+    search_packages('{
+        "ts_query_web": "kw1",
+        "capabilities": ["Basic Install"],
+        "deprecated": true
+    }')::jsonb,
+    '{
+        "data": {
+            "packages": [{
+                "package_id": "00000000-0000-0000-0000-000000000001",
+                "name": "package1",
+                "normalized_name": "package1",
+                "logo_image_id": "00000000-0000-0000-0000-000000000001",
+                "stars": 10,
+                "display_name": "Package 1",
+                "description": "description",
+                "version": "1.0.0",
+                "app_version": "12.1.0",
+                "deprecated": null,
+                "signed": null,
+                "capabilities": "Basic Install",
+                "created_at": 1592299234,
+                "repository": {
+                    "repository_id": "00000000-0000-0000-0000-000000000001",
+                    "kind": 0,
+                    "name": "repo1",
+                    "display_name": "Repo 1",
+                    "url": "https://repo1.com",
+                    "user_alias": "user1",
+                    "organization_name": null,
+                    "organization_display_name": null
+                }
+            }],
+            "facets": null
+        },
+        "metadata": {
+            "limit": null,
+            "offset": null,
+            "total": 1
+        }
+    }'::jsonb,
+    'TsQueryWeb: kw1 Capabilities: Basic Install | Package 1 expected'
+);
+select is(
+    search_packages('{
+        "limit": 1,
+        "offset": 0,
+        "ts_query_web": "kw1",
+        "deprecated": true,
+        "sort": "capabilities"
+    }')::jsonb,
+    '{
+        "data": {
+            "packages": [{
+                "package_id": "00000000-0000-0000-0000-000000000002",
+                "name": "package2",
+                "normalized_name": "package2",
+                "logo_image_id": "00000000-0000-0000-0000-000000000002",
+                "stars": 11,
+                "display_name": "Package 2",
+                "description": "description",
+                "version": "1.0.0",
+                "app_version": "12.1.0",
+                "deprecated": true,
+                "signed": true,
+                "capabilities": "Full Lifecycle",
+                "created_at": 1592299234,
+                "repository": {
+                    "repository_id": "00000000-0000-0000-0000-000000000002",
+                    "kind": 0,
+                    "name": "repo2",
+                    "display_name": "Repo 2",
+                    "url": "https://repo2.com",
+                    "user_alias": null,
+                    "organization_name": "org1",
+                    "organization_display_name": "Organization 1"
+                }
+            }],
+            "facets": null
+        },
+        "metadata": {
+            "limit": 1,
+            "offset": 0,
+            "total": 2
+        }
+    }'::jsonb,
+    'Limit: 1 Offset: 0 TsQueryWeb: kw1 Sort: capabilities | Package 2 expected'
+);
+
+-- Set some packages EOL dates
+update snapshot set maintenance = '{"eol": "2020-01-01"}'
+where package_id = :'package2ID' and version = '1.0.0';
+
+select is(
+    search_packages('{
+        "ts_query_web": "kw1",
+        "deprecated": true,
+        "supported_only": true
+    }')::jsonb,
+    '{
+        "data": {
+            "packages": [{
+                "package_id": "00000000-0000-0000-0000-000000000001",
+                "name": "package1",
+                "normalized_name": "package1",
+                "logo_image_id": "00000000-0000-0000-0000-000000000001",
+                "stars": 10,
+                "display_name": "Package 1",
+                "description": "description",
+                "version": "1.0.0",
+                "app_version": "12.1.0",
+                "deprecated": null,
+                "signed": null,
+                "capabilities": "Basic Install",
+                "created_at": 1592299234,
+                "repository": {
+                    "repository_id": "00000000-0000-0000-0000-000000000001",
+                    "kind": 0,
+                    "name": "repo1",
+                    "display_name": "Repo 1",
+                    "url": "https://repo1.com",
+                    "user_alias": "user1",
+                    "organization_name": null,
+                    "organization_display_name": null
+                }
+            }],
+            "facets": null
+        },
+        "metadata": {
+            "limit": null,
+            "offset": null,
+            "total": 1
+        }
+    }'::jsonb,
+    'TsQueryWeb: kw1 SupportedOnly: true | Package 1 expected'
+);
+select is(
+    (search_packages('{
+        "facets": true,
+        "deprecated": true,
+        "scope_org": "org1"
+    }')::jsonb)->'data'->'facets',
+    '[{
+        "title": "Organization",
+        "filter_key": "org",
+        "options": [{
+            "id": "org1",
+            "name": "Organization 1",
+            "total": 2
+        }]
+    }, {
+        "title": "User",
+        "filter_key": "user",
+        "options": []
+    }, {
+        "title": "Kind",
+        "filter_key": "kind",
+        "options": [{
+            "id": 1,
+            "name": "Falco rules",
+            "total": 1
+        }, {
+            "id": 0,
+            "name": "Helm charts",
+            "total": 1
+        }]
+    }, {
+        "title": "Repository",
+        "filter_key": "repo",
+        "options": [{
+            "id": "repo2",
+            "name": "Repo2",
+            "total": 1
+        }, {
+            "id": "repo3",
+            "name": "Repo3",
+            "total": 1
+        }]
+    }, {
+        "title": "Chart type",
+        "filter_key": "chart_type",
+        "options": []
+    }]'::jsonb,
+    'ScopeOrg: org1 | Facets computed only from org1 packages expected'
+);
+select is(
+    (search_packages('{
+        "deprecated": true,
+        "scope_repository": "repo1"
+    }')::jsonb)->'data'->'packages'->0->>'name',
+    'package1',
+    'ScopeRepository: repo1 | Package 1 expected'
+);
+select is(
+    (search_packages('{
+        "deprecated": true,
+        "scope_user": "user1",
+        "scope_org": "org1"
+    }')::jsonb)->'metadata'->>'total',
+    '0',
+    'ScopeUser: user1 ScopeOrg: org1 | No packages expected'
+);
+
+-- Set some packages kubeVersion constraints
+update snapshot set kube_version = '>=1.16.0, <1.18.0'
+where package_id = :'package1ID' and version = '1.0.0';
+update snapshot set kube_version = '>=1.14.0, <1.15.0 || >=1.18.0'
+where package_id = :'package2ID' and version = '1.0.0';
+
+select is(
+    (
+        select array_agg(p->>'name' order by p->>'name')
+        from jsonb_array_elements((search_packages('{
+            "deprecated": true,
+            "kubernetes_version": "1.19"
+        }')::jsonb)->'data'->'packages') p
+    ),
+    array['package2', 'package3'],
+    'KubernetesVersion: 1.19 | Packages 2 and 3 expected'
+);
+select is(
+    (
+        select array_agg(p->>'name' order by p->>'name')
+        from jsonb_array_elements((search_packages('{
+            "deprecated": true,
+            "kubernetes_version": "1.17"
+        }')::jsonb)->'data'->'packages') p
+    ),
+    array['package1', 'package3'],
+    'KubernetesVersion: 1.17 | Packages 1 and 3 expected'
+);
+
+-- Set some packages chart types
+update snapshot set chart_type = 'application'
+where package_id = :'package1ID' and version = '1.0.0';
+update snapshot set chart_type = 'library'
+where package_id = :'package2ID' and version = '1.0.0';
+
+select is(
+    (search_packages('{
+        "facets": true,
+        "deprecated": true
+    }')::jsonb)->'data'->'facets'->4,
+    '{
+        "title": "Chart type",
+        "filter_key": "chart_type",
+        "options": [{
+            "id": "application",
+            "name": "Application",
+            "total": 1
+        }, {
+            "id": "library",
+            "name": "Library",
+            "total": 1
+        }]
+    }'::jsonb,
+    'Facets: true | Chart type facet expected'
+);
+select is(
+    (
+        select array_agg(p->>'name' order by p->>'name')
+        from jsonb_array_elements((search_packages('{
+            "deprecated": true,
+            "chart_types": ["application"]
+        }')::jsonb)->'data'->'packages') p
+    ),
+    array['package1'],
+    'ChartTypes: application | Package 1 expected'
+);
+
+-- Set some packages tags
+insert into package_tag (package_id, name) values (:'package1ID', 'database');
+insert into package_tag (package_id, name) values (:'package1ID', 'monitoring');
+insert into package_tag (package_id, name) values (:'package2ID', 'monitoring');
+insert into package_tag (package_id, name) values (:'package3ID', 'networking');
+
+select is(
+    (
+        select array_agg(p->>'name' order by p->>'name')
+        from jsonb_array_elements((search_packages('{
+            "deprecated": true,
+            "tags": ["monitoring"]
+        }')::jsonb)->'data'->'packages') p
+    ),
+    array['package1', 'package2'],
+    'Tags: monitoring | Packages 1 and 2 expected'
+);
+select is(
+    (
+        select array_agg(p->>'name' order by p->>'name')
+        from jsonb_array_elements((search_packages('{
+            "deprecated": true,
+            "tags": ["database", "networking"]
+        }')::jsonb)->'data'->'packages') p
+    ),
+    array['package1', 'package3'],
+    'Tags: database, networking | Packages 1 and 3 expected'
+);
+
+-- Packages in private repositories are only returned to users with read access
+update repository set private = true where repository_id = :'repo2ID';
+insert into organization (organization_id, name) values (:'org2ID', 'org2');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into user__organization (user_id, organization_id, confirmed) values (:'user2ID', :'org2ID', true);
+select is(
+    (
+        select array_agg(p->>'name' order by p->>'name')
+        from jsonb_array_elements((search_packages('{
+            "deprecated": true,
+            "user_id": "00000000-0000-0000-0000-000000000002"
+        }')::jsonb)->'data'->'packages') p
+    ),
+    array['package1', 'package3'],
+    'Private repository | User without read access | Packages 1 and 3 expected'
+);
+insert into organization_share (organization_id, shared_with_organization_id) values (:'org1ID', :'org2ID');
+select is(
+    (
+        select array_agg(p->>'name' order by p->>'name')
+        from jsonb_array_elements((search_packages('{
+            "deprecated": true,
+            "user_id": "00000000-0000-0000-0000-000000000002"
+        }')::jsonb)->'data'->'packages') p
+    ),
+    array['package1', 'package2', 'package3'],
+    'Private repository | Repository shared with user organization | Packages 1, 2 and 3 expected'
+);
+
 -- Finish tests and rollback transaction
 select * from finish();
 rollback;
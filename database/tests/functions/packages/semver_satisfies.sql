@@ -0,0 +1,49 @@
+-- Start transaction and plan tests
+begin;
+select plan(8);
+
+-- Test function
+select is(
+    semver_satisfies('1.0.0', '>=1.0.0'),
+    true,
+    '1.0.0 satisfies >=1.0.0'
+);
+select is(
+    semver_satisfies('0.9.0', '>=1.0.0'),
+    false,
+    '0.9.0 does not satisfy >=1.0.0'
+);
+select is(
+    semver_satisfies('1.5.0', '>=1.0.0, <2.0.0'),
+    true,
+    '1.5.0 satisfies >=1.0.0, <2.0.0'
+);
+select is(
+    semver_satisfies('2.0.0', '>=1.0.0, <2.0.0'),
+    false,
+    '2.0.0 does not satisfy >=1.0.0, <2.0.0'
+);
+select is(
+    semver_satisfies('2.0.0', '>1.0.0,<=2.0.0'),
+    true,
+    '2.0.0 satisfies >1.0.0,<=2.0.0'
+);
+select is(
+    semver_satisfies('1.0.0', '>1.0.0'),
+    false,
+    '1.0.0 does not satisfy >1.0.0'
+);
+select is(
+    semver_satisfies('1.2.3', '1.2.3'),
+    true,
+    '1.2.3 satisfies 1.2.3'
+);
+select is(
+    semver_satisfies('1.2.4', '=1.2.3'),
+    false,
+    '1.2.4 does not satisfy =1.2.3'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(10);
+select plan(12);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
@@ -117,6 +117,36 @@ select is_empty(
     $$ select * from maintainer $$,
     'Orphan maintainer should have been deleted'
 );
+select results_eq(
+    $$
+        select package_name, package_version, change_kind
+        from package_change
+        order by package_change_id asc
+    $$,
+    $$
+        values
+        ('package1', '1.0.0', 'deleted'),
+        ('package1', '0.0.9', 'deleted'),
+        ('package1', '0.0.9-rc1', 'deleted'),
+        ('package1', '0.0.9-rc2', 'deleted')
+    $$,
+    'Package versions deletions should have been tracked'
+);
+select results_eq(
+    $$
+        select package_name, package_version, snapshot->>'version', package->>'package_id'
+        from package_tombstone
+        order by package_tombstone_id asc
+    $$,
+    $$
+        values
+        ('package1', '1.0.0', '1.0.0', '00000000-0000-0000-0000-000000000001'),
+        ('package1', '0.0.9', '0.0.9', '00000000-0000-0000-0000-000000000001'),
+        ('package1', '0.0.9-rc1', '0.0.9-rc1', '00000000-0000-0000-0000-000000000001'),
+        ('package1', '0.0.9-rc2', '0.0.9-rc2', '00000000-0000-0000-0000-000000000001')
+    $$,
+    'Tombstones of the unregistered package versions should have been kept'
+);
 
 -- Finish tests and rollback transaction
 select * from finish();
@@ -0,0 +1,46 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set image1ID '00000000-0000-0000-0000-000000000001'
+\set image2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into package (
+    package_id,
+    name,
+    latest_version,
+    logo_url,
+    repository_id
+) values (
+    :'package1ID',
+    'package1',
+    '1.0.0',
+    'logo_url',
+    :'repo1ID'
+);
+
+-- Run some tests
+select update_package_logo_image(:'repo1ID', 'package1', 'logo_url', :'image1ID');
+select results_eq(
+    $$ select logo_image_id from package where name = 'package1' $$,
+    $$ values ('00000000-0000-0000-0000-000000000001'::uuid) $$,
+    'Package logo image id should have been updated'
+);
+select update_package_logo_image(:'repo1ID', 'package1', 'old_logo_url', :'image2ID');
+select results_eq(
+    $$ select logo_image_id from package where name = 'package1' $$,
+    $$ values ('00000000-0000-0000-0000-000000000001'::uuid) $$,
+    'Package logo image id should not have been updated as logo url does not match'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,64 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into package (
+    package_id,
+    name,
+    latest_version,
+    repository_id
+) values (
+    :'package1ID',
+    'Package 1',
+    '1.0.0',
+    :'repo1ID'
+);
+
+-- Run some tests
+select throws_ok(
+    $$
+        select update_package_seeking_maintainers(
+            '00000000-0000-0000-0000-000000000002',
+            '00000000-0000-0000-0000-000000000001',
+            true
+        )
+    $$,
+    42501,
+    'insufficient_privilege',
+    'User2 should not be able to update package1 as they do not own repo1'
+);
+select update_package_seeking_maintainers(:'user1ID', :'package1ID', true);
+select is(
+    (select seeking_maintainers from package where package_id = :'package1ID'),
+    true,
+    'Package1 should be seeking maintainers'
+);
+insert into package_adoption_request (package_id, user_id, message)
+values (:'package1ID', :'user2ID', 'I would like to help');
+select update_package_seeking_maintainers(:'user1ID', :'package1ID', false);
+select results_eq(
+    $$
+        select p.seeking_maintainers, (select count(*) from package_adoption_request)
+        from package p
+        where p.package_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values (false, 0::bigint)
+    $$,
+    'Package1 should not be seeking maintainers and its adoption requests should have been removed'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -19,7 +19,19 @@ select add_repository(:'user1ID', null, '
     "name": "repo1",
     "display_name": "Repository 1",
     "url": "repo1_url",
-    "kind": 0
+    "kind": 0,
+    "tracking_interval": 60,
+    "quiet_hours": {"start": 22, "end": 6},
+    "private": true,
+    "metadata": {"team": "team1", "tier": "gold"},
+    "branch": "release-1.0",
+    "path": "packages",
+    "skip_prereleases": true,
+    "auth_user": "user1",
+    "auth_pass": "pass1",
+    "tls_ca_cert": "ca1",
+    "tls_client_cert": "cert1",
+    "tls_client_key": "key1"
 }
 '::jsonb);
 select results_eq(
@@ -29,6 +41,20 @@ select results_eq(
             display_name,
             url,
             repository_kind_id,
+            tracking_interval,
+            quiet_hours,
+            private,
+            metadata,
+            branch,
+            path,
+            tag_pattern,
+            skip_prereleases,
+            skip_deprecated,
+            auth_user,
+            auth_pass,
+            tls_ca_cert,
+            tls_client_cert,
+            tls_client_key,
             user_id,
             organization_id
         from repository
@@ -40,6 +66,20 @@ select results_eq(
             'Repository 1',
             'repo1_url',
             0,
+            60,
+            '{"start": 22, "end": 6}'::jsonb,
+            true,
+            '{"team": "team1", "tier": "gold"}'::jsonb,
+            'release-1.0',
+            'packages',
+            null,
+            true,
+            false,
+            'user1',
+            'pass1',
+            'ca1',
+            'cert1',
+            'key1',
             '00000000-0000-0000-0000-000000000001'::uuid,
             null::uuid
         )
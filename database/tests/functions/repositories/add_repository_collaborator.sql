@@ -0,0 +1,55 @@
+-- Start transaction and plan tests
+begin;
+select plan(4);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set user3ID '00000000-0000-0000-0000-000000000003'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, first_name, last_name, email)
+values (:'user1ID', 'user1', 'firstname1', 'lastname1', 'user1@email.com');
+insert into "user" (user_id, alias, first_name, last_name, email)
+values (:'user2ID', 'user2', 'firstname2', 'lastname2', 'user2@email.com');
+insert into "user" (user_id, alias, first_name, last_name, email)
+values (:'user3ID', 'user3', 'firstname3', 'lastname3', 'user3@email.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'org1ID');
+
+-- Run some tests
+select throws_ok(
+    $$ select add_repository_collaborator('00000000-0000-0000-0000-000000000002', 'repo1', 'user3', 'read') $$,
+    42501,
+    'insufficient_privilege',
+    'User2 should not be able to add collaborators to repo1 as they do not own it'
+);
+select throws_ok(
+    $$ select add_repository_collaborator('00000000-0000-0000-0000-000000000001', 'repo1', 'user4', 'read') $$,
+    'user not found',
+    'Collaborators must be existing users'
+);
+select add_repository_collaborator(:'user1ID', 'repo2', 'user2', 'read');
+select results_eq(
+    $$ select repository_id, user_id, role from repository_collaborator $$,
+    $$ values ('00000000-0000-0000-0000-000000000002'::uuid, '00000000-0000-0000-0000-000000000002'::uuid, 'read') $$,
+    'User2 should have been added as a read collaborator of repo2'
+);
+select add_repository_collaborator(:'user1ID', 'repo2', 'user2', 'write');
+select results_eq(
+    $$ select repository_id, user_id, role from repository_collaborator $$,
+    $$ values ('00000000-0000-0000-0000-000000000002'::uuid, '00000000-0000-0000-0000-000000000002'::uuid, 'write') $$,
+    'User2 role in repo2 should have been updated to write'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(4);
+select plan(5);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
@@ -8,6 +8,7 @@ select plan(4);
 \set org1ID '00000000-0000-0000-0000-000000000001'
 \set repo1ID '00000000-0000-0000-0000-000000000001'
 \set repo2ID '00000000-0000-0000-0000-000000000002'
+\set package1ID '00000000-0000-0000-0000-000000000001'
 
 -- Seed some data
 insert into "user" (user_id, alias, email)
@@ -19,6 +20,9 @@ insert into repository (repository_id, name, display_name, url, repository_kind_
 values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
 insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
 values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'org1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'package1', '1.0.0', :'repo1ID');
+insert into snapshot (package_id, version) values (:'package1ID', '1.0.0');
 
 -- Try to delete a repository owned by a user by other user
 select throws_ok(
@@ -50,6 +54,14 @@ select is_empty(
     $$,
     'Repository should have been deleted by user who owns it'
 );
+select results_eq(
+    $$
+        select package_name, package_version, repository_name, change_kind
+        from package_change
+    $$,
+    $$ values ('package1', '1.0.0', 'repo1', 'deleted') $$,
+    'Repository packages versions deletions should have been tracked'
+);
 
 -- Delete repository owned by organization (requesting user belongs to organization)
 select delete_repository(:'user1ID', 'repo2');
@@ -34,13 +34,47 @@ select is(
         "name": "repo1",
         "display_name": "Repo 1",
         "url": "https://repo1.com",
-        "kind": 0
+        "kind": 0,
+        "disabled": false,
+        "skip_prereleases": false,
+        "skip_deprecated": false,
+        "tracking_interval": null,
+        "quiet_hours": null,
+        "private": false,
+        "metadata": null,
+        "branch": null,
+        "path": null,
+        "tag_pattern": null,
+        "last_tracking_ts": null,
+        "tracking_requested_ts": null,
+        "auth_user": null,
+        "auth_pass": null,
+        "tls_ca_cert": null,
+        "tls_client_cert": null,
+        "tls_client_key": null
     }, {
         "repository_id": "00000000-0000-0000-0000-000000000002",
         "name": "repo2",
         "display_name": "Repo 2",
         "url": "https://repo2.com",
-        "kind": 0
+        "kind": 0,
+        "disabled": false,
+        "skip_prereleases": false,
+        "skip_deprecated": false,
+        "tracking_interval": null,
+        "quiet_hours": null,
+        "private": false,
+        "metadata": null,
+        "branch": null,
+        "path": null,
+        "tag_pattern": null,
+        "last_tracking_ts": null,
+        "tracking_requested_ts": null,
+        "auth_user": null,
+        "auth_pass": null,
+        "tls_ca_cert": null,
+        "tls_client_cert": null,
+        "tls_client_key": null
     }]'::jsonb,
     'Repositories 1 and 2 are returned'
 );
@@ -51,7 +85,24 @@ select is(
         "name": "repo3",
         "display_name": "Repo 3",
         "url": "https://repo3.com",
-        "kind": 1
+        "kind": 1,
+        "disabled": false,
+        "skip_prereleases": false,
+        "skip_deprecated": false,
+        "tracking_interval": null,
+        "quiet_hours": null,
+        "private": false,
+        "metadata": null,
+        "branch": null,
+        "path": null,
+        "tag_pattern": null,
+        "last_tracking_ts": null,
+        "tracking_requested_ts": null,
+        "auth_user": null,
+        "auth_pass": null,
+        "tls_ca_cert": null,
+        "tls_client_cert": null,
+        "tls_client_key": null
     }]'::jsonb,
     'Repository 3 is returned'
 );
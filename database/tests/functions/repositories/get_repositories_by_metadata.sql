@@ -0,0 +1,85 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set repo3ID '00000000-0000-0000-0000-000000000003'
+
+-- Seed some data
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, metadata, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, '{"team": "team1", "tier": "gold"}', :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, metadata, organization_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, '{"team": "team1"}', :'org1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo3ID', 'repo3', 'Repo 3', 'https://repo3.com', 1, :'user1ID');
+
+-- Run some tests
+select is(
+    get_repositories_by_metadata('{"team": "team1"}')::jsonb,
+    '[{
+        "repository_id": "00000000-0000-0000-0000-000000000001",
+        "name": "repo1",
+        "display_name": "Repo 1",
+        "url": "https://repo1.com",
+        "kind": 0,
+        "disabled": false,
+        "tracking_interval": null,
+        "quiet_hours": null,
+        "private": false,
+        "last_tracking_ts": null,
+        "metadata": {"team": "team1", "tier": "gold"},
+        "user_alias": "user1",
+        "organization_name": null
+    }, {
+        "repository_id": "00000000-0000-0000-0000-000000000002",
+        "name": "repo2",
+        "display_name": "Repo 2",
+        "url": "https://repo2.com",
+        "kind": 0,
+        "disabled": false,
+        "tracking_interval": null,
+        "quiet_hours": null,
+        "private": false,
+        "last_tracking_ts": null,
+        "metadata": {"team": "team1"},
+        "user_alias": null,
+        "organization_name": "org1"
+    }]'::jsonb,
+    'Repositories 1 and 2 are returned'
+);
+select is(
+    get_repositories_by_metadata('{"team": "team1", "tier": "gold"}')::jsonb,
+    '[{
+        "repository_id": "00000000-0000-0000-0000-000000000001",
+        "name": "repo1",
+        "display_name": "Repo 1",
+        "url": "https://repo1.com",
+        "kind": 0,
+        "disabled": false,
+        "tracking_interval": null,
+        "quiet_hours": null,
+        "private": false,
+        "last_tracking_ts": null,
+        "metadata": {"team": "team1", "tier": "gold"},
+        "user_alias": "user1",
+        "organization_name": null
+    }]'::jsonb,
+    'Repository 1 is returned'
+);
+select is(
+    get_repositories_by_metadata('{"team": "team2"}')::jsonb,
+    '[]'::jsonb,
+    'No repositories are returned'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
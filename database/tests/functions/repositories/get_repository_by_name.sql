@@ -26,7 +26,24 @@ select is(
         "name": "repo1",
         "display_name": "Repo 1",
         "url": "https://repo1.com",
-        "kind": 0
+        "kind": 0,
+        "disabled": false,
+        "skip_prereleases": false,
+        "skip_deprecated": false,
+        "tracking_interval": null,
+        "quiet_hours": null,
+        "private": false,
+        "metadata": null,
+        "branch": null,
+        "path": null,
+        "tag_pattern": null,
+        "last_tracking_ts": null,
+        "tracking_requested_ts": null,
+        "auth_user": null,
+        "auth_pass": null,
+        "tls_ca_cert": null,
+        "tls_client_cert": null,
+        "tls_client_key": null
     }'::jsonb,
     'Repository just seeded is returned as a json object'
 );
@@ -0,0 +1,55 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set user3ID '00000000-0000-0000-0000-000000000003'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, first_name, last_name, email)
+values (:'user1ID', 'user1', 'firstname1', 'lastname1', 'user1@email.com');
+insert into "user" (user_id, alias, first_name, last_name, email)
+values (:'user2ID', 'user2', 'firstname2', 'lastname2', 'user2@email.com');
+insert into "user" (user_id, alias, first_name, last_name, email)
+values (:'user3ID', 'user3', 'firstname3', 'lastname3', 'user3@email.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'org1ID');
+insert into repository_collaborator (repository_id, user_id, role) values (:'repo2ID', :'user3ID', 'read');
+insert into repository_collaborator (repository_id, user_id, role) values (:'repo2ID', :'user2ID', 'write');
+
+-- Run some tests
+select is(
+    get_repository_collaborators(:'user1ID', 'repo2')::jsonb,
+    '[{
+        "alias": "user2",
+        "first_name": "firstname2",
+        "last_name": "lastname2",
+        "role": "write"
+    },{
+        "alias": "user3",
+        "first_name": "firstname3",
+        "last_name": "lastname3",
+        "role": "read"
+    }]'::jsonb,
+    'Repo2 collaborators are returned as a json array of objects'
+);
+select throws_ok(
+    $$ select get_repository_collaborators('00000000-0000-0000-0000-000000000003', 'repo2') $$,
+    42501,
+    'insufficient_privilege',
+    'User3 should not be able to get repo2 collaborators'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
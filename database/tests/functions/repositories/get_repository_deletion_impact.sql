@@ -0,0 +1,77 @@
+-- Start transaction and plan tests
+begin;
+select plan(4);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set package2ID '00000000-0000-0000-0000-000000000002'
+\set webhook1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'org1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'package1', '1.0.0', :'repo1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package2ID', 'package2', '1.0.0', :'repo1ID');
+insert into snapshot (package_id, version) values (:'package1ID', '0.9.0');
+insert into snapshot (package_id, version) values (:'package1ID', '1.0.0');
+insert into snapshot (package_id, version) values (:'package2ID', '1.0.0');
+insert into subscription (user_id, package_id, event_kind_id) values (:'user1ID', :'package1ID', 0);
+insert into subscription (user_id, package_id, event_kind_id) values (:'user1ID', :'package2ID', 0);
+insert into subscription (user_id, package_id, event_kind_id) values (:'user2ID', :'package1ID', 0);
+insert into user_starred_package (user_id, package_id) values (:'user2ID', :'package1ID');
+insert into webhook (webhook_id, name, url, secret, active, user_id)
+values (:'webhook1ID', 'webhook1', 'http://webhook1.url', 'very', true, :'user2ID');
+insert into webhook__package (webhook_id, package_id) values (:'webhook1ID', :'package1ID');
+insert into webhook__package (webhook_id, package_id) values (:'webhook1ID', :'package2ID');
+
+-- Run some tests
+select is(
+    get_repository_deletion_impact(:'user1ID', 'repo1')::jsonb,
+    '{
+        "packages": 2,
+        "packages_versions": 3,
+        "subscribers": 2,
+        "webhooks": 1,
+        "stars": 1
+    }'::jsonb,
+    'Deletion impact of repo1 should be returned'
+);
+select is(
+    get_repository_deletion_impact(:'user1ID', 'repo2')::jsonb,
+    '{
+        "packages": 0,
+        "packages_versions": 0,
+        "subscribers": 0,
+        "webhooks": 0,
+        "stars": 0
+    }'::jsonb,
+    'Deletion impact of repo2 (owned by org1) should be returned'
+);
+select throws_ok(
+    $$ select get_repository_deletion_impact('00000000-0000-0000-0000-000000000002', 'repo1') $$,
+    42501,
+    'insufficient_privilege',
+    'User2 should not be able to get the deletion impact of repo1'
+);
+select is_empty(
+    $$ select get_repository_deletion_impact('00000000-0000-0000-0000-000000000001', 'repo3') $$,
+    'No rows expected for a repository that does not exist'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,84 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set package2ID '00000000-0000-0000-0000-000000000002'
+\set image1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (
+    repository_id,
+    name,
+    display_name,
+    url,
+    repository_kind_id,
+    user_id,
+    last_tracking_ts,
+    tracking_runs,
+    tracking_failed_runs
+) values (
+    :'repo1ID',
+    'repo1',
+    'Repo 1',
+    'https://repo1.com',
+    0,
+    :'user1ID',
+    current_timestamp,
+    4,
+    1
+);
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'user1ID');
+insert into package (package_id, name, latest_version, logo_url, logo_image_id, repository_id)
+values (:'package1ID', 'package1', '1.0.0', 'https://logo1', :'image1ID', :'repo1ID');
+insert into package (package_id, name, latest_version, logo_url, repository_id)
+values (:'package2ID', 'package2', '2.0.0', 'https://logo2', :'repo1ID');
+insert into snapshot (package_id, version, signed) values (:'package1ID', '0.9.0', false);
+insert into snapshot (package_id, version, signed) values (:'package1ID', '1.0.0', true);
+insert into snapshot (package_id, version, signed) values (:'package2ID', '2.0.0', false);
+
+-- Run some tests
+select is(
+    get_repository_health('repo1')::jsonb - 'last_tracking_ts',
+    '{
+        "tracking_runs": 4,
+        "tracking_success_rate": 0.75,
+        "freshness": 1.00,
+        "packages": 2,
+        "broken_links_rate": 0.50,
+        "signed_rate": 0.50,
+        "score": 75
+    }'::jsonb,
+    'Health of repo1 should be returned'
+);
+select is(
+    get_repository_health('repo2')::jsonb,
+    '{
+        "tracking_runs": 0,
+        "tracking_success_rate": null,
+        "last_tracking_ts": null,
+        "freshness": 0,
+        "packages": 0,
+        "broken_links_rate": null,
+        "signed_rate": null,
+        "score": 15
+    }'::jsonb,
+    'Health of repo2 (never tracked and without packages) should be returned'
+);
+select is_empty(
+    $$
+        select get_repository_health('repo3')
+    $$,
+    'Nothing should be returned for a repository that does not exist'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
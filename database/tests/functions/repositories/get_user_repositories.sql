@@ -80,7 +80,17 @@ select is(
         "url": "https://repo1.com",
         "last_tracking_ts": 0,
         "last_tracking_errors": "error1\\nerror2\\nerror3",
-        "kind": 0
+        "kind": 0,
+        "disabled": false,
+        "skip_prereleases": false,
+        "skip_deprecated": false,
+        "tracking_interval": null,
+        "quiet_hours": null,
+        "private": false,
+        "metadata": null,
+        "branch": null,
+        "path": null,
+        "tag_pattern": null
     }, {
         "repository_id": "00000000-0000-0000-0000-000000000002",
         "name": "repo2",
@@ -88,7 +98,17 @@ select is(
         "url": "https://repo2.com",
         "last_tracking_ts": null,
         "last_tracking_errors": null,
-        "kind": 0
+        "kind": 0,
+        "disabled": false,
+        "skip_prereleases": false,
+        "skip_deprecated": false,
+        "tracking_interval": null,
+        "quiet_hours": null,
+        "private": false,
+        "metadata": null,
+        "branch": null,
+        "path": null,
+        "tag_pattern": null
     }]'::jsonb,
     'Repositories belonging to user provided are returned as a json array of objects'
 );
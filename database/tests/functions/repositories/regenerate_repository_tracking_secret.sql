@@ -0,0 +1,45 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email)
+values (:'user2ID', 'user2', 'user2@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+
+-- Try to regenerate the secret of a repository without write access to it
+select throws_ok(
+    $$
+        select regenerate_repository_tracking_secret('00000000-0000-0000-0000-000000000002', 'repo1')
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Tracking secret should not be regenerated by a user without write access to the repository'
+);
+
+-- Regenerate the secret of a repository owned by the user
+select regenerate_repository_tracking_secret(:'user1ID', 'repo1') as secret \gset
+select is(
+    length(:'secret'),
+    64,
+    'Secret returned should have 64 hex characters'
+);
+select results_eq(
+    $$
+        select tracking_secret from repository where name = 'repo1'
+    $$,
+    format('values (digest(%L, ''sha256''))', :'secret'),
+    'Only the hash of the secret should be stored'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,52 @@
+-- Start transaction and plan tests
+begin;
+select plan(5);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id, tracking_secret)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID', digest('secret1', 'sha256'));
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id, tracking_secret, disabled)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'user1ID', digest('secret2', 'sha256'), true);
+
+-- Run some tests
+select is(
+    request_repository_tracking('repo1', 'invalid'),
+    false,
+    'Tracking should not be requested using an invalid secret'
+);
+select is(
+    request_repository_tracking('repo2', 'secret2'),
+    false,
+    'Tracking should not be requested for disabled repositories'
+);
+select is_empty(
+    $$
+        select 1 from repository where tracking_requested_at is not null
+    $$,
+    'No tracking requests should have been registered'
+);
+select is(
+    request_repository_tracking('repo1', 'secret1'),
+    true,
+    'Tracking should be requested using a valid secret'
+);
+select results_eq(
+    $$
+        select name from repository where tracking_requested_at is not null
+    $$,
+    $$
+        values ('repo1')
+    $$,
+    'Tracking request should have been registered for repo1'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(4);
+select plan(11);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
@@ -52,22 +52,106 @@ select throws_ok(
     'Repository update should fail because requesting user does not belong to owning organization'
 );
 
+-- Try to update repository by a collaborator with read access
+insert into "user" (user_id, alias, email)
+values (:'user2ID', 'user2', 'user2@email.com');
+insert into repository_collaborator (repository_id, user_id, role) values (:'repo1ID', :'user2ID', 'read');
+select throws_ok(
+    $$
+        select update_repository('00000000-0000-0000-0000-000000000002', '
+        {
+            "name": "repo1",
+            "display_name": "Repo 1 updated",
+            "url": "https://repo1.com/updated"
+        }
+        '::jsonb)
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Repository update should fail because requesting user only has read access'
+);
+
+-- Update repository by a collaborator with write access
+update repository_collaborator set role = 'write' where user_id = :'user2ID';
+select lives_ok(
+    $$
+        select update_repository('00000000-0000-0000-0000-000000000002', '
+        {
+            "name": "repo1",
+            "display_name": "Repo 1 updated by collaborator",
+            "url": "https://repo1.com"
+        }
+        '::jsonb)
+    $$,
+    'Repository update should succeed because requesting user has write access'
+);
+select throws_ok(
+    $$
+        select update_repository('00000000-0000-0000-0000-000000000002', '
+        {
+            "name": "repo1",
+            "display_name": "Repo 1 updated by collaborator",
+            "url": "https://repo1.com/collaborator"
+        }
+        '::jsonb)
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Repository url update should fail because requesting user is only a collaborator'
+);
+select throws_ok(
+    $$
+        select update_repository('00000000-0000-0000-0000-000000000002', '
+        {
+            "name": "repo1",
+            "display_name": "Repo 1 updated by collaborator",
+            "url": "https://repo1.com",
+            "private": true
+        }
+        '::jsonb)
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Repository visibility update should fail because requesting user is only a collaborator'
+);
+select throws_ok(
+    $$
+        select update_repository('00000000-0000-0000-0000-000000000002', '
+        {
+            "name": "repo1",
+            "display_name": "Repo 1 updated by collaborator",
+            "url": "https://repo1.com",
+            "disabled": true
+        }
+        '::jsonb)
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Repository disabled status update should fail because requesting user is only a collaborator'
+);
+
 -- Update repository owned by user
 select update_repository(:'user1ID', '
 {
     "name": "repo1",
     "display_name": "Repo 1 updated",
-    "url": "https://repo1.com/updated"
+    "url": "https://repo1.com/updated",
+    "tracking_interval": 1440,
+    "quiet_hours": {"start": 8, "end": 18},
+    "private": true,
+    "metadata": {"team": "team1"},
+    "path": "packages",
+    "tag_pattern": "v*"
 }
 '::jsonb);
 select results_eq(
     $$
-        select name, display_name, url
+        select name, display_name, url, tracking_interval, quiet_hours, private, metadata, branch, path, tag_pattern
         from repository
         where name = 'repo1'
     $$,
     $$
-        values ('repo1', 'Repo 1 updated', 'https://repo1.com/updated')
+        values ('repo1', 'Repo 1 updated', 'https://repo1.com/updated', 1440, '{"start": 8, "end": 18}'::jsonb, true, '{"team": "team1"}'::jsonb, null::text, 'packages', 'v*')
     $$,
     'Repository should have been updated by user who owns it'
 );
@@ -77,21 +161,81 @@ select update_repository(:'user1ID', '
 {
     "name": "repo2",
     "display_name": "Repo 2 updated",
-    "url": "https://repo2.com/updated"
+    "url": "https://repo2.com/updated",
+    "disabled": true,
+    "skip_prereleases": true,
+    "skip_deprecated": true
 }
 '::jsonb);
 select results_eq(
     $$
-        select name, display_name, url
+        select name, display_name, url, disabled, skip_prereleases, skip_deprecated
         from repository
         where name = 'repo2'
     $$,
     $$
-        values ('repo2', 'Repo 2 updated', 'https://repo2.com/updated')
+        values ('repo2', 'Repo 2 updated', 'https://repo2.com/updated', true, true, true)
     $$,
     'Repository should have been updated by user who belongs to owning organization'
 );
 
+-- Update repository credentials
+select update_repository(:'user1ID', '
+{
+    "name": "repo1",
+    "display_name": "Repo 1 updated",
+    "url": "https://repo1.com/updated",
+    "auth_user": "user1",
+    "auth_pass": "pass1",
+    "tls_ca_cert": "ca1",
+    "tls_client_cert": "cert1",
+    "tls_client_key": "key1"
+}
+'::jsonb);
+select update_repository(:'user1ID', '
+{
+    "name": "repo1",
+    "display_name": "Repo 1 updated",
+    "url": "https://repo1.com/updated",
+    "auth_user": "user2",
+    "auth_pass": "=",
+    "tls_ca_cert": "ca2",
+    "tls_client_cert": "cert2",
+    "tls_client_key": "="
+}
+'::jsonb);
+select results_eq(
+    $$
+        select auth_user, auth_pass, tls_ca_cert, tls_client_cert, tls_client_key
+        from repository
+        where name = 'repo1'
+    $$,
+    $$
+        values ('user2', 'pass1', 'ca2', 'cert2', 'key1')
+    $$,
+    'Repository stored password and tls client key should have been kept'
+);
+
+-- Remove repository credentials
+select update_repository(:'user1ID', '
+{
+    "name": "repo1",
+    "display_name": "Repo 1 updated",
+    "url": "https://repo1.com/updated"
+}
+'::jsonb);
+select results_eq(
+    $$
+        select auth_user, auth_pass, tls_client_key
+        from repository
+        where name = 'repo1'
+    $$,
+    $$
+        values (null::text, null::text, null::text)
+    $$,
+    'Repository credentials should have been removed'
+);
+
 -- Finish tests and rollback transaction
 select * from finish();
 rollback;
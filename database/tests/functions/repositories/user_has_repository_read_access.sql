@@ -0,0 +1,59 @@
+-- Start transaction and plan tests
+begin;
+select plan(6);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set user3ID '00000000-0000-0000-0000-000000000003'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set org2ID '00000000-0000-0000-0000-000000000002'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into "user" (user_id, alias, email) values (:'user3ID', 'user3', 'user3@email.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org2ID', 'org2', 'Organization 2', 'Description 2', 'https://org2.com');
+insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+insert into user__organization (user_id, organization_id, confirmed) values(:'user2ID', :'org2ID', true);
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user3ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'org1ID');
+
+-- Run some tests
+select ok(
+    user_has_repository_read_access(:'user3ID', :'repo1ID'),
+    'User3 should have read access to repo1 as they own it'
+);
+select ok(
+    user_has_repository_read_access(:'user1ID', :'repo2ID'),
+    'User1 should have read access to repo2 as they belong to org1'
+);
+select ok(
+    not user_has_repository_read_access(:'user2ID', :'repo2ID'),
+    'User2 should not have read access to repo2 before org1 shares its repositories'
+);
+insert into organization_share (organization_id, shared_with_organization_id) values (:'org1ID', :'org2ID');
+select ok(
+    user_has_repository_read_access(:'user2ID', :'repo2ID'),
+    'User2 should have read access to repo2 once org1 shares its repositories with org2'
+);
+select ok(
+    not user_has_repository_read_access(:'user2ID', :'repo1ID'),
+    'User2 should not have read access to repo1'
+);
+insert into repository_collaborator (repository_id, user_id, role) values (:'repo1ID', :'user2ID', 'read');
+select ok(
+    user_has_repository_read_access(:'user2ID', :'repo1ID'),
+    'User2 should have read access to repo1 once added as a collaborator'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,50 @@
+-- Start transaction and plan tests
+begin;
+select plan(4);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set user3ID '00000000-0000-0000-0000-000000000003'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, first_name, last_name, email)
+values (:'user1ID', 'user1', 'firstname1', 'lastname1', 'user1@email.com');
+insert into "user" (user_id, alias, first_name, last_name, email)
+values (:'user2ID', 'user2', 'firstname2', 'lastname2', 'user2@email.com');
+insert into "user" (user_id, alias, first_name, last_name, email)
+values (:'user3ID', 'user3', 'firstname3', 'lastname3', 'user3@email.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'org1ID');
+insert into repository_collaborator (repository_id, user_id, role) values (:'repo2ID', :'user2ID', 'write');
+insert into repository_collaborator (repository_id, user_id, role) values (:'repo2ID', :'user3ID', 'read');
+
+-- Run some tests
+select ok(
+    user_has_repository_write_access(:'user1ID', 'repo2'),
+    'User1 has write access to repo2 as they belong to org1'
+);
+select ok(
+    user_has_repository_write_access(:'user2ID', 'repo2'),
+    'User2 has write access to repo2 as a write collaborator'
+);
+select ok(
+    not user_has_repository_write_access(:'user3ID', 'repo2'),
+    'User3 does not have write access to repo2 as a read collaborator'
+);
+select ok(
+    not user_has_repository_write_access(:'user2ID', 'repo1'),
+    'User2 does not have write access to repo1'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
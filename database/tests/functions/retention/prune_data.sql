@@ -0,0 +1,85 @@
+-- Start transaction and plan tests
+begin;
+select plan(8);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set event1ID '00000000-0000-0000-0000-000000000001'
+\set event2ID '00000000-0000-0000-0000-000000000002'
+\set event3ID '00000000-0000-0000-0000-000000000003'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id, last_tracking_ts, last_tracking_errors)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID', current_timestamp - '10 days'::interval, 'errors');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id, last_tracking_ts, last_tracking_errors)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'user1ID', current_timestamp, 'errors');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'Package 1', '1.0.0', :'repo1ID');
+insert into event (event_id, package_version, package_id, event_kind_id, processed, processed_at)
+values (:'event1ID', '1.0.0', :'package1ID', 0, true, current_timestamp - '10 days'::interval);
+insert into event (event_id, package_version, package_id, event_kind_id, processed, processed_at)
+values (:'event2ID', '2.0.0', :'package1ID', 0, true, current_timestamp - '10 days'::interval);
+insert into event (event_id, package_version, package_id, event_kind_id, processed, processed_at)
+values (:'event3ID', '3.0.0', :'package1ID', 0, true, current_timestamp);
+insert into notification (event_id, user_id, processed, processed_at)
+values (:'event1ID', :'user1ID', true, current_timestamp - '10 days'::interval);
+insert into notification (event_id, user_id, processed)
+values (:'event2ID', :'user1ID', false);
+insert into package_change (package_id, package_name, package_version, repository_id, repository_name, repository_kind_id, change_kind, created_at)
+values (:'package1ID', 'Package 1', '1.0.0', :'repo1ID', 'repo1', 0, 'created', current_timestamp - '10 days'::interval);
+insert into package_change (package_id, package_name, package_version, repository_id, repository_name, repository_kind_id, change_kind)
+values (:'package1ID', 'Package 1', '2.0.0', :'repo1ID', 'repo1', 0, 'created');
+insert into package_tombstone (repository_id, package_name, package_version, package, snapshot, created_at)
+values (:'repo1ID', 'Package 1', '0.9.0', '{}', '{}', current_timestamp - '10 days'::interval);
+insert into package_tombstone (repository_id, package_name, package_version, package, snapshot)
+values (:'repo1ID', 'Package 1', '0.8.0', '{}', '{}');
+
+-- Run some tests
+select is(
+    prune_data('notifications', '7 days'),
+    1::bigint,
+    'Only processed notifications older than 7 days should be deleted'
+);
+select is(
+    prune_data('events', '7 days'),
+    1::bigint,
+    'Only processed events older than 7 days without notifications should be deleted'
+);
+select results_eq(
+    'select event_id from event order by event_id',
+    $$ values ('00000000-0000-0000-0000-000000000002'::uuid), ('00000000-0000-0000-0000-000000000003'::uuid) $$,
+    'Events with pending notifications or recently processed should be kept'
+);
+select is(
+    prune_data('packages_changes', '7 days'),
+    1::bigint,
+    'Only packages changes older than 7 days should be deleted'
+);
+select is(
+    prune_data('packages_tombstones', '7 days'),
+    1::bigint,
+    'Only packages tombstones older than 7 days should be deleted'
+);
+select is(
+    prune_data('tracking_errors', '7 days'),
+    1::bigint,
+    'Only tracking errors older than 7 days should be cleared'
+);
+select results_eq(
+    'select last_tracking_errors from repository order by name',
+    $$ values (null::text), ('errors') $$,
+    'Recent tracking errors should be kept'
+);
+select throws_ok(
+    $$ select prune_data('invalid', '7 days') $$,
+    'invalid data category: invalid',
+    'Invalid categories should be rejected'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,69 @@
+-- Start transaction and plan tests
+begin;
+select plan(6);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email, email_verified, locale, subscriptions_digest)
+values (:'user1ID', 'user1', 'user1@email.com', true, 'es', true);
+insert into "user" (user_id, alias, email, email_verified)
+values (:'user2ID', 'user2', 'user2@email.com', true);
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'Package 1', '1.0.0', :'repo1ID');
+insert into subscription (user_id, package_id, event_kind_id)
+values (:'user2ID', :'package1ID', 0);
+
+-- Run some tests
+select is_empty(
+    $$ select get_pending_subscriptions_digest('7 days'::interval, 0, 24) $$,
+    'No digests due: user1 has no subscriptions and user2 has not enabled the digest'
+);
+insert into subscription (user_id, package_id, event_kind_id)
+values (:'user1ID', :'package1ID', 0);
+select is(
+    (get_pending_subscriptions_digest('7 days'::interval, 0, 24)::jsonb)->'user',
+    '{
+        "user_id": "00000000-0000-0000-0000-000000000001",
+        "email": "user1@email.com",
+        "locale": "es"
+    }'::jsonb,
+    'User1 digest never generated should be returned'
+);
+select is(
+    ((get_pending_subscriptions_digest('7 days'::interval, 0, 24)::jsonb)->>'since')::bigint,
+    floor(extract(epoch from current_timestamp - '7 days'::interval))::bigint,
+    'Digest never generated should cover the interval provided'
+);
+update "user" set last_subscriptions_digest_at = current_timestamp - '1 day'::interval
+where user_id = :'user1ID';
+select is_empty(
+    $$ select get_pending_subscriptions_digest('7 days'::interval, 0, 24) $$,
+    'No digests due: last digest is recent'
+);
+select is(
+    ((get_pending_subscriptions_digest('12 hours'::interval, 0, 24)::jsonb)->>'since')::bigint,
+    floor(extract(epoch from current_timestamp - '1 day'::interval))::bigint,
+    'Digest should cover the changes published since the last one'
+);
+
+-- Digests are only delivered during the hours provided, in the user's local time
+select is_empty(
+    format(
+        'select get_pending_subscriptions_digest(%L::interval, %s, %s)',
+        '12 hours',
+        extract(hour from current_timestamp at time zone 'UTC')::int + 1,
+        extract(hour from current_timestamp at time zone 'UTC')::int + 2
+    ),
+    'No digests due: current UTC hour is out of the delivery hours'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,125 @@
+-- Start transaction and plan tests
+begin;
+select plan(4);
+
+-- Declare some variables
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set package2ID '00000000-0000-0000-0000-000000000002'
+\set package3ID '00000000-0000-0000-0000-000000000003'
+\set image1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email)
+values (:'user2ID', 'user2', 'user2@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'org1ID');
+insert into package (package_id, name, latest_version, logo_image_id, repository_id)
+values (:'package1ID', 'Package 1', '2.0.0', :'image1ID', :'repo1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package2ID', 'Package 2', '1.0.0', :'repo2ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package3ID', 'Package 3', '1.0.0', :'repo2ID');
+insert into snapshot (package_id, version, changes, created_at)
+values (:'package1ID', '1.0.0', '["Initial release"]', '2020-06-16 10:00:00+00');
+insert into snapshot (package_id, version, created_at)
+values (:'package1ID', '1.1.0', '2020-06-17 10:00:00+00');
+insert into snapshot (package_id, version, changes, created_at)
+values (:'package1ID', '2.0.0', '["Added feature 1", "Fixed bug 1"]', '2020-06-19 10:00:00+00');
+insert into snapshot (package_id, version, changes, created_at)
+values (:'package2ID', '1.0.0', '["Initial release"]', '2020-06-18 10:00:00+00');
+insert into snapshot (package_id, version, changes, created_at)
+values (:'package3ID', '1.0.0', '["Initial release"]', '2020-06-20 10:00:00+00');
+insert into subscription (user_id, package_id, event_kind_id)
+values (:'user1ID', :'package1ID', 0);
+insert into subscription (user_id, package_id, event_kind_id)
+values (:'user1ID', :'package1ID', 1);
+insert into subscription (user_id, package_id, event_kind_id)
+values (:'user1ID', :'package2ID', 0);
+
+-- Run some tests
+select is(
+    get_user_subscriptions_changes(:'user1ID', 10, 0)::jsonb,
+    '{
+        "changes": [{
+            "package_id": "00000000-0000-0000-0000-000000000001",
+            "name": "Package 1",
+            "normalized_name": "package-1",
+            "logo_image_id": "00000000-0000-0000-0000-000000000001",
+            "version": "2.0.0",
+            "changes": ["Added feature 1", "Fixed bug 1"],
+            "created_at": 1592560800,
+            "repository": {
+                "kind": 0,
+                "name": "repo1",
+                "display_name": "Repo 1",
+                "user_alias": "user1",
+                "organization_name": null,
+                "organization_display_name": null
+            }
+        }, {
+            "package_id": "00000000-0000-0000-0000-000000000002",
+            "name": "Package 2",
+            "normalized_name": "package-2",
+            "logo_image_id": null,
+            "version": "1.0.0",
+            "changes": ["Initial release"],
+            "created_at": 1592474400,
+            "repository": {
+                "kind": 0,
+                "name": "repo2",
+                "display_name": "Repo 2",
+                "user_alias": null,
+                "organization_name": "org1",
+                "organization_display_name": "Organization 1"
+            }
+        }, {
+            "package_id": "00000000-0000-0000-0000-000000000001",
+            "name": "Package 1",
+            "normalized_name": "package-1",
+            "logo_image_id": "00000000-0000-0000-0000-000000000001",
+            "version": "1.0.0",
+            "changes": ["Initial release"],
+            "created_at": 1592301600,
+            "repository": {
+                "kind": 0,
+                "name": "repo1",
+                "display_name": "Repo 1",
+                "user_alias": "user1",
+                "organization_name": null,
+                "organization_display_name": null
+            }
+        }],
+        "total": 3
+    }'::jsonb,
+    'Changes of subscribed packages versions should be returned newest first'
+);
+select is(
+    get_user_subscriptions_changes(:'user1ID', 1, 1)::jsonb->'changes'->0->>'normalized_name',
+    'package-2',
+    'Second page of one entry should contain the changes of package2'
+);
+select is(
+    (get_user_subscriptions_changes(:'user1ID', 1, 1)::jsonb->>'total')::int,
+    3,
+    'Total should not be affected by the pagination'
+);
+select is(
+    get_user_subscriptions_changes(:'user2ID', 10, 0)::jsonb,
+    '{"changes": [], "total": 0}'::jsonb,
+    'No changes expected for user2'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,24 @@
+-- Start transaction and plan tests
+begin;
+select plan(1);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email, subscriptions_digest)
+values (:'user1ID', 'user1', 'user1@email.com', true);
+
+-- Update last digest
+select update_subscriptions_last_digest(:'user1ID');
+
+-- Run some tests
+select isnt(
+    (select last_subscriptions_digest_at from "user" where user_id = :'user1ID'),
+    null,
+    'User last subscriptions digest should have been updated'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(2);
+select plan(3);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
@@ -13,7 +13,9 @@ insert into "user" (
     last_name,
     email,
     password,
-    profile_image_id
+    profile_image_id,
+    locale,
+    timezone
 ) values (
     :'user1ID',
     'user1',
@@ -21,7 +23,9 @@ insert into "user" (
     'lastname',
     'user1@email.com',
     'password',
-    '00000000-0000-0000-0000-000000000001'
+    '00000000-0000-0000-0000-000000000001',
+    'es',
+    'Europe/Madrid'
 );
 
 -- Run some tests
@@ -32,7 +36,11 @@ select is(
         "first_name": "firstname",
         "last_name": "lastname",
         "email": "user1@email.com",
-        "profile_image_id": "00000000-0000-0000-0000-000000000001"
+        "profile_image_id": "00000000-0000-0000-0000-000000000001",
+        "locale": "es",
+        "timezone": "Europe/Madrid",
+        "subscriptions_digest": false,
+        "email_suppressed": false
     }
     '::jsonb,
     'User1 should exist'
@@ -41,6 +49,12 @@ select is_empty(
     $$ select get_user_profile('00000000-0000-0000-0000-000000000002')::jsonb $$,
     'User2 should not exist'
 );
+insert into email_suppression (email, reason) values ('user1@email.com', 'bounce');
+select is(
+    (get_user_profile(:'user1ID')::jsonb->>'email_suppressed')::boolean,
+    true,
+    'User1 email should be suppressed'
+);
 
 
 -- Finish tests and rollback transaction
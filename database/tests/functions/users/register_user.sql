@@ -11,7 +11,9 @@ select register_user('
     "email": "email",
     "email_verified": true,
     "password": "password",
-    "profile_image_id": "00000000-0000-0000-0000-000000000001"
+    "profile_image_id": "00000000-0000-0000-0000-000000000001",
+    "locale": "es",
+    "timezone": "Europe/Madrid"
 }
 ') as code \gset
 
@@ -25,7 +27,9 @@ select results_eq(
             email,
             email_verified,
             password,
-            profile_image_id
+            profile_image_id,
+            locale,
+            timezone
         from "user"
         where alias = 'alias'
     $$,
@@ -37,7 +41,9 @@ select results_eq(
             'email',
             true,
             'password',
-            '00000000-0000-0000-0000-000000000001'::uuid
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            'es',
+            'Europe/Madrid'
         )
     $$,
     'User should exist'
@@ -30,7 +30,10 @@ select update_user_profile(:'user1ID', '
     "alias": "user1 updated",
     "first_name": "firstname updated",
     "last_name": "lastname updated",
-    "profile_image_id": "00000000-0000-0000-0000-000000000002"
+    "profile_image_id": "00000000-0000-0000-0000-000000000002",
+    "locale": "es",
+    "timezone": "Europe/Madrid",
+    "subscriptions_digest": true
 }
 '::jsonb);
 
@@ -43,7 +46,10 @@ select results_eq(
             last_name,
             email,
             password,
-            profile_image_id
+            profile_image_id,
+            locale,
+            timezone,
+            subscriptions_digest
         from "user"
     $$,
     $$
@@ -53,7 +59,10 @@ select results_eq(
             'lastname updated',
             'user1@email.com',
             'password',
-            '00000000-0000-0000-0000-000000000002'::uuid
+            '00000000-0000-0000-0000-000000000002'::uuid,
+            'es',
+            'Europe/Madrid',
+            true
         )
     $$,
     'User first and last name should have been updated'
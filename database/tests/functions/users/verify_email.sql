@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(6);
+select plan(7);
 
 -- Register user
 select register_user('
@@ -66,6 +66,36 @@ select is(
     'Email verification should not succeed as code is expired'
 );
 
+-- Register a user whose email domain has been verified by an organization
+insert into organization (organization_id, name, display_name, description, home_url)
+values ('00000000-0000-0000-0000-000000000001', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into organization_domain (organization_id, domain, verified, auto_join)
+values ('00000000-0000-0000-0000-000000000001', 'org1.com', true, true);
+select register_user('
+{
+    "alias": "alias3",
+    "first_name": "first_name",
+    "last_name": "last_name",
+    "email": "user3@org1.com",
+    "email_verified": false,
+    "password": "password"
+}
+') as code3 \gset
+
+-- Verify new user's email and check they joined the organization
+select verify_email(:'code3');
+select results_eq(
+    $$
+        select o.name, uo.confirmed
+        from user__organization uo
+        join organization o using (organization_id)
+        join "user" u using (user_id)
+        where u.alias = 'alias3'
+    $$,
+    $$ values ('org1', true) $$,
+    'User should have joined org1 once the email was verified'
+);
+
 -- Finish tests and rollback transaction
 select * from finish();
 rollback;
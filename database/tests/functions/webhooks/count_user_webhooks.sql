@@ -0,0 +1,41 @@
+-- Start transaction and plan tests
+begin;
+select plan(2);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set webhook1ID '00000000-0000-0000-0000-000000000001'
+\set webhook2ID '00000000-0000-0000-0000-000000000002'
+\set webhook3ID '00000000-0000-0000-0000-000000000003'
+
+-- Seed some data
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email)
+values (:'user2ID', 'user2', 'user2@email.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into webhook (webhook_id, name, url, active, user_id)
+values (:'webhook1ID', 'webhook1', 'http://webhook1.url', true, :'user1ID');
+insert into webhook (webhook_id, name, url, active, user_id)
+values (:'webhook2ID', 'webhook2', 'http://webhook2.url', true, :'user1ID');
+insert into webhook (webhook_id, name, url, active, organization_id)
+values (:'webhook3ID', 'webhook3', 'http://webhook3.url', true, :'org1ID');
+
+-- Run some tests
+select is(
+    count_user_webhooks(:'user1ID'),
+    2,
+    'User1 owns 2 webhooks'
+);
+select is(
+    count_user_webhooks(:'user2ID'),
+    0,
+    'User2 does not own any webhook'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
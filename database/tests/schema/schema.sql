@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(112);
+select plan(201);
 
 -- Check default_text_search_config is correct
 select results_eq(
@@ -15,6 +15,9 @@ select has_extension('pgcrypto');
 -- Check expected tables exist
 select tables_are(array[
     'api_key',
+    'api_key_usage',
+    'cluster_inventory',
+    'email_suppression',
     'email_verification_code',
     'event',
     'event_kind',
@@ -23,9 +26,18 @@ select tables_are(array[
     'maintainer',
     'notification',
     'organization',
+    'organization_domain',
+    'organization_featured_package',
+    'organization_share',
     'package',
     'package__maintainer',
+    'package_adoption_request',
+    'package_change',
+    'package_statement',
+    'package_tag',
+    'package_tombstone',
     'repository',
+    'repository_collaborator',
     'repository_kind',
     'session',
     'snapshot',
@@ -46,6 +58,30 @@ select columns_are('api_key', array[
     'name',
     'key',
     'user_id',
+    'created_at',
+    'repository_id'
+]);
+select columns_are('api_key_usage', array[
+    'api_key_id',
+    'day',
+    'endpoint',
+    'requests',
+    'rate_limited_requests'
+]);
+select columns_are('cluster_inventory', array[
+    'cluster_inventory_id',
+    'name',
+    'releases',
+    'email_report',
+    'webhook_url',
+    'last_report_at',
+    'created_at',
+    'updated_at',
+    'user_id'
+]);
+select columns_are('email_suppression', array[
+    'email',
+    'reason',
     'created_at'
 ]);
 select columns_are('email_verification_code', array[
@@ -97,6 +133,28 @@ select columns_are('organization', array[
     'description',
     'home_url',
     'logo_image_id',
+    'created_at',
+    'validation_webhook_url',
+    'validation_webhook_secret'
+]);
+select columns_are('organization_domain', array[
+    'organization_domain_id',
+    'organization_id',
+    'domain',
+    'verification_token',
+    'verified',
+    'auto_join',
+    'last_check_ts',
+    'created_at'
+]);
+select columns_are('organization_featured_package', array[
+    'organization_id',
+    'package_id',
+    'position'
+]);
+select columns_are('organization_share', array[
+    'organization_id',
+    'shared_with_organization_id',
     'created_at'
 ]);
 select columns_are('package', array[
@@ -111,12 +169,56 @@ select columns_are('package', array[
     'is_operator',
     'channels',
     'default_channel',
-    'repository_id'
+    'repository_id',
+    'seeking_maintainers'
 ]);
 select columns_are('package__maintainer', array[
     'package_id',
     'maintainer_id'
 ]);
+select columns_are('package_adoption_request', array[
+    'package_adoption_request_id',
+    'package_id',
+    'user_id',
+    'message',
+    'created_at'
+]);
+select columns_are('package_change', array[
+    'package_change_id',
+    'package_id',
+    'package_name',
+    'package_version',
+    'repository_id',
+    'repository_name',
+    'repository_kind_id',
+    'change_kind',
+    'created_at'
+]);
+select columns_are('package_statement', array[
+    'package_statement_id',
+    'package_id',
+    'kind',
+    'content',
+    'signature',
+    'signature_algorithm',
+    'signature_key_id',
+    'user_id',
+    'created_at',
+    'updated_at'
+]);
+select columns_are('package_tag', array[
+    'package_id',
+    'name'
+]);
+select columns_are('package_tombstone', array[
+    'package_tombstone_id',
+    'repository_id',
+    'package_name',
+    'package_version',
+    'package',
+    'snapshot',
+    'created_at'
+]);
 select columns_are('repository', array[
     'repository_id',
     'name',
@@ -125,8 +227,35 @@ select columns_are('repository', array[
     'last_tracking_ts',
     'last_tracking_errors',
     'repository_kind_id',
+    'tracking_interval',
+    'disabled',
+    'metadata',
+    'index_etag',
+    'index_last_modified',
+    'auth_user',
+    'auth_pass',
+    'tls_ca_cert',
+    'tls_client_cert',
+    'tls_client_key',
+    'branch',
+    'path',
+    'tag_pattern',
     'user_id',
-    'organization_id'
+    'organization_id',
+    'tracking_secret',
+    'tracking_requested_at',
+    'tracking_runs',
+    'tracking_failed_runs',
+    'skip_prereleases',
+    'skip_deprecated',
+    'quiet_hours',
+    'private'
+]);
+select columns_are('repository_collaborator', array[
+    'repository_id',
+    'user_id',
+    'role',
+    'created_at'
 ]);
 select columns_are('repository_kind', array[
     'repository_kind_id',
@@ -158,7 +287,16 @@ select columns_are('snapshot', array[
     'content_url',
     'container_image',
     'provider',
-    'created_at'
+    'created_at',
+    'capabilities',
+    'maintenance',
+    'sign_key',
+    'signature_verified',
+    'changes',
+    'containers_images',
+    'changelog',
+    'kube_version',
+    'chart_type'
 ]);
 select columns_are('subscription', array[
     'user_id',
@@ -174,7 +312,11 @@ select columns_are('user', array[
     'email_verified',
     'password',
     'profile_image_id',
-    'created_at'
+    'created_at',
+    'locale',
+    'timezone',
+    'subscriptions_digest',
+    'last_subscriptions_digest_at'
 ]);
 select columns_are('user_starred_package', array[
     'user_id',
@@ -219,6 +361,17 @@ select indexes_are('api_key', array[
     'api_key_pkey',
     'api_key_user_id_idx'
 ]);
+select indexes_are('api_key_usage', array[
+    'api_key_usage_pkey'
+]);
+select indexes_are('cluster_inventory', array[
+    'cluster_inventory_pkey',
+    'cluster_inventory_user_id_name_key',
+    'cluster_inventory_user_id_idx'
+]);
+select indexes_are('email_suppression', array[
+    'email_suppression_pkey'
+]);
 select indexes_are('email_verification_code', array[
     'email_verification_code_pkey',
     'email_verification_code_user_id_key'
@@ -250,6 +403,19 @@ select indexes_are('organization', array[
     'organization_pkey',
     'organization_name_key'
 ]);
+select indexes_are('organization_domain', array[
+    'organization_domain_pkey',
+    'organization_domain_organization_id_domain_key',
+    'organization_domain_domain_idx'
+]);
+select indexes_are('organization_featured_package', array[
+    'organization_featured_package_pkey',
+    'organization_featured_package_package_id_idx'
+]);
+select indexes_are('organization_share', array[
+    'organization_share_pkey',
+    'organization_share_shared_with_organization_id_idx'
+]);
 select indexes_are('package', array[
     'package_pkey',
     'package_tsdoc_idx',
@@ -259,13 +425,39 @@ select indexes_are('package', array[
 select indexes_are('package__maintainer', array[
     'package__maintainer_pkey'
 ]);
+select indexes_are('package_adoption_request', array[
+    'package_adoption_request_pkey',
+    'package_adoption_request_package_id_user_id_key'
+]);
+select indexes_are('package_change', array[
+    'package_change_pkey',
+    'package_change_created_at_idx'
+]);
+select indexes_are('package_statement', array[
+    'package_statement_pkey',
+    'package_statement_package_id_kind_key'
+]);
+select indexes_are('package_tag', array[
+    'package_tag_pkey',
+    'package_tag_name_idx'
+]);
+select indexes_are('package_tombstone', array[
+    'package_tombstone_pkey',
+    'package_tombstone_repository_id_package_name_package_versio_key',
+    'package_tombstone_created_at_idx'
+]);
 select indexes_are('repository', array[
     'repository_pkey',
     'repository_name_key',
     'repository_url_key',
     'repository_repository_kind_id_idx',
     'repository_user_id_idx',
-    'repository_organization_id_idx'
+    'repository_organization_id_idx',
+    'repository_metadata_idx'
+]);
+select indexes_are('repository_collaborator', array[
+    'repository_collaborator_pkey',
+    'repository_collaborator_user_id_idx'
 ]);
 select indexes_are('repository_kind', array[
     'repository_kind_pkey'
@@ -307,58 +499,127 @@ select indexes_are('webhook__package', array[
 select has_function('add_api_key');
 select has_function('delete_api_key');
 select has_function('get_api_key');
+select has_function('get_organization_api_usage');
 select has_function('get_user_api_keys');
+select has_function('register_api_keys_usage');
 select has_function('update_api_key');
 
+select has_function('add_organization_domain');
+select has_function('delete_organization_domain');
+select has_function('get_all_organizations_domains');
+select has_function('get_organization_domain');
+select has_function('get_organization_domains');
+select has_function('join_organizations_by_email_domain');
+select has_function('update_organization_domain_verification');
+
+select has_function('is_email_suppressed');
+select has_function('suppress_email');
+
 select has_function('get_pending_event');
 
 select has_function('get_image');
 select has_function('register_image');
 
+select has_function('add_cluster_inventory');
+select has_function('delete_cluster_inventory');
+select has_function('get_pending_cluster_inventory_report');
+select has_function('get_user_cluster_inventories');
+select has_function('update_cluster_inventory');
+select has_function('update_cluster_inventory_last_report');
+
+select has_function('register_mirror_repository');
+
 select has_function('add_notification');
 select has_function('get_pending_notification');
 select has_function('update_notification_status');
 
 select has_function('add_organization');
 select has_function('add_organization_member');
+select has_function('add_organization_share');
 select has_function('confirm_organization_membership');
 select has_function('delete_organization_member');
+select has_function('delete_organization_share');
 select has_function('get_organization');
+select has_function('get_organization_member_deletion_impact');
 select has_function('get_organization_members');
+select has_function('get_organization_shares');
+select has_function('get_organization_validation_webhook');
 select has_function('get_user_organizations');
 select has_function('update_organization');
+select has_function('update_organization_featured_packages');
+select has_function('update_organization_validation_webhook');
 select has_function('user_belongs_to_organization');
 
+select has_function('add_package_adoption_request');
+select has_function('add_package_statement');
+select has_function('delete_package_statement');
 select has_function('generate_package_tsdoc');
+select has_function('get_all_package_adoption_requests');
+select has_function('get_all_packages');
 select has_function('get_package');
+select has_function('get_package_adoption_requests');
+select has_function('get_package_changes');
+select has_function('get_package_dependencies_graph');
+select has_function('get_package_latest_versions');
+select has_function('get_package_project');
 select has_function('get_package_summary');
+select has_function('get_package_version_docs');
+select has_function('get_packages_by_owner');
 select has_function('get_packages_starred_by_user');
 select has_function('get_package_stars');
+select has_function('get_package_statements');
 select has_function('get_packages_stats');
+select has_function('get_packages_tags');
 select has_function('get_random_packages');
+select has_function('kube_version_compatible');
+select has_function('match_installed_packages');
+select has_function('package_version_is_eol');
+select has_function('push_package');
 select has_function('register_package');
+select has_function('resolve_packages');
+select has_function('restore_unregistered_packages');
 select has_function('search_packages');
 select has_function('semver_gt');
 select has_function('semver_gte');
+select has_function('semver_satisfies');
 select has_function('toggle_star');
 select has_function('unregister_package');
+select has_function('update_package_logo_image');
+select has_function('update_package_seeking_maintainers');
 
 select has_function('add_repository');
+select has_function('add_repository_collaborator');
+select has_function('count_org_repositories');
 select has_function('delete_repository');
+select has_function('delete_repository_collaborator');
 select has_function('get_all_repositories');
 select has_function('get_repositories_by_kind');
+select has_function('get_repositories_by_metadata');
 select has_function('get_repository_by_name');
+select has_function('get_repository_collaborators');
+select has_function('get_repository_deletion_impact');
+select has_function('get_repository_health');
 select has_function('get_repository_packages_digest');
 select has_function('get_org_repositories');
 select has_function('get_user_repositories');
+select has_function('regenerate_repository_tracking_secret');
+select has_function('request_repository_tracking');
 select has_function('transfer_repository');
 select has_function('update_repository');
+select has_function('user_has_repository_read_access');
+select has_function('user_has_repository_write_access');
+select has_function('user_owns_repository');
+
+select has_function('prune_data');
 
 select has_function('add_subscription');
 select has_function('delete_subscription');
 select has_function('get_package_subscriptions');
+select has_function('get_pending_subscriptions_digest');
 select has_function('get_subscriptors');
 select has_function('get_user_subscriptions');
+select has_function('get_user_subscriptions_changes');
+select has_function('update_subscriptions_last_digest');
 
 select has_function('get_user_profile');
 select has_function('register_session');
@@ -368,6 +629,7 @@ select has_function('update_user_profile');
 select has_function('verify_email');
 
 select has_function('add_webhook');
+select has_function('count_user_webhooks');
 select has_function('delete_webhook');
 select has_function('get_webhook');
 select has_function('get_org_webhooks');
@@ -393,7 +655,8 @@ select results_eq(
     'select * from event_kind',
     $$ values
         (0, 'New package release'),
-        (1, 'Security alert')
+        (1, 'Security alert'),
+        (2, 'Package statement change')
     $$,
     'Event kinds should exist'
 );
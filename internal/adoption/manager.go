@@ -0,0 +1,197 @@
+package adoption
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/email"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/i18n"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/jackc/pgx/v4"
+	"github.com/satori/uuid"
+)
+
+// maxMessageLength represents the maximum length allowed for the message of
+// an adoption request.
+const maxMessageLength = 1000
+
+// Manager provides an API to manage the adoption of the packages whose
+// publishers are seeking new maintainers.
+type Manager struct {
+	db           hub.DB
+	es           hub.EmailSender
+	adminsEmails []string
+}
+
+// NewManager creates a new Manager instance.
+func NewManager(db hub.DB, es hub.EmailSender, opts ...func(m *Manager)) *Manager {
+	m := &Manager{
+		db: db,
+		es: es,
+	}
+	for _, o := range opts {
+		o(m)
+	}
+	return m
+}
+
+// WithAdminsEmails allows providing the email addresses of the hub admins,
+// who will be notified about the adoption requests as well.
+func WithAdminsEmails(emails []string) func(m *Manager) {
+	return func(m *Manager) {
+		m.adminsEmails = emails
+	}
+}
+
+// Add registers a request from the user doing the request to adopt the
+// provided package. The package must be seeking maintainers. The package
+// owners and the hub admins will receive an email about the request.
+func (m *Manager) Add(ctx context.Context, packageID string, r *hub.AdoptionRequest, baseURL string) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if _, err := uuid.FromString(packageID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
+	}
+	r.Message = strings.TrimSpace(r.Message)
+	if r.Message == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "message not provided")
+	}
+	if len(r.Message) > maxMessageLength {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "message too long")
+	}
+	if baseURL == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "base url not provided")
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid base url")
+	}
+
+	// Add adoption request to database
+	query := "select add_package_adoption_request($1::uuid, $2::uuid, $3::text)"
+	var dataJSON []byte
+	err = m.db.QueryRow(ctx, query, userID, packageID, r.Message).Scan(&dataJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "package is not seeking maintainers")
+		}
+		return err
+	}
+
+	// Send adoption request emails
+	if m.es != nil {
+		var data *adoptionRequestData
+		if err := json.Unmarshal(dataJSON, &data); err != nil {
+			return err
+		}
+		recipients := data.Owners
+		for _, adminEmail := range m.adminsEmails {
+			recipients = append(recipients, &recipient{Email: adminEmail})
+		}
+		templateData := map[string]string{
+			"link": fmt.Sprintf("%s/packages/%s/%s/%s",
+				baseURL,
+				hub.GetKindName(data.RepositoryKind),
+				data.RepositoryName,
+				data.PackageName,
+			),
+			"message":     r.Message,
+			"packageName": data.PackageName,
+			"userAlias":   data.UserAlias,
+		}
+		for _, rcpt := range recipients {
+			var emailBody bytes.Buffer
+			if err := adoptionRequestTmpl.Execute(&emailBody, rcpt.Locale, templateData); err != nil {
+				return err
+			}
+			emailData := &email.Data{
+				To:      rcpt.Email,
+				Subject: i18n.T(rcpt.Locale, "Adoption request for %s", data.PackageName),
+				Body:    emailBody.Bytes(),
+			}
+			if err := m.es.SendEmail(emailData); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetAllJSON returns all the pending adoption requests as a json array. It's
+// meant to be used by the hub admins.
+func (m *Manager) GetAllJSON(ctx context.Context) ([]byte, error) {
+	query := "select get_all_package_adoption_requests()"
+	var dataJSON []byte
+	if err := m.db.QueryRow(ctx, query).Scan(&dataJSON); err != nil {
+		return nil, err
+	}
+	return dataJSON, nil
+}
+
+// GetByPackageJSON returns the adoption requests of the provided package as a
+// json array. The user doing the request must be an owner of the package.
+func (m *Manager) GetByPackageJSON(ctx context.Context, packageID string) ([]byte, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if _, err := uuid.FromString(packageID); err != nil {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
+	}
+
+	// Get package adoption requests from database
+	query := "select get_package_adoption_requests($1::uuid, $2::uuid)"
+	var dataJSON []byte
+	err := m.db.QueryRow(ctx, query, userID, packageID).Scan(&dataJSON)
+	if err != nil {
+		if err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+			return nil, hub.ErrInsufficientPrivilege
+		}
+		return nil, err
+	}
+	return dataJSON, nil
+}
+
+// SetSeekingMaintainers sets whether the provided package is seeking new
+// maintainers or not. When it stops seeking maintainers, its pending adoption
+// requests are removed. The user doing the request must be an owner of the
+// package.
+func (m *Manager) SetSeekingMaintainers(ctx context.Context, packageID string, seekingMaintainers bool) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if _, err := uuid.FromString(packageID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
+	}
+
+	// Update package in database
+	query := "select update_package_seeking_maintainers($1::uuid, $2::uuid, $3::boolean)"
+	_, err := m.db.Exec(ctx, query, userID, packageID, seekingMaintainers)
+	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+		return hub.ErrInsufficientPrivilege
+	}
+	return err
+}
+
+// adoptionRequestData represents the details of an adoption request needed to
+// notify the package owners about it.
+type adoptionRequestData struct {
+	PackageName    string             `json:"package_name"`
+	RepositoryKind hub.RepositoryKind `json:"repository_kind"`
+	RepositoryName string             `json:"repository_name"`
+	UserAlias      string             `json:"user_alias"`
+	Owners         []*recipient       `json:"owners"`
+}
+
+// recipient represents a recipient of the adoption request emails.
+type recipient struct {
+	Email  string `json:"email"`
+	Locale string `json:"locale"`
+}
@@ -0,0 +1,290 @@
+package adoption
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/email"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const packageID = "00000000-0000-0000-0000-000000000001"
+
+func TestAdd(t *testing.T) {
+	dbQuery := "select add_package_adoption_request($1::uuid, $2::uuid, $3::text)"
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+	dataJSON := []byte(`
+	{
+		"package_name": "pkg1",
+		"repository_kind": 0,
+		"repository_name": "repo1",
+		"user_alias": "user2",
+		"owners": [
+			{"email": "user1@email.com", "locale": "es"}
+		]
+	}
+	`)
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil, nil)
+		assert.Panics(t, func() {
+			_ = m.Add(context.Background(), packageID, &hub.AdoptionRequest{}, "http://baseurl.com")
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg    string
+			packageID string
+			message   string
+			baseURL   string
+		}{
+			{
+				"invalid package id",
+				"invalid",
+				"message",
+				"http://baseurl.com",
+			},
+			{
+				"message not provided",
+				packageID,
+				"  ",
+				"http://baseurl.com",
+			},
+			{
+				"message too long",
+				packageID,
+				strings.Repeat("a", maxMessageLength+1),
+				"http://baseurl.com",
+			},
+			{
+				"base url not provided",
+				packageID,
+				"message",
+				"",
+			},
+			{
+				"invalid base url",
+				packageID,
+				"message",
+				"/invalid",
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil, nil)
+				err := m.Add(ctx, tc.packageID, &hub.AdoptionRequest{Message: tc.message}, tc.baseURL)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("package not seeking maintainers", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "userID", packageID, "message").Return(nil, pgx.ErrNoRows)
+		m := NewManager(db, nil)
+
+		err := m.Add(ctx, packageID, &hub.AdoptionRequest{Message: "message"}, "http://baseurl.com")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "package is not seeking maintainers")
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "userID", packageID, "message").Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db, nil)
+
+		err := m.Add(ctx, packageID, &hub.AdoptionRequest{Message: "message"}, "http://baseurl.com")
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		testCases := []struct {
+			description         string
+			emailSenderResponse error
+		}{
+			{
+				"adoption request emails sent successfully",
+				nil,
+			},
+			{
+				"error sending adoption request emails",
+				email.ErrFakeSenderFailure,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.description, func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, dbQuery, "userID", packageID, "message").Return(dataJSON, nil)
+				es := &email.SenderMock{}
+				es.On("SendEmail", mock.MatchedBy(func(data *email.Data) bool {
+					return data.To == "user1@email.com" && data.Subject == "Solicitud de adopción de pkg1"
+				})).Return(tc.emailSenderResponse).Once()
+				if tc.emailSenderResponse == nil {
+					es.On("SendEmail", mock.MatchedBy(func(data *email.Data) bool {
+						return data.To == "admin@email.com" && data.Subject == "Adoption request for pkg1"
+					})).Return(nil).Once()
+				}
+				m := NewManager(db, es, WithAdminsEmails([]string{"admin@email.com"}))
+
+				err := m.Add(ctx, packageID, &hub.AdoptionRequest{Message: "message"}, "http://baseurl.com")
+				assert.Equal(t, tc.emailSenderResponse, err)
+				db.AssertExpectations(t)
+				es.AssertExpectations(t)
+			})
+		}
+	})
+}
+
+func TestGetAllJSON(t *testing.T) {
+	dbQuery := "select get_all_package_adoption_requests()"
+	ctx := context.Background()
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery).Return([]byte("dataJSON"), nil)
+		m := NewManager(db, nil)
+
+		dataJSON, err := m.GetAllJSON(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery).Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db, nil)
+
+		dataJSON, err := m.GetAllJSON(ctx)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetByPackageJSON(t *testing.T) {
+	dbQuery := "select get_package_adoption_requests($1::uuid, $2::uuid)"
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil, nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetByPackageJSON(context.Background(), packageID)
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		m := NewManager(nil, nil)
+		_, err := m.GetByPackageJSON(ctx, "invalid")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "userID", packageID).Return([]byte("dataJSON"), nil)
+		m := NewManager(db, nil)
+
+		dataJSON, err := m.GetByPackageJSON(ctx, packageID)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDatabaseFailure,
+				tests.ErrFakeDatabaseFailure,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, dbQuery, "userID", packageID).Return(nil, tc.dbErr)
+				m := NewManager(db, nil)
+
+				dataJSON, err := m.GetByPackageJSON(ctx, packageID)
+				assert.Equal(t, tc.expectedError, err)
+				assert.Nil(t, dataJSON)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+}
+
+func TestSetSeekingMaintainers(t *testing.T) {
+	dbQuery := "select update_package_seeking_maintainers($1::uuid, $2::uuid, $3::boolean)"
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil, nil)
+		assert.Panics(t, func() {
+			_ = m.SetSeekingMaintainers(context.Background(), packageID, true)
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		m := NewManager(nil, nil)
+		err := m.SetSeekingMaintainers(ctx, "invalid", true)
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, "userID", packageID, true).Return(nil)
+		m := NewManager(db, nil)
+
+		err := m.SetSeekingMaintainers(ctx, packageID, true)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDatabaseFailure,
+				tests.ErrFakeDatabaseFailure,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("Exec", ctx, dbQuery, "userID", packageID, false).Return(tc.dbErr)
+				m := NewManager(db, nil)
+
+				err := m.SetSeekingMaintainers(ctx, packageID, false)
+				assert.Equal(t, tc.expectedError, err)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+}
@@ -0,0 +1,39 @@
+package adoption
+
+import (
+	"context"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/mock"
+)
+
+// ManagerMock is a mock implementation of the AdoptionManager interface.
+type ManagerMock struct {
+	mock.Mock
+}
+
+// Add implements the AdoptionManager interface.
+func (m *ManagerMock) Add(ctx context.Context, packageID string, r *hub.AdoptionRequest, baseURL string) error {
+	args := m.Called(ctx, packageID, r, baseURL)
+	return args.Error(0)
+}
+
+// GetAllJSON implements the AdoptionManager interface.
+func (m *ManagerMock) GetAllJSON(ctx context.Context) ([]byte, error) {
+	args := m.Called(ctx)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+// GetByPackageJSON implements the AdoptionManager interface.
+func (m *ManagerMock) GetByPackageJSON(ctx context.Context, packageID string) ([]byte, error) {
+	args := m.Called(ctx, packageID)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+// SetSeekingMaintainers implements the AdoptionManager interface.
+func (m *ManagerMock) SetSeekingMaintainers(ctx context.Context, packageID string, seekingMaintainers bool) error {
+	args := m.Called(ctx, packageID, seekingMaintainers)
+	return args.Error(0)
+}
@@ -6,9 +6,16 @@ import (
 	"fmt"
 
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/satori/uuid"
 )
 
+const (
+	// MaxUsageDays represents the maximum number of days the api keys usage
+	// can be requested for.
+	MaxUsageDays = 90
+)
+
 // Manager provides an API to manage api keys.
 type Manager struct {
 	db hub.DB
@@ -29,12 +36,23 @@ func (m *Manager) Add(ctx context.Context, ak *hub.APIKey) ([]byte, error) {
 	if ak.Name == "" {
 		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "name not provided")
 	}
+	if ak.RepositoryID != "" {
+		if _, err := uuid.FromString(ak.RepositoryID); err != nil {
+			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid repository id")
+		}
+	}
 
 	// Add api key to the database
 	akJSON, _ := json.Marshal(ak)
 	var key []byte
 	err := m.db.QueryRow(ctx, "select add_api_key($1::jsonb)", akJSON).Scan(&key)
-	return key, err
+	if err != nil {
+		if err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+			return nil, hub.ErrInsufficientPrivilege
+		}
+		return nil, err
+	}
+	return key, nil
 }
 
 // Delete deletes the provided api key from the database.
@@ -66,6 +84,33 @@ func (m *Manager) GetJSON(ctx context.Context, apiKeyID string) ([]byte, error)
 	return m.dbQueryJSON(ctx, query, userID, apiKeyID)
 }
 
+// GetOrganizationUsageJSON returns a summary of the usage of the api keys
+// owned by the members of the organization provided during the last days
+// given as a json object. The requesting user must belong to the
+// organization.
+func (m *Manager) GetOrganizationUsageJSON(ctx context.Context, orgName string, days int) ([]byte, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if orgName == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "organization name not provided")
+	}
+	if days <= 0 || days > MaxUsageDays {
+		return nil, fmt.Errorf("%w: invalid days (0 < d <= %d)", hub.ErrInvalidInput, MaxUsageDays)
+	}
+
+	// Get organization api keys usage from database
+	query := "select get_organization_api_usage($1::uuid, $2::text, $3::int)"
+	dataJSON, err := m.dbQueryJSON(ctx, query, userID, orgName, days)
+	if err != nil {
+		if err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+			return nil, hub.ErrInsufficientPrivilege
+		}
+		return nil, err
+	}
+	return dataJSON, nil
+}
+
 // GetOwnedByUserJSON returns the api keys belonging to the requesting user as
 // a json array.
 func (m *Manager) GetOwnedByUserJSON(ctx context.Context) ([]byte, error) {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/tests"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -40,6 +41,13 @@ func TestAdd(t *testing.T) {
 					Name: "",
 				},
 			},
+			{
+				"invalid repository id",
+				&hub.APIKey{
+					Name:         "apikey1",
+					RepositoryID: "invalid",
+				},
+			},
 		}
 		for _, tc := range testCases {
 			tc := tc
@@ -65,6 +73,23 @@ func TestAdd(t *testing.T) {
 		db.AssertExpectations(t)
 	})
 
+	t.Run("user does not have write access to the repository", func(t *testing.T) {
+		ak := &hub.APIKey{
+			Name:         "apikey1",
+			UserID:       "userID",
+			RepositoryID: "00000000-0000-0000-0000-000000000001",
+		}
+		akJSON, _ := json.Marshal(ak)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, akJSON).Return(nil, util.ErrDBInsufficientPrivilege)
+		m := NewManager(db)
+
+		dataJSON, err := m.Add(ctx, ak)
+		assert.Equal(t, hub.ErrInsufficientPrivilege, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+
 	t.Run("add api key succeeded", func(t *testing.T) {
 		db := &tests.DBMock{}
 		db.On("QueryRow", ctx, dbQuery, akJSON).Return([]byte("key"), nil)
@@ -155,6 +180,92 @@ func TestGetJSON(t *testing.T) {
 	})
 }
 
+func TestGetOrganizationUsageJSON(t *testing.T) {
+	dbQuery := "select get_organization_api_usage($1::uuid, $2::text, $3::int)"
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetOrganizationUsageJSON(context.Background(), "org1", 30)
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg  string
+			orgName string
+			days    int
+		}{
+			{
+				"organization name not provided",
+				"",
+				30,
+			},
+			{
+				"invalid days",
+				"org1",
+				0,
+			},
+			{
+				"invalid days",
+				"org1",
+				MaxUsageDays + 1,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil)
+				dataJSON, err := m.GetOrganizationUsageJSON(ctx, tc.orgName, tc.days)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+				assert.Nil(t, dataJSON)
+			})
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDatabaseFailure,
+				tests.ErrFakeDatabaseFailure,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, dbQuery, "userID", "org1", 30).Return(nil, tc.dbErr)
+				m := NewManager(db)
+
+				dataJSON, err := m.GetOrganizationUsageJSON(ctx, "org1", 30)
+				assert.Equal(t, tc.expectedError, err)
+				assert.Nil(t, dataJSON)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("organization api usage data returned successfully", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "userID", "org1", 30).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetOrganizationUsageJSON(ctx, "org1", 30)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
 func TestGetOwnedByUserJSON(t *testing.T) {
 	dbQuery := "select get_user_api_keys($1::uuid)"
 	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
@@ -25,6 +25,13 @@ func (m *ManagerMock) Delete(ctx context.Context, apiKeyID string) error {
 	return args.Error(0)
 }
 
+// GetOrganizationUsageJSON implements the APIKeyManager interface.
+func (m *ManagerMock) GetOrganizationUsageJSON(ctx context.Context, orgName string, days int) ([]byte, error) {
+	args := m.Called(ctx, orgName, days)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
 // GetOwnedByUserJSON implements the APIKeyManager interface.
 func (m *ManagerMock) GetOwnedByUserJSON(ctx context.Context) ([]byte, error) {
 	args := m.Called(ctx)
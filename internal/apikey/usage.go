@@ -0,0 +1,163 @@
+package apikey
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/rs/zerolog/log"
+)
+
+// usageFlushInterval represents how often the usage collected is registered
+// in the database.
+const usageFlushInterval = 1 * time.Minute
+
+// usageEntryKey identifies the usage of an api key on a given endpoint during
+// a day.
+type usageEntryKey struct {
+	apiKeyID string
+	day      string
+	endpoint string
+}
+
+// usageEntry represents the usage of an api key on a given endpoint during a
+// day, as expected by the database.
+type usageEntry struct {
+	APIKeyID            string `json:"api_key_id"`
+	Day                 string `json:"day"`
+	Endpoint            string `json:"endpoint"`
+	Requests            int    `json:"requests"`
+	RateLimitedRequests int    `json:"rate_limited_requests"`
+}
+
+// UsageTracker collects the usage of the api keys in memory, registering it
+// periodically in the database.
+type UsageTracker struct {
+	db    hub.DB
+	mu    sync.Mutex
+	usage map[usageEntryKey]*usageEntry
+	keys  map[[sha256.Size]byte]string
+	now   func() time.Time
+}
+
+// NewUsageTracker creates a new UsageTracker instance.
+func NewUsageTracker(db hub.DB) *UsageTracker {
+	return &UsageTracker{
+		db:    db,
+		usage: make(map[usageEntryKey]*usageEntry),
+		keys:  make(map[[sha256.Size]byte]string),
+		now:   time.Now,
+	}
+}
+
+// Track records a request to the endpoint provided authenticated using the
+// given api key. The key is remembered so that the requests rejected by the
+// rate limiter, which happens before authenticating them, can be attributed
+// to it.
+func (t *UsageTracker) Track(apiKeyID string, key []byte, endpoint string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(key) > 0 {
+		t.keys[sha256.Sum256(key)] = apiKeyID
+	}
+	t.entry(apiKeyID, endpoint).Requests++
+}
+
+// TrackRateLimited records a request to the endpoint provided rejected by the
+// rate limiter. Requests using keys that haven't been seen authenticating a
+// request yet are ignored.
+func (t *UsageTracker) TrackRateLimited(key []byte, endpoint string) {
+	if len(key) == 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	apiKeyID, ok := t.keys[sha256.Sum256(key)]
+	if !ok {
+		return
+	}
+	t.entry(apiKeyID, endpoint).RateLimitedRequests++
+}
+
+// entry returns the usage entry of the api key and endpoint provided for the
+// current day, creating it if needed. It must be called with the lock held.
+func (t *UsageTracker) entry(apiKeyID, endpoint string) *usageEntry {
+	k := usageEntryKey{
+		apiKeyID: apiKeyID,
+		day:      t.now().UTC().Format("2006-01-02"),
+		endpoint: endpoint,
+	}
+	e, ok := t.usage[k]
+	if !ok {
+		e = &usageEntry{
+			APIKeyID: k.apiKeyID,
+			Day:      k.day,
+			Endpoint: k.endpoint,
+		}
+		t.usage[k] = e
+	}
+	return e
+}
+
+// Flush registers the usage collected so far in the database. If it can't be
+// registered, it'll be retried in the next flush.
+func (t *UsageTracker) Flush(ctx context.Context) error {
+	t.mu.Lock()
+	if len(t.usage) == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	usage := t.usage
+	t.usage = make(map[usageEntryKey]*usageEntry)
+	t.mu.Unlock()
+
+	entries := make([]*usageEntry, 0, len(usage))
+	for _, e := range usage {
+		entries = append(entries, e)
+	}
+	entriesJSON, _ := json.Marshal(entries)
+	if _, err := t.db.Exec(ctx, "select register_api_keys_usage($1::jsonb)", entriesJSON); err != nil {
+		t.restore(usage)
+		return err
+	}
+	return nil
+}
+
+// restore merges the usage provided back into the usage collected.
+func (t *UsageTracker) restore(usage map[usageEntryKey]*usageEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for k, e := range usage {
+		if current, ok := t.usage[k]; ok {
+			current.Requests += e.Requests
+			current.RateLimitedRequests += e.RateLimitedRequests
+			continue
+		}
+		t.usage[k] = e
+	}
+}
+
+// Run registers periodically the usage collected in the database until the
+// context provided is done. The pending usage is registered before returning.
+func (t *UsageTracker) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for {
+		select {
+		case <-time.After(usageFlushInterval):
+			if err := t.Flush(ctx); err != nil {
+				log.Error().Err(err).Msg("error registering api keys usage")
+			}
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := t.Flush(flushCtx); err != nil {
+				log.Error().Err(err).Msg("error registering api keys usage")
+			}
+			cancel()
+			return
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package apikey
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageTracker(t *testing.T) {
+	dbQuery := "select register_api_keys_usage($1::jsonb)"
+	ctx := context.Background()
+	now := time.Date(2020, 6, 16, 23, 0, 0, 0, time.FixedZone("", -2*3600))
+
+	newTracker := func(db *tests.DBMock) *UsageTracker {
+		ut := NewUsageTracker(db)
+		ut.now = func() time.Time { return now }
+		return ut
+	}
+	usageMatcher := func(expectedUsage []*usageEntry) interface{} {
+		return mock.MatchedBy(func(usageJSON []byte) bool {
+			var usage []*usageEntry
+			if err := json.Unmarshal(usageJSON, &usage); err != nil || len(usage) != len(expectedUsage) {
+				return false
+			}
+			for _, e := range expectedUsage {
+				found := false
+				for _, u := range usage {
+					if *u == *e {
+						found = true
+						break
+					}
+				}
+				if !found {
+					return false
+				}
+			}
+			return true
+		})
+	}
+
+	t.Run("nothing to flush", func(t *testing.T) {
+		db := &tests.DBMock{}
+		ut := newTracker(db)
+
+		assert.NoError(t, ut.Flush(ctx))
+		db.AssertExpectations(t)
+	})
+
+	t.Run("usage registered successfully", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, usageMatcher([]*usageEntry{
+			{APIKeyID: "key1", Day: "2020-06-17", Endpoint: "GET /api/v1/packages/search", Requests: 2},
+			{APIKeyID: "key1", Day: "2020-06-17", Endpoint: "GET /api/v1/*", RateLimitedRequests: 1},
+			{APIKeyID: "key2", Day: "2020-06-17", Endpoint: "GET /api/v1/packages/search", Requests: 1},
+		})).Return(nil)
+		ut := newTracker(db)
+
+		ut.Track("key1", []byte("secret1"), "GET /api/v1/packages/search")
+		ut.Track("key1", []byte("secret1"), "GET /api/v1/packages/search")
+		ut.Track("key2", []byte("secret2"), "GET /api/v1/packages/search")
+		ut.TrackRateLimited([]byte("secret1"), "GET /api/v1/*")
+		ut.TrackRateLimited([]byte("unknown"), "GET /api/v1/*")
+		ut.TrackRateLimited(nil, "GET /api/v1/*")
+		require.NoError(t, ut.Flush(ctx))
+
+		// Usage is reset once it has been registered
+		require.NoError(t, ut.Flush(ctx))
+		db.AssertExpectations(t)
+	})
+
+	t.Run("usage kept when it could not be registered", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, usageMatcher([]*usageEntry{
+			{APIKeyID: "key1", Day: "2020-06-17", Endpoint: "GET /api/v1/packages/search", Requests: 1},
+		})).Return(tests.ErrFakeDatabaseFailure).Once()
+		db.On("Exec", ctx, dbQuery, usageMatcher([]*usageEntry{
+			{APIKeyID: "key1", Day: "2020-06-17", Endpoint: "GET /api/v1/packages/search", Requests: 2},
+		})).Return(nil).Once()
+		ut := newTracker(db)
+
+		ut.Track("key1", []byte("secret1"), "GET /api/v1/packages/search")
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, ut.Flush(ctx))
+		ut.Track("key1", []byte("secret1"), "GET /api/v1/packages/search")
+		assert.NoError(t, ut.Flush(ctx))
+		db.AssertExpectations(t)
+	})
+}
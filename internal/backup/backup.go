@@ -0,0 +1,321 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	// formatVersion represents the version of the backup archive format.
+	formatVersion = 1
+
+	// manifestFile represents the name of the archive entry containing the
+	// backup manifest. It's always the first entry of the archive.
+	manifestFile = "manifest.json"
+
+	// tablesDir represents the directory in the archive where the tables data
+	// is stored.
+	tablesDir = "tables"
+)
+
+// tables represents the tables included in the backups, in an order that
+// satisfies their foreign keys constraints. All the tables created by the
+// schema migrations must be listed here or in lookupTables.
+var tables = []string{
+	"organization",
+	"user",
+	"user__organization",
+	"email_verification_code",
+	"session",
+	"repository",
+	"api_key",
+	"api_key_usage",
+	"repository_collaborator",
+	"organization_share",
+	"organization_domain",
+	"package",
+	"snapshot",
+	"maintainer",
+	"package__maintainer",
+	"package_statement",
+	"package_change",
+	"package_tag",
+	"package_adoption_request",
+	"package_tombstone",
+	"organization_featured_package",
+	"image",
+	"image_version",
+	"user_starred_package",
+	"event",
+	"subscription",
+	"webhook",
+	"webhook__event_kind",
+	"webhook__package",
+	"notification",
+	"email_suppression",
+	"cluster_inventory",
+}
+
+// serialColumns represents the serial columns of the tables included in the
+// backups, whose sequences must be updated once the data has been restored.
+var serialColumns = map[string]string{
+	"package_change":    "package_change_id",
+	"package_tombstone": "package_tombstone_id",
+}
+
+// lookupTables represents the tables populated by the schema migrations, which
+// are not included in the backups.
+var lookupTables = []string{
+	"event_kind",
+	"repository_kind",
+}
+
+// ErrInvalidBackup indicates that the backup provided is not valid.
+var ErrInvalidBackup = errors.New("invalid backup")
+
+// Manifest represents the manifest of a backup, describing its content.
+type Manifest struct {
+	FormatVersion int              `json:"format_version"`
+	SchemaVersion int64            `json:"schema_version"`
+	CreatedAt     int64            `json:"created_at"`
+	Tables        map[string]int64 `json:"tables"`
+	Images        []*ImageObject   `json:"images"`
+}
+
+// ImageObject represents an object of the image store included in a backup.
+type ImageObject struct {
+	ImageID string `json:"image_id"`
+	Version string `json:"version"`
+	Size    int64  `json:"size"`
+	SHA256  string `json:"sha256"`
+}
+
+// Backup writes to the writer provided a tar archive containing a consistent
+// export of the hub data. All the data is read from a single repeatable read
+// transaction, so the export represents a snapshot of the database even when
+// the hub is running. The archive contains a manifest describing the backup,
+// including the objects of the image store, followed by the tables data.
+func Backup(ctx context.Context, db hub.DB, w io.Writer) error {
+	tmpDir, err := ioutil.TempDir("", "hub-backup")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Export data to temporary files
+	m := &Manifest{
+		FormatVersion: formatVersion,
+		CreatedAt:     time.Now().Unix(),
+		Tables:        make(map[string]int64, len(tables)),
+	}
+	err = util.DBTransact(ctx, db, func(tx pgx.Tx) error {
+		query := "set transaction isolation level repeatable read, read only"
+		if _, err := tx.Exec(ctx, query); err != nil {
+			return err
+		}
+		if err := tx.QueryRow(ctx, "select version from version_schema").Scan(&m.SchemaVersion); err != nil {
+			return err
+		}
+		images, err := getImagesObjects(ctx, tx)
+		if err != nil {
+			return err
+		}
+		m.Images = images
+		for _, table := range tables {
+			f, err := os.Create(path.Join(tmpDir, table))
+			if err != nil {
+				return err
+			}
+			query := fmt.Sprintf("copy %s to stdout", pgx.Identifier{table}.Sanitize())
+			tag, err := tx.Conn().PgConn().CopyTo(ctx, f, query)
+			f.Close()
+			if err != nil {
+				return fmt.Errorf("error exporting table %s: %w", table, err)
+			}
+			m.Tables[table] = tag.RowsAffected()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Write archive
+	tw := tar.NewWriter(w)
+	manifestJSON, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeArchiveEntry(tw, manifestFile, int64(len(manifestJSON)), bytes.NewReader(manifestJSON)); err != nil {
+		return err
+	}
+	for _, table := range tables {
+		f, err := os.Open(path.Join(tmpDir, table))
+		if err != nil {
+			return err
+		}
+		fi, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return err
+		}
+		err = writeArchiveEntry(tw, path.Join(tablesDir, table), fi.Size(), f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+// Restore restores the hub data from the backup archive provided. The
+// database must have been migrated to the same schema version the backup was
+// created from. All existing data is replaced in a single transaction, so
+// nothing is changed if the restore fails. The image store objects and the
+// number of rows restored for each table are verified against the manifest.
+func Restore(ctx context.Context, db hub.DB, r io.Reader) error {
+	tr := tar.NewReader(r)
+
+	// Read and validate manifest
+	m, err := readManifest(tr)
+	if err != nil {
+		return err
+	}
+	var schemaVersion int64
+	if err := db.QueryRow(ctx, "select version from version_schema").Scan(&schemaVersion); err != nil {
+		return err
+	}
+	if m.SchemaVersion != schemaVersion {
+		return fmt.Errorf("%w: backup schema version (%d) does not match the database one (%d)",
+			ErrInvalidBackup, m.SchemaVersion, schemaVersion)
+	}
+
+	// Import data
+	return util.DBTransact(ctx, db, func(tx pgx.Tx) error {
+		identifiers := make([]string, 0, len(tables))
+		for _, table := range tables {
+			identifiers = append(identifiers, pgx.Identifier{table}.Sanitize())
+		}
+		if _, err := tx.Exec(ctx, "truncate "+strings.Join(identifiers, ", ")); err != nil {
+			return err
+		}
+		for _, table := range tables {
+			hdr, err := tr.Next()
+			if err != nil {
+				return fmt.Errorf("%w: error reading table %s: %v", ErrInvalidBackup, table, err)
+			}
+			if hdr.Name != path.Join(tablesDir, table) {
+				return fmt.Errorf("%w: unexpected entry %s", ErrInvalidBackup, hdr.Name)
+			}
+			query := fmt.Sprintf("copy %s from stdin", pgx.Identifier{table}.Sanitize())
+			tag, err := tx.Conn().PgConn().CopyFrom(ctx, tr, query)
+			if err != nil {
+				return fmt.Errorf("error importing table %s: %w", table, err)
+			}
+			if tag.RowsAffected() != m.Tables[table] {
+				return fmt.Errorf("%w: table %s rows restored (%d) do not match the manifest (%d)",
+					ErrInvalidBackup, table, tag.RowsAffected(), m.Tables[table])
+			}
+		}
+		for table, column := range serialColumns {
+			query := fmt.Sprintf(`
+			select setval(pg_get_serial_sequence('%[1]s', '%[2]s'), coalesce(max(%[2]s), 0) + 1, false)
+			from %[1]s`, table, column)
+			if _, err := tx.Exec(ctx, query); err != nil {
+				return err
+			}
+		}
+		images, err := getImagesObjects(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if !imagesObjectsMatch(images, m.Images) {
+			return fmt.Errorf("%w: image store objects do not match the manifest", ErrInvalidBackup)
+		}
+		return nil
+	})
+}
+
+// getImagesObjects returns the objects available in the image store.
+func getImagesObjects(ctx context.Context, tx pgx.Tx) ([]*ImageObject, error) {
+	query := `
+	select coalesce(json_agg(json_build_object(
+		'image_id', image_id,
+		'version', version,
+		'size', length(data),
+		'sha256', encode(sha256(data), 'hex')
+	) order by image_id, version), '[]')
+	from image_version`
+	var dataJSON []byte
+	if err := tx.QueryRow(ctx, query).Scan(&dataJSON); err != nil {
+		return nil, err
+	}
+	var images []*ImageObject
+	if err := json.Unmarshal(dataJSON, &images); err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// imagesObjectsMatch checks if the two lists of image store objects provided
+// are the same.
+func imagesObjectsMatch(a, b []*ImageObject) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if *a[i] != *b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// readManifest reads the manifest from the backup archive provided, checking
+// that it's a valid one.
+func readManifest(tr *tar.Reader) (*Manifest, error) {
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+	}
+	if hdr.Name != manifestFile {
+		return nil, fmt.Errorf("%w: manifest not found", ErrInvalidBackup)
+	}
+	var m *Manifest
+	if err := json.NewDecoder(tr).Decode(&m); err != nil {
+		return nil, fmt.Errorf("%w: invalid manifest: %v", ErrInvalidBackup, err)
+	}
+	if m.FormatVersion != formatVersion {
+		return nil, fmt.Errorf("%w: unsupported format version %d", ErrInvalidBackup, m.FormatVersion)
+	}
+	return m, nil
+}
+
+// writeArchiveEntry writes a file to the tar archive provided.
+func writeArchiveEntry(tw *tar.Writer, name string, size int64, r io.Reader) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    size,
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := io.Copy(tw, r)
+	return err
+}
@@ -0,0 +1,210 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackup(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("error starting transaction", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("Begin", ctx).Return(nil, tests.ErrFakeDatabaseFailure)
+
+		var buf bytes.Buffer
+		err := Backup(ctx, db, &buf)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.Zero(t, buf.Len())
+		db.AssertExpectations(t)
+	})
+
+	t.Run("error getting schema version", func(t *testing.T) {
+		tx := &tests.TXMock{}
+		tx.On("Exec", ctx, "set transaction isolation level repeatable read, read only").Return(nil)
+		tx.On("QueryRow", ctx, "select version from version_schema").Return(nil, tests.ErrFakeDatabaseFailure)
+		tx.On("Rollback", ctx).Return(nil)
+		db := &tests.DBMock{}
+		db.On("Begin", ctx).Return(tx, nil)
+
+		var buf bytes.Buffer
+		err := Backup(ctx, db, &buf)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.Zero(t, buf.Len())
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	})
+}
+
+func TestRestore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid backup", func(t *testing.T) {
+		testCases := []struct {
+			description string
+			backup      []byte
+		}{
+			{
+				"not a tar archive",
+				[]byte("invalid"),
+			},
+			{
+				"manifest not found",
+				newTestArchive(t, "tables/user", ""),
+			},
+			{
+				"invalid manifest",
+				newTestArchive(t, manifestFile, "{invalid"),
+			},
+			{
+				"unsupported format version",
+				newTestArchive(t, manifestFile, `{"format_version": 99}`),
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.description, func(t *testing.T) {
+				t.Parallel()
+				db := &tests.DBMock{}
+				err := Restore(ctx, db, bytes.NewReader(tc.backup))
+				assert.True(t, errors.Is(err, ErrInvalidBackup))
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("error getting schema version", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, "select version from version_schema").Return(nil, tests.ErrFakeDatabaseFailure)
+
+		backup := newTestArchive(t, manifestFile, `{"format_version": 1, "schema_version": 10}`)
+		err := Restore(ctx, db, bytes.NewReader(backup))
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("schema version mismatch", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, "select version from version_schema").Return(int64(11), nil)
+
+		backup := newTestArchive(t, manifestFile, `{"format_version": 1, "schema_version": 10}`)
+		err := Restore(ctx, db, bytes.NewReader(backup))
+		assert.True(t, errors.Is(err, ErrInvalidBackup))
+		db.AssertExpectations(t)
+	})
+
+	t.Run("error truncating tables", func(t *testing.T) {
+		tx := &tests.TXMock{}
+		tx.On("Exec", ctx, mock.MatchedBy(func(query string) bool {
+			return strings.HasPrefix(query, "truncate ")
+		})).Return(tests.ErrFakeDatabaseFailure)
+		tx.On("Rollback", ctx).Return(nil)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, "select version from version_schema").Return(int64(10), nil)
+		db.On("Begin", ctx).Return(tx, nil)
+
+		backup := newTestArchive(t, manifestFile, `{"format_version": 1, "schema_version": 10}`)
+		err := Restore(ctx, db, bytes.NewReader(backup))
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	})
+}
+
+func TestTables(t *testing.T) {
+	// Collect the tables created by the schema migrations and the tables each
+	// of them references
+	createTableRE := regexp.MustCompile(`^create table (?:if not exists )?"?(\w+)"?`)
+	alterTableRE := regexp.MustCompile(`^alter table "?(\w+)"? add column`)
+	referencesRE := regexp.MustCompile(`references "?(\w+)"?`)
+	files, err := filepath.Glob("../../database/migrations/schema/*.sql")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	references := make(map[string][]string)
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		up := strings.Split(string(data), "---- create above / drop below ----")[0]
+		for _, stmt := range strings.Split(up, ";") {
+			stmt = strings.TrimSpace(stmt)
+			var table string
+			if m := createTableRE.FindStringSubmatch(stmt); m != nil {
+				table = m[1]
+				if _, ok := references[table]; !ok {
+					references[table] = nil
+				}
+			} else if m := alterTableRE.FindStringSubmatch(stmt); m != nil {
+				table = m[1]
+			} else {
+				continue
+			}
+			for _, m := range referencesRE.FindAllStringSubmatch(stmt, -1) {
+				if m[1] != table {
+					references[table] = append(references[table], m[1])
+				}
+			}
+		}
+	}
+
+	t.Run("all tables created by the migrations are included in the backups", func(t *testing.T) {
+		var created []string
+		for table := range references {
+			created = append(created, table)
+		}
+		assert.ElementsMatch(t, created, append(append([]string{}, tables...), lookupTables...))
+	})
+
+	t.Run("tables are sorted satisfying their foreign keys", func(t *testing.T) {
+		done := make(map[string]bool)
+		for _, table := range lookupTables {
+			done[table] = true
+		}
+		for _, table := range tables {
+			for _, ref := range references[table] {
+				assert.True(t, done[ref], "table %s references %s, which is not backed up before", table, ref)
+			}
+			done[table] = true
+		}
+	})
+
+	t.Run("serial columns belong to tables included in the backups", func(t *testing.T) {
+		for table := range serialColumns {
+			assert.Contains(t, tables, table)
+		}
+	})
+}
+
+func TestImagesObjectsMatch(t *testing.T) {
+	i1 := &ImageObject{ImageID: "image1", Version: "1x", Size: 10, SHA256: "hash1"}
+	i2 := &ImageObject{ImageID: "image1", Version: "2x", Size: 20, SHA256: "hash2"}
+	i2b := &ImageObject{ImageID: "image1", Version: "2x", Size: 20, SHA256: "hash3"}
+
+	assert.True(t, imagesObjectsMatch(nil, []*ImageObject{}))
+	assert.True(t, imagesObjectsMatch([]*ImageObject{i1, i2}, []*ImageObject{i1, i2}))
+	assert.False(t, imagesObjectsMatch([]*ImageObject{i1}, []*ImageObject{i1, i2}))
+	assert.False(t, imagesObjectsMatch([]*ImageObject{i1, i2}, []*ImageObject{i1, i2b}))
+}
+
+func newTestArchive(t *testing.T, name, content string) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := writeArchiveEntry(tw, name, int64(len(content)), bytes.NewReader([]byte(content))); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+)
+
+const defaultCheckInterval = 24 * time.Hour
+
+// Checker is in charge of re-checking periodically the verification status of
+// all the email domains registered by the organizations, so that domains whose
+// verification record is removed stop being considered verified.
+type Checker struct {
+	dm       hub.DomainManager
+	interval time.Duration
+}
+
+// NewChecker creates a new Checker instance.
+func NewChecker(cfg *viper.Viper, dm hub.DomainManager) *Checker {
+	interval := defaultCheckInterval
+	if cfg != nil && cfg.GetDuration("server.domainsCheckInterval") > 0 {
+		interval = cfg.GetDuration("server.domainsCheckInterval")
+	}
+	return &Checker{
+		dm:       dm,
+		interval: interval,
+	}
+}
+
+// Run checks all the domains periodically until it's asked to stop via the
+// context provided.
+func (c *Checker) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for {
+		c.checkDomains(ctx)
+		select {
+		case <-time.After(c.interval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// checkDomains checks the verification status of all the domains registered.
+func (c *Checker) checkDomains(ctx context.Context) {
+	domains, err := c.dm.GetAll(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("error getting organizations domains")
+		return
+	}
+	for _, d := range domains {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		if _, err := c.dm.Check(ctx, d); err != nil {
+			log.Error().Err(err).Str("domain", d.Domain).Msg("error checking domain")
+		}
+	}
+}
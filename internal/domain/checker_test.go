@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestChecker(t *testing.T) {
+	d1 := &hub.OrganizationDomain{OrganizationDomainID: "domain1ID", Domain: "org1.com"}
+	d2 := &hub.OrganizationDomain{OrganizationDomainID: "domain2ID", Domain: "org2.com"}
+
+	t.Run("check interval is read from the configuration", func(t *testing.T) {
+		cfg := viper.New()
+		cfg.Set("server.domainsCheckInterval", "1h")
+		c := NewChecker(cfg, nil)
+		assert.Equal(t, 1*time.Hour, c.interval)
+
+		c = NewChecker(viper.New(), nil)
+		assert.Equal(t, defaultCheckInterval, c.interval)
+	})
+
+	t.Run("error getting domains", func(t *testing.T) {
+		ctx, stop := context.WithCancel(context.Background())
+		dm := &ManagerMock{}
+		dm.On("GetAll", ctx).Return(nil, tests.ErrFakeDatabaseFailure).Run(func(_ mock.Arguments) {
+			stop()
+		})
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go NewChecker(nil, dm).Run(ctx, &wg)
+		wg.Wait()
+		dm.AssertExpectations(t)
+	})
+
+	t.Run("all domains are checked, even when checking one fails", func(t *testing.T) {
+		ctx, stop := context.WithCancel(context.Background())
+		dm := &ManagerMock{}
+		dm.On("GetAll", ctx).Return([]*hub.OrganizationDomain{d1, d2}, nil)
+		dm.On("Check", ctx, d1).Return(false, tests.ErrFakeDatabaseFailure)
+		dm.On("Check", ctx, d2).Return(true, nil).Run(func(_ mock.Arguments) {
+			stop()
+		})
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go NewChecker(nil, dm).Run(ctx, &wg)
+		wg.Wait()
+		dm.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,219 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	// ChallengeRecordPrefix represents the prefix added to a domain to build
+	// the name of the DNS TXT record used to verify it.
+	ChallengeRecordPrefix = "_artifacthub-challenge."
+
+	// ChallengeValuePrefix represents the prefix of the value that the DNS
+	// TXT record used to verify a domain must contain, followed by the
+	// domain's verification token.
+	ChallengeValuePrefix = "artifacthub-domain-verification="
+)
+
+// domainRE is a regexp used to validate the email domains provided.
+var domainRE = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// Manager provides an API to manage the email domains of the organizations
+// and verify their ownership.
+type Manager struct {
+	db hub.DB
+	r  hub.TXTResolver
+}
+
+// NewManager creates a new Manager instance.
+func NewManager(db hub.DB, opts ...func(m *Manager)) *Manager {
+	m := &Manager{
+		db: db,
+		r:  net.DefaultResolver,
+	}
+	for _, o := range opts {
+		o(m)
+	}
+	return m
+}
+
+// WithTXTResolver allows providing a specific TXT records resolver to a
+// Manager instance.
+func WithTXTResolver(r hub.TXTResolver) func(m *Manager) {
+	return func(m *Manager) {
+		m.r = r
+	}
+}
+
+// Add adds the provided email domain to the organization given. The user doing
+// the request must be a member of the organization.
+func (m *Manager) Add(ctx context.Context, orgName string, d *hub.OrganizationDomain) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if orgName == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "organization name not provided")
+	}
+	d.Domain = strings.ToLower(d.Domain)
+	if !domainRE.MatchString(d.Domain) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid domain")
+	}
+
+	// Add organization domain to database
+	query := "select add_organization_domain($1::uuid, $2::text, $3::jsonb)"
+	dJSON, _ := json.Marshal(d)
+	_, err := m.db.Exec(ctx, query, userID, orgName, dJSON)
+	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+		return hub.ErrInsufficientPrivilege
+	}
+	return err
+}
+
+// Check checks if the DNS TXT record used to verify the domain provided
+// contains its verification token, storing the result in the database. When
+// the record cannot be found the domain is considered not verified, but any
+// other resolution error is returned, leaving the verification status of the
+// domain untouched.
+func (m *Manager) Check(ctx context.Context, d *hub.OrganizationDomain) (bool, error) {
+	var verified bool
+	records, err := m.r.LookupTXT(ctx, ChallengeRecordPrefix+d.Domain)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+			return false, err
+		}
+	}
+	for _, record := range records {
+		if record == ChallengeValuePrefix+d.VerificationToken {
+			verified = true
+			break
+		}
+	}
+
+	// Update domain verification status in database
+	query := "select update_organization_domain_verification($1::uuid, $2::boolean)"
+	if _, err := m.db.Exec(ctx, query, d.OrganizationDomainID, verified); err != nil {
+		return false, err
+	}
+	return verified, nil
+}
+
+// Delete deletes the provided email domain from the organization given. The
+// user doing the request must be a member of the organization.
+func (m *Manager) Delete(ctx context.Context, orgName, domain string) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if err := validateDomainInput(orgName, domain); err != nil {
+		return err
+	}
+
+	// Delete organization domain from database
+	query := "select delete_organization_domain($1::uuid, $2::text, $3::text)"
+	_, err := m.db.Exec(ctx, query, userID, orgName, domain)
+	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+		return hub.ErrInsufficientPrivilege
+	}
+	return err
+}
+
+// GetAll returns all the email domains registered by the organizations.
+func (m *Manager) GetAll(ctx context.Context) ([]*hub.OrganizationDomain, error) {
+	var domains []*hub.OrganizationDomain
+	dataJSON, err := m.dbQueryJSON(ctx, "select get_all_organizations_domains()")
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(dataJSON, &domains); err != nil {
+		return nil, err
+	}
+	return domains, nil
+}
+
+// GetByOrgJSON returns the email domains of the provided organization as a
+// json object. The user doing the request must be a member of the
+// organization.
+func (m *Manager) GetByOrgJSON(ctx context.Context, orgName string) ([]byte, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if orgName == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "organization name not provided")
+	}
+
+	// Get organization domains from database
+	query := "select get_organization_domains($1::uuid, $2::text)"
+	dataJSON, err := m.dbQueryJSON(ctx, query, userID, orgName)
+	if err != nil {
+		if err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+			return nil, hub.ErrInsufficientPrivilege
+		}
+		return nil, err
+	}
+	return dataJSON, nil
+}
+
+// Verify checks the ownership of the provided email domain of the
+// organization given, returning if it was verified or not. The user doing the
+// request must be a member of the organization.
+func (m *Manager) Verify(ctx context.Context, orgName, domain string) (bool, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if err := validateDomainInput(orgName, domain); err != nil {
+		return false, err
+	}
+
+	// Get organization domain from database
+	query := "select get_organization_domain($1::uuid, $2::text, $3::text)"
+	dataJSON, err := m.dbQueryJSON(ctx, query, userID, orgName, domain)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return false, hub.ErrNotFound
+		case err.Error() == util.ErrDBInsufficientPrivilege.Error():
+			return false, hub.ErrInsufficientPrivilege
+		default:
+			return false, err
+		}
+	}
+	var d *hub.OrganizationDomain
+	if err := json.Unmarshal(dataJSON, &d); err != nil {
+		return false, err
+	}
+
+	// Check domain verification record
+	return m.Check(ctx, d)
+}
+
+// dbQueryJSON is a helper that executes the query provided and returns a bytes
+// slice containing the json data returned from the database.
+func (m *Manager) dbQueryJSON(ctx context.Context, query string, args ...interface{}) ([]byte, error) {
+	var dataJSON []byte
+	if err := m.db.QueryRow(ctx, query, args...).Scan(&dataJSON); err != nil {
+		return nil, err
+	}
+	return dataJSON, nil
+}
+
+// validateDomainInput checks the input provided to operate on an organization
+// domain is valid.
+func validateDomainInput(orgName, domain string) error {
+	if orgName == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "organization name not provided")
+	}
+	if domain == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "domain not provided")
+	}
+	return nil
+}
@@ -0,0 +1,438 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdd(t *testing.T) {
+	dbQuery := "select add_organization_domain($1::uuid, $2::text, $3::jsonb)"
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_ = m.Add(context.Background(), "org1", &hub.OrganizationDomain{})
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg  string
+			orgName string
+			domain  string
+		}{
+			{
+				"organization name not provided",
+				"",
+				"org1.com",
+			},
+			{
+				"invalid domain",
+				"org1",
+				"",
+			},
+			{
+				"invalid domain",
+				"org1",
+				"org1",
+			},
+			{
+				"invalid domain",
+				"org1",
+				"user@org1.com",
+			},
+			{
+				"invalid domain",
+				"org1",
+				"-org1.com",
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil)
+				err := m.Add(ctx, tc.orgName, &hub.OrganizationDomain{Domain: tc.domain})
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDatabaseFailure,
+				tests.ErrFakeDatabaseFailure,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("Exec", ctx, dbQuery, "userID", "org1", []byte(`{"organization_domain_id":"","domain":"org1.com","verification_token":"","verified":false,"auto_join":true}`)).Return(tc.dbErr)
+				m := NewManager(db)
+
+				err := m.Add(ctx, "org1", &hub.OrganizationDomain{Domain: "org1.com", AutoJoin: true})
+				assert.Equal(t, tc.expectedError, err)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("add domain succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, "userID", "org1", []byte(`{"organization_domain_id":"","domain":"org1.com","verification_token":"","verified":false,"auto_join":false}`)).Return(nil)
+		m := NewManager(db)
+
+		err := m.Add(ctx, "org1", &hub.OrganizationDomain{Domain: "Org1.com"})
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestCheck(t *testing.T) {
+	dbQuery := "select update_organization_domain_verification($1::uuid, $2::boolean)"
+	ctx := context.Background()
+	d := &hub.OrganizationDomain{
+		OrganizationDomainID: "domainID",
+		Domain:               "org1.com",
+		VerificationToken:    "token",
+	}
+	recordName := "_artifacthub-challenge.org1.com"
+
+	t.Run("error resolving record", func(t *testing.T) {
+		r := &TXTResolverMock{}
+		r.On("LookupTXT", ctx, recordName).Return(nil, &net.DNSError{Err: "timeout", IsTimeout: true})
+		m := NewManager(nil, WithTXTResolver(r))
+
+		verified, err := m.Check(ctx, d)
+		assert.Error(t, err)
+		assert.False(t, verified)
+		r.AssertExpectations(t)
+	})
+
+	t.Run("verification status is stored", func(t *testing.T) {
+		testCases := []struct {
+			description      string
+			records          []string
+			lookupErr        error
+			expectedVerified bool
+		}{
+			{
+				"record not found",
+				nil,
+				&net.DNSError{Err: "no such host", IsNotFound: true},
+				false,
+			},
+			{
+				"record does not contain the token",
+				[]string{"artifacthub-domain-verification=other"},
+				nil,
+				false,
+			},
+			{
+				"record contains the token",
+				[]string{"v=spf1 -all", "artifacthub-domain-verification=token"},
+				nil,
+				true,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.description, func(t *testing.T) {
+				r := &TXTResolverMock{}
+				r.On("LookupTXT", ctx, recordName).Return(tc.records, tc.lookupErr)
+				db := &tests.DBMock{}
+				db.On("Exec", ctx, dbQuery, "domainID", tc.expectedVerified).Return(nil)
+				m := NewManager(db, WithTXTResolver(r))
+
+				verified, err := m.Check(ctx, d)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedVerified, verified)
+				r.AssertExpectations(t)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		r := &TXTResolverMock{}
+		r.On("LookupTXT", ctx, recordName).Return([]string{"artifacthub-domain-verification=token"}, nil)
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, "domainID", true).Return(tests.ErrFakeDatabaseFailure)
+		m := NewManager(db, WithTXTResolver(r))
+
+		verified, err := m.Check(ctx, d)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.False(t, verified)
+		r.AssertExpectations(t)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	dbQuery := "select delete_organization_domain($1::uuid, $2::text, $3::text)"
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_ = m.Delete(context.Background(), "org1", "org1.com")
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg  string
+			orgName string
+			domain  string
+		}{
+			{
+				"organization name not provided",
+				"",
+				"org1.com",
+			},
+			{
+				"domain not provided",
+				"org1",
+				"",
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil)
+				err := m.Delete(ctx, tc.orgName, tc.domain)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDatabaseFailure,
+				tests.ErrFakeDatabaseFailure,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("Exec", ctx, dbQuery, "userID", "org1", "org1.com").Return(tc.dbErr)
+				m := NewManager(db)
+
+				err := m.Delete(ctx, "org1", "org1.com")
+				assert.Equal(t, tc.expectedError, err)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("delete domain succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, "userID", "org1", "org1.com").Return(nil)
+		m := NewManager(db)
+
+		err := m.Delete(ctx, "org1", "org1.com")
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetAll(t *testing.T) {
+	dbQuery := "select get_all_organizations_domains()"
+	ctx := context.Background()
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery).Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		domains, err := m.GetAll(ctx)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.Nil(t, domains)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("all domains returned successfully", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery).Return([]byte(`
+		[{
+			"organization_domain_id": "domainID",
+			"domain": "org1.com",
+			"verification_token": "token",
+			"verified": true,
+			"auto_join": false
+		}]
+		`), nil)
+		m := NewManager(db)
+
+		domains, err := m.GetAll(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []*hub.OrganizationDomain{
+			{
+				OrganizationDomainID: "domainID",
+				Domain:               "org1.com",
+				VerificationToken:    "token",
+				Verified:             true,
+			},
+		}, domains)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetByOrgJSON(t *testing.T) {
+	dbQuery := "select get_organization_domains($1::uuid, $2::text)"
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetByOrgJSON(context.Background(), "org1")
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		m := NewManager(nil)
+		_, err := m.GetByOrgJSON(ctx, "")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDatabaseFailure,
+				tests.ErrFakeDatabaseFailure,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, dbQuery, "userID", "org1").Return(nil, tc.dbErr)
+				m := NewManager(db)
+
+				dataJSON, err := m.GetByOrgJSON(ctx, "org1")
+				assert.Equal(t, tc.expectedError, err)
+				assert.Nil(t, dataJSON)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("organization domains data returned successfully", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "userID", "org1").Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetByOrgJSON(ctx, "org1")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestVerify(t *testing.T) {
+	getDomainDBQuery := "select get_organization_domain($1::uuid, $2::text, $3::text)"
+	updateDomainDBQuery := "select update_organization_domain_verification($1::uuid, $2::boolean)"
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_, _ = m.Verify(context.Background(), "org1", "org1.com")
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		m := NewManager(nil)
+		_, err := m.Verify(ctx, "org1", "")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("error getting domain", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDatabaseFailure,
+				tests.ErrFakeDatabaseFailure,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+			{
+				pgx.ErrNoRows,
+				hub.ErrNotFound,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, getDomainDBQuery, "userID", "org1", "org1.com").Return(nil, tc.dbErr)
+				m := NewManager(db)
+
+				verified, err := m.Verify(ctx, "org1", "org1.com")
+				assert.Equal(t, tc.expectedError, err)
+				assert.False(t, verified)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("domain verified successfully", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getDomainDBQuery, "userID", "org1", "org1.com").Return([]byte(`
+		{
+			"organization_domain_id": "domainID",
+			"domain": "org1.com",
+			"verification_token": "token"
+		}
+		`), nil)
+		db.On("Exec", ctx, updateDomainDBQuery, "domainID", true).Return(nil)
+		r := &TXTResolverMock{}
+		r.On("LookupTXT", ctx, "_artifacthub-challenge.org1.com").Return([]string{"artifacthub-domain-verification=token"}, nil)
+		m := NewManager(db, WithTXTResolver(r))
+
+		verified, err := m.Verify(ctx, "org1", "org1.com")
+		assert.NoError(t, err)
+		assert.True(t, verified)
+		db.AssertExpectations(t)
+		r.AssertExpectations(t)
+	})
+}
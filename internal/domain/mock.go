@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/mock"
+)
+
+// ManagerMock is a mock implementation of the DomainManager interface.
+type ManagerMock struct {
+	mock.Mock
+}
+
+// Add implements the DomainManager interface.
+func (m *ManagerMock) Add(ctx context.Context, orgName string, d *hub.OrganizationDomain) error {
+	args := m.Called(ctx, orgName, d)
+	return args.Error(0)
+}
+
+// Check implements the DomainManager interface.
+func (m *ManagerMock) Check(ctx context.Context, d *hub.OrganizationDomain) (bool, error) {
+	args := m.Called(ctx, d)
+	return args.Bool(0), args.Error(1)
+}
+
+// Delete implements the DomainManager interface.
+func (m *ManagerMock) Delete(ctx context.Context, orgName, domain string) error {
+	args := m.Called(ctx, orgName, domain)
+	return args.Error(0)
+}
+
+// GetAll implements the DomainManager interface.
+func (m *ManagerMock) GetAll(ctx context.Context) ([]*hub.OrganizationDomain, error) {
+	args := m.Called(ctx)
+	data, _ := args.Get(0).([]*hub.OrganizationDomain)
+	return data, args.Error(1)
+}
+
+// GetByOrgJSON implements the DomainManager interface.
+func (m *ManagerMock) GetByOrgJSON(ctx context.Context, orgName string) ([]byte, error) {
+	args := m.Called(ctx, orgName)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+// Verify implements the DomainManager interface.
+func (m *ManagerMock) Verify(ctx context.Context, orgName, domain string) (bool, error) {
+	args := m.Called(ctx, orgName, domain)
+	return args.Bool(0), args.Error(1)
+}
+
+// TXTResolverMock is a mock implementation of the TXTResolver interface.
+type TXTResolverMock struct {
+	mock.Mock
+}
+
+// LookupTXT implements the TXTResolver interface.
+func (m *TXTResolverMock) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	args := m.Called(ctx, name)
+	records, _ := args.Get(0).([]string)
+	return records, args.Error(1)
+}
@@ -0,0 +1,18 @@
+package hub
+
+import "context"
+
+// AdoptionRequest represents a request from a user to become a maintainer of
+// a package whose publisher is seeking new maintainers.
+type AdoptionRequest struct {
+	Message string `json:"message"`
+}
+
+// AdoptionManager describes the methods an AdoptionManager implementation
+// must provide.
+type AdoptionManager interface {
+	Add(ctx context.Context, packageID string, r *AdoptionRequest, baseURL string) error
+	GetAllJSON(ctx context.Context) ([]byte, error)
+	GetByPackageJSON(ctx context.Context, packageID string) ([]byte, error)
+	SetSeekingMaintainers(ctx context.Context, packageID string, seekingMaintainers bool) error
+}
@@ -4,10 +4,11 @@ import "context"
 
 // APIKey represents a key used to interact with the HTTP API.
 type APIKey struct {
-	APIKeyID  string `json:"api_key_id"`
-	Name      string `json:"name"`
-	CreatedAt int64  `json:"created_at"`
-	UserID    string `json:"user_id"`
+	APIKeyID     string `json:"api_key_id"`
+	Name         string `json:"name"`
+	CreatedAt    int64  `json:"created_at"`
+	UserID       string `json:"user_id"`
+	RepositoryID string `json:"repository_id,omitempty"`
 }
 
 // APIKeyManager describes the methods an APIKeyManager implementation must
@@ -16,6 +17,14 @@ type APIKeyManager interface {
 	Add(ctx context.Context, ak *APIKey) ([]byte, error)
 	Delete(ctx context.Context, apiKeyID string) error
 	GetJSON(ctx context.Context, apiKeyID string) ([]byte, error)
+	GetOrganizationUsageJSON(ctx context.Context, orgName string, days int) ([]byte, error)
 	GetOwnedByUserJSON(ctx context.Context) ([]byte, error)
 	Update(ctx context.Context, ak *APIKey) error
 }
+
+// APIKeyUsageTracker describes the methods an APIKeyUsageTracker
+// implementation must provide.
+type APIKeyUsageTracker interface {
+	Track(apiKeyID string, key []byte, endpoint string)
+	TrackRateLimited(key []byte, endpoint string)
+}
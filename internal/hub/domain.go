@@ -0,0 +1,31 @@
+package hub
+
+import "context"
+
+// OrganizationDomain represents an email domain claimed by an organization.
+// Once verified, the domain is displayed in the organization's profile and,
+// when auto join is enabled, users with a verified email address in it join
+// the organization automatically.
+type OrganizationDomain struct {
+	OrganizationDomainID string `json:"organization_domain_id"`
+	Domain               string `json:"domain"`
+	VerificationToken    string `json:"verification_token"`
+	Verified             bool   `json:"verified"`
+	AutoJoin             bool   `json:"auto_join"`
+}
+
+// DomainManager describes the methods a DomainManager implementation must
+// provide.
+type DomainManager interface {
+	Add(ctx context.Context, orgName string, d *OrganizationDomain) error
+	Check(ctx context.Context, d *OrganizationDomain) (bool, error)
+	Delete(ctx context.Context, orgName, domain string) error
+	GetAll(ctx context.Context) ([]*OrganizationDomain, error)
+	GetByOrgJSON(ctx context.Context, orgName string) ([]byte, error)
+	Verify(ctx context.Context, orgName, domain string) (bool, error)
+}
+
+// TXTResolver describes the methods a TXTResolver implementation must provide.
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
@@ -0,0 +1,20 @@
+package hub
+
+import "context"
+
+const (
+	// EmailBounce represents the reason used to suppress the email addresses
+	// which hard bounced.
+	EmailBounce = "bounce"
+
+	// EmailComplaint represents the reason used to suppress the email
+	// addresses whose owners marked an email sent by the hub as spam.
+	EmailComplaint = "complaint"
+)
+
+// EmailSuppressionManager describes the methods an EmailSuppressionManager
+// implementation must provide.
+type EmailSuppressionManager interface {
+	IsSuppressed(ctx context.Context, email string) (bool, error)
+	Suppress(ctx context.Context, email, reason string) error
+}
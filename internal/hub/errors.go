@@ -10,6 +10,10 @@ var (
 	// required privilege to perform the operation.
 	ErrInsufficientPrivilege = errors.New("insufficient_privilege")
 
+	// ErrLimitExceeded indicates that the operation cannot be performed
+	// because it would exceed one of the configured limits.
+	ErrLimitExceeded = errors.New("limit exceeded")
+
 	// ErrNotFound indicates that the requested item was not found.
 	ErrNotFound = errors.New("not found")
 )
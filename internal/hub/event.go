@@ -23,8 +23,16 @@ const (
 
 	// SecurityAlert represents an event for a security alert.
 	SecurityAlert EventKind = 1
+
+	// StatementChange represents an event for a change in the statements
+	// attached to a package by its publisher.
+	StatementChange EventKind = 2
 )
 
+// SubscribableEventKinds represents the kinds of events users and webhooks
+// can subscribe to.
+var SubscribableEventKinds = []EventKind{NewRelease, StatementChange}
+
 // EventManager describes the methods an EventManager implementation must
 // provide.
 type EventManager interface {
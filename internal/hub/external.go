@@ -19,3 +19,10 @@ type DB interface {
 type EmailSender interface {
 	SendEmail(data *email.Data) error
 }
+
+// SecretsCipher defines the methods the secrets cipher used to encrypt the
+// sensitive data stored in the database must provide.
+type SecretsCipher interface {
+	Encrypt(ctx context.Context, plaintext string) (string, error)
+	Decrypt(ctx context.Context, value string) (string, error)
+}
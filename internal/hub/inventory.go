@@ -0,0 +1,33 @@
+package hub
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// ClusterInventory represents the list of releases installed in a cluster,
+// stored so that they can be checked periodically against the packages
+// available in the hub. Reports listing the releases that are outdated are
+// delivered by email to the owner of the inventory and/or to the webhook url
+// provided.
+type ClusterInventory struct {
+	ClusterInventoryID string              `json:"cluster_inventory_id"`
+	Name               string              `json:"name"`
+	Releases           []*InstalledRelease `json:"releases"`
+	EmailReport        bool                `json:"email_report"`
+	WebhookURL         string              `json:"webhook_url"`
+	User               *User               `json:"user,omitempty"`
+}
+
+// ClusterInventoryManager describes the methods a ClusterInventoryManager
+// implementation must provide.
+type ClusterInventoryManager interface {
+	Add(ctx context.Context, inv *ClusterInventory) error
+	Delete(ctx context.Context, clusterInventoryID string) error
+	GetOwnedByUserJSON(ctx context.Context) ([]byte, error)
+	GetPendingReport(ctx context.Context, tx pgx.Tx, interval time.Duration, fromHour, toHour int) (*ClusterInventory, error)
+	Update(ctx context.Context, inv *ClusterInventory) error
+	UpdateLastReport(ctx context.Context, tx pgx.Tx, clusterInventoryID string) error
+}
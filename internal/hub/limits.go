@@ -0,0 +1,39 @@
+package hub
+
+import "fmt"
+
+// Limits represents the global limits enforced by the managers. Operators can
+// override the default values using the server.limits configuration section.
+// A zero value in WebhooksPerUser, RepositoriesPerOrg or ReadmeMaxBytes means
+// that no limit is enforced.
+type Limits struct {
+	SearchMaxPageSize  int
+	ReadmeMaxBytes     int
+	WebhooksPerUser    int
+	RepositoriesPerOrg int
+}
+
+// DefaultLimits returns the limits used when no overrides are configured.
+func DefaultLimits() *Limits {
+	return &Limits{
+		SearchMaxPageSize: 50,
+	}
+}
+
+// LimitExceededError represents an error returned when an operation cannot be
+// performed because it would exceed one of the configured limits.
+type LimitExceededError struct {
+	Limit string
+	Max   int
+}
+
+// Error implements the error interface.
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s: %s (max %d)", ErrLimitExceeded, e.Limit, e.Max)
+}
+
+// Unwrap returns ErrLimitExceeded, so that LimitExceededError instances can be
+// checked using errors.Is.
+func (e *LimitExceededError) Unwrap() error {
+	return ErrLimitExceeded
+}
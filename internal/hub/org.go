@@ -18,11 +18,27 @@ type Organization struct {
 type OrganizationManager interface {
 	Add(ctx context.Context, org *Organization) error
 	AddMember(ctx context.Context, orgName, userAlias, baseURL string) error
+	AddShare(ctx context.Context, orgName, targetOrgName string) error
 	CheckAvailability(ctx context.Context, resourceKind, value string) (bool, error)
 	ConfirmMembership(ctx context.Context, orgName string) error
 	DeleteMember(ctx context.Context, orgName, userAlias string) error
+	DeleteShare(ctx context.Context, orgName, targetOrgName string) error
 	GetJSON(ctx context.Context, orgName string) ([]byte, error)
 	GetByUserJSON(ctx context.Context) ([]byte, error)
+	GetMemberDeletionImpactJSON(ctx context.Context, orgName, userAlias string) ([]byte, error)
 	GetMembersJSON(ctx context.Context, orgName string) ([]byte, error)
+	GetSharesJSON(ctx context.Context, orgName string) ([]byte, error)
+	GetValidationWebhookJSON(ctx context.Context, orgName string) ([]byte, error)
 	Update(ctx context.Context, org *Organization) error
+	UpdateFeaturedPackages(ctx context.Context, orgName string, packagesIDs []string) error
+	UpdateValidationWebhook(ctx context.Context, orgName string, vw *ValidationWebhook) error
+}
+
+// ValidationWebhook represents a webhook that an organization can set up to
+// validate its packages before they are published. The trackers will call it
+// with the metadata extracted from each package version, allowing the
+// organization to flag or reject the versions that don't meet its policies.
+type ValidationWebhook struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret"`
 }
@@ -4,18 +4,58 @@ import (
 	"context"
 )
 
+// OperatorCapabilities represents the operator capability levels supported,
+// sorted from the least to the most mature one.
+var OperatorCapabilities = []string{
+	"Basic Install",
+	"Seamless Upgrades",
+	"Full Lifecycle",
+	"Deep Insights",
+	"Auto Pilot",
+}
+
+// ChartTypes represents the Helm chart types supported.
+var ChartTypes = []string{
+	"application",
+	"library",
+}
+
 // Channel represents a package's channel.
 type Channel struct {
 	Name    string `json:"name"`
 	Version string `json:"version"`
 }
 
+// ContainerImage represents a container image referenced by a package.
+type ContainerImage struct {
+	Name  string `json:"name,omitempty" yaml:"name"`
+	Image string `json:"image" yaml:"image"`
+}
+
+// GetAllPackagesInput represents the input used to iterate over all the
+// packages available.
+type GetAllPackagesInput struct {
+	Cursor          string           `json:"cursor,omitempty"`
+	Limit           int              `json:"limit"`
+	RepositoryKinds []RepositoryKind `json:"repository_kinds,omitempty"`
+}
+
+// GetPackageChangesInput represents the input used to get a page of the
+// packages versions changes registered since a given time (unix timestamp).
+// The cursor is the id of the last change of the previous page, if any.
+type GetPackageChangesInput struct {
+	Since  int64 `json:"since"`
+	Cursor int64 `json:"cursor,omitempty"`
+	Limit  int   `json:"limit"`
+}
+
 // GetPackageInput represents the input used to get a specific package.
 type GetPackageInput struct {
 	PackageID      string `json:"package_id"`
 	RepositoryName string `json:"repository_name"`
 	PackageName    string `json:"package_name"`
 	Version        string `json:"version"`
+	UserID         string `json:"user_id,omitempty"`
 }
 
 // Link represents a url associated with a package.
@@ -52,31 +92,121 @@ type Package struct {
 	Version           string                 `json:"version"`
 	AvailableVersions []*Version             `json:"available_versions"`
 	AppVersion        string                 `json:"app_version"`
+	KubeVersion       string                 `json:"kube_version"`
+	ChartType         string                 `json:"chart_type"`
 	Digest            string                 `json:"digest"`
 	Deprecated        bool                   `json:"deprecated"`
 	License           string                 `json:"license"`
 	Signed            bool                   `json:"signed"`
+	SignKey           *SignKey               `json:"sign_key"`
+	SignatureVerified bool                   `json:"signature_verified"`
+	Changes           []string               `json:"changes"`
+	Changelog         string                 `json:"changelog,omitempty"`
 	ContentURL        string                 `json:"content_url"`
 	ContainerImage    string                 `json:"container_image"`
+	ContainersImages  []*ContainerImage      `json:"containers_images"`
 	Provider          string                 `json:"provider"`
+	Capabilities      string                 `json:"capabilities"`
+	Maintenance       *Maintenance           `json:"maintenance"`
 	Maintainers       []*Maintainer          `json:"maintainers"`
 	Repository        *Repository            `json:"repository"`
 	CreatedAt         int64                  `json:"created_at,omitempty"`
 }
 
+// Maintenance represents the maintenance details declared by a package
+// version: the range of versions of the package that are still supported and
+// the date the version reaches its end of life (YYYY-MM-DD).
+type Maintenance struct {
+	SupportedVersions string `json:"supported_versions,omitempty"`
+	EOL               string `json:"eol,omitempty"`
+}
+
+// SignKey represents the details of the key used to sign a package version:
+// its id and fingerprint, and the url where the public key can be fetched
+// from to verify the package, when declared by the publisher.
+type SignKey struct {
+	KeyID       string `json:"key_id"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+	URL         string `json:"url,omitempty"`
+}
+
+// PackageChange represents a change (creation, update or deletion) of a
+// package version.
+type PackageChange struct {
+	PackageChangeID int64          `json:"package_change_id"`
+	PackageID       string         `json:"package_id"`
+	PackageName     string         `json:"package_name"`
+	Version         string         `json:"version"`
+	RepositoryID    string         `json:"repository_id"`
+	RepositoryName  string         `json:"repository_name"`
+	RepositoryKind  RepositoryKind `json:"repository_kind"`
+	ChangeKind      string         `json:"change_kind"`
+	TS              int64          `json:"ts"`
+}
+
+// PackageChangesPage represents a page of packages versions changes. The next
+// cursor is only set when more changes may be available.
+type PackageChangesPage struct {
+	Changes    []*PackageChange `json:"changes"`
+	NextCursor *int64           `json:"next_cursor"`
+}
+
 // PackageManager describes the methods a PackageManager implementation must
 // provide.
 type PackageManager interface {
 	Get(ctx context.Context, input *GetPackageInput) (*Package, error)
+	GetAllJSON(ctx context.Context, input *GetAllPackagesInput) ([]byte, error)
+	GetByOwner(ctx context.Context, owner string) ([]*Package, error)
+	GetChangesJSON(ctx context.Context, input *GetPackageChangesInput) ([]byte, error)
+	GetDependenciesGraphJSON(ctx context.Context, input *GetPackageInput) ([]byte, error)
 	GetJSON(ctx context.Context, input *GetPackageInput) ([]byte, error)
+	GetLatestVersionsJSON(ctx context.Context, input *GetPackageInput) ([]byte, error)
+	GetProjectJSON(ctx context.Context, packageID string) ([]byte, error)
 	GetRandomJSON(ctx context.Context) ([]byte, error)
 	GetStarredByUserJSON(ctx context.Context) ([]byte, error)
 	GetStarsJSON(ctx context.Context, packageID string) ([]byte, error)
 	GetStatsJSON(ctx context.Context) ([]byte, error)
+	GetTagsJSON(ctx context.Context, limit int) ([]byte, error)
+	GetVersionDocsJSON(ctx context.Context, input *GetPackageInput) ([]byte, error)
+	MatchInstalledJSON(ctx context.Context, releases []*InstalledRelease) ([]byte, error)
+	Push(ctx context.Context, md *PackageMetadata) error
 	Register(ctx context.Context, pkg *Package) error
+	Resolve(ctx context.Context, input *ResolvePackageInput) ([]*Package, error)
 	SearchJSON(ctx context.Context, input *SearchPackageInput) ([]byte, error)
 	ToggleStar(ctx context.Context, packageID string) error
 	Unregister(ctx context.Context, pkg *Package) error
+	UpdateLogoImage(ctx context.Context, pkg *Package, logoImageID string) error
+}
+
+// InstalledRelease represents a release installed in a cluster, identified by
+// the name and version of the chart and the url of the repository it was
+// installed from.
+type InstalledRelease struct {
+	Name          string `json:"name"`
+	Version       string `json:"version"`
+	RepositoryURL string `json:"repository_url"`
+}
+
+// InstalledReleaseStatus represents the status of an installed release once it
+// has been matched against the packages available in the hub.
+type InstalledReleaseStatus struct {
+	Name              string          `json:"name"`
+	Version           string          `json:"version"`
+	RepositoryURL     string          `json:"repository_url"`
+	Package           *MatchedPackage `json:"package"`
+	VersionRegistered bool            `json:"version_registered"`
+	UpdateAvailable   bool            `json:"update_available"`
+	Deprecated        bool            `json:"deprecated"`
+	EOL               bool            `json:"eol"`
+}
+
+// MatchedPackage represents the package an installed release was matched to.
+type MatchedPackage struct {
+	PackageID      string      `json:"package_id"`
+	Name           string      `json:"name"`
+	NormalizedName string      `json:"normalized_name"`
+	LatestVersion  string      `json:"latest_version"`
+	Repository     *Repository `json:"repository"`
 }
 
 // PackageMetadata represents some metadata about a given package. It's usually
@@ -111,19 +241,40 @@ type Provider struct {
 	Name string `yaml:"name"`
 }
 
+// ResolvePackageInput represents the input used to resolve the packages
+// matching some external identifiers: their repository url and name, or the
+// source url of their latest version. The repository kind is optional.
+type ResolvePackageInput struct {
+	RepositoryKind *RepositoryKind `json:"repository_kind,omitempty"`
+	RepositoryURL  string          `json:"repository_url,omitempty"`
+	PackageName    string          `json:"package_name,omitempty"`
+	SourceURL      string          `json:"source_url,omitempty"`
+	UserID         string          `json:"user_id,omitempty"`
+}
+
 // SearchPackageInput represents the query input when searching for packages.
 type SearchPackageInput struct {
-	Limit           int              `json:"limit,omitempty"`
-	Offset          int              `json:"offset,omitempty"`
-	Facets          bool             `json:"facets"`
-	TsQueryWeb      string           `json:"ts_query_web,omitempty"`
-	TsQuery         string           `json:"ts_query,omitempty"`
-	Users           []string         `json:"users,omitempty"`
-	Orgs            []string         `json:"orgs,omitempty"`
-	Repositories    []string         `json:"repositories,omitempty"`
-	RepositoryKinds []RepositoryKind `json:"repository_kinds,omitempty"`
-	Operators       bool             `json:"operators"`
-	Deprecated      bool             `json:"deprecated"`
+	Limit             int              `json:"limit,omitempty"`
+	Offset            int              `json:"offset,omitempty"`
+	Facets            bool             `json:"facets"`
+	TsQueryWeb        string           `json:"ts_query_web,omitempty"`
+	TsQuery           string           `json:"ts_query,omitempty"`
+	Users             []string         `json:"users,omitempty"`
+	Orgs              []string         `json:"orgs,omitempty"`
+	Repositories      []string         `json:"repositories,omitempty"`
+	ScopeUser         string           `json:"scope_user,omitempty"`
+	ScopeOrg          string           `json:"scope_org,omitempty"`
+	ScopeRepository   string           `json:"scope_repository,omitempty"`
+	RepositoryKinds   []RepositoryKind `json:"repository_kinds,omitempty"`
+	Operators         bool             `json:"operators"`
+	Deprecated        bool             `json:"deprecated"`
+	Capabilities      []string         `json:"capabilities,omitempty"`
+	ChartTypes        []string         `json:"chart_types,omitempty"`
+	Tags              []string         `json:"tags,omitempty"`
+	SupportedOnly     bool             `json:"supported_only"`
+	KubernetesVersion string           `json:"kubernetes_version,omitempty"`
+	Sort              string           `json:"sort,omitempty"`
+	UserID            string           `json:"user_id,omitempty"`
 }
 
 // Version represents a package's version
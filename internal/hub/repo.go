@@ -3,6 +3,7 @@ package hub
 import (
 	"context"
 	"errors"
+	"time"
 
 	helmrepo "helm.sh/helm/v3/pkg/repo"
 )
@@ -57,41 +58,112 @@ func GetKindFromName(kind string) (RepositoryKind, error) {
 	}
 }
 
+// RepositoryCollaboratorRoles represents the roles that can be granted to the
+// collaborators of a repository. Collaborators with the read role can access
+// the packages of the repository when it is private, whereas the ones with the
+// write role can also update it.
+var RepositoryCollaboratorRoles = []string{
+	"read",
+	"write",
+}
+
 // Repository represents a packages repository.
 type Repository struct {
-	RepositoryID            string         `json:"repository_id"`
-	Name                    string         `json:"name"`
-	DisplayName             string         `json:"display_name"`
-	URL                     string         `json:"url"`
-	Kind                    RepositoryKind `json:"kind"`
-	UserID                  string         `json:"user_id"`
-	UserAlias               string         `json:"user_alias"`
-	OrganizationID          string         `json:"organization_id"`
-	OrganizationName        string         `json:"organization_name"`
-	OrganizationDisplayName string         `json:"organization_display_name"`
+	RepositoryID            string            `json:"repository_id"`
+	Name                    string            `json:"name"`
+	DisplayName             string            `json:"display_name"`
+	URL                     string            `json:"url"`
+	Kind                    RepositoryKind    `json:"kind"`
+	TrackingInterval        int64             `json:"tracking_interval"`
+	QuietHours              *QuietHours       `json:"quiet_hours,omitempty"`
+	Private                 bool              `json:"private"`
+	Disabled                bool              `json:"disabled"`
+	SkipPrereleases         bool              `json:"skip_prereleases"`
+	SkipDeprecated          bool              `json:"skip_deprecated"`
+	Metadata                map[string]string `json:"metadata,omitempty"`
+	AuthUser                string            `json:"auth_user,omitempty"`
+	AuthPass                string            `json:"auth_pass,omitempty"`
+	TLSCACert               string            `json:"tls_ca_cert,omitempty"`
+	TLSClientCert           string            `json:"tls_client_cert,omitempty"`
+	TLSClientKey            string            `json:"tls_client_key,omitempty"`
+	ProxyURL                string            `json:"-"`
+	Branch                  string            `json:"branch,omitempty"`
+	Path                    string            `json:"path,omitempty"`
+	TagPattern              string            `json:"tag_pattern,omitempty"`
+	LastTrackingTS          int64             `json:"last_tracking_ts"`
+	TrackingRequestedTS     int64             `json:"tracking_requested_ts"`
+	UserID                  string            `json:"user_id"`
+	UserAlias               string            `json:"user_alias"`
+	OrganizationID          string            `json:"organization_id"`
+	OrganizationName        string            `json:"organization_name"`
+	OrganizationDisplayName string            `json:"organization_display_name"`
+}
+
+// QuietHours represents a daily time window during which a repository won't
+// be tracked. The start and end of the window are hours of the day in UTC. The
+// window wraps around midnight when the end is lower than the start.
+type QuietHours struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
 }
 
+// Contains checks if the time provided is within the quiet hours window.
+func (qh *QuietHours) Contains(t time.Time) bool {
+	h := t.UTC().Hour()
+	if qh.Start <= qh.End {
+		return h >= qh.Start && h < qh.End
+	}
+	return h >= qh.Start || h < qh.End
+}
+
+type repositoryIDKey struct{}
+
+// RepositoryIDKey represents the key used for the repositoryID value inside a
+// context. It is set when the request is authenticated using an api key scoped
+// to a repository.
+var RepositoryIDKey = repositoryIDKey{}
+
 // RepositoryManager describes the methods an RepositoryManager
 // implementation must provide.
 type RepositoryManager interface {
 	Add(ctx context.Context, orgName string, r *Repository) error
+	AddCollaborator(ctx context.Context, repoName, userAlias, role string) error
 	CheckAvailability(ctx context.Context, resourceKind, value string) (bool, error)
 	Delete(ctx context.Context, name string) error
+	DeleteCollaborator(ctx context.Context, repoName, userAlias string) error
 	GetAll(ctx context.Context) ([]*Repository, error)
 	GetByKind(ctx context.Context, kind RepositoryKind) ([]*Repository, error)
+	GetByMetadataJSON(ctx context.Context, metadata map[string]string) ([]byte, error)
 	GetByName(ctx context.Context, name string) (*Repository, error)
+	GetCollaboratorsJSON(ctx context.Context, repoName string) ([]byte, error)
+	GetDeletionImpactJSON(ctx context.Context, name string) ([]byte, error)
+	GetHealthJSON(ctx context.Context, repoName string) ([]byte, error)
+	GetHelmIndexValidators(ctx context.Context, repositoryID string) (*HelmIndexValidators, error)
 	GetPackagesDigest(ctx context.Context, repositoryID string) (map[string]string, error)
 	GetOwnedByOrgJSON(ctx context.Context, orgName string) ([]byte, error)
 	GetOwnedByUserJSON(ctx context.Context) ([]byte, error)
+	RegenerateTrackingSecret(ctx context.Context, repoName string) (string, error)
+	RequestTracking(ctx context.Context, repoName, secret string) error
+	SetHelmIndexValidators(ctx context.Context, repositoryID string, v *HelmIndexValidators) error
 	SetLastTrackingResults(ctx context.Context, repositoryID, errs string) error
 	Transfer(ctx context.Context, name, orgName string) error
 	Update(ctx context.Context, r *Repository) error
 }
 
+// HelmIndexValidators represents the values of the ETag and Last-Modified
+// headers returned the last time the index file of a Helm repository was
+// downloaded. They are used to send conditional requests for the index file,
+// so that it's only downloaded and parsed again when it has changed.
+type HelmIndexValidators struct {
+	ETag         string `json:"etag"`
+	LastModified string `json:"last_modified"`
+}
+
 // HelmIndexLoader interface defines the methods a Helm index loader
 // implementation should provide.
 type HelmIndexLoader interface {
 	LoadIndex(r *Repository) (*helmrepo.IndexFile, error)
+	LoadIndexIfModified(r *Repository, v *HelmIndexValidators) (*helmrepo.IndexFile, *HelmIndexValidators, error)
 }
 
 // RepositoryCloner describes the methods a RepositoryCloner implementation
@@ -0,0 +1,29 @@
+package hub
+
+import "context"
+
+// PackageStatement represents a signed statement attached to a package by its
+// publisher, like its support policy or end of life date.
+type PackageStatement struct {
+	Kind               string `json:"kind"`
+	Content            string `json:"content"`
+	Signature          string `json:"signature"`
+	SignatureAlgorithm string `json:"signature_algorithm"`
+	SignatureKeyID     string `json:"signature_key_id"`
+}
+
+// PackageStatementKinds represents the kinds of statements that can be
+// attached to a package.
+var PackageStatementKinds = []string{
+	"compliance-attestation",
+	"eol",
+	"support-policy",
+}
+
+// StatementManager describes the methods a StatementManager implementation
+// must provide.
+type StatementManager interface {
+	Add(ctx context.Context, packageID string, s *PackageStatement) error
+	Delete(ctx context.Context, packageID, kind string) error
+	GetByPackageJSON(ctx context.Context, packageID string) ([]byte, error)
+}
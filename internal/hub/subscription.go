@@ -1,6 +1,11 @@
 package hub
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
 
 // Subscription represents a user's subscription to receive notifications about
 // a given package and event kind.
@@ -10,6 +15,13 @@ type Subscription struct {
 	EventKind EventKind `json:"event_kind"`
 }
 
+// SubscriptionsDigest represents a digest of the changes introduced in the
+// packages a user is subscribed to since the time provided.
+type SubscriptionsDigest struct {
+	User  *User `json:"user"`
+	Since int64 `json:"since"`
+}
+
 // SubscriptionManager describes the methods a SubscriptionManager
 // implementation must provide.
 type SubscriptionManager interface {
@@ -17,5 +29,8 @@ type SubscriptionManager interface {
 	Delete(ctx context.Context, s *Subscription) error
 	GetByPackageJSON(ctx context.Context, packageID string) ([]byte, error)
 	GetByUserJSON(ctx context.Context) ([]byte, error)
+	GetPendingDigest(ctx context.Context, tx pgx.Tx, interval time.Duration, fromHour, toHour int) (*SubscriptionsDigest, error)
 	GetSubscriptors(ctx context.Context, packageID string, eventKind EventKind) ([]*User, error)
+	GetUserChangesJSON(ctx context.Context, limit, offset int) ([]byte, error)
+	UpdateLastDigest(ctx context.Context, tx pgx.Tx, userID string) error
 }
@@ -7,8 +7,10 @@ import (
 
 // CheckAPIKeyOutput represents the output returned by the CheckApiKey method.
 type CheckAPIKeyOutput struct {
-	Valid  bool   `json:"valid"`
-	UserID string `json:"user_id"`
+	Valid        bool   `json:"valid"`
+	APIKeyID     string `json:"api_key_id"`
+	UserID       string `json:"user_id"`
+	RepositoryID string `json:"repository_id"`
 }
 
 // CheckCredentialsOutput represents the output returned by the
@@ -34,14 +36,17 @@ type Session struct {
 
 // User represents a Hub user.
 type User struct {
-	UserID         string `json:"user_id"`
-	Alias          string `json:"alias"`
-	FirstName      string `json:"first_name"`
-	LastName       string `json:"last_name"`
-	Email          string `json:"email"`
-	EmailVerified  bool   `json:"email_verified"`
-	Password       string `json:"password"`
-	ProfileImageID string `json:"profile_image_id"`
+	UserID              string `json:"user_id"`
+	Alias               string `json:"alias"`
+	FirstName           string `json:"first_name"`
+	LastName            string `json:"last_name"`
+	Email               string `json:"email"`
+	EmailVerified       bool   `json:"email_verified"`
+	Password            string `json:"password"`
+	ProfileImageID      string `json:"profile_image_id"`
+	Locale              string `json:"locale"`
+	Timezone            string `json:"timezone"`
+	SubscriptionsDigest bool   `json:"subscriptions_digest"`
 }
 
 type userIDKey struct{}
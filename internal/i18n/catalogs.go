@@ -0,0 +1,89 @@
+package i18n
+
+// catalogs contains the translations of the messages for each of the
+// supported locales (other than the default one), indexed by the message
+// English text.
+var catalogs = map[string]map[string]string{
+	"es": es,
+}
+
+// es contains the Spanish translations.
+var es = map[string]string{
+	// Emails
+	"%s cluster report":               "Informe del clúster %s",
+	"%s new release":                  "Nueva versión de %s",
+	"%s publisher statements changed": "Declaraciones del publicador de %s modificadas",
+	"%s statements changed":           "Declaraciones de %s modificadas",
+	"%s version %s released":          "Publicada la versión %[2]s de %[1]s",
+	"%s wants to adopt %s":            "%s quiere adoptar %s",
+	"<b>%s</b> has requested to adopt the <b>%s</b> package, which is seeking maintainers.": "<b>%s</b> ha solicitado adoptar el paquete <b>%s</b>, que busca mantenedores.",
+	"Accept invitation":       "Aceptar invitación",
+	"Adoption request":        "Solicitud de adopción",
+	"Adoption request for %s": "Solicitud de adopción de %s",
+	"After activation you may sign in to Artifact Hub using your credentials.": "Después de la activación podrás iniciar sesión en Artifact Hub con tus credenciales.",
+	"Confirm your account": "Confirma tu cuenta",
+	"Deprecated":           "Obsoleta",
+	"Didn't create an Artifact Hub account? It's likely someone just typed in your email address by accident.": "¿No has creado una cuenta en Artifact Hub? Probablemente alguien ha escrito tu dirección de correo por error.",
+	"Didn't subscribe to Artifact Hub notifications for %s package? You can unsubscribe":                       "¿No te has suscrito a las notificaciones de Artifact Hub del paquete %s? Puedes cancelar la suscripción",
+	"Don't want to receive these digests anymore? You can disable them":                                        "¿No quieres seguir recibiendo estos resúmenes? Puedes desactivarlos",
+	"Don't want to receive the reports of this cluster anymore? You can disable them":                          "¿No quieres seguir recibiendo los informes de este clúster? Puedes desactivarlos",
+	"Email confirmation":              "Confirmación de correo",
+	"End of life":                     "Fin de vida",
+	"Feel free to ignore this email.": "Puedes ignorar este correo.",
+	"Hi!":                             "¡Hola!",
+	"here":                            "aquí",
+	"If this email means nothing to you, then it is possible that somebody else has entered your user alias accidentally, so please ignore this email.": "Si este correo no significa nada para ti, es posible que alguien haya introducido tu alias de usuario por error, así que por favor ignóralo.",
+	"Installed": "Instalada",
+	"Invitation to %s organization on Artifact Hub": "Invitación a la organización %s en Artifact Hub",
+	"Invitation to join %s on Artifact Hub":         "Invitación para unirte a %s en Artifact Hub",
+	"Latest":                                        "Última",
+	"Or you can copy-paste this link:":              "O puedes copiar y pegar este enlace:",
+	"Some of the releases installed in this cluster need your attention": "Algunas de las releases instaladas en este clúster requieren tu atención",
+	"Status":                          "Estado",
+	"Subscriptions digest":            "Resumen de suscripciones",
+	"Thanks for creating an account.": "Gracias por crear una cuenta.",
+	"Thanks.":                         "Gracias.",
+	"The statements attached to this package by its publisher have changed":           "Las declaraciones adjuntas a este paquete por su publicador han cambiado",
+	"These are the changes introduced recently in the packages you are subscribed to": "Estos son los cambios introducidos recientemente en los paquetes a los que estás suscrito",
+	"Update available":                    "Actualización disponible",
+	"Verify your email address":           "Verifica tu dirección de correo",
+	"Version <b>%s</b> has been released": "Se ha publicado la versión <b>%s</b>",
+	"View in Artifact Hub":                "Ver en Artifact Hub",
+	"Welcome to Artifact Hub! You are only one step from being able to sign in on our site. Please simply click on the link below to confirm your account.": "¡Bienvenido a Artifact Hub! Estás a un paso de poder iniciar sesión en nuestro sitio. Simplemente haz clic en el siguiente enlace para confirmar tu cuenta.",
+	"You are receiving this email because you are an owner of this package or an Artifact Hub admin.":                                                       "Recibes este correo porque eres propietario de este paquete o administrador de Artifact Hub.",
+	"You can also accept the invitation by visiting the page directly at":                                                                                   "También puedes aceptar la invitación visitando directamente la página",
+	"You can get in touch with them and review all the pending requests from the package page at":                                                           "Puedes ponerte en contacto con esta persona y revisar todas las solicitudes pendientes desde la página del paquete",
+	"You have been invited to join <b>%s</b> organization on Artifact Hub.":                                                                                 "Has sido invitado a unirte a la organización <b>%s</b> en Artifact Hub.",
+	"Your Artifact Hub subscriptions digest":                                                                                                                "Resumen de tus suscripciones en Artifact Hub",
+
+	// Validation errors
+	"invalid input":                      "entrada no válida",
+	"alias not provided":                 "alias no proporcionado",
+	"base url not provided":              "url base no proporcionada",
+	"email not provided":                 "correo no proporcionado",
+	"invalid base url":                   "url base no válida",
+	"invalid kind":                       "tipo no válido",
+	"invalid locale":                     "idioma no válido",
+	"invalid name":                       "nombre no válido",
+	"invalid organization name":          "nombre de organización no válido",
+	"invalid package id":                 "id de paquete no válido",
+	"invalid profile image id":           "id de imagen de perfil no válido",
+	"invalid repository id":              "id de repositorio no válido",
+	"invalid repository name":            "nombre de repositorio no válido",
+	"invalid tag":                        "etiqueta no válida",
+	"invalid timezone":                   "zona horaria no válida",
+	"invalid url":                        "url no válida",
+	"invalid user alias":                 "alias de usuario no válido",
+	"message not provided":               "mensaje no proporcionado",
+	"message too long":                   "mensaje demasiado largo",
+	"name not provided":                  "nombre no proporcionado",
+	"new password not provided":          "nueva contraseña no proporcionada",
+	"old password not provided":          "contraseña anterior no proporcionada",
+	"organization name not provided":     "nombre de organización no proporcionado",
+	"package is not seeking maintainers": "el paquete no busca mantenedores",
+	"password not provided":              "contraseña no proporcionada",
+	"repository name not provided":       "nombre de repositorio no proporcionado",
+	"url not provided":                   "url no proporcionada",
+	"user alias not provided":            "alias de usuario no proporcionado",
+	"version not provided":               "versión no proporcionada",
+}
@@ -0,0 +1,93 @@
+package i18n
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// DefaultLocale represents the locale used when no locale has been provided
+// or when the one provided is not supported.
+const DefaultLocale = "en"
+
+// IsSupported checks if the locale provided is supported.
+func IsSupported(locale string) bool {
+	if locale == DefaultLocale {
+		return true
+	}
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// T returns the translation of the message provided in the given locale,
+// formatted using the arguments provided (if any). Messages are identified by
+// their English text, which is returned when no translation is available.
+func T(locale, msg string, args ...interface{}) string {
+	if translation, ok := catalogs[locale][msg]; ok {
+		msg = translation
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// MatchLocale returns the best supported locale for the Accept-Language
+// header value provided, falling back to the default locale.
+func MatchLocale(acceptLanguage string) string {
+	for _, languageRange := range strings.Split(acceptLanguage, ",") {
+		tag := strings.TrimSpace(strings.Split(languageRange, ";")[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+		primary := strings.ToLower(strings.Split(tag, "-")[0])
+		if IsSupported(primary) {
+			return primary
+		}
+	}
+	return DefaultLocale
+}
+
+// Template represents an html template that can be rendered in any of the
+// supported locales. Templates can use the t function to translate messages:
+// the message (which may contain some html markup) is trusted, whereas the
+// arguments are escaped.
+type Template struct {
+	tmpls map[string]*template.Template
+}
+
+// MustParseTemplate parses the html template provided once for each of the
+// supported locales, panicking if an error occurs.
+func MustParseTemplate(text string) *Template {
+	t := &Template{
+		tmpls: make(map[string]*template.Template, len(catalogs)+1),
+	}
+	locales := []string{DefaultLocale}
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	for _, locale := range locales {
+		locale := locale
+		funcs := template.FuncMap{
+			"t": func(msg string, args ...interface{}) template.HTML {
+				for i, arg := range args {
+					args[i] = template.HTMLEscapeString(fmt.Sprint(arg))
+				}
+				return template.HTML(T(locale, msg, args...))
+			},
+		}
+		t.tmpls[locale] = template.Must(template.New("").Funcs(funcs).Parse(text))
+	}
+	return t
+}
+
+// Execute applies the template in the locale provided to the data object
+// given, writing the output to w.
+func (t *Template) Execute(w io.Writer, locale string, data interface{}) error {
+	tmpl, ok := t.tmpls[locale]
+	if !ok {
+		tmpl = t.tmpls[DefaultLocale]
+	}
+	return tmpl.Execute(w, data)
+}
@@ -0,0 +1,85 @@
+package i18n
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsSupported(t *testing.T) {
+	assert.True(t, IsSupported("en"))
+	assert.True(t, IsSupported("es"))
+	assert.False(t, IsSupported(""))
+	assert.False(t, IsSupported("xx"))
+}
+
+func TestT(t *testing.T) {
+	testCases := []struct {
+		locale   string
+		msg      string
+		args     []interface{}
+		expected string
+	}{
+		{"en", "Hi!", nil, "Hi!"},
+		{"es", "Hi!", nil, "¡Hola!"},
+		{"", "Hi!", nil, "Hi!"},
+		{"xx", "Hi!", nil, "Hi!"},
+		{"es", "untranslated message", nil, "untranslated message"},
+		{"en", "%s version %s released", []interface{}{"pkg1", "1.0.0"}, "pkg1 version 1.0.0 released"},
+		{"es", "%s version %s released", []interface{}{"pkg1", "1.0.0"}, "Publicada la versión 1.0.0 de pkg1"},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.locale+": "+tc.msg, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, T(tc.locale, tc.msg, tc.args...))
+		})
+	}
+}
+
+func TestMatchLocale(t *testing.T) {
+	testCases := []struct {
+		acceptLanguage string
+		expected       string
+	}{
+		{"", "en"},
+		{"*", "en"},
+		{"fr-FR,fr;q=0.9", "en"},
+		{"es", "es"},
+		{"ES-es", "es"},
+		{"fr-FR,fr;q=0.9,es;q=0.8,en;q=0.7", "es"},
+		{"en-US,en;q=0.9,es;q=0.8", "en"},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.acceptLanguage, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, MatchLocale(tc.acceptLanguage))
+		})
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	tmpl := MustParseTemplate(`<p>{{ t "Version <b>%s</b> has been released" .version }}</p>`)
+	data := map[string]string{"version": "<1.0.0>"}
+
+	testCases := []struct {
+		locale   string
+		expected string
+	}{
+		{"en", "<p>Version <b>&lt;1.0.0&gt;</b> has been released</p>"},
+		{"es", "<p>Se ha publicado la versión <b>&lt;1.0.0&gt;</b></p>"},
+		{"xx", "<p>Version <b>&lt;1.0.0&gt;</b> has been released</p>"},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.locale, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			require.NoError(t, tmpl.Execute(&buf, tc.locale, data))
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
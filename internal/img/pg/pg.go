@@ -19,13 +19,27 @@ type DB interface {
 // ImageStore is an image.Store implementation that uses PostgreSQL as the
 // underlying storage.
 type ImageStore struct {
-	db DB
+	db     DB
+	limits *img.Limits
 }
 
 // NewImageStore creates a new ImageStore instance.
-func NewImageStore(db DB) *ImageStore {
-	return &ImageStore{
-		db: db,
+func NewImageStore(db DB, opts ...func(s *ImageStore)) *ImageStore {
+	s := &ImageStore{
+		db:     db,
+		limits: img.DefaultLimits(),
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	return s
+}
+
+// WithLimits allows overriding the default limits enforced on the images
+// saved by an ImageStore instance, like their maximum size.
+func WithLimits(l *img.Limits) func(s *ImageStore) {
+	return func(s *ImageStore) {
+		s.limits = l
 	}
 }
 
@@ -49,6 +63,12 @@ func (s *ImageStore) SaveImage(ctx context.Context, data []byte) (string, error)
 	sum := sha256.Sum256(data)
 	originalHash := sum[:]
 
+	// Validate and sanitize image before storing anything
+	data, err := img.Sanitize(data, s.limits)
+	if err != nil {
+		return "", err
+	}
+
 	// If image is already registered we just return its id
 	imageID, err := s.getImageID(ctx, originalHash)
 	if err != nil {
@@ -58,8 +78,8 @@ func (s *ImageStore) SaveImage(ctx context.Context, data []byte) (string, error)
 		return imageID, nil
 	}
 
-	// If image format is svg register it as is in database, as this format
-	// doesn't require to store additional size specific versions
+	// If image format is svg register its sanitized version in database, as
+	// this format doesn't require to store additional size specific versions
 	if svg.Is(data) {
 		return s.registerImage(ctx, originalHash, "svg", data)
 	}
@@ -3,9 +3,13 @@ package pg
 import (
 	"context"
 	"crypto/sha256"
+	"errors"
+	"image"
 	"io/ioutil"
 	"testing"
 
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/img"
 	"github.com/artifacthub/hub/internal/tests"
 	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/assert"
@@ -19,6 +23,7 @@ func TestNewImageStore(t *testing.T) {
 
 	assert.IsType(t, &ImageStore{}, s)
 	assert.Equal(t, db, s.db)
+	assert.Equal(t, img.DefaultLimits(), s.limits)
 }
 
 func TestGetImage(t *testing.T) {
@@ -87,6 +92,41 @@ func TestSaveImage(t *testing.T) {
 		db.AssertExpectations(t)
 	})
 
+	t.Run("image exceeding limits is not registered", func(t *testing.T) {
+		db := &tests.DBMock{}
+		s := NewImageStore(db, WithLimits(&img.Limits{MaxSize: 10}))
+
+		imageID, err := s.SaveImage(ctx, pngImgData)
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Empty(t, imageID)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("unsupported image format", func(t *testing.T) {
+		db := &tests.DBMock{}
+		s := NewImageStore(db)
+
+		imageID, err := s.SaveImage(ctx, []byte("<html></html>"))
+		assert.True(t, errors.Is(err, image.ErrFormat))
+		assert.Empty(t, imageID)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("svg image is sanitized before being registered", func(t *testing.T) {
+		data := []byte(`<svg xmlns="http://www.w3.org/2000/svg" onload="alert(1)"><script>alert(2)</script></svg>`)
+		sum := sha256.Sum256(data)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery1, sum[:]).Return(nil, pgx.ErrNoRows)
+		db.On("QueryRow", ctx, dbQuery2, sum[:], "svg", []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`)).
+			Return("svgImgID", nil)
+		s := NewImageStore(db)
+
+		imageID, err := s.SaveImage(ctx, data)
+		require.NoError(t, err)
+		assert.Equal(t, "svgImgID", imageID)
+		db.AssertExpectations(t)
+	})
+
 	t.Run("try to register existing png image", func(t *testing.T) {
 		db := &tests.DBMock{}
 		db.On("QueryRow", ctx, dbQuery1, pngImgHash).Return("existingImageID", nil)
@@ -0,0 +1,235 @@
+package img
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"  // Register gif decoder
+	_ "image/jpeg" // Register jpeg decoder
+	_ "image/png"  // Register png decoder
+	"io"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/hub"
+	svg "github.com/h2non/go-is-svg"
+)
+
+// Limits represents the limits enforced on the images before storing them.
+// A zero value disables the corresponding limit.
+type Limits struct {
+	MaxSize      int
+	MaxDimension int
+}
+
+// DefaultLimits returns the limits used when no overrides are configured.
+func DefaultLimits() *Limits {
+	return &Limits{
+		MaxSize:      2 * 1024 * 1024,
+		MaxDimension: 4096,
+	}
+}
+
+// rasterSignatures represents the magic bytes of the raster image formats
+// supported.
+var rasterSignatures = [][]byte{
+	[]byte("\x89PNG\r\n\x1a\n"),
+	[]byte("\xff\xd8\xff"),
+	[]byte("GIF87a"),
+	[]byte("GIF89a"),
+}
+
+// svgForbiddenElements represents the svg elements that will be removed,
+// including all their content, when sanitizing svg images.
+var svgForbiddenElements = map[string]struct{}{
+	"embed":         {},
+	"foreignobject": {},
+	"handler":       {},
+	"iframe":        {},
+	"object":        {},
+	"script":        {},
+}
+
+// Sanitize checks that the image provided does not exceed the limits given
+// and is in one of the supported formats, returning the data that should be
+// stored. Raster images are only accepted when their magic bytes match a
+// supported format and they can be decoded, and are returned untouched. Svg
+// images are rebuilt without scripts, event handlers, foreign objects and
+// links to javascript urls. Images in unsupported formats are rejected with an
+// error that wraps image.ErrFormat.
+func Sanitize(data []byte, l *Limits) ([]byte, error) {
+	if l.MaxSize > 0 && len(data) > l.MaxSize {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, fmt.Sprintf("image too large (max %d bytes)", l.MaxSize))
+	}
+	if svg.Is(data) {
+		return sanitizeSVG(data)
+	}
+
+	var supported bool
+	for _, signature := range rasterSignatures {
+		if bytes.HasPrefix(data, signature) {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return nil, image.ErrFormat
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "error decoding image")
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid image dimensions")
+	}
+	if l.MaxDimension > 0 && (cfg.Width > l.MaxDimension || cfg.Height > l.MaxDimension) {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, fmt.Sprintf(
+			"image dimensions too large (max %dx%d)", l.MaxDimension, l.MaxDimension,
+		))
+	}
+	return data, nil
+}
+
+// sanitizeSVG rebuilds the svg image provided removing all the elements and
+// attributes that could be used to run scripts when the image is rendered.
+// Comments, doctypes (which may declare entities) and processing instructions
+// other than the xml declaration are dropped as well.
+func sanitizeSVG(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var (
+		openElements []string
+		skipFrom     = -1
+	)
+	for {
+		token, err := d.RawToken()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "error parsing svg image")
+		}
+
+		// RawToken does not verify that start and end elements match
+		switch t := token.(type) {
+		case xml.StartElement:
+			openElements = append(openElements, rawName(t.Name))
+		case xml.EndElement:
+			last := len(openElements) - 1
+			if last < 0 || openElements[last] != rawName(t.Name) {
+				return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "error parsing svg image")
+			}
+			openElements = openElements[:last]
+		}
+
+		// Skip forbidden elements, including all their content
+		if skipFrom >= 0 {
+			if len(openElements) <= skipFrom {
+				skipFrom = -1
+			}
+			continue
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if isForbiddenSVGElement(t) {
+				skipFrom = len(openElements) - 1
+				continue
+			}
+			buf.WriteString("<" + rawName(t.Name))
+			for _, attr := range t.Attr {
+				if isForbiddenSVGAttr(attr) {
+					continue
+				}
+				buf.WriteString(" " + rawName(attr.Name) + `="`)
+				_ = xml.EscapeText(&buf, []byte(attr.Value))
+				buf.WriteString(`"`)
+			}
+			buf.WriteString(">")
+		case xml.EndElement:
+			buf.WriteString("</" + rawName(t.Name) + ">")
+		case xml.CharData:
+			_ = xml.EscapeText(&buf, t)
+		case xml.ProcInst:
+			if t.Target == "xml" {
+				buf.WriteString("<?xml " + string(t.Inst) + "?>")
+			}
+		}
+	}
+	if len(openElements) > 0 {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "error parsing svg image")
+	}
+	if buf.Len() == 0 {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "empty svg image")
+	}
+	return buf.Bytes(), nil
+}
+
+// isForbiddenSVGElement checks if the element provided must be removed from
+// svg images. Animation elements are forbidden when they target links or
+// event handlers, as they could be used to set them to malicious values.
+func isForbiddenSVGElement(e xml.StartElement) bool {
+	name := strings.ToLower(e.Name.Local)
+	if _, ok := svgForbiddenElements[name]; ok {
+		return true
+	}
+	switch name {
+	case "animate", "set":
+		for _, attr := range e.Attr {
+			if strings.ToLower(attr.Name.Local) != "attributename" {
+				continue
+			}
+			target := strings.ToLower(attr.Value)
+			if i := strings.LastIndex(target, ":"); i >= 0 {
+				target = target[i+1:]
+			}
+			if target == "href" || strings.HasPrefix(target, "on") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isForbiddenSVGAttr checks if the attribute provided must be removed from
+// svg images. Event handlers are always removed, as well as links whose url
+// uses a scheme that allows running scripts or embedding documents.
+func isForbiddenSVGAttr(attr xml.Attr) bool {
+	name := strings.ToLower(attr.Name.Local)
+	if strings.HasPrefix(name, "on") {
+		return true
+	}
+	if name != "href" {
+		return false
+	}
+
+	// Browsers ignore whitespace and control characters in urls schemes
+	value := strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(attr.Value))
+	switch {
+	case strings.HasPrefix(value, "javascript:"), strings.HasPrefix(value, "vbscript:"):
+		return true
+	case strings.HasPrefix(value, "data:"):
+		for _, allowed := range []string{"data:image/png", "data:image/jpeg", "data:image/gif"} {
+			if strings.HasPrefix(value, allowed) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// rawName returns the name provided as it was found in the document, using
+// the namespace prefix (RawToken does not translate prefixes into namespaces).
+func rawName(n xml.Name) string {
+	if n.Space == "" {
+		return n.Local
+	}
+	return n.Space + ":" + n.Local
+}
@@ -0,0 +1,107 @@
+package img
+
+import (
+	"errors"
+	"image"
+	"io/ioutil"
+	"strconv"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitize(t *testing.T) {
+	validImgData, err := ioutil.ReadFile("testdata/valid.png")
+	require.NoError(t, err)
+	invalidImgData, err := ioutil.ReadFile("testdata/invalid.png")
+	require.NoError(t, err)
+
+	t.Run("image too large", func(t *testing.T) {
+		_, err := Sanitize(validImgData, &Limits{MaxSize: 10})
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "image too large")
+	})
+
+	t.Run("unsupported image format", func(t *testing.T) {
+		testCases := []string{
+			"<html><body>Not found</body></html>",
+			"BM\x00\x00",
+			"GIF",
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc, func(t *testing.T) {
+				_, err := Sanitize([]byte(tc), DefaultLimits())
+				assert.True(t, errors.Is(err, image.ErrFormat))
+			})
+		}
+	})
+
+	t.Run("raster image cannot be decoded", func(t *testing.T) {
+		_, err := Sanitize(append([]byte("\x89PNG\r\n\x1a\n"), invalidImgData...), DefaultLimits())
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("raster image dimensions too large", func(t *testing.T) {
+		_, err := Sanitize(validImgData, &Limits{MaxDimension: 1})
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "image dimensions too large")
+	})
+
+	t.Run("valid raster image returned untouched", func(t *testing.T) {
+		data, err := Sanitize(validImgData, DefaultLimits())
+		require.NoError(t, err)
+		assert.Equal(t, validImgData, data)
+	})
+
+	t.Run("invalid svg image", func(t *testing.T) {
+		_, err := Sanitize([]byte(`<svg xmlns="http://www.w3.org/2000/svg"><path d="M0 0"></svg>`), DefaultLimits())
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("svg image sanitized", func(t *testing.T) {
+		testCases := []struct {
+			input    string
+			expected string
+		}{
+			{
+				`<?xml version="1.0" encoding="UTF-8"?><svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 8 8"><path d="M0 0h8v8H0z" fill="#fff"/></svg>`,
+				`<?xml version="1.0" encoding="UTF-8"?><svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 8 8"><path d="M0 0h8v8H0z" fill="#fff"></path></svg>`,
+			},
+			{
+				`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script><g><script type="text/javascript"><![CDATA[alert(2)]]></script></g></svg>`,
+				`<svg xmlns="http://www.w3.org/2000/svg"><g></g></svg>`,
+			},
+			{
+				`<svg xmlns="http://www.w3.org/2000/svg" onload="alert(1)"><rect width="8" height="8" ONCLICK="alert(2)"/></svg>`,
+				`<svg xmlns="http://www.w3.org/2000/svg"><rect width="8" height="8"></rect></svg>`,
+			},
+			{
+				`<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink"><a xlink:href=" java&#x09;script:alert(1)"><text>a</text></a><a href="https://artifacthub.io"><text>b</text></a><use href="#c"/></svg>`,
+				`<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink"><a><text>a</text></a><a href="https://artifacthub.io"><text>b</text></a><use href="#c"></use></svg>`,
+			},
+			{
+				`<svg xmlns="http://www.w3.org/2000/svg"><image href="data:text/html;base64,PHNjcmlwdD4="/><image href="data:image/png;base64,iVBORw0KGgo="/></svg>`,
+				`<svg xmlns="http://www.w3.org/2000/svg"><image></image><image href="data:image/png;base64,iVBORw0KGgo="></image></svg>`,
+			},
+			{
+				`<svg xmlns="http://www.w3.org/2000/svg"><foreignObject><body xmlns="http://www.w3.org/1999/xhtml"><iframe src="https://evil.url"></iframe></body></foreignObject><a><set attributeName="href" to="javascript:alert(1)"/><animate attributeName="fill" to="red"/></a></svg>`,
+				`<svg xmlns="http://www.w3.org/2000/svg"><a><animate attributeName="fill" to="red"></animate></a></svg>`,
+			},
+			{
+				`<!DOCTYPE svg [<!ENTITY x "y">]><!-- comment --><?xml-stylesheet href="https://evil.url/style.css"?><svg xmlns="http://www.w3.org/2000/svg"><text>a &lt; b</text></svg>`,
+				`<svg xmlns="http://www.w3.org/2000/svg"><text>a &lt; b</text></svg>`,
+			},
+		}
+		for i, tc := range testCases {
+			tc := tc
+			t.Run(strconv.Itoa(i), func(t *testing.T) {
+				data, err := Sanitize([]byte(tc.input), DefaultLimits())
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, string(data))
+			})
+		}
+	})
+}
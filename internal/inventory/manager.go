@@ -0,0 +1,166 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/jackc/pgx/v4"
+	"github.com/satori/uuid"
+)
+
+// maxReleases is the maximum number of releases a cluster inventory can
+// contain. It matches the maximum number of releases that can be matched
+// against the packages available in a single request.
+const maxReleases = 500
+
+// Manager provides an API to manage cluster inventories.
+type Manager struct {
+	db hub.DB
+}
+
+// NewManager creates a new Manager instance.
+func NewManager(db hub.DB) *Manager {
+	return &Manager{
+		db: db,
+	}
+}
+
+// Add adds the provided cluster inventory to the database.
+func (m *Manager) Add(ctx context.Context, inv *hub.ClusterInventory) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if err := validateClusterInventory(inv); err != nil {
+		return err
+	}
+
+	// Add cluster inventory to the database
+	query := "select add_cluster_inventory($1::uuid, $2::jsonb)"
+	invJSON, _ := json.Marshal(inv)
+	_, err := m.db.Exec(ctx, query, userID, invJSON)
+	return err
+}
+
+// Delete deletes the provided cluster inventory from the database.
+func (m *Manager) Delete(ctx context.Context, clusterInventoryID string) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if _, err := uuid.FromString(clusterInventoryID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid cluster inventory id")
+	}
+
+	// Delete cluster inventory from database
+	query := "select delete_cluster_inventory($1::uuid, $2::uuid)"
+	_, err := m.db.Exec(ctx, query, userID, clusterInventoryID)
+	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+		return hub.ErrInsufficientPrivilege
+	}
+	return err
+}
+
+// GetOwnedByUserJSON returns the cluster inventories belonging to the
+// requesting user as a json array.
+func (m *Manager) GetOwnedByUserJSON(ctx context.Context) ([]byte, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Get cluster inventories from database
+	query := "select get_user_cluster_inventories($1::uuid)"
+	var dataJSON []byte
+	if err := m.db.QueryRow(ctx, query, userID).Scan(&dataJSON); err != nil {
+		return nil, err
+	}
+	return dataJSON, nil
+}
+
+// GetPendingReport returns a cluster inventory whose report is due if
+// available. Reports are due once the interval provided has passed since the
+// last one was generated, and only between the hours provided of the local
+// time of the inventory owner.
+func (m *Manager) GetPendingReport(
+	ctx context.Context,
+	tx pgx.Tx,
+	interval time.Duration,
+	fromHour, toHour int,
+) (*hub.ClusterInventory, error) {
+	query := "select get_pending_cluster_inventory_report($1::interval, $2::int, $3::int)"
+	var dataJSON []byte
+	if err := tx.QueryRow(ctx, query, interval, fromHour, toHour).Scan(&dataJSON); err != nil {
+		return nil, err
+	}
+	var inv *hub.ClusterInventory
+	if err := json.Unmarshal(dataJSON, &inv); err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+// Update updates the provided cluster inventory in the database.
+func (m *Manager) Update(ctx context.Context, inv *hub.ClusterInventory) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if _, err := uuid.FromString(inv.ClusterInventoryID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid cluster inventory id")
+	}
+	if err := validateClusterInventory(inv); err != nil {
+		return err
+	}
+
+	// Update cluster inventory in database
+	query := "select update_cluster_inventory($1::uuid, $2::jsonb)"
+	invJSON, _ := json.Marshal(inv)
+	_, err := m.db.Exec(ctx, query, userID, invJSON)
+	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+		return hub.ErrInsufficientPrivilege
+	}
+	return err
+}
+
+// UpdateLastReport records that the report of the provided cluster inventory
+// has just been generated.
+func (m *Manager) UpdateLastReport(ctx context.Context, tx pgx.Tx, clusterInventoryID string) error {
+	query := "select update_cluster_inventory_last_report($1::uuid)"
+	_, err := tx.Exec(ctx, query, clusterInventoryID)
+	return err
+}
+
+// validateClusterInventory checks if the cluster inventory provided is valid.
+func validateClusterInventory(inv *hub.ClusterInventory) error {
+	if inv.Name == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "name not provided")
+	}
+	if len(inv.Releases) > maxReleases {
+		return fmt.Errorf("%w: %s (max: %d)", hub.ErrInvalidInput, "too many releases", maxReleases)
+	}
+	for _, r := range inv.Releases {
+		if r == nil || r.Name == "" {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "release name not provided")
+		}
+		if _, err := semver.NewVersion(r.Version); err != nil {
+			return fmt.Errorf("%w: %s: %s", hub.ErrInvalidInput, "invalid release version", r.Version)
+		}
+		if !isAbsoluteURL(r.RepositoryURL) {
+			return fmt.Errorf("%w: %s: %s", hub.ErrInvalidInput, "invalid release repository url", r.RepositoryURL)
+		}
+	}
+	if inv.WebhookURL != "" && !isAbsoluteURL(inv.WebhookURL) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid webhook url")
+	}
+	return nil
+}
+
+// isAbsoluteURL checks if the provided string is a valid absolute url.
+func isAbsoluteURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return u.Scheme != "" && u.Host != ""
+}
@@ -0,0 +1,380 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const validUUID = "00000000-0000-0000-0000-000000000001"
+
+var validReleases = []*hub.InstalledRelease{
+	{Name: "pkg1", Version: "1.0.0", RepositoryURL: "https://repo1.com"},
+}
+
+func TestAdd(t *testing.T) {
+	dbQuery := "select add_cluster_inventory($1::uuid, $2::jsonb)"
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	inv := &hub.ClusterInventory{
+		Name:        "cluster1",
+		Releases:    validReleases,
+		EmailReport: true,
+	}
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_ = m.Add(context.Background(), inv)
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		tooManyReleases := make([]*hub.InstalledRelease, maxReleases+1)
+		for i := range tooManyReleases {
+			tooManyReleases[i] = validReleases[0]
+		}
+		testCases := []struct {
+			errMsg string
+			inv    *hub.ClusterInventory
+		}{
+			{
+				"name not provided",
+				&hub.ClusterInventory{},
+			},
+			{
+				"too many releases",
+				&hub.ClusterInventory{
+					Name:     "cluster1",
+					Releases: tooManyReleases,
+				},
+			},
+			{
+				"release name not provided",
+				&hub.ClusterInventory{
+					Name: "cluster1",
+					Releases: []*hub.InstalledRelease{
+						{Version: "1.0.0", RepositoryURL: "https://repo1.com"},
+					},
+				},
+			},
+			{
+				"invalid release version",
+				&hub.ClusterInventory{
+					Name: "cluster1",
+					Releases: []*hub.InstalledRelease{
+						{Name: "pkg1", Version: "invalid", RepositoryURL: "https://repo1.com"},
+					},
+				},
+			},
+			{
+				"invalid release repository url",
+				&hub.ClusterInventory{
+					Name: "cluster1",
+					Releases: []*hub.InstalledRelease{
+						{Name: "pkg1", Version: "1.0.0", RepositoryURL: "repo1.com"},
+					},
+				},
+			},
+			{
+				"invalid webhook url",
+				&hub.ClusterInventory{
+					Name:       "cluster1",
+					Releases:   validReleases,
+					WebhookURL: "webhook1.url",
+				},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil)
+				err := m.Add(ctx, tc.inv)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, "userID", mock.Anything).Return(tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		err := m.Add(ctx, inv)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("add cluster inventory succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, "userID", mock.Anything).Return(nil)
+		m := NewManager(db)
+
+		err := m.Add(ctx, inv)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	dbQuery := "select delete_cluster_inventory($1::uuid, $2::uuid)"
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_ = m.Delete(context.Background(), validUUID)
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		m := NewManager(nil)
+		err := m.Delete(ctx, "invalid")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "invalid cluster inventory id")
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDatabaseFailure,
+				tests.ErrFakeDatabaseFailure,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("Exec", ctx, dbQuery, "userID", validUUID).Return(tc.dbErr)
+				m := NewManager(db)
+
+				err := m.Delete(ctx, validUUID)
+				assert.Equal(t, tc.expectedError, err)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("delete cluster inventory succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, "userID", validUUID).Return(nil)
+		m := NewManager(db)
+
+		err := m.Delete(ctx, validUUID)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetOwnedByUserJSON(t *testing.T) {
+	dbQuery := "select get_user_cluster_inventories($1::uuid)"
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetOwnedByUserJSON(context.Background())
+		})
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "userID").Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetOwnedByUserJSON(ctx)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "userID").Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetOwnedByUserJSON(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetPendingReport(t *testing.T) {
+	dbQuery := "select get_pending_cluster_inventory_report($1::interval, $2::int, $3::int)"
+	ctx := context.Background()
+	interval := 24 * time.Hour
+
+	t.Run("database error", func(t *testing.T) {
+		tx := &tests.TXMock{}
+		tx.On("QueryRow", ctx, dbQuery, interval, 8, 18).Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(nil)
+
+		inv, err := m.GetPendingReport(ctx, tx, interval, 8, 18)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.Nil(t, inv)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		tx := &tests.TXMock{}
+		tx.On("QueryRow", ctx, dbQuery, interval, 8, 18).Return([]byte(`
+		{
+			"cluster_inventory_id": "00000000-0000-0000-0000-000000000001",
+			"name": "cluster1",
+			"releases": [
+				{"name": "pkg1", "version": "1.0.0", "repository_url": "https://repo1.com"}
+			],
+			"email_report": true,
+			"webhook_url": null,
+			"user": {
+				"email": "user1@email.com",
+				"locale": "es"
+			}
+		}
+		`), nil)
+		m := NewManager(nil)
+
+		inv, err := m.GetPendingReport(ctx, tx, interval, 8, 18)
+		require.NoError(t, err)
+		assert.Equal(t, &hub.ClusterInventory{
+			ClusterInventoryID: validUUID,
+			Name:               "cluster1",
+			Releases:           validReleases,
+			EmailReport:        true,
+			User: &hub.User{
+				Email:  "user1@email.com",
+				Locale: "es",
+			},
+		}, inv)
+		tx.AssertExpectations(t)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	dbQuery := "select update_cluster_inventory($1::uuid, $2::jsonb)"
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	inv := &hub.ClusterInventory{
+		ClusterInventoryID: validUUID,
+		Name:               "cluster1",
+		Releases:           validReleases,
+		WebhookURL:         "https://webhook1.url",
+	}
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_ = m.Update(context.Background(), inv)
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg string
+			inv    *hub.ClusterInventory
+		}{
+			{
+				"invalid cluster inventory id",
+				&hub.ClusterInventory{
+					ClusterInventoryID: "invalid",
+					Name:               "cluster1",
+				},
+			},
+			{
+				"name not provided",
+				&hub.ClusterInventory{
+					ClusterInventoryID: validUUID,
+				},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil)
+				err := m.Update(ctx, tc.inv)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDatabaseFailure,
+				tests.ErrFakeDatabaseFailure,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("Exec", ctx, dbQuery, "userID", mock.Anything).Return(tc.dbErr)
+				m := NewManager(db)
+
+				err := m.Update(ctx, inv)
+				assert.Equal(t, tc.expectedError, err)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("update cluster inventory succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, "userID", mock.Anything).Return(nil)
+		m := NewManager(db)
+
+		err := m.Update(ctx, inv)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestUpdateLastReport(t *testing.T) {
+	dbQuery := "select update_cluster_inventory_last_report($1::uuid)"
+	ctx := context.Background()
+
+	t.Run("database error", func(t *testing.T) {
+		tx := &tests.TXMock{}
+		tx.On("Exec", ctx, dbQuery, validUUID).Return(tests.ErrFakeDatabaseFailure)
+		m := NewManager(nil)
+
+		err := m.UpdateLastReport(ctx, tx, validUUID)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("last report updated successfully", func(t *testing.T) {
+		tx := &tests.TXMock{}
+		tx.On("Exec", ctx, dbQuery, validUUID).Return(nil)
+		m := NewManager(nil)
+
+		err := m.UpdateLastReport(ctx, tx, validUUID)
+		assert.NoError(t, err)
+		tx.AssertExpectations(t)
+	})
+}
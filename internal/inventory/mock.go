@@ -0,0 +1,59 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/mock"
+)
+
+// ManagerMock is a mock implementation of the ClusterInventoryManager
+// interface.
+type ManagerMock struct {
+	mock.Mock
+}
+
+// Add implements the ClusterInventoryManager interface.
+func (m *ManagerMock) Add(ctx context.Context, inv *hub.ClusterInventory) error {
+	args := m.Called(ctx, inv)
+	return args.Error(0)
+}
+
+// Delete implements the ClusterInventoryManager interface.
+func (m *ManagerMock) Delete(ctx context.Context, clusterInventoryID string) error {
+	args := m.Called(ctx, clusterInventoryID)
+	return args.Error(0)
+}
+
+// GetOwnedByUserJSON implements the ClusterInventoryManager interface.
+func (m *ManagerMock) GetOwnedByUserJSON(ctx context.Context) ([]byte, error) {
+	args := m.Called(ctx)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+// GetPendingReport implements the ClusterInventoryManager interface.
+func (m *ManagerMock) GetPendingReport(
+	ctx context.Context,
+	tx pgx.Tx,
+	interval time.Duration,
+	fromHour, toHour int,
+) (*hub.ClusterInventory, error) {
+	args := m.Called(ctx, tx, interval, fromHour, toHour)
+	data, _ := args.Get(0).(*hub.ClusterInventory)
+	return data, args.Error(1)
+}
+
+// Update implements the ClusterInventoryManager interface.
+func (m *ManagerMock) Update(ctx context.Context, inv *hub.ClusterInventory) error {
+	args := m.Called(ctx, inv)
+	return args.Error(0)
+}
+
+// UpdateLastReport implements the ClusterInventoryManager interface.
+func (m *ManagerMock) UpdateLastReport(ctx context.Context, tx pgx.Tx, clusterInventoryID string) error {
+	args := m.Called(ctx, tx, clusterInventoryID)
+	return args.Error(0)
+}
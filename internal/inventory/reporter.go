@@ -0,0 +1,288 @@
+package inventory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/artifacthub/hub/internal/email"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/i18n"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/jackc/pgx/v4"
+	"github.com/rs/zerolog/log"
+	"github.com/satori/uuid"
+	"github.com/spf13/viper"
+)
+
+const (
+	defaultReportInterval = 7 * 24 * time.Hour
+	pauseOnEmptyQueue     = 5 * time.Minute
+	pauseOnError          = 1 * time.Minute
+	reportPayloadType     = "application/cloudevents+json"
+
+	// reportFromHour and reportToHour represent the hours of the day, in the
+	// inventories owners local time (UTC when they haven't set a timezone),
+	// between which reports are delivered.
+	reportFromHour = 8
+	reportToHour   = 18
+)
+
+// HTTPClient defines the methods an HTTPClient implementation must provide.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Reporter is in charge of generating periodically the reports of the cluster
+// inventories, listing the releases that are outdated, deprecated or that
+// have reached their end of life, and delivering them to the inventories
+// owners during working hours of their timezone.
+type Reporter struct {
+	db       hub.DB
+	cim      hub.ClusterInventoryManager
+	pm       hub.PackageManager
+	es       hub.EmailSender
+	hc       HTTPClient
+	baseURL  string
+	interval time.Duration
+}
+
+// NewReporter creates a new Reporter instance.
+func NewReporter(
+	cfg *viper.Viper,
+	db hub.DB,
+	cim hub.ClusterInventoryManager,
+	pm hub.PackageManager,
+	es hub.EmailSender,
+	hc HTTPClient,
+) *Reporter {
+	interval := defaultReportInterval
+	if cfg != nil && cfg.GetDuration("server.clusterInventoriesReportInterval") > 0 {
+		interval = cfg.GetDuration("server.clusterInventoriesReportInterval")
+	}
+	var baseURL string
+	if cfg != nil {
+		baseURL = cfg.GetString("server.baseURL")
+	}
+	return &Reporter{
+		db:       db,
+		cim:      cim,
+		pm:       pm,
+		es:       es,
+		hc:       hc,
+		baseURL:  baseURL,
+		interval: interval,
+	}
+}
+
+// Run processes the pending reports until it's asked to stop via the context
+// provided.
+func (r *Reporter) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for {
+		err := r.processReport(ctx)
+		switch {
+		case err == nil:
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+		case errors.Is(err, pgx.ErrNoRows):
+			select {
+			case <-time.After(pauseOnEmptyQueue):
+			case <-ctx.Done():
+				return
+			}
+		default:
+			select {
+			case <-time.After(pauseOnError):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// processReport gets a cluster inventory whose report is due, generating and
+// delivering it when any of its releases needs attention. Delivery errors are
+// logged, but they don't prevent the report from being considered done.
+func (r *Reporter) processReport(ctx context.Context) error {
+	return util.DBTransact(ctx, r.db, func(tx pgx.Tx) error {
+		// Get pending report to process
+		inv, err := r.cim.GetPendingReport(ctx, tx, r.interval, reportFromHour, reportToHour)
+		if err != nil {
+			if !errors.Is(err, pgx.ErrNoRows) {
+				log.Error().Err(err).Msg("error getting pending cluster inventory report")
+			}
+			return err
+		}
+
+		// Generate and deliver report
+		releases, err := r.getReportReleases(ctx, inv)
+		if err != nil {
+			log.Error().Err(err).Str("id", inv.ClusterInventoryID).Msg("error matching cluster inventory releases")
+			return err
+		}
+		if len(releases) > 0 {
+			if inv.EmailReport && inv.User != nil && r.es != nil {
+				if err := r.deliverEmailReport(inv, releases); err != nil {
+					log.Error().Err(err).Str("id", inv.ClusterInventoryID).Msg("error delivering email report")
+				}
+			}
+			if inv.WebhookURL != "" {
+				if err := r.deliverWebhookReport(ctx, inv, releases); err != nil {
+					log.Error().Err(err).Str("id", inv.ClusterInventoryID).Msg("error delivering webhook report")
+				}
+			}
+		}
+
+		// Update last report
+		err = r.cim.UpdateLastReport(ctx, tx, inv.ClusterInventoryID)
+		if err != nil {
+			log.Error().Err(err).Str("id", inv.ClusterInventoryID).Msg("error updating last report")
+		}
+		return err
+	})
+}
+
+// getReportReleases returns the releases of the cluster inventory provided
+// that must be included in the report: the ones that have an update available,
+// that have been deprecated or that have reached their end of life. Releases
+// are matched on behalf of the inventory owner, so that packages in private
+// repositories they have access to are considered.
+func (r *Reporter) getReportReleases(
+	ctx context.Context,
+	inv *hub.ClusterInventory,
+) ([]*hub.InstalledReleaseStatus, error) {
+	if len(inv.Releases) == 0 {
+		return nil, nil
+	}
+	if inv.User != nil && inv.User.UserID != "" {
+		ctx = context.WithValue(ctx, hub.UserIDKey, inv.User.UserID)
+	}
+	dataJSON, err := r.pm.MatchInstalledJSON(ctx, inv.Releases)
+	if err != nil {
+		return nil, err
+	}
+	var matches []*hub.InstalledReleaseStatus
+	if err := json.Unmarshal(dataJSON, &matches); err != nil {
+		return nil, err
+	}
+	var releases []*hub.InstalledReleaseStatus
+	for _, m := range matches {
+		if m.Package != nil && (m.UpdateAvailable || m.Deprecated || m.EOL) {
+			releases = append(releases, m)
+		}
+	}
+	return releases, nil
+}
+
+// reportRelease represents a release included in a report.
+type reportRelease struct {
+	Name            string `json:"name"`
+	Version         string `json:"version"`
+	LatestVersion   string `json:"latest_version"`
+	UpdateAvailable bool   `json:"update_available"`
+	Deprecated      bool   `json:"deprecated"`
+	EOL             bool   `json:"eol"`
+	URL             string `json:"url"`
+}
+
+// reportData represents the data of a report, used to render both the email
+// and the webhook payload.
+type reportData struct {
+	BaseURL  string           `json:"-"`
+	Name     string           `json:"cluster_inventory"`
+	Releases []*reportRelease `json:"releases"`
+}
+
+// prepareReportData prepares the data of the report of the cluster inventory
+// provided.
+func (r *Reporter) prepareReportData(
+	inv *hub.ClusterInventory,
+	releases []*hub.InstalledReleaseStatus,
+) *reportData {
+	data := &reportData{
+		BaseURL:  r.baseURL,
+		Name:     inv.Name,
+		Releases: make([]*reportRelease, 0, len(releases)),
+	}
+	for _, m := range releases {
+		data.Releases = append(data.Releases, &reportRelease{
+			Name:            m.Name,
+			Version:         m.Version,
+			LatestVersion:   m.Package.LatestVersion,
+			UpdateAvailable: m.UpdateAvailable,
+			Deprecated:      m.Deprecated,
+			EOL:             m.EOL,
+			URL: fmt.Sprintf("%s/packages/%s/%s/%s",
+				r.baseURL,
+				hub.GetKindName(m.Package.Repository.Kind),
+				m.Package.Repository.Name,
+				m.Package.NormalizedName,
+			),
+		})
+	}
+	return data
+}
+
+// deliverEmailReport delivers the report provided via email to the owner of
+// the cluster inventory.
+func (r *Reporter) deliverEmailReport(inv *hub.ClusterInventory, releases []*hub.InstalledReleaseStatus) error {
+	var body bytes.Buffer
+	if err := reportEmailTmpl.Execute(&body, inv.User.Locale, r.prepareReportData(inv, releases)); err != nil {
+		return err
+	}
+	return r.es.SendEmail(&email.Data{
+		To:      inv.User.Email,
+		Subject: i18n.T(inv.User.Locale, "%s cluster report", inv.Name),
+		Body:    body.Bytes(),
+	})
+}
+
+// reportPayload represents the payload posted to the webhook url of a cluster
+// inventory, following the CloudEvents specification.
+type reportPayload struct {
+	SpecVersion     string      `json:"specversion"`
+	ID              string      `json:"id"`
+	Source          string      `json:"source"`
+	Type            string      `json:"type"`
+	DataContentType string      `json:"datacontenttype"`
+	Data            *reportData `json:"data"`
+}
+
+// deliverWebhookReport delivers the report provided to the webhook url of the
+// cluster inventory.
+func (r *Reporter) deliverWebhookReport(
+	ctx context.Context,
+	inv *hub.ClusterInventory,
+	releases []*hub.InstalledReleaseStatus,
+) error {
+	payload, _ := json.Marshal(&reportPayload{
+		SpecVersion:     "1.0",
+		ID:              uuid.NewV4().String(),
+		Source:          "https://artifacthub.io/cloudevents",
+		Type:            "io.artifacthub.cluster-inventory.report",
+		DataContentType: "application/json",
+		Data:            r.prepareReportData(inv, releases),
+	})
+	req, _ := http.NewRequest("POST", inv.WebhookURL, bytes.NewReader(payload))
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", reportPayloadType)
+	resp, err := r.hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/email"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/pkg"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var errFake = errors.New("fake error for tests")
+
+func TestReporter(t *testing.T) {
+	inv := &hub.ClusterInventory{
+		ClusterInventoryID: validUUID,
+		Name:               "cluster1",
+		Releases:           validReleases,
+		EmailReport:        true,
+		WebhookURL:         "http://webhook1.url",
+		User: &hub.User{
+			UserID: "00000000-0000-0000-0000-000000000001",
+			Email:  "user1@email.com",
+		},
+	}
+	userCtx := mock.MatchedBy(func(ctx context.Context) bool {
+		return ctx.Value(hub.UserIDKey) == inv.User.UserID
+	})
+	outdatedReleasesJSON := []byte(`
+	[
+		{
+			"name": "pkg1",
+			"version": "1.0.0",
+			"repository_url": "https://repo1.com",
+			"package": {
+				"package_id": "00000000-0000-0000-0000-000000000001",
+				"name": "pkg1",
+				"normalized_name": "pkg1",
+				"latest_version": "2.0.0",
+				"repository": {
+					"kind": 0,
+					"name": "repo1"
+				}
+			},
+			"version_registered": true,
+			"update_available": true,
+			"deprecated": false,
+			"eol": false
+		}
+	]
+	`)
+	upToDateReleasesJSON := []byte(`
+	[
+		{
+			"name": "pkg1",
+			"version": "1.0.0",
+			"repository_url": "https://repo1.com",
+			"package": {
+				"package_id": "00000000-0000-0000-0000-000000000001",
+				"name": "pkg1",
+				"normalized_name": "pkg1",
+				"latest_version": "1.0.0",
+				"repository": {
+					"kind": 0,
+					"name": "repo1"
+				}
+			},
+			"version_registered": true,
+			"update_available": false,
+			"deprecated": false,
+			"eol": false
+		}
+	]
+	`)
+
+	t.Run("no pending reports", func(t *testing.T) {
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.cim.On("GetPendingReport", sw.ctx, sw.tx, defaultReportInterval, reportFromHour, reportToHour).Return(nil, pgx.ErrNoRows)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+
+		r := NewReporter(nil, sw.db, sw.cim, sw.pm, sw.es, sw.hc)
+		go r.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("error matching cluster inventory releases", func(t *testing.T) {
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.cim.On("GetPendingReport", sw.ctx, sw.tx, defaultReportInterval, reportFromHour, reportToHour).Return(inv, nil)
+		sw.pm.On("MatchInstalledJSON", userCtx, inv.Releases).Return(nil, errFake)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+
+		r := NewReporter(nil, sw.db, sw.cim, sw.pm, sw.es, sw.hc)
+		go r.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("no releases need attention, report not delivered", func(t *testing.T) {
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.cim.On("GetPendingReport", sw.ctx, sw.tx, defaultReportInterval, reportFromHour, reportToHour).Return(inv, nil)
+		sw.pm.On("MatchInstalledJSON", userCtx, inv.Releases).Return(upToDateReleasesJSON, nil)
+		sw.cim.On("UpdateLastReport", sw.ctx, sw.tx, inv.ClusterInventoryID).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		r := NewReporter(nil, sw.db, sw.cim, sw.pm, sw.es, sw.hc)
+		go r.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("error delivering report, last report updated anyway", func(t *testing.T) {
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.cim.On("GetPendingReport", sw.ctx, sw.tx, defaultReportInterval, reportFromHour, reportToHour).Return(inv, nil)
+		sw.pm.On("MatchInstalledJSON", userCtx, inv.Releases).Return(outdatedReleasesJSON, nil)
+		sw.es.On("SendEmail", mock.Anything).Return(errFake)
+		sw.hc.On("Do", mock.Anything).Return(nil, errFake)
+		sw.cim.On("UpdateLastReport", sw.ctx, sw.tx, inv.ClusterInventoryID).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		r := NewReporter(nil, sw.db, sw.cim, sw.pm, sw.es, sw.hc)
+		go r.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("report delivered successfully", func(t *testing.T) {
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.cim.On("GetPendingReport", sw.ctx, sw.tx, defaultReportInterval, reportFromHour, reportToHour).Return(inv, nil)
+		sw.pm.On("MatchInstalledJSON", userCtx, inv.Releases).Return(outdatedReleasesJSON, nil)
+		sw.es.On("SendEmail", mock.MatchedBy(func(d *email.Data) bool {
+			return d.To == "user1@email.com" &&
+				d.Subject == "cluster1 cluster report" &&
+				strings.Contains(string(d.Body), "/packages/helm/repo1/pkg1")
+		})).Return(nil)
+		sw.hc.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+			return req.Method == "POST" &&
+				req.URL.String() == inv.WebhookURL &&
+				req.Header.Get("Content-Type") == reportPayloadType
+		})).Return(&http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+			StatusCode: http.StatusOK,
+		}, nil)
+		sw.cim.On("UpdateLastReport", sw.ctx, sw.tx, inv.ClusterInventoryID).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		r := NewReporter(nil, sw.db, sw.cim, sw.pm, sw.es, sw.hc)
+		go r.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+}
+
+type servicesWrapper struct {
+	ctx          context.Context
+	stopReporter context.CancelFunc
+	wg           *sync.WaitGroup
+	db           *tests.DBMock
+	tx           *tests.TXMock
+	cim          *ManagerMock
+	pm           *pkg.ManagerMock
+	es           *email.SenderMock
+	hc           *httpClientMock
+}
+
+func newServicesWrapper() *servicesWrapper {
+	// Context and wait group used for Reporter.Run()
+	ctx, stopReporter := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	return &servicesWrapper{
+		ctx:          ctx,
+		stopReporter: stopReporter,
+		wg:           &wg,
+		db:           &tests.DBMock{},
+		tx:           &tests.TXMock{},
+		cim:          &ManagerMock{},
+		pm:           &pkg.ManagerMock{},
+		es:           &email.SenderMock{},
+		hc:           &httpClientMock{},
+	}
+}
+
+func (sw *servicesWrapper) assertExpectations(t *testing.T) {
+	sw.stopReporter()
+	assert.Eventually(t, func() bool {
+		sw.wg.Wait()
+		return true
+	}, 2*time.Second, 100*time.Millisecond)
+
+	sw.db.AssertExpectations(t)
+	sw.tx.AssertExpectations(t)
+	sw.cim.AssertExpectations(t)
+	sw.pm.AssertExpectations(t)
+	sw.es.AssertExpectations(t)
+	sw.hc.AssertExpectations(t)
+}
+
+type httpClientMock struct {
+	mock.Mock
+}
+
+func (m *httpClientMock) Do(req *http.Request) (*http.Response, error) {
+	args := m.Called(req)
+	resp, _ := args.Get(0).(*http.Response)
+	return resp, args.Error(1)
+}
@@ -0,0 +1,170 @@
+package inventory
+
+import "github.com/artifacthub/hub/internal/i18n"
+
+var reportEmailTmpl = i18n.MustParseTemplate(`
+<!doctype html>
+<html>
+  <head>
+    <meta name="viewport" content="width=device-width">
+    <meta http-equiv="Content-Type" content="text/html; charset=UTF-8">
+    <title>{{ t "%s cluster report" .Name }}</title>
+    <style>
+    @media only screen and (max-width: 620px) {
+      table[class=body] h1 {
+        font-size: 28px !important;
+        margin-bottom: 10px !important;
+      }
+      table[class=body] p,
+            table[class=body] ul,
+            table[class=body] ol,
+            table[class=body] td,
+            table[class=body] span,
+            table[class=body] a {
+        font-size: 16px !important;
+      }
+      table[class=body] .wrapper,
+      table[class=body] .article {
+        padding: 10px !important;
+      }
+      table[class=body] .content {
+        padding: 0 !important;
+      }
+      table[class=body] .container {
+        padding: 0 !important;
+        width: 100% !important;
+      }
+      table[class=body] .main {
+        border-left-width: 0 !important;
+        border-radius: 0 !important;
+        border-right-width: 0 !important;
+      }
+      table[class=body] .btn table {
+        width: 100% !important;
+      }
+      table[class=body] .btn a {
+        width: 100% !important;
+      }
+      table[class=body] .img-responsive {
+        height: auto !important;
+        max-width: 100% !important;
+        width: auto !important;
+      }
+    }
+
+    a[x-apple-data-detectors] {
+      color: inherit !important;
+      text-decoration: none !important;
+      font-size: inherit !important;
+      font-family: inherit !important;
+      font-weight: inherit !important;
+      line-height: inherit !important;
+    }
+
+    @media all {
+      .ExternalClass {
+        width: 100%;
+      }
+      .ExternalClass,
+            .ExternalClass p,
+            .ExternalClass span,
+            .ExternalClass font,
+            .ExternalClass td,
+            .ExternalClass div {
+        line-height: 100%;
+      }
+      .apple-link a {
+        color: inherit !important;
+        font-family: inherit !important;
+        font-size: inherit !important;
+        font-weight: inherit !important;
+        line-height: inherit !important;
+        text-decoration: none !important;
+      }
+      #MessageViewBody a {
+        color: inherit;
+        text-decoration: none;
+        font-size: inherit;
+        font-family: inherit;
+        font-weight: inherit;
+        line-height: inherit;
+      }
+    }
+    </style>
+  </head>
+  <body class="" style="background-color: #f4f4f4; font-family: sans-serif; -webkit-font-smoothing: antialiased; font-size: 14px; line-height: 1.4; margin: 0; padding: 0; -ms-text-size-adjust: 100%; -webkit-text-size-adjust: 100%;">
+    <table border="0" cellpadding="0" cellspacing="0" class="body" style="border-collapse: separate; mso-table-lspace: 0pt; mso-table-rspace: 0pt; width: 100%; background-color: #f4f4f4;">
+      <tr>
+        <td style="font-family: sans-serif; font-size: 14px; vertical-align: top;">&nbsp;</td>
+        <td class="container" style="font-family: sans-serif; font-size: 14px; vertical-align: top; display: block; Margin: 0 auto; max-width: 580px; padding: 10px; width: 580px;">
+          <div class="content" style="box-sizing: border-box; display: block; Margin: 0 auto; max-width: 580px; padding: 10px;">
+
+            <!-- START CENTERED WHITE CONTAINER -->
+            <span class="preheader" style="color: transparent; display: none; height: 0; max-height: 0; max-width: 0; opacity: 0; overflow: hidden; mso-hide: all; visibility: hidden; width: 0;">{{ t "%s cluster report" .Name }}</span>
+            <table class="main" style="border-collapse: separate; mso-table-lspace: 0pt; mso-table-rspace: 0pt; width: 100%; background: #ffffff; border-radius: 3px; border-top: 7px solid #659DBD;">
+
+              <!-- START MAIN CONTENT AREA -->
+              <tr>
+                <td class="wrapper" style="font-family: sans-serif; font-size: 14px; vertical-align: top; box-sizing: border-box; padding: 20px;">
+                  <table border="0" cellpadding="0" cellspacing="0" style="border-collapse: separate; mso-table-lspace: 0pt; mso-table-rspace: 0pt; width: 100%;">
+                    <tr>
+                      <td style="font-family: sans-serif; font-size: 14px; vertical-align: top; text-align: center;">
+                        <h2 style="color: #39596c; font-family: sans-serif; margin: 0; Margin-top: 15px; Margin-bottom: 15px;">{{ .Name }}</h2>
+
+                        <p style="font-family: sans-serif; font-size: 14px; font-weight: normal; margin: 0; Margin-bottom: 30px;">{{ t "Some of the releases installed in this cluster need your attention" }}</p>
+
+                        <table border="0" cellpadding="0" cellspacing="0" style="border-collapse: collapse; mso-table-lspace: 0pt; mso-table-rspace: 0pt; width: 100%; box-sizing: border-box; Margin-bottom: 30px;">
+                          <thead>
+                            <tr>
+                              <th style="font-family: sans-serif; font-size: 12px; text-align: left; color: #1c2c35; border-bottom: 1px solid #dddddd; padding: 8px;">{{ t "Release" }}</th>
+                              <th style="font-family: sans-serif; font-size: 12px; text-align: left; color: #1c2c35; border-bottom: 1px solid #dddddd; padding: 8px;">{{ t "Installed" }}</th>
+                              <th style="font-family: sans-serif; font-size: 12px; text-align: left; color: #1c2c35; border-bottom: 1px solid #dddddd; padding: 8px;">{{ t "Latest" }}</th>
+                              <th style="font-family: sans-serif; font-size: 12px; text-align: left; color: #1c2c35; border-bottom: 1px solid #dddddd; padding: 8px;">{{ t "Status" }}</th>
+                            </tr>
+                          </thead>
+                          <tbody>
+                            {{ range .Releases }}
+                            <tr>
+                              <td style="font-family: sans-serif; font-size: 12px; text-align: left; border-bottom: 1px solid #dddddd; padding: 8px;"><a href="{{ .URL }}" target="_blank" style="color: #39596C;">{{ .Name }}</a></td>
+                              <td style="font-family: sans-serif; font-size: 12px; text-align: left; border-bottom: 1px solid #dddddd; padding: 8px;">{{ .Version }}</td>
+                              <td style="font-family: sans-serif; font-size: 12px; text-align: left; border-bottom: 1px solid #dddddd; padding: 8px;">{{ .LatestVersion }}</td>
+                              <td style="font-family: sans-serif; font-size: 12px; text-align: left; border-bottom: 1px solid #dddddd; padding: 8px;">{{ if .UpdateAvailable }}{{ t "Update available" }} {{ end }}{{ if .Deprecated }}{{ t "Deprecated" }} {{ end }}{{ if .EOL }}{{ t "End of life" }}{{ end }}</td>
+                            </tr>
+                            {{ end }}
+                          </tbody>
+                        </table>
+                      </td>
+                    </tr>
+                  </table>
+                </td>
+              </tr>
+
+            <!-- END MAIN CONTENT AREA -->
+            </table>
+
+            <!-- START FOOTER -->
+            <div class="footer" style="clear: both; Margin-top: 10px; text-align: center; width: 100%;">
+              <table border="0" cellpadding="0" cellspacing="0" style="border-collapse: separate; mso-table-lspace: 0pt; mso-table-rspace: 0pt; width: 100%;">
+                <tr>
+                  <td class="content-block powered-by" style="font-family: sans-serif; vertical-align: top; padding-bottom: 10px; padding-top: 10px; font-size: 10px; color: #545454; text-align: center;">
+                    <p style="color: #545454; font-size: 10px; text-align: center; text-decoration: none;">{{ t "Don't want to receive the reports of this cluster anymore? You can disable them" }} <a href="{{ .BaseURL }}/control-panel/settings/cluster-inventories" target="_blank" style="text-decoration: underline; color: #545454;">{{ t "here" }}</a>.</p>
+                  </td>
+                </tr>
+                <tr>
+                  <td class="content-block powered-by" style="font-family: sans-serif; vertical-align: top; padding-bottom: 10px; padding-top: 10px; font-size: 12px; color: #39596C; text-align: center;">
+                    <a href="{{ .BaseURL }}" style="color: #39596C; font-size: 12px; text-align: center; text-decoration: none;">© Artifact Hub</a>
+                  </td>
+                </tr>
+              </table>
+            </div>
+            <!-- END FOOTER -->
+
+          <!-- END CENTERED WHITE CONTAINER -->
+          </div>
+        </td>
+        <td style="font-family: sans-serif; font-size: 14px; vertical-align: top;">&nbsp;</td>
+      </tr>
+    </table>
+  </body>
+</html>
+`)
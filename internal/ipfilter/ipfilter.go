@@ -0,0 +1,157 @@
+package ipfilter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// errIPNotAllowed indicates that requests from the client IP are not allowed.
+var errIPNotAllowed = errors.New("access from your IP address is not allowed")
+
+// xForwardedFor represents the header where the proxies add the address of
+// the clients they forward the requests from.
+var xForwardedFor = http.CanonicalHeaderKey("X-Forwarded-For")
+
+type peerAddrKey struct{}
+
+// PeerAddrKey represents the key used for the address of the peer connected to
+// the server inside a request context. Middlewares that replace the request
+// remote address (like RealIP) must keep the original one under this key.
+var PeerAddrKey = peerAddrKey{}
+
+// Filter restricts the access to some routes based on the IP address of the
+// client making the request.
+type Filter struct {
+	allow          []*net.IPNet
+	deny           []*net.IPNet
+	trustedProxies int
+}
+
+// New creates a new Filter instance from the allow and deny lists
+// provided. Each entry can be a CIDR or a single IP address. Requests from an
+// IP in the deny list are always rejected. When the allow list is not empty,
+// only requests from an IP it contains are accepted. A nil filter is returned
+// when both lists are empty.
+//
+// The number of trusted proxies indicates how many reverse proxies the
+// requests go through before reaching the server. When it's zero, the address
+// of the peer connected to the server is used as the client address.
+func New(allow, deny []string, trustedProxies int) (*Filter, error) {
+	if len(allow) == 0 && len(deny) == 0 {
+		return nil, nil
+	}
+	if trustedProxies < 0 {
+		return nil, fmt.Errorf("invalid number of trusted proxies: %d", trustedProxies)
+	}
+	if trustedProxies == 0 {
+		log.Warn().Msg("ip filter enabled without trusted proxies, the address of the peer connected will be used")
+	}
+	f := &Filter{
+		trustedProxies: trustedProxies,
+	}
+	var err error
+	if f.allow, err = parseIPNets(allow); err != nil {
+		return nil, err
+	}
+	if f.deny, err = parseIPNets(deny); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Handler is an http middleware that rejects the requests from clients whose
+// IP address is not allowed by the filter.
+func (f *Filter) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !f.allowed(f.clientIP(r)) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"message": errIPNotAllowed.Error(),
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP returns the IP address of the client that sent the request
+// provided. When there are no trusted proxies in front of the server, the
+// address of the peer connected is used. Otherwise the client address is the
+// one added to the X-Forwarded-For header by the outermost trusted proxy, as
+// the entries on its left are set by the client and can't be trusted. The
+// header entries are counted from the right for this reason.
+func (f *Filter) clientIP(r *http.Request) net.IP {
+	if f.trustedProxies == 0 {
+		peerAddr, ok := r.Context().Value(PeerAddrKey).(string)
+		if !ok {
+			peerAddr = r.RemoteAddr
+		}
+		host, _, err := net.SplitHostPort(peerAddr)
+		if err != nil {
+			host = strings.TrimSuffix(peerAddr, ":")
+		}
+		return net.ParseIP(host)
+	}
+	var ips []string
+	for _, xff := range r.Header[xForwardedFor] {
+		ips = append(ips, strings.Split(xff, ",")...)
+	}
+	i := len(ips) - f.trustedProxies
+	if i < 0 {
+		return nil
+	}
+	return net.ParseIP(strings.TrimSpace(ips[i]))
+}
+
+// allowed checks if requests from the IP provided are allowed.
+func (f *Filter) allowed(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, n := range f.deny {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	if len(f.allow) == 0 {
+		return true
+	}
+	for _, n := range f.allow {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIPNets parses the list of CIDRs or IP addresses provided.
+func parseIPNets(entries []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid ip address: %s", entry)
+			}
+			if ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		_, n, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cidr: %s", entry)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
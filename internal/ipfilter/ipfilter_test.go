@@ -0,0 +1,169 @@
+package ipfilter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("no rules provided", func(t *testing.T) {
+		f, err := New(nil, nil, 0)
+		assert.NoError(t, err)
+		assert.Nil(t, f)
+	})
+
+	t.Run("invalid entries", func(t *testing.T) {
+		for _, entry := range []string{"invalid", "1.1.1.1/99", "1.1.1"} {
+			_, err := New([]string{entry}, nil, 0)
+			assert.Error(t, err)
+			_, err = New(nil, []string{entry}, 0)
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("invalid number of trusted proxies", func(t *testing.T) {
+		_, err := New([]string{"10.0.0.0/8"}, nil, -1)
+		assert.Error(t, err)
+	})
+}
+
+func TestFilter(t *testing.T) {
+	testCases := []struct {
+		allow              []string
+		deny               []string
+		remoteAddr         string
+		expectedStatusCode int
+	}{
+		{
+			[]string{"10.0.0.0/8"},
+			nil,
+			"10.1.2.3:",
+			http.StatusOK,
+		},
+		{
+			[]string{"10.0.0.0/8"},
+			nil,
+			"11.1.2.3:",
+			http.StatusForbidden,
+		},
+		{
+			[]string{"10.0.0.0/8", "1.1.1.1"},
+			nil,
+			"1.1.1.1:1234",
+			http.StatusOK,
+		},
+		{
+			nil,
+			[]string{"1.1.1.0/24"},
+			"1.1.1.1:",
+			http.StatusForbidden,
+		},
+		{
+			nil,
+			[]string{"1.1.1.0/24"},
+			"2.2.2.2:",
+			http.StatusOK,
+		},
+		{
+			[]string{"10.0.0.0/8"},
+			[]string{"10.0.0.1"},
+			"10.0.0.1:",
+			http.StatusForbidden,
+		},
+		{
+			[]string{"2001:db8::/32"},
+			nil,
+			"[2001:db8::1]:1234",
+			http.StatusOK,
+		},
+		{
+			[]string{"10.0.0.0/8"},
+			nil,
+			"invalid",
+			http.StatusForbidden,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		desc := fmt.Sprintf("Allow: %v Deny: %v RemoteAddr: %s", tc.allow, tc.deny, tc.remoteAddr)
+		t.Run(desc, func(t *testing.T) {
+			f, err := New(tc.allow, tc.deny, 0)
+			assert.NoError(t, err)
+			w := httptest.NewRecorder()
+			r := &http.Request{RemoteAddr: tc.remoteAddr}
+			f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(w, r)
+			assert.Equal(t, tc.expectedStatusCode, w.Result().StatusCode)
+		})
+	}
+
+	t.Run("trusted proxies", func(t *testing.T) {
+		testCases := []struct {
+			trustedProxies     int
+			xForwardedFor      []string
+			expectedStatusCode int
+		}{
+			{1, nil, http.StatusForbidden},
+			{1, []string{"10.0.0.1"}, http.StatusOK},
+			{1, []string{"11.0.0.1, 10.0.0.1"}, http.StatusOK},
+			{1, []string{"10.0.0.1, 11.0.0.1"}, http.StatusForbidden},
+			{2, []string{"10.0.0.1, 11.0.0.1"}, http.StatusOK},
+			{2, []string{"10.0.0.1", "11.0.0.1"}, http.StatusOK},
+			{3, []string{"10.0.0.1, 11.0.0.1"}, http.StatusForbidden},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			desc := fmt.Sprintf("TrustedProxies: %d XFF: %v", tc.trustedProxies, tc.xForwardedFor)
+			t.Run(desc, func(t *testing.T) {
+				f, err := New([]string{"10.0.0.0/8"}, nil, tc.trustedProxies)
+				assert.NoError(t, err)
+				w := httptest.NewRecorder()
+				r := &http.Request{
+					RemoteAddr: "12.0.0.1:1234",
+					Header:     http.Header{xForwardedFor: tc.xForwardedFor},
+				}
+				f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(w, r)
+				assert.Equal(t, tc.expectedStatusCode, w.Result().StatusCode)
+			})
+		}
+	})
+
+	t.Run("client address spoofed in the X-Forwarded-For header", func(t *testing.T) {
+		testCases := []struct {
+			trustedProxies     int
+			remoteAddr         string
+			xForwardedFor      string
+			expectedStatusCode int
+		}{
+			// No proxies: the address set by the client in the header is ignored
+			{0, "11.0.0.1:1234", "10.0.0.1", http.StatusForbidden},
+			{0, "10.0.0.1:1234", "11.0.0.1", http.StatusOK},
+			// One proxy: the entries on the left of the one it adds are ignored
+			{1, "12.0.0.1:1234", "10.0.0.1, 11.0.0.1", http.StatusForbidden},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			desc := fmt.Sprintf("TrustedProxies: %d RemoteAddr: %s XFF: %s", tc.trustedProxies, tc.remoteAddr, tc.xForwardedFor)
+			t.Run(desc, func(t *testing.T) {
+				f, err := New([]string{"10.0.0.0/8"}, nil, tc.trustedProxies)
+				assert.NoError(t, err)
+				w := httptest.NewRecorder()
+				r := &http.Request{
+					RemoteAddr: tc.remoteAddr,
+					Header:     http.Header{xForwardedFor: []string{tc.xForwardedFor}},
+				}
+				// The remote address is replaced with the one in the header,
+				// keeping the address of the peer connected in the context
+				r = r.WithContext(context.WithValue(r.Context(), PeerAddrKey, r.RemoteAddr))
+				r.RemoteAddr = strings.TrimSpace(strings.Split(tc.xForwardedFor, ",")[0]) + ":"
+				f.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(w, r)
+				assert.Equal(t, tc.expectedStatusCode, w.Result().StatusCode)
+			})
+		}
+	})
+}
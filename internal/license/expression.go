@@ -0,0 +1,142 @@
+package license
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidExpression indicates that the license expression provided is not
+// a valid SPDX license expression.
+var ErrInvalidExpression = errors.New("invalid license expression")
+
+// idRE is a regexp used to validate the license and exception identifiers
+// used in SPDX license expressions, including references to licenses defined
+// in other documents (i.e. DocumentRef-spdx-tool-1.2:LicenseRef-MIT-Style-2).
+var idRE = regexp.MustCompile(`^(DocumentRef-[A-Za-z0-9.-]+:)?[A-Za-z0-9.-]+$`)
+
+// ParseExpression parses the SPDX license expression provided, like
+// "Apache-2.0 OR MIT" or "(MIT AND BSD-3-Clause) OR GPL-2.0+ WITH
+// Bison-exception-2.2", returning it normalized: operators are uppercased and
+// extra whitespace is removed. Licenses identifiers are kept as provided, as
+// they are matched in a case insensitive way.
+func ParseExpression(expr string) (string, error) {
+	p := &exprParser{
+		tokens: strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expr)),
+	}
+	if len(p.tokens) == 0 {
+		return "", fmt.Errorf("%w: empty expression", ErrInvalidExpression)
+	}
+	normalizedExpr, err := p.parseOr()
+	if err != nil {
+		return "", err
+	}
+	if p.pos < len(p.tokens) {
+		return "", fmt.Errorf("%w: unexpected %s", ErrInvalidExpression, p.tokens[p.pos])
+	}
+	return normalizedExpr, nil
+}
+
+// exprParser is a recursive descent parser of SPDX license expressions. The
+// WITH operator takes precedence over AND, which takes precedence over OR.
+type exprParser struct {
+	tokens []string
+	pos    int
+}
+
+// parseOr parses a sequence of expressions joined by the OR operator.
+func (p *exprParser) parseOr() (string, error) {
+	expr, err := p.parseAnd()
+	if err != nil {
+		return "", err
+	}
+	for p.accept("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return "", err
+		}
+		expr += " OR " + right
+	}
+	return expr, nil
+}
+
+// parseAnd parses a sequence of expressions joined by the AND operator.
+func (p *exprParser) parseAnd() (string, error) {
+	expr, err := p.parseWith()
+	if err != nil {
+		return "", err
+	}
+	for p.accept("AND") {
+		right, err := p.parseWith()
+		if err != nil {
+			return "", err
+		}
+		expr += " AND " + right
+	}
+	return expr, nil
+}
+
+// parseWith parses an expression enclosed in parentheses or a license
+// identifier, optionally followed by the WITH operator and an exception.
+func (p *exprParser) parseWith() (string, error) {
+	if p.accept("(") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return "", err
+		}
+		if !p.accept(")") {
+			return "", fmt.Errorf("%w: missing closing parenthesis", ErrInvalidExpression)
+		}
+		return "(" + expr + ")", nil
+	}
+	license, err := p.parseID(true)
+	if err != nil {
+		return "", err
+	}
+	if p.accept("WITH") {
+		exception, err := p.parseID(false)
+		if err != nil {
+			return "", err
+		}
+		return license + " WITH " + exception, nil
+	}
+	return license, nil
+}
+
+// parseID parses a license or exception identifier. License identifiers can
+// be followed by a plus sign, meaning that later versions are allowed too.
+func (p *exprParser) parseID(allowPlus bool) (string, error) {
+	if p.pos >= len(p.tokens) {
+		return "", fmt.Errorf("%w: unexpected end of expression", ErrInvalidExpression)
+	}
+	id := p.tokens[p.pos]
+	v := id
+	if allowPlus {
+		v = strings.TrimSuffix(v, "+")
+	}
+	if isOperator(id) || !idRE.MatchString(v) {
+		return "", fmt.Errorf("%w: unexpected %s", ErrInvalidExpression, id)
+	}
+	p.pos++
+	return id, nil
+}
+
+// accept consumes the next token if it matches the one provided (operators
+// are matched in a case insensitive way).
+func (p *exprParser) accept(token string) bool {
+	if p.pos < len(p.tokens) && strings.EqualFold(p.tokens[p.pos], token) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// isOperator checks if the token provided is an SPDX expression operator.
+func isOperator(token string) bool {
+	switch strings.ToUpper(token) {
+	case "AND", "OR", "WITH":
+		return true
+	}
+	return false
+}
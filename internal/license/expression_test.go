@@ -0,0 +1,61 @@
+package license
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpression(t *testing.T) {
+	t.Run("invalid expressions", func(t *testing.T) {
+		testCases := []string{
+			"",
+			"  ",
+			"MIT OR",
+			"OR MIT",
+			"MIT Apache-2.0",
+			"MIT AND AND Apache-2.0",
+			"(MIT OR Apache-2.0",
+			"MIT OR Apache-2.0)",
+			"()",
+			"GPL-2.0 WITH",
+			"GPL-2.0 WITH Classpath-exception-2.0+",
+			"Apache 2.0",
+			"MIT/X11",
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc, func(t *testing.T) {
+				_, err := ParseExpression(tc)
+				assert.True(t, errors.Is(err, ErrInvalidExpression))
+			})
+		}
+	})
+
+	t.Run("valid expressions", func(t *testing.T) {
+		testCases := []struct {
+			expr         string
+			expectedExpr string
+		}{
+			{"MIT", "MIT"},
+			{" Apache-2.0 ", "Apache-2.0"},
+			{"GPL-2.0+", "GPL-2.0+"},
+			{"Apache-2.0 or MIT", "Apache-2.0 OR MIT"},
+			{"LGPL-2.1-only  OR  BSD-3-Clause AND MIT", "LGPL-2.1-only OR BSD-3-Clause AND MIT"},
+			{"(MIT AND BSD-3-Clause)OR GPL-2.0+ with Bison-exception-2.2", "(MIT AND BSD-3-Clause) OR GPL-2.0+ WITH Bison-exception-2.2"},
+			{"((MIT))", "((MIT))"},
+			{"LicenseRef-Proprietary", "LicenseRef-Proprietary"},
+			{"DocumentRef-spdx-tool-1.2:LicenseRef-MIT-Style-2", "DocumentRef-spdx-tool-1.2:LicenseRef-MIT-Style-2"},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.expr, func(t *testing.T) {
+				expr, err := ParseExpression(tc.expr)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedExpr, expr)
+			})
+		}
+	})
+}
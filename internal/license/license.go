@@ -5,6 +5,17 @@ import (
 	"gopkg.in/src-d/go-license-detector.v3/licensedb/filer"
 )
 
+// FileNames represents the names of the files the license of a package is
+// detected from, in order of preference.
+var FileNames = []string{
+	"LICENSE",
+	"LICENSE.md",
+	"LICENSE.txt",
+	"LICENCE",
+	"LICENCE.md",
+	"COPYING",
+}
+
 // Detect detects the license used in the file provided.
 func Detect(data []byte) string {
 	var license string
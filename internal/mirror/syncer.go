@@ -0,0 +1,250 @@
+package mirror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/jackc/pgx/v4"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+)
+
+const (
+	defaultSyncInterval = 15 * time.Minute
+
+	// sourceMetadataKey represents the repository metadata key used to keep a
+	// reference to the hub instance the repository was mirrored from.
+	sourceMetadataKey = "mirror_source"
+)
+
+// HTTPClient defines the methods an HTTPClient implementation must provide.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Syncer is in charge of mirroring periodically the public catalog of another
+// hub instance (the source). The packages changes published by the source are
+// applied locally: the packages versions created or updated are fetched and
+// registered, and the deleted ones are unregistered. Mirrored packages belong
+// to disabled repositories owned by the mirror organization, so that they are
+// never tracked locally and remain read-only.
+type Syncer struct {
+	db        hub.DB
+	pm        hub.PackageManager
+	hc        HTTPClient
+	sourceURL string
+	orgName   string
+	interval  time.Duration
+
+	since int64
+	repos map[string]string
+}
+
+// NewSyncer creates a new Syncer instance using the mirror configuration
+// provided.
+func NewSyncer(cfg *viper.Viper, db hub.DB, pm hub.PackageManager, hc HTTPClient) (*Syncer, error) {
+	s := &Syncer{
+		db:       db,
+		pm:       pm,
+		hc:       hc,
+		interval: defaultSyncInterval,
+		repos:    make(map[string]string),
+	}
+	if cfg == nil {
+		return s, nil
+	}
+	s.sourceURL = strings.TrimSuffix(cfg.GetString("server.mirror.sourceURL"), "/")
+	if s.sourceURL == "" {
+		return s, nil
+	}
+	u, err := url.Parse(s.sourceURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid mirror source url: %s", s.sourceURL)
+	}
+	s.orgName = cfg.GetString("server.mirror.organization")
+	if s.orgName == "" {
+		return nil, errors.New("mirror organization not provided")
+	}
+	if cfg.GetDuration("server.mirror.interval") > 0 {
+		s.interval = cfg.GetDuration("server.mirror.interval")
+	}
+	return s, nil
+}
+
+// Run syncs the catalog of the source periodically until it's asked to stop
+// via the context provided.
+func (s *Syncer) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	if s.sourceURL == "" {
+		return
+	}
+
+	for {
+		if err := s.sync(ctx); err != nil {
+			log.Error().Err(err).Str("source", s.sourceURL).Msg("error syncing mirror")
+		}
+		select {
+		case <-time.After(s.interval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sync applies the packages changes published by the source since the last
+// sync. Changes are applied in order, so the sync stops at the first one that
+// cannot be applied and it will be retried in the next run. The first sync
+// processes all the changes available, as applying them again is harmless.
+// Changes are fetched in pages, following the cursor returned by the source
+// until there are no more changes available.
+func (s *Syncer) sync(ctx context.Context) error {
+	// Changes registered in the same second as the last one applied may not
+	// have been published yet, so they are requested again
+	since := s.since
+	if since > 0 {
+		since--
+	}
+	var cursor int64
+	for {
+		path := fmt.Sprintf("/api/v1/packages/changes?since=%d", since)
+		if cursor > 0 {
+			path += fmt.Sprintf("&cursor=%d", cursor)
+		}
+		page := &hub.PackageChangesPage{}
+		if err := s.get(ctx, path, page); err != nil {
+			return fmt.Errorf("error getting packages changes: %w", err)
+		}
+		for _, c := range page.Changes {
+			select {
+			case <-ctx.Done():
+				return nil
+			default:
+			}
+			if err := s.applyChange(ctx, c); err != nil {
+				return fmt.Errorf("error applying change (package: %s version: %s): %w", c.PackageName, c.Version, err)
+			}
+			s.since = c.TS
+		}
+		if page.NextCursor == nil {
+			return nil
+		}
+		cursor = *page.NextCursor
+	}
+}
+
+// applyChange applies locally the package change provided.
+func (s *Syncer) applyChange(ctx context.Context, c *hub.PackageChange) error {
+	switch c.ChangeKind {
+	case "created", "updated":
+		p, err := s.getPackage(ctx, c)
+		if err != nil {
+			if errors.Is(err, hub.ErrNotFound) {
+				// The package version was deleted afterwards
+				return nil
+			}
+			return err
+		}
+		repositoryID, err := s.getRepositoryID(ctx, p.Repository)
+		if err != nil {
+			return err
+		}
+		p.PackageID = ""
+		if p.LogoImageID != "" {
+			p.LogoURL = s.sourceURL + "/image/" + p.LogoImageID
+			p.LogoImageID = ""
+		}
+		p.Repository = &hub.Repository{RepositoryID: repositoryID}
+		return s.pm.Register(ctx, p)
+	case "deleted":
+		repositoryID, ok := s.repos[c.RepositoryName]
+		if !ok {
+			query := `
+			select repository_id from repository r join organization o using (organization_id)
+			where r.name = $1 and o.name = $2`
+			err := s.db.QueryRow(ctx, query, c.RepositoryName, s.orgName).Scan(&repositoryID)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					// The repository has not been mirrored yet
+					return nil
+				}
+				return fmt.Errorf("error getting repository %s: %w", c.RepositoryName, err)
+			}
+		}
+		return s.pm.Unregister(ctx, &hub.Package{
+			Name:       c.PackageName,
+			Version:    c.Version,
+			Repository: &hub.Repository{RepositoryID: repositoryID},
+		})
+	default:
+		return nil
+	}
+}
+
+// getPackage fetches from the source the package version the change provided
+// refers to.
+func (s *Syncer) getPackage(ctx context.Context, c *hub.PackageChange) (*hub.Package, error) {
+	p := &hub.Package{}
+	path := fmt.Sprintf("/api/v1/packages/%s/%s/%s/%s",
+		hub.GetKindName(c.RepositoryKind),
+		url.PathEscape(c.RepositoryName),
+		url.PathEscape(c.PackageName),
+		url.PathEscape(c.Version),
+	)
+	if err := s.get(ctx, path, p); err != nil {
+		return nil, fmt.Errorf("error getting package: %w", err)
+	}
+	if p.Repository == nil {
+		return nil, errors.New("package repository not provided by source")
+	}
+	return p, nil
+}
+
+// getRepositoryID returns the id of the local repository mirroring the source
+// repository provided, registering it if needed. Repositories are registered
+// the first time one of their packages is synced after the syncer starts,
+// keeping their details up to date with the source.
+func (s *Syncer) getRepositoryID(ctx context.Context, r *hub.Repository) (string, error) {
+	if repositoryID, ok := s.repos[r.Name]; ok {
+		return repositoryID, nil
+	}
+	repositoryJSON, _ := json.Marshal(&hub.Repository{
+		Name:        r.Name,
+		DisplayName: r.DisplayName,
+		URL:         r.URL,
+		Kind:        r.Kind,
+		Metadata:    map[string]string{sourceMetadataKey: s.sourceURL},
+	})
+	var repositoryID string
+	query := "select register_mirror_repository($1::text, $2::jsonb)"
+	if err := s.db.QueryRow(ctx, query, s.orgName, repositoryJSON).Scan(&repositoryID); err != nil {
+		return "", fmt.Errorf("error registering repository %s: %w", r.Name, err)
+	}
+	s.repos[r.Name] = repositoryID
+	return repositoryID, nil
+}
+
+// get fetches the source's api endpoint provided, decoding the json response
+// in v.
+func (s *Syncer) get(ctx context.Context, path string, v interface{}) error {
+	req, _ := http.NewRequestWithContext(ctx, "GET", s.sourceURL+path, nil)
+	resp, err := s.hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return hub.ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code received: %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
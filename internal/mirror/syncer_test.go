@@ -0,0 +1,296 @@
+package mirror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/pkg"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/jackc/pgx/v4"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	sourceURL  = "https://source.hub"
+	changesURL = sourceURL + "/api/v1/packages/changes?since=0"
+	pkgURL     = sourceURL + "/api/v1/packages/helm/repo1/pkg1/1.0.0"
+
+	registerRepositoryDBQuery = "select register_mirror_repository($1::text, $2::jsonb)"
+	getRepositoryIDDBQuery    = `
+			select repository_id from repository r join organization o using (organization_id)
+			where r.name = $1 and o.name = $2`
+)
+
+var errFake = errors.New("fake error for tests")
+
+func TestNewSyncer(t *testing.T) {
+	t.Run("invalid source url", func(t *testing.T) {
+		cfg := viper.New()
+		cfg.Set("server.mirror.sourceURL", "ftp://source.hub")
+		cfg.Set("server.mirror.organization", "org1")
+		_, err := NewSyncer(cfg, nil, nil, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("organization not provided", func(t *testing.T) {
+		cfg := viper.New()
+		cfg.Set("server.mirror.sourceURL", sourceURL)
+		_, err := NewSyncer(cfg, nil, nil, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("valid configuration", func(t *testing.T) {
+		cfg := viper.New()
+		cfg.Set("server.mirror.sourceURL", sourceURL+"/")
+		cfg.Set("server.mirror.organization", "org1")
+		cfg.Set("server.mirror.interval", "1h")
+		s, err := NewSyncer(cfg, nil, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, sourceURL, s.sourceURL)
+		assert.Equal(t, "org1", s.orgName)
+		assert.Equal(t, "1h0m0s", s.interval.String())
+	})
+}
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("error getting changes", func(t *testing.T) {
+		sw := newSyncerWrapper(t)
+		sw.hc.On("Do", changesURL).Return(nil, errFake)
+
+		err := sw.s.sync(ctx)
+		assert.True(t, errors.Is(err, errFake))
+		sw.assertExpectations(t)
+	})
+
+	t.Run("package version created is registered", func(t *testing.T) {
+		sw := newSyncerWrapper(t)
+		sw.hc.On("Do", changesURL).Return(newResponse(http.StatusOK, `{"changes": [{
+			"package_name": "pkg1",
+			"version": "1.0.0",
+			"repository_name": "repo1",
+			"repository_kind": 0,
+			"change_kind": "created",
+			"ts": 10
+		}], "next_cursor": null}`), nil)
+		sw.hc.On("Do", pkgURL).Return(newResponse(http.StatusOK, `{
+			"package_id": "sourcePackageID",
+			"name": "pkg1",
+			"version": "1.0.0",
+			"logo_image_id": "imageID",
+			"repository": {
+				"repository_id": "sourceRepositoryID",
+				"name": "repo1",
+				"display_name": "Repo 1",
+				"url": "https://repo1.url",
+				"kind": 0
+			}
+		}`), nil)
+		sw.db.On("QueryRow", ctx, registerRepositoryDBQuery, "org1", mock.Anything).
+			Run(func(args mock.Arguments) {
+				var r *hub.Repository
+				require.NoError(t, json.Unmarshal(args.Get(3).([]byte), &r))
+				assert.Equal(t, &hub.Repository{
+					Name:        "repo1",
+					DisplayName: "Repo 1",
+					URL:         "https://repo1.url",
+					Kind:        hub.Helm,
+					Metadata:    map[string]string{"mirror_source": sourceURL},
+				}, r)
+			}).
+			Return("repositoryID", nil)
+		sw.pm.On("Register", ctx, &hub.Package{
+			Name:       "pkg1",
+			Version:    "1.0.0",
+			LogoURL:    sourceURL + "/image/imageID",
+			Repository: &hub.Repository{RepositoryID: "repositoryID"},
+		}).Return(nil)
+
+		err := sw.s.sync(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(10), sw.s.since)
+		assert.Equal(t, "repositoryID", sw.s.repos["repo1"])
+		sw.assertExpectations(t)
+	})
+
+	t.Run("package version no longer available in source is skipped", func(t *testing.T) {
+		sw := newSyncerWrapper(t)
+		sw.hc.On("Do", changesURL).Return(newResponse(http.StatusOK, `{"changes": [{
+			"package_name": "pkg1",
+			"version": "1.0.0",
+			"repository_name": "repo1",
+			"repository_kind": 0,
+			"change_kind": "updated",
+			"ts": 10
+		}], "next_cursor": null}`), nil)
+		sw.hc.On("Do", pkgURL).Return(newResponse(http.StatusNotFound, ""), nil)
+
+		err := sw.s.sync(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(10), sw.s.since)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("package version deleted is unregistered", func(t *testing.T) {
+		sw := newSyncerWrapper(t)
+		sw.hc.On("Do", changesURL).Return(newResponse(http.StatusOK, `{"changes": [
+			{
+				"package_name": "pkg1",
+				"version": "1.0.0",
+				"repository_name": "repo1",
+				"repository_kind": 0,
+				"change_kind": "deleted",
+				"ts": 10
+			},
+			{
+				"package_name": "pkg2",
+				"version": "1.0.0",
+				"repository_name": "repo2",
+				"repository_kind": 0,
+				"change_kind": "deleted",
+				"ts": 11
+			}
+		], "next_cursor": null}`), nil)
+		sw.db.On("QueryRow", ctx, getRepositoryIDDBQuery, "repo1", "org1").Return("repositoryID", nil)
+		sw.db.On("QueryRow", ctx, getRepositoryIDDBQuery, "repo2", "org1").Return(nil, pgx.ErrNoRows)
+		sw.pm.On("Unregister", ctx, &hub.Package{
+			Name:       "pkg1",
+			Version:    "1.0.0",
+			Repository: &hub.Repository{RepositoryID: "repositoryID"},
+		}).Return(nil)
+
+		err := sw.s.sync(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(11), sw.s.since)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("all changes pages are processed", func(t *testing.T) {
+		sw := newSyncerWrapper(t)
+		sw.hc.On("Do", changesURL).Return(newResponse(http.StatusOK, `{"changes": [
+			{
+				"package_change_id": 1,
+				"package_name": "pkg1",
+				"version": "1.0.0",
+				"repository_name": "repo1",
+				"repository_kind": 0,
+				"change_kind": "deleted",
+				"ts": 10
+			}
+		], "next_cursor": 1}`), nil)
+		sw.hc.On("Do", changesURL+"&cursor=1").Return(newResponse(http.StatusOK, `{"changes": [
+			{
+				"package_change_id": 2,
+				"package_name": "pkg2",
+				"version": "1.0.0",
+				"repository_name": "repo1",
+				"repository_kind": 0,
+				"change_kind": "deleted",
+				"ts": 11
+			}
+		], "next_cursor": null}`), nil)
+		sw.db.On("QueryRow", ctx, getRepositoryIDDBQuery, "repo1", "org1").Return("repositoryID", nil)
+		sw.pm.On("Unregister", ctx, &hub.Package{
+			Name:       "pkg1",
+			Version:    "1.0.0",
+			Repository: &hub.Repository{RepositoryID: "repositoryID"},
+		}).Return(nil)
+		sw.pm.On("Unregister", ctx, &hub.Package{
+			Name:       "pkg2",
+			Version:    "1.0.0",
+			Repository: &hub.Repository{RepositoryID: "repositoryID"},
+		}).Return(nil)
+
+		err := sw.s.sync(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(11), sw.s.since)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("sync stops at the first change that cannot be applied", func(t *testing.T) {
+		sw := newSyncerWrapper(t)
+		sw.s.since = 5
+		sw.hc.On("Do", sourceURL+"/api/v1/packages/changes?since=4").Return(newResponse(http.StatusOK, `{"changes": [
+			{
+				"package_name": "pkg1",
+				"version": "1.0.0",
+				"repository_name": "repo1",
+				"repository_kind": 0,
+				"change_kind": "deleted",
+				"ts": 10
+			},
+			{
+				"package_name": "pkg2",
+				"version": "1.0.0",
+				"repository_name": "repo1",
+				"repository_kind": 0,
+				"change_kind": "deleted",
+				"ts": 11
+			}
+		], "next_cursor": null}`), nil)
+		sw.db.On("QueryRow", ctx, getRepositoryIDDBQuery, "repo1", "org1").Return(nil, tests.ErrFakeDatabaseFailure)
+
+		err := sw.s.sync(ctx)
+		assert.True(t, errors.Is(err, tests.ErrFakeDatabaseFailure))
+		assert.Equal(t, int64(5), sw.s.since)
+		sw.assertExpectations(t)
+	})
+}
+
+type syncerWrapper struct {
+	db *tests.DBMock
+	pm *pkg.ManagerMock
+	hc *httpClientMock
+	s  *Syncer
+}
+
+func newSyncerWrapper(t *testing.T) *syncerWrapper {
+	cfg := viper.New()
+	cfg.Set("server.mirror.sourceURL", sourceURL)
+	cfg.Set("server.mirror.organization", "org1")
+	db := &tests.DBMock{}
+	pm := &pkg.ManagerMock{}
+	hc := &httpClientMock{}
+	s, err := NewSyncer(cfg, db, pm, hc)
+	require.NoError(t, err)
+
+	return &syncerWrapper{
+		db: db,
+		pm: pm,
+		hc: hc,
+		s:  s,
+	}
+}
+
+func (sw *syncerWrapper) assertExpectations(t *testing.T) {
+	sw.db.AssertExpectations(t)
+	sw.pm.AssertExpectations(t)
+	sw.hc.AssertExpectations(t)
+}
+
+func newResponse(statusCode int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}
+}
+
+type httpClientMock struct {
+	mock.Mock
+}
+
+func (m *httpClientMock) Do(req *http.Request) (*http.Response, error) {
+	args := m.Called(req.URL.String())
+	resp, _ := args.Get(0).(*http.Response)
+	return resp, args.Error(1)
+}
@@ -11,11 +11,25 @@ import (
 )
 
 // Manager provides an API to manage notifications.
-type Manager struct{}
+type Manager struct {
+	sc hub.SecretsCipher
+}
 
 // NewManager creates a new Manager instance.
-func NewManager() *Manager {
-	return &Manager{}
+func NewManager(opts ...func(m *Manager)) *Manager {
+	m := &Manager{}
+	for _, o := range opts {
+		o(m)
+	}
+	return m
+}
+
+// WithSecretsCipher sets the cipher used to decrypt the webhooks secrets of
+// the pending notifications in a Manager instance.
+func WithSecretsCipher(sc hub.SecretsCipher) func(m *Manager) {
+	return func(m *Manager) {
+		m.sc = sc
+	}
 }
 
 // Add adds the provided notification to the database.
@@ -56,6 +70,13 @@ func (m *Manager) GetPending(ctx context.Context, tx pgx.Tx) (*hub.Notification,
 	if err := json.Unmarshal(dataJSON, &n); err != nil {
 		return nil, err
 	}
+	if m.sc != nil && n.Webhook != nil && n.Webhook.Secret != "" {
+		secret, err := m.sc.Decrypt(ctx, n.Webhook.Secret)
+		if err != nil {
+			return nil, err
+		}
+		n.Webhook.Secret = secret
+	}
 	return n, nil
 }
 
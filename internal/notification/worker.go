@@ -12,6 +12,7 @@ import (
 
 	"github.com/artifacthub/hub/internal/email"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/i18n"
 	"github.com/artifacthub/hub/internal/util"
 	"github.com/jackc/pgx/v4"
 	"github.com/patrickmn/go-cache"
@@ -126,13 +127,13 @@ func (w *Worker) processNotification(ctx context.Context) error {
 func (w *Worker) deliverEmailNotification(ctx context.Context, n *hub.Notification) error {
 	// Prepare email data
 	var emailData email.Data
-	cKey := "emailData.%" + n.Event.EventID
+	cKey := "emailData.%" + n.Event.EventID + "." + n.User.Locale
 	cValue, ok := w.cache.Get(cKey)
 	if ok {
 		emailData = cValue.(email.Data)
 	} else {
 		var err error
-		emailData, err = w.prepareEmailData(ctx, n.Event)
+		emailData, err = w.prepareEmailData(ctx, n.Event, n.User.Locale)
 		if err != nil {
 			log.Error().Err(err).Msg("deliverEmailNotification: error preparing email data")
 			return fmt.Errorf("%w: %v", ErrRetryable, err)
@@ -189,8 +190,9 @@ func (w *Worker) deliverWebhookNotification(ctx context.Context, n *hub.Notifica
 	return nil
 }
 
-// prepareEmailData prepares the email data corresponding to the event provided.
-func (w *Worker) prepareEmailData(ctx context.Context, e *hub.Event) (email.Data, error) {
+// prepareEmailData prepares the email data corresponding to the event provided
+// in the given locale.
+func (w *Worker) prepareEmailData(ctx context.Context, e *hub.Event, locale string) (email.Data, error) {
 	var subject string
 	var emailBody bytes.Buffer
 
@@ -201,8 +203,18 @@ func (w *Worker) prepareEmailData(ctx context.Context, e *hub.Event) (email.Data
 			log.Error().Err(err).Msg("error prepating template data")
 			return email.Data{}, fmt.Errorf("%w: %v", ErrRetryable, err)
 		}
-		subject = fmt.Sprintf("%s version %s released", tmplData.Package["name"], tmplData.Package["version"])
-		if err := newReleaseEmailTmpl.Execute(&emailBody, tmplData); err != nil {
+		subject = i18n.T(locale, "%s version %s released", tmplData.Package["name"], tmplData.Package["version"])
+		if err := newReleaseEmailTmpl.Execute(&emailBody, locale, tmplData); err != nil {
+			return email.Data{}, err
+		}
+	case hub.StatementChange:
+		tmplData, err := w.prepareTemplateData(ctx, e)
+		if err != nil {
+			log.Error().Err(err).Msg("error preparing template data")
+			return email.Data{}, fmt.Errorf("%w: %v", ErrRetryable, err)
+		}
+		subject = i18n.T(locale, "%s publisher statements changed", tmplData.Package["name"])
+		if err := statementChangeEmailTmpl.Execute(&emailBody, locale, tmplData); err != nil {
 			return email.Data{}, err
 		}
 	}
@@ -238,6 +250,8 @@ func (w *Worker) prepareTemplateData(ctx context.Context, e *hub.Event) (*hub.No
 	switch e.EventKind {
 	case hub.NewRelease:
 		eventKindStr = "package.new-release"
+	case hub.StatementChange:
+		eventKindStr = "package.statement-change"
 	}
 	publisher := p.Repository.OrganizationName
 	if publisher == "" {
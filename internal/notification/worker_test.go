@@ -110,6 +110,61 @@ func TestWorker(t *testing.T) {
 		sw.assertExpectations(t)
 	})
 
+	t.Run("statement change email notification delivered successfully", func(t *testing.T) {
+		n := &hub.Notification{
+			NotificationID: "notificationID",
+			Event: &hub.Event{
+				EventID:        "eventID",
+				EventKind:      hub.StatementChange,
+				PackageID:      "packageID",
+				PackageVersion: "1.0.0",
+			},
+			User: u,
+		}
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(n, nil)
+		sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
+		sw.es.On("SendEmail", mock.MatchedBy(func(d *email.Data) bool {
+			return d.Subject == "package1 publisher statements changed"
+		})).Return(nil)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, n.NotificationID, true, nil).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("localized email notification delivered successfully", func(t *testing.T) {
+		n := &hub.Notification{
+			NotificationID: "notificationID",
+			Event: &hub.Event{
+				EventID:        "eventID",
+				EventKind:      hub.StatementChange,
+				PackageID:      "packageID",
+				PackageVersion: "1.0.0",
+			},
+			User: &hub.User{
+				Email:  "user1@email.com",
+				Locale: "es",
+			},
+		}
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.nm.On("GetPending", sw.ctx, sw.tx).Return(n, nil)
+		sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
+		sw.es.On("SendEmail", mock.MatchedBy(func(d *email.Data) bool {
+			return d.Subject == "Declaraciones del publicador de package1 modificadas"
+		})).Return(nil)
+		sw.nm.On("UpdateStatus", sw.ctx, sw.tx, n.NotificationID, true, nil).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		w := NewWorker(sw.svc, sw.cache, "", sw.hc)
+		go w.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
 	t.Run("error getting package preparing webhook payload", func(t *testing.T) {
 		sw := newServicesWrapper()
 		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
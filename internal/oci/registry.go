@@ -0,0 +1,429 @@
+package oci
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	// Scheme represents the url scheme used to reference OCI repositories.
+	Scheme = "oci"
+
+	// ManifestMediaType represents the media type of the OCI image manifests.
+	ManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+
+	// ChartLayerMediaType represents the media type of the layers containing
+	// a Helm chart archive.
+	ChartLayerMediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+
+	// LegacyChartLayerMediaType represents the media type used by the Helm
+	// chart layers pushed by previous Helm versions.
+	LegacyChartLayerMediaType = "application/tar+gzip"
+
+	// maxManifestSize represents the maximum size in bytes of the manifests
+	// that will be processed.
+	maxManifestSize = 4 * 1024 * 1024
+
+	// maxBlobSize represents the maximum size in bytes of the blobs that will
+	// be downloaded.
+	maxBlobSize = 20 * 1024 * 1024
+)
+
+var (
+	// ErrNotFound indicates that the artifact requested does not exist in the
+	// registry.
+	ErrNotFound = errors.New("not found")
+
+	// ErrChartLayerNotFound indicates that the manifest does not contain a
+	// layer with a Helm chart.
+	ErrChartLayerNotFound = errors.New("helm chart layer not found")
+
+	// ErrInvalidDigest indicates that the content downloaded does not match
+	// the expected digest.
+	ErrInvalidDigest = errors.New("invalid digest")
+
+	// bearerParamRE is a regexp used to extract the parameters of a bearer
+	// authentication challenge.
+	bearerParamRE = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+	// linkNextRE is a regexp used to extract the url of the next page from a
+	// Link header.
+	linkNextRE = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+)
+
+// HTTPClient defines the methods an HTTPClient implementation must provide.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Reference represents a reference to an OCI repository, optionally pointing
+// to a specific tag (i.e. oci://ghcr.io/org/charts/chart1:1.0.0).
+type Reference struct {
+	Registry   string
+	Repository string
+	Tag        string
+}
+
+// IsOCI checks if the url provided references an OCI repository.
+func IsOCI(u string) bool {
+	return strings.HasPrefix(u, Scheme+"://")
+}
+
+// ParseReference parses the OCI reference provided.
+func ParseReference(u string) (*Reference, error) {
+	if !IsOCI(u) {
+		return nil, fmt.Errorf("invalid oci reference (%s scheme expected): %s", Scheme, u)
+	}
+	parts := strings.SplitN(strings.TrimPrefix(u, Scheme+"://"), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || strings.Trim(parts[1], "/") == "" {
+		return nil, fmt.Errorf("invalid oci reference: %s", u)
+	}
+	ref := &Reference{
+		Registry:   parts[0],
+		Repository: strings.Trim(parts[1], "/"),
+	}
+	if i := strings.LastIndex(ref.Repository, ":"); i > strings.LastIndex(ref.Repository, "/") {
+		ref.Tag = ref.Repository[i+1:]
+		ref.Repository = ref.Repository[:i]
+	}
+	return ref, nil
+}
+
+// Name returns the name of the artifact referenced, which matches the last
+// element of the repository path.
+func (ref *Reference) Name() string {
+	return ref.Repository[strings.LastIndex(ref.Repository, "/")+1:]
+}
+
+// WithTag returns a copy of the reference pointing to the tag provided.
+func (ref *Reference) WithTag(tag string) *Reference {
+	return &Reference{
+		Registry:   ref.Registry,
+		Repository: ref.Repository,
+		Tag:        tag,
+	}
+}
+
+// String implements the fmt.Stringer interface.
+func (ref *Reference) String() string {
+	s := fmt.Sprintf("%s://%s/%s", Scheme, ref.Registry, ref.Repository)
+	if ref.Tag != "" {
+		s += ":" + ref.Tag
+	}
+	return s
+}
+
+// Manifest represents an OCI image manifest.
+type Manifest struct {
+	MediaType   string            `json:"mediaType"`
+	Config      *Descriptor       `json:"config"`
+	Layers      []*Descriptor     `json:"layers"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// Descriptor represents an OCI content descriptor.
+type Descriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+// Client is a minimal OCI distribution registry client, supporting the
+// operations required to track the Helm charts stored in OCI registries
+// (Harbor, GHCR, ECR, etc). Anonymous bearer tokens are requested when the
+// registry asks for them.
+type Client struct {
+	hc     HTTPClient
+	mu     sync.RWMutex
+	tokens map[string]string
+}
+
+// NewClient creates a new Client instance.
+func NewClient(hc HTTPClient) *Client {
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	return &Client{
+		hc:     hc,
+		tokens: make(map[string]string),
+	}
+}
+
+// Tags returns all the tags available in the repository referenced.
+func (c *Client) Tags(ctx context.Context, ref *Reference) ([]string, error) {
+	var tags []string
+	u := fmt.Sprintf("https://%s/v2/%s/tags/list", ref.Registry, ref.Repository)
+	for u != "" {
+		resp, err := c.do(ctx, ref, http.MethodGet, u, "")
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Tags []string `json:"tags"`
+		}
+		err = json.NewDecoder(io.LimitReader(resp.Body, maxManifestSize)).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error decoding tags list: %w", err)
+		}
+		tags = append(tags, page.Tags...)
+		u, err = nextPageURL(u, resp.Header.Get("Link"))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tags, nil
+}
+
+// Digest returns the digest of the manifest referenced.
+func (c *Client) Digest(ctx context.Context, ref *Reference) (string, error) {
+	resp, err := c.do(ctx, ref, http.MethodHead, manifestURL(ref), ManifestMediaType)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	if digest := resp.Header.Get("Docker-Content-Digest"); digest != "" {
+		return digest, nil
+	}
+
+	// Some registries don't return the digest header on HEAD requests
+	_, digest, err := c.Manifest(ctx, ref)
+	return digest, err
+}
+
+// Manifest returns the manifest referenced as well as its digest.
+func (c *Client) Manifest(ctx context.Context, ref *Reference) (*Manifest, string, error) {
+	resp, err := c.do(ctx, ref, http.MethodGet, manifestURL(ref), ManifestMediaType)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxManifestSize))
+	if err != nil {
+		return nil, "", err
+	}
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		digest = computeDigest(data)
+	}
+	var m *Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, "", fmt.Errorf("error decoding manifest: %w", err)
+	}
+	return m, digest, nil
+}
+
+// Blob downloads the blob with the digest provided from the repository
+// referenced, verifying its content matches the digest.
+func (c *Client) Blob(ctx context.Context, ref *Reference, digest string) ([]byte, error) {
+	u := fmt.Sprintf("https://%s/v2/%s/blobs/%s", ref.Registry, ref.Repository, digest)
+	resp, err := c.do(ctx, ref, http.MethodGet, u, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxBlobSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxBlobSize {
+		return nil, fmt.Errorf("blob %s exceeds maximum size allowed", digest)
+	}
+	if computeDigest(data) != digest {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDigest, digest)
+	}
+	return data, nil
+}
+
+// PullChart downloads the Helm chart archive referenced, returning its data
+// and the digest of the manifest it belongs to.
+func (c *Client) PullChart(ctx context.Context, ref *Reference) ([]byte, string, error) {
+	m, digest, err := c.Manifest(ctx, ref)
+	if err != nil {
+		return nil, "", err
+	}
+	layer := GetChartLayer(m)
+	if layer == nil {
+		return nil, "", ErrChartLayerNotFound
+	}
+	data, err := c.Blob(ctx, ref, layer.Digest)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, digest, nil
+}
+
+// GetChartLayer returns the layer of the manifest provided that contains the
+// Helm chart archive, if any.
+func GetChartLayer(m *Manifest) *Descriptor {
+	for _, layer := range m.Layers {
+		switch layer.MediaType {
+		case ChartLayerMediaType, LegacyChartLayerMediaType:
+			return layer
+		}
+	}
+	return nil
+}
+
+// do performs an http request to the registry, authenticating it using an
+// anonymous bearer token when the registry requires it.
+func (c *Client) do(ctx context.Context, ref *Reference, method, u, accept string) (*http.Response, error) {
+	scope := fmt.Sprintf("repository:%s:pull", ref.Repository)
+	resp, err := c.doWithToken(ctx, method, u, accept, c.getToken(ref.Registry, scope))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		token, err := c.requestToken(ctx, challenge, scope)
+		if err != nil {
+			return nil, err
+		}
+		c.setToken(ref.Registry, scope, token)
+		resp, err = c.doWithToken(ctx, method, u, accept, token)
+		if err != nil {
+			return nil, err
+		}
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, u)
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status code received: %d", resp.StatusCode)
+	}
+}
+
+// doWithToken performs an http request using the bearer token provided, if
+// any.
+func (c *Client) doWithToken(ctx context.Context, method, u, accept, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return c.hc.Do(req)
+}
+
+// requestToken requests an anonymous token to the authorization service
+// described in the bearer authentication challenge provided.
+func (c *Client) requestToken(ctx context.Context, challenge, scope string) (string, error) {
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return "", errors.New("unauthorized: unsupported authentication challenge")
+	}
+	params := make(map[string]string)
+	for _, m := range bearerParamRE.FindAllStringSubmatch(challenge, -1) {
+		params[strings.ToLower(m[1])] = m[2]
+	}
+	if params["realm"] == "" {
+		return "", errors.New("unauthorized: authentication realm not provided")
+	}
+	u, err := url.Parse(params["realm"])
+	if err != nil {
+		return "", fmt.Errorf("invalid authentication realm: %w", err)
+	}
+	qs := u.Query()
+	if params["service"] != "" {
+		qs.Set("service", params["service"])
+	}
+	if params["scope"] != "" {
+		scope = params["scope"]
+	}
+	qs.Set("scope", scope)
+	u.RawQuery = qs.Encode()
+
+	resp, err := c.doWithToken(ctx, http.MethodGet, u.String(), "", "")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code received requesting token: %d", resp.StatusCode)
+	}
+	var tokenResp struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxManifestSize)).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("error decoding token: %w", err)
+	}
+	if tokenResp.Token != "" {
+		return tokenResp.Token, nil
+	}
+	return tokenResp.AccessToken, nil
+}
+
+// getToken returns the token cached for the registry and scope provided.
+func (c *Client) getToken(registry, scope string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.tokens[registry+"#"+scope]
+}
+
+// setToken caches the token provided for the registry and scope given.
+func (c *Client) setToken(registry, scope, token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokens[registry+"#"+scope] = token
+}
+
+// manifestURL returns the url of the manifest referenced.
+func manifestURL(ref *Reference) string {
+	return fmt.Sprintf("https://%s/v2/%s/manifests/%s", ref.Registry, ref.Repository, ref.Tag)
+}
+
+// nextPageURL returns the url of the next page described in the Link header
+// provided, resolved against the url of the current page.
+func nextPageURL(current, link string) (string, error) {
+	m := linkNextRE.FindStringSubmatch(link)
+	if m == nil {
+		return "", nil
+	}
+	base, err := url.Parse(current)
+	if err != nil {
+		return "", err
+	}
+	next, err := base.Parse(m[1])
+	if err != nil {
+		return "", err
+	}
+	return next.String(), nil
+}
+
+// computeDigest returns the sha256 digest of the data provided.
+func computeDigest(data []byte) string {
+	hash := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(hash[:])
+}
+
+// VersionFromTag returns the version corresponding to the tag provided. Helm
+// replaces the plus sign of the versions build metadata with an underscore in
+// the tags, as the plus sign is not allowed in them.
+func VersionFromTag(tag string) string {
+	return strings.ReplaceAll(tag, "_", "+")
+}
+
+// TagFromVersion returns the tag corresponding to the version provided.
+func TagFromVersion(version string) string {
+	return strings.ReplaceAll(version, "+", "_")
+}
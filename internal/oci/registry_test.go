@@ -0,0 +1,185 @@
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReference(t *testing.T) {
+	t.Run("invalid reference", func(t *testing.T) {
+		testCases := []string{
+			"https://ghcr.io/org/chart1",
+			"oci://ghcr.io",
+			"oci://ghcr.io/",
+			"oci:///org/chart1",
+		}
+		for _, u := range testCases {
+			u := u
+			t.Run(u, func(t *testing.T) {
+				ref, err := ParseReference(u)
+				assert.Error(t, err)
+				assert.Nil(t, ref)
+			})
+		}
+	})
+
+	t.Run("valid reference", func(t *testing.T) {
+		testCases := []struct {
+			u           string
+			expectedRef *Reference
+			name        string
+		}{
+			{
+				"oci://ghcr.io/org/charts/chart1",
+				&Reference{Registry: "ghcr.io", Repository: "org/charts/chart1"},
+				"chart1",
+			},
+			{
+				"oci://localhost:5000/chart1:1.0.0",
+				&Reference{Registry: "localhost:5000", Repository: "chart1", Tag: "1.0.0"},
+				"chart1",
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.u, func(t *testing.T) {
+				ref, err := ParseReference(tc.u)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedRef, ref)
+				assert.Equal(t, tc.name, ref.Name())
+				assert.Equal(t, tc.u, ref.String())
+			})
+		}
+	})
+}
+
+func TestVersionTagConversion(t *testing.T) {
+	assert.Equal(t, "1.0.0+build.1", VersionFromTag("1.0.0_build.1"))
+	assert.Equal(t, "1.0.0_build.1", TagFromVersion("1.0.0+build.1"))
+}
+
+func TestClient(t *testing.T) {
+	chartData := []byte("chart archive data")
+	chartDigest := computeDigest(chartData)
+	manifest, _ := json.Marshal(&Manifest{
+		MediaType: ManifestMediaType,
+		Layers: []*Descriptor{
+			{MediaType: ChartLayerMediaType, Digest: chartDigest, Size: int64(len(chartData))},
+		},
+	})
+	manifestDigest := computeDigest(manifest)
+
+	// Setup a registry that requires an anonymous bearer token
+	var tokenRequests int
+	var ts *httptest.Server
+	ts = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			tokenRequests++
+			assert.Equal(t, "registry", r.URL.Query().Get("service"))
+			assert.Equal(t, "repository:org/chart1:pull", r.URL.Query().Get("scope"))
+			_, _ = w.Write([]byte(`{"token": "token1"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token1" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+				`Bearer realm="%s/token",service="registry",scope="repository:org/chart1:pull"`, ts.URL,
+			))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/org/chart1/tags/list":
+			if r.URL.Query().Get("last") == "" {
+				w.Header().Set("Link", `</v2/org/chart1/tags/list?last=1.0.0>; rel="next"`)
+				_, _ = w.Write([]byte(`{"tags": ["1.0.0"]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"tags": ["1.1.0", "latest"]}`))
+		case "/v2/org/chart1/manifests/1.0.0":
+			assert.Equal(t, ManifestMediaType, r.Header.Get("Accept"))
+			w.Header().Set("Docker-Content-Digest", manifestDigest)
+			if r.Method == http.MethodGet {
+				_, _ = w.Write(manifest)
+			}
+		case "/v2/org/chart1/blobs/" + chartDigest:
+			_, _ = w.Write(chartData)
+		case "/v2/org/chart1/blobs/sha256:invalid":
+			_, _ = w.Write(chartData)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	ctx := context.Background()
+	ref, err := ParseReference("oci://" + strings.TrimPrefix(ts.URL, "https://") + "/org/chart1")
+	require.NoError(t, err)
+	c := NewClient(ts.Client())
+
+	t.Run("tags", func(t *testing.T) {
+		tags, err := c.Tags(ctx, ref)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1.0.0", "1.1.0", "latest"}, tags)
+	})
+
+	t.Run("digest", func(t *testing.T) {
+		digest, err := c.Digest(ctx, ref.WithTag("1.0.0"))
+		require.NoError(t, err)
+		assert.Equal(t, manifestDigest, digest)
+	})
+
+	t.Run("pull chart", func(t *testing.T) {
+		data, digest, err := c.PullChart(ctx, ref.WithTag("1.0.0"))
+		require.NoError(t, err)
+		assert.Equal(t, chartData, data)
+		assert.Equal(t, manifestDigest, digest)
+	})
+
+	t.Run("pull chart not found", func(t *testing.T) {
+		_, _, err := c.PullChart(ctx, ref.WithTag("2.0.0"))
+		assert.True(t, errors.Is(err, ErrNotFound))
+	})
+
+	t.Run("blob digest does not match", func(t *testing.T) {
+		_, err := c.Blob(ctx, ref, "sha256:invalid")
+		assert.True(t, errors.Is(err, ErrInvalidDigest))
+	})
+
+	// The token is requested once and reused afterwards
+	assert.Equal(t, 1, tokenRequests)
+}
+
+func TestGetChartLayer(t *testing.T) {
+	legacyLayer := &Descriptor{MediaType: LegacyChartLayerMediaType, Digest: "sha256:1"}
+	testCases := []struct {
+		m             *Manifest
+		expectedLayer *Descriptor
+	}{
+		{
+			&Manifest{},
+			nil,
+		},
+		{
+			&Manifest{Layers: []*Descriptor{{MediaType: "application/octet-stream"}}},
+			nil,
+		},
+		{
+			&Manifest{Layers: []*Descriptor{{MediaType: "application/octet-stream"}, legacyLayer}},
+			legacyLayer,
+		},
+	}
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			assert.Equal(t, tc.expectedLayer, GetChartLayer(tc.m))
+		})
+	}
+}
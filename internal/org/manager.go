@@ -4,16 +4,23 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"regexp"
 
 	"github.com/artifacthub/hub/internal/email"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/i18n"
 	"github.com/artifacthub/hub/internal/util"
+	"github.com/jackc/pgx/v4"
 	"github.com/satori/uuid"
 )
 
+// maxFeaturedPackages represents the maximum number of packages an
+// organization can feature in its profile.
+const maxFeaturedPackages = 12
+
 var (
 	// organizationNameRE is a regexp used to validate an organization name.
 	organizationNameRE = regexp.MustCompile(`^[a-z0-9-]+$`)
@@ -23,14 +30,27 @@ var (
 type Manager struct {
 	db hub.DB
 	es hub.EmailSender
+	sc hub.SecretsCipher
 }
 
 // NewManager creates a new Manager instance.
-func NewManager(db hub.DB, es hub.EmailSender) *Manager {
-	return &Manager{
+func NewManager(db hub.DB, es hub.EmailSender, opts ...func(m *Manager)) *Manager {
+	m := &Manager{
 		db: db,
 		es: es,
 	}
+	for _, o := range opts {
+		o(m)
+	}
+	return m
+}
+
+// WithSecretsCipher sets the cipher used to encrypt the validation webhooks
+// secrets before storing them in the database in a Manager instance.
+func WithSecretsCipher(sc hub.SecretsCipher) func(m *Manager) {
+	return func(m *Manager) {
+		m.sc = sc
+	}
 }
 
 // Add adds the provided organization to the database.
@@ -91,9 +111,9 @@ func (m *Manager) AddMember(ctx context.Context, orgName, userAlias, baseURL str
 
 	// Send organization invitation email
 	if m.es != nil {
-		var userEmail string
-		query := `select email from "user" where alias = $1`
-		if err := m.db.QueryRow(ctx, query, userAlias).Scan(&userEmail); err != nil {
+		var userEmail, userLocale string
+		query := `select email, coalesce(locale, '') from "user" where alias = $1`
+		if err := m.db.QueryRow(ctx, query, userAlias).Scan(&userEmail, &userLocale); err != nil {
 			return err
 		}
 		templateData := map[string]string{
@@ -101,12 +121,12 @@ func (m *Manager) AddMember(ctx context.Context, orgName, userAlias, baseURL str
 			"orgName": orgName,
 		}
 		var emailBody bytes.Buffer
-		if err := invitationTmpl.Execute(&emailBody, templateData); err != nil {
+		if err := invitationTmpl.Execute(&emailBody, userLocale, templateData); err != nil {
 			return err
 		}
 		emailData := &email.Data{
 			To:      userEmail,
-			Subject: fmt.Sprintf("Invitation to join %s on Artifact Hub", orgName),
+			Subject: i18n.T(userLocale, "Invitation to join %s on Artifact Hub", orgName),
 			Body:    emailBody.Bytes(),
 		}
 		if err := m.es.SendEmail(emailData); err != nil {
@@ -117,6 +137,26 @@ func (m *Manager) AddMember(ctx context.Context, orgName, userAlias, baseURL str
 	return nil
 }
 
+// AddShare grants the target organization provided read access to the
+// repositories of the organization provided. The user doing the request must
+// be a member of the organization sharing its repositories.
+func (m *Manager) AddShare(ctx context.Context, orgName, targetOrgName string) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if err := validateShareInput(orgName, targetOrgName); err != nil {
+		return err
+	}
+
+	// Add organization share to database
+	query := "select add_organization_share($1::uuid, $2::text, $3::text)"
+	_, err := m.db.Exec(ctx, query, userID, orgName, targetOrgName)
+	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+		return hub.ErrInsufficientPrivilege
+	}
+	return err
+}
+
 // CheckAvailability checks the availability of a given value for the provided
 // resource kind.
 func (m *Manager) CheckAvailability(ctx context.Context, resourceKind, value string) (bool, error) {
@@ -190,6 +230,26 @@ func (m *Manager) DeleteMember(ctx context.Context, orgName, userAlias string) e
 	return err
 }
 
+// DeleteShare revokes the read access to the repositories of the organization
+// provided previously granted to the target organization. The user doing the
+// request must be a member of the organization sharing its repositories.
+func (m *Manager) DeleteShare(ctx context.Context, orgName, targetOrgName string) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if err := validateShareInput(orgName, targetOrgName); err != nil {
+		return err
+	}
+
+	// Delete organization share from database
+	query := "select delete_organization_share($1::uuid, $2::text, $3::text)"
+	_, err := m.db.Exec(ctx, query, userID, orgName, targetOrgName)
+	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+		return hub.ErrInsufficientPrivilege
+	}
+	return err
+}
+
 // GetByUserJSON returns the organizations the user doing the request belongs
 // to as a json object.
 func (m *Manager) GetByUserJSON(ctx context.Context) ([]byte, error) {
@@ -210,6 +270,35 @@ func (m *Manager) GetJSON(ctx context.Context, orgName string) ([]byte, error) {
 	return m.dbQueryJSON(ctx, query, orgName)
 }
 
+// GetMemberDeletionImpactJSON returns what would happen if the provided user
+// was removed from the given organization, as a json object. Nothing is
+// deleted. The user doing the request must be a member of the organization.
+func (m *Manager) GetMemberDeletionImpactJSON(ctx context.Context, orgName, userAlias string) ([]byte, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if orgName == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "organization name not provided")
+	}
+	if userAlias == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "user alias not provided")
+	}
+
+	// Get organization member deletion impact from database
+	query := "select get_organization_member_deletion_impact($1::uuid, $2::text, $3::text)"
+	dataJSON, err := m.dbQueryJSON(ctx, query, userID, orgName, userAlias)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, hub.ErrNotFound
+		case err.Error() == util.ErrDBInsufficientPrivilege.Error():
+			return nil, hub.ErrInsufficientPrivilege
+		}
+		return nil, err
+	}
+	return dataJSON, nil
+}
+
 // GetMembersJSON returns the members of the provided organization as a json
 // object.
 func (m *Manager) GetMembersJSON(ctx context.Context, orgName string) ([]byte, error) {
@@ -232,6 +321,64 @@ func (m *Manager) GetMembersJSON(ctx context.Context, orgName string) ([]byte, e
 	return dataJSON, nil
 }
 
+// GetSharesJSON returns the organizations the repositories of the provided
+// organization have been shared with as a json object.
+func (m *Manager) GetSharesJSON(ctx context.Context, orgName string) ([]byte, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if orgName == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "organization name not provided")
+	}
+
+	// Get organization shares from database
+	query := "select get_organization_shares($1::uuid, $2::text)"
+	dataJSON, err := m.dbQueryJSON(ctx, query, userID, orgName)
+	if err != nil {
+		if err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+			return nil, hub.ErrInsufficientPrivilege
+		}
+		return nil, err
+	}
+	return dataJSON, nil
+}
+
+// GetValidationWebhookJSON returns the validation webhook of the provided
+// organization as a json object. The user doing the request must be a member
+// of the organization.
+func (m *Manager) GetValidationWebhookJSON(ctx context.Context, orgName string) ([]byte, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if orgName == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "organization name not provided")
+	}
+
+	// Get organization validation webhook from database
+	query := "select get_organization_validation_webhook($1::uuid, $2::text)"
+	dataJSON, err := m.dbQueryJSON(ctx, query, userID, orgName)
+	if err != nil {
+		if err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+			return nil, hub.ErrInsufficientPrivilege
+		}
+		return nil, err
+	}
+	if m.sc == nil {
+		return dataJSON, nil
+	}
+	var vw *hub.ValidationWebhook
+	if err := json.Unmarshal(dataJSON, &vw); err != nil {
+		return nil, err
+	}
+	if vw.Secret != "" {
+		vw.Secret, err = m.sc.Decrypt(ctx, vw.Secret)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(vw)
+}
+
 // Update updates the provided organization in the database.
 func (m *Manager) Update(ctx context.Context, org *hub.Organization) error {
 	userID := ctx.Value(hub.UserIDKey).(string)
@@ -253,6 +400,80 @@ func (m *Manager) Update(ctx context.Context, org *hub.Organization) error {
 	return err
 }
 
+// UpdateFeaturedPackages replaces the packages featured in the profile of the
+// provided organization. Packages are displayed in the order provided and they
+// must belong to one of the organization's repositories.
+func (m *Manager) UpdateFeaturedPackages(ctx context.Context, orgName string, packagesIDs []string) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if orgName == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "organization name not provided")
+	}
+	if len(packagesIDs) > maxFeaturedPackages {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "too many featured packages")
+	}
+	featuredPackagesIDs := make([]string, 0, len(packagesIDs))
+	seen := make(map[string]struct{}, len(packagesIDs))
+	for _, packageID := range packagesIDs {
+		id, err := uuid.FromString(packageID)
+		if err != nil {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
+		}
+		if _, ok := seen[id.String()]; ok {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "duplicated package id")
+		}
+		seen[id.String()] = struct{}{}
+		featuredPackagesIDs = append(featuredPackagesIDs, id.String())
+	}
+
+	// Update organization featured packages in database
+	query := "select update_organization_featured_packages($1::uuid, $2::text, $3::jsonb)"
+	packagesIDsJSON, _ := json.Marshal(featuredPackagesIDs)
+	_, err := m.db.Exec(ctx, query, userID, orgName, packagesIDsJSON)
+	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+		return hub.ErrInsufficientPrivilege
+	}
+	return err
+}
+
+// UpdateValidationWebhook updates the validation webhook of the provided
+// organization. An empty url removes the validation webhook. The user doing
+// the request must be a member of the organization.
+func (m *Manager) UpdateValidationWebhook(ctx context.Context, orgName string, vw *hub.ValidationWebhook) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if orgName == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "organization name not provided")
+	}
+	if vw.URL != "" {
+		u, err := url.Parse(vw.URL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid url")
+		}
+	}
+
+	// Encrypt secret when a cipher is set
+	vwCopy := *vw
+	if m.sc != nil && vw.Secret != "" {
+		secret, err := m.sc.Encrypt(ctx, vw.Secret)
+		if err != nil {
+			return err
+		}
+		vwCopy.Secret = secret
+	}
+
+	// Update organization validation webhook in database
+	query := "select update_organization_validation_webhook($1::uuid, $2::text, $3::jsonb)"
+	vwJSON, _ := json.Marshal(vwCopy)
+	_, err := m.db.Exec(ctx, query, userID, orgName, vwJSON)
+	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+		return hub.ErrInsufficientPrivilege
+	}
+	return err
+}
+
 // dbQueryJSON is a helper that executes the query provided and returns a bytes
 // slice containing the json data returned from the database.
 func (m *Manager) dbQueryJSON(ctx context.Context, query string, args ...interface{}) ([]byte, error) {
@@ -262,3 +483,18 @@ func (m *Manager) dbQueryJSON(ctx context.Context, query string, args ...interfa
 	}
 	return dataJSON, nil
 }
+
+// validateShareInput checks the input provided to manage an organization share
+// is valid.
+func validateShareInput(orgName, targetOrgName string) error {
+	if orgName == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "organization name not provided")
+	}
+	if targetOrgName == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "target organization name not provided")
+	}
+	if orgName == targetOrgName {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "an organization cannot share its repositories with itself")
+	}
+	return nil
+}
@@ -2,18 +2,23 @@ package org
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"testing"
 
 	"github.com/artifacthub/hub/internal/email"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/secrets"
 	"github.com/artifacthub/hub/internal/tests"
 	"github.com/artifacthub/hub/internal/util"
+	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+var errFake = errors.New("fake error for tests")
+
 func TestAdd(t *testing.T) {
 	dbQuery := `select add_organization($1::uuid, $2::jsonb)`
 	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
@@ -90,7 +95,7 @@ func TestAdd(t *testing.T) {
 
 func TestAddMember(t *testing.T) {
 	dbQueryAddMember := `select add_organization_member($1::uuid, $2::text, $3::text)`
-	dbQueryGetUserEmail := `select email from "user" where alias = $1`
+	dbQueryGetUserEmail := `select email, coalesce(locale, '') from "user" where alias = $1`
 	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
 
 	t.Run("user id not found in ctx", func(t *testing.T) {
@@ -162,7 +167,7 @@ func TestAddMember(t *testing.T) {
 			t.Run(tc.description, func(t *testing.T) {
 				db := &tests.DBMock{}
 				db.On("Exec", ctx, dbQueryAddMember, "userID", "orgName", "userAlias").Return(nil)
-				db.On("QueryRow", ctx, dbQueryGetUserEmail, mock.Anything).Return("email", nil)
+				db.On("QueryRow", ctx, dbQueryGetUserEmail, mock.Anything).Return([]interface{}{"email", "es"}, nil)
 				es := &email.SenderMock{}
 				es.On("SendEmail", mock.Anything).Return(tc.emailSenderResponse)
 				m := NewManager(db, es)
@@ -204,6 +209,89 @@ func TestAddMember(t *testing.T) {
 	})
 }
 
+func TestAddShare(t *testing.T) {
+	dbQuery := `select add_organization_share($1::uuid, $2::text, $3::text)`
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil, nil)
+		assert.Panics(t, func() {
+			_ = m.AddShare(context.Background(), "orgName", "targetOrgName")
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg        string
+			orgName       string
+			targetOrgName string
+		}{
+			{
+				"organization name not provided",
+				"",
+				"org2",
+			},
+			{
+				"target organization name not provided",
+				"org1",
+				"",
+			},
+			{
+				"an organization cannot share its repositories with itself",
+				"org1",
+				"org1",
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil, nil)
+				err := m.AddShare(ctx, tc.orgName, tc.targetOrgName)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, "userID", "orgName", "targetOrgName").Return(nil)
+		m := NewManager(db, nil)
+
+		err := m.AddShare(ctx, "orgName", "targetOrgName")
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDatabaseFailure,
+				tests.ErrFakeDatabaseFailure,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("Exec", ctx, dbQuery, "userID", "orgName", "targetOrgName").Return(tc.dbErr)
+				m := NewManager(db, nil)
+
+				err := m.AddShare(ctx, "orgName", "targetOrgName")
+				assert.Equal(t, tc.expectedError, err)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+}
+
 func TestCheckAvailability(t *testing.T) {
 	ctx := context.Background()
 
@@ -392,6 +480,89 @@ func TestDeleteMember(t *testing.T) {
 	})
 }
 
+func TestDeleteShare(t *testing.T) {
+	dbQuery := `select delete_organization_share($1::uuid, $2::text, $3::text)`
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil, nil)
+		assert.Panics(t, func() {
+			_ = m.DeleteShare(context.Background(), "orgName", "targetOrgName")
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg        string
+			orgName       string
+			targetOrgName string
+		}{
+			{
+				"organization name not provided",
+				"",
+				"org2",
+			},
+			{
+				"target organization name not provided",
+				"org1",
+				"",
+			},
+			{
+				"an organization cannot share its repositories with itself",
+				"org1",
+				"org1",
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil, nil)
+				err := m.DeleteShare(ctx, tc.orgName, tc.targetOrgName)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, "userID", "orgName", "targetOrgName").Return(nil)
+		m := NewManager(db, nil)
+
+		err := m.DeleteShare(ctx, "orgName", "targetOrgName")
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDatabaseFailure,
+				tests.ErrFakeDatabaseFailure,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("Exec", ctx, dbQuery, "userID", "orgName", "targetOrgName").Return(tc.dbErr)
+				m := NewManager(db, nil)
+
+				err := m.DeleteShare(ctx, "orgName", "targetOrgName")
+				assert.Equal(t, tc.expectedError, err)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+}
+
 func TestGetByUserJSON(t *testing.T) {
 	dbQuery := `select get_user_organizations($1::uuid)`
 	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
@@ -459,6 +630,90 @@ func TestGetJSON(t *testing.T) {
 	})
 }
 
+func TestGetMemberDeletionImpactJSON(t *testing.T) {
+	dbQuery := `select get_organization_member_deletion_impact($1::uuid, $2::text, $3::text)`
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil, nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetMemberDeletionImpactJSON(context.Background(), "orgName", "userAlias")
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg    string
+			orgName   string
+			userAlias string
+		}{
+			{
+				"organization name not provided",
+				"",
+				"userAlias",
+			},
+			{
+				"user alias not provided",
+				"orgName",
+				"",
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil, nil)
+				_, err := m.GetMemberDeletionImpactJSON(ctx, tc.orgName, tc.userAlias)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "userID", "orgName", "userAlias").Return([]byte("dataJSON"), nil)
+		m := NewManager(db, nil)
+
+		dataJSON, err := m.GetMemberDeletionImpactJSON(ctx, "orgName", "userAlias")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDatabaseFailure,
+				tests.ErrFakeDatabaseFailure,
+			},
+			{
+				pgx.ErrNoRows,
+				hub.ErrNotFound,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, dbQuery, "userID", "orgName", "userAlias").Return(nil, tc.dbErr)
+				m := NewManager(db, nil)
+
+				dataJSON, err := m.GetMemberDeletionImpactJSON(ctx, "orgName", "userAlias")
+				assert.Equal(t, tc.expectedError, err)
+				assert.Nil(t, dataJSON)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+}
+
 func TestGetMembersJSON(t *testing.T) {
 	dbQuery := `select get_organization_members($1::uuid, $2::text)`
 	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
@@ -517,6 +772,152 @@ func TestGetMembersJSON(t *testing.T) {
 	})
 }
 
+func TestGetSharesJSON(t *testing.T) {
+	dbQuery := `select get_organization_shares($1::uuid, $2::text)`
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil, nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetSharesJSON(context.Background(), "orgName")
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		m := NewManager(nil, nil)
+		_, err := m.GetSharesJSON(ctx, "")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "userID", "orgName").Return([]byte("dataJSON"), nil)
+		m := NewManager(db, nil)
+
+		dataJSON, err := m.GetSharesJSON(ctx, "orgName")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDatabaseFailure,
+				tests.ErrFakeDatabaseFailure,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, dbQuery, "userID", "orgName").Return(nil, tc.dbErr)
+				m := NewManager(db, nil)
+
+				dataJSON, err := m.GetSharesJSON(ctx, "orgName")
+				assert.Equal(t, tc.expectedError, err)
+				assert.Nil(t, dataJSON)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+}
+
+func TestGetValidationWebhookJSON(t *testing.T) {
+	dbQuery := `select get_organization_validation_webhook($1::uuid, $2::text)`
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil, nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetValidationWebhookJSON(context.Background(), "orgName")
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		m := NewManager(nil, nil)
+		_, err := m.GetValidationWebhookJSON(ctx, "")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "userID", "orgName").Return([]byte("dataJSON"), nil)
+		m := NewManager(db, nil)
+
+		dataJSON, err := m.GetValidationWebhookJSON(ctx, "orgName")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("validation webhook with decrypted secret returned successfully", func(t *testing.T) {
+		sc := &secrets.CipherMock{}
+		sc.On("Decrypt", ctx, "encryptedSecret").Return("secret", nil)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "userID", "orgName").
+			Return([]byte(`{"url": "https://validation.url", "secret": "encryptedSecret"}`), nil)
+		m := NewManager(db, nil, WithSecretsCipher(sc))
+
+		dataJSON, err := m.GetValidationWebhookJSON(ctx, "orgName")
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"url": "https://validation.url", "secret": "secret"}`, string(dataJSON))
+		db.AssertExpectations(t)
+		sc.AssertExpectations(t)
+	})
+
+	t.Run("error decrypting secret", func(t *testing.T) {
+		sc := &secrets.CipherMock{}
+		sc.On("Decrypt", ctx, "encryptedSecret").Return("", errFake)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "userID", "orgName").
+			Return([]byte(`{"url": "https://validation.url", "secret": "encryptedSecret"}`), nil)
+		m := NewManager(db, nil, WithSecretsCipher(sc))
+
+		dataJSON, err := m.GetValidationWebhookJSON(ctx, "orgName")
+		assert.Equal(t, errFake, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+		sc.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDatabaseFailure,
+				tests.ErrFakeDatabaseFailure,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, dbQuery, "userID", "orgName").Return(nil, tc.dbErr)
+				m := NewManager(db, nil)
+
+				dataJSON, err := m.GetValidationWebhookJSON(ctx, "orgName")
+				assert.Equal(t, tc.expectedError, err)
+				assert.Nil(t, dataJSON)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+}
+
 func TestUpdate(t *testing.T) {
 	dbQuery := `select update_organization($1::uuid, $2::jsonb)`
 	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
@@ -590,3 +991,246 @@ func TestUpdate(t *testing.T) {
 		}
 	})
 }
+
+func TestUpdateFeaturedPackages(t *testing.T) {
+	dbQuery := `select update_organization_featured_packages($1::uuid, $2::text, $3::jsonb)`
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+	pkg1ID := "00000000-0000-0000-0000-000000000001"
+	pkg2ID := "00000000-0000-0000-0000-000000000002"
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil, nil)
+		assert.Panics(t, func() {
+			_ = m.UpdateFeaturedPackages(context.Background(), "orgName", nil)
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		tooManyPackagesIDs := make([]string, 0, maxFeaturedPackages+1)
+		for i := 0; i <= maxFeaturedPackages; i++ {
+			tooManyPackagesIDs = append(tooManyPackagesIDs, fmt.Sprintf("00000000-0000-0000-0000-%012d", i))
+		}
+		testCases := []struct {
+			errMsg      string
+			orgName     string
+			packagesIDs []string
+		}{
+			{
+				"organization name not provided",
+				"",
+				[]string{pkg1ID},
+			},
+			{
+				"too many featured packages",
+				"org1",
+				tooManyPackagesIDs,
+			},
+			{
+				"invalid package id",
+				"org1",
+				[]string{pkg1ID, "invalid"},
+			},
+			{
+				"duplicated package id",
+				"org1",
+				[]string{pkg1ID, pkg2ID, pkg1ID},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil, nil)
+				err := m.UpdateFeaturedPackages(ctx, tc.orgName, tc.packagesIDs)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		testCases := []struct {
+			packagesIDs             []string
+			expectedPackagesIDsJSON []byte
+		}{
+			{
+				[]string{pkg2ID, pkg1ID},
+				[]byte(`["` + pkg2ID + `","` + pkg1ID + `"]`),
+			},
+			{
+				nil,
+				[]byte(`[]`),
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(string(tc.expectedPackagesIDsJSON), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("Exec", ctx, dbQuery, "userID", "orgName", tc.expectedPackagesIDsJSON).Return(nil)
+				m := NewManager(db, nil)
+
+				err := m.UpdateFeaturedPackages(ctx, "orgName", tc.packagesIDs)
+				assert.NoError(t, err)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDatabaseFailure,
+				tests.ErrFakeDatabaseFailure,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("Exec", ctx, dbQuery, "userID", "orgName", mock.Anything).Return(tc.dbErr)
+				m := NewManager(db, nil)
+
+				err := m.UpdateFeaturedPackages(ctx, "orgName", []string{pkg1ID})
+				assert.Equal(t, tc.expectedError, err)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+}
+
+func TestUpdateValidationWebhook(t *testing.T) {
+	dbQuery := `select update_organization_validation_webhook($1::uuid, $2::text, $3::jsonb)`
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+	vw := &hub.ValidationWebhook{
+		URL:    "https://validation.url",
+		Secret: "secret",
+	}
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil, nil)
+		assert.Panics(t, func() {
+			_ = m.UpdateValidationWebhook(context.Background(), "orgName", vw)
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg  string
+			orgName string
+			vw      *hub.ValidationWebhook
+		}{
+			{
+				"organization name not provided",
+				"",
+				vw,
+			},
+			{
+				"invalid url",
+				"orgName",
+				&hub.ValidationWebhook{
+					URL: "validation.url",
+				},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil, nil)
+				err := m.UpdateValidationWebhook(ctx, tc.orgName, tc.vw)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		testCases := []struct {
+			description string
+			vw          *hub.ValidationWebhook
+		}{
+			{
+				"validation webhook set",
+				vw,
+			},
+			{
+				"validation webhook removed",
+				&hub.ValidationWebhook{},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.description, func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("Exec", ctx, dbQuery, "userID", "orgName", mock.Anything).Return(nil)
+				m := NewManager(db, nil)
+
+				err := m.UpdateValidationWebhook(ctx, "orgName", tc.vw)
+				assert.NoError(t, err)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("validation webhook with encrypted secret set", func(t *testing.T) {
+		sc := &secrets.CipherMock{}
+		sc.On("Encrypt", ctx, "secret").Return("encryptedSecret", nil)
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, "userID", "orgName", mock.MatchedBy(func(vwJSON []byte) bool {
+			var vw *hub.ValidationWebhook
+			_ = json.Unmarshal(vwJSON, &vw)
+			return vw.Secret == "encryptedSecret"
+		})).Return(nil)
+		m := NewManager(db, nil, WithSecretsCipher(sc))
+
+		vwCopy := *vw
+		err := m.UpdateValidationWebhook(ctx, "orgName", &vwCopy)
+		assert.NoError(t, err)
+		assert.Equal(t, "secret", vwCopy.Secret)
+		db.AssertExpectations(t)
+		sc.AssertExpectations(t)
+	})
+
+	t.Run("error encrypting secret", func(t *testing.T) {
+		sc := &secrets.CipherMock{}
+		sc.On("Encrypt", ctx, "secret").Return("", errFake)
+		m := NewManager(nil, nil, WithSecretsCipher(sc))
+
+		err := m.UpdateValidationWebhook(ctx, "orgName", vw)
+		assert.Equal(t, errFake, err)
+		sc.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDatabaseFailure,
+				tests.ErrFakeDatabaseFailure,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("Exec", ctx, dbQuery, "userID", "orgName", mock.Anything).Return(tc.dbErr)
+				m := NewManager(db, nil)
+
+				err := m.UpdateValidationWebhook(ctx, "orgName", vw)
+				assert.Equal(t, tc.expectedError, err)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+}
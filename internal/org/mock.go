@@ -24,6 +24,12 @@ func (m *ManagerMock) AddMember(ctx context.Context, orgName, userAlias, baseURL
 	return args.Error(0)
 }
 
+// AddShare implements the OrganizationManager interface.
+func (m *ManagerMock) AddShare(ctx context.Context, orgName, targetOrgName string) error {
+	args := m.Called(ctx, orgName, targetOrgName)
+	return args.Error(0)
+}
+
 // CheckAvailability implements the OrganizationManager interface.
 func (m *ManagerMock) CheckAvailability(ctx context.Context, resourceKind, value string) (bool, error) {
 	args := m.Called(ctx, resourceKind, value)
@@ -42,6 +48,12 @@ func (m *ManagerMock) DeleteMember(ctx context.Context, orgName, userAlias strin
 	return args.Error(0)
 }
 
+// DeleteShare implements the OrganizationManager interface.
+func (m *ManagerMock) DeleteShare(ctx context.Context, orgName, targetOrgName string) error {
+	args := m.Called(ctx, orgName, targetOrgName)
+	return args.Error(0)
+}
+
 // GetJSON implements the OrganizationManager interface.
 func (m *ManagerMock) GetJSON(ctx context.Context, orgName string) ([]byte, error) {
 	args := m.Called(ctx, orgName)
@@ -56,6 +68,13 @@ func (m *ManagerMock) GetByUserJSON(ctx context.Context) ([]byte, error) {
 	return data, args.Error(1)
 }
 
+// GetMemberDeletionImpactJSON implements the OrganizationManager interface.
+func (m *ManagerMock) GetMemberDeletionImpactJSON(ctx context.Context, orgName, userAlias string) ([]byte, error) {
+	args := m.Called(ctx, orgName, userAlias)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
 // GetMembersJSON implements the OrganizationManager interface.
 func (m *ManagerMock) GetMembersJSON(ctx context.Context, orgName string) ([]byte, error) {
 	args := m.Called(ctx, orgName)
@@ -63,8 +82,34 @@ func (m *ManagerMock) GetMembersJSON(ctx context.Context, orgName string) ([]byt
 	return data, args.Error(1)
 }
 
+// GetSharesJSON implements the OrganizationManager interface.
+func (m *ManagerMock) GetSharesJSON(ctx context.Context, orgName string) ([]byte, error) {
+	args := m.Called(ctx, orgName)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+// GetValidationWebhookJSON implements the OrganizationManager interface.
+func (m *ManagerMock) GetValidationWebhookJSON(ctx context.Context, orgName string) ([]byte, error) {
+	args := m.Called(ctx, orgName)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
 // Update implements the OrganizationManager interface.
 func (m *ManagerMock) Update(ctx context.Context, org *hub.Organization) error {
 	args := m.Called(ctx, org)
 	return args.Error(0)
 }
+
+// UpdateFeaturedPackages implements the OrganizationManager interface.
+func (m *ManagerMock) UpdateFeaturedPackages(ctx context.Context, orgName string, packagesIDs []string) error {
+	args := m.Called(ctx, orgName, packagesIDs)
+	return args.Error(0)
+}
+
+// UpdateValidationWebhook implements the OrganizationManager interface.
+func (m *ManagerMock) UpdateValidationWebhook(ctx context.Context, orgName string, vw *hub.ValidationWebhook) error {
+	args := m.Called(ctx, orgName, vw)
+	return args.Error(0)
+}
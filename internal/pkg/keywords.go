@@ -0,0 +1,96 @@
+package pkg
+
+import (
+	"regexp"
+	"strings"
+)
+
+// keywordSeparatorsRE is a regexp used to find the separators (spaces and
+// underscores) that will be replaced by hyphens when normalizing a keyword.
+var keywordSeparatorsRE = regexp.MustCompile(`[\s_]+`)
+
+// keywordInvariantSuffixes represents the suffixes of the keywords that end
+// with an s but are not plurals, so they must not be singularized.
+var keywordInvariantSuffixes = []string{
+	"aas", "ics", "is", "js", "ops", "os", "ss", "us",
+}
+
+// keywordInvariants represents some well known keywords that end with an s
+// but are not plurals.
+var keywordInvariants = map[string]struct{}{
+	"https":      {},
+	"jenkins":    {},
+	"kubernetes": {},
+	"postgres":   {},
+	"series":     {},
+}
+
+// normalizeKeywords normalizes the keywords provided, so that the same
+// concept is described using the same keyword across packages. Keywords are
+// case folded, trimmed, singularized and mapped using the aliases provided
+// (i.e. k8s -> kubernetes). Empty and duplicated keywords are removed.
+func normalizeKeywords(keywords []string, aliases map[string]string) []string {
+	if len(keywords) == 0 {
+		return keywords
+	}
+	normalized := make([]string, 0, len(keywords))
+	seen := make(map[string]struct{}, len(keywords))
+	for _, k := range keywords {
+		k = normalizeKeyword(k, aliases)
+		if k == "" {
+			continue
+		}
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		normalized = append(normalized, k)
+	}
+	return normalized
+}
+
+// normalizeKeyword normalizes the keyword provided. Aliases are checked both
+// before and after singularizing the keyword.
+func normalizeKeyword(k string, aliases map[string]string) string {
+	k = foldKeyword(k)
+	if alias, ok := aliases[k]; ok {
+		return foldKeyword(alias)
+	}
+	k = singularizeKeyword(k)
+	if alias, ok := aliases[k]; ok {
+		return foldKeyword(alias)
+	}
+	return k
+}
+
+// foldKeyword lower cases and trims the keyword provided, replacing spaces
+// and underscores with hyphens.
+func foldKeyword(k string) string {
+	k = strings.ToLower(strings.TrimSpace(k))
+	return keywordSeparatorsRE.ReplaceAllString(k, "-")
+}
+
+// singularizeKeyword returns the singular form of the keyword provided when
+// it looks like a regular plural. Only the last word of hyphenated keywords is
+// singularized (i.e. helm-charts -> helm-chart).
+func singularizeKeyword(k string) string {
+	prefix, word := "", k
+	if i := strings.LastIndex(k, "-"); i >= 0 {
+		prefix, word = k[:i+1], k[i+1:]
+	}
+	if len(word) <= 3 || !strings.HasSuffix(word, "s") {
+		return k
+	}
+	if _, ok := keywordInvariants[word]; ok {
+		return k
+	}
+	for _, suffix := range keywordInvariantSuffixes {
+		if strings.HasSuffix(word, suffix) {
+			return k
+		}
+	}
+	if strings.HasSuffix(word, "ies") && len(word) > 4 {
+		return prefix + strings.TrimSuffix(word, "ies") + "y"
+	}
+	return prefix + strings.TrimSuffix(word, "s")
+}
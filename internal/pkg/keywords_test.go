@@ -0,0 +1,46 @@
+package pkg
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeKeywords(t *testing.T) {
+	aliases := map[string]string{
+		"k8s":        "kubernetes",
+		"postgresql": "Postgres",
+	}
+	testCases := []struct {
+		keywords         []string
+		expectedKeywords []string
+	}{
+		{
+			nil,
+			nil,
+		},
+		{
+			[]string{"Database", " database ", "DATABASES", ""},
+			[]string{"database"},
+		},
+		{
+			[]string{"Helm Charts", "service_mesh", "policies"},
+			[]string{"helm-chart", "service-mesh", "policy"},
+		},
+		{
+			[]string{"kubernetes", "redis", "ingress", "prometheus", "metrics", "devops", "nodejs", "aws", "saas"},
+			[]string{"kubernetes", "redis", "ingress", "prometheus", "metrics", "devops", "nodejs", "aws", "saas"},
+		},
+		{
+			[]string{"K8s", "postgresql", "k8s"},
+			[]string{"kubernetes", "postgres"},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprint(tc.keywords), func(t *testing.T) {
+			assert.Equal(t, tc.expectedKeywords, normalizeKeywords(tc.keywords, aliases))
+		})
+	}
+}
@@ -6,22 +6,83 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/jackc/pgx/v4"
 	"github.com/satori/uuid"
 )
 
+// supportedVersionsComparisonRE is a regexp used to validate each of the
+// comparisons in the maintenance supported versions constraint.
+var supportedVersionsComparisonRE = regexp.MustCompile(`^(>=|<=|>|<|=)?\s*(.+)$`)
+
+// kubeVersionComparisonRE is a regexp used to validate each of the comparisons
+// in a normalized kubeVersion constraint.
+var kubeVersionComparisonRE = regexp.MustCompile(`^(>=|<)(.+)$`)
+
+// kubernetesVersionRE is a regexp used to validate the Kubernetes minor
+// versions used to filter packages (i.e. 1.19).
+var kubernetesVersionRE = regexp.MustCompile(`^\d+\.\d+$`)
+
+// maxInstalledReleases is the maximum number of installed releases that can be
+// matched in a single request.
+const maxInstalledReleases = 500
+
+// maxChangesLimit is the maximum number of packages versions changes that can
+// be requested in a single page.
+const maxChangesLimit = 1000
+
 // Manager provides an API to manage packages.
 type Manager struct {
-	db hub.DB
+	db              hub.DB
+	dualWrite       bool
+	keywordsAliases map[string]string
+	limits          *hub.Limits
 }
 
 // NewManager creates a new Manager instance.
-func NewManager(db hub.DB) *Manager {
-	return &Manager{
-		db: db,
+func NewManager(db hub.DB, opts ...func(m *Manager)) *Manager {
+	m := &Manager{
+		db:     db,
+		limits: hub.DefaultLimits(),
+	}
+	for _, o := range opts {
+		o(m)
+	}
+	return m
+}
+
+// WithDualWrite enables the dual write compatibility mode in a Manager
+// instance. When enabled, the packages fields that have been moved to a
+// dedicated column (like the operators capabilities) are also written to
+// their previous location in the package data, so that hub instances still
+// running a previous version keep working during an upgrade window.
+func WithDualWrite() func(m *Manager) {
+	return func(m *Manager) {
+		m.dualWrite = true
+	}
+}
+
+// WithKeywordsAliases allows providing a mapping table used to normalize the
+// packages keywords at registration (i.e. k8s -> kubernetes).
+func WithKeywordsAliases(aliases map[string]string) func(m *Manager) {
+	return func(m *Manager) {
+		m.keywordsAliases = aliases
+	}
+}
+
+// WithLimits allows overriding the default limits enforced by a Manager
+// instance, like the search results page size or the size of the readme files
+// returned.
+func WithLimits(l *hub.Limits) func(m *Manager) {
+	return func(m *Manager) {
+		m.limits = l
 	}
 }
 
@@ -38,6 +99,99 @@ func (m *Manager) Get(ctx context.Context, input *hub.GetPackageInput) (*hub.Pac
 	return p, nil
 }
 
+// GetAllJSON returns a json object with a page of all the packages available,
+// sorted by id. The next_cursor value returned can be used to get the next
+// page. The json object is built by the database.
+func (m *Manager) GetAllJSON(ctx context.Context, input *hub.GetAllPackagesInput) ([]byte, error) {
+	// Validate input
+	if input.Limit <= 0 || input.Limit > 100 {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid limit (0 < l <= 100)")
+	}
+	if input.Cursor != "" {
+		if _, err := uuid.FromString(input.Cursor); err != nil {
+			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid cursor")
+		}
+	}
+
+	// Get packages from database
+	inputJSON, _ := json.Marshal(input)
+	return m.dbQueryJSON(ctx, "select get_all_packages($1::jsonb)", inputJSON)
+}
+
+// GetByOwner returns the latest version of the packages in the repositories
+// owned by the user alias or organization name provided. Packages in private
+// repositories are only returned to the users with read access to them.
+func (m *Manager) GetByOwner(ctx context.Context, owner string) ([]*hub.Package, error) {
+	// Validate input
+	if owner == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "owner not provided")
+	}
+
+	// Get packages from database
+	query := "select get_packages_by_owner($1::uuid, $2::text)"
+	dataJSON, err := m.dbQueryJSON(ctx, query, getUserID(ctx), owner)
+	if err != nil {
+		return nil, err
+	}
+	var packages []*hub.Package
+	if err := json.Unmarshal(dataJSON, &packages); err != nil {
+		return nil, err
+	}
+	return packages, nil
+}
+
+// GetChangesJSON returns a json object with a page of the packages versions
+// created, updated or deleted since the time provided (unix timestamp), sorted
+// by change id. The next_cursor value returned can be used to get the next
+// page. The json object is built by the database.
+func (m *Manager) GetChangesJSON(ctx context.Context, input *hub.GetPackageChangesInput) ([]byte, error) {
+	// Validate input
+	if input.Since < 0 {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid since (s >= 0)")
+	}
+	if input.Cursor < 0 {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid cursor")
+	}
+	if input.Limit <= 0 || input.Limit > maxChangesLimit {
+		return nil, fmt.Errorf("%w: invalid limit (0 < l <= %d)", hub.ErrInvalidInput, maxChangesLimit)
+	}
+
+	// Get changes from database
+	inputJSON, _ := json.Marshal(input)
+	return m.dbQueryJSON(ctx, "select get_package_changes($1::jsonb)", inputJSON)
+}
+
+// GetDependenciesGraphJSON returns a json object with the dependencies graph
+// of the package version identified by the input provided. The json object is
+// built by the database.
+func (m *Manager) GetDependenciesGraphJSON(ctx context.Context, input *hub.GetPackageInput) ([]byte, error) {
+	// Validate input
+	if input.PackageName == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "package name not provided")
+	}
+	if input.RepositoryName == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "repository name not provided")
+	}
+
+	// Packages in private repositories are only returned to the users with
+	// read access to them
+	if userID, ok := ctx.Value(hub.UserIDKey).(string); ok {
+		input.UserID = userID
+	}
+
+	// Get package dependencies graph from database
+	query := "select get_package_dependencies_graph($1::jsonb)"
+	inputJSON, _ := json.Marshal(input)
+	dataJSON, err := m.dbQueryJSON(ctx, query, inputJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, hub.ErrNotFound
+		}
+		return nil, err
+	}
+	return dataJSON, nil
+}
+
 // GetJSON returns the package identified by the input provided as a json
 // object. The json object is built by the database.
 func (m *Manager) GetJSON(ctx context.Context, input *hub.GetPackageInput) ([]byte, error) {
@@ -46,6 +200,16 @@ func (m *Manager) GetJSON(ctx context.Context, input *hub.GetPackageInput) ([]by
 		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "package name not provided")
 	}
 
+	// Tags are normalized the same way as the packages keywords, so that any
+	// form of a tag matches the packages tagged with it
+	input.Tags = normalizeKeywords(input.Tags, m.keywordsAliases)
+
+	// Packages in private repositories are only returned to the users with
+	// read access to them
+	if userID, ok := ctx.Value(hub.UserIDKey).(string); ok {
+		input.UserID = userID
+	}
+
 	// Get package from database
 	query := "select get_package($1::jsonb)"
 	inputJSON, _ := json.Marshal(input)
@@ -56,9 +220,60 @@ func (m *Manager) GetJSON(ctx context.Context, input *hub.GetPackageInput) ([]by
 		}
 		return nil, err
 	}
+	if m.limits.ReadmeMaxBytes > 0 {
+		return truncateReadme(dataJSON, m.limits.ReadmeMaxBytes)
+	}
 	return dataJSON, nil
 }
 
+// GetLatestVersionsJSON returns a json object with the latest stable and
+// prerelease versions of the package identified by the input provided. The
+// json object is built by the database.
+func (m *Manager) GetLatestVersionsJSON(ctx context.Context, input *hub.GetPackageInput) ([]byte, error) {
+	// Validate input
+	if input.PackageName == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "package name not provided")
+	}
+	if input.RepositoryName == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "repository name not provided")
+	}
+
+	// Packages in private repositories are only returned to the users with
+	// read access to them
+	if userID, ok := ctx.Value(hub.UserIDKey).(string); ok {
+		input.UserID = userID
+	}
+
+	// Get package latest versions from database
+	query := "select get_package_latest_versions($1::jsonb)"
+	inputJSON, _ := json.Marshal(input)
+	dataJSON, err := m.dbQueryJSON(ctx, query, inputJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, hub.ErrNotFound
+		}
+		return nil, err
+	}
+	return dataJSON, nil
+}
+
+// GetProjectJSON returns the packages of other kinds that belong to the same
+// project as the package provided (i.e. a chart and an operator published from
+// the same source repository). The json array is built by the database.
+func (m *Manager) GetProjectJSON(ctx context.Context, packageID string) ([]byte, error) {
+	// Validate input
+	if packageID == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "package id not provided")
+	}
+	if _, err := uuid.FromString(packageID); err != nil {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
+	}
+
+	// Get packages from database
+	query := "select get_package_project($1::uuid, $2::uuid)"
+	return m.dbQueryJSON(ctx, query, getUserID(ctx), packageID)
+}
+
 // GetRandomJSON returns a json object with some random packages. The json
 // object is built by the database.
 func (m *Manager) GetRandomJSON(ctx context.Context) ([]byte, error) {
@@ -90,13 +305,147 @@ func (m *Manager) GetStarsJSON(ctx context.Context, packageID string) ([]byte, e
 }
 
 // GetStatsJSON returns a json object describing the number of packages and
-// releases available in the database. The json object is built by the database.
+// releases available in the database, not including the ones in private
+// repositories. The json object is built by the database.
 func (m *Manager) GetStatsJSON(ctx context.Context) ([]byte, error) {
 	return m.dbQueryJSON(ctx, "select get_packages_stats()")
 }
 
-// Register registers the package provided in the database.
+// GetTagsJSON returns a json array with the tags used by the packages
+// available, along with the number of packages using each of them. Packages in
+// private repositories are not counted. The json array is built by the
+// database.
+func (m *Manager) GetTagsJSON(ctx context.Context, limit int) ([]byte, error) {
+	// Validate input
+	if limit <= 0 || limit > 500 {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid limit (0 < l <= 500)")
+	}
+
+	// Get tags from database
+	return m.dbQueryJSON(ctx, "select get_packages_tags($1::int)", limit)
+}
+
+// GetVersionDocsJSON returns a json object with the readme and the changelog
+// of the package version identified by the input provided (the latest version
+// is used when none is provided). The json object is built by the database.
+func (m *Manager) GetVersionDocsJSON(ctx context.Context, input *hub.GetPackageInput) ([]byte, error) {
+	// Validate input
+	if input.PackageName == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "package name not provided")
+	}
+	if input.RepositoryName == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "repository name not provided")
+	}
+
+	// Packages in private repositories are only returned to the users with
+	// read access to them
+	if userID, ok := ctx.Value(hub.UserIDKey).(string); ok {
+		input.UserID = userID
+	}
+
+	// Get package version docs from database
+	query := "select get_package_version_docs($1::jsonb)"
+	inputJSON, _ := json.Marshal(input)
+	dataJSON, err := m.dbQueryJSON(ctx, query, inputJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, hub.ErrNotFound
+		}
+		return nil, err
+	}
+	if m.limits.ReadmeMaxBytes > 0 {
+		return truncateReadme(dataJSON, m.limits.ReadmeMaxBytes)
+	}
+	return dataJSON, nil
+}
+
+// MatchInstalledJSON returns a json array with the packages matching the
+// installed releases provided, along with the status of the versions
+// installed (i.e. if an update is available or if they have been deprecated).
+// The json array is built by the database.
+func (m *Manager) MatchInstalledJSON(ctx context.Context, releases []*hub.InstalledRelease) ([]byte, error) {
+	// Validate input
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "releases not provided")
+	}
+	if len(releases) > maxInstalledReleases {
+		return nil, fmt.Errorf("%w: %s (max: %d)", hub.ErrInvalidInput, "too many releases", maxInstalledReleases)
+	}
+	input := make([]*hub.InstalledRelease, 0, len(releases))
+	for _, r := range releases {
+		if r == nil || r.Name == "" {
+			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "release name not provided")
+		}
+		sv, err := semver.NewVersion(r.Version)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %s", hub.ErrInvalidInput, "invalid release version", r.Version)
+		}
+		if !isAbsoluteURL(r.RepositoryURL) {
+			return nil, fmt.Errorf("%w: %s: %s", hub.ErrInvalidInput, "invalid release repository url", r.RepositoryURL)
+		}
+
+		// Versions are normalized the same way they are when registered
+		input = append(input, &hub.InstalledRelease{
+			Name:          r.Name,
+			Version:       sv.String(),
+			RepositoryURL: r.RepositoryURL,
+		})
+	}
+
+	// Match installed releases in database
+	inputJSON, _ := json.Marshal(input)
+	query := "select match_installed_packages($1::uuid, $2::jsonb)"
+	return m.dbQueryJSON(ctx, query, getUserID(ctx), inputJSON)
+}
+
+// Push registers the package described in the metadata provided in the
+// repository the api key used to authenticate the request is scoped to. The
+// requesting user must have write access to the repository.
+func (m *Manager) Push(ctx context.Context, md *hub.PackageMetadata) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+	repositoryID := ctx.Value(hub.RepositoryIDKey).(string)
+
+	// Validate input
+	if md == nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "metadata not provided")
+	}
+	pkg, err := PreparePackageFromMetadata(md)
+	if err != nil {
+		return fmt.Errorf("%w: %v", hub.ErrInvalidInput, err)
+	}
+	if err := m.prepareForRegistration(pkg); err != nil {
+		return err
+	}
+
+	// Register package in the repository
+	pkgJSON, _ := json.Marshal(pkg)
+	query := "select push_package($1::uuid, $2::uuid, $3::jsonb)"
+	_, err = m.db.Exec(ctx, query, userID, repositoryID, pkgJSON)
+	if err != nil {
+		if err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+			return hub.ErrInsufficientPrivilege
+		}
+		return err
+	}
+	return nil
+}
+
+// Register registers the package provided in the database. The package
+// keywords are normalized before registering it.
 func (m *Manager) Register(ctx context.Context, pkg *hub.Package) error {
+	if err := m.prepareForRegistration(pkg); err != nil {
+		return err
+	}
+
+	// Register package in database
+	pkgJSON, _ := json.Marshal(pkg)
+	_, err := m.db.Exec(ctx, "select register_package($1::jsonb)", pkgJSON)
+	return err
+}
+
+// prepareForRegistration validates the package provided and normalizes some
+// of its fields so that it's ready to be registered.
+func (m *Manager) prepareForRegistration(pkg *hub.Package) error {
 	// Validate input
 	if pkg.Name == "" {
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "name not provided")
@@ -143,19 +492,114 @@ func (m *Manager) Register(ctx context.Context, pkg *hub.Package) error {
 			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid channel version (semver expected)")
 		}
 	}
+	if pkg.Capabilities != "" && !isValidCapabilities(pkg.Capabilities) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid capabilities")
+	}
+	for _, i := range pkg.ContainersImages {
+		if i == nil || i.Image == "" {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "container image not provided")
+		}
+	}
+	if pkg.ChartType != "" && !isValidChartType(pkg.ChartType) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid chart type")
+	}
+	if pkg.SignKey != nil {
+		if pkg.SignKey.KeyID == "" {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "sign key id not provided")
+		}
+		if pkg.SignKey.URL != "" && !isAbsoluteURL(pkg.SignKey.URL) {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid sign key url")
+		}
+	}
+	if pkg.KubeVersion != "" && !isValidKubeVersion(pkg.KubeVersion) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid kube version")
+	}
+	if pkg.Maintenance != nil {
+		if pkg.Maintenance.EOL != "" {
+			if _, err := time.Parse("2006-01-02", pkg.Maintenance.EOL); err != nil {
+				return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid maintenance eol (YYYY-MM-DD expected)")
+			}
+		}
+		if pkg.Maintenance.SupportedVersions != "" && !isValidSupportedVersions(pkg.Maintenance.SupportedVersions) {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid maintenance supported versions")
+		}
+	}
 
-	// Register package in database
-	pkgJSON, _ := json.Marshal(pkg)
-	_, err = m.db.Exec(ctx, "select register_package($1::jsonb)", pkgJSON)
-	return err
+	pkg.Keywords = normalizeKeywords(pkg.Keywords, m.keywordsAliases)
+	if m.dualWrite {
+		pkg.Data = addLegacyData(pkg)
+	}
+	return nil
+}
+
+// Resolve returns the latest version of the packages matching the external
+// identifiers provided: their repository url and name, or the source url of
+// their latest version.
+func (m *Manager) Resolve(ctx context.Context, input *hub.ResolvePackageInput) ([]*hub.Package, error) {
+	// Validate input
+	if input.RepositoryKind != nil && hub.GetKindName(*input.RepositoryKind) == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid repository kind")
+	}
+	switch {
+	case input.RepositoryURL == "" && input.SourceURL == "":
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "repository url or source url not provided")
+	case input.RepositoryURL != "" && input.SourceURL != "":
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "repository url and source url cannot be used together")
+	case input.RepositoryURL != "":
+		if !isAbsoluteURL(input.RepositoryURL) {
+			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid repository url")
+		}
+		if input.PackageName == "" {
+			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "package name not provided")
+		}
+	default:
+		if !isAbsoluteURL(input.SourceURL) {
+			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid source url")
+		}
+	}
+
+	// Packages in private repositories are only returned to the users with
+	// read access to them
+	if userID, ok := ctx.Value(hub.UserIDKey).(string); ok {
+		input.UserID = userID
+	}
+
+	// Resolve packages in database
+	inputJSON, _ := json.Marshal(input)
+	dataJSON, err := m.dbQueryJSON(ctx, "select resolve_packages($1::jsonb)", inputJSON)
+	if err != nil {
+		return nil, err
+	}
+	var packages []*hub.Package
+	if err := json.Unmarshal(dataJSON, &packages); err != nil {
+		return nil, err
+	}
+	return packages, nil
+}
+
+// RestoreUnregistered registers again the package versions unregistered from
+// the repository provided whose tombstones have not been pruned yet, returning
+// the number of versions restored. When a package name is provided, only the
+// versions of that package are restored.
+func (m *Manager) RestoreUnregistered(ctx context.Context, repoName, pkgName string) (int64, error) {
+	// Validate input
+	if repoName == "" {
+		return 0, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "repository name not provided")
+	}
+
+	// Restore packages versions in database
+	var n int64
+	query := "select restore_unregistered_packages($1::text, nullif($2::text, ''))"
+	err := m.db.QueryRow(ctx, query, repoName, pkgName).Scan(&n)
+	return n, err
 }
 
 // SearchJSON returns a json object with the search results produced by the
 // input provided. The json object is built by the database.
 func (m *Manager) SearchJSON(ctx context.Context, input *hub.SearchPackageInput) ([]byte, error) {
 	// Validate input
-	if input.Limit <= 0 || input.Limit > 50 {
-		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid limit (0 < l <= 50)")
+	if input.Limit <= 0 || input.Limit > m.limits.SearchMaxPageSize {
+		return nil, fmt.Errorf("%w: invalid limit (0 < l <= %d)", hub.ErrInvalidInput, m.limits.SearchMaxPageSize)
 	}
 	if input.Offset < 0 {
 		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid offset (o >= 0)")
@@ -175,6 +619,37 @@ func (m *Manager) SearchJSON(ctx context.Context, input *hub.SearchPackageInput)
 			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid repository name")
 		}
 	}
+	for _, capabilities := range input.Capabilities {
+		if !isValidCapabilities(capabilities) {
+			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid capabilities")
+		}
+	}
+	for _, chartType := range input.ChartTypes {
+		if !isValidChartType(chartType) {
+			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid chart type")
+		}
+	}
+	for _, tag := range input.Tags {
+		if foldKeyword(tag) == "" {
+			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid tag")
+		}
+	}
+	if input.KubernetesVersion != "" && !kubernetesVersionRE.MatchString(input.KubernetesVersion) {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid kubernetes version (major.minor expected)")
+	}
+	if input.Sort != "" && input.Sort != "relevance" && input.Sort != "capabilities" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid sort")
+	}
+
+	// Tags are normalized the same way as the packages keywords, so that any
+	// form of a tag matches the packages tagged with it
+	input.Tags = normalizeKeywords(input.Tags, m.keywordsAliases)
+
+	// Packages in private repositories are only returned to the users with
+	// read access to them
+	if userID, ok := ctx.Value(hub.UserIDKey).(string); ok {
+		input.UserID = userID
+	}
 
 	// Search packages in database
 	inputJSON, _ := json.Marshal(input)
@@ -217,6 +692,32 @@ func (m *Manager) Unregister(ctx context.Context, pkg *hub.Package) error {
 	return err
 }
 
+// UpdateLogoImage updates the logo image of the package provided, as long as
+// its logo url has not changed since the image was requested.
+func (m *Manager) UpdateLogoImage(ctx context.Context, pkg *hub.Package, logoImageID string) error {
+	// Validate input
+	if pkg.Name == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "name not provided")
+	}
+	if pkg.LogoURL == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "logo url not provided")
+	}
+	if pkg.Repository == nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "repository not provided")
+	}
+	if _, err := uuid.FromString(pkg.Repository.RepositoryID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid repository id")
+	}
+	if _, err := uuid.FromString(logoImageID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid logo image id")
+	}
+
+	// Update package logo image in database
+	query := "select update_package_logo_image($1::uuid, $2::text, $3::text, $4::uuid)"
+	_, err := m.db.Exec(ctx, query, pkg.Repository.RepositoryID, pkg.Name, pkg.LogoURL, logoImageID)
+	return err
+}
+
 // dbQueryJSON is a helper that executes the query provided and returns a bytes
 // slice containing the json data returned from the database.
 func (m *Manager) dbQueryJSON(ctx context.Context, query string, args ...interface{}) ([]byte, error) {
@@ -227,6 +728,21 @@ func (m *Manager) dbQueryJSON(ctx context.Context, query string, args ...interfa
 	return dataJSON, nil
 }
 
+// addLegacyData returns a copy of the package data provided that includes the
+// fields that used to be stored in it before being moved to a dedicated
+// column.
+func addLegacyData(pkg *hub.Package) map[string]interface{} {
+	if pkg.Capabilities == "" {
+		return pkg.Data
+	}
+	data := make(map[string]interface{}, len(pkg.Data)+1)
+	for k, v := range pkg.Data {
+		data[k] = v
+	}
+	data["capabilities"] = pkg.Capabilities
+	return data
+}
+
 // getUserID returns the user id from the context provided when available.
 func getUserID(ctx context.Context) *string {
 	var userID *string
@@ -236,3 +752,92 @@ func getUserID(ctx context.Context) *string {
 	}
 	return userID
 }
+
+// isAbsoluteURL checks if the url provided is an absolute url, including
+// both the scheme and the host.
+func isAbsoluteURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return u.Scheme != "" && u.Host != ""
+}
+
+// isValidCapabilities checks if the capabilities provided are one of the
+// operator capability levels supported.
+func isValidCapabilities(capabilities string) bool {
+	for _, c := range hub.OperatorCapabilities {
+		if capabilities == c {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidChartType checks if the chart type provided is one of the Helm chart
+// types supported.
+func isValidChartType(chartType string) bool {
+	for _, t := range hub.ChartTypes {
+		if chartType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidSupportedVersions checks if the supported versions constraint
+// provided is valid. It must be a comma separated list of comparisons using
+// one of the >=, >, <=, < and = operators, like ">=1.0.0, <2.0.0".
+func isValidSupportedVersions(constraint string) bool {
+	for _, comparison := range strings.Split(constraint, ",") {
+		parts := supportedVersionsComparisonRE.FindStringSubmatch(strings.TrimSpace(comparison))
+		if parts == nil {
+			return false
+		}
+		if _, err := semver.StrictNewVersion(parts[2]); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// isValidKubeVersion checks if the kubeVersion constraint provided is in its
+// normalized form: groups of comma separated >= and < comparisons joined by
+// ||, like '>=1.16.0, <1.20.0 || >=1.21.0'.
+func isValidKubeVersion(constraint string) bool {
+	for _, group := range strings.Split(constraint, "||") {
+		for _, comparison := range strings.Split(group, ",") {
+			parts := kubeVersionComparisonRE.FindStringSubmatch(strings.TrimSpace(comparison))
+			if parts == nil {
+				return false
+			}
+			if _, err := semver.StrictNewVersion(parts[2]); err != nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// truncateReadme truncates the readme of the package json data provided when
+// it is larger than the maximum number of bytes allowed. The readme is cut at
+// a valid utf-8 boundary and the rest of the package fields are left intact.
+func truncateReadme(dataJSON []byte, maxBytes int) ([]byte, error) {
+	var p map[string]json.RawMessage
+	if err := json.Unmarshal(dataJSON, &p); err != nil {
+		return nil, err
+	}
+	var readme string
+	if raw, ok := p["readme"]; ok {
+		_ = json.Unmarshal(raw, &readme)
+	}
+	if len(readme) <= maxBytes {
+		return dataJSON, nil
+	}
+	readme = readme[:maxBytes]
+	for len(readme) > 0 && !utf8.ValidString(readme) {
+		readme = readme[:len(readme)-1]
+	}
+	p["readme"], _ = json.Marshal(readme)
+	return json.Marshal(p)
+}
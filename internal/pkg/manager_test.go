@@ -2,11 +2,15 @@ package pkg
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strconv"
 	"testing"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/tests"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -192,6 +196,300 @@ func TestGet(t *testing.T) {
 	})
 }
 
+func TestGetAllJSON(t *testing.T) {
+	dbQuery := "select get_all_packages($1::jsonb)"
+	ctx := context.Background()
+	input := &hub.GetAllPackagesInput{
+		Cursor: "00000000-0000-0000-0000-000000000001",
+		Limit:  10,
+	}
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg string
+			input  *hub.GetAllPackagesInput
+		}{
+			{
+				"invalid limit (0 < l <= 100)",
+				&hub.GetAllPackagesInput{
+					Limit: 0,
+				},
+			},
+			{
+				"invalid limit (0 < l <= 100)",
+				&hub.GetAllPackagesInput{
+					Limit: 101,
+				},
+			},
+			{
+				"invalid cursor",
+				&hub.GetAllPackagesInput{
+					Cursor: "invalid",
+					Limit:  10,
+				},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil)
+				dataJSON, err := m.GetAllJSON(ctx, tc.input)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+				assert.Nil(t, dataJSON)
+			})
+		}
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, mock.Anything).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetAllJSON(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, mock.Anything).Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetAllJSON(ctx, input)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetByOwner(t *testing.T) {
+	dbQuery := "select get_packages_by_owner($1::uuid, $2::text)"
+	ctx := context.Background()
+
+	t.Run("invalid input", func(t *testing.T) {
+		m := NewManager(nil)
+		packages, err := m.GetByOwner(ctx, "")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Nil(t, packages)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, (*string)(nil), "org1").Return([]byte(`
+		[{
+			"package_id": "00000000-0000-0000-0000-000000000001",
+			"name": "package1",
+			"normalized_name": "package1",
+			"keywords": ["kw1"],
+			"version": "1.0.0",
+			"repository": {
+				"repository_id": "00000000-0000-0000-0000-000000000001",
+				"kind": 0,
+				"name": "repo1",
+				"organization_name": "org1"
+			}
+		}]
+		`), nil)
+		m := NewManager(db)
+
+		packages, err := m.GetByOwner(ctx, "org1")
+		assert.NoError(t, err)
+		assert.Equal(t, []*hub.Package{
+			{
+				PackageID:      "00000000-0000-0000-0000-000000000001",
+				Name:           "package1",
+				NormalizedName: "package1",
+				Keywords:       []string{"kw1"},
+				Version:        "1.0.0",
+				Repository: &hub.Repository{
+					RepositoryID:     "00000000-0000-0000-0000-000000000001",
+					Kind:             hub.Helm,
+					Name:             "repo1",
+					OrganizationName: "org1",
+				},
+			},
+		}, packages)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, (*string)(nil), "org1").Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		packages, err := m.GetByOwner(ctx, "org1")
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.Nil(t, packages)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("requesting user is provided to the database", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+		userID := "userID"
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, &userID, "org1").Return([]byte("[]"), nil)
+		m := NewManager(db)
+
+		packages, err := m.GetByOwner(ctx, "org1")
+		assert.NoError(t, err)
+		assert.Empty(t, packages)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetChangesJSON(t *testing.T) {
+	dbQuery := "select get_package_changes($1::jsonb)"
+	ctx := context.Background()
+	input := &hub.GetPackageChangesInput{
+		Since:  1592299234,
+		Cursor: 10,
+		Limit:  100,
+	}
+	inputJSON, _ := json.Marshal(input)
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg string
+			input  *hub.GetPackageChangesInput
+		}{
+			{
+				"invalid since",
+				&hub.GetPackageChangesInput{Since: -1, Limit: 100},
+			},
+			{
+				"invalid cursor",
+				&hub.GetPackageChangesInput{Cursor: -1, Limit: 100},
+			},
+			{
+				"invalid limit",
+				&hub.GetPackageChangesInput{Limit: 0},
+			},
+			{
+				"invalid limit",
+				&hub.GetPackageChangesInput{Limit: maxChangesLimit + 1},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil)
+				dataJSON, err := m.GetChangesJSON(ctx, tc.input)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+				assert.Nil(t, dataJSON)
+			})
+		}
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, inputJSON).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetChangesJSON(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, inputJSON).Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetChangesJSON(ctx, input)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetDependenciesGraphJSON(t *testing.T) {
+	dbQuery := "select get_package_dependencies_graph($1::jsonb)"
+	ctx := context.Background()
+	input := &hub.GetPackageInput{
+		PackageName:    "pkg1",
+		RepositoryName: "repo1",
+	}
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg string
+			input  *hub.GetPackageInput
+		}{
+			{
+				"package name not provided",
+				&hub.GetPackageInput{RepositoryName: "repo1"},
+			},
+			{
+				"repository name not provided",
+				&hub.GetPackageInput{PackageName: "pkg1"},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil)
+				dataJSON, err := m.GetDependenciesGraphJSON(ctx, tc.input)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+				assert.Nil(t, dataJSON)
+			})
+		}
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, mock.Anything).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetDependenciesGraphJSON(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("package not found", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, mock.Anything).Return(nil, pgx.ErrNoRows)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetDependenciesGraphJSON(ctx, input)
+		assert.Equal(t, hub.ErrNotFound, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, mock.Anything).Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetDependenciesGraphJSON(ctx, input)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("requesting user is provided to the database", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, mock.MatchedBy(func(inputJSON []byte) bool {
+			var input *hub.GetPackageInput
+			_ = json.Unmarshal(inputJSON, &input)
+			return input.UserID == "userID"
+		})).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetDependenciesGraphJSON(ctx, &hub.GetPackageInput{PackageName: "pkg1", RepositoryName: "repo1"})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
 func TestGetJSON(t *testing.T) {
 	dbQuery := "select get_package($1::jsonb)"
 	ctx := context.Background()
@@ -204,157 +502,673 @@ func TestGetJSON(t *testing.T) {
 
 	t.Run("database query succeeded", func(t *testing.T) {
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, dbQuery, mock.Anything, mock.Anything).Return([]byte("dataJSON"), nil)
+		db.On("QueryRow", ctx, dbQuery, mock.Anything, mock.Anything).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetJSON(ctx, &hub.GetPackageInput{PackageName: "pkg1"})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("requesting user is provided to the database", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, []byte(`{"package_id":"","repository_name":"","package_name":"pkg1","version":"","user_id":"userID"}`)).
+			Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetJSON(ctx, &hub.GetPackageInput{PackageName: "pkg1"})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("readme larger than the limit configured is truncated", func(t *testing.T) {
+		testCases := []struct {
+			readme         string
+			expectedReadme string
+		}{
+			{"readme", "readme"},
+			{"readme too long", "readme too"},
+			{"readme áé", "readme á"},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.readme, func(t *testing.T) {
+				dataJSON, _ := json.Marshal(map[string]interface{}{
+					"name":   "pkg1",
+					"readme": tc.readme,
+				})
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, dbQuery, mock.Anything, mock.Anything).Return(dataJSON, nil)
+				m := NewManager(db, WithLimits(&hub.Limits{
+					SearchMaxPageSize: 50,
+					ReadmeMaxBytes:    10,
+				}))
+
+				p, err := m.Get(ctx, &hub.GetPackageInput{PackageName: "pkg1"})
+				assert.NoError(t, err)
+				assert.Equal(t, "pkg1", p.Name)
+				assert.Equal(t, tc.expectedReadme, p.Readme)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, mock.Anything, mock.Anything).Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetJSON(ctx, &hub.GetPackageInput{PackageName: "pkg1"})
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetLatestVersionsJSON(t *testing.T) {
+	dbQuery := "select get_package_latest_versions($1::jsonb)"
+	ctx := context.Background()
+	input := &hub.GetPackageInput{
+		PackageName:    "pkg1",
+		RepositoryName: "repo1",
+	}
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg string
+			input  *hub.GetPackageInput
+		}{
+			{
+				"package name not provided",
+				&hub.GetPackageInput{RepositoryName: "repo1"},
+			},
+			{
+				"repository name not provided",
+				&hub.GetPackageInput{PackageName: "pkg1"},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil)
+				dataJSON, err := m.GetLatestVersionsJSON(ctx, tc.input)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+				assert.Nil(t, dataJSON)
+			})
+		}
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, mock.Anything).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetLatestVersionsJSON(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("package not found", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, mock.Anything).Return(nil, pgx.ErrNoRows)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetLatestVersionsJSON(ctx, input)
+		assert.Equal(t, hub.ErrNotFound, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, mock.Anything).Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetLatestVersionsJSON(ctx, input)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("requesting user is provided to the database", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, mock.MatchedBy(func(inputJSON []byte) bool {
+			var input *hub.GetPackageInput
+			_ = json.Unmarshal(inputJSON, &input)
+			return input.UserID == "userID"
+		})).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetLatestVersionsJSON(ctx, &hub.GetPackageInput{PackageName: "pkg1", RepositoryName: "repo1"})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetProjectJSON(t *testing.T) {
+	dbQuery := "select get_package_project($1::uuid, $2::uuid)"
+	ctx := context.Background()
+	pkgID := "00000000-0000-0000-0000-000000000001"
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg    string
+			packageID string
+		}{
+			{"package id not provided", ""},
+			{"invalid package id", "pkgID"},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil)
+				_, err := m.GetProjectJSON(ctx, tc.packageID)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, (*string)(nil), pkgID).Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		_, err := m.GetProjectJSON(ctx, pkgID)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, (*string)(nil), pkgID).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetProjectJSON(ctx, pkgID)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("requesting user is provided to the database", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+		userID := "userID"
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, &userID, pkgID).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetProjectJSON(ctx, pkgID)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetRandomJSON(t *testing.T) {
+	dbQuery := "select get_random_packages()"
+	ctx := context.Background()
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetRandomJSON(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery).Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetRandomJSON(ctx)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetStarredByUserJSON(t *testing.T) {
+	dbQuery := "select get_packages_starred_by_user($1::uuid)"
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetStarredByUserJSON(context.Background())
+		})
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "userID").Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetStarredByUserJSON(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "userID").Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetStarredByUserJSON(ctx)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetStarsJSON(t *testing.T) {
+	dbQuery := "select get_package_stars($1::uuid, $2::uuid)"
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+	pkgID := "00000000-0000-0000-0000-000000000001"
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg    string
+			packageID string
+		}{
+			{"package id not provided", ""},
+			{"invalid package id", "pkgID"},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil)
+				_, err := m.GetStarsJSON(ctx, tc.packageID)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, mock.Anything, pkgID).Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		_, err := m.GetStarsJSON(ctx, pkgID)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, mock.Anything, pkgID).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetStarsJSON(ctx, pkgID)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetStatsJSON(t *testing.T) {
+	dbQuery := "select get_packages_stats()"
+	ctx := context.Background()
+
+	t.Run("packages stats data returned successfully", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetStatsJSON(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery).Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetStatsJSON(ctx)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetTagsJSON(t *testing.T) {
+	dbQuery := "select get_packages_tags($1::int)"
+	ctx := context.Background()
+
+	t.Run("invalid input", func(t *testing.T) {
+		for _, limit := range []int{-1, 0, 501} {
+			limit := limit
+			t.Run(strconv.Itoa(limit), func(t *testing.T) {
+				m := NewManager(nil)
+				dataJSON, err := m.GetTagsJSON(ctx, limit)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Nil(t, dataJSON)
+			})
+		}
+	})
+
+	t.Run("tags data returned successfully", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, 100).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetTagsJSON(ctx, 100)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, 100).Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetTagsJSON(ctx, 100)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetVersionDocsJSON(t *testing.T) {
+	dbQuery := "select get_package_version_docs($1::jsonb)"
+	ctx := context.Background()
+	input := &hub.GetPackageInput{
+		PackageName:    "pkg1",
+		RepositoryName: "repo1",
+		Version:        "1.0.0",
+	}
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg string
+			input  *hub.GetPackageInput
+		}{
+			{
+				"package name not provided",
+				&hub.GetPackageInput{RepositoryName: "repo1"},
+			},
+			{
+				"repository name not provided",
+				&hub.GetPackageInput{PackageName: "pkg1"},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil)
+				dataJSON, err := m.GetVersionDocsJSON(ctx, tc.input)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+				assert.Nil(t, dataJSON)
+			})
+		}
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, mock.Anything).Return([]byte("dataJSON"), nil)
 		m := NewManager(db)
 
-		dataJSON, err := m.GetJSON(ctx, &hub.GetPackageInput{PackageName: "pkg1"})
+		dataJSON, err := m.GetVersionDocsJSON(ctx, input)
 		assert.NoError(t, err)
 		assert.Equal(t, []byte("dataJSON"), dataJSON)
 		db.AssertExpectations(t)
 	})
 
-	t.Run("database error", func(t *testing.T) {
+	t.Run("readme truncated when limit is set", func(t *testing.T) {
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, dbQuery, mock.Anything, mock.Anything).Return(nil, tests.ErrFakeDatabaseFailure)
-		m := NewManager(db)
+		db.On("QueryRow", ctx, dbQuery, mock.Anything).
+			Return([]byte(`{"version": "1.0.0", "readme": "0123456789", "changelog": "changelog"}`), nil)
+		m := NewManager(db, WithLimits(&hub.Limits{ReadmeMaxBytes: 4}))
 
-		dataJSON, err := m.GetJSON(ctx, &hub.GetPackageInput{PackageName: "pkg1"})
-		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
-		assert.Nil(t, dataJSON)
+		dataJSON, err := m.GetVersionDocsJSON(ctx, input)
+		assert.NoError(t, err)
+		var docs map[string]interface{}
+		assert.NoError(t, json.Unmarshal(dataJSON, &docs))
+		assert.Equal(t, "0123", docs["readme"])
+		assert.Equal(t, "changelog", docs["changelog"])
 		db.AssertExpectations(t)
 	})
-}
-
-func TestGetRandomJSON(t *testing.T) {
-	dbQuery := "select get_random_packages()"
-	ctx := context.Background()
 
-	t.Run("database query succeeded", func(t *testing.T) {
+	t.Run("package version not found", func(t *testing.T) {
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, dbQuery).Return([]byte("dataJSON"), nil)
+		db.On("QueryRow", ctx, dbQuery, mock.Anything).Return(nil, pgx.ErrNoRows)
 		m := NewManager(db)
 
-		dataJSON, err := m.GetRandomJSON(ctx)
-		assert.NoError(t, err)
-		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		dataJSON, err := m.GetVersionDocsJSON(ctx, input)
+		assert.Equal(t, hub.ErrNotFound, err)
+		assert.Nil(t, dataJSON)
 		db.AssertExpectations(t)
 	})
 
 	t.Run("database error", func(t *testing.T) {
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, dbQuery).Return(nil, tests.ErrFakeDatabaseFailure)
+		db.On("QueryRow", ctx, dbQuery, mock.Anything).Return(nil, tests.ErrFakeDatabaseFailure)
 		m := NewManager(db)
 
-		dataJSON, err := m.GetRandomJSON(ctx)
+		dataJSON, err := m.GetVersionDocsJSON(ctx, input)
 		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
 		assert.Nil(t, dataJSON)
 		db.AssertExpectations(t)
 	})
+
+	t.Run("requesting user is provided to the database", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, mock.MatchedBy(func(inputJSON []byte) bool {
+			var input *hub.GetPackageInput
+			_ = json.Unmarshal(inputJSON, &input)
+			return input.UserID == "userID"
+		})).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetVersionDocsJSON(ctx, &hub.GetPackageInput{PackageName: "pkg1", RepositoryName: "repo1"})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
 }
 
-func TestGetStarredByUserJSON(t *testing.T) {
-	dbQuery := "select get_packages_starred_by_user($1::uuid)"
-	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+func TestMatchInstalledJSON(t *testing.T) {
+	dbQuery := "select match_installed_packages($1::uuid, $2::jsonb)"
+	ctx := context.Background()
 
-	t.Run("user id not found in ctx", func(t *testing.T) {
-		m := NewManager(nil)
-		assert.Panics(t, func() {
-			_, _ = m.GetStarredByUserJSON(context.Background())
-		})
+	t.Run("invalid input", func(t *testing.T) {
+		tooManyReleases := make([]*hub.InstalledRelease, maxInstalledReleases+1)
+		for i := range tooManyReleases {
+			tooManyReleases[i] = &hub.InstalledRelease{
+				Name:          "pkg1",
+				Version:       "1.0.0",
+				RepositoryURL: "https://repo1.com",
+			}
+		}
+		testCases := []struct {
+			errMsg   string
+			releases []*hub.InstalledRelease
+		}{
+			{
+				"releases not provided",
+				nil,
+			},
+			{
+				"too many releases",
+				tooManyReleases,
+			},
+			{
+				"release name not provided",
+				[]*hub.InstalledRelease{{Version: "1.0.0", RepositoryURL: "https://repo1.com"}},
+			},
+			{
+				"invalid release version",
+				[]*hub.InstalledRelease{{Name: "pkg1", Version: "invalid", RepositoryURL: "https://repo1.com"}},
+			},
+			{
+				"invalid release repository url",
+				[]*hub.InstalledRelease{{Name: "pkg1", Version: "1.0.0", RepositoryURL: "repo1.com"}},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil)
+				dataJSON, err := m.MatchInstalledJSON(ctx, tc.releases)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+				assert.Nil(t, dataJSON)
+			})
+		}
 	})
 
 	t.Run("database query succeeded", func(t *testing.T) {
+		releases := []*hub.InstalledRelease{
+			{Name: "pkg1", Version: "v1.0.0", RepositoryURL: "https://repo1.com"},
+		}
+		expectedInput := `[{"name":"pkg1","version":"1.0.0","repository_url":"https://repo1.com"}]`
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, dbQuery, "userID").Return([]byte("dataJSON"), nil)
+		db.On("QueryRow", ctx, dbQuery, (*string)(nil), []byte(expectedInput)).Return([]byte("dataJSON"), nil)
 		m := NewManager(db)
 
-		dataJSON, err := m.GetStarredByUserJSON(ctx)
+		dataJSON, err := m.MatchInstalledJSON(ctx, releases)
 		assert.NoError(t, err)
 		assert.Equal(t, []byte("dataJSON"), dataJSON)
 		db.AssertExpectations(t)
 	})
 
 	t.Run("database error", func(t *testing.T) {
+		releases := []*hub.InstalledRelease{
+			{Name: "pkg1", Version: "1.0.0", RepositoryURL: "https://repo1.com"},
+		}
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, dbQuery, "userID").Return(nil, tests.ErrFakeDatabaseFailure)
+		db.On("QueryRow", ctx, dbQuery, mock.Anything, mock.Anything).Return(nil, tests.ErrFakeDatabaseFailure)
 		m := NewManager(db)
 
-		dataJSON, err := m.GetStarredByUserJSON(ctx)
+		dataJSON, err := m.MatchInstalledJSON(ctx, releases)
 		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
 		assert.Nil(t, dataJSON)
 		db.AssertExpectations(t)
 	})
+
+	t.Run("requesting user is provided to the database", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+		userID := "userID"
+		releases := []*hub.InstalledRelease{
+			{Name: "pkg1", Version: "1.0.0", RepositoryURL: "https://repo1.com"},
+		}
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, &userID, mock.Anything).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.MatchInstalledJSON(ctx, releases)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
 }
 
-func TestGetStarsJSON(t *testing.T) {
-	dbQuery := "select get_package_stars($1::uuid, $2::uuid)"
+func TestPush(t *testing.T) {
+	dbQuery := "select push_package($1::uuid, $2::uuid, $3::jsonb)"
 	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
-	pkgID := "00000000-0000-0000-0000-000000000001"
+	ctx = context.WithValue(ctx, hub.RepositoryIDKey, "repositoryID")
+	md := &hub.PackageMetadata{
+		Version:     "1.0.0",
+		Name:        "package1",
+		DisplayName: "Package 1",
+		CreatedAt:   "2020-06-16T11:20:34Z",
+		Description: "description",
+		Keywords:    []string{"kw1", "kw2"},
+	}
+	pkgJSON, _ := json.Marshal(&hub.Package{
+		Name:        "package1",
+		DisplayName: "Package 1",
+		Description: "description",
+		Keywords:    []string{"kw1", "kw2"},
+		Version:     "1.0.0",
+		CreatedAt:   1592306434,
+	})
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_ = m.Push(context.Background(), md)
+		})
+	})
 
 	t.Run("invalid input", func(t *testing.T) {
 		testCases := []struct {
-			errMsg    string
-			packageID string
+			errMsg string
+			md     *hub.PackageMetadata
 		}{
-			{"package id not provided", ""},
-			{"invalid package id", "pkgID"},
+			{
+				"metadata not provided",
+				nil,
+			},
+			{
+				"version not provided",
+				&hub.PackageMetadata{},
+			},
+			{
+				"description not provided",
+				&hub.PackageMetadata{
+					Version:     "1.0.0",
+					Name:        "package1",
+					DisplayName: "Package 1",
+					CreatedAt:   "2020-06-16T11:20:34Z",
+				},
+			},
 		}
 		for _, tc := range testCases {
 			tc := tc
 			t.Run(tc.errMsg, func(t *testing.T) {
 				m := NewManager(nil)
-				_, err := m.GetStarsJSON(ctx, tc.packageID)
+				err := m.Push(ctx, tc.md)
 				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
 				assert.Contains(t, err.Error(), tc.errMsg)
 			})
 		}
 	})
 
-	t.Run("database error", func(t *testing.T) {
+	t.Run("user does not have write access to the repository", func(t *testing.T) {
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, dbQuery, mock.Anything, pkgID).Return(nil, tests.ErrFakeDatabaseFailure)
+		db.On("Exec", ctx, dbQuery, "userID", "repositoryID", pkgJSON).Return(util.ErrDBInsufficientPrivilege)
 		m := NewManager(db)
 
-		_, err := m.GetStarsJSON(ctx, pkgID)
-		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		err := m.Push(ctx, md)
+		assert.Equal(t, hub.ErrInsufficientPrivilege, err)
 		db.AssertExpectations(t)
 	})
 
-	t.Run("database query succeeded", func(t *testing.T) {
+	t.Run("database error", func(t *testing.T) {
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, dbQuery, mock.Anything, pkgID).Return([]byte("dataJSON"), nil)
+		db.On("Exec", ctx, dbQuery, "userID", "repositoryID", pkgJSON).Return(tests.ErrFakeDatabaseFailure)
 		m := NewManager(db)
 
-		dataJSON, err := m.GetStarsJSON(ctx, pkgID)
-		assert.NoError(t, err)
-		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		err := m.Push(ctx, md)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
 		db.AssertExpectations(t)
 	})
-}
-
-func TestGetStatsJSON(t *testing.T) {
-	dbQuery := "select get_packages_stats()"
-	ctx := context.Background()
 
-	t.Run("packages stats data returned successfully", func(t *testing.T) {
+	t.Run("push succeeded", func(t *testing.T) {
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, dbQuery).Return([]byte("dataJSON"), nil)
+		db.On("Exec", ctx, dbQuery, "userID", "repositoryID", pkgJSON).Return(nil)
 		m := NewManager(db)
 
-		dataJSON, err := m.GetStatsJSON(ctx)
+		err := m.Push(ctx, md)
 		assert.NoError(t, err)
-		assert.Equal(t, []byte("dataJSON"), dataJSON)
-		db.AssertExpectations(t)
-	})
-
-	t.Run("database error", func(t *testing.T) {
-		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, dbQuery).Return(nil, tests.ErrFakeDatabaseFailure)
-		m := NewManager(db)
-
-		dataJSON, err := m.GetStatsJSON(ctx)
-		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
-		assert.Nil(t, dataJSON)
 		db.AssertExpectations(t)
 	})
 }
@@ -376,9 +1190,24 @@ func TestRegister(t *testing.T) {
 				URL:  "source_url",
 			},
 		},
-		Version:    "1.0.0",
-		AppVersion: "12.1.0",
-		Digest:     "digest-package1-1.0.0",
+		Version:     "1.0.0",
+		AppVersion:  "12.1.0",
+		KubeVersion: ">=1.16.0, <1.17.0 || >=1.18.0",
+		ChartType:   "application",
+		Digest:      "digest-package1-1.0.0",
+		Signed:      true,
+		SignKey: &hub.SignKey{
+			KeyID:       "34365D9472D7468F",
+			Fingerprint: "C874011F0AB405110D02105534365D9472D7468F",
+			URL:         "https://keybase.io/user1/pgp_keys.asc",
+		},
+		Maintenance: &hub.Maintenance{
+			SupportedVersions: ">=1.0.0, <2.0.0",
+			EOL:               "2021-06-30",
+		},
+		ContainersImages: []*hub.ContainerImage{
+			{Image: "quay.io/org/img:1.0.0"},
+		},
 		Maintainers: []*hub.Maintainer{
 			{
 				Name:  "name1",
@@ -432,83 +1261,182 @@ func TestRegister(t *testing.T) {
 				},
 			},
 			{
-				"repository id not provided",
+				"repository id not provided",
+				&hub.Package{
+					Name:    "package1",
+					Version: "1.0.0",
+					Repository: &hub.Repository{
+						RepositoryID: "",
+					},
+				},
+			},
+			{
+				"invalid repository id",
+				&hub.Package{
+					Name:    "package1",
+					Version: "1.0.0",
+					Repository: &hub.Repository{
+						RepositoryID: "invalid",
+					},
+				},
+			},
+			{
+				"maintainer email not provided",
+				&hub.Package{
+					Name:    "package1",
+					Version: "1.0.0",
+					Repository: &hub.Repository{
+						RepositoryID: "00000000-0000-0000-0000-000000000001",
+					},
+					Maintainers: []*hub.Maintainer{
+						{
+							Name: "name",
+						},
+					},
+				},
+			},
+			{
+				"channel name not provided",
+				&hub.Package{
+					Name:    "package1",
+					Version: "1.0.0",
+					Repository: &hub.Repository{
+						RepositoryID: "00000000-0000-0000-0000-000000000001",
+					},
+					Channels: []*hub.Channel{
+						{
+							Version: "1.0.0",
+						},
+					},
+				},
+			},
+			{
+				"channel version not provided",
+				&hub.Package{
+					Name:    "package1",
+					Version: "1.0.0",
+					Repository: &hub.Repository{
+						RepositoryID: "00000000-0000-0000-0000-000000000001",
+					},
+					Channels: []*hub.Channel{
+						{
+							Name: "stable",
+						},
+					},
+				},
+			},
+			{
+				"invalid channel version (semver expected)",
+				&hub.Package{
+					Name:    "package1",
+					Version: "1.0.0",
+					Repository: &hub.Repository{
+						RepositoryID: "00000000-0000-0000-0000-000000000001",
+					},
+					Channels: []*hub.Channel{
+						{
+							Name:    "stable",
+							Version: "invalid",
+						},
+					},
+				},
+			},
+			{
+				"invalid maintenance eol (YYYY-MM-DD expected)",
+				&hub.Package{
+					Name:    "package1",
+					Version: "1.0.0",
+					Repository: &hub.Repository{
+						RepositoryID: "00000000-0000-0000-0000-000000000001",
+					},
+					Maintenance: &hub.Maintenance{
+						EOL: "30/06/2021",
+					},
+				},
+			},
+			{
+				"invalid capabilities",
+				&hub.Package{
+					Name:    "package1",
+					Version: "1.0.0",
+					Repository: &hub.Repository{
+						RepositoryID: "00000000-0000-0000-0000-000000000001",
+					},
+					Capabilities: "Unknown",
+				},
+			},
+			{
+				"container image not provided",
 				&hub.Package{
 					Name:    "package1",
 					Version: "1.0.0",
 					Repository: &hub.Repository{
-						RepositoryID: "",
+						RepositoryID: "00000000-0000-0000-0000-000000000001",
+					},
+					ContainersImages: []*hub.ContainerImage{
+						{Image: ""},
 					},
 				},
 			},
 			{
-				"invalid repository id",
+				"invalid chart type",
 				&hub.Package{
 					Name:    "package1",
 					Version: "1.0.0",
 					Repository: &hub.Repository{
-						RepositoryID: "invalid",
+						RepositoryID: "00000000-0000-0000-0000-000000000001",
 					},
+					ChartType: "plugin",
 				},
 			},
 			{
-				"maintainer email not provided",
+				"sign key id not provided",
 				&hub.Package{
 					Name:    "package1",
 					Version: "1.0.0",
 					Repository: &hub.Repository{
 						RepositoryID: "00000000-0000-0000-0000-000000000001",
 					},
-					Maintainers: []*hub.Maintainer{
-						{
-							Name: "name",
-						},
-					},
+					Signed:  true,
+					SignKey: &hub.SignKey{},
 				},
 			},
 			{
-				"channel name not provided",
+				"invalid sign key url",
 				&hub.Package{
 					Name:    "package1",
 					Version: "1.0.0",
 					Repository: &hub.Repository{
 						RepositoryID: "00000000-0000-0000-0000-000000000001",
 					},
-					Channels: []*hub.Channel{
-						{
-							Version: "1.0.0",
-						},
+					Signed: true,
+					SignKey: &hub.SignKey{
+						KeyID: "34365D9472D7468F",
+						URL:   "keys/pgp_keys.asc",
 					},
 				},
 			},
 			{
-				"channel version not provided",
+				"invalid kube version",
 				&hub.Package{
 					Name:    "package1",
 					Version: "1.0.0",
 					Repository: &hub.Repository{
 						RepositoryID: "00000000-0000-0000-0000-000000000001",
 					},
-					Channels: []*hub.Channel{
-						{
-							Name: "stable",
-						},
-					},
+					KubeVersion: ">=1.16.0 <=1.18",
 				},
 			},
 			{
-				"invalid channel version (semver expected)",
+				"invalid maintenance supported versions",
 				&hub.Package{
 					Name:    "package1",
 					Version: "1.0.0",
 					Repository: &hub.Repository{
 						RepositoryID: "00000000-0000-0000-0000-000000000001",
 					},
-					Channels: []*hub.Channel{
-						{
-							Name:    "stable",
-							Version: "invalid",
-						},
+					Maintenance: &hub.Maintenance{
+						SupportedVersions: ">=1.0.0, ~2.0",
 					},
 				},
 			},
@@ -534,6 +1462,33 @@ func TestRegister(t *testing.T) {
 		db.AssertExpectations(t)
 	})
 
+	t.Run("successful package registration in dual write mode", func(t *testing.T) {
+		p := &hub.Package{
+			Name:         "package1",
+			Version:      "1.0.0",
+			Capabilities: "Basic Install",
+			Data: map[string]interface{}{
+				"isGlobalOperator": true,
+			},
+			Repository: &hub.Repository{
+				RepositoryID: "00000000-0000-0000-0000-000000000001",
+			},
+		}
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, mock.MatchedBy(func(pkgJSON []byte) bool {
+			var pkg *hub.Package
+			_ = json.Unmarshal(pkgJSON, &pkg)
+			return pkg.Capabilities == "Basic Install" &&
+				pkg.Data["capabilities"] == "Basic Install" &&
+				pkg.Data["isGlobalOperator"] == true
+		})).Return(nil)
+		m := NewManager(db, WithDualWrite())
+
+		err := m.Register(ctx, p)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
 	t.Run("database error", func(t *testing.T) {
 		db := &tests.DBMock{}
 		db.On("Exec", ctx, dbQuery, mock.Anything).Return(tests.ErrFakeDatabaseFailure)
@@ -545,6 +1500,172 @@ func TestRegister(t *testing.T) {
 	})
 }
 
+func TestResolve(t *testing.T) {
+	dbQuery := "select resolve_packages($1::jsonb)"
+	ctx := context.Background()
+	input := &hub.ResolvePackageInput{
+		SourceURL: "https://github.com/org1/package1",
+	}
+
+	t.Run("invalid input", func(t *testing.T) {
+		invalidKind := hub.RepositoryKind(9)
+		testCases := []struct {
+			errMsg string
+			input  *hub.ResolvePackageInput
+		}{
+			{
+				"invalid repository kind",
+				&hub.ResolvePackageInput{
+					RepositoryKind: &invalidKind,
+					SourceURL:      "https://github.com/org1/package1",
+				},
+			},
+			{
+				"repository url or source url not provided",
+				&hub.ResolvePackageInput{
+					PackageName: "package1",
+				},
+			},
+			{
+				"repository url and source url cannot be used together",
+				&hub.ResolvePackageInput{
+					RepositoryURL: "https://repo1.com",
+					PackageName:   "package1",
+					SourceURL:     "https://github.com/org1/package1",
+				},
+			},
+			{
+				"invalid repository url",
+				&hub.ResolvePackageInput{
+					RepositoryURL: "repo1.com",
+					PackageName:   "package1",
+				},
+			},
+			{
+				"package name not provided",
+				&hub.ResolvePackageInput{
+					RepositoryURL: "https://repo1.com",
+				},
+			},
+			{
+				"invalid source url",
+				&hub.ResolvePackageInput{
+					SourceURL: "/org1/package1",
+				},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil)
+				packages, err := m.Resolve(ctx, tc.input)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+				assert.Nil(t, packages)
+			})
+		}
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, mock.Anything).Return([]byte(`
+		[{
+			"package_id": "00000000-0000-0000-0000-000000000001",
+			"name": "package1",
+			"normalized_name": "package1",
+			"version": "1.0.0",
+			"repository": {
+				"repository_id": "00000000-0000-0000-0000-000000000001",
+				"kind": 0,
+				"name": "repo1",
+				"url": "https://repo1.com",
+				"organization_name": "org1"
+			}
+		}]
+		`), nil)
+		m := NewManager(db)
+
+		packages, err := m.Resolve(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, []*hub.Package{
+			{
+				PackageID:      "00000000-0000-0000-0000-000000000001",
+				Name:           "package1",
+				NormalizedName: "package1",
+				Version:        "1.0.0",
+				Repository: &hub.Repository{
+					RepositoryID:     "00000000-0000-0000-0000-000000000001",
+					Kind:             hub.Helm,
+					Name:             "repo1",
+					URL:              "https://repo1.com",
+					OrganizationName: "org1",
+				},
+			},
+		}, packages)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, mock.Anything).Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		packages, err := m.Resolve(ctx, input)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.Nil(t, packages)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("requesting user is provided to the database", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, mock.MatchedBy(func(inputJSON []byte) bool {
+			var input *hub.ResolvePackageInput
+			_ = json.Unmarshal(inputJSON, &input)
+			return input.UserID == "userID"
+		})).Return([]byte("[]"), nil)
+		m := NewManager(db)
+
+		packages, err := m.Resolve(ctx, &hub.ResolvePackageInput{SourceURL: "https://github.com/org1/pkg1"})
+		assert.NoError(t, err)
+		assert.Empty(t, packages)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestRestoreUnregistered(t *testing.T) {
+	dbQuery := "select restore_unregistered_packages($1::text, nullif($2::text, ''))"
+	ctx := context.Background()
+
+	t.Run("invalid input", func(t *testing.T) {
+		m := NewManager(nil)
+		_, err := m.RestoreUnregistered(ctx, "", "pkg1")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "repository name not provided")
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "repo1", "").Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		_, err := m.RestoreUnregistered(ctx, "repo1", "")
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("packages versions restored successfully", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "repo1", "pkg1").Return(int64(3), nil)
+		m := NewManager(db)
+
+		n, err := m.RestoreUnregistered(ctx, "repo1", "pkg1")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), n)
+		db.AssertExpectations(t)
+	})
+}
+
 func TestSearchJSON(t *testing.T) {
 	dbQuery := "select search_packages($1::jsonb)"
 	ctx := context.Background()
@@ -604,6 +1725,41 @@ func TestSearchJSON(t *testing.T) {
 					Repositories: []string{""},
 				},
 			},
+			{
+				"invalid capabilities",
+				&hub.SearchPackageInput{
+					Limit:        10,
+					Capabilities: []string{"Unknown"},
+				},
+			},
+			{
+				"invalid chart type",
+				&hub.SearchPackageInput{
+					Limit:      10,
+					ChartTypes: []string{"application", "plugin"},
+				},
+			},
+			{
+				"invalid tag",
+				&hub.SearchPackageInput{
+					Limit: 10,
+					Tags:  []string{"monitoring", " "},
+				},
+			},
+			{
+				"invalid kubernetes version",
+				&hub.SearchPackageInput{
+					Limit:             10,
+					KubernetesVersion: "1.19.3",
+				},
+			},
+			{
+				"invalid sort",
+				&hub.SearchPackageInput{
+					Limit: 10,
+					Sort:  "stars",
+				},
+			},
 		}
 		for _, tc := range testCases {
 			tc := tc
@@ -617,6 +1773,22 @@ func TestSearchJSON(t *testing.T) {
 		}
 	})
 
+	t.Run("search max page size limit overridden", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, mock.Anything).Return([]byte("dataJSON"), nil)
+		m := NewManager(db, WithLimits(&hub.Limits{SearchMaxPageSize: 5}))
+
+		dataJSON, err := m.SearchJSON(ctx, input)
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "invalid limit (0 < l <= 5)")
+		assert.Nil(t, dataJSON)
+
+		dataJSON, err = m.SearchJSON(ctx, &hub.SearchPackageInput{Limit: 5})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
 	t.Run("database query succeeded", func(t *testing.T) {
 		db := &tests.DBMock{}
 		db.On("QueryRow", ctx, dbQuery, mock.Anything).Return([]byte("dataJSON"), nil)
@@ -628,6 +1800,40 @@ func TestSearchJSON(t *testing.T) {
 		db.AssertExpectations(t)
 	})
 
+	t.Run("tags are normalized", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, mock.MatchedBy(func(inputJSON []byte) bool {
+			var input *hub.SearchPackageInput
+			_ = json.Unmarshal(inputJSON, &input)
+			return assert.ObjectsAreEqual([]string{"kubernetes", "database"}, input.Tags)
+		})).Return([]byte("dataJSON"), nil)
+		m := NewManager(db, WithKeywordsAliases(map[string]string{"k8s": "kubernetes"}))
+
+		dataJSON, err := m.SearchJSON(ctx, &hub.SearchPackageInput{
+			Limit: 10,
+			Tags:  []string{"K8s", "Databases", "database"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("requesting user is provided to the database", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, mock.MatchedBy(func(inputJSON []byte) bool {
+			var input *hub.SearchPackageInput
+			_ = json.Unmarshal(inputJSON, &input)
+			return input.UserID == "userID"
+		})).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.SearchJSON(ctx, &hub.SearchPackageInput{Limit: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
 	t.Run("database error", func(t *testing.T) {
 		db := &tests.DBMock{}
 		db.On("QueryRow", ctx, dbQuery, mock.Anything).Return(nil, tests.ErrFakeDatabaseFailure)
@@ -758,3 +1964,92 @@ func TestUnregister(t *testing.T) {
 		db.AssertExpectations(t)
 	})
 }
+
+func TestUpdateLogoImage(t *testing.T) {
+	dbQuery := "select update_package_logo_image($1::uuid, $2::text, $3::text, $4::uuid)"
+	ctx := context.Background()
+
+	p := &hub.Package{
+		Name:    "package1",
+		LogoURL: "https://logo.url",
+		Repository: &hub.Repository{
+			RepositoryID: "00000000-0000-0000-0000-000000000001",
+		},
+	}
+	logoImageID := "00000000-0000-0000-0000-000000000002"
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg      string
+			p           *hub.Package
+			logoImageID string
+		}{
+			{
+				"name not provided",
+				&hub.Package{},
+				logoImageID,
+			},
+			{
+				"logo url not provided",
+				&hub.Package{
+					Name: "package1",
+				},
+				logoImageID,
+			},
+			{
+				"repository not provided",
+				&hub.Package{
+					Name:    "package1",
+					LogoURL: "https://logo.url",
+				},
+				logoImageID,
+			},
+			{
+				"invalid repository id",
+				&hub.Package{
+					Name:    "package1",
+					LogoURL: "https://logo.url",
+					Repository: &hub.Repository{
+						RepositoryID: "invalid",
+					},
+				},
+				logoImageID,
+			},
+			{
+				"invalid logo image id",
+				p,
+				"invalid",
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil)
+				err := m.UpdateLogoImage(ctx, tc.p, tc.logoImageID)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("successful logo image update", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, p.Repository.RepositoryID, p.Name, p.LogoURL, logoImageID).Return(nil)
+		m := NewManager(db)
+
+		err := m.UpdateLogoImage(ctx, p, logoImageID)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, p.Repository.RepositoryID, p.Name, p.LogoURL, logoImageID).
+			Return(tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		err := m.UpdateLogoImage(ctx, p, logoImageID)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		db.AssertExpectations(t)
+	})
+}
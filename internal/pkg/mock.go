@@ -19,6 +19,37 @@ func (m *ManagerMock) Get(ctx context.Context, input *hub.GetPackageInput) (*hub
 	return data, args.Error(1)
 }
 
+// GetAllJSON implements the PackageManager interface.
+func (m *ManagerMock) GetAllJSON(ctx context.Context, input *hub.GetAllPackagesInput) ([]byte, error) {
+	args := m.Called(ctx, input)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+// GetByOwner implements the PackageManager interface.
+func (m *ManagerMock) GetByOwner(ctx context.Context, owner string) ([]*hub.Package, error) {
+	args := m.Called(ctx, owner)
+	data, _ := args.Get(0).([]*hub.Package)
+	return data, args.Error(1)
+}
+
+// GetChangesJSON implements the PackageManager interface.
+func (m *ManagerMock) GetChangesJSON(
+	ctx context.Context,
+	input *hub.GetPackageChangesInput,
+) ([]byte, error) {
+	args := m.Called(ctx, input)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+// GetDependenciesGraphJSON implements the PackageManager interface.
+func (m *ManagerMock) GetDependenciesGraphJSON(ctx context.Context, input *hub.GetPackageInput) ([]byte, error) {
+	args := m.Called(ctx, input)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
 // GetJSON implements the PackageManager interface.
 func (m *ManagerMock) GetJSON(ctx context.Context, input *hub.GetPackageInput) ([]byte, error) {
 	args := m.Called(ctx, input)
@@ -26,6 +57,20 @@ func (m *ManagerMock) GetJSON(ctx context.Context, input *hub.GetPackageInput) (
 	return data, args.Error(1)
 }
 
+// GetLatestVersionsJSON implements the PackageManager interface.
+func (m *ManagerMock) GetLatestVersionsJSON(ctx context.Context, input *hub.GetPackageInput) ([]byte, error) {
+	args := m.Called(ctx, input)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+// GetProjectJSON implements the PackageManager interface.
+func (m *ManagerMock) GetProjectJSON(ctx context.Context, packageID string) ([]byte, error) {
+	args := m.Called(ctx, packageID)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
 // GetRandomJSON implements the PackageManager interface.
 func (m *ManagerMock) GetRandomJSON(ctx context.Context) ([]byte, error) {
 	args := m.Called(ctx)
@@ -54,15 +99,49 @@ func (m *ManagerMock) GetStatsJSON(ctx context.Context) ([]byte, error) {
 	return data, args.Error(1)
 }
 
+// GetTagsJSON implements the PackageManager interface.
+func (m *ManagerMock) GetTagsJSON(ctx context.Context, limit int) ([]byte, error) {
+	args := m.Called(ctx, limit)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+// GetVersionDocsJSON implements the PackageManager interface.
+func (m *ManagerMock) GetVersionDocsJSON(ctx context.Context, input *hub.GetPackageInput) ([]byte, error) {
+	args := m.Called(ctx, input)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+// MatchInstalledJSON implements the PackageManager interface.
+func (m *ManagerMock) MatchInstalledJSON(ctx context.Context, releases []*hub.InstalledRelease) ([]byte, error) {
+	args := m.Called(ctx, releases)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+// Push implements the PackageManager interface.
+func (m *ManagerMock) Push(ctx context.Context, md *hub.PackageMetadata) error {
+	args := m.Called(ctx, md)
+	return args.Error(0)
+}
+
 // Register implements the PackageManager interface.
 func (m *ManagerMock) Register(ctx context.Context, pkg *hub.Package) error {
 	args := m.Called(ctx, pkg)
 	return args.Error(0)
 }
 
+// Resolve implements the PackageManager interface.
+func (m *ManagerMock) Resolve(ctx context.Context, input *hub.ResolvePackageInput) ([]*hub.Package, error) {
+	args := m.Called(ctx, input)
+	data, _ := args.Get(0).([]*hub.Package)
+	return data, args.Error(1)
+}
+
 // SearchJSON implements the PackageManager interface.
 func (m *ManagerMock) SearchJSON(ctx context.Context, input *hub.SearchPackageInput) ([]byte, error) {
-	args := m.Called(ctx)
+	args := m.Called(ctx, input)
 	data, _ := args.Get(0).([]byte)
 	return data, args.Error(1)
 }
@@ -78,3 +157,9 @@ func (m *ManagerMock) Unregister(ctx context.Context, pkg *hub.Package) error {
 	args := m.Called(ctx, pkg)
 	return args.Error(0)
 }
+
+// UpdateLogoImage implements the PackageManager interface.
+func (m *ManagerMock) UpdateLogoImage(ctx context.Context, pkg *hub.Package, logoImageID string) error {
+	args := m.Called(ctx, pkg, logoImageID)
+	return args.Error(0)
+}
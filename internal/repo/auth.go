@@ -0,0 +1,48 @@
+package repo
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+// KeepSecret represents a special value that can be provided as the password
+// or the tls client key of a repository when updating it to keep the one
+// already stored.
+const KeepSecret = "="
+
+// RequestSetupFunc represents a function used to prepare the http requests
+// sent to a repository before they are sent (i.e. adding some headers or the
+// repository credentials).
+type RequestSetupFunc func(req *http.Request, r *hub.Repository)
+
+// HasCredentials checks if the repository provided has some credentials set.
+func HasCredentials(r *hub.Repository) bool {
+	return r.AuthUser != "" || r.AuthPass != ""
+}
+
+// SetupAuth adds the credentials of the repository provided, if any, to the
+// http request given. Basic authentication is used when a user is set, and the
+// password is sent as a bearer token otherwise. Credentials are only sent to
+// the repository's host, using the same scheme as the repository url, so they
+// are not leaked to third parties (i.e. when the logo of a chart is hosted
+// somewhere else).
+func SetupAuth(req *http.Request, r *hub.Repository) {
+	if r == nil || !HasCredentials(r) {
+		return
+	}
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return
+	}
+	if req.URL.Scheme != u.Scheme || !strings.EqualFold(req.URL.Host, u.Host) {
+		return
+	}
+	if r.AuthUser != "" {
+		req.SetBasicAuth(r.AuthUser, r.AuthPass)
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+r.AuthPass)
+}
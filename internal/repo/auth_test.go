@@ -0,0 +1,56 @@
+package repo
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetupAuth(t *testing.T) {
+	testCases := []struct {
+		r                     *hub.Repository
+		u                     string
+		expectedAuthorization string
+	}{
+		{
+			nil,
+			"https://repo1.com/index.yaml",
+			"",
+		},
+		{
+			&hub.Repository{URL: "https://repo1.com"},
+			"https://repo1.com/index.yaml",
+			"",
+		},
+		{
+			&hub.Repository{URL: "https://repo1.com", AuthUser: "user1", AuthPass: "pass1"},
+			"https://repo1.com/index.yaml",
+			"Basic dXNlcjE6cGFzczE=",
+		},
+		{
+			&hub.Repository{URL: "https://repo1.com/charts", AuthPass: "token1"},
+			"https://REPO1.com/charts/pkg1-1.0.0.tgz",
+			"Bearer token1",
+		},
+		{
+			&hub.Repository{URL: "https://repo1.com", AuthUser: "user1", AuthPass: "pass1"},
+			"https://logos.com/pkg1.png",
+			"",
+		},
+		{
+			&hub.Repository{URL: "https://repo1.com", AuthUser: "user1", AuthPass: "pass1"},
+			"http://repo1.com/index.yaml",
+			"",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.u, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tc.u, nil)
+			SetupAuth(req, tc.r)
+			assert.Equal(t, tc.expectedAuthorization, req.Header.Get("Authorization"))
+		})
+	}
+}
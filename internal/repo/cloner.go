@@ -4,18 +4,59 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"os"
 	"strings"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 )
 
 // Cloner is a hub.RepositoryCloner implementation.
-type Cloner struct{}
+type Cloner struct {
+	gc           *GitCache
+	setupRequest RequestSetupFunc
+}
+
+// NewCloner creates a new Cloner instance.
+func NewCloner(opts ...func(c *Cloner)) *Cloner {
+	c := &Cloner{}
+	for _, o := range opts {
+		o(c)
+	}
+	return c
+}
+
+// WithGitCache allows providing a git cache to a Cloner instance, which will
+// be used to keep up to date shallow clones of the git based repositories
+// between tracker runs instead of cloning them from scratch every time.
+func WithGitCache(gc *GitCache) func(c *Cloner) {
+	return func(c *Cloner) {
+		c.gc = gc
+	}
+}
+
+// WithClonerRequestSetup allows providing a function to a Cloner instance
+// that will be used to prepare all the http requests sent when cloning or
+// fetching git repositories over http(s).
+func WithClonerRequestSetup(f RequestSetupFunc) func(c *Cloner) {
+	return func(c *Cloner) {
+		c.setupRequest = f
+	}
+}
 
-// CloneRepository implements the hub.RepositoryCloner interface.
+// CloneRepository implements the hub.RepositoryCloner interface. Repositories
+// located in the local file system (file:// urls) are copied into a temporary
+// directory instead, so that they can be cleaned up like the cloned ones. Git
+// based repositories are cloned from the branch or the latest tag matching
+// the pattern they define (master by default), using the git cache when one
+// has been provided.
 func (c *Cloner) CloneRepository(ctx context.Context, r *hub.Repository) (string, string, error) {
+	if IsLocal(r.URL) {
+		return copyLocalRepository(r.URL)
+	}
+
 	// Parse repository url
 	var repoBaseURL, packagesPath string
 	switch r.Kind {
@@ -27,9 +68,11 @@ func (c *Cloner) CloneRepository(ctx context.Context, r *hub.Repository) (string
 		if len(matches) >= 3 {
 			repoBaseURL = matches[1]
 		}
-		if len(matches) == 4 {
-			packagesPath = strings.TrimSuffix(matches[3], "/")
-		}
+		packagesPath = GitPackagesPath(r)
+	}
+	refName, err := gitReference(repoBaseURL, r)
+	if err != nil {
+		return "", "", err
 	}
 
 	// Clone git repository
@@ -37,15 +80,89 @@ func (c *Cloner) CloneRepository(ctx context.Context, r *hub.Repository) (string
 	if err != nil {
 		return "", "", fmt.Errorf("error creating temp dir: %w", err)
 	}
-	_, err = git.PlainCloneContext(ctx, tmpDir, false, &git.CloneOptions{
-		URL:           repoBaseURL,
-		ReferenceName: plumbing.NewBranchReferenceName("master"),
-		SingleBranch:  true,
-		Depth:         1,
-	})
+	auth := c.gitAuth(repoBaseURL, r)
+	if c.gc != nil {
+		err = c.gc.checkout(ctx, repoBaseURL, refName, tmpDir, auth)
+	} else {
+		_, err = git.PlainCloneContext(ctx, tmpDir, false, &git.CloneOptions{
+			URL:           repoBaseURL,
+			Auth:          auth,
+			ReferenceName: refName,
+			SingleBranch:  true,
+			Depth:         1,
+		})
+	}
 	if err != nil {
+		os.RemoveAll(tmpDir)
 		return "", "", err
 	}
 
 	return tmpDir, packagesPath, nil
 }
+
+// gitAuth returns the auth method that should be used to prepare the requests
+// sent to the git repository url provided, if any. Only git repositories
+// accessed over http(s) are supported.
+func (c *Cloner) gitAuth(u string, r *hub.Repository) transport.AuthMethod {
+	if c.setupRequest == nil {
+		return nil
+	}
+	if !strings.HasPrefix(u, "https://") && !strings.HasPrefix(u, "http://") {
+		return nil
+	}
+	return &requestSetupAuth{r: r, setup: c.setupRequest}
+}
+
+// requestSetupAuth is a go-git http auth method that prepares the requests
+// sent to a git repository using the setup function provided, so that they
+// are set up like the rest of requests sent to the repository.
+type requestSetupAuth struct {
+	r     *hub.Repository
+	setup RequestSetupFunc
+}
+
+// Name implements the transport.AuthMethod interface.
+func (a *requestSetupAuth) Name() string {
+	return "http-request-setup"
+}
+
+// String implements the transport.AuthMethod interface.
+func (a *requestSetupAuth) String() string {
+	return a.Name()
+}
+
+// SetAuth implements the go-git http.AuthMethod interface.
+func (a *requestSetupAuth) SetAuth(req *http.Request) {
+	a.setup(req, a.r)
+
+	// Git over https expects GitHub tokens to be provided using basic auth
+	if v := req.Header.Get("Authorization"); strings.HasPrefix(v, "token ") {
+		req.SetBasicAuth("x-access-token", strings.TrimPrefix(v, "token "))
+	}
+}
+
+// copyLocalRepository copies the content of the repository located in the
+// local file system path the file url provided points to into a temporary
+// directory.
+func copyLocalRepository(u string) (string, string, error) {
+	p, err := LocalPath(u)
+	if err != nil {
+		return "", "", err
+	}
+	info, err := os.Stat(p)
+	if err != nil {
+		return "", "", err
+	}
+	if !info.IsDir() {
+		return "", "", fmt.Errorf("local repository path is not a directory: %s", p)
+	}
+	tmpDir, err := ioutil.TempDir("", "artifact-hub")
+	if err != nil {
+		return "", "", fmt.Errorf("error creating temp dir: %w", err)
+	}
+	if err := copyDir(p, tmpDir); err != nil {
+		os.RemoveAll(tmpDir)
+		return "", "", fmt.Errorf("error copying local repository: %w", err)
+	}
+	return tmpDir, "", nil
+}
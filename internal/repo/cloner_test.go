@@ -0,0 +1,43 @@
+package repo
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClonerGitAuth(t *testing.T) {
+	r := &hub.Repository{Name: "repo1"}
+	setupRequest := func(req *http.Request, r *hub.Repository) {
+		req.Header.Set("User-Agent", "agent1")
+		if r.Name == "repo1" {
+			req.Header.Set("Authorization", "token token1")
+		}
+	}
+
+	t.Run("no request setup function provided", func(t *testing.T) {
+		c := NewCloner()
+		assert.Nil(t, c.gitAuth("https://github.com/org1/repo1", r))
+	})
+
+	t.Run("git repository not accessed over http", func(t *testing.T) {
+		c := NewCloner(WithClonerRequestSetup(setupRequest))
+		assert.Nil(t, c.gitAuth("/tmp/repo1", r))
+	})
+
+	t.Run("requests prepared using request setup function", func(t *testing.T) {
+		c := NewCloner(WithClonerRequestSetup(setupRequest))
+		auth := c.gitAuth("https://github.com/org1/repo1", r)
+		require.NotNil(t, auth)
+		req, _ := http.NewRequest(http.MethodGet, "https://github.com/org1/repo1/info/refs", nil)
+		auth.(*requestSetupAuth).SetAuth(req)
+		assert.Equal(t, "agent1", req.Header.Get("User-Agent"))
+		user, pass, ok := req.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "x-access-token", user)
+		assert.Equal(t, "token1", pass)
+	})
+}
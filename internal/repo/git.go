@@ -0,0 +1,158 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// DefaultGitBranch represents the branch cloned from git based repositories
+// that don't define a branch or tag pattern explicitly.
+const DefaultGitBranch = "master"
+
+// gitBranchRE is a regexp used to validate the branch names provided.
+var gitBranchRE = regexp.MustCompile(`^[A-Za-z0-9_.-]+(/[A-Za-z0-9_.-]+)*$`)
+
+// errNoMatchingTag indicates that none of the repository tags matches the tag
+// pattern provided.
+var errNoMatchingTag = errors.New("no tags matching the pattern provided found")
+
+// HasGitSettings checks if the repository provided defines a branch, path or
+// tag pattern.
+func HasGitSettings(r *hub.Repository) bool {
+	return r.Branch != "" || r.Path != "" || r.TagPattern != ""
+}
+
+// GitBranch returns the branch that will be cloned from the git based
+// repository provided when it does not define a tag pattern.
+func GitBranch(r *hub.Repository) string {
+	if r.Branch != "" {
+		return r.Branch
+	}
+	return DefaultGitBranch
+}
+
+// GitPackagesPath returns the path of the packages in the git based repository
+// provided. An explicit path takes precedence over the one included in the
+// repository url.
+func GitPackagesPath(r *hub.Repository) string {
+	if r.Path != "" {
+		return strings.Trim(path.Clean(r.Path), "/")
+	}
+	matches := GitRepoURLRE.FindStringSubmatch(r.URL)
+	if len(matches) == 4 {
+		return strings.TrimSuffix(matches[3], "/")
+	}
+	return ""
+}
+
+// GitSourceRef returns the git reference that should be used in the links to
+// the files of the repository provided, cloned in the directory provided. When
+// the repository was cloned from a tag, the commit checked out is used.
+func GitSourceRef(dir string, r *hub.Repository) string {
+	if r.TagPattern != "" {
+		if gr, err := git.PlainOpen(dir); err == nil {
+			if head, err := gr.Head(); err == nil {
+				return head.Hash().String()
+			}
+		}
+	}
+	return GitBranch(r)
+}
+
+// gitReference returns the git reference that should be cloned from the
+// repository provided. When a tag pattern is set, the remote tags are listed
+// and the one matching the pattern with the highest semantic version is used.
+func gitReference(repoBaseURL string, r *hub.Repository) (plumbing.ReferenceName, error) {
+	if r.TagPattern == "" {
+		return plumbing.NewBranchReferenceName(GitBranch(r)), nil
+	}
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{repoBaseURL},
+	})
+	refs, err := remote.List(&git.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("error listing repository tags: %w", err)
+	}
+	tag, err := latestMatchingTag(refs, r.TagPattern)
+	if err != nil {
+		return "", err
+	}
+	return plumbing.NewTagReferenceName(tag), nil
+}
+
+// latestMatchingTag returns the name of the tag matching the pattern provided
+// with the highest semantic version. Tags that are not valid semantic
+// versions (once the pattern has been matched) are ignored.
+func latestMatchingTag(refs []*plumbing.Reference, pattern string) (string, error) {
+	var latestTag string
+	var latestVersion *semver.Version
+	for _, ref := range refs {
+		if !ref.Name().IsTag() {
+			continue
+		}
+		tag := ref.Name().Short()
+		if matched, _ := path.Match(pattern, tag); !matched {
+			continue
+		}
+		v, err := semver.NewVersion(tag)
+		if err != nil {
+			continue
+		}
+		if latestVersion == nil || v.GreaterThan(latestVersion) {
+			latestTag, latestVersion = tag, v
+		}
+	}
+	if latestTag == "" {
+		return "", errNoMatchingTag
+	}
+	return latestTag, nil
+}
+
+// validateGitSettings checks if the branch, path and tag pattern of the
+// repository provided are valid. These settings are only supported by git
+// based repositories that are not located in the local file system.
+func validateGitSettings(r *hub.Repository) error {
+	if !HasGitSettings(r) {
+		return nil
+	}
+	switch r.Kind {
+	case hub.Falco, hub.OLM, hub.OPA:
+	default:
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "git settings not supported by this repository")
+	}
+	if IsLocal(r.URL) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "git settings not supported by this repository")
+	}
+	if r.Branch != "" && r.TagPattern != "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "branch and tag pattern cannot be used together")
+	}
+	if r.Branch != "" && (!gitBranchRE.MatchString(r.Branch) || strings.Contains(r.Branch, "..")) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid branch")
+	}
+	if r.Path != "" {
+		if path.IsAbs(r.Path) || strings.HasPrefix(path.Clean(r.Path), "..") {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid path")
+		}
+		matches := GitRepoURLRE.FindStringSubmatch(r.URL)
+		if len(matches) == 4 && strings.Trim(matches[3], "/") != "" {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "path cannot be provided when the url includes one")
+		}
+	}
+	if r.TagPattern != "" {
+		if _, err := path.Match(r.TagPattern, ""); err != nil {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid tag pattern")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package repo
+
+import (
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitPackagesPath(t *testing.T) {
+	testCases := []struct {
+		r            *hub.Repository
+		expectedPath string
+	}{
+		{&hub.Repository{URL: "https://github.com/org1/repo1"}, ""},
+		{&hub.Repository{URL: "https://github.com/org1/repo1/path/to/packages/"}, "path/to/packages"},
+		{&hub.Repository{URL: "https://github.com/org1/repo1", Path: "/path/to/packages/"}, "path/to/packages"},
+		{&hub.Repository{URL: "https://github.com/org1/repo1", Path: "path/./to/packages"}, "path/to/packages"},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.r.URL+"#"+tc.r.Path, func(t *testing.T) {
+			assert.Equal(t, tc.expectedPath, GitPackagesPath(tc.r))
+		})
+	}
+}
+
+func TestLatestMatchingTag(t *testing.T) {
+	refs := []*plumbing.Reference{
+		plumbing.NewHashReference(plumbing.NewBranchReferenceName("v9.0.0"), plumbing.ZeroHash),
+		plumbing.NewHashReference(plumbing.NewTagReferenceName("v1.0.0"), plumbing.ZeroHash),
+		plumbing.NewHashReference(plumbing.NewTagReferenceName("v1.10.0"), plumbing.ZeroHash),
+		plumbing.NewHashReference(plumbing.NewTagReferenceName("v1.2.0"), plumbing.ZeroHash),
+		plumbing.NewHashReference(plumbing.NewTagReferenceName("v2.0.0-rc1"), plumbing.ZeroHash),
+		plumbing.NewHashReference(plumbing.NewTagReferenceName("release-1"), plumbing.ZeroHash),
+		plumbing.NewHashReference(plumbing.NewTagReferenceName("latest"), plumbing.ZeroHash),
+	}
+
+	testCases := []struct {
+		pattern     string
+		expectedTag string
+	}{
+		{"v*", "v2.0.0-rc1"},
+		{"v1.*", "v1.10.0"},
+		{"v1.2.*", "v1.2.0"},
+		{"*", "v2.0.0-rc1"},
+		{"latest", ""},
+		{"v3.*", ""},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.pattern, func(t *testing.T) {
+			tag, err := latestMatchingTag(refs, tc.pattern)
+			if tc.expectedTag == "" {
+				assert.Equal(t, errNoMatchingTag, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedTag, tag)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package repo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+// GitCache is an on-disk cache of shallow clones of git based repositories.
+// It is shared by the trackers of all the git based repositories kinds, so
+// that each repository is cloned only once and then kept up to date fetching
+// just the latest commit of the reference tracked, instead of cloning it from
+// scratch on every tracker run. Operations on the same cache entry are
+// serialized using a lock per entry, as multiple repositories may point to the
+// same git repository (i.e. using different paths).
+type GitCache struct {
+	dir string
+
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex // K: cache entry key
+}
+
+// NewGitCache creates a new GitCache instance that stores the clones in the
+// directory provided.
+func NewGitCache(dir string) *GitCache {
+	return &GitCache{
+		dir:   dir,
+		locks: make(map[string]*sync.Mutex),
+	}
+}
+
+// checkout updates the clone of the git repository and reference provided,
+// cloning it when it is not in the cache yet, and copies its content into the
+// dst directory. Cache entries that cannot be updated (i.e. because they are
+// corrupted or the remote history has been rewritten) are cloned again. The
+// auth method provided, if any, is used to prepare the requests sent to the
+// remote.
+func (c *GitCache) checkout(
+	ctx context.Context,
+	url string,
+	refName plumbing.ReferenceName,
+	dst string,
+	auth transport.AuthMethod,
+) error {
+	key := gitCacheKey(url, refName)
+	l := c.lock(key)
+	l.Lock()
+	defer l.Unlock()
+
+	entryDir := filepath.Join(c.dir, key)
+	if _, err := os.Stat(entryDir); err == nil {
+		if err := updateClone(ctx, entryDir, refName, auth); err != nil {
+			if err := os.RemoveAll(entryDir); err != nil {
+				return fmt.Errorf("error removing git cache entry: %w", err)
+			}
+		}
+	}
+	if _, err := os.Stat(entryDir); os.IsNotExist(err) {
+		_, err := git.PlainCloneContext(ctx, entryDir, false, &git.CloneOptions{
+			URL:           url,
+			Auth:          auth,
+			ReferenceName: refName,
+			SingleBranch:  true,
+			Depth:         1,
+		})
+		if err != nil {
+			os.RemoveAll(entryDir)
+			return err
+		}
+	}
+
+	return copyTree(entryDir, dst, false)
+}
+
+// lock returns the lock of the cache entry provided.
+func (c *GitCache) lock(key string) *sync.Mutex {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	l, ok := c.locks[key]
+	if !ok {
+		l = &sync.Mutex{}
+		c.locks[key] = l
+	}
+	return l
+}
+
+// gitCacheKey returns the key of the cache entry for the git repository and
+// reference provided. All the tags of a repository share the same entry, so
+// that entries are not left behind when a new tag matching the repository's
+// tag pattern is released.
+func gitCacheKey(url string, refName plumbing.ReferenceName) string {
+	ref := refName.String()
+	if refName.IsTag() {
+		ref = "refs/tags/*"
+	}
+	sum := sha256.Sum256([]byte(url + "\x00" + ref))
+	return hex.EncodeToString(sum[:])
+}
+
+// updateClone fetches the latest commit of the reference provided into the
+// shallow clone located in the directory given and checks it out.
+func updateClone(ctx context.Context, dir string, refName plumbing.ReferenceName, auth transport.AuthMethod) error {
+	gr, err := git.PlainOpen(dir)
+	if err != nil {
+		return err
+	}
+	localRefName := refName
+	if refName.IsBranch() {
+		localRefName = plumbing.NewRemoteReferenceName(git.DefaultRemoteName, refName.Short())
+	}
+	err = gr.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: git.DefaultRemoteName,
+		Auth:       auth,
+		RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", refName, localRefName))},
+		Depth:      1,
+		Force:      true,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return err
+	}
+	ref, err := gr.Reference(localRefName, true)
+	if err != nil {
+		return err
+	}
+	hash := ref.Hash()
+	if tag, err := gr.TagObject(hash); err == nil {
+		// Annotated tags point to the tag object, not to the commit
+		hash = tag.Target
+	}
+	wt, err := gr.Worktree()
+	if err != nil {
+		return err
+	}
+	return wt.Checkout(&git.CheckoutOptions{
+		Hash:  hash,
+		Force: true,
+	})
+}
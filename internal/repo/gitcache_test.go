@@ -0,0 +1,200 @@
+package repo
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitCache(t *testing.T) {
+	ctx := context.Background()
+	masterRefName := plumbing.NewBranchReferenceName("master")
+
+	t.Run("cache entries keys", func(t *testing.T) {
+		url := "https://github.com/org1/repo1"
+		master := gitCacheKey(url, plumbing.NewBranchReferenceName("master"))
+		mainBranch := gitCacheKey(url, plumbing.NewBranchReferenceName("main"))
+		v1 := gitCacheKey(url, plumbing.NewTagReferenceName("v1.0.0"))
+		v2 := gitCacheKey(url, plumbing.NewTagReferenceName("v2.0.0"))
+		other := gitCacheKey("https://github.com/org1/repo2", plumbing.NewBranchReferenceName("master"))
+
+		assert.NotEqual(t, master, mainBranch)
+		assert.NotEqual(t, master, other)
+		assert.NotEqual(t, master, v1)
+		assert.Equal(t, v1, v2)
+	})
+
+	t.Run("cache entries locks", func(t *testing.T) {
+		c := NewGitCache("")
+		assert.Same(t, c.lock("key1"), c.lock("key1"))
+		assert.NotSame(t, c.lock("key1"), c.lock("key2"))
+	})
+
+	t.Run("error cloning repository, cache entry not created", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "artifact-hub-test")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		url := filepath.Join(dir, "not-found")
+		refName := plumbing.NewBranchReferenceName("master")
+		c := NewGitCache(filepath.Join(dir, "cache"))
+
+		err = c.checkout(context.Background(), url, refName, filepath.Join(dir, "dst"), nil)
+		assert.Error(t, err)
+		_, err = os.Stat(filepath.Join(dir, "cache", gitCacheKey(url, refName)))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("existing shallow clone updated with the latest commit", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "artifact-hub-test")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		src := newGitRepoFixture(t, filepath.Join(dir, "src"))
+		src.commitFile(t, "file.txt", "v0")
+		src.commitFile(t, "file.txt", "v1")
+		c := NewGitCache(filepath.Join(dir, "cache"))
+		entryDir := filepath.Join(dir, "cache", gitCacheKey(src.dir, masterRefName))
+
+		// Only the latest commit is cloned
+		require.NoError(t, c.checkout(ctx, src.dir, masterRefName, filepath.Join(dir, "dst1"), nil))
+		assertFileContent(t, filepath.Join(dir, "dst1", "file.txt"), "v1")
+		assert.FileExists(t, filepath.Join(entryDir, ".git", "shallow"))
+
+		// The cache entry is updated instead of being cloned again
+		marker := filepath.Join(entryDir, ".git", "marker")
+		require.NoError(t, ioutil.WriteFile(marker, nil, 0644))
+		src.commitFile(t, "file.txt", "v2")
+		require.NoError(t, c.checkout(ctx, src.dir, masterRefName, filepath.Join(dir, "dst2"), nil))
+		assertFileContent(t, filepath.Join(dir, "dst2", "file.txt"), "v2")
+		assert.FileExists(t, marker)
+		assert.FileExists(t, filepath.Join(entryDir, ".git", "shallow"))
+	})
+
+	t.Run("local changes in the cache entry are discarded", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "artifact-hub-test")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		src := newGitRepoFixture(t, filepath.Join(dir, "src"))
+		src.commitFile(t, "file.txt", "v1")
+		c := NewGitCache(filepath.Join(dir, "cache"))
+		entryDir := filepath.Join(dir, "cache", gitCacheKey(src.dir, masterRefName))
+		require.NoError(t, c.checkout(ctx, src.dir, masterRefName, filepath.Join(dir, "dst"), nil))
+
+		require.NoError(t, ioutil.WriteFile(filepath.Join(entryDir, "file.txt"), []byte("modified"), 0644))
+		require.NoError(t, updateClone(ctx, entryDir, masterRefName, nil))
+		assertFileContent(t, filepath.Join(entryDir, "file.txt"), "v1")
+	})
+
+	t.Run("tags share the same cache entry", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "artifact-hub-test")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		src := newGitRepoFixture(t, filepath.Join(dir, "src"))
+		v1 := src.commitFile(t, "file.txt", "v1")
+		_, err = src.gr.CreateTag("v1.0.0", v1, nil)
+		require.NoError(t, err)
+		v2 := src.commitFile(t, "file.txt", "v2")
+		_, err = src.gr.CreateTag("v2.0.0", v2, &git.CreateTagOptions{
+			Tagger:  testSignature(),
+			Message: "v2.0.0",
+		})
+		require.NoError(t, err)
+		c := NewGitCache(filepath.Join(dir, "cache"))
+
+		// Lightweight tag
+		v1RefName := plumbing.NewTagReferenceName("v1.0.0")
+		require.NoError(t, c.checkout(ctx, src.dir, v1RefName, filepath.Join(dir, "dst1"), nil))
+		assertFileContent(t, filepath.Join(dir, "dst1", "file.txt"), "v1")
+
+		// Annotated tag, the tag object is resolved to the commit it points to
+		v2RefName := plumbing.NewTagReferenceName("v2.0.0")
+		require.NoError(t, c.checkout(ctx, src.dir, v2RefName, filepath.Join(dir, "dst2"), nil))
+		assertFileContent(t, filepath.Join(dir, "dst2", "file.txt"), "v2")
+
+		// Back to the first tag
+		require.NoError(t, c.checkout(ctx, src.dir, v1RefName, filepath.Join(dir, "dst3"), nil))
+		assertFileContent(t, filepath.Join(dir, "dst3", "file.txt"), "v1")
+
+		entries, err := ioutil.ReadDir(filepath.Join(dir, "cache"))
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("corrupted cache entry is cloned again", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "artifact-hub-test")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		src := newGitRepoFixture(t, filepath.Join(dir, "src"))
+		src.commitFile(t, "file.txt", "v1")
+		c := NewGitCache(filepath.Join(dir, "cache"))
+		entryDir := filepath.Join(dir, "cache", gitCacheKey(src.dir, masterRefName))
+		require.NoError(t, c.checkout(ctx, src.dir, masterRefName, filepath.Join(dir, "dst1"), nil))
+
+		require.NoError(t, os.RemoveAll(filepath.Join(entryDir, ".git")))
+		leftover := filepath.Join(entryDir, "leftover.txt")
+		require.NoError(t, ioutil.WriteFile(leftover, nil, 0644))
+		assert.Error(t, updateClone(ctx, entryDir, masterRefName, nil))
+
+		src.commitFile(t, "file.txt", "v2")
+		require.NoError(t, c.checkout(ctx, src.dir, masterRefName, filepath.Join(dir, "dst2"), nil))
+		assertFileContent(t, filepath.Join(dir, "dst2", "file.txt"), "v2")
+		_, err = os.Stat(leftover)
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+// gitRepoFixture represents a git repository used as the remote in tests.
+type gitRepoFixture struct {
+	dir string
+	gr  *git.Repository
+	wt  *git.Worktree
+}
+
+// newGitRepoFixture initializes a git repository in the directory provided.
+func newGitRepoFixture(t *testing.T, dir string) *gitRepoFixture {
+	gr, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	wt, err := gr.Worktree()
+	require.NoError(t, err)
+	return &gitRepoFixture{
+		dir: dir,
+		gr:  gr,
+		wt:  wt,
+	}
+}
+
+// commitFile writes the content provided to the file given and commits it,
+// returning the hash of the new commit.
+func (f *gitRepoFixture) commitFile(t *testing.T, name, content string) plumbing.Hash {
+	require.NoError(t, ioutil.WriteFile(filepath.Join(f.dir, name), []byte(content), 0644))
+	_, err := f.wt.Add(name)
+	require.NoError(t, err)
+	hash, err := f.wt.Commit(content, &git.CommitOptions{Author: testSignature()})
+	require.NoError(t, err)
+	return hash
+}
+
+// testSignature returns the signature used in the commits and tags created
+// in tests.
+func testSignature() *object.Signature {
+	return &object.Signature{
+		Name:  "test",
+		Email: "test@artifacthub.io",
+		When:  time.Now(),
+	}
+}
+
+// assertFileContent checks that the file provided has the expected content.
+func assertFileContent(t *testing.T, path, expectedContent string) {
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, expectedContent, string(data))
+}
@@ -1,34 +1,253 @@
 package repo
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
 	"github.com/artifacthub/hub/internal/hub"
-	"helm.sh/helm/v3/pkg/cli"
-	"helm.sh/helm/v3/pkg/getter"
+	"github.com/artifacthub/hub/internal/oci"
+	"helm.sh/helm/v3/pkg/chart"
 	helmrepo "helm.sh/helm/v3/pkg/repo"
 )
 
+// ociRequestTimeout represents the timeout used for the requests sent to the
+// OCI registries when building the index of an OCI based Helm repository.
+const ociRequestTimeout = 30 * time.Second
+
+// indexRequestTimeout represents the timeout used for the requests sent to
+// download the index file of a Helm repository.
+const indexRequestTimeout = 5 * time.Minute
+
+// ErrIndexNotModified indicates that the index file of a Helm repository has
+// not been modified since the last time it was downloaded.
+var ErrIndexNotModified = errors.New("index file not modified")
+
 // HelmIndexLoader provides a mechanism to load a Helm repository index file,
 // verifying it is valid.
-type HelmIndexLoader struct{}
+type HelmIndexLoader struct {
+	setupRequest RequestSetupFunc
+}
 
-// LoadIndex downloads and parses the index file of the provided repository.
+// NewHelmIndexLoader creates a new HelmIndexLoader instance.
+func NewHelmIndexLoader(opts ...func(l *HelmIndexLoader)) *HelmIndexLoader {
+	l := &HelmIndexLoader{}
+	for _, o := range opts {
+		o(l)
+	}
+	return l
+}
+
+// WithIndexLoaderRequestSetup allows providing a function to a
+// HelmIndexLoader instance that will be used to prepare all the http requests
+// sent to load the index files. When none is provided, requests are only set
+// up with the repository credentials.
+func WithIndexLoaderRequestSetup(f RequestSetupFunc) func(l *HelmIndexLoader) {
+	return func(l *HelmIndexLoader) {
+		l.setupRequest = f
+	}
+}
+
+// LoadIndex downloads and parses the index file of the provided repository,
+// using its credentials and custom tls settings if it has some set.
+// OCI based repositories (oci:// urls) don't have an index file, so one is
+// built from the tags available in the registry. The index file of the
+// repositories located in the local file system (file:// urls) is read from
+// the repository directory.
 func (l *HelmIndexLoader) LoadIndex(r *hub.Repository) (*helmrepo.IndexFile, error) {
-	repoConfig := &helmrepo.Entry{
-		Name: r.Name,
-		URL:  r.URL,
+	if oci.IsOCI(r.URL) {
+		hc, err := NewHTTPClient(r, ociRequestTimeout)
+		if err != nil {
+			return nil, err
+		}
+		c := &requestSetupClient{hc: hc, r: r, setup: l.requestSetup()}
+		return loadOCIIndex(context.Background(), oci.NewClient(c), r.URL)
+	}
+	if IsLocal(r.URL) {
+		p, err := LocalPath(r.URL)
+		if err != nil {
+			return nil, err
+		}
+		return helmrepo.LoadIndexFile(filepath.Join(p, "index.yaml"))
 	}
-	getters := getter.All(&cli.EnvSettings{})
-	chartRepository, err := helmrepo.NewChartRepository(repoConfig, getters)
+	hc, err := NewHTTPClient(r, indexRequestTimeout)
 	if err != nil {
 		return nil, err
 	}
-	path, err := chartRepository.DownloadIndexFile()
+	indexFile, _, err := loadIndexIfModified(hc, l.requestSetup(), r, nil)
+	return indexFile, err
+}
+
+// LoadIndexIfModified downloads and parses the index file of the provided
+// repository, sending a conditional request using the validators provided.
+// When the index file has not been modified since the validators were
+// obtained, ErrIndexNotModified is returned. On success, the validators of
+// the index file downloaded are returned along with it, so that they can be
+// used in subsequent requests. Conditional requests are not supported for OCI
+// based and local repositories, so their index is always loaded and no
+// validators are returned.
+func (l *HelmIndexLoader) LoadIndexIfModified(
+	r *hub.Repository,
+	v *hub.HelmIndexValidators,
+) (*helmrepo.IndexFile, *hub.HelmIndexValidators, error) {
+	if oci.IsOCI(r.URL) || IsLocal(r.URL) {
+		indexFile, err := l.LoadIndex(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		return indexFile, &hub.HelmIndexValidators{}, nil
+	}
+	hc, err := NewHTTPClient(r, indexRequestTimeout)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	indexFile, err := helmrepo.LoadIndexFile(path)
+	return loadIndexIfModified(hc, l.requestSetup(), r, v)
+}
+
+// requestSetup returns the function that should be used to prepare the http
+// requests sent by the loader.
+func (l *HelmIndexLoader) requestSetup() RequestSetupFunc {
+	if l.setupRequest != nil {
+		return l.setupRequest
+	}
+	return SetupAuth
+}
+
+// requestSetupClient is an oci.HTTPClient implementation that prepares the
+// requests using the setup function provided before sending them.
+type requestSetupClient struct {
+	hc    *http.Client
+	r     *hub.Repository
+	setup RequestSetupFunc
+}
+
+// Do implements the oci.HTTPClient interface.
+func (c *requestSetupClient) Do(req *http.Request) (*http.Response, error) {
+	c.setup(req, c.r)
+	return c.hc.Do(req)
+}
+
+// loadIndexIfModified downloads the index file of the Helm repository
+// provided if it has been modified, based on the validators provided (when no
+// validators are provided the index file is always downloaded). The request
+// is prepared using the setup function given.
+func loadIndexIfModified(
+	hc *http.Client,
+	setupRequest RequestSetupFunc,
+	r *hub.Repository,
+	v *hub.HelmIndexValidators,
+) (*helmrepo.IndexFile, *hub.HelmIndexValidators, error) {
+	// Prepare conditional request
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return nil, nil, err
+	}
+	u.Path = path.Join(u.Path, "index.yaml")
+	u.RawPath = ""
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	setupRequest(req, r)
+	if v != nil && v.ETag != "" {
+		req.Header.Set("If-None-Match", v.ETag)
+	}
+	if v != nil && v.LastModified != "" {
+		req.Header.Set("If-Modified-Since", v.LastModified)
+	}
+
+	// Send request and process response
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return nil, nil, ErrIndexNotModified
+	default:
+		return nil, nil, fmt.Errorf("unexpected status code received downloading index file: %d", resp.StatusCode)
+	}
+
+	// Parse index file, which is stored in a temporary file first as it may
+	// be quite large
+	tmpFile, err := ioutil.TempFile("", "artifact-hub-index-")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := io.Copy(tmpFile, resp.Body); err != nil {
+		tmpFile.Close()
+		return nil, nil, err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return nil, nil, err
+	}
+	indexFile, err := helmrepo.LoadIndexFile(tmpFile.Name())
+	if err != nil {
+		return nil, nil, err
+	}
+	newV := &hub.HelmIndexValidators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	return indexFile, newV, nil
+}
+
+// ociRegistry defines the methods of the OCI registry client used to build the
+// index of an OCI based Helm repository.
+type ociRegistry interface {
+	Tags(ctx context.Context, ref *oci.Reference) ([]string, error)
+	Digest(ctx context.Context, ref *oci.Reference) (string, error)
+}
+
+// loadOCIIndex builds an index file for the OCI based Helm repository
+// provided. Each repository holds the versions of a single chart, named after
+// the last element of the repository path, which are available as tags. Tags
+// that are not valid semver versions are ignored. The digest of the manifest
+// of each tag is used as the chart version digest.
+func loadOCIIndex(ctx context.Context, c ociRegistry, u string) (*helmrepo.IndexFile, error) {
+	ref, err := oci.ParseReference(u)
 	if err != nil {
 		return nil, err
 	}
+	if ref.Tag != "" {
+		return nil, fmt.Errorf("oci repository url must not include a tag: %s", u)
+	}
+	tags, err := c.Tags(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("error listing oci repository tags: %w", err)
+	}
+	indexFile := helmrepo.NewIndexFile()
+	for _, tag := range tags {
+		version := oci.VersionFromTag(tag)
+		if _, err := semver.NewVersion(version); err != nil {
+			continue
+		}
+		tagRef := ref.WithTag(tag)
+		digest, err := c.Digest(ctx, tagRef)
+		if err != nil {
+			return nil, fmt.Errorf("error getting oci manifest digest (%s): %w", tagRef, err)
+		}
+		indexFile.Entries[ref.Name()] = append(indexFile.Entries[ref.Name()], &helmrepo.ChartVersion{
+			Metadata: &chart.Metadata{
+				Name:    ref.Name(),
+				Version: version,
+			},
+			URLs:   []string{tagRef.String()},
+			Digest: digest,
+		})
+	}
+	indexFile.SortEntries()
 	return indexFile, nil
 }
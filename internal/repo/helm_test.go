@@ -0,0 +1,161 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/oci"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ociRegistryMock struct {
+	tags    []string
+	digests map[string]string
+	err     error
+}
+
+func (m *ociRegistryMock) Tags(ctx context.Context, ref *oci.Reference) ([]string, error) {
+	return m.tags, m.err
+}
+
+func (m *ociRegistryMock) Digest(ctx context.Context, ref *oci.Reference) (string, error) {
+	return m.digests[ref.Tag], nil
+}
+
+func TestLoadOCIIndex(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("url including a tag", func(t *testing.T) {
+		_, err := loadOCIIndex(ctx, &ociRegistryMock{}, "oci://ghcr.io/org/chart1:1.0.0")
+		assert.Error(t, err)
+	})
+
+	t.Run("error listing tags", func(t *testing.T) {
+		tagsErr := errors.New("fake error for tests")
+		_, err := loadOCIIndex(ctx, &ociRegistryMock{err: tagsErr}, "oci://ghcr.io/org/chart1")
+		assert.True(t, errors.Is(err, tagsErr))
+	})
+
+	t.Run("index built successfully", func(t *testing.T) {
+		c := &ociRegistryMock{
+			tags: []string{"1.0.0", "latest", "1.1.0_build.1"},
+			digests: map[string]string{
+				"1.0.0":         "sha256:1",
+				"1.1.0_build.1": "sha256:2",
+			},
+		}
+		indexFile, err := loadOCIIndex(ctx, c, "oci://ghcr.io/org/chart1")
+		require.NoError(t, err)
+		require.Len(t, indexFile.Entries, 1)
+		chartVersions := indexFile.Entries["chart1"]
+		require.Len(t, chartVersions, 2)
+		assert.Equal(t, "1.1.0+build.1", chartVersions[0].Version)
+		assert.Equal(t, []string{"oci://ghcr.io/org/chart1:1.1.0_build.1"}, chartVersions[0].URLs)
+		assert.Equal(t, "sha256:2", chartVersions[0].Digest)
+		assert.Equal(t, "1.0.0", chartVersions[1].Version)
+		assert.Equal(t, []string{"oci://ghcr.io/org/chart1:1.0.0"}, chartVersions[1].URLs)
+		assert.Equal(t, "sha256:1", chartVersions[1].Digest)
+	})
+}
+
+func TestLoadIndexIfModified(t *testing.T) {
+	indexYAML := `apiVersion: v1
+entries:
+  pkg1:
+  - apiVersion: v2
+    name: pkg1
+    version: 1.0.0
+    urls:
+    - https://repo1.com/pkg1-1.0.0.tgz
+`
+	lastModified := "Wed, 21 Oct 2015 07:28:00 GMT"
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/private/index.yaml":
+			if user, pass, ok := r.BasicAuth(); !ok || user != "user1" || pass != "pass1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(indexYAML))
+		case "/agent/index.yaml":
+			if r.Header.Get("User-Agent") != "agent1" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte(indexYAML))
+		case "/charts/index.yaml":
+			if r.Header.Get("If-None-Match") == `"etag1"` || r.Header.Get("If-Modified-Since") == lastModified {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"etag1"`)
+			w.Header().Set("Last-Modified", lastModified)
+			_, _ = w.Write([]byte(indexYAML))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer s.Close()
+
+	t.Run("index file not found", func(t *testing.T) {
+		_, _, err := loadIndexIfModified(s.Client(), SetupAuth, &hub.Repository{URL: s.URL + "/repo2"}, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("index file of private repository", func(t *testing.T) {
+		r := &hub.Repository{URL: s.URL + "/private"}
+		_, _, err := loadIndexIfModified(s.Client(), SetupAuth, r, nil)
+		assert.Error(t, err)
+
+		r.AuthUser = "user1"
+		r.AuthPass = "pass1"
+		indexFile, _, err := loadIndexIfModified(s.Client(), SetupAuth, r, nil)
+		require.NoError(t, err)
+		assert.Len(t, indexFile.Entries["pkg1"], 1)
+	})
+
+	t.Run("index file request prepared using setup function", func(t *testing.T) {
+		setupRequest := func(req *http.Request, r *hub.Repository) {
+			req.Header.Set("User-Agent", "agent1")
+		}
+		indexFile, _, err := loadIndexIfModified(s.Client(), setupRequest, &hub.Repository{URL: s.URL + "/agent"}, nil)
+		require.NoError(t, err)
+		assert.Len(t, indexFile.Entries["pkg1"], 1)
+	})
+
+	t.Run("index file modified", func(t *testing.T) {
+		testCases := []*hub.HelmIndexValidators{
+			nil,
+			{},
+			{ETag: `"etag0"`},
+		}
+		for _, v := range testCases {
+			indexFile, newV, err := loadIndexIfModified(s.Client(), SetupAuth, &hub.Repository{URL: s.URL + "/charts/"}, v)
+			require.NoError(t, err)
+			require.Len(t, indexFile.Entries["pkg1"], 1)
+			assert.Equal(t, "1.0.0", indexFile.Entries["pkg1"][0].Version)
+			assert.Equal(t, &hub.HelmIndexValidators{
+				ETag:         `"etag1"`,
+				LastModified: lastModified,
+			}, newV)
+		}
+	})
+
+	t.Run("index file not modified", func(t *testing.T) {
+		testCases := []*hub.HelmIndexValidators{
+			{ETag: `"etag1"`},
+			{LastModified: lastModified},
+		}
+		for _, v := range testCases {
+			indexFile, newV, err := loadIndexIfModified(s.Client(), SetupAuth, &hub.Repository{URL: s.URL + "/charts"}, v)
+			assert.True(t, errors.Is(err, ErrIndexNotModified))
+			assert.Nil(t, indexFile)
+			assert.Nil(t, newV)
+		}
+	})
+}
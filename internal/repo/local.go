@@ -0,0 +1,118 @@
+package repo
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// FileScheme represents the url scheme used by the repositories located in
+// the local file system (i.e. file:///mnt/charts), which allow tracking
+// packages in air-gapped deployments without an http server.
+const FileScheme = "file"
+
+// IsLocal checks if the url provided belongs to a repository located in the
+// local file system.
+func IsLocal(u string) bool {
+	return strings.HasPrefix(u, FileScheme+"://")
+}
+
+// LocalPath returns the local file system path the file url provided points
+// to. Only absolute paths without a host are supported. On Windows, urls
+// paths include the drive letter after a leading slash (i.e.
+// file:///C:/charts), which is removed to get a valid path.
+func LocalPath(u string) (string, error) {
+	tmp, err := url.Parse(u)
+	if err != nil {
+		return "", err
+	}
+	p := tmp.Path
+	if runtime.GOOS == "windows" && len(p) >= 3 && p[0] == '/' && p[2] == ':' {
+		p = p[1:]
+	}
+	p = filepath.FromSlash(p)
+	if tmp.Scheme != FileScheme || tmp.Host != "" || p == "" || !filepath.IsAbs(p) {
+		return "", fmt.Errorf("invalid file url: %s", u)
+	}
+	return filepath.Clean(p), nil
+}
+
+// IsPathWithin checks if the path provided is located within the directory
+// provided (or is the directory itself).
+func IsPathWithin(path, dir string) bool {
+	rel, err := filepath.Rel(filepath.Clean(dir), filepath.Clean(path))
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// isAllowedLocalURL checks if the file url provided points to a path located
+// within any of the allowed paths provided.
+func isAllowedLocalURL(u string, allowedPaths []string) bool {
+	p, err := LocalPath(u)
+	if err != nil {
+		return false
+	}
+	for _, allowedPath := range allowedPaths {
+		if filepath.IsAbs(allowedPath) && IsPathWithin(p, allowedPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// copyDir copies recursively the content of the src directory into dst. Git
+// metadata directories are skipped.
+func copyDir(src, dst string) error {
+	return copyTree(src, dst, true)
+}
+
+// copyTree copies recursively the content of the src directory into dst,
+// skipping the git metadata directories when requested.
+func copyTree(src, dst string, skipGit bool) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		switch {
+		case info.IsDir():
+			if skipGit && info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, 0755)
+		case info.Mode().IsRegular():
+			return copyFile(path, target)
+		default:
+			// Symlinks and other special files are ignored
+			return nil
+		}
+	})
+}
+
+// copyFile copies the src file into dst.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,83 @@
+package repo
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalPath(t *testing.T) {
+	t.Run("invalid file url", func(t *testing.T) {
+		testCases := []string{
+			"https://repo1.com",
+			"file://host/mnt/repo1",
+			"file://",
+			"file:relative/repo1",
+		}
+		for _, u := range testCases {
+			u := u
+			t.Run(u, func(t *testing.T) {
+				_, err := LocalPath(u)
+				assert.Error(t, err)
+			})
+		}
+	})
+
+	t.Run("valid file url", func(t *testing.T) {
+		p, err := LocalPath("file:///mnt/repos/../repos/repo1/")
+		require.NoError(t, err)
+		assert.Equal(t, "/mnt/repos/repo1", p)
+	})
+}
+
+func TestIsPathWithin(t *testing.T) {
+	testCases := []struct {
+		path     string
+		dir      string
+		expected bool
+	}{
+		{"/mnt/repos", "/mnt/repos", true},
+		{"/mnt/repos/repo1", "/mnt/repos", true},
+		{"/mnt/repos/repo1/../../etc", "/mnt/repos", false},
+		{"/mnt/repos2", "/mnt/repos", false},
+		{"/mnt/..repo1", "/mnt", true},
+		{"/etc", "/mnt/repos", false},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.path, func(t *testing.T) {
+			assert.Equal(t, tc.expected, IsPathWithin(tc.path, tc.dir))
+		})
+	}
+}
+
+func TestCloneLocalRepository(t *testing.T) {
+	// Setup local repository
+	repoPath, err := ioutil.TempDir("", "artifact-hub-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(repoPath)
+	require.NoError(t, os.MkdirAll(filepath.Join(repoPath, "pkg1", "1.0.0"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(repoPath, "pkg1", "1.0.0", "pkg.yaml"), []byte("pkg1"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(repoPath, ".git"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(repoPath, ".git", "HEAD"), []byte("ref"), 0644))
+
+	// Clone it and check the content was copied
+	c := &Cloner{}
+	r := &hub.Repository{Kind: hub.OLM, URL: "file://" + repoPath}
+	tmpDir, packagesPath, err := c.CloneRepository(context.Background(), r)
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	assert.NotEqual(t, repoPath, tmpDir)
+	assert.Equal(t, "", packagesPath)
+	data, err := ioutil.ReadFile(filepath.Join(tmpDir, "pkg1", "1.0.0", "pkg.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("pkg1"), data)
+	_, err = os.Stat(filepath.Join(tmpDir, ".git"))
+	assert.True(t, os.IsNotExist(err))
+}
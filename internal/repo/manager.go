@@ -3,18 +3,34 @@ package repo
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"regexp"
 
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/oci"
 	"github.com/artifacthub/hub/internal/util"
+	"github.com/jackc/pgx/v4"
 	"github.com/satori/uuid"
 )
 
+const (
+	// maxMetadataEntries represents the maximum number of metadata key/values
+	// a repository can have.
+	maxMetadataEntries = 20
+
+	// maxMetadataValueLength represents the maximum length of a repository
+	// metadata value.
+	maxMetadataValueLength = 256
+)
+
 var (
 	// repositoryNameRE is a regexp used to validate a repository name.
 	repositoryNameRE = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
 
+	// metadataKeyRE is a regexp used to validate a repository metadata key.
+	metadataKeyRE = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
 	// GitRepoURLRE is a regexp used to validate and parse a git based
 	// repository URL.
 	GitRepoURLRE = regexp.MustCompile(`^(https:\/\/(github|gitlab)\.com\/[A-Za-z0-9_.-]+\/[A-Za-z0-9_.-]+)\/?(.*)$`)
@@ -22,8 +38,11 @@ var (
 
 // Manager provides an API to manage repositories.
 type Manager struct {
-	db              hub.DB
-	helmIndexLoader hub.HelmIndexLoader
+	db                hub.DB
+	helmIndexLoader   hub.HelmIndexLoader
+	allowedLocalPaths []string
+	sc                hub.SecretsCipher
+	limits            *hub.Limits
 }
 
 // NewManager creates a new Manager instance.
@@ -31,6 +50,7 @@ func NewManager(db hub.DB, opts ...func(m *Manager)) *Manager {
 	m := &Manager{
 		db:              db,
 		helmIndexLoader: &HelmIndexLoader{},
+		limits:          hub.DefaultLimits(),
 	}
 	for _, o := range opts {
 		o(m)
@@ -46,7 +66,35 @@ func WithIndexLoader(l hub.HelmIndexLoader) func(m *Manager) {
 	}
 }
 
-// Add adds the provided repository to the database.
+// WithAllowedLocalPaths allows providing the local file system paths where
+// the repositories located in the local file system (file:// urls) can be
+// added from. Local repositories are not allowed unless some paths are
+// provided.
+func WithAllowedLocalPaths(paths []string) func(m *Manager) {
+	return func(m *Manager) {
+		m.allowedLocalPaths = paths
+	}
+}
+
+// WithSecretsCipher sets the cipher used to encrypt the repositories
+// credentials before storing them in the database in a Manager instance.
+func WithSecretsCipher(sc hub.SecretsCipher) func(m *Manager) {
+	return func(m *Manager) {
+		m.sc = sc
+	}
+}
+
+// WithLimits allows overriding the default limits enforced by a Manager
+// instance, like the maximum number of repositories an organization can own.
+func WithLimits(l *hub.Limits) func(m *Manager) {
+	return func(m *Manager) {
+		m.limits = l
+	}
+}
+
+// Add adds the provided repository to the database. Some well-known url
+// shortcuts are supported, deriving from them the url, branch and path
+// expected by the repository kind.
 func (m *Manager) Add(ctx context.Context, orgName string, r *hub.Repository) error {
 	userID := ctx.Value(hub.UserIDKey).(string)
 
@@ -63,7 +111,39 @@ func (m *Manager) Add(ctx context.Context, orgName string, r *hub.Repository) er
 	if r.URL == "" {
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "url not provided")
 	}
-	if r.Kind == hub.Falco || r.Kind == hub.OLM {
+	expandURLShortcut(r)
+	if r.TrackingInterval < 0 {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid tracking interval")
+	}
+	if err := validateQuietHours(r.QuietHours); err != nil {
+		return err
+	}
+	if err := validateMetadata(r.Metadata); err != nil {
+		return err
+	}
+	if err := validateCredentials(r); err != nil {
+		return err
+	}
+	if err := validateTLSSettings(r); err != nil {
+		return err
+	}
+	if err := validateGitSettings(r); err != nil {
+		return err
+	}
+	if r.AuthPass == KeepSecret {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid password")
+	}
+	if r.TLSClientKey == KeepSecret {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid tls client key")
+	}
+	if _, err := TLSConfig(r); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, err.Error())
+	}
+	if IsLocal(r.URL) {
+		if !isAllowedLocalURL(r.URL, m.allowedLocalPaths) {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid url")
+		}
+	} else if r.Kind == hub.Falco || r.Kind == hub.OLM {
 		if !GitRepoURLRE.MatchString(r.URL) {
 			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid url")
 		}
@@ -74,10 +154,45 @@ func (m *Manager) Add(ctx context.Context, orgName string, r *hub.Repository) er
 		}
 	}
 
+	// Check the organization has not reached the maximum number of
+	// repositories allowed
+	if err := m.checkOrgRepositoriesLimit(ctx, orgName); err != nil {
+		return err
+	}
+
 	// Add repository to the database
 	query := "select add_repository($1::uuid, $2::text, $3::jsonb)"
-	rJSON, _ := json.Marshal(r)
-	_, err := m.db.Exec(ctx, query, userID, orgName, rJSON)
+	rJSON, err := m.marshalRepository(ctx, r)
+	if err != nil {
+		return err
+	}
+	_, err = m.db.Exec(ctx, query, userID, orgName, rJSON)
+	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+		return hub.ErrInsufficientPrivilege
+	}
+	return err
+}
+
+// AddCollaborator adds the user provided as a collaborator of the given
+// repository with the role specified. The user doing the request must own the
+// repository, directly or by belonging to the organization which owns it.
+func (m *Manager) AddCollaborator(ctx context.Context, repoName, userAlias, role string) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if repoName == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "repository name not provided")
+	}
+	if userAlias == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "user alias not provided")
+	}
+	if !isValidCollaboratorRole(role) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid role")
+	}
+
+	// Add repository collaborator to database
+	query := "select add_repository_collaborator($1::uuid, $2::text, $3::text, $4::text)"
+	_, err := m.db.Exec(ctx, query, userID, repoName, userAlias, role)
 	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
 		return hub.ErrInsufficientPrivilege
 	}
@@ -140,18 +255,63 @@ func (m *Manager) Delete(ctx context.Context, name string) error {
 	return err
 }
 
+// DeleteCollaborator removes the user provided from the collaborators of the
+// given repository. The user doing the request must own the repository.
+func (m *Manager) DeleteCollaborator(ctx context.Context, repoName, userAlias string) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if repoName == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "repository name not provided")
+	}
+	if userAlias == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "user alias not provided")
+	}
+
+	// Delete repository collaborator from database
+	query := "select delete_repository_collaborator($1::uuid, $2::text, $3::text)"
+	_, err := m.db.Exec(ctx, query, userID, repoName, userAlias)
+	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+		return hub.ErrInsufficientPrivilege
+	}
+	return err
+}
+
 // GetAll returns all available repositories.
 func (m *Manager) GetAll(ctx context.Context) ([]*hub.Repository, error) {
 	var r []*hub.Repository
-	err := m.dbQueryUnmarshal(ctx, &r, "select get_all_repositories()")
-	return r, err
+	if err := m.dbQueryUnmarshal(ctx, &r, "select get_all_repositories()"); err != nil {
+		return nil, err
+	}
+	if err := m.decryptSecrets(ctx, r...); err != nil {
+		return nil, err
+	}
+	return r, nil
 }
 
 // GetByKind returns all available repositories of the provided kind.
 func (m *Manager) GetByKind(ctx context.Context, kind hub.RepositoryKind) ([]*hub.Repository, error) {
 	var r []*hub.Repository
-	err := m.dbQueryUnmarshal(ctx, &r, "select get_repositories_by_kind($1::int)", kind)
-	return r, err
+	if err := m.dbQueryUnmarshal(ctx, &r, "select get_repositories_by_kind($1::int)", kind); err != nil {
+		return nil, err
+	}
+	if err := m.decryptSecrets(ctx, r...); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetByMetadataJSON returns all repositories whose metadata contains all the
+// key/values provided.
+func (m *Manager) GetByMetadataJSON(ctx context.Context, metadata map[string]string) ([]byte, error) {
+	// Validate input
+	if len(metadata) == 0 {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "metadata not provided")
+	}
+
+	// Get repositories from database
+	metadataJSON, _ := json.Marshal(metadata)
+	return m.dbQueryJSON(ctx, "select get_repositories_by_metadata($1::jsonb)", metadataJSON)
 }
 
 // GetByName returns the repository identified by the name provided.
@@ -163,8 +323,108 @@ func (m *Manager) GetByName(ctx context.Context, name string) (*hub.Repository,
 
 	// Get repository from database
 	var r *hub.Repository
-	err := m.dbQueryUnmarshal(ctx, &r, "select get_repository_by_name($1::text)", name)
-	return r, err
+	if err := m.dbQueryUnmarshal(ctx, &r, "select get_repository_by_name($1::text)", name); err != nil {
+		return nil, err
+	}
+	if r != nil {
+		if err := m.decryptSecrets(ctx, r); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// GetCollaboratorsJSON returns the collaborators of the provided repository as
+// a json object. The user doing the request must own the repository.
+func (m *Manager) GetCollaboratorsJSON(ctx context.Context, repoName string) ([]byte, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if repoName == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "repository name not provided")
+	}
+
+	// Get repository collaborators from database
+	query := "select get_repository_collaborators($1::uuid, $2::text)"
+	dataJSON, err := m.dbQueryJSON(ctx, query, userID, repoName)
+	if err != nil {
+		if err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+			return nil, hub.ErrInsufficientPrivilege
+		}
+		return nil, err
+	}
+	return dataJSON, nil
+}
+
+// GetDeletionImpactJSON returns what would be removed if the provided
+// repository was deleted, as a json object. Nothing is deleted. The user
+// doing the request must be allowed to delete the repository.
+func (m *Manager) GetDeletionImpactJSON(ctx context.Context, name string) ([]byte, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if name == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "name not provided")
+	}
+
+	// Get repository deletion impact from database
+	query := "select get_repository_deletion_impact($1::uuid, $2::text)"
+	dataJSON, err := m.dbQueryJSON(ctx, query, userID, name)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, hub.ErrNotFound
+		case err.Error() == util.ErrDBInsufficientPrivilege.Error():
+			return nil, hub.ErrInsufficientPrivilege
+		}
+		return nil, err
+	}
+	return dataJSON, nil
+}
+
+// GetHealthJSON returns some indicators about the health of the provided
+// repository, along with a score combining them, as a json object.
+func (m *Manager) GetHealthJSON(ctx context.Context, repoName string) ([]byte, error) {
+	// Validate input
+	if repoName == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "repository name not provided")
+	}
+
+	// Get repository health from database
+	dataJSON, err := m.dbQueryJSON(ctx, "select get_repository_health($1::text)", repoName)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, hub.ErrNotFound
+		}
+		return nil, err
+	}
+	return dataJSON, nil
+}
+
+// GetHelmIndexValidators returns the validators of the index file of the
+// Helm repository identified by the id provided, as stored the last time the
+// index file was downloaded.
+func (m *Manager) GetHelmIndexValidators(
+	ctx context.Context,
+	repositoryID string,
+) (*hub.HelmIndexValidators, error) {
+	// Validate input
+	if _, err := uuid.FromString(repositoryID); err != nil {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid repository id")
+	}
+
+	// Get index validators from database
+	v := &hub.HelmIndexValidators{}
+	query := `
+	select
+		coalesce(index_etag, ''),
+		coalesce(index_last_modified, '')
+	from repository
+	where repository_id = $1`
+	if err := m.db.QueryRow(ctx, query, repositoryID).Scan(&v.ETag, &v.LastModified); err != nil {
+		return nil, err
+	}
+	return v, nil
 }
 
 // GetPackagesDigest returns the digests for all packages in the repository
@@ -208,8 +468,82 @@ func (m *Manager) GetOwnedByUserJSON(ctx context.Context) ([]byte, error) {
 	return m.dbQueryJSON(ctx, query, userID)
 }
 
+// RegenerateTrackingSecret generates a new secret that can be used to request
+// the tracking of the provided repository. The user doing the request must
+// have write access to the repository.
+func (m *Manager) RegenerateTrackingSecret(ctx context.Context, repoName string) (string, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if repoName == "" {
+		return "", fmt.Errorf("%w: %s", hub.ErrInvalidInput, "repository name not provided")
+	}
+
+	// Regenerate tracking secret in database
+	var secret string
+	query := "select regenerate_repository_tracking_secret($1::uuid, $2::text)"
+	err := m.db.QueryRow(ctx, query, userID, repoName).Scan(&secret)
+	if err != nil {
+		if err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+			return "", hub.ErrInsufficientPrivilege
+		}
+		return "", err
+	}
+	return secret, nil
+}
+
+// RequestTracking requests the tracking of the provided repository, so that
+// it's tracked right away regardless of its tracking interval.
+// The secret provided must match the one generated for the repository.
+func (m *Manager) RequestTracking(ctx context.Context, repoName, secret string) error {
+	// Validate input
+	if repoName == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "repository name not provided")
+	}
+	if secret == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "secret not provided")
+	}
+
+	// Register tracking request in database
+	var requested bool
+	query := "select request_repository_tracking($1::text, $2::text)"
+	if err := m.db.QueryRow(ctx, query, repoName, secret).Scan(&requested); err != nil {
+		return err
+	}
+	if !requested {
+		return hub.ErrInsufficientPrivilege
+	}
+	return nil
+}
+
+// SetHelmIndexValidators stores the validators of the index file of the Helm
+// repository identified by the id provided in the database.
+func (m *Manager) SetHelmIndexValidators(
+	ctx context.Context,
+	repositoryID string,
+	v *hub.HelmIndexValidators,
+) error {
+	// Validate input
+	if _, err := uuid.FromString(repositoryID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid repository id")
+	}
+	if v == nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "index validators not provided")
+	}
+
+	// Update index validators in database
+	query := `
+	update repository set
+		index_etag = nullif($2, ''),
+		index_last_modified = nullif($3, '')
+	where repository_id = $1`
+	_, err := m.db.Exec(ctx, query, repositoryID, v.ETag, v.LastModified)
+	return err
+}
+
 // SetLastTrackingResults updates the timestamp and errors of the last tracking
-// of the provided repository in the database.
+// of the provided repository in the database, keeping count of the tracking
+// runs and how many of them failed.
 func (m *Manager) SetLastTrackingResults(ctx context.Context, repositoryID, errs string) error {
 	// Validate input
 	if _, err := uuid.FromString(repositoryID); err != nil {
@@ -220,7 +554,9 @@ func (m *Manager) SetLastTrackingResults(ctx context.Context, repositoryID, errs
 	query := `
 	update repository set
 		last_tracking_ts = current_timestamp,
-		last_tracking_errors = nullif($2, '')
+		last_tracking_errors = nullif($2, ''),
+		tracking_runs = tracking_runs + 1,
+		tracking_failed_runs = tracking_failed_runs + (case when $2 <> '' then 1 else 0 end)
 	where repository_id = $1`
 	_, err := m.db.Exec(ctx, query, repositoryID, errs)
 	return err
@@ -247,6 +583,12 @@ func (m *Manager) Transfer(ctx context.Context, repoName, orgName string) error
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "repository name not provided")
 	}
 
+	// Check the destination organization has not reached the maximum number
+	// of repositories allowed
+	if err := m.checkOrgRepositoriesLimit(ctx, orgName); err != nil {
+		return err
+	}
+
 	// Update repository owner in database
 	query := "select transfer_repository($1::text, $2::uuid, $3::text)"
 	_, err := m.db.Exec(ctx, query, repoName, userIDP, orgNameP)
@@ -267,27 +609,146 @@ func (m *Manager) Update(ctx context.Context, r *hub.Repository) error {
 	if r.URL == "" {
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "url not provided")
 	}
-	if r.Kind == hub.Falco || r.Kind == hub.OLM {
+	if r.TrackingInterval < 0 {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid tracking interval")
+	}
+	if err := validateQuietHours(r.QuietHours); err != nil {
+		return err
+	}
+	if err := validateMetadata(r.Metadata); err != nil {
+		return err
+	}
+	if err := validateCredentials(r); err != nil {
+		return err
+	}
+	if err := validateTLSSettings(r); err != nil {
+		return err
+	}
+	if err := validateGitSettings(r); err != nil {
+		return err
+	}
+	if IsLocal(r.URL) {
+		if !isAllowedLocalURL(r.URL, m.allowedLocalPaths) {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid url")
+		}
+	} else if r.Kind == hub.Falco || r.Kind == hub.OLM {
 		if !GitRepoURLRE.MatchString(r.URL) {
 			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid url")
 		}
 	}
 	if r.Kind == hub.Helm {
-		if _, err := m.helmIndexLoader.LoadIndex(r); err != nil {
+		rToValidate, err := m.withStoredSecrets(ctx, r)
+		if err != nil {
+			return err
+		}
+		if _, err := TLSConfig(rToValidate); err != nil {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, err.Error())
+		}
+		if _, err := m.helmIndexLoader.LoadIndex(rToValidate); err != nil {
 			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid url")
 		}
 	}
 
 	// Update repository in database
 	query := "select update_repository($1::uuid, $2::jsonb)"
-	rJSON, _ := json.Marshal(r)
-	_, err := m.db.Exec(ctx, query, userID, rJSON)
+	rJSON, err := m.marshalRepository(ctx, r)
+	if err != nil {
+		return err
+	}
+	_, err = m.db.Exec(ctx, query, userID, rJSON)
 	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
 		return hub.ErrInsufficientPrivilege
 	}
 	return err
 }
 
+// checkOrgRepositoriesLimit checks if the organization provided can own one
+// more repository without exceeding the repositories per organization limit.
+func (m *Manager) checkOrgRepositoriesLimit(ctx context.Context, orgName string) error {
+	if orgName == "" || m.limits.RepositoriesPerOrg <= 0 {
+		return nil
+	}
+	var count int64
+	query := "select count_org_repositories($1::text)"
+	if err := m.db.QueryRow(ctx, query, orgName).Scan(&count); err != nil {
+		return err
+	}
+	if count >= int64(m.limits.RepositoriesPerOrg) {
+		return &hub.LimitExceededError{Limit: "repositories per organization", Max: m.limits.RepositoriesPerOrg}
+	}
+	return nil
+}
+
+// withStoredSecrets returns a copy of the repository provided in which the
+// secrets the user asked to keep have been replaced by the ones stored. The
+// stored password can only be kept when the url has not changed, so that it
+// is never sent to a different location.
+func (m *Manager) withStoredSecrets(ctx context.Context, r *hub.Repository) (*hub.Repository, error) {
+	if r.AuthPass != KeepSecret && r.TLSClientKey != KeepSecret {
+		return r, nil
+	}
+	storedRepo, err := m.GetByName(ctx, r.Name)
+	if err != nil {
+		return nil, err
+	}
+	if storedRepo == nil {
+		return nil, hub.ErrNotFound
+	}
+	rCopy := *r
+	if r.AuthPass == KeepSecret {
+		if storedRepo.URL != r.URL {
+			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "password must be provided when the url changes")
+		}
+		rCopy.AuthPass = storedRepo.AuthPass
+	}
+	if r.TLSClientKey == KeepSecret {
+		rCopy.TLSClientKey = storedRepo.TLSClientKey
+	}
+	return &rCopy, nil
+}
+
+// marshalRepository returns the json representation of the repository
+// provided to be stored in the database, encrypting its password and tls
+// client key when a cipher is set.
+func (m *Manager) marshalRepository(ctx context.Context, r *hub.Repository) ([]byte, error) {
+	if m.sc == nil {
+		return json.Marshal(r)
+	}
+	rCopy := *r
+	for _, secret := range []*string{&rCopy.AuthPass, &rCopy.TLSClientKey} {
+		if *secret == "" || *secret == KeepSecret {
+			continue
+		}
+		encryptedSecret, err := m.sc.Encrypt(ctx, *secret)
+		if err != nil {
+			return nil, err
+		}
+		*secret = encryptedSecret
+	}
+	return json.Marshal(rCopy)
+}
+
+// decryptSecrets decrypts the password and tls client key of the
+// repositories provided when a cipher is set.
+func (m *Manager) decryptSecrets(ctx context.Context, repos ...*hub.Repository) error {
+	if m.sc == nil {
+		return nil
+	}
+	for _, r := range repos {
+		for _, secret := range []*string{&r.AuthPass, &r.TLSClientKey} {
+			if *secret == "" {
+				continue
+			}
+			decryptedSecret, err := m.sc.Decrypt(ctx, *secret)
+			if err != nil {
+				return err
+			}
+			*secret = decryptedSecret
+		}
+	}
+	return nil
+}
+
 // dbQueryJSON is a helper that executes the query provided and returns a bytes
 // slice containing the json data returned from the database.
 func (m *Manager) dbQueryJSON(ctx context.Context, query string, args ...interface{}) ([]byte, error) {
@@ -311,6 +772,16 @@ func (m *Manager) dbQueryUnmarshal(ctx context.Context, v interface{}, query str
 	return nil
 }
 
+// isValidCollaboratorRole checks if the provided collaborator role is valid.
+func isValidCollaboratorRole(role string) bool {
+	for _, validRole := range hub.RepositoryCollaboratorRoles {
+		if role == validRole {
+			return true
+		}
+	}
+	return false
+}
+
 // isValidKind checks if the provided repository kind is valid.
 func isValidKind(kind hub.RepositoryKind) bool {
 	for _, validKind := range []hub.RepositoryKind{
@@ -325,3 +796,63 @@ func isValidKind(kind hub.RepositoryKind) bool {
 	}
 	return false
 }
+
+// validateCredentials checks if the credentials of the repository provided
+// are valid. Credentials are only supported by Helm repositories served over
+// http(s).
+func validateCredentials(r *hub.Repository) error {
+	if !HasCredentials(r) {
+		return nil
+	}
+	if r.Kind != hub.Helm || IsLocal(r.URL) || oci.IsOCI(r.URL) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "credentials not supported by this repository")
+	}
+	if r.AuthPass == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "password not provided")
+	}
+	return nil
+}
+
+// validateTLSSettings checks if the custom tls settings of the repository
+// provided are supported. Custom tls settings are only supported by Helm
+// repositories that are not located in the local file system.
+func validateTLSSettings(r *hub.Repository) error {
+	if !HasTLSSettings(r) {
+		return nil
+	}
+	if r.Kind != hub.Helm || IsLocal(r.URL) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "tls settings not supported by this repository")
+	}
+	if r.TLSClientCert != "" && r.TLSClientKey == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "tls client key not provided")
+	}
+	return nil
+}
+
+// validateQuietHours checks that the quiet hours provided, when set, define a
+// valid window of hours of the day.
+func validateQuietHours(qh *hub.QuietHours) error {
+	if qh == nil {
+		return nil
+	}
+	if qh.Start < 0 || qh.Start > 23 || qh.End < 0 || qh.End > 23 || qh.Start == qh.End {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid quiet hours")
+	}
+	return nil
+}
+
+// validateMetadata checks if the repository metadata provided is valid.
+func validateMetadata(metadata map[string]string) error {
+	if len(metadata) > maxMetadataEntries {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "too many metadata entries")
+	}
+	for k, v := range metadata {
+		if !metadataKeyRE.MatchString(k) {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid metadata key")
+		}
+		if len(v) > maxMetadataValueLength {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "metadata value too long")
+		}
+	}
+	return nil
+}
@@ -2,14 +2,18 @@ package repo
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/secrets"
 	"github.com/artifacthub/hub/internal/tests"
 	"github.com/artifacthub/hub/internal/util"
+	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -90,6 +94,50 @@ func TestAdd(t *testing.T) {
 				},
 				nil,
 			},
+			{
+				"invalid tracking interval",
+				"org1",
+				&hub.Repository{
+					Kind:             hub.Helm,
+					Name:             "repo1",
+					URL:              "https://repo1.com",
+					TrackingInterval: -1,
+				},
+				nil,
+			},
+			{
+				"invalid quiet hours",
+				"org1",
+				&hub.Repository{
+					Kind:       hub.Helm,
+					Name:       "repo1",
+					URL:        "https://repo1.com",
+					QuietHours: &hub.QuietHours{Start: 22, End: 24},
+				},
+				nil,
+			},
+			{
+				"invalid metadata key",
+				"org1",
+				&hub.Repository{
+					Kind:     hub.Helm,
+					Name:     "repo1",
+					URL:      "https://repo1.com",
+					Metadata: map[string]string{"Invalid Key": "value"},
+				},
+				nil,
+			},
+			{
+				"metadata value too long",
+				"org1",
+				&hub.Repository{
+					Kind:     hub.Helm,
+					Name:     "repo1",
+					URL:      "https://repo1.com",
+					Metadata: map[string]string{"team": strings.Repeat("a", maxMetadataValueLength+1)},
+				},
+				nil,
+			},
 			{
 				"invalid url",
 				"org1",
@@ -120,6 +168,196 @@ func TestAdd(t *testing.T) {
 				},
 				errors.New("invalid url"),
 			},
+			{
+				"invalid url",
+				"org1",
+				&hub.Repository{
+					Kind: hub.OPA,
+					Name: "repo1",
+					URL:  "file:///etc/repo1",
+				},
+				nil,
+			},
+			{
+				"invalid url",
+				"org1",
+				&hub.Repository{
+					Kind: hub.OPA,
+					Name: "repo1",
+					URL:  "file:///mnt/repos/../repo1",
+				},
+				nil,
+			},
+			{
+				"credentials not supported by this repository",
+				"org1",
+				&hub.Repository{
+					Kind:     hub.OLM,
+					Name:     "repo1",
+					URL:      "https://github.com/org1/repo1",
+					AuthUser: "user1",
+					AuthPass: "pass1",
+				},
+				nil,
+			},
+			{
+				"credentials not supported by this repository",
+				"org1",
+				&hub.Repository{
+					Kind:     hub.Helm,
+					Name:     "repo1",
+					URL:      "oci://registry.io/org1/chart1",
+					AuthPass: "token1",
+				},
+				nil,
+			},
+			{
+				"password not provided",
+				"org1",
+				&hub.Repository{
+					Kind:     hub.Helm,
+					Name:     "repo1",
+					URL:      "https://repo1.com",
+					AuthUser: "user1",
+				},
+				nil,
+			},
+			{
+				"invalid password",
+				"org1",
+				&hub.Repository{
+					Kind:     hub.Helm,
+					Name:     "repo1",
+					URL:      "https://repo1.com",
+					AuthUser: "user1",
+					AuthPass: KeepSecret,
+				},
+				nil,
+			},
+			{
+				"tls settings not supported by this repository",
+				"org1",
+				&hub.Repository{
+					Kind:      hub.OLM,
+					Name:      "repo1",
+					URL:       "https://github.com/org1/repo1",
+					TLSCACert: "ca1",
+				},
+				nil,
+			},
+			{
+				"tls client key not provided",
+				"org1",
+				&hub.Repository{
+					Kind:          hub.Helm,
+					Name:          "repo1",
+					URL:           "https://repo1.com",
+					TLSClientCert: "cert1",
+				},
+				nil,
+			},
+			{
+				"invalid tls client key",
+				"org1",
+				&hub.Repository{
+					Kind:          hub.Helm,
+					Name:          "repo1",
+					URL:           "https://repo1.com",
+					TLSClientCert: "cert1",
+					TLSClientKey:  KeepSecret,
+				},
+				nil,
+			},
+			{
+				"invalid tls ca certificate",
+				"org1",
+				&hub.Repository{
+					Kind:      hub.Helm,
+					Name:      "repo1",
+					URL:       "https://repo1.com",
+					TLSCACert: "ca1",
+				},
+				nil,
+			},
+			{
+				"invalid tls client certificate",
+				"org1",
+				&hub.Repository{
+					Kind:          hub.Helm,
+					Name:          "repo1",
+					URL:           "https://repo1.com",
+					TLSClientCert: "cert1",
+					TLSClientKey:  "key1",
+				},
+				nil,
+			},
+			{
+				"git settings not supported by this repository",
+				"org1",
+				&hub.Repository{
+					Kind:   hub.Helm,
+					Name:   "repo1",
+					URL:    "https://repo1.com",
+					Branch: "release-1.0",
+				},
+				nil,
+			},
+			{
+				"branch and tag pattern cannot be used together",
+				"org1",
+				&hub.Repository{
+					Kind:       hub.OLM,
+					Name:       "repo1",
+					URL:        "https://github.com/org1/repo1",
+					Branch:     "release-1.0",
+					TagPattern: "v*",
+				},
+				nil,
+			},
+			{
+				"invalid branch",
+				"org1",
+				&hub.Repository{
+					Kind:   hub.OLM,
+					Name:   "repo1",
+					URL:    "https://github.com/org1/repo1",
+					Branch: "release..1.0",
+				},
+				nil,
+			},
+			{
+				"invalid path",
+				"org1",
+				&hub.Repository{
+					Kind: hub.OLM,
+					Name: "repo1",
+					URL:  "https://github.com/org1/repo1",
+					Path: "../packages",
+				},
+				nil,
+			},
+			{
+				"path cannot be provided when the url includes one",
+				"org1",
+				&hub.Repository{
+					Kind: hub.OLM,
+					Name: "repo1",
+					URL:  "https://github.com/org1/repo1/packages",
+					Path: "packages",
+				},
+				nil,
+			},
+			{
+				"invalid tag pattern",
+				"org1",
+				&hub.Repository{
+					Kind:       hub.OLM,
+					Name:       "repo1",
+					URL:        "https://github.com/org1/repo1",
+					TagPattern: "v[",
+				},
+				nil,
+			},
 		}
 		for _, tc := range testCases {
 			tc := tc
@@ -128,7 +366,7 @@ func TestAdd(t *testing.T) {
 				if tc.lErr != nil {
 					l.On("LoadIndex", mock.Anything).Return(nil, tc.lErr)
 				}
-				m := NewManager(nil, WithIndexLoader(l))
+				m := NewManager(nil, WithIndexLoader(l), WithAllowedLocalPaths([]string{"/mnt/repos"}))
 
 				err := m.Add(ctx, tc.orgName, tc.r)
 				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
@@ -202,6 +440,22 @@ func TestAdd(t *testing.T) {
 					Kind:        hub.OLM,
 				},
 			},
+			{
+				&hub.Repository{
+					Name:        "repo3",
+					DisplayName: "Repository 3",
+					URL:         "file:///mnt/repos/repo3",
+					Kind:        hub.OLM,
+				},
+			},
+			{
+				&hub.Repository{
+					Name:        "repo4",
+					DisplayName: "Repository 4",
+					URL:         "file:///mnt/repos/repo4",
+					Kind:        hub.Helm,
+				},
+			},
 		}
 		for i, tc := range testCases {
 			tc := tc
@@ -212,7 +466,7 @@ func TestAdd(t *testing.T) {
 				if tc.r.Kind == hub.Helm {
 					l.On("LoadIndex", tc.r).Return(nil, nil)
 				}
-				m := NewManager(db, WithIndexLoader(l))
+				m := NewManager(db, WithIndexLoader(l), WithAllowedLocalPaths([]string{"/mnt/repos"}))
 
 				err := m.Add(ctx, "orgName", tc.r)
 				assert.NoError(t, err)
@@ -221,6 +475,238 @@ func TestAdd(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("add repository using url shortcut succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, "userID", "orgName", mock.MatchedBy(func(rJSON []byte) bool {
+			var r *hub.Repository
+			_ = json.Unmarshal(rJSON, &r)
+			return r.URL == "https://github.com/org1/repo1" && r.Branch == "main" && r.Path == "operators"
+		})).Return(nil)
+		m := NewManager(db)
+
+		err := m.Add(ctx, "orgName", &hub.Repository{
+			Name: "repo1",
+			URL:  "https://github.com/org1/repo1/tree/main/operators",
+			Kind: hub.OLM,
+		})
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("repositories per organization limit", func(t *testing.T) {
+		countQuery := "select count_org_repositories($1::text)"
+		limits := &hub.Limits{RepositoriesPerOrg: 2}
+		r := &hub.Repository{
+			Name: "repo1",
+			URL:  "https://github.com/org1/repo1",
+			Kind: hub.OLM,
+		}
+
+		t.Run("limit not reached, repository added", func(t *testing.T) {
+			db := &tests.DBMock{}
+			db.On("QueryRow", ctx, countQuery, "orgName").Return(int64(1), nil)
+			db.On("Exec", ctx, dbQuery, "userID", "orgName", mock.Anything).Return(nil)
+			m := NewManager(db, WithLimits(limits))
+
+			err := m.Add(ctx, "orgName", r)
+			assert.NoError(t, err)
+			db.AssertExpectations(t)
+		})
+
+		t.Run("limit reached, repository not added", func(t *testing.T) {
+			db := &tests.DBMock{}
+			db.On("QueryRow", ctx, countQuery, "orgName").Return(int64(2), nil)
+			m := NewManager(db, WithLimits(limits))
+
+			err := m.Add(ctx, "orgName", r)
+			assert.True(t, errors.Is(err, hub.ErrLimitExceeded))
+			assert.Equal(t, &hub.LimitExceededError{Limit: "repositories per organization", Max: 2}, err)
+			db.AssertExpectations(t)
+		})
+
+		t.Run("error counting organization repositories", func(t *testing.T) {
+			db := &tests.DBMock{}
+			db.On("QueryRow", ctx, countQuery, "orgName").Return(nil, tests.ErrFakeDatabaseFailure)
+			m := NewManager(db, WithLimits(limits))
+
+			err := m.Add(ctx, "orgName", r)
+			assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+			db.AssertExpectations(t)
+		})
+
+		t.Run("limit not applied to users repositories", func(t *testing.T) {
+			db := &tests.DBMock{}
+			db.On("Exec", ctx, dbQuery, "userID", "", mock.Anything).Return(nil)
+			m := NewManager(db, WithLimits(limits))
+
+			err := m.Add(ctx, "", r)
+			assert.NoError(t, err)
+			db.AssertExpectations(t)
+		})
+	})
+
+	t.Run("add repository with credentials succeeded", func(t *testing.T) {
+		r := &hub.Repository{
+			Name:     "repo1",
+			URL:      "https://repo1.com",
+			Kind:     hub.Helm,
+			AuthUser: "user1",
+			AuthPass: "pass1",
+		}
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, "userID", "orgName", mock.MatchedBy(func(rJSON []byte) bool {
+			var storedRepo *hub.Repository
+			_ = json.Unmarshal(rJSON, &storedRepo)
+			return storedRepo.AuthUser == "user1" && storedRepo.AuthPass == "encryptedPass"
+		})).Return(nil)
+		l := &HelmIndexLoaderMock{}
+		l.On("LoadIndex", r).Return(nil, nil)
+		sc := &secrets.CipherMock{}
+		sc.On("Encrypt", ctx, "pass1").Return("encryptedPass", nil)
+		m := NewManager(db, WithIndexLoader(l), WithSecretsCipher(sc))
+
+		err := m.Add(ctx, "orgName", r)
+		assert.NoError(t, err)
+		assert.Equal(t, "pass1", r.AuthPass)
+		db.AssertExpectations(t)
+		l.AssertExpectations(t)
+		sc.AssertExpectations(t)
+	})
+
+	t.Run("add repository with tls client certificate succeeded", func(t *testing.T) {
+		certPEM, keyPEM := generateCertificate(t)
+		r := &hub.Repository{
+			Name:          "repo1",
+			URL:           "https://repo1.com",
+			Kind:          hub.Helm,
+			TLSClientCert: certPEM,
+			TLSClientKey:  keyPEM,
+		}
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, "userID", "orgName", mock.MatchedBy(func(rJSON []byte) bool {
+			var storedRepo *hub.Repository
+			_ = json.Unmarshal(rJSON, &storedRepo)
+			return storedRepo.TLSClientCert == certPEM && storedRepo.TLSClientKey == "encryptedKey"
+		})).Return(nil)
+		l := &HelmIndexLoaderMock{}
+		l.On("LoadIndex", r).Return(nil, nil)
+		sc := &secrets.CipherMock{}
+		sc.On("Encrypt", ctx, keyPEM).Return("encryptedKey", nil)
+		m := NewManager(db, WithIndexLoader(l), WithSecretsCipher(sc))
+
+		err := m.Add(ctx, "orgName", r)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+		l.AssertExpectations(t)
+		sc.AssertExpectations(t)
+	})
+
+	t.Run("error encrypting repository password", func(t *testing.T) {
+		r := &hub.Repository{
+			Name:     "repo1",
+			URL:      "https://repo1.com",
+			Kind:     hub.Helm,
+			AuthPass: "token1",
+		}
+		l := &HelmIndexLoaderMock{}
+		l.On("LoadIndex", r).Return(nil, nil)
+		sc := &secrets.CipherMock{}
+		sc.On("Encrypt", ctx, "token1").Return("", tests.ErrFakeDatabaseFailure)
+		m := NewManager(nil, WithIndexLoader(l), WithSecretsCipher(sc))
+
+		err := m.Add(ctx, "orgName", r)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		l.AssertExpectations(t)
+		sc.AssertExpectations(t)
+	})
+}
+
+func TestAddCollaborator(t *testing.T) {
+	dbQuery := "select add_repository_collaborator($1::uuid, $2::text, $3::text, $4::text)"
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_ = m.AddCollaborator(context.Background(), "repo1", "user1", "read")
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg    string
+			repoName  string
+			userAlias string
+			role      string
+		}{
+			{
+				"repository name not provided",
+				"",
+				"user1",
+				"read",
+			},
+			{
+				"user alias not provided",
+				"repo1",
+				"",
+				"read",
+			},
+			{
+				"invalid role",
+				"repo1",
+				"user1",
+				"admin",
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil)
+				err := m.AddCollaborator(ctx, tc.repoName, tc.userAlias, tc.role)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDatabaseFailure,
+				tests.ErrFakeDatabaseFailure,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("Exec", ctx, dbQuery, "userID", "repo1", "user1", "write").Return(tc.dbErr)
+				m := NewManager(db)
+
+				err := m.AddCollaborator(ctx, "repo1", "user1", "write")
+				assert.Equal(t, tc.expectedError, err)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("add repository collaborator succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, "userID", "repo1", "user1", "write").Return(nil)
+		m := NewManager(db)
+
+		err := m.AddCollaborator(ctx, "repo1", "user1", "write")
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
 }
 
 func TestCheckAvailability(t *testing.T) {
@@ -356,6 +842,84 @@ func TestDelete(t *testing.T) {
 	})
 }
 
+func TestDeleteCollaborator(t *testing.T) {
+	dbQuery := "select delete_repository_collaborator($1::uuid, $2::text, $3::text)"
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_ = m.DeleteCollaborator(context.Background(), "repo1", "user1")
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg    string
+			repoName  string
+			userAlias string
+		}{
+			{
+				"repository name not provided",
+				"",
+				"user1",
+			},
+			{
+				"user alias not provided",
+				"repo1",
+				"",
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil)
+				err := m.DeleteCollaborator(ctx, tc.repoName, tc.userAlias)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDatabaseFailure,
+				tests.ErrFakeDatabaseFailure,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("Exec", ctx, dbQuery, "userID", "repo1", "user1").Return(tc.dbErr)
+				m := NewManager(db)
+
+				err := m.DeleteCollaborator(ctx, "repo1", "user1")
+				assert.Equal(t, tc.expectedError, err)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("delete repository collaborator succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, "userID", "repo1", "user1").Return(nil)
+		m := NewManager(db)
+
+		err := m.DeleteCollaborator(ctx, "repo1", "user1")
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+}
+
 func TestGetAll(t *testing.T) {
 	dbQuery := "select get_all_repositories()"
 	ctx := context.Background()
@@ -443,58 +1007,334 @@ func TestGetByKind(t *testing.T) {
 	db.AssertExpectations(t)
 }
 
-func TestGetByName(t *testing.T) {
-	dbQuery := "select get_repository_by_name($1::text)"
+func TestGetByMetadataJSON(t *testing.T) {
+	dbQuery := "select get_repositories_by_metadata($1::jsonb)"
+	ctx := context.Background()
+	metadata := map[string]string{"team": "team1"}
+
+	t.Run("invalid input", func(t *testing.T) {
+		m := NewManager(nil)
+		_, err := m.GetByMetadataJSON(ctx, nil)
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, []byte(`{"team":"team1"}`)).Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetByMetadataJSON(ctx, metadata)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("repositories data returned successfully", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, []byte(`{"team":"team1"}`)).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetByMetadataJSON(ctx, metadata)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetByName(t *testing.T) {
+	dbQuery := "select get_repository_by_name($1::text)"
+	ctx := context.Background()
+
+	t.Run("invalid input", func(t *testing.T) {
+		m := NewManager(nil)
+		_, err := m.GetByName(ctx, "")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("get existing repository by name", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "repo1").Return([]byte(`
+		{
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"name": "repo1",
+			"display_name": "Repo 1",
+			"url": "https://repo1.com",
+			"kind": 0
+		}
+		`), nil)
+		m := NewManager(db)
+
+		r, err := m.GetByName(context.Background(), "repo1")
+		require.NoError(t, err)
+		assert.Equal(t, "00000000-0000-0000-0000-000000000001", r.RepositoryID)
+		assert.Equal(t, "repo1", r.Name)
+		assert.Equal(t, "Repo 1", r.DisplayName)
+		assert.Equal(t, "https://repo1.com", r.URL)
+		assert.Equal(t, hub.Helm, r.Kind)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("get existing repository with credentials by name", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "repo1").Return([]byte(`
+		{
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"name": "repo1",
+			"url": "https://repo1.com",
+			"kind": 0,
+			"auth_user": "user1",
+			"auth_pass": "encryptedPass"
+		}
+		`), nil)
+		sc := &secrets.CipherMock{}
+		sc.On("Decrypt", ctx, "encryptedPass").Return("pass1", nil)
+		m := NewManager(db, WithSecretsCipher(sc))
+
+		r, err := m.GetByName(ctx, "repo1")
+		require.NoError(t, err)
+		assert.Equal(t, "user1", r.AuthUser)
+		assert.Equal(t, "pass1", r.AuthPass)
+		db.AssertExpectations(t)
+		sc.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "repo1").Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		r, err := m.GetByName(context.Background(), "repo1")
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.Nil(t, r)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("invalid json data returned from database", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "repo1").Return([]byte("invalid json"), nil)
+		m := NewManager(db)
+
+		r, err := m.GetByName(context.Background(), "repo1")
+		assert.Error(t, err)
+		assert.Nil(t, r)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetCollaboratorsJSON(t *testing.T) {
+	dbQuery := "select get_repository_collaborators($1::uuid, $2::text)"
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetCollaboratorsJSON(context.Background(), "repo1")
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		m := NewManager(nil)
+		_, err := m.GetCollaboratorsJSON(ctx, "")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDatabaseFailure,
+				tests.ErrFakeDatabaseFailure,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, dbQuery, "userID", "repo1").Return(nil, tc.dbErr)
+				m := NewManager(db)
+
+				dataJSON, err := m.GetCollaboratorsJSON(ctx, "repo1")
+				assert.Equal(t, tc.expectedError, err)
+				assert.Nil(t, dataJSON)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("repository collaborators data returned successfully", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "userID", "repo1").Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetCollaboratorsJSON(ctx, "repo1")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetDeletionImpactJSON(t *testing.T) {
+	dbQuery := "select get_repository_deletion_impact($1::uuid, $2::text)"
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetDeletionImpactJSON(context.Background(), "repo1")
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		m := NewManager(nil)
+		_, err := m.GetDeletionImpactJSON(ctx, "")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "name not provided")
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDatabaseFailure,
+				tests.ErrFakeDatabaseFailure,
+			},
+			{
+				pgx.ErrNoRows,
+				hub.ErrNotFound,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, dbQuery, "userID", "repo1").Return(nil, tc.dbErr)
+				m := NewManager(db)
+
+				dataJSON, err := m.GetDeletionImpactJSON(ctx, "repo1")
+				assert.Equal(t, tc.expectedError, err)
+				assert.Nil(t, dataJSON)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("repository deletion impact data returned successfully", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "userID", "repo1").Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetDeletionImpactJSON(ctx, "repo1")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetHealthJSON(t *testing.T) {
+	dbQuery := "select get_repository_health($1::text)"
 	ctx := context.Background()
 
 	t.Run("invalid input", func(t *testing.T) {
 		m := NewManager(nil)
-		_, err := m.GetByName(ctx, "")
+		_, err := m.GetHealthJSON(ctx, "")
 		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "repository name not provided")
 	})
 
-	t.Run("get existing repository by name", func(t *testing.T) {
-		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, dbQuery, "repo1").Return([]byte(`
-		{
-			"repository_id": "00000000-0000-0000-0000-000000000001",
-			"name": "repo1",
-			"display_name": "Repo 1",
-			"url": "https://repo1.com",
-			"kind": 0
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDatabaseFailure,
+				tests.ErrFakeDatabaseFailure,
+			},
+			{
+				pgx.ErrNoRows,
+				hub.ErrNotFound,
+			},
 		}
-		`), nil)
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, dbQuery, "repo1").Return(nil, tc.dbErr)
+				m := NewManager(db)
+
+				dataJSON, err := m.GetHealthJSON(ctx, "repo1")
+				assert.Equal(t, tc.expectedError, err)
+				assert.Nil(t, dataJSON)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("repository health data returned successfully", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "repo1").Return([]byte("dataJSON"), nil)
 		m := NewManager(db)
 
-		r, err := m.GetByName(context.Background(), "repo1")
-		require.NoError(t, err)
-		assert.Equal(t, "00000000-0000-0000-0000-000000000001", r.RepositoryID)
-		assert.Equal(t, "repo1", r.Name)
-		assert.Equal(t, "Repo 1", r.DisplayName)
-		assert.Equal(t, "https://repo1.com", r.URL)
-		assert.Equal(t, hub.Helm, r.Kind)
+		dataJSON, err := m.GetHealthJSON(ctx, "repo1")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
 		db.AssertExpectations(t)
 	})
+}
 
-	t.Run("database error", func(t *testing.T) {
+func TestGetHelmIndexValidators(t *testing.T) {
+	ctx := context.Background()
+	repoID := "00000000-0000-0000-0000-000000000001"
+	dbQuery := `
+	select
+		coalesce(index_etag, ''),
+		coalesce(index_last_modified, '')
+	from repository
+	where repository_id = $1`
+
+	t.Run("invalid input", func(t *testing.T) {
+		m := NewManager(nil)
+		_, err := m.GetHelmIndexValidators(ctx, "invalid")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, dbQuery, "repo1").Return(nil, tests.ErrFakeDatabaseFailure)
+		db.On("QueryRow", ctx, dbQuery, repoID).Return([]interface{}{
+			"etag1",
+			"Wed, 21 Oct 2015 07:28:00 GMT",
+		}, nil)
 		m := NewManager(db)
 
-		r, err := m.GetByName(context.Background(), "repo1")
-		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
-		assert.Nil(t, r)
+		v, err := m.GetHelmIndexValidators(ctx, repoID)
+		require.NoError(t, err)
+		assert.Equal(t, &hub.HelmIndexValidators{
+			ETag:         "etag1",
+			LastModified: "Wed, 21 Oct 2015 07:28:00 GMT",
+		}, v)
 		db.AssertExpectations(t)
 	})
 
-	t.Run("invalid json data returned from database", func(t *testing.T) {
+	t.Run("database error", func(t *testing.T) {
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, dbQuery, "repo1").Return([]byte("invalid json"), nil)
+		db.On("QueryRow", ctx, dbQuery, repoID).Return(nil, tests.ErrFakeDatabaseFailure)
 		m := NewManager(db)
 
-		r, err := m.GetByName(context.Background(), "repo1")
-		assert.Error(t, err)
-		assert.Nil(t, r)
+		v, err := m.GetHelmIndexValidators(ctx, repoID)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.Nil(t, v)
 		db.AssertExpectations(t)
 	})
 }
@@ -606,13 +1446,199 @@ func TestGetOwnedByUserJSON(t *testing.T) {
 	})
 }
 
+func TestRegenerateTrackingSecret(t *testing.T) {
+	dbQuery := "select regenerate_repository_tracking_secret($1::uuid, $2::text)"
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_, _ = m.RegenerateTrackingSecret(context.Background(), "repo1")
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		m := NewManager(nil)
+		_, err := m.RegenerateTrackingSecret(ctx, "")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "repository name not provided")
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDatabaseFailure,
+				tests.ErrFakeDatabaseFailure,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, dbQuery, "userID", "repo1").Return(nil, tc.dbErr)
+				m := NewManager(db)
+
+				secret, err := m.RegenerateTrackingSecret(ctx, "repo1")
+				assert.Equal(t, tc.expectedError, err)
+				assert.Empty(t, secret)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("tracking secret regenerated successfully", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "userID", "repo1").Return("secret", nil)
+		m := NewManager(db)
+
+		secret, err := m.RegenerateTrackingSecret(ctx, "repo1")
+		assert.NoError(t, err)
+		assert.Equal(t, "secret", secret)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestRequestTracking(t *testing.T) {
+	dbQuery := "select request_repository_tracking($1::text, $2::text)"
+	ctx := context.Background()
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg   string
+			repoName string
+			secret   string
+		}{
+			{
+				"repository name not provided",
+				"",
+				"secret",
+			},
+			{
+				"secret not provided",
+				"repo1",
+				"",
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil)
+				err := m.RequestTracking(ctx, tc.repoName, tc.secret)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "repo1", "secret").Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		err := m.RequestTracking(ctx, "repo1", "secret")
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("invalid secret", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "repo1", "secret").Return(false, nil)
+		m := NewManager(db)
+
+		err := m.RequestTracking(ctx, "repo1", "secret")
+		assert.Equal(t, hub.ErrInsufficientPrivilege, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("tracking requested successfully", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "repo1", "secret").Return(true, nil)
+		m := NewManager(db)
+
+		err := m.RequestTracking(ctx, "repo1", "secret")
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestSetHelmIndexValidators(t *testing.T) {
+	ctx := context.Background()
+	repoID := "00000000-0000-0000-0000-000000000001"
+	dbQuery := `
+	update repository set
+		index_etag = nullif($2, ''),
+		index_last_modified = nullif($3, '')
+	where repository_id = $1`
+	v := &hub.HelmIndexValidators{
+		ETag:         "etag1",
+		LastModified: "Wed, 21 Oct 2015 07:28:00 GMT",
+	}
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg       string
+			repositoryID string
+			v            *hub.HelmIndexValidators
+		}{
+			{
+				"invalid repository id",
+				"invalid",
+				v,
+			},
+			{
+				"index validators not provided",
+				repoID,
+				nil,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil)
+				err := m.SetHelmIndexValidators(ctx, tc.repositoryID, tc.v)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database update succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, repoID, v.ETag, v.LastModified).Return(nil)
+		m := NewManager(db)
+
+		err := m.SetHelmIndexValidators(ctx, repoID, v)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, repoID, v.ETag, v.LastModified).Return(tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		err := m.SetHelmIndexValidators(ctx, repoID, v)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		db.AssertExpectations(t)
+	})
+}
+
 func TestSetLastTrackingResults(t *testing.T) {
 	ctx := context.Background()
 	repoID := "00000000-0000-0000-0000-000000000001"
 	dbQuery := `
 	update repository set
 		last_tracking_ts = current_timestamp,
-		last_tracking_errors = nullif($2, '')
+		last_tracking_errors = nullif($2, ''),
+		tracking_runs = tracking_runs + 1,
+		tracking_failed_runs = tracking_failed_runs + (case when $2 <> '' then 1 else 0 end)
 	where repository_id = $1`
 
 	t.Run("invalid input", func(t *testing.T) {
@@ -715,6 +1741,16 @@ func TestTransfer(t *testing.T) {
 		assert.NoError(t, err)
 		db.AssertExpectations(t)
 	})
+
+	t.Run("repositories per organization limit reached", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, "select count_org_repositories($1::text)", org).Return(int64(2), nil)
+		m := NewManager(db, WithLimits(&hub.Limits{RepositoriesPerOrg: 2}))
+
+		err := m.Transfer(ctx, "repo1", org)
+		assert.True(t, errors.Is(err, hub.ErrLimitExceeded))
+		db.AssertExpectations(t)
+	})
 }
 
 func TestUpdate(t *testing.T) {
@@ -749,6 +1785,33 @@ func TestUpdate(t *testing.T) {
 				},
 				nil,
 			},
+			{
+				"invalid tracking interval",
+				&hub.Repository{
+					Name:             "repo1",
+					URL:              "https://repo1.com",
+					TrackingInterval: -1,
+				},
+				nil,
+			},
+			{
+				"invalid quiet hours",
+				&hub.Repository{
+					Name:       "repo1",
+					URL:        "https://repo1.com",
+					QuietHours: &hub.QuietHours{Start: 6, End: 6},
+				},
+				nil,
+			},
+			{
+				"invalid metadata key",
+				&hub.Repository{
+					Name:     "repo1",
+					URL:      "https://repo1.com",
+					Metadata: map[string]string{"1team": "value"},
+				},
+				nil,
+			},
 			{
 				"invalid url",
 				&hub.Repository{
@@ -767,6 +1830,16 @@ func TestUpdate(t *testing.T) {
 				},
 				errors.New("invalid url"),
 			},
+			{
+				"credentials not supported by this repository",
+				&hub.Repository{
+					Name:     "repo1",
+					URL:      "https://github.com/org1/repo1",
+					Kind:     hub.Falco,
+					AuthPass: "token1",
+				},
+				nil,
+			},
 		}
 		for _, tc := range testCases {
 			tc := tc
@@ -784,6 +1857,70 @@ func TestUpdate(t *testing.T) {
 		}
 	})
 
+	t.Run("keep stored password", func(t *testing.T) {
+		getByNameQuery := "select get_repository_by_name($1::text)"
+		storedRepoJSON := []byte(`
+		{
+			"name": "repo1",
+			"url": "https://repo1.com",
+			"kind": 0,
+			"auth_user": "user1",
+			"auth_pass": "encryptedPass"
+		}
+		`)
+
+		t.Run("url changed", func(t *testing.T) {
+			r := &hub.Repository{
+				Name:     "repo1",
+				URL:      "https://repo2.com",
+				Kind:     hub.Helm,
+				AuthUser: "user1",
+				AuthPass: KeepSecret,
+			}
+			db := &tests.DBMock{}
+			db.On("QueryRow", ctx, getByNameQuery, "repo1").Return(storedRepoJSON, nil)
+			sc := &secrets.CipherMock{}
+			sc.On("Decrypt", ctx, "encryptedPass").Return("pass1", nil)
+			m := NewManager(db, WithSecretsCipher(sc))
+
+			err := m.Update(ctx, r)
+			assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+			assert.Contains(t, err.Error(), "password must be provided when the url changes")
+			db.AssertExpectations(t)
+			sc.AssertExpectations(t)
+		})
+
+		t.Run("update repository succeeded", func(t *testing.T) {
+			r := &hub.Repository{
+				Name:     "repo1",
+				URL:      "https://repo1.com",
+				Kind:     hub.Helm,
+				AuthUser: "user1",
+				AuthPass: KeepSecret,
+			}
+			db := &tests.DBMock{}
+			db.On("QueryRow", ctx, getByNameQuery, "repo1").Return(storedRepoJSON, nil)
+			db.On("Exec", ctx, dbQuery, "userID", mock.MatchedBy(func(rJSON []byte) bool {
+				var updatedRepo *hub.Repository
+				_ = json.Unmarshal(rJSON, &updatedRepo)
+				return updatedRepo.AuthPass == KeepSecret
+			})).Return(nil)
+			l := &HelmIndexLoaderMock{}
+			l.On("LoadIndex", mock.MatchedBy(func(r *hub.Repository) bool {
+				return r.AuthUser == "user1" && r.AuthPass == "pass1"
+			})).Return(nil, nil)
+			sc := &secrets.CipherMock{}
+			sc.On("Decrypt", ctx, "encryptedPass").Return("pass1", nil)
+			m := NewManager(db, WithIndexLoader(l), WithSecretsCipher(sc))
+
+			err := m.Update(ctx, r)
+			assert.NoError(t, err)
+			db.AssertExpectations(t)
+			l.AssertExpectations(t)
+			sc.AssertExpectations(t)
+		})
+	})
+
 	t.Run("database error", func(t *testing.T) {
 		testCases := []struct {
 			r             *hub.Repository
@@ -19,6 +19,12 @@ func (m *ManagerMock) Add(ctx context.Context, orgName string, r *hub.Repository
 	return args.Error(0)
 }
 
+// AddCollaborator implements the RepositoryManager interface.
+func (m *ManagerMock) AddCollaborator(ctx context.Context, repoName, userAlias, role string) error {
+	args := m.Called(ctx, repoName, userAlias, role)
+	return args.Error(0)
+}
+
 // CheckAvailability implements the RepositoryManager interface.
 func (m *ManagerMock) CheckAvailability(ctx context.Context, resourceKind, value string) (bool, error) {
 	args := m.Called(ctx, resourceKind, value)
@@ -31,6 +37,12 @@ func (m *ManagerMock) Delete(ctx context.Context, name string) error {
 	return args.Error(0)
 }
 
+// DeleteCollaborator implements the RepositoryManager interface.
+func (m *ManagerMock) DeleteCollaborator(ctx context.Context, repoName, userAlias string) error {
+	args := m.Called(ctx, repoName, userAlias)
+	return args.Error(0)
+}
+
 // GetAll implements the RepositoryManager interface.
 func (m *ManagerMock) GetAll(ctx context.Context) ([]*hub.Repository, error) {
 	args := m.Called(ctx)
@@ -45,6 +57,13 @@ func (m *ManagerMock) GetByKind(ctx context.Context, kind hub.RepositoryKind) ([
 	return data, args.Error(1)
 }
 
+// GetByMetadataJSON implements the RepositoryManager interface.
+func (m *ManagerMock) GetByMetadataJSON(ctx context.Context, metadata map[string]string) ([]byte, error) {
+	args := m.Called(ctx, metadata)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
 // GetByName implements the RepositoryManager interface.
 func (m *ManagerMock) GetByName(ctx context.Context, name string) (*hub.Repository, error) {
 	args := m.Called(ctx, name)
@@ -52,6 +71,37 @@ func (m *ManagerMock) GetByName(ctx context.Context, name string) (*hub.Reposito
 	return data, args.Error(1)
 }
 
+// GetCollaboratorsJSON implements the RepositoryManager interface.
+func (m *ManagerMock) GetCollaboratorsJSON(ctx context.Context, repoName string) ([]byte, error) {
+	args := m.Called(ctx, repoName)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+// GetDeletionImpactJSON implements the RepositoryManager interface.
+func (m *ManagerMock) GetDeletionImpactJSON(ctx context.Context, name string) ([]byte, error) {
+	args := m.Called(ctx, name)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+// GetHealthJSON implements the RepositoryManager interface.
+func (m *ManagerMock) GetHealthJSON(ctx context.Context, repoName string) ([]byte, error) {
+	args := m.Called(ctx, repoName)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+// GetHelmIndexValidators implements the RepositoryManager interface.
+func (m *ManagerMock) GetHelmIndexValidators(
+	ctx context.Context,
+	repositoryID string,
+) (*hub.HelmIndexValidators, error) {
+	args := m.Called(ctx, repositoryID)
+	v, _ := args.Get(0).(*hub.HelmIndexValidators)
+	return v, args.Error(1)
+}
+
 // GetPackagesDigest implements the RepositoryManager interface.
 func (m *ManagerMock) GetPackagesDigest(
 	ctx context.Context,
@@ -76,6 +126,28 @@ func (m *ManagerMock) GetOwnedByUserJSON(ctx context.Context) ([]byte, error) {
 	return data, args.Error(1)
 }
 
+// RegenerateTrackingSecret implements the RepositoryManager interface.
+func (m *ManagerMock) RegenerateTrackingSecret(ctx context.Context, repoName string) (string, error) {
+	args := m.Called(ctx, repoName)
+	return args.String(0), args.Error(1)
+}
+
+// RequestTracking implements the RepositoryManager interface.
+func (m *ManagerMock) RequestTracking(ctx context.Context, repoName, secret string) error {
+	args := m.Called(ctx, repoName, secret)
+	return args.Error(0)
+}
+
+// SetHelmIndexValidators implements the RepositoryManager interface.
+func (m *ManagerMock) SetHelmIndexValidators(
+	ctx context.Context,
+	repositoryID string,
+	v *hub.HelmIndexValidators,
+) error {
+	args := m.Called(ctx, repositoryID, v)
+	return args.Error(0)
+}
+
 // SetLastTrackingResults implements the RepositoryManager interface.
 func (m *ManagerMock) SetLastTrackingResults(ctx context.Context, repositoryID, errs string) error {
 	args := m.Called(ctx, repositoryID, errs)
@@ -107,6 +179,17 @@ func (m *HelmIndexLoaderMock) LoadIndex(r *hub.Repository) (*repo.IndexFile, err
 	return indexFile, args.Error(1)
 }
 
+// LoadIndexIfModified implements the HelmIndexLoader interface.
+func (m *HelmIndexLoaderMock) LoadIndexIfModified(
+	r *hub.Repository,
+	v *hub.HelmIndexValidators,
+) (*repo.IndexFile, *hub.HelmIndexValidators, error) {
+	args := m.Called(r, v)
+	indexFile, _ := args.Get(0).(*repo.IndexFile)
+	newV, _ := args.Get(1).(*hub.HelmIndexValidators)
+	return indexFile, newV, args.Error(2)
+}
+
 // ClonerMock is a mock implementation of the RepositoryCloner interface.
 type ClonerMock struct {
 	mock.Mock
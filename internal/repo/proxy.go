@@ -0,0 +1,29 @@
+package repo
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+// ProxyFunc returns the function used to select the proxy of the requests
+// sent to the repository provided. Repositories with a specific proxy set use
+// it for all their requests, while the proxies defined in the environment
+// (HTTP_PROXY, HTTPS_PROXY and NO_PROXY) are used for the rest.
+func ProxyFunc(r *hub.Repository) (func(*http.Request) (*url.URL, error), error) {
+	if r.ProxyURL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	u, err := url.Parse(r.ProxyURL)
+	if err != nil || u.Host == "" {
+		return nil, errors.New("invalid proxy url")
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, errors.New("invalid proxy url scheme")
+	}
+	return http.ProxyURL(u), nil
+}
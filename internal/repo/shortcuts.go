@@ -0,0 +1,61 @@
+package repo
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+var (
+	// gitShortcutRE is a regexp used to parse the shortcuts that can be used
+	// to refer to GitHub and GitLab repositories, like github:org/repo.
+	gitShortcutRE = regexp.MustCompile(`^(github|gitlab):([A-Za-z0-9_.-]+\/[A-Za-z0-9_.-]+)(\/.*)?$`)
+
+	// gitTreeURLRE is a regexp used to parse the urls of directories in
+	// GitHub or GitLab repositories, as displayed by their web interface
+	// (i.e. https://github.com/org/repo/tree/branch/path).
+	gitTreeURLRE = regexp.MustCompile(`^(https:\/\/(?:github|gitlab)\.com\/[A-Za-z0-9_.-]+\/[A-Za-z0-9_.-]+?)(?:\.git)?\/(?:-\/)?tree\/([^/]+)\/?(.*)$`)
+
+	// gitRepoBaseURLRE is a regexp used to parse the base url of GitHub or
+	// GitLab repositories, without any path.
+	gitRepoBaseURLRE = regexp.MustCompile(`^https:\/\/(github|gitlab)\.com\/([A-Za-z0-9_.-]+)\/([A-Za-z0-9_.-]+?)(?:\.git)?\/?$`)
+)
+
+// expandURLShortcut expands the well-known shortcuts that can be used when
+// adding a repository, deriving the url, branch and path expected by the
+// repository kind. The github:org/repo and gitlab:org/repo shortcuts are
+// expanded to the corresponding https url. For git based repositories, the
+// urls of directories copied from the GitHub or GitLab web interface are split
+// into the repository url, the branch and the path (branches and paths
+// provided explicitly take precedence over the ones in the url). For Helm
+// repositories, GitHub and GitLab repositories urls are replaced by the url of
+// their pages site, where charts released by tools like chart-releaser are
+// published. Urls that don't match any of the shortcuts are left untouched.
+func expandURLShortcut(r *hub.Repository) {
+	if matches := gitShortcutRE.FindStringSubmatch(r.URL); len(matches) == 4 {
+		r.URL = fmt.Sprintf("https://%s.com/%s%s", matches[1], matches[2], matches[3])
+	}
+
+	switch r.Kind {
+	case hub.Falco, hub.OLM, hub.OPA:
+		matches := gitTreeURLRE.FindStringSubmatch(r.URL)
+		if len(matches) != 4 {
+			return
+		}
+		r.URL = matches[1]
+		if r.Branch == "" && r.TagPattern == "" {
+			r.Branch = matches[2]
+		}
+		if r.Path == "" {
+			r.Path = strings.Trim(matches[3], "/")
+		}
+	case hub.Helm:
+		matches := gitRepoBaseURLRE.FindStringSubmatch(r.URL)
+		if len(matches) != 4 {
+			return
+		}
+		r.URL = fmt.Sprintf("https://%s.%s.io/%s", strings.ToLower(matches[2]), matches[1], matches[3])
+	}
+}
@@ -0,0 +1,71 @@
+package repo
+
+import (
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandURLShortcut(t *testing.T) {
+	testCases := []struct {
+		r        *hub.Repository
+		expected *hub.Repository
+	}{
+		{
+			&hub.Repository{Kind: hub.OLM, URL: "github:org1/repo1"},
+			&hub.Repository{Kind: hub.OLM, URL: "https://github.com/org1/repo1"},
+		},
+		{
+			&hub.Repository{Kind: hub.OPA, URL: "gitlab:org1/repo1/path/to/packages"},
+			&hub.Repository{Kind: hub.OPA, URL: "https://gitlab.com/org1/repo1/path/to/packages"},
+		},
+		{
+			&hub.Repository{Kind: hub.Falco, URL: "https://github.com/org1/repo1/tree/main/path/to/packages/"},
+			&hub.Repository{Kind: hub.Falco, URL: "https://github.com/org1/repo1", Branch: "main", Path: "path/to/packages"},
+		},
+		{
+			&hub.Repository{Kind: hub.OLM, URL: "https://gitlab.com/org1/repo1/-/tree/develop"},
+			&hub.Repository{Kind: hub.OLM, URL: "https://gitlab.com/org1/repo1", Branch: "develop"},
+		},
+		{
+			&hub.Repository{Kind: hub.OLM, URL: "https://github.com/org1/repo1/tree/main/path", Branch: "release", Path: "other"},
+			&hub.Repository{Kind: hub.OLM, URL: "https://github.com/org1/repo1", Branch: "release", Path: "other"},
+		},
+		{
+			&hub.Repository{Kind: hub.OLM, URL: "https://github.com/org1/repo1/tree/main", TagPattern: "v*"},
+			&hub.Repository{Kind: hub.OLM, URL: "https://github.com/org1/repo1", TagPattern: "v*"},
+		},
+		{
+			&hub.Repository{Kind: hub.OLM, URL: "https://github.com/org1/repo1/path"},
+			&hub.Repository{Kind: hub.OLM, URL: "https://github.com/org1/repo1/path"},
+		},
+		{
+			&hub.Repository{Kind: hub.Helm, URL: "https://github.com/Org1/charts"},
+			&hub.Repository{Kind: hub.Helm, URL: "https://org1.github.io/charts"},
+		},
+		{
+			&hub.Repository{Kind: hub.Helm, URL: "github:org1/charts"},
+			&hub.Repository{Kind: hub.Helm, URL: "https://org1.github.io/charts"},
+		},
+		{
+			&hub.Repository{Kind: hub.Helm, URL: "https://gitlab.com/org1/charts.git"},
+			&hub.Repository{Kind: hub.Helm, URL: "https://org1.gitlab.io/charts"},
+		},
+		{
+			&hub.Repository{Kind: hub.Helm, URL: "https://charts.repo1.com"},
+			&hub.Repository{Kind: hub.Helm, URL: "https://charts.repo1.com"},
+		},
+		{
+			&hub.Repository{Kind: hub.Helm, URL: "oci://registry.io/org1/chart1"},
+			&hub.Repository{Kind: hub.Helm, URL: "oci://registry.io/org1/chart1"},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.r.URL, func(t *testing.T) {
+			expandURLShortcut(tc.r)
+			assert.Equal(t, tc.expected, tc.r)
+		})
+	}
+}
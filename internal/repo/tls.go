@@ -0,0 +1,72 @@
+package repo
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+// HasTLSSettings checks if the repository provided has some custom tls
+// settings (ca certificate or client certificate) set.
+func HasTLSSettings(r *hub.Repository) bool {
+	return r.TLSCACert != "" || r.TLSClientCert != "" || r.TLSClientKey != ""
+}
+
+// TLSConfig returns the tls configuration that must be used to talk to the
+// repository provided. The ca certificate of the repository, if any, is
+// trusted in addition to the system ones, and its client certificate is
+// presented to the server when requested. A nil configuration is returned
+// when the repository has no custom tls settings.
+func TLSConfig(r *hub.Repository) (*tls.Config, error) {
+	if !HasTLSSettings(r) {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{}
+	if r.TLSCACert != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(r.TLSCACert)) {
+			return nil, errors.New("invalid tls ca certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if r.TLSClientCert != "" || r.TLSClientKey != "" {
+		cert, err := tls.X509KeyPair([]byte(r.TLSClientCert), []byte(r.TLSClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid tls client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// NewHTTPClient returns an http client that can be used to talk to the
+// repository provided, honoring its custom tls settings and proxy. The
+// repositories with custom tls settings or a specific proxy get their own
+// transport, while the default one (which uses the proxies defined in the
+// environment) is used for the rest.
+func NewHTTPClient(r *hub.Repository, timeout time.Duration) (*http.Client, error) {
+	hc := &http.Client{Timeout: timeout}
+	tlsConfig, err := TLSConfig(r)
+	if err != nil {
+		return nil, err
+	}
+	proxy, err := ProxyFunc(r)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil || r.ProxyURL != "" {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.TLSClientConfig = tlsConfig
+		t.Proxy = proxy
+		hc.Transport = t
+	}
+	return hc, nil
+}
@@ -0,0 +1,116 @@
+package repo
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSConfig(t *testing.T) {
+	certPEM, keyPEM := generateCertificate(t)
+
+	t.Run("no custom tls settings", func(t *testing.T) {
+		tlsConfig, err := TLSConfig(&hub.Repository{})
+		require.NoError(t, err)
+		assert.Nil(t, tlsConfig)
+	})
+
+	t.Run("invalid tls settings", func(t *testing.T) {
+		testCases := []*hub.Repository{
+			{TLSCACert: "invalid"},
+			{TLSClientCert: certPEM},
+			{TLSClientCert: certPEM, TLSClientKey: "invalid"},
+		}
+		for _, r := range testCases {
+			_, err := TLSConfig(r)
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("valid tls settings", func(t *testing.T) {
+		tlsConfig, err := TLSConfig(&hub.Repository{
+			TLSCACert:     certPEM,
+			TLSClientCert: certPEM,
+			TLSClientKey:  keyPEM,
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, tlsConfig.RootCAs)
+		assert.Len(t, tlsConfig.Certificates, 1)
+	})
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	certPEM, keyPEM := generateCertificate(t)
+	s := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	s.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	s.StartTLS()
+	defer s.Close()
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw}))
+
+	testCases := []struct {
+		r             *hub.Repository
+		expectedError bool
+	}{
+		{
+			&hub.Repository{URL: s.URL},
+			true,
+		},
+		{
+			&hub.Repository{URL: s.URL, TLSCACert: caPEM},
+			true,
+		},
+		{
+			&hub.Repository{URL: s.URL, TLSCACert: caPEM, TLSClientCert: certPEM, TLSClientKey: keyPEM},
+			false,
+		},
+	}
+	for _, tc := range testCases {
+		hc, err := NewHTTPClient(tc.r, 10*time.Second)
+		require.NoError(t, err)
+		resp, err := hc.Get(s.URL)
+		if tc.expectedError {
+			assert.Error(t, err)
+		} else {
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+	}
+}
+
+// generateCertificate generates a self signed certificate and its key for
+// tests, returning them pem encoded.
+func generateCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "artifact-hub-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return string(certPEM), string(keyPEM)
+}
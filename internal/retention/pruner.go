@@ -0,0 +1,130 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+)
+
+const defaultPruneInterval = 24 * time.Hour
+
+// categories represents the categories of data that can be pruned, in the
+// order they are processed. Notifications go before events as events can only
+// be deleted once they have no notifications.
+var categories = []string{
+	"notifications",
+	"events",
+	"packages_changes",
+	"packages_tombstones",
+	"tracking_errors",
+}
+
+// deletedRows represents the number of rows deleted (or cleared) by the
+// pruner for each category of data.
+var deletedRows = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "retention_deleted_rows_total",
+	Help: "Number of rows deleted by the data retention pruner.",
+},
+	[]string{"category"},
+)
+
+func init() {
+	prometheus.MustRegister(deletedRows)
+}
+
+// Pruner is in charge of deleting periodically the data older than the
+// retention configured for each category. Categories without a retention
+// policy are kept forever.
+type Pruner struct {
+	db       hub.DB
+	interval time.Duration
+	policies map[string]time.Duration
+}
+
+// NewPruner creates a new Pruner instance using the retention policies
+// defined in the configuration provided.
+func NewPruner(cfg *viper.Viper, db hub.DB) (*Pruner, error) {
+	p := &Pruner{
+		db:       db,
+		interval: defaultPruneInterval,
+		policies: make(map[string]time.Duration),
+	}
+	if cfg == nil {
+		return p, nil
+	}
+	if cfg.GetDuration("server.retention.interval") > 0 {
+		p.interval = cfg.GetDuration("server.retention.interval")
+	}
+	for category, v := range cfg.GetStringMapString("server.retention.policies") {
+		if !isValidCategory(category) {
+			return nil, fmt.Errorf("invalid retention policy: unknown category %s", category)
+		}
+		if v == "" {
+			continue
+		}
+		maxAge, err := time.ParseDuration(v)
+		if err != nil || maxAge <= 0 {
+			return nil, fmt.Errorf("invalid retention policy for %s: %s", category, v)
+		}
+		p.policies[category] = maxAge
+	}
+	return p, nil
+}
+
+// Run applies the retention policies periodically until it's asked to stop
+// via the context provided.
+func (p *Pruner) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	if len(p.policies) == 0 {
+		return
+	}
+
+	for {
+		p.prune(ctx)
+		select {
+		case <-time.After(p.interval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// prune deletes the data of each category with a retention policy that is
+// older than the maximum age configured.
+func (p *Pruner) prune(ctx context.Context) {
+	for _, category := range categories {
+		if _, ok := p.policies[category]; !ok {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		query := "select prune_data($1::text, $2::interval)"
+		maxAge := fmt.Sprintf("%d seconds", int64(p.policies[category].Seconds()))
+		var n int64
+		if err := p.db.QueryRow(ctx, query, category, maxAge).Scan(&n); err != nil {
+			log.Error().Err(err).Str("category", category).Msg("error pruning data")
+			continue
+		}
+		deletedRows.WithLabelValues(category).Add(float64(n))
+		log.Debug().Str("category", category).Int64("rows", n).Msg("data pruned")
+	}
+}
+
+// isValidCategory checks if the category provided is valid.
+func isValidCategory(category string) bool {
+	for _, c := range categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
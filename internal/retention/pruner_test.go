@@ -0,0 +1,92 @@
+package retention
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPruner(t *testing.T) {
+	t.Run("invalid policies", func(t *testing.T) {
+		testCases := []struct {
+			category string
+			maxAge   string
+		}{
+			{"unknown", "24h"},
+			{"notifications", "invalid"},
+			{"notifications", "-24h"},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.category+"="+tc.maxAge, func(t *testing.T) {
+				cfg := viper.New()
+				cfg.Set("server.retention.policies", map[string]string{tc.category: tc.maxAge})
+				p, err := NewPruner(cfg, nil)
+				assert.Error(t, err)
+				assert.Nil(t, p)
+			})
+		}
+	})
+
+	t.Run("valid configuration", func(t *testing.T) {
+		cfg := viper.New()
+		cfg.Set("server.retention.interval", "1h")
+		cfg.Set("server.retention.policies", map[string]string{
+			"notifications":    "720h",
+			"packages_changes": "",
+		})
+		p, err := NewPruner(cfg, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1*time.Hour, p.interval)
+		assert.Equal(t, map[string]time.Duration{"notifications": 720 * time.Hour}, p.policies)
+
+		p, err = NewPruner(viper.New(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, defaultPruneInterval, p.interval)
+		assert.Empty(t, p.policies)
+	})
+}
+
+func TestPruner(t *testing.T) {
+	query := "select prune_data($1::text, $2::interval)"
+
+	t.Run("nothing is done when there are no policies", func(t *testing.T) {
+		db := &tests.DBMock{}
+		p, _ := NewPruner(nil, db)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		p.Run(context.Background(), &wg)
+		wg.Wait()
+		db.AssertExpectations(t)
+	})
+
+	t.Run("all policies are applied, even when applying one fails", func(t *testing.T) {
+		cfg := viper.New()
+		cfg.Set("server.retention.policies", map[string]string{
+			"events":        "48h",
+			"notifications": "24h",
+		})
+		ctx, stop := context.WithCancel(context.Background())
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, query, "notifications", "86400 seconds").Return(nil, tests.ErrFakeDatabaseFailure)
+		db.On("QueryRow", ctx, query, "events", "172800 seconds").Return(int64(5), nil).Run(func(_ mock.Arguments) {
+			stop()
+		})
+		p, err := NewPruner(cfg, db)
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go p.Run(ctx, &wg)
+		wg.Wait()
+		db.AssertExpectations(t)
+	})
+}
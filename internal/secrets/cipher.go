@@ -0,0 +1,199 @@
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// prefix represents the prefix of the values encrypted by the cipher. Values
+// without it are considered plaintext values stored before the encryption
+// was enabled.
+const prefix = "enc:v1:"
+
+// dataKeySize represents the size in bytes of the data keys generated.
+const dataKeySize = 32
+
+// ErrInvalidValue indicates that the encrypted value provided is not valid.
+var ErrInvalidValue = errors.New("invalid encrypted value")
+
+// KeyProvider defines the methods a key provider must implement. Key providers
+// manage the keys used to wrap (encrypt) the data keys, so a KMS can be used
+// by implementing this interface.
+type KeyProvider interface {
+	// CurrentKeyID returns the id of the key used to wrap new data keys.
+	CurrentKeyID() string
+
+	// WrapKey encrypts the data key provided using the current key.
+	WrapKey(ctx context.Context, dataKey []byte) ([]byte, error)
+
+	// UnwrapKey decrypts the data key provided using the key identified by
+	// the key id given.
+	UnwrapKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error)
+}
+
+// Cipher implements the hub.SecretsCipher interface using envelope
+// encryption: each value is encrypted with a new random data key, which is
+// wrapped using the key provider and stored along with the encrypted value.
+type Cipher struct {
+	kp KeyProvider
+}
+
+// NewCipher creates a new Cipher instance that uses the key provider given.
+func NewCipher(kp KeyProvider) *Cipher {
+	return &Cipher{
+		kp: kp,
+	}
+}
+
+// SetupCipher creates a new Cipher instance using the key provider defined in
+// the configuration provided. A nil cipher is returned when no key provider
+// has been configured, meaning the encryption at rest is disabled.
+func SetupCipher(cfg *viper.Viper) (*Cipher, error) {
+	switch provider := cfg.GetString("secrets.provider"); provider {
+	case "":
+		return nil, nil
+	case "local":
+		kp, err := NewLocalKeyProvider(
+			cfg.GetString("secrets.local.currentKey"),
+			cfg.GetStringMapString("secrets.local.keys"),
+		)
+		if err != nil {
+			return nil, err
+		}
+		return NewCipher(kp), nil
+	default:
+		return nil, fmt.Errorf("invalid secrets key provider: %s", provider)
+	}
+}
+
+// Encrypt encrypts the plaintext value provided. Empty values are not
+// encrypted.
+func (c *Cipher) Encrypt(ctx context.Context, plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", err
+	}
+	wrappedKey, err := c.kp.WrapKey(ctx, dataKey)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(dataKey, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return prefix + strings.Join([]string{
+		c.kp.CurrentKeyID(),
+		base64.StdEncoding.EncodeToString(wrappedKey),
+		base64.StdEncoding.EncodeToString(ciphertext),
+	}, ":"), nil
+}
+
+// Decrypt decrypts the value provided. Values that were not encrypted by the
+// cipher are returned as they are.
+func (c *Cipher) Decrypt(ctx context.Context, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	keyID, wrappedKey, ciphertext, err := parse(value)
+	if err != nil {
+		return "", err
+	}
+	dataKey, err := c.kp.UnwrapKey(ctx, keyID, wrappedKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dataKey, ciphertext)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// NeedsRotation checks if the value provided has to be encrypted again, as it
+// is a plaintext value or was encrypted using a key other than the current
+// one.
+func (c *Cipher) NeedsRotation(value string) bool {
+	if value == "" {
+		return false
+	}
+	if !IsEncrypted(value) {
+		return true
+	}
+	keyID, _, _, err := parse(value)
+	return err != nil || keyID != c.kp.CurrentKeyID()
+}
+
+// IsEncrypted checks if the value provided was encrypted by a cipher.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// parse parses the encrypted value provided, returning its parts.
+func parse(value string) (keyID string, wrappedKey, ciphertext []byte, err error) {
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 || parts[0] == "" {
+		return "", nil, nil, ErrInvalidValue
+	}
+	wrappedKey, err = base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", nil, nil, ErrInvalidValue
+	}
+	ciphertext, err = base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", nil, nil, ErrInvalidValue
+	}
+	return parts[0], wrappedKey, ciphertext, nil
+}
+
+// seal encrypts and authenticates the plaintext provided using AES-GCM with
+// the key given. The nonce is prepended to the ciphertext returned.
+func seal(key, plaintext []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts and authenticates the ciphertext provided, which must have
+// been produced by seal using the same key.
+func open(key, ciphertext []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, ErrInvalidValue
+	}
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrInvalidValue
+	}
+	return plaintext, nil
+}
+
+// newAEAD returns an AES-GCM AEAD instance for the key provided.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
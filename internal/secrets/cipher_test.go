@@ -0,0 +1,162 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	key1 = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("1", dataKeySize)))
+	key2 = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("2", dataKeySize)))
+)
+
+func TestSetupCipher(t *testing.T) {
+	t.Run("no provider configured", func(t *testing.T) {
+		c, err := SetupCipher(viper.New())
+		assert.NoError(t, err)
+		assert.Nil(t, c)
+	})
+
+	t.Run("invalid provider", func(t *testing.T) {
+		cfg := viper.New()
+		cfg.Set("secrets.provider", "invalid")
+		c, err := SetupCipher(cfg)
+		assert.Error(t, err)
+		assert.Nil(t, c)
+	})
+
+	t.Run("local provider", func(t *testing.T) {
+		cfg := viper.New()
+		cfg.Set("secrets.provider", "local")
+		cfg.Set("secrets.local.currentKey", "key1")
+		cfg.Set("secrets.local.keys", map[string]string{"key1": key1})
+		c, err := SetupCipher(cfg)
+		assert.NoError(t, err)
+		assert.NotNil(t, c)
+	})
+}
+
+func TestNewLocalKeyProvider(t *testing.T) {
+	testCases := []struct {
+		description  string
+		currentKeyID string
+		keys         map[string]string
+		errMsg       string
+	}{
+		{
+			"invalid key id",
+			"key:1",
+			map[string]string{"key:1": key1},
+			"invalid key id",
+		},
+		{
+			"invalid key encoding",
+			"key1",
+			map[string]string{"key1": "invalid"},
+			"invalid key key1",
+		},
+		{
+			"invalid key size",
+			"key1",
+			map[string]string{"key1": base64.StdEncoding.EncodeToString([]byte("short"))},
+			"invalid key key1",
+		},
+		{
+			"current key not found",
+			"key2",
+			map[string]string{"key1": key1},
+			"current key key2 not found",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			_, err := NewLocalKeyProvider(tc.currentKeyID, tc.keys)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errMsg)
+		})
+	}
+}
+
+func TestCipher(t *testing.T) {
+	ctx := context.Background()
+	kp1, err := NewLocalKeyProvider("key1", map[string]string{"key1": key1})
+	require.NoError(t, err)
+	c1 := NewCipher(kp1)
+
+	t.Run("encrypt and decrypt value", func(t *testing.T) {
+		value, err := c1.Encrypt(ctx, "secret")
+		require.NoError(t, err)
+		assert.True(t, IsEncrypted(value))
+		assert.NotContains(t, value, "secret")
+		assert.False(t, c1.NeedsRotation(value))
+
+		plaintext, err := c1.Decrypt(ctx, value)
+		require.NoError(t, err)
+		assert.Equal(t, "secret", plaintext)
+	})
+
+	t.Run("same value encrypted differently each time", func(t *testing.T) {
+		value1, _ := c1.Encrypt(ctx, "secret")
+		value2, _ := c1.Encrypt(ctx, "secret")
+		assert.NotEqual(t, value1, value2)
+	})
+
+	t.Run("empty values are not encrypted", func(t *testing.T) {
+		value, err := c1.Encrypt(ctx, "")
+		require.NoError(t, err)
+		assert.Equal(t, "", value)
+		assert.False(t, c1.NeedsRotation(value))
+	})
+
+	t.Run("plaintext values are returned as they are", func(t *testing.T) {
+		plaintext, err := c1.Decrypt(ctx, "secret")
+		require.NoError(t, err)
+		assert.Equal(t, "secret", plaintext)
+		assert.True(t, c1.NeedsRotation("secret"))
+	})
+
+	t.Run("tampered value", func(t *testing.T) {
+		value, _ := c1.Encrypt(ctx, "secret")
+		parts := strings.Split(value, ":")
+		ciphertext, _ := base64.StdEncoding.DecodeString(parts[len(parts)-1])
+		ciphertext[len(ciphertext)-1] ^= 1
+		parts[len(parts)-1] = base64.StdEncoding.EncodeToString(ciphertext)
+
+		_, err := c1.Decrypt(ctx, strings.Join(parts, ":"))
+		assert.Equal(t, ErrInvalidValue, err)
+	})
+
+	t.Run("malformed value", func(t *testing.T) {
+		_, err := c1.Decrypt(ctx, prefix+"key1:invalid")
+		assert.Equal(t, ErrInvalidValue, err)
+	})
+
+	t.Run("key rotation", func(t *testing.T) {
+		value, _ := c1.Encrypt(ctx, "secret")
+
+		kp2, err := NewLocalKeyProvider("key2", map[string]string{"key1": key1, "key2": key2})
+		require.NoError(t, err)
+		c2 := NewCipher(kp2)
+		assert.True(t, c2.NeedsRotation(value))
+		plaintext, err := c2.Decrypt(ctx, value)
+		require.NoError(t, err)
+		assert.Equal(t, "secret", plaintext)
+
+		newValue, err := c2.Encrypt(ctx, plaintext)
+		require.NoError(t, err)
+		assert.False(t, c2.NeedsRotation(newValue))
+
+		// Values encrypted with the new key can't be decrypted without it
+		_, err = c1.Decrypt(ctx, newValue)
+		assert.True(t, errors.Is(err, ErrKeyNotFound))
+	})
+}
@@ -0,0 +1,62 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrKeyNotFound indicates that the key requested is not available.
+var ErrKeyNotFound = errors.New("key not found")
+
+// LocalKeyProvider is a KeyProvider implementation that uses some keys
+// provided in the configuration. Keeping the previous keys around allows
+// decrypting the values encrypted with them until they are rotated.
+type LocalKeyProvider struct {
+	currentKeyID string
+	keys         map[string][]byte
+}
+
+// NewLocalKeyProvider creates a new LocalKeyProvider instance. The keys must
+// be base64 encoded 256 bits keys, indexed by their id.
+func NewLocalKeyProvider(currentKeyID string, keys map[string]string) (*LocalKeyProvider, error) {
+	kp := &LocalKeyProvider{
+		currentKeyID: currentKeyID,
+		keys:         make(map[string][]byte, len(keys)),
+	}
+	for keyID, keyB64 := range keys {
+		if keyID == "" || strings.Contains(keyID, ":") {
+			return nil, fmt.Errorf("invalid key id: %s", keyID)
+		}
+		key, err := base64.StdEncoding.DecodeString(keyB64)
+		if err != nil || len(key) != dataKeySize {
+			return nil, fmt.Errorf("invalid key %s: a base64 encoded 256 bits key is expected", keyID)
+		}
+		kp.keys[keyID] = key
+	}
+	if _, ok := kp.keys[currentKeyID]; !ok {
+		return nil, fmt.Errorf("current key %s not found", currentKeyID)
+	}
+	return kp, nil
+}
+
+// CurrentKeyID implements the KeyProvider interface.
+func (kp *LocalKeyProvider) CurrentKeyID() string {
+	return kp.currentKeyID
+}
+
+// WrapKey implements the KeyProvider interface.
+func (kp *LocalKeyProvider) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	return seal(kp.keys[kp.currentKeyID], dataKey)
+}
+
+// UnwrapKey implements the KeyProvider interface.
+func (kp *LocalKeyProvider) UnwrapKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error) {
+	key, ok := kp.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, keyID)
+	}
+	return open(key, wrappedKey)
+}
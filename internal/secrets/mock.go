@@ -0,0 +1,24 @@
+package secrets
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// CipherMock is a mock implementation of the SecretsCipher interface.
+type CipherMock struct {
+	mock.Mock
+}
+
+// Encrypt implements the SecretsCipher interface.
+func (m *CipherMock) Encrypt(ctx context.Context, plaintext string) (string, error) {
+	args := m.Called(ctx, plaintext)
+	return args.String(0), args.Error(1)
+}
+
+// Decrypt implements the SecretsCipher interface.
+func (m *CipherMock) Decrypt(ctx context.Context, value string) (string, error) {
+	args := m.Called(ctx, value)
+	return args.String(0), args.Error(1)
+}
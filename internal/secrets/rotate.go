@@ -0,0 +1,88 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/jackc/pgx/v4"
+)
+
+// column represents a database column containing sensitive data that is
+// encrypted at rest.
+type column struct {
+	table    string
+	idColumn string
+	name     string
+}
+
+// columns represents the database columns encrypted at rest.
+var columns = []column{
+	{table: "organization", idColumn: "organization_id", name: "validation_webhook_secret"},
+	{table: "repository", idColumn: "repository_id", name: "auth_pass"},
+	{table: "repository", idColumn: "repository_id", name: "tls_client_key"},
+	{table: "webhook", idColumn: "webhook_id", name: "secret"},
+}
+
+// Rotate encrypts again using the current key all the sensitive values stored
+// in the database that are in plaintext or were encrypted with a previous key,
+// returning the number of values updated. Once it completes, the previous keys
+// can be removed from the key provider.
+func Rotate(ctx context.Context, db hub.DB, c *Cipher) (int64, error) {
+	var updated int64
+	err := util.DBTransact(ctx, db, func(tx pgx.Tx) error {
+		for _, col := range columns {
+			table := pgx.Identifier{col.table}.Sanitize()
+			idColumn := pgx.Identifier{col.idColumn}.Sanitize()
+			name := pgx.Identifier{col.name}.Sanitize()
+
+			// Get values stored
+			query := fmt.Sprintf(`
+			select coalesce(json_agg(json_build_object('id', id, 'value', value)), '[]')
+			from (
+				select %s::text as id, %s as value
+				from %s
+				where %s is not null
+				for update
+			) v`, idColumn, name, table, name)
+			var dataJSON []byte
+			if err := tx.QueryRow(ctx, query).Scan(&dataJSON); err != nil {
+				return err
+			}
+			var values []*struct {
+				ID    string `json:"id"`
+				Value string `json:"value"`
+			}
+			if err := json.Unmarshal(dataJSON, &values); err != nil {
+				return err
+			}
+
+			// Encrypt again the ones that need it
+			query = fmt.Sprintf("update %s set %s = $1 where %s = $2", table, name, idColumn)
+			for _, v := range values {
+				if !c.NeedsRotation(v.Value) {
+					continue
+				}
+				plaintext, err := c.Decrypt(ctx, v.Value)
+				if err != nil {
+					return fmt.Errorf("error decrypting %s.%s (%s): %w", col.table, col.name, v.ID, err)
+				}
+				newValue, err := c.Encrypt(ctx, plaintext)
+				if err != nil {
+					return err
+				}
+				if _, err := tx.Exec(ctx, query, newValue, v.ID); err != nil {
+					return err
+				}
+				updated++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return updated, nil
+}
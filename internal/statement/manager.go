@@ -0,0 +1,112 @@
+package statement
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/satori/uuid"
+)
+
+// Manager provides an API to manage the signed statements attached to the
+// packages by their publishers.
+type Manager struct {
+	db hub.DB
+}
+
+// NewManager creates a new Manager instance.
+func NewManager(db hub.DB) *Manager {
+	return &Manager{
+		db: db,
+	}
+}
+
+// Add attaches the provided statement to the given package, replacing any
+// existing statement of the same kind. The user doing the request must be
+// allowed to update the package's repository.
+func (m *Manager) Add(ctx context.Context, packageID string, s *hub.PackageStatement) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if _, err := uuid.FromString(packageID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
+	}
+	if !isValidKind(s.Kind) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid kind")
+	}
+	if s.Content == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "content not provided")
+	}
+	if s.Signature == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "signature not provided")
+	}
+	if s.SignatureAlgorithm == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "signature algorithm not provided")
+	}
+
+	// Add package statement to database
+	query := "select add_package_statement($1::uuid, $2::uuid, $3::jsonb)"
+	sJSON, _ := json.Marshal(s)
+	_, err := m.db.Exec(ctx, query, userID, packageID, sJSON)
+	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+		return hub.ErrInsufficientPrivilege
+	}
+	return err
+}
+
+// Delete removes the statement of the kind provided from the given package.
+// The user doing the request must be allowed to update the package's
+// repository.
+func (m *Manager) Delete(ctx context.Context, packageID, kind string) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if _, err := uuid.FromString(packageID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
+	}
+	if !isValidKind(kind) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid kind")
+	}
+
+	// Delete package statement from database
+	query := "select delete_package_statement($1::uuid, $2::uuid, $3::text)"
+	_, err := m.db.Exec(ctx, query, userID, packageID, kind)
+	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+		return hub.ErrInsufficientPrivilege
+	}
+	return err
+}
+
+// GetByPackageJSON returns the statements attached to the provided package as
+// a json array. The statements of packages in private repositories are only
+// returned to the users with read access to them.
+func (m *Manager) GetByPackageJSON(ctx context.Context, packageID string) ([]byte, error) {
+	// Validate input
+	if _, err := uuid.FromString(packageID); err != nil {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
+	}
+
+	// Get package statements from database
+	query := "select get_package_statements($1::uuid, $2::uuid)"
+	var userID *string
+	if v, _ := ctx.Value(hub.UserIDKey).(string); v != "" {
+		userID = &v
+	}
+	var dataJSON []byte
+	if err := m.db.QueryRow(ctx, query, userID, packageID).Scan(&dataJSON); err != nil {
+		return nil, err
+	}
+	return dataJSON, nil
+}
+
+// isValidKind checks if the provided statement kind is valid.
+func isValidKind(kind string) bool {
+	for _, validKind := range hub.PackageStatementKinds {
+		if kind == validKind {
+			return true
+		}
+	}
+	return false
+}
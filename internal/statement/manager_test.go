@@ -0,0 +1,250 @@
+package statement
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+const packageID = "00000000-0000-0000-0000-000000000001"
+
+func TestAdd(t *testing.T) {
+	dbQuery := "select add_package_statement($1::uuid, $2::uuid, $3::jsonb)"
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+	s := &hub.PackageStatement{
+		Kind:               "eol",
+		Content:            "EOL on 2021-01-01",
+		Signature:          "signature",
+		SignatureAlgorithm: "ed25519",
+		SignatureKeyID:     "key1",
+	}
+	sJSON := []byte(`{"kind":"eol","content":"EOL on 2021-01-01","signature":"signature","signature_algorithm":"ed25519","signature_key_id":"key1"}`)
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_ = m.Add(context.Background(), packageID, s)
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg    string
+			packageID string
+			s         *hub.PackageStatement
+		}{
+			{
+				"invalid package id",
+				"invalid",
+				s,
+			},
+			{
+				"invalid kind",
+				packageID,
+				&hub.PackageStatement{
+					Kind: "invalid",
+				},
+			},
+			{
+				"content not provided",
+				packageID,
+				&hub.PackageStatement{
+					Kind: "eol",
+				},
+			},
+			{
+				"signature not provided",
+				packageID,
+				&hub.PackageStatement{
+					Kind:    "eol",
+					Content: "content",
+				},
+			},
+			{
+				"signature algorithm not provided",
+				packageID,
+				&hub.PackageStatement{
+					Kind:      "eol",
+					Content:   "content",
+					Signature: "signature",
+				},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil)
+				err := m.Add(ctx, tc.packageID, tc.s)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDatabaseFailure,
+				tests.ErrFakeDatabaseFailure,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("Exec", ctx, dbQuery, "userID", packageID, sJSON).Return(tc.dbErr)
+				m := NewManager(db)
+
+				err := m.Add(ctx, packageID, s)
+				assert.Equal(t, tc.expectedError, err)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("add statement succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, "userID", packageID, sJSON).Return(nil)
+		m := NewManager(db)
+
+		err := m.Add(ctx, packageID, s)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	dbQuery := "select delete_package_statement($1::uuid, $2::uuid, $3::text)"
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_ = m.Delete(context.Background(), packageID, "eol")
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg    string
+			packageID string
+			kind      string
+		}{
+			{
+				"invalid package id",
+				"invalid",
+				"eol",
+			},
+			{
+				"invalid kind",
+				packageID,
+				"invalid",
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil)
+				err := m.Delete(ctx, tc.packageID, tc.kind)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDatabaseFailure,
+				tests.ErrFakeDatabaseFailure,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				db := &tests.DBMock{}
+				db.On("Exec", ctx, dbQuery, "userID", packageID, "eol").Return(tc.dbErr)
+				m := NewManager(db)
+
+				err := m.Delete(ctx, packageID, "eol")
+				assert.Equal(t, tc.expectedError, err)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("delete statement succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, "userID", packageID, "eol").Return(nil)
+		m := NewManager(db)
+
+		err := m.Delete(ctx, packageID, "eol")
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetByPackageJSON(t *testing.T) {
+	dbQuery := "select get_package_statements($1::uuid, $2::uuid)"
+	ctx := context.Background()
+
+	t.Run("invalid input", func(t *testing.T) {
+		m := NewManager(nil)
+		_, err := m.GetByPackageJSON(ctx, "invalid")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, (*string)(nil), packageID).Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetByPackageJSON(ctx, packageID)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("package statements data returned successfully", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, (*string)(nil), packageID).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetByPackageJSON(ctx, packageID)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("requesting user is provided to the database", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+		userID := "userID"
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, &userID, packageID).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetByPackageJSON(ctx, packageID)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+}
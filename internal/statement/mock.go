@@ -0,0 +1,32 @@
+package statement
+
+import (
+	"context"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/mock"
+)
+
+// ManagerMock is a mock implementation of the StatementManager interface.
+type ManagerMock struct {
+	mock.Mock
+}
+
+// Add implements the StatementManager interface.
+func (m *ManagerMock) Add(ctx context.Context, packageID string, s *hub.PackageStatement) error {
+	args := m.Called(ctx, packageID, s)
+	return args.Error(0)
+}
+
+// Delete implements the StatementManager interface.
+func (m *ManagerMock) Delete(ctx context.Context, packageID, kind string) error {
+	args := m.Called(ctx, packageID, kind)
+	return args.Error(0)
+}
+
+// GetByPackageJSON implements the StatementManager interface.
+func (m *ManagerMock) GetByPackageJSON(ctx context.Context, packageID string) ([]byte, error) {
+	args := m.Called(ctx, packageID)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
@@ -0,0 +1,212 @@
+package subscription
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/artifacthub/hub/internal/email"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/i18n"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/jackc/pgx/v4"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+)
+
+const (
+	defaultDigestInterval = 7 * 24 * time.Hour
+	pauseOnEmptyQueue     = 5 * time.Minute
+	pauseOnError          = 1 * time.Minute
+
+	// digestFromHour and digestToHour represent the hours of the day, in the
+	// users local time (UTC when they haven't set a timezone), between which
+	// digests are delivered.
+	digestFromHour = 8
+	digestToHour   = 18
+)
+
+// Digester is in charge of delivering periodically to the users who have
+// enabled it a digest email with the changes introduced in the packages they
+// are subscribed to, during working hours of their timezone.
+type Digester struct {
+	db       hub.DB
+	sm       hub.SubscriptionManager
+	es       hub.EmailSender
+	baseURL  string
+	interval time.Duration
+}
+
+// NewDigester creates a new Digester instance.
+func NewDigester(
+	cfg *viper.Viper,
+	db hub.DB,
+	sm hub.SubscriptionManager,
+	es hub.EmailSender,
+) *Digester {
+	interval := defaultDigestInterval
+	if cfg != nil && cfg.GetDuration("server.subscriptionsDigestInterval") > 0 {
+		interval = cfg.GetDuration("server.subscriptionsDigestInterval")
+	}
+	var baseURL string
+	if cfg != nil {
+		baseURL = cfg.GetString("server.baseURL")
+	}
+	return &Digester{
+		db:       db,
+		sm:       sm,
+		es:       es,
+		baseURL:  baseURL,
+		interval: interval,
+	}
+}
+
+// Run processes the pending digests until it's asked to stop via the context
+// provided.
+func (d *Digester) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for {
+		err := d.processDigest(ctx)
+		switch {
+		case err == nil:
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+		case errors.Is(err, pgx.ErrNoRows):
+			select {
+			case <-time.After(pauseOnEmptyQueue):
+			case <-ctx.Done():
+				return
+			}
+		default:
+			select {
+			case <-time.After(pauseOnError):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// processDigest gets a user whose subscriptions digest is due, delivering it
+// when any changes have been introduced in the packages the user is subscribed
+// to since the last one. Delivery errors are logged, but they don't prevent
+// the digest from being considered done.
+func (d *Digester) processDigest(ctx context.Context) error {
+	return util.DBTransact(ctx, d.db, func(tx pgx.Tx) error {
+		// Get pending digest to process
+		digest, err := d.sm.GetPendingDigest(ctx, tx, d.interval, digestFromHour, digestToHour)
+		if err != nil {
+			if !errors.Is(err, pgx.ErrNoRows) {
+				log.Error().Err(err).Msg("error getting pending subscriptions digest")
+			}
+			return err
+		}
+
+		// Prepare and deliver digest
+		entries, err := d.getDigestEntries(ctx, digest)
+		if err != nil {
+			log.Error().Err(err).Str("userID", digest.User.UserID).Msg("error getting subscriptions changes")
+			return err
+		}
+		if len(entries) > 0 && d.es != nil {
+			if err := d.deliverDigest(digest, entries); err != nil {
+				log.Error().Err(err).Str("userID", digest.User.UserID).Msg("error delivering subscriptions digest")
+			}
+		}
+
+		// Update last digest
+		err = d.sm.UpdateLastDigest(ctx, tx, digest.User.UserID)
+		if err != nil {
+			log.Error().Err(err).Str("userID", digest.User.UserID).Msg("error updating last digest")
+		}
+		return err
+	})
+}
+
+// changesEntry represents an entry of the changes introduced in the packages
+// a user is subscribed to.
+type changesEntry struct {
+	Name           string          `json:"name"`
+	NormalizedName string          `json:"normalized_name"`
+	Version        string          `json:"version"`
+	Changes        []string        `json:"changes"`
+	CreatedAt      int64           `json:"created_at"`
+	Repository     *hub.Repository `json:"repository"`
+}
+
+// getDigestEntries returns the changes entries registered since the last
+// digest of the user provided. Only the most recent maxChangesLimit entries
+// are included.
+func (d *Digester) getDigestEntries(ctx context.Context, digest *hub.SubscriptionsDigest) ([]*changesEntry, error) {
+	userCtx := context.WithValue(ctx, hub.UserIDKey, digest.User.UserID)
+	dataJSON, err := d.sm.GetUserChangesJSON(userCtx, maxChangesLimit, 0)
+	if err != nil {
+		return nil, err
+	}
+	var changes struct {
+		Changes []*changesEntry `json:"changes"`
+	}
+	if err := json.Unmarshal(dataJSON, &changes); err != nil {
+		return nil, err
+	}
+	var entries []*changesEntry
+	for _, e := range changes.Changes {
+		if e.CreatedAt > digest.Since {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// digestEntry represents an entry included in a digest.
+type digestEntry struct {
+	Name    string
+	Version string
+	Changes []string
+	URL     string
+}
+
+// digestData represents the data used to render the digest email.
+type digestData struct {
+	BaseURL string
+	Entries []*digestEntry
+}
+
+// deliverDigest delivers the digest provided via email to the user.
+func (d *Digester) deliverDigest(digest *hub.SubscriptionsDigest, entries []*changesEntry) error {
+	data := &digestData{
+		BaseURL: d.baseURL,
+		Entries: make([]*digestEntry, 0, len(entries)),
+	}
+	for _, e := range entries {
+		data.Entries = append(data.Entries, &digestEntry{
+			Name:    e.Name,
+			Version: e.Version,
+			Changes: e.Changes,
+			URL: fmt.Sprintf("%s/packages/%s/%s/%s/%s",
+				d.baseURL,
+				hub.GetKindName(e.Repository.Kind),
+				e.Repository.Name,
+				e.NormalizedName,
+				e.Version,
+			),
+		})
+	}
+	var body bytes.Buffer
+	if err := digestEmailTmpl.Execute(&body, digest.User.Locale, data); err != nil {
+		return err
+	}
+	return d.es.SendEmail(&email.Data{
+		To:      digest.User.Email,
+		Subject: i18n.T(digest.User.Locale, "Your Artifact Hub subscriptions digest"),
+		Body:    body.Bytes(),
+	})
+}
@@ -0,0 +1,190 @@
+package subscription
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/email"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDigester(t *testing.T) {
+	digest := &hub.SubscriptionsDigest{
+		User: &hub.User{
+			UserID: userID,
+			Email:  "user1@email.com",
+		},
+		Since: 1000,
+	}
+	changesJSON := []byte(`
+	{
+		"changes": [
+			{
+				"package_id": "00000000-0000-0000-0000-000000000001",
+				"name": "pkg1",
+				"normalized_name": "pkg1",
+				"version": "2.0.0",
+				"changes": ["Added feature 1"],
+				"created_at": 2000,
+				"repository": {
+					"kind": 0,
+					"name": "repo1"
+				}
+			},
+			{
+				"package_id": "00000000-0000-0000-0000-000000000001",
+				"name": "pkg1",
+				"normalized_name": "pkg1",
+				"version": "1.0.0",
+				"changes": ["Initial release"],
+				"created_at": 500,
+				"repository": {
+					"kind": 0,
+					"name": "repo1"
+				}
+			}
+		],
+		"total": 2
+	}
+	`)
+	noNewChangesJSON := []byte(`
+	{
+		"changes": [
+			{
+				"package_id": "00000000-0000-0000-0000-000000000001",
+				"name": "pkg1",
+				"normalized_name": "pkg1",
+				"version": "1.0.0",
+				"changes": ["Initial release"],
+				"created_at": 500,
+				"repository": {
+					"kind": 0,
+					"name": "repo1"
+				}
+			}
+		],
+		"total": 1
+	}
+	`)
+	userCtx := mock.MatchedBy(func(ctx context.Context) bool {
+		return ctx.Value(hub.UserIDKey) == userID
+	})
+
+	t.Run("no pending digests", func(t *testing.T) {
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.sm.On("GetPendingDigest", sw.ctx, sw.tx, defaultDigestInterval, digestFromHour, digestToHour).Return(nil, pgx.ErrNoRows)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+
+		d := NewDigester(nil, sw.db, sw.sm, sw.es)
+		go d.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("error getting subscriptions changes", func(t *testing.T) {
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.sm.On("GetPendingDigest", sw.ctx, sw.tx, defaultDigestInterval, digestFromHour, digestToHour).Return(digest, nil)
+		sw.sm.On("GetUserChangesJSON", userCtx, maxChangesLimit, 0).Return(nil, tests.ErrFakeDatabaseFailure)
+		sw.tx.On("Rollback", sw.ctx).Return(nil)
+
+		d := NewDigester(nil, sw.db, sw.sm, sw.es)
+		go d.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("no changes since last digest, digest not delivered", func(t *testing.T) {
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.sm.On("GetPendingDigest", sw.ctx, sw.tx, defaultDigestInterval, digestFromHour, digestToHour).Return(digest, nil)
+		sw.sm.On("GetUserChangesJSON", userCtx, maxChangesLimit, 0).Return(noNewChangesJSON, nil)
+		sw.sm.On("UpdateLastDigest", sw.ctx, sw.tx, userID).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		d := NewDigester(nil, sw.db, sw.sm, sw.es)
+		go d.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("error delivering digest, last digest updated anyway", func(t *testing.T) {
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.sm.On("GetPendingDigest", sw.ctx, sw.tx, defaultDigestInterval, digestFromHour, digestToHour).Return(digest, nil)
+		sw.sm.On("GetUserChangesJSON", userCtx, maxChangesLimit, 0).Return(changesJSON, nil)
+		sw.es.On("SendEmail", mock.Anything).Return(email.ErrFakeSenderFailure)
+		sw.sm.On("UpdateLastDigest", sw.ctx, sw.tx, userID).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		d := NewDigester(nil, sw.db, sw.sm, sw.es)
+		go d.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("digest delivered successfully", func(t *testing.T) {
+		sw := newServicesWrapper()
+		sw.db.On("Begin", sw.ctx).Return(sw.tx, nil)
+		sw.sm.On("GetPendingDigest", sw.ctx, sw.tx, defaultDigestInterval, digestFromHour, digestToHour).Return(digest, nil)
+		sw.sm.On("GetUserChangesJSON", userCtx, maxChangesLimit, 0).Return(changesJSON, nil)
+		sw.es.On("SendEmail", mock.MatchedBy(func(d *email.Data) bool {
+			body := string(d.Body)
+			return d.To == "user1@email.com" &&
+				d.Subject == "Your Artifact Hub subscriptions digest" &&
+				strings.Contains(body, "/packages/helm/repo1/pkg1/2.0.0") &&
+				strings.Contains(body, "Added feature 1") &&
+				!strings.Contains(body, "Initial release")
+		})).Return(nil)
+		sw.sm.On("UpdateLastDigest", sw.ctx, sw.tx, userID).Return(nil)
+		sw.tx.On("Commit", sw.ctx).Return(nil)
+
+		d := NewDigester(nil, sw.db, sw.sm, sw.es)
+		go d.Run(sw.ctx, sw.wg)
+		sw.assertExpectations(t)
+	})
+}
+
+type servicesWrapper struct {
+	ctx          context.Context
+	stopDigester context.CancelFunc
+	wg           *sync.WaitGroup
+	db           *tests.DBMock
+	tx           *tests.TXMock
+	sm           *ManagerMock
+	es           *email.SenderMock
+}
+
+func newServicesWrapper() *servicesWrapper {
+	// Context and wait group used for Digester.Run()
+	ctx, stopDigester := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	return &servicesWrapper{
+		ctx:          ctx,
+		stopDigester: stopDigester,
+		wg:           &wg,
+		db:           &tests.DBMock{},
+		tx:           &tests.TXMock{},
+		sm:           &ManagerMock{},
+		es:           &email.SenderMock{},
+	}
+}
+
+func (sw *servicesWrapper) assertExpectations(t *testing.T) {
+	sw.stopDigester()
+	assert.Eventually(t, func() bool {
+		sw.wg.Wait()
+		return true
+	}, 2*time.Second, 100*time.Millisecond)
+
+	sw.db.AssertExpectations(t)
+	sw.tx.AssertExpectations(t)
+	sw.sm.AssertExpectations(t)
+	sw.es.AssertExpectations(t)
+}
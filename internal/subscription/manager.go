@@ -4,11 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/jackc/pgx/v4"
 	"github.com/satori/uuid"
 )
 
+// maxChangesLimit represents the maximum number of changes entries that can be
+// requested at once.
+const maxChangesLimit = 60
+
 // Manager provides an API to manage subscriptions.
 type Manager struct {
 	db hub.DB
@@ -74,6 +80,28 @@ func (m *Manager) GetByUserJSON(ctx context.Context) ([]byte, error) {
 	return dataJSON, nil
 }
 
+// GetPendingDigest returns the subscriptions digest of a user whose delivery
+// is due if available. Digests are due once the interval provided has passed
+// since the last one was delivered, and only between the hours provided of the
+// local time of the user.
+func (m *Manager) GetPendingDigest(
+	ctx context.Context,
+	tx pgx.Tx,
+	interval time.Duration,
+	fromHour, toHour int,
+) (*hub.SubscriptionsDigest, error) {
+	query := "select get_pending_subscriptions_digest($1::interval, $2::int, $3::int)"
+	var dataJSON []byte
+	if err := tx.QueryRow(ctx, query, interval, fromHour, toHour).Scan(&dataJSON); err != nil {
+		return nil, err
+	}
+	var d *hub.SubscriptionsDigest
+	if err := json.Unmarshal(dataJSON, &d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
 // GetSubscriptors returns the users subscribed to a package to receive
 // notifications for certain kind of events.
 func (m *Manager) GetSubscriptors(
@@ -84,7 +112,7 @@ func (m *Manager) GetSubscriptors(
 	if _, err := uuid.FromString(packageID); err != nil {
 		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
 	}
-	if eventKind != hub.NewRelease {
+	if !isValidEventKind(eventKind) {
 		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid event kind")
 	}
 
@@ -101,14 +129,51 @@ func (m *Manager) GetSubscriptors(
 	return subscriptors, nil
 }
 
+// GetUserChangesJSON returns the changes introduced in the versions of the
+// packages the user doing the request is subscribed to as a json object,
+// newest first. The json object is built by the database.
+func (m *Manager) GetUserChangesJSON(ctx context.Context, limit, offset int) ([]byte, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+	if limit <= 0 || limit > maxChangesLimit {
+		return nil, fmt.Errorf("%w: invalid limit (0 < l <= %d)", hub.ErrInvalidInput, maxChangesLimit)
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid offset (o >= 0)")
+	}
+	query := "select get_user_subscriptions_changes($1::uuid, $2::int, $3::int)"
+	var dataJSON []byte
+	if err := m.db.QueryRow(ctx, query, userID, limit, offset).Scan(&dataJSON); err != nil {
+		return nil, err
+	}
+	return dataJSON, nil
+}
+
+// UpdateLastDigest records that the subscriptions digest of the provided user
+// has just been delivered.
+func (m *Manager) UpdateLastDigest(ctx context.Context, tx pgx.Tx, userID string) error {
+	query := "select update_subscriptions_last_digest($1::uuid)"
+	_, err := tx.Exec(ctx, query, userID)
+	return err
+}
+
 // validateSubscription checks if the subscription provided is valid to be used
 // as input for some database functions calls.
 func validateSubscription(s *hub.Subscription) error {
 	if _, err := uuid.FromString(s.PackageID); err != nil {
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid package id")
 	}
-	if s.EventKind != hub.NewRelease {
+	if !isValidEventKind(s.EventKind) {
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid event kind")
 	}
 	return nil
 }
+
+// isValidEventKind checks if the provided event kind can be subscribed to.
+func isValidEventKind(eventKind hub.EventKind) bool {
+	for _, validKind := range hub.SubscribableEventKinds {
+		if eventKind == validKind {
+			return true
+		}
+	}
+	return false
+}
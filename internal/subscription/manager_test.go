@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/tests"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -225,6 +227,50 @@ func TestGetByUserJSON(t *testing.T) {
 	})
 }
 
+func TestGetPendingDigest(t *testing.T) {
+	dbQuery := "select get_pending_subscriptions_digest($1::interval, $2::int, $3::int)"
+	ctx := context.Background()
+	interval := 7 * 24 * time.Hour
+
+	t.Run("database error", func(t *testing.T) {
+		tx := &tests.TXMock{}
+		tx.On("QueryRow", ctx, dbQuery, interval, 8, 18).Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(nil)
+
+		d, err := m.GetPendingDigest(ctx, tx, interval, 8, 18)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.Nil(t, d)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		tx := &tests.TXMock{}
+		tx.On("QueryRow", ctx, dbQuery, interval, 8, 18).Return([]byte(`
+		{
+			"user": {
+				"user_id": "00000000-0000-0000-0000-000000000001",
+				"email": "user1@email.com",
+				"locale": "es"
+			},
+			"since": 1600000000
+		}
+		`), nil)
+		m := NewManager(nil)
+
+		d, err := m.GetPendingDigest(ctx, tx, interval, 8, 18)
+		require.NoError(t, err)
+		assert.Equal(t, &hub.SubscriptionsDigest{
+			User: &hub.User{
+				UserID: userID,
+				Email:  "user1@email.com",
+				Locale: "es",
+			},
+			Since: 1600000000,
+		}, d)
+		tx.AssertExpectations(t)
+	})
+}
+
 func TestGetSubscriptors(t *testing.T) {
 	dbQuery := "select get_subscriptors($1::uuid, $2::integer)"
 	ctx := context.Background()
@@ -298,3 +344,84 @@ func TestGetSubscriptors(t *testing.T) {
 		db.AssertExpectations(t)
 	})
 }
+
+func TestGetUserChangesJSON(t *testing.T) {
+	dbQuery := "select get_user_subscriptions_changes($1::uuid, $2::int, $3::int)"
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, userID)
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetUserChangesJSON(context.Background(), 10, 0)
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg string
+			limit  int
+			offset int
+		}{
+			{"invalid limit", 0, 0},
+			{"invalid limit", maxChangesLimit + 1, 0},
+			{"invalid offset", 10, -1},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil)
+				dataJSON, err := m.GetUserChangesJSON(ctx, tc.limit, tc.offset)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+				assert.Nil(t, dataJSON)
+			})
+		}
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, userID, 10, 0).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetUserChangesJSON(ctx, 10, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, userID, 10, 0).Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetUserChangesJSON(ctx, 10, 0)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestUpdateLastDigest(t *testing.T) {
+	dbQuery := "select update_subscriptions_last_digest($1::uuid)"
+	ctx := context.Background()
+
+	t.Run("database error", func(t *testing.T) {
+		tx := &tests.TXMock{}
+		tx.On("Exec", ctx, dbQuery, userID).Return(tests.ErrFakeDatabaseFailure)
+		m := NewManager(nil)
+
+		err := m.UpdateLastDigest(ctx, tx, userID)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("last digest updated successfully", func(t *testing.T) {
+		tx := &tests.TXMock{}
+		tx.On("Exec", ctx, dbQuery, userID).Return(nil)
+		m := NewManager(nil)
+
+		err := m.UpdateLastDigest(ctx, tx, userID)
+		assert.NoError(t, err)
+		tx.AssertExpectations(t)
+	})
+}
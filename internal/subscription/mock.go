@@ -2,8 +2,10 @@ package subscription
 
 import (
 	"context"
+	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -38,6 +40,18 @@ func (m *ManagerMock) GetByUserJSON(ctx context.Context) ([]byte, error) {
 	return data, args.Error(1)
 }
 
+// GetPendingDigest implements the SubscriptionManager interface.
+func (m *ManagerMock) GetPendingDigest(
+	ctx context.Context,
+	tx pgx.Tx,
+	interval time.Duration,
+	fromHour, toHour int,
+) (*hub.SubscriptionsDigest, error) {
+	args := m.Called(ctx, tx, interval, fromHour, toHour)
+	data, _ := args.Get(0).(*hub.SubscriptionsDigest)
+	return data, args.Error(1)
+}
+
 // GetByUserJSON implements the SubscriptionManager interface.
 func (m *ManagerMock) GetSubscriptors(
 	ctx context.Context,
@@ -48,3 +62,16 @@ func (m *ManagerMock) GetSubscriptors(
 	data, _ := args.Get(0).([]*hub.User)
 	return data, args.Error(1)
 }
+
+// GetUserChangesJSON implements the SubscriptionManager interface.
+func (m *ManagerMock) GetUserChangesJSON(ctx context.Context, limit, offset int) ([]byte, error) {
+	args := m.Called(ctx, limit, offset)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+// UpdateLastDigest implements the SubscriptionManager interface.
+func (m *ManagerMock) UpdateLastDigest(ctx context.Context, tx pgx.Tx, userID string) error {
+	args := m.Called(ctx, tx, userID)
+	return args.Error(0)
+}
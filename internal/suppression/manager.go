@@ -0,0 +1,48 @@
+package suppression
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+// Manager provides an API to manage the list of email addresses no more
+// emails should be sent to, usually because they bounced or their owners
+// complained about the emails received.
+type Manager struct {
+	db hub.DB
+}
+
+// NewManager creates a new Manager instance.
+func NewManager(db hub.DB) *Manager {
+	return &Manager{
+		db: db,
+	}
+}
+
+// IsSuppressed checks if the email address provided is in the suppression
+// list.
+func (m *Manager) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	query := "select is_email_suppressed($1::text)"
+	var suppressed bool
+	if err := m.db.QueryRow(ctx, query, email).Scan(&suppressed); err != nil {
+		return false, err
+	}
+	return suppressed, nil
+}
+
+// Suppress adds the email address provided to the suppression list.
+func (m *Manager) Suppress(ctx context.Context, email, reason string) error {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "email not provided")
+	}
+	if reason != hub.EmailBounce && reason != hub.EmailComplaint {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid reason")
+	}
+	query := "select suppress_email($1::text, $2::text)"
+	_, err := m.db.Exec(ctx, query, email, reason)
+	return err
+}
@@ -0,0 +1,91 @@
+package suppression
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSuppressed(t *testing.T) {
+	dbQuery := "select is_email_suppressed($1::text)"
+	ctx := context.Background()
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "user1@email.com").Return(true, nil)
+		m := NewManager(db)
+
+		suppressed, err := m.IsSuppressed(ctx, "user1@email.com")
+		assert.NoError(t, err)
+		assert.True(t, suppressed)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "user1@email.com").Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		suppressed, err := m.IsSuppressed(ctx, "user1@email.com")
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		assert.False(t, suppressed)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestSuppress(t *testing.T) {
+	dbQuery := "select suppress_email($1::text, $2::text)"
+	ctx := context.Background()
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg string
+			email  string
+			reason string
+		}{
+			{
+				"email not provided",
+				" ",
+				hub.EmailBounce,
+			},
+			{
+				"invalid reason",
+				"user1@email.com",
+				"invalid",
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.errMsg, func(t *testing.T) {
+				m := NewManager(nil)
+				err := m.Suppress(ctx, tc.email, tc.reason)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, "user1@email.com", hub.EmailComplaint).Return(nil)
+		m := NewManager(db)
+
+		err := m.Suppress(ctx, " user1@email.com ", hub.EmailComplaint)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, "user1@email.com", hub.EmailBounce).Return(tests.ErrFakeDatabaseFailure)
+		m := NewManager(db)
+
+		err := m.Suppress(ctx, "user1@email.com", hub.EmailBounce)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		db.AssertExpectations(t)
+	})
+}
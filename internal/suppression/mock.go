@@ -0,0 +1,25 @@
+package suppression
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// ManagerMock is a mock implementation of the EmailSuppressionManager
+// interface.
+type ManagerMock struct {
+	mock.Mock
+}
+
+// IsSuppressed implements the EmailSuppressionManager interface.
+func (m *ManagerMock) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	args := m.Called(ctx, email)
+	return args.Bool(0), args.Error(1)
+}
+
+// Suppress implements the EmailSuppressionManager interface.
+func (m *ManagerMock) Suppress(ctx context.Context, email, reason string) error {
+	args := m.Called(ctx, email, reason)
+	return args.Error(0)
+}
@@ -0,0 +1,41 @@
+package suppression
+
+import (
+	"context"
+
+	"github.com/artifacthub/hub/internal/email"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/rs/zerolog"
+)
+
+// Sender is an email sender wrapper that skips the emails addressed to the
+// recipients in the suppression list.
+type Sender struct {
+	es     hub.EmailSender
+	m      hub.EmailSuppressionManager
+	logger zerolog.Logger
+}
+
+// NewSender creates a new Sender instance that sends the emails using the
+// email sender provided, unless their recipient has been suppressed.
+func NewSender(es hub.EmailSender, m hub.EmailSuppressionManager) *Sender {
+	return &Sender{
+		es:     es,
+		m:      m,
+		logger: util.LogWith("email").Logger(),
+	}
+}
+
+// SendEmail implements the EmailSender interface.
+func (s *Sender) SendEmail(d *email.Data) error {
+	suppressed, err := s.m.IsSuppressed(context.Background(), d.To)
+	if err != nil {
+		return err
+	}
+	if suppressed {
+		s.logger.Debug().Str("to", d.To).Msg("recipient suppressed, email not sent")
+		return nil
+	}
+	return s.es.SendEmail(d)
+}
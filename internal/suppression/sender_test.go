@@ -0,0 +1,55 @@
+package suppression
+
+import (
+	"context"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/email"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSenderSendEmail(t *testing.T) {
+	d := &email.Data{
+		To:      "user1@email.com",
+		Subject: "subject",
+		Body:    []byte("body"),
+	}
+
+	t.Run("error checking if recipient is suppressed", func(t *testing.T) {
+		es := &email.SenderMock{}
+		m := &ManagerMock{}
+		m.On("IsSuppressed", context.Background(), d.To).Return(false, tests.ErrFakeDatabaseFailure)
+		s := NewSender(es, m)
+
+		err := s.SendEmail(d)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		es.AssertExpectations(t)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("recipient suppressed, email not sent", func(t *testing.T) {
+		es := &email.SenderMock{}
+		m := &ManagerMock{}
+		m.On("IsSuppressed", context.Background(), d.To).Return(true, nil)
+		s := NewSender(es, m)
+
+		err := s.SendEmail(d)
+		assert.NoError(t, err)
+		es.AssertExpectations(t)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("recipient not suppressed, email sent", func(t *testing.T) {
+		es := &email.SenderMock{}
+		es.On("SendEmail", d).Return(nil)
+		m := &ManagerMock{}
+		m.On("IsSuppressed", context.Background(), d.To).Return(false, nil)
+		s := NewSender(es, m)
+
+		err := s.SendEmail(d)
+		assert.NoError(t, err)
+		es.AssertExpectations(t)
+		m.AssertExpectations(t)
+	})
+}
@@ -2,6 +2,7 @@ package tracker
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"sync"
 
@@ -22,6 +23,69 @@ const (
 	maxErrorsPerRepository = 100
 )
 
+// ErrorCode represents a code used to classify some common problems found
+// while tracking repositories, so that publishers can get actionable messages.
+type ErrorCode string
+
+const (
+	// ErrCodeMissingURL indicates that a package version entry does not
+	// provide any url to download it from.
+	ErrCodeMissingURL ErrorCode = "missing_url"
+
+	// ErrCodeInvalidURL indicates that a package version url is not valid.
+	ErrCodeInvalidURL ErrorCode = "invalid_url"
+
+	// ErrCodeUnsupportedURLScheme indicates that the scheme of a package
+	// version url is not supported.
+	ErrCodeUnsupportedURLScheme ErrorCode = "unsupported_url_scheme"
+
+	// ErrCodeArchiveNotFound indicates that the package version archive could
+	// not be found at the url provided.
+	ErrCodeArchiveNotFound ErrorCode = "archive_not_found"
+
+	// ErrCodeArchiveTooLarge indicates that the package version archive, or
+	// its uncompressed content, exceeds the maximum size allowed.
+	ErrCodeArchiveTooLarge ErrorCode = "archive_too_large"
+
+	// ErrCodeInvalidVersion indicates that a package version is not a valid
+	// semantic version.
+	ErrCodeInvalidVersion ErrorCode = "invalid_version"
+
+	// ErrCodeDigestMismatch indicates that the digest of the package version
+	// archive downloaded does not match the one announced in the repository.
+	ErrCodeDigestMismatch ErrorCode = "digest_mismatch"
+
+	// ErrCodeUnregisterThresholdExceeded indicates that too many of the
+	// packages versions registered have disappeared from the repository at
+	// once, so they have not been unregistered.
+	ErrCodeUnregisterThresholdExceeded ErrorCode = "unregister_threshold_exceeded"
+)
+
+// Error represents an error found while tracking a repository that has been
+// classified using one of the available error codes.
+type Error struct {
+	Code ErrorCode
+	Err  error
+}
+
+// NewError creates a new Error instance.
+func NewError(code ErrorCode, err error) *Error {
+	return &Error{
+		Code: code,
+		Err:  err,
+	}
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
 // DBErrorsCollector is in charge of collecting errors that happen while
 // repositories are being processed. Once all the processing is done, the
 // collected errors can be flushed, which will store them in the database.
@@ -61,14 +125,19 @@ func (c *DBErrorsCollector) Append(repositoryID string, err error) {
 }
 
 // Flush aggregates all errors collected per repository as a single text and
-// stores it in the database.
+// stores it in the database. Errors that have been classified will be
+// prefixed with their code.
 func (c *DBErrorsCollector) Flush() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	for repositoryID, errors := range c.errors {
+	for repositoryID, repositoryErrors := range c.errors {
 		var errStr strings.Builder
-		for _, err := range errors {
+		for _, err := range repositoryErrors {
+			var e *Error
+			if errors.As(err, &e) {
+				errStr.WriteString("[" + string(e.Code) + "] ")
+			}
 			errStr.WriteString(err.Error())
 			errStr.WriteString("\n")
 		}
@@ -0,0 +1,30 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/repo"
+)
+
+func TestDBErrorsCollector(t *testing.T) {
+	ctx := context.Background()
+	repo1 := &hub.Repository{RepositoryID: "repo1"}
+	repo2 := &hub.Repository{RepositoryID: "repo2"}
+
+	t.Run("errors are flushed including their code when available", func(t *testing.T) {
+		rm := &repo.ManagerMock{}
+		rm.On("SetLastTrackingResults", ctx, "repo1", "error1\n[invalid_version] error2: error3\n").Return(nil)
+		rm.On("SetLastTrackingResults", ctx, "repo2", "").Return(nil)
+		ec := NewDBErrorsCollector(ctx, rm, []*hub.Repository{repo1, repo2})
+
+		ec.Append("repo1", errors.New("error1"))
+		ec.Append("repo1", fmt.Errorf("error2: %w", NewError(ErrCodeInvalidVersion, errors.New("error3"))))
+		ec.Flush()
+
+		rm.AssertExpectations(t)
+	})
+}
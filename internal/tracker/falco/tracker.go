@@ -15,17 +15,18 @@ import (
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/repo"
 	"github.com/artifacthub/hub/internal/tracker"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/rs/zerolog"
-	"github.com/rs/zerolog/log"
 	"gopkg.in/yaml.v2"
 )
 
 // Tracker is in charge of tracking the packages available in a Falco rules
 // repository, registering and unregistering them as needed.
 type Tracker struct {
-	svc    *tracker.Services
-	r      *hub.Repository
-	logger zerolog.Logger
+	svc       *tracker.Services
+	r         *hub.Repository
+	sourceRef string
+	logger    zerolog.Logger
 }
 
 // NewTracker creates a new Tracker instance.
@@ -37,7 +38,7 @@ func NewTracker(
 	t := &Tracker{
 		svc:    svc,
 		r:      r,
-		logger: log.With().Str("repo", r.Name).Str("kind", hub.GetKindName(r.Kind)).Logger(),
+		logger: util.LogWith("tracker").Str("repo", r.Name).Str("kind", hub.GetKindName(r.Kind)).Logger(),
 	}
 	for _, o := range opts {
 		o(t)
@@ -59,6 +60,7 @@ func (t *Tracker) Track(wg *sync.WaitGroup) error {
 		return fmt.Errorf("error cloning repository: %w", err)
 	}
 	defer os.RemoveAll(tmpDir)
+	t.sourceRef = repo.GitSourceRef(tmpDir, t.r)
 
 	// Load packages already registered from this repository
 	packagesRegistered, err := t.svc.Rm.GetPackagesDigest(t.svc.Ctx, t.r.RepositoryID)
@@ -69,7 +71,7 @@ func (t *Tracker) Track(wg *sync.WaitGroup) error {
 	// Register available packages when needed
 	bypassDigestCheck := t.svc.Cfg.GetBool("tracker.bypassDigestCheck")
 	packagesAvailable := make(map[string]struct{})
-	basePath := filepath.Join(tmpDir, packagesPath)
+	basePath := filepath.Join(tmpDir, filepath.FromSlash(packagesPath))
 	err = filepath.Walk(basePath, func(pkgPath string, info os.FileInfo, err error) error {
 		if err != nil {
 			return fmt.Errorf("error reading packages: %w", err)
@@ -115,7 +117,7 @@ func (t *Tracker) Track(wg *sync.WaitGroup) error {
 
 		// Register package
 		t.logger.Debug().Str("name", md.Name).Str("v", md.Version).Msg("registering package")
-		err = t.registerPackage(md, strings.TrimPrefix(pkgPath, basePath))
+		err = t.registerPackage(md, filepath.ToSlash(strings.TrimPrefix(pkgPath, basePath)))
 		if err != nil {
 			t.warn(fmt.Errorf("error registering package %s version %s: %w", md.Name, md.Version, err))
 		}
@@ -149,11 +151,12 @@ func (t *Tracker) Track(wg *sync.WaitGroup) error {
 // registerPackage registers a package version using the package metadata
 // provided.
 func (t *Tracker) registerPackage(md *PackageMetadata, pkgPath string) error {
-	// Register logo image if needed
-	var logoURL, logoImageID string
-	if md.Icon != "" {
-		logoURL = md.Icon
-		data, err := downloadImage(logoURL)
+	// Register logo image if needed. When a logos queue is available, the logo
+	// will be fetched asynchronously once the package has been registered.
+	logoURL, logoImageID := md.Icon, ""
+	enqueueLogo := logoURL != "" && t.svc.Lq != nil
+	if logoURL != "" && !enqueueLogo {
+		data, err := t.downloadImage(logoURL)
 		if err != nil {
 			return fmt.Errorf("error downloading package %s version %s image: %w", md.Name, md.Version, err)
 		}
@@ -164,21 +167,19 @@ func (t *Tracker) registerPackage(md *PackageMetadata, pkgPath string) error {
 	}
 
 	// Prepare source link
-	var repoBaseURL, pkgsPath, provider string
+	var repoBaseURL, provider string
 	matches := repo.GitRepoURLRE.FindStringSubmatch(t.r.URL)
 	if len(matches) >= 3 {
 		repoBaseURL = matches[1]
 		provider = matches[2]
 	}
-	if len(matches) == 4 {
-		pkgsPath = strings.TrimSuffix(matches[3], "/")
-	}
+	pkgsPath := repo.GitPackagesPath(t.r)
 	var blobPath string
 	switch provider {
 	case "github":
-		blobPath = "blob/master"
+		blobPath = "blob/" + t.sourceRef
 	case "gitlab":
-		blobPath = "-/blob/master"
+		blobPath = "-/blob/" + t.sourceRef
 	}
 	sourceURL := fmt.Sprintf("%s/%s/%s%s", repoBaseURL, blobPath, pkgsPath, pkgPath)
 
@@ -203,7 +204,23 @@ func (t *Tracker) registerPackage(md *PackageMetadata, pkgPath string) error {
 		},
 		Repository: t.r,
 	}
-	return t.svc.Pm.Register(t.svc.Ctx, p)
+	if t.svc.Pv != nil {
+		if err := t.svc.Pv.Validate(p); err != nil {
+			return err
+		}
+	}
+	if err := t.svc.Pm.Register(t.svc.Ctx, p); err != nil {
+		return err
+	}
+
+	// Enqueue logo to be fetched if needed
+	if enqueueLogo {
+		t.svc.Lq.Enqueue(&tracker.LogoJob{
+			Package:  p,
+			GetImage: func() ([]byte, error) { return t.downloadImage(p.LogoURL) },
+		})
+	}
+	return nil
 }
 
 // unregisterPackage unregisters the package version provided.
@@ -243,8 +260,13 @@ type Rule struct {
 
 // downloadImage is a helper function used to download the image located in the
 // url provided.
-func downloadImage(u string) ([]byte, error) {
-	resp, err := http.Get(fmt.Sprint(u))
+func (t *Tracker) downloadImage(u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(t.svc.Ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	tracker.SetupRequest(req, t.svc.Cfg, t.r)
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,149 @@
+package helm
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/license"
+	"gopkg.in/yaml.v2"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+const (
+	// changesAnnotation represents the chart annotation used to declare the
+	// changes introduced in a chart version, as a list of strings.
+	changesAnnotation = "artifacthub.io/changes"
+
+	// imagesAnnotation represents the chart annotation used to declare the
+	// containers images used by a chart version. When provided, it takes
+	// precedence over the images extracted from the chart manifests.
+	imagesAnnotation = "artifacthub.io/images"
+
+	// licenseAnnotation represents the chart annotation used to declare the
+	// license of a chart version (SPDX license expression, i.e. Apache-2.0 OR
+	// MIT), overriding the one detected from the license file.
+	licenseAnnotation = "artifacthub.io/license"
+
+	// linksAnnotation represents the chart annotation used to declare some
+	// links (i.e. documentation, support) in addition to the chart sources.
+	linksAnnotation = "artifacthub.io/links"
+
+	// maintainersAnnotation represents the chart annotation used to declare
+	// the maintainers of a chart version, overriding the ones in Chart.yaml.
+	maintainersAnnotation = "artifacthub.io/maintainers"
+)
+
+// applyMetadataAnnotations enriches the package provided with the metadata
+// declared in the annotations of the chart given. Invalid annotations are
+// ignored, returning an error describing them.
+func applyMetadataAnnotations(p *hub.Package, md *chart.Metadata) error {
+	var errs []string
+
+	// Changes
+	if v, ok := md.Annotations[changesAnnotation]; ok {
+		var changes []string
+		if err := yaml.Unmarshal([]byte(v), &changes); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid changes: %v", err))
+		} else {
+			p.Changes = nil
+			for _, c := range changes {
+				if c = strings.TrimSpace(c); c != "" {
+					p.Changes = append(p.Changes, c)
+				}
+			}
+		}
+	}
+
+	// Images
+	if v, ok := md.Annotations[imagesAnnotation]; ok {
+		var images []*hub.ContainerImage
+		if err := yaml.Unmarshal([]byte(v), &images); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid images: %v", err))
+		} else if err := validateImages(images); err != nil {
+			errs = append(errs, err.Error())
+		} else {
+			p.ContainersImages = images
+		}
+	}
+
+	// License
+	if v := strings.TrimSpace(md.Annotations[licenseAnnotation]); v != "" {
+		expr, err := license.ParseExpression(v)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid license: %v", err))
+		} else {
+			p.License = expr
+		}
+	}
+
+	// Links
+	if v, ok := md.Annotations[linksAnnotation]; ok {
+		var links []*hub.Link
+		if err := yaml.Unmarshal([]byte(v), &links); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid links: %v", err))
+		} else if err := validateLinks(links); err != nil {
+			errs = append(errs, err.Error())
+		} else {
+			p.Links = append(p.Links, links...)
+		}
+	}
+
+	// Maintainers
+	if v, ok := md.Annotations[maintainersAnnotation]; ok {
+		var maintainers []*hub.Maintainer
+		if err := yaml.Unmarshal([]byte(v), &maintainers); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid maintainers: %v", err))
+		} else if err := validateMaintainers(maintainers); err != nil {
+			errs = append(errs, err.Error())
+		} else if len(maintainers) > 0 {
+			p.Maintainers = maintainers
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// validateImages checks that all the images provided have a reference.
+func validateImages(images []*hub.ContainerImage) error {
+	for _, i := range images {
+		if i == nil || strings.TrimSpace(i.Image) == "" {
+			return errors.New("invalid images: image not provided")
+		}
+	}
+	return nil
+}
+
+// validateLinks checks that all the links provided have a name and a valid
+// http(s) url.
+func validateLinks(links []*hub.Link) error {
+	for _, l := range links {
+		if l == nil || strings.TrimSpace(l.Name) == "" {
+			return errors.New("invalid links: name not provided")
+		}
+		u, err := url.Parse(l.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid links: invalid url: %s", l.URL)
+		}
+	}
+	return nil
+}
+
+// validateMaintainers checks that all the maintainers provided have an email,
+// using it as their name when none is provided.
+func validateMaintainers(maintainers []*hub.Maintainer) error {
+	for _, m := range maintainers {
+		if m == nil || strings.TrimSpace(m.Email) == "" {
+			return errors.New("invalid maintainers: email not provided")
+		}
+		if m.Name == "" {
+			m.Name = m.Email
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package helm
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestApplyMetadataAnnotations(t *testing.T) {
+	sourceLink := &hub.Link{Name: "source", URL: "https://github.com/org/repo"}
+	testCases := []struct {
+		annotations map[string]string
+		expectedPkg *hub.Package
+		expectedErr bool
+	}{
+		{
+			nil,
+			&hub.Package{License: "MIT", Links: []*hub.Link{sourceLink}},
+			false,
+		},
+		{
+			map[string]string{
+				changesAnnotation: `
+- Added feature 1
+- " "
+- Fixed bug 1
+`,
+				licenseAnnotation: " (Apache-2.0 or MIT)  AND BSD-3-Clause ",
+			},
+			&hub.Package{
+				License: "(Apache-2.0 OR MIT) AND BSD-3-Clause",
+				Links:   []*hub.Link{sourceLink},
+				Changes: []string{"Added feature 1", "Fixed bug 1"},
+			},
+			false,
+		},
+		{
+			map[string]string{
+				imagesAnnotation: `
+- name: app
+  image: quay.io/org/app:1.0.0
+`,
+				linksAnnotation: `
+- name: docs
+  url: https://docs.example.com
+`,
+				maintainersAnnotation: `
+- email: user1@example.com
+- name: User 2
+  email: user2@example.com
+`,
+			},
+			&hub.Package{
+				License: "MIT",
+				Links: []*hub.Link{
+					sourceLink,
+					{Name: "docs", URL: "https://docs.example.com"},
+				},
+				ContainersImages: []*hub.ContainerImage{
+					{Name: "app", Image: "quay.io/org/app:1.0.0"},
+				},
+				Maintainers: []*hub.Maintainer{
+					{Name: "user1@example.com", Email: "user1@example.com"},
+					{Name: "User 2", Email: "user2@example.com"},
+				},
+			},
+			false,
+		},
+		{
+			map[string]string{
+				changesAnnotation:     "invalid: [",
+				imagesAnnotation:      "- name: app",
+				linksAnnotation:       "- name: docs\n  url: ftp://docs.example.com",
+				maintainersAnnotation: "- name: User 1",
+				licenseAnnotation:     "Apache-2.0",
+			},
+			&hub.Package{License: "Apache-2.0", Links: []*hub.Link{sourceLink}},
+			true,
+		},
+		{
+			map[string]string{
+				licenseAnnotation: "Apache 2.0",
+			},
+			&hub.Package{License: "MIT", Links: []*hub.Link{sourceLink}},
+			true,
+		},
+	}
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			p := &hub.Package{License: "MIT", Links: []*hub.Link{sourceLink}}
+			md := &chart.Metadata{Annotations: tc.annotations}
+			err := applyMetadataAnnotations(p, md)
+			assert.Equal(t, tc.expectedPkg, p)
+			if tc.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
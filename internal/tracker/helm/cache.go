@@ -0,0 +1,178 @@
+package helm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+// chartExtractionVersion represents the version of the chart extraction
+// logic. It must be bumped every time the information extracted from the
+// charts archives changes, so that the extractions cached by previous
+// versions are not used anymore.
+const chartExtractionVersion = 4
+
+// chartDigestRE is a regexp used to validate the charts archives digests used
+// as keys in the charts cache (sha256, optionally prefixed with the algorithm).
+var chartDigestRE = regexp.MustCompile(`^(sha256:)?[a-f0-9]{64}$`)
+
+// chartsCache is a content addressable on-disk cache of charts archives,
+// keyed by their digest. It allows the workers to load the charts versions
+// whose archives have already been downloaded in a previous tracker run from
+// the local disk. The information extracted from the archives is cached as
+// well, so that it can be reused when the same archive is tracked again (i.e.
+// from a mirror).
+type chartsCache struct {
+	dir string
+}
+
+// chartExtraction represents the information extracted from a chart archive
+// that is expensive to compute. It only depends on the archive's content, so
+// it can be safely cached by the archive's digest.
+type chartExtraction struct {
+	Version          int                    `json:"version"`
+	Readme           string                 `json:"readme,omitempty"`
+	Changelog        string                 `json:"changelog,omitempty"`
+	License          string                 `json:"license,omitempty"`
+	ValuesPresets    []*ValuesPreset        `json:"values_presets,omitempty"`
+	DefaultValues    string                 `json:"default_values,omitempty"`
+	ValuesSchema     map[string]interface{} `json:"values_schema,omitempty"`
+	APIAvailability  *APIAvailabilityReport `json:"api_availability,omitempty"`
+	Resources        *ResourcesEstimation   `json:"resources,omitempty"`
+	ContainersImages []*hub.ContainerImage  `json:"containers_images,omitempty"`
+	Notes            string                 `json:"notes,omitempty"`
+	CRDs             []*CRD                 `json:"crds,omitempty"`
+	Analyzed         bool                   `json:"analyzed"`
+	Recommendations  []*Recommendation      `json:"recommendations,omitempty"`
+}
+
+// newChartsCache creates a new chartsCache instance that stores the archives
+// in the directory provided.
+func newChartsCache(dir string) *chartsCache {
+	return &chartsCache{dir: dir}
+}
+
+// get returns the chart archive with the digest provided from the cache, if
+// available.
+func (c *chartsCache) get(digest string) ([]byte, bool) {
+	p, ok := c.path(digest, ".tgz")
+	if !ok {
+		return nil, false
+	}
+	data, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, false
+	}
+	if computeChartDigest(data) != normalizeChartDigest(digest) {
+		// Corrupted cache entry, it'll be replaced on the next put
+		return nil, false
+	}
+	return data, true
+}
+
+// put stores the chart archive provided in the cache. The archive's content
+// must match the digest provided, so that the cache cannot be populated with
+// archives different than the ones announced in the repository index.
+func (c *chartsCache) put(digest string, data []byte) error {
+	p, ok := c.path(digest, ".tgz")
+	if !ok {
+		return fmt.Errorf("invalid chart digest: %s", digest)
+	}
+	if computeChartDigest(data) != normalizeChartDigest(digest) {
+		return fmt.Errorf("chart archive does not match digest: %s", digest)
+	}
+	return writeCacheEntry(p, data)
+}
+
+// getExtraction returns the information extracted from the chart archive
+// with the digest provided from the cache, if available. Extractions cached
+// by a different version of the extraction logic are ignored.
+func (c *chartsCache) getExtraction(digest string) (*chartExtraction, bool) {
+	p, ok := c.path(digest, ".json")
+	if !ok {
+		return nil, false
+	}
+	data, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, false
+	}
+	var e *chartExtraction
+	if err := json.Unmarshal(data, &e); err != nil || e == nil || e.Version != chartExtractionVersion {
+		return nil, false
+	}
+	return e, true
+}
+
+// putExtraction stores in the cache the information extracted from the chart
+// archive with the digest provided.
+func (c *chartsCache) putExtraction(digest string, e *chartExtraction) error {
+	p, ok := c.path(digest, ".json")
+	if !ok {
+		return fmt.Errorf("invalid chart digest: %s", digest)
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return writeCacheEntry(p, data)
+}
+
+// path returns the path of the cache entry with the extension provided for
+// the digest given. Entries are spread in subdirectories named after the
+// first two characters of the digest.
+func (c *chartsCache) path(digest, ext string) (string, bool) {
+	if c == nil || c.dir == "" || !chartDigestRE.MatchString(digest) {
+		return "", false
+	}
+	digest = normalizeChartDigest(digest)
+	return filepath.Join(c.dir, digest[:2], digest+ext), true
+}
+
+// writeCacheEntry writes the cache entry data provided to the path given.
+func writeCacheEntry(p string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+
+	// Write the archive to a temporary file first and rename it afterwards,
+	// so that concurrent readers never see partially written entries
+	tmpFile, err := ioutil.TempFile(filepath.Dir(p), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+
+	// Temporary files are created with 0600 permissions, which would prevent
+	// other users (i.e. a different uid sharing the cache volume) from reading
+	// the entry
+	if err := os.Chmod(tmpFile.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), p)
+}
+
+// computeChartDigest returns the sha256 digest of the chart archive provided.
+func computeChartDigest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeChartDigest removes the algorithm prefix from the digest provided.
+func normalizeChartDigest(digest string) string {
+	return strings.TrimPrefix(digest, "sha256:")
+}
@@ -0,0 +1,143 @@
+package helm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChartsCache(t *testing.T) {
+	data := []byte("chart archive data")
+	digest := computeChartDigest(data)
+
+	t.Run("cache not configured", func(t *testing.T) {
+		var c *chartsCache
+		_, ok := c.get(digest)
+		assert.False(t, ok)
+		assert.Error(t, c.put(digest, data))
+	})
+
+	t.Run("invalid digest", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "artifact-hub-test")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		c := newChartsCache(dir)
+
+		for _, d := range []string{"", "digest", "../" + digest[3:], "md5:" + digest} {
+			assert.Error(t, c.put(d, data))
+			_, ok := c.get(d)
+			assert.False(t, ok)
+		}
+	})
+
+	t.Run("archive does not match digest", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "artifact-hub-test")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		c := newChartsCache(dir)
+
+		assert.Error(t, c.put(digest, []byte("other data")))
+		_, ok := c.get(digest)
+		assert.False(t, ok)
+	})
+
+	t.Run("archive cached successfully", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "artifact-hub-test")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		c := newChartsCache(dir)
+
+		require.NoError(t, c.put("sha256:"+digest, data))
+		cachedData, ok := c.get(digest)
+		assert.True(t, ok)
+		assert.Equal(t, data, cachedData)
+	})
+
+	t.Run("corrupted cache entry", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "artifact-hub-test")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		c := newChartsCache(dir)
+
+		require.NoError(t, c.put(digest, data))
+		p := filepath.Join(dir, digest[:2], digest+".tgz")
+		require.NoError(t, ioutil.WriteFile(p, []byte("corrupted"), 0644))
+		_, ok := c.get(digest)
+		assert.False(t, ok)
+	})
+}
+
+func TestChartsCacheExtractions(t *testing.T) {
+	digest := computeChartDigest([]byte("chart archive data"))
+	e := &chartExtraction{
+		Version:       chartExtractionVersion,
+		Readme:        "readme",
+		DefaultValues: "key: value\n",
+		ValuesSchema:  map[string]interface{}{"type": "object"},
+		Resources: &ResourcesEstimation{
+			Workloads: 1,
+			Requests:  &ResourcesAmounts{CPUMillicores: 100, MemoryBytes: 1024},
+			Limits:    &ResourcesAmounts{},
+		},
+	}
+
+	t.Run("cache not configured", func(t *testing.T) {
+		var c *chartsCache
+		_, ok := c.getExtraction(digest)
+		assert.False(t, ok)
+		assert.Error(t, c.putExtraction(digest, e))
+	})
+
+	t.Run("invalid digest", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "artifact-hub-test")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		c := newChartsCache(dir)
+
+		for _, d := range []string{"", "digest", "../" + digest[3:], "md5:" + digest} {
+			assert.Error(t, c.putExtraction(d, e))
+			_, ok := c.getExtraction(d)
+			assert.False(t, ok)
+		}
+	})
+
+	t.Run("extraction cached successfully", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "artifact-hub-test")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		c := newChartsCache(dir)
+
+		require.NoError(t, c.putExtraction("sha256:"+digest, e))
+		cachedExtraction, ok := c.getExtraction(digest)
+		assert.True(t, ok)
+		assert.Equal(t, e, cachedExtraction)
+	})
+
+	t.Run("extraction cached by a different version", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "artifact-hub-test")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		c := newChartsCache(dir)
+
+		require.NoError(t, c.putExtraction(digest, &chartExtraction{Version: chartExtractionVersion - 1}))
+		_, ok := c.getExtraction(digest)
+		assert.False(t, ok)
+	})
+
+	t.Run("corrupted cache entry", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "artifact-hub-test")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		c := newChartsCache(dir)
+
+		require.NoError(t, c.putExtraction(digest, e))
+		p := filepath.Join(dir, digest[:2], digest+".json")
+		require.NoError(t, ioutil.WriteFile(p, []byte("corrupted"), 0644))
+		_, ok := c.getExtraction(digest)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,110 @@
+package helm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// crdsDir represents the directory where charts ship the custom resource
+// definitions that Helm installs before rendering the templates.
+const crdsDir = "crds/"
+
+// CRD represents some details about a custom resource definition shipped in a
+// chart.
+type CRD struct {
+	Group    string   `json:"group"`
+	Kind     string   `json:"kind"`
+	Versions []string `json:"versions"`
+}
+
+// crdManifest represents the fields of a custom resource definition manifest
+// used to extract its details. Both the apiextensions.k8s.io/v1 (versions)
+// and v1beta1 (version) formats are supported.
+type crdManifest struct {
+	Kind string `yaml:"kind"`
+	Spec struct {
+		Group string `yaml:"group"`
+		Names struct {
+			Kind string `yaml:"kind"`
+		} `yaml:"names"`
+		Version  string `yaml:"version"`
+		Versions []struct {
+			Name string `yaml:"name"`
+		} `yaml:"versions"`
+	} `yaml:"spec"`
+}
+
+// getCRDs returns the custom resource definitions shipped in the crds
+// directory of the chart provided and its dependencies, sorted by group and
+// kind. Definitions declared more than once are only returned once.
+func getCRDs(chrt *chart.Chart) ([]*CRD, error) {
+	crds := make(map[string]*CRD)
+	if err := collectCRDs(chrt, crds); err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(crds))
+	for k := range crds {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	result := make([]*CRD, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, crds[k])
+	}
+	return result, nil
+}
+
+// collectCRDs adds the custom resource definitions found in the crds directory
+// of the chart provided and its dependencies to the map given, indexed by
+// group and kind.
+func collectCRDs(chrt *chart.Chart, crds map[string]*CRD) error {
+	for _, file := range chrt.Files {
+		if !strings.HasPrefix(file.Name, crdsDir) {
+			continue
+		}
+		switch path.Ext(file.Name) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+		dec := yaml.NewDecoder(strings.NewReader(string(file.Data)))
+		for {
+			var m *crdManifest
+			if err := dec.Decode(&m); err != nil {
+				if !errors.Is(err, io.EOF) {
+					return fmt.Errorf("invalid yaml in %s: %w", path.Join(chrt.Name(), file.Name), err)
+				}
+				break
+			}
+			if m == nil || m.Kind != "CustomResourceDefinition" || m.Spec.Names.Kind == "" {
+				continue
+			}
+			crd := &CRD{
+				Group: m.Spec.Group,
+				Kind:  m.Spec.Names.Kind,
+			}
+			for _, v := range m.Spec.Versions {
+				if v.Name != "" {
+					crd.Versions = append(crd.Versions, v.Name)
+				}
+			}
+			if len(crd.Versions) == 0 && m.Spec.Version != "" {
+				crd.Versions = []string{m.Spec.Version}
+			}
+			crds[crd.Group+"/"+crd.Kind] = crd
+		}
+	}
+	for _, dep := range chrt.Dependencies() {
+		if err := collectCRDs(dep, crds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
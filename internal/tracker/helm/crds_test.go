@@ -0,0 +1,100 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestGetCRDs(t *testing.T) {
+	t.Run("chart without crds", func(t *testing.T) {
+		crds, err := getCRDs(newTestCRDsChart("test", map[string]string{
+			"README.md": "# Test",
+		}))
+		require.NoError(t, err)
+		assert.Empty(t, crds)
+	})
+
+	t.Run("crds extracted from the chart and its dependencies", func(t *testing.T) {
+		chrt := newTestCRDsChart("test", map[string]string{
+			"crds/certificates.yaml": `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: certificates.cert-manager.io
+spec:
+  group: cert-manager.io
+  names:
+    kind: Certificate
+  versions:
+    - name: v1alpha2
+    - name: v1
+---
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: issuers.cert-manager.io
+spec:
+  group: cert-manager.io
+  names:
+    kind: Issuer
+  version: v1alpha2
+`,
+			"crds/README.md": "apiVersion: v1\nkind: CustomResourceDefinition\n",
+		})
+		chrt.AddDependency(newTestCRDsChart("dep", map[string]string{
+			"crds/issuers.yaml": `
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+spec:
+  group: cert-manager.io
+  names:
+    kind: Issuer
+  version: v1alpha2
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+spec:
+  group: acme.cert-manager.io
+  names:
+    kind: Order
+  versions:
+    - name: v1
+`,
+		}))
+		crds, err := getCRDs(chrt)
+		require.NoError(t, err)
+		assert.Equal(t, []*CRD{
+			{Group: "acme.cert-manager.io", Kind: "Order", Versions: []string{"v1"}},
+			{Group: "cert-manager.io", Kind: "Certificate", Versions: []string{"v1alpha2", "v1"}},
+			{Group: "cert-manager.io", Kind: "Issuer", Versions: []string{"v1alpha2"}},
+		}, crds)
+	})
+
+	t.Run("invalid crds manifest", func(t *testing.T) {
+		crds, err := getCRDs(newTestCRDsChart("test", map[string]string{
+			"crds/invalid.yaml": "kind: [",
+		}))
+		assert.Error(t, err)
+		assert.Nil(t, crds)
+	})
+}
+
+func newTestCRDsChart(name string, files map[string]string) *chart.Chart {
+	chrt := &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion: chart.APIVersionV2,
+			Name:       name,
+			Version:    "1.0.0",
+		},
+	}
+	for name, data := range files {
+		chrt.Files = append(chrt.Files, &chart.File{
+			Name: name,
+			Data: []byte(data),
+		})
+	}
+	return chrt
+}
@@ -0,0 +1,37 @@
+package helm
+
+// jobKey identifies the package version artifact a register job refers to.
+type jobKey struct {
+	repositoryID string
+	name         string
+	version      string
+	digest       string
+}
+
+// jobsDeduplicator keeps track of the register jobs dispatched in a tracker
+// run, so that the workers never process the same package version artifact
+// twice. This may happen when an index file lists the same chart version
+// several times or when the same version is listed using different notations
+// (i.e. 1.0.0 and v1.0.0). It is not safe for concurrent use, as jobs are
+// only generated by the tracker's goroutine.
+type jobsDeduplicator struct {
+	seen map[jobKey]struct{}
+}
+
+// newJobsDeduplicator creates a new jobsDeduplicator instance.
+func newJobsDeduplicator() *jobsDeduplicator {
+	return &jobsDeduplicator{
+		seen: make(map[jobKey]struct{}),
+	}
+}
+
+// isDuplicate checks if a job for the artifact identified by the key provided
+// has already been dispatched. When it hasn't, the key is recorded so that
+// subsequent checks for the same artifact report it as a duplicate.
+func (d *jobsDeduplicator) isDuplicate(k jobKey) bool {
+	if _, ok := d.seen[k]; ok {
+		return true
+	}
+	d.seen[k] = struct{}{}
+	return false
+}
@@ -0,0 +1,21 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobsDeduplicator(t *testing.T) {
+	d := newJobsDeduplicator()
+	k := jobKey{repositoryID: "repo1", name: "pkg1", version: "1.0.0", digest: "digest1"}
+
+	assert.False(t, d.isDuplicate(k))
+	assert.True(t, d.isDuplicate(k))
+
+	// Keys that differ in any of their fields are not duplicates
+	assert.False(t, d.isDuplicate(jobKey{repositoryID: "repo2", name: "pkg1", version: "1.0.0", digest: "digest1"}))
+	assert.False(t, d.isDuplicate(jobKey{repositoryID: "repo1", name: "pkg2", version: "1.0.0", digest: "digest1"}))
+	assert.False(t, d.isDuplicate(jobKey{repositoryID: "repo1", name: "pkg1", version: "2.0.0", digest: "digest1"}))
+	assert.False(t, d.isDuplicate(jobKey{repositoryID: "repo1", name: "pkg1", version: "1.0.0", digest: "digest2"}))
+}
@@ -0,0 +1,45 @@
+package helm
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// extractContainersImages renders the provided chart using its default values
+// and collects the images referenced by the containers (and init containers)
+// of the workloads obtained. Images are returned sorted and without
+// duplicates.
+func extractContainersImages(chrt *chart.Chart) ([]*hub.ContainerImage, error) {
+	workloads, err := renderWorkloads(chrt)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]struct{})
+	for _, wl := range workloads {
+		s := wl.pod()
+		containers := append(append([]*container{}, s.InitContainers...), s.Containers...)
+		for _, c := range containers {
+			if c == nil {
+				continue
+			}
+			image := strings.TrimSpace(c.Image)
+			if image == "" {
+				continue
+			}
+			seen[image] = struct{}{}
+		}
+	}
+	images := make([]string, 0, len(seen))
+	for image := range seen {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+	containersImages := make([]*hub.ContainerImage, 0, len(images))
+	for _, image := range images {
+		containersImages = append(containersImages, &hub.ContainerImage{Image: image})
+	}
+	return containersImages, nil
+}
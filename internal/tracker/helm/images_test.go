@@ -0,0 +1,82 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractContainersImages(t *testing.T) {
+	t.Run("error rendering chart", func(t *testing.T) {
+		t.Parallel()
+		images, err := extractContainersImages(newTestChart(map[string]string{
+			"templates/invalid.yaml": "{{ .Values.missing.key }}",
+		}))
+		assert.Error(t, err)
+		assert.Nil(t, images)
+	})
+
+	t.Run("no workloads found", func(t *testing.T) {
+		t.Parallel()
+		images, err := extractContainersImages(newTestChart(map[string]string{
+			"templates/configmap.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+`,
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, []*hub.ContainerImage{}, images)
+	})
+
+	t.Run("images extracted successfully", func(t *testing.T) {
+		t.Parallel()
+		chrt := newTestChart(map[string]string{
+			"templates/deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test
+spec:
+  template:
+    spec:
+      initContainers:
+        - name: init
+          image: busybox:1.32
+      containers:
+        - name: app
+          image: "{{ .Values.image.repository }}:{{ .Values.image.tag }}"
+        - name: sidecar
+          image: envoyproxy/envoy:v1.16.0
+`,
+			"templates/cronjob.yaml": `apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: test
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: job
+              image: busybox:1.32
+            - name: no-image
+`,
+		})
+		chrt.Values = map[string]interface{}{
+			"image": map[string]interface{}{
+				"repository": "artifacthub/app",
+				"tag":        "1.0.0",
+			},
+		}
+		images, err := extractContainersImages(chrt)
+		require.NoError(t, err)
+		assert.Equal(t, []*hub.ContainerImage{
+			{Image: "artifacthub/app:1.0.0"},
+			{Image: "busybox:1.32"},
+			{Image: "envoyproxy/envoy:v1.16.0"},
+		}, images)
+	})
+}
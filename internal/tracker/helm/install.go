@@ -0,0 +1,41 @@
+package helm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/oci"
+	"github.com/artifacthub/hub/internal/repo"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// getInstallInstructions returns the canonical install instructions (in
+// markdown) of the chart version provided, available in the repository
+// given. Charts in OCI registries are installed directly from their
+// reference, whereas the rest require the repository to be added first. No
+// instructions are returned for charts in local repositories, as they cannot
+// be installed from the hub, or for library charts, as they cannot be
+// installed at all.
+func getInstallInstructions(r *hub.Repository, md *chart.Metadata) string {
+	if repo.IsLocal(r.URL) || md.Type == "library" {
+		return ""
+	}
+	var credentials string
+	if r.AuthUser != "" {
+		credentials = " --username <username> --password <password>"
+	}
+
+	var b strings.Builder
+	chartRef := r.Name + "/" + md.Name
+	if oci.IsOCI(r.URL) {
+		chartRef = r.URL
+		if ref, err := oci.ParseReference(r.URL); err == nil && credentials != "" {
+			fmt.Fprintf(&b, "Log in to the registry:\n\n```\nhelm registry login %s%s\n```\n\n", ref.Registry, credentials)
+		}
+	} else {
+		fmt.Fprintf(&b, "Add the repository:\n\n```\nhelm repo add %s %s%s\n```\n\n", r.Name, r.URL, credentials)
+	}
+	fmt.Fprintf(&b, "Install the chart:\n\n```\nhelm install my-%s %s --version %s\n```\n", md.Name, chartRef, md.Version)
+	return b.String()
+}
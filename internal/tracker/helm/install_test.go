@@ -0,0 +1,69 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestGetInstallInstructions(t *testing.T) {
+	md := &chart.Metadata{
+		Name:    "pkg1",
+		Version: "1.0.0",
+	}
+
+	testCases := []struct {
+		description          string
+		r                    *hub.Repository
+		md                   *chart.Metadata
+		expectedInstructions string
+	}{
+		{
+			"http repository",
+			&hub.Repository{Name: "repo1", URL: "https://repo1.url"},
+			md,
+			"Add the repository:\n\n```\nhelm repo add repo1 https://repo1.url\n```\n\n" +
+				"Install the chart:\n\n```\nhelm install my-pkg1 repo1/pkg1 --version 1.0.0\n```\n",
+		},
+		{
+			"private http repository",
+			&hub.Repository{Name: "repo1", URL: "https://repo1.url", AuthUser: "user", AuthPass: "pass"},
+			md,
+			"Add the repository:\n\n```\nhelm repo add repo1 https://repo1.url --username <username> --password <password>\n```\n\n" +
+				"Install the chart:\n\n```\nhelm install my-pkg1 repo1/pkg1 --version 1.0.0\n```\n",
+		},
+		{
+			"oci repository",
+			&hub.Repository{Name: "repo1", URL: "oci://registry.io/org/pkg1"},
+			md,
+			"Install the chart:\n\n```\nhelm install my-pkg1 oci://registry.io/org/pkg1 --version 1.0.0\n```\n",
+		},
+		{
+			"private oci repository",
+			&hub.Repository{Name: "repo1", URL: "oci://registry.io/org/pkg1", AuthUser: "user", AuthPass: "pass"},
+			md,
+			"Log in to the registry:\n\n```\nhelm registry login registry.io --username <username> --password <password>\n```\n\n" +
+				"Install the chart:\n\n```\nhelm install my-pkg1 oci://registry.io/org/pkg1 --version 1.0.0\n```\n",
+		},
+		{
+			"local repository",
+			&hub.Repository{Name: "repo1", URL: "file:///charts"},
+			md,
+			"",
+		},
+		{
+			"library chart",
+			&hub.Repository{Name: "repo1", URL: "https://repo1.url"},
+			&chart.Metadata{Name: "pkg1", Version: "1.0.0", Type: "library"},
+			"",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expectedInstructions, getInstallInstructions(tc.r, tc.md))
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package helm
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+var (
+	// kubeVersionHyphenRangeRE is a regexp used to find the hyphen ranges
+	// (i.e. 1.16 - 1.20) in a kubeVersion constraint.
+	kubeVersionHyphenRangeRE = regexp.MustCompile(`(v?[0-9xX*.]+)(?:-[0-9A-Za-z.-]+)?\s+-\s+(v?[0-9xX*.]+)(?:-[0-9A-Za-z.-]+)?`)
+
+	// kubeVersionComparisonRE is a regexp used to find the comparisons a
+	// kubeVersion constraint is made of.
+	kubeVersionComparisonRE = regexp.MustCompile(`(>=|<=|=>|=<|!=|==|=|>|<|~>|~|\^)?\s*v?(\d+|[xX*])(?:\.(\d+|[xX*]))?(?:\.(\d+|[xX*]))?(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?`)
+)
+
+// errUnsatisfiableKubeVersion indicates that a kubeVersion constraint cannot
+// be satisfied by any Kubernetes version.
+var errUnsatisfiableKubeVersion = errors.New("kubeVersion constraint cannot be satisfied")
+
+// kubeVersionRange represents a range of Kubernetes versions, from lower
+// (inclusive) to upper (exclusive). A nil bound means the range is open on
+// that side.
+type kubeVersionRange struct {
+	lower *semver.Version
+	upper *semver.Version
+}
+
+// normalizeKubeVersion validates the kubeVersion constraint provided and
+// returns its normalized form. Normalized constraints are made of groups of
+// comma separated >= and < comparisons (i.e. >=1.16.0, <1.20.0) joined by ||,
+// so that they can be evaluated easily by the database when filtering
+// packages by the Kubernetes version they are compatible with. Prereleases
+// are ignored, and != comparisons are dropped as they only exclude specific
+// versions.
+func normalizeKubeVersion(constraint string) (string, error) {
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" {
+		return "", nil
+	}
+	if _, err := semver.NewConstraint(constraint); err != nil {
+		return "", fmt.Errorf("invalid kubeVersion constraint: %w", err)
+	}
+
+	var groups []string
+	for _, group := range strings.Split(constraint, "||") {
+		r, err := parseKubeVersionGroup(group)
+		if err != nil {
+			return "", err
+		}
+		if r.lower != nil && r.upper != nil && !r.lower.LessThan(r.upper) {
+			continue
+		}
+		var comparisons []string
+		if r.lower != nil {
+			comparisons = append(comparisons, ">="+r.lower.String())
+		}
+		if r.upper != nil {
+			comparisons = append(comparisons, "<"+r.upper.String())
+		}
+		if len(comparisons) == 0 {
+			// The group is satisfied by any version
+			return ">=0.0.0", nil
+		}
+		groups = append(groups, strings.Join(comparisons, ", "))
+	}
+	if len(groups) == 0 {
+		return "", errUnsatisfiableKubeVersion
+	}
+	return strings.Join(groups, " || "), nil
+}
+
+// parseKubeVersionGroup returns the range of versions that satisfy all the
+// comparisons in the kubeVersion constraint group provided.
+func parseKubeVersionGroup(group string) (*kubeVersionRange, error) {
+	group = kubeVersionHyphenRangeRE.ReplaceAllString(group, ">=$1, <=$2")
+	matches := kubeVersionComparisonRE.FindAllStringSubmatch(group, -1)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("invalid kubeVersion constraint: %s", group)
+	}
+	r := &kubeVersionRange{}
+	for _, m := range matches {
+		lower, upper := comparisonRange(m[1], m[2:5])
+		if lower != nil && (r.lower == nil || lower.GreaterThan(r.lower)) {
+			r.lower = lower
+		}
+		if upper != nil && (r.upper == nil || upper.LessThan(r.upper)) {
+			r.upper = upper
+		}
+	}
+	return r, nil
+}
+
+// comparisonRange returns the lower (inclusive) and upper (exclusive) bounds
+// of the versions that satisfy the comparison provided. The version parts
+// that are not provided or are wildcards are considered x-ranges.
+func comparisonRange(operator string, parts []string) (lower, upper *semver.Version) {
+	// Parse the version parts, stopping at the first wildcard
+	var nums []uint64
+	for _, p := range parts {
+		n, err := strconv.ParseUint(p, 10, 64)
+		if err != nil {
+			break
+		}
+		nums = append(nums, n)
+	}
+	if len(nums) == 0 {
+		return nil, nil
+	}
+	v := newKubeVersion(nums, 0)
+	next := newKubeVersion(nums, 1)
+
+	switch operator {
+	case ">=", "=>":
+		return v, nil
+	case ">":
+		return next, nil
+	case "<":
+		return nil, v
+	case "<=", "=<":
+		return nil, next
+	case "~", "~>":
+		if len(nums) == 1 {
+			return v, next
+		}
+		return v, newKubeVersion(nums[:2], 1)
+	case "^":
+		switch {
+		case nums[0] > 0 || len(nums) == 1:
+			return v, newKubeVersion(nums[:1], 1)
+		case len(nums) == 2 || nums[1] > 0:
+			return v, newKubeVersion(nums[:2], 1)
+		default:
+			return v, next
+		}
+	case "!=":
+		return nil, nil
+	default:
+		return v, next
+	}
+}
+
+// newKubeVersion creates a new version from the parts provided, adding the
+// increment to the last of them. Missing parts are set to zero.
+func newKubeVersion(nums []uint64, increment uint64) *semver.Version {
+	var parts [3]uint64
+	copy(parts[:], nums)
+	parts[len(nums)-1] += increment
+	return semver.New(parts[0], parts[1], parts[2], "", "")
+}
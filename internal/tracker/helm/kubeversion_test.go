@@ -0,0 +1,58 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeKubeVersion(t *testing.T) {
+	t.Run("valid constraints", func(t *testing.T) {
+		testCases := []struct {
+			constraint         string
+			expectedConstraint string
+		}{
+			{"", ""},
+			{">=1.16.0", ">=1.16.0"},
+			{">= 1.16.0-0", ">=1.16.0"},
+			{">=v1.16.0-0 <1.20.0-0", ">=1.16.0, <1.20.0"},
+			{">=1.16.0, <1.20.0", ">=1.16.0, <1.20.0"},
+			{">1.16.3 <=1.19.2", ">=1.16.4, <1.19.3"},
+			{">1.16 <=1.19", ">=1.17.0, <1.20.0"},
+			{"1.16.x", ">=1.16.0, <1.17.0"},
+			{"1.16.2", ">=1.16.2, <1.16.3"},
+			{"~1.16.2", ">=1.16.2, <1.17.0"},
+			{"~1.16", ">=1.16.0, <1.17.0"},
+			{"^1.16.2", ">=1.16.2, <2.0.0"},
+			{"1.16 - 1.18", ">=1.16.0, <1.19.0"},
+			{">=1.14.0 <1.15.0 || >=1.16.0", ">=1.14.0, <1.15.0 || >=1.16.0"},
+			{">=1.20.0 <1.16.0 || >=1.18.0", ">=1.18.0"},
+			{">=1.16.0 !=1.17.0", ">=1.16.0"},
+			{"*", ">=0.0.0"},
+			{">=1.16.0 || *", ">=0.0.0"},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.constraint, func(t *testing.T) {
+				constraint, err := normalizeKubeVersion(tc.constraint)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedConstraint, constraint)
+			})
+		}
+	})
+
+	t.Run("invalid constraints", func(t *testing.T) {
+		testCases := []string{
+			"invalid",
+			">= foo",
+			">=1.20.0 <1.16.0",
+		}
+		for _, constraint := range testCases {
+			constraint := constraint
+			t.Run(constraint, func(t *testing.T) {
+				_, err := normalizeKubeVersion(constraint)
+				assert.Error(t, err)
+			})
+		}
+	})
+}
@@ -0,0 +1,278 @@
+package helm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+)
+
+// targetKubernetesVersions represents the Kubernetes versions (minor
+// releases) the charts manifests api availability is checked against.
+var targetKubernetesVersions = []string{"1.16", "1.17", "1.18", "1.19"}
+
+// apiAvailability represents the range of Kubernetes minor releases in which
+// a given resource api version and kind is served. A removed value of 0 means
+// it hasn't been removed yet.
+type apiAvailability struct {
+	introduced int
+	removed    int
+}
+
+// knownAPIs represents the resources api versions and kinds whose
+// availability is known, indexed by apiVersion/kind. Resources not listed here
+// (like custom resources) are not checked and are reported as skipped.
+var knownAPIs = map[string]apiAvailability{
+	"v1/ConfigMap":             {0, 0},
+	"v1/Endpoints":             {0, 0},
+	"v1/LimitRange":            {0, 0},
+	"v1/Namespace":             {0, 0},
+	"v1/PersistentVolume":      {0, 0},
+	"v1/PersistentVolumeClaim": {0, 0},
+	"v1/Pod":                   {0, 0},
+	"v1/ReplicationController": {0, 0},
+	"v1/ResourceQuota":         {0, 0},
+	"v1/Secret":                {0, 0},
+	"v1/Service":               {0, 0},
+	"v1/ServiceAccount":        {0, 0},
+	"admissionregistration.k8s.io/v1/MutatingWebhookConfiguration":        {16, 0},
+	"admissionregistration.k8s.io/v1/ValidatingWebhookConfiguration":      {16, 0},
+	"admissionregistration.k8s.io/v1beta1/MutatingWebhookConfiguration":   {9, 22},
+	"admissionregistration.k8s.io/v1beta1/ValidatingWebhookConfiguration": {9, 22},
+	"apiextensions.k8s.io/v1/CustomResourceDefinition":                    {16, 0},
+	"apiextensions.k8s.io/v1beta1/CustomResourceDefinition":               {7, 22},
+	"apps/v1/ControllerRevision":                                          {9, 0},
+	"apps/v1/DaemonSet":                                                   {9, 0},
+	"apps/v1/Deployment":                                                  {9, 0},
+	"apps/v1/ReplicaSet":                                                  {9, 0},
+	"apps/v1/StatefulSet":                                                 {9, 0},
+	"apps/v1beta1/ControllerRevision":                                     {7, 16},
+	"apps/v1beta1/Deployment":                                             {6, 16},
+	"apps/v1beta1/StatefulSet":                                            {5, 16},
+	"apps/v1beta2/ControllerRevision":                                     {8, 16},
+	"apps/v1beta2/DaemonSet":                                              {8, 16},
+	"apps/v1beta2/Deployment":                                             {8, 16},
+	"apps/v1beta2/ReplicaSet":                                             {8, 16},
+	"apps/v1beta2/StatefulSet":                                            {8, 16},
+	"autoscaling/v1/HorizontalPodAutoscaler":                              {2, 0},
+	"autoscaling/v2beta1/HorizontalPodAutoscaler":                         {8, 0},
+	"autoscaling/v2beta2/HorizontalPodAutoscaler":                         {12, 0},
+	"batch/v1/Job":                                                        {2, 0},
+	"batch/v1beta1/CronJob":                                               {8, 0},
+	"certificates.k8s.io/v1/CertificateSigningRequest":                    {19, 0},
+	"certificates.k8s.io/v1beta1/CertificateSigningRequest":               {4, 22},
+	"coordination.k8s.io/v1/Lease":                                        {14, 0},
+	"coordination.k8s.io/v1beta1/Lease":                                   {12, 22},
+	"extensions/v1beta1/DaemonSet":                                        {2, 16},
+	"extensions/v1beta1/Deployment":                                       {2, 16},
+	"extensions/v1beta1/Ingress":                                          {2, 22},
+	"extensions/v1beta1/NetworkPolicy":                                    {3, 16},
+	"extensions/v1beta1/PodSecurityPolicy":                                {3, 16},
+	"extensions/v1beta1/ReplicaSet":                                       {2, 16},
+	"networking.k8s.io/v1/Ingress":                                        {19, 0},
+	"networking.k8s.io/v1/IngressClass":                                   {19, 0},
+	"networking.k8s.io/v1/NetworkPolicy":                                  {7, 0},
+	"networking.k8s.io/v1beta1/Ingress":                                   {14, 22},
+	"networking.k8s.io/v1beta1/IngressClass":                              {18, 22},
+	"policy/v1beta1/PodDisruptionBudget":                                  {5, 0},
+	"policy/v1beta1/PodSecurityPolicy":                                    {10, 0},
+	"rbac.authorization.k8s.io/v1/ClusterRole":                            {8, 0},
+	"rbac.authorization.k8s.io/v1/ClusterRoleBinding":                     {8, 0},
+	"rbac.authorization.k8s.io/v1/Role":                                   {8, 0},
+	"rbac.authorization.k8s.io/v1/RoleBinding":                            {8, 0},
+	"rbac.authorization.k8s.io/v1beta1/ClusterRole":                       {6, 22},
+	"rbac.authorization.k8s.io/v1beta1/ClusterRoleBinding":                {6, 22},
+	"rbac.authorization.k8s.io/v1beta1/Role":                              {6, 22},
+	"rbac.authorization.k8s.io/v1beta1/RoleBinding":                       {6, 22},
+	"scheduling.k8s.io/v1/PriorityClass":                                  {14, 0},
+	"scheduling.k8s.io/v1beta1/PriorityClass":                             {11, 22},
+	"storage.k8s.io/v1/CSIDriver":                                         {18, 0},
+	"storage.k8s.io/v1/StorageClass":                                      {6, 0},
+	"storage.k8s.io/v1beta1/CSIDriver":                                    {14, 22},
+	"storage.k8s.io/v1beta1/StorageClass":                                 {4, 22},
+}
+
+// APIAvailabilityReport represents the result of checking if the api versions
+// and kinds of the resources rendered from a chart version are served by the
+// target Kubernetes versions. Manifests are not validated against the
+// Kubernetes OpenAPI schemas, so a resource reported as available may still be
+// rejected by the cluster.
+type APIAvailabilityReport struct {
+	KubernetesVersions []string                `json:"kubernetes_versions"`
+	Resources          int                     `json:"resources"`
+	Skipped            []string                `json:"skipped,omitempty"`
+	Errors             []*APIAvailabilityError `json:"errors,omitempty"`
+}
+
+// APIAvailabilityError represents an error found checking a resource
+// manifest. The Kubernetes version is not set when the error applies to all
+// of them.
+type APIAvailabilityError struct {
+	KubernetesVersion string `json:"kubernetes_version,omitempty"`
+	Template          string `json:"template,omitempty"`
+	Resource          string `json:"resource,omitempty"`
+	Message           string `json:"message"`
+}
+
+// resource represents the fields of a Kubernetes resource manifest used
+// to check its api availability.
+type resource struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name string `yaml:"name"`
+	} `yaml:"metadata"`
+}
+
+// checkAPIAvailability renders the provided chart using its default values
+// for each of the target Kubernetes versions, checking that the api versions and
+// kinds of the resources obtained are served by them. The report returned
+// contains the Kubernetes versions for which all the resources are available.
+func checkAPIAvailability(chrt *chart.Chart) *APIAvailabilityReport {
+	report := &APIAvailabilityReport{
+		KubernetesVersions: []string{},
+	}
+	if err := chartutil.ProcessDependencies(chrt, chrt.Values); err != nil {
+		report.Errors = append(report.Errors, &APIAvailabilityError{
+			Message: fmt.Sprintf("error processing dependencies: %v", err),
+		})
+		return report
+	}
+	skipped := make(map[string]struct{})
+	for _, kubeVersion := range targetKubernetesVersions {
+		manifests, err := renderManifests(chrt, kubeVersion)
+		if err != nil {
+			report.Errors = append(report.Errors, &APIAvailabilityError{
+				KubernetesVersion: kubeVersion,
+				Message:           fmt.Sprintf("error rendering chart: %v", err),
+			})
+			continue
+		}
+		report.Resources = 0
+		valid := true
+		for _, name := range sortedKeys(manifests) {
+			dec := yaml.NewDecoder(strings.NewReader(manifests[name]))
+			for {
+				var r *resource
+				if err := dec.Decode(&r); err != nil {
+					if !errors.Is(err, io.EOF) {
+						report.Errors = append(report.Errors, &APIAvailabilityError{
+							KubernetesVersion: kubeVersion,
+							Template:          name,
+							Message:           fmt.Sprintf("invalid yaml: %v", err),
+						})
+						valid = false
+					}
+					break
+				}
+				if r == nil {
+					continue
+				}
+				report.Resources++
+				id := strings.TrimSpace(fmt.Sprintf("%s/%s %s", r.APIVersion, r.Kind, r.Metadata.Name))
+				msg, ok := checkResourceAPI(r, kubeVersion)
+				if !ok {
+					skipped[id] = struct{}{}
+					continue
+				}
+				if msg != "" {
+					report.Errors = append(report.Errors, &APIAvailabilityError{
+						KubernetesVersion: kubeVersion,
+						Template:          name,
+						Resource:          id,
+						Message:           msg,
+					})
+					valid = false
+				}
+			}
+		}
+		if valid {
+			report.KubernetesVersions = append(report.KubernetesVersions, kubeVersion)
+		}
+	}
+	for id := range skipped {
+		report.Skipped = append(report.Skipped, id)
+	}
+	sort.Strings(report.Skipped)
+	return report
+}
+
+// renderManifests renders the templates of the provided chart using its
+// default values and the Kubernetes version given as capabilities. Only
+// manifests files are returned (notes and empty files are discarded).
+func renderManifests(chrt *chart.Chart, kubeVersion string) (map[string]string, error) {
+	caps := *chartutil.DefaultCapabilities
+	caps.KubeVersion = chartutil.KubeVersion{
+		Version: "v" + kubeVersion + ".0",
+		Major:   strings.Split(kubeVersion, ".")[0],
+		Minor:   strings.Split(kubeVersion, ".")[1],
+	}
+	files, err := renderTemplates(chrt, &caps)
+	if err != nil {
+		return nil, err
+	}
+	manifests := make(map[string]string, len(files))
+	for name, content := range files {
+		if strings.HasSuffix(name, "NOTES.txt") || len(bytes.TrimSpace([]byte(content))) == 0 {
+			continue
+		}
+		manifests[name] = content
+	}
+	return manifests, nil
+}
+
+// renderTemplates renders all the templates of the provided chart, including
+// the notes, using its default values and the capabilities given.
+func renderTemplates(chrt *chart.Chart, caps *chartutil.Capabilities) (map[string]string, error) {
+	options := chartutil.ReleaseOptions{
+		Name:      "release-name",
+		Namespace: "default",
+		Revision:  1,
+		IsInstall: true,
+	}
+	values, err := chartutil.ToRenderValues(chrt, chrt.Values, options, caps)
+	if err != nil {
+		return nil, err
+	}
+	return engine.Render(chrt, values)
+}
+
+// checkResourceAPI checks if the api version and kind of the resource provided
+// are served by the given Kubernetes version, returning an error message when
+// they aren't or the resource is missing them. The boolean returned is false
+// when the resource kind is not known and could not be checked.
+func checkResourceAPI(r *resource, kubeVersion string) (string, bool) {
+	if r.APIVersion == "" || r.Kind == "" {
+		return "apiVersion and kind are required", true
+	}
+	if r.Metadata.Name == "" {
+		return "metadata.name is required", true
+	}
+	api, ok := knownAPIs[r.APIVersion+"/"+r.Kind]
+	if !ok {
+		return "", false
+	}
+	minor, _ := strconv.Atoi(strings.Split(kubeVersion, ".")[1])
+	if minor < api.introduced || (api.removed != 0 && minor >= api.removed) {
+		return fmt.Sprintf("%s %s is not available in Kubernetes %s", r.APIVersion, r.Kind, kubeVersion), true
+	}
+	return "", true
+}
+
+// sortedKeys returns the keys of the map provided sorted.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,198 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestCheckAPIAvailability(t *testing.T) {
+	testCases := []struct {
+		description    string
+		templates      map[string]string
+		expectedReport *APIAvailabilityReport
+	}{
+		{
+			"valid deployment in all versions",
+			map[string]string{
+				"templates/deployment.yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: {{ .Release.Name }}\n",
+				"templates/NOTES.txt":       "Thanks for installing",
+			},
+			&APIAvailabilityReport{
+				KubernetesVersions: []string{"1.16", "1.17", "1.18", "1.19"},
+				Resources:          1,
+			},
+		},
+		{
+			"deployment api version removed",
+			map[string]string{
+				"templates/deployment.yaml": "apiVersion: extensions/v1beta1\nkind: Deployment\nmetadata:\n  name: test\n",
+			},
+			&APIAvailabilityReport{
+				KubernetesVersions: []string{},
+				Resources:          1,
+				Errors: []*APIAvailabilityError{
+					{
+						KubernetesVersion: "1.16",
+						Template:          "test/templates/deployment.yaml",
+						Resource:          "extensions/v1beta1/Deployment test",
+						Message:           "extensions/v1beta1 Deployment is not available in Kubernetes 1.16",
+					},
+					{
+						KubernetesVersion: "1.17",
+						Template:          "test/templates/deployment.yaml",
+						Resource:          "extensions/v1beta1/Deployment test",
+						Message:           "extensions/v1beta1 Deployment is not available in Kubernetes 1.17",
+					},
+					{
+						KubernetesVersion: "1.18",
+						Template:          "test/templates/deployment.yaml",
+						Resource:          "extensions/v1beta1/Deployment test",
+						Message:           "extensions/v1beta1 Deployment is not available in Kubernetes 1.18",
+					},
+					{
+						KubernetesVersion: "1.19",
+						Template:          "test/templates/deployment.yaml",
+						Resource:          "extensions/v1beta1/Deployment test",
+						Message:           "extensions/v1beta1 Deployment is not available in Kubernetes 1.19",
+					},
+				},
+			},
+		},
+		{
+			"ingress api version not available yet in some versions",
+			map[string]string{
+				"templates/ingress.yaml": "apiVersion: networking.k8s.io/v1\nkind: Ingress\nmetadata:\n  name: test\n",
+			},
+			&APIAvailabilityReport{
+				KubernetesVersions: []string{"1.19"},
+				Resources:          1,
+				Errors: []*APIAvailabilityError{
+					{
+						KubernetesVersion: "1.16",
+						Template:          "test/templates/ingress.yaml",
+						Resource:          "networking.k8s.io/v1/Ingress test",
+						Message:           "networking.k8s.io/v1 Ingress is not available in Kubernetes 1.16",
+					},
+					{
+						KubernetesVersion: "1.17",
+						Template:          "test/templates/ingress.yaml",
+						Resource:          "networking.k8s.io/v1/Ingress test",
+						Message:           "networking.k8s.io/v1 Ingress is not available in Kubernetes 1.17",
+					},
+					{
+						KubernetesVersion: "1.18",
+						Template:          "test/templates/ingress.yaml",
+						Resource:          "networking.k8s.io/v1/Ingress test",
+						Message:           "networking.k8s.io/v1 Ingress is not available in Kubernetes 1.18",
+					},
+				},
+			},
+		},
+		{
+			"custom resource skipped",
+			map[string]string{
+				"templates/resources.yaml": "apiVersion: v1\nkind: Service\nmetadata:\n  name: test\n---\napiVersion: example.com/v1\nkind: Custom\nmetadata:\n  name: test\n",
+			},
+			&APIAvailabilityReport{
+				KubernetesVersions: []string{"1.16", "1.17", "1.18", "1.19"},
+				Resources:          2,
+				Skipped:            []string{"example.com/v1/Custom test"},
+			},
+		},
+		{
+			"resource name missing",
+			map[string]string{
+				"templates/service.yaml": "apiVersion: v1\nkind: Service\n",
+			},
+			&APIAvailabilityReport{
+				KubernetesVersions: []string{},
+				Resources:          1,
+				Errors: []*APIAvailabilityError{
+					{
+						KubernetesVersion: "1.16",
+						Template:          "test/templates/service.yaml",
+						Resource:          "v1/Service",
+						Message:           "metadata.name is required",
+					},
+					{
+						KubernetesVersion: "1.17",
+						Template:          "test/templates/service.yaml",
+						Resource:          "v1/Service",
+						Message:           "metadata.name is required",
+					},
+					{
+						KubernetesVersion: "1.18",
+						Template:          "test/templates/service.yaml",
+						Resource:          "v1/Service",
+						Message:           "metadata.name is required",
+					},
+					{
+						KubernetesVersion: "1.19",
+						Template:          "test/templates/service.yaml",
+						Resource:          "v1/Service",
+						Message:           "metadata.name is required",
+					},
+				},
+			},
+		},
+		{
+			"manifests rendered depend on kubernetes version",
+			map[string]string{
+				"templates/ingress.yaml": `{{- if semverCompare ">=1.19-0" .Capabilities.KubeVersion.Version -}}
+apiVersion: networking.k8s.io/v1
+{{- else -}}
+apiVersion: networking.k8s.io/v1beta1
+{{- end }}
+kind: Ingress
+metadata:
+  name: test
+`,
+			},
+			&APIAvailabilityReport{
+				KubernetesVersions: []string{"1.16", "1.17", "1.18", "1.19"},
+				Resources:          1,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			report := checkAPIAvailability(newTestChart(tc.templates))
+			assert.Equal(t, tc.expectedReport, report)
+		})
+	}
+
+	t.Run("error rendering chart", func(t *testing.T) {
+		t.Parallel()
+		report := checkAPIAvailability(newTestChart(map[string]string{
+			"templates/invalid.yaml": "{{ .Values.missing.key }}",
+		}))
+		assert.Empty(t, report.KubernetesVersions)
+		assert.Len(t, report.Errors, len(targetKubernetesVersions))
+		for i, e := range report.Errors {
+			assert.Equal(t, targetKubernetesVersions[i], e.KubernetesVersion)
+			assert.Contains(t, e.Message, "error rendering chart")
+		}
+	})
+}
+
+func newTestChart(templates map[string]string) *chart.Chart {
+	chrt := &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion: chart.APIVersionV2,
+			Name:       "test",
+			Version:    "1.0.0",
+		},
+		Values: map[string]interface{}{},
+	}
+	for name, data := range templates {
+		chrt.Templates = append(chrt.Templates, &chart.File{
+			Name: name,
+			Data: []byte(data),
+		})
+	}
+	return chrt
+}
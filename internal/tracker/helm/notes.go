@@ -0,0 +1,50 @@
+package helm
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// notesFileName represents the name of the template that contains the notes
+// displayed by Helm once a chart has been installed.
+const notesFileName = "templates/NOTES.txt"
+
+// maxNotesSize represents the maximum size in bytes of the notes that will be
+// stored for a chart version.
+const maxNotesSize = 64 * 1024
+
+// getNotes returns the notes of the chart provided, if available. The notes
+// template is rendered using the chart's default values. When it cannot be
+// rendered (i.e. some required values have no defaults), the raw template is
+// returned instead. Only the notes of the chart itself are considered, as
+// Helm does not display the ones of the chart's dependencies.
+func getNotes(chrt *chart.Chart) (string, error) {
+	var raw string
+	for _, file := range chrt.Templates {
+		if file.Name == notesFileName {
+			raw = string(file.Data)
+			break
+		}
+	}
+	if strings.TrimSpace(raw) == "" {
+		return "", nil
+	}
+	if len(raw) > maxNotesSize {
+		return "", fmt.Errorf("%s: %w", notesFileName, errValuesFileTooBig)
+	}
+
+	notes := raw
+	files, err := renderTemplates(chrt, chartutil.DefaultCapabilities)
+	if err == nil {
+		notes = files[path.Join(chrt.Name(), notesFileName)]
+	}
+	notes = strings.TrimSpace(notes)
+	if len(notes) > maxNotesSize {
+		return "", fmt.Errorf("%s: %w", notesFileName, errValuesFileTooBig)
+	}
+	return notes, nil
+}
@@ -0,0 +1,46 @@
+package helm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNotes(t *testing.T) {
+	t.Run("chart without notes", func(t *testing.T) {
+		notes, err := getNotes(newTestChart(map[string]string{
+			"templates/deployment.yaml": "apiVersion: apps/v1\nkind: Deployment\n",
+		}))
+		require.NoError(t, err)
+		assert.Empty(t, notes)
+	})
+
+	t.Run("notes rendered using the default values", func(t *testing.T) {
+		chrt := newTestChart(map[string]string{
+			"templates/NOTES.txt":       "\n{{ .Release.Name }} installed using {{ .Values.key }}\n",
+			"templates/deployment.yaml": "apiVersion: apps/v1\nkind: Deployment\n",
+		})
+		chrt.Values["key"] = "value"
+		notes, err := getNotes(chrt)
+		require.NoError(t, err)
+		assert.Equal(t, "release-name installed using value", notes)
+	})
+
+	t.Run("raw notes returned when they cannot be rendered", func(t *testing.T) {
+		notes, err := getNotes(newTestChart(map[string]string{
+			"templates/NOTES.txt": `{{ required "key is required" .Values.key }}`,
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, `{{ required "key is required" .Values.key }}`, notes)
+	})
+
+	t.Run("notes too large", func(t *testing.T) {
+		notes, err := getNotes(newTestChart(map[string]string{
+			"templates/NOTES.txt": strings.Repeat("a", maxNotesSize+1),
+		}))
+		assert.Error(t, err)
+		assert.Empty(t, notes)
+	})
+}
@@ -0,0 +1,109 @@
+package helm
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"gopkg.in/yaml.v2"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+const (
+	// valuesPresetsAnnotation represents the chart annotation used to declare
+	// some named values presets for a chart version.
+	valuesPresetsAnnotation = "artifacthub.io/valuesPresets"
+
+	// maxValuesPresets represents the maximum number of values presets that
+	// will be processed for a chart version.
+	maxValuesPresets = 10
+)
+
+// valuesPresetNameRE is a regexp used to validate a values preset name.
+var valuesPresetNameRE = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// ValuesPreset represents a named set of values published along with a chart
+// version to ease its installation in some common scenarios (i.e. minimal,
+// high availability, air-gapped, etc).
+type ValuesPreset struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Values      string `json:"values"`
+}
+
+// getValuesPresets returns the values presets declared in the annotation
+// provided. The values of each preset can be provided inline or in a file
+// included in the chart archive. When the chart has a values schema, the
+// presets values (merged with the chart default values) are validated against
+// it. Presets that are not valid are skipped, and the errors found returned.
+func getValuesPresets(chrt *chart.Chart, v string) ([]*ValuesPreset, []error) {
+	var entries []*struct {
+		Name        string `yaml:"name"`
+		Description string `yaml:"description"`
+		Values      string `yaml:"values"`
+		File        string `yaml:"file"`
+	}
+	if err := yaml.Unmarshal([]byte(v), &entries); err != nil {
+		return nil, []error{fmt.Errorf("invalid values presets annotation: %w", err)}
+	}
+	if len(entries) > maxValuesPresets {
+		return nil, []error{fmt.Errorf("too many values presets (max %d)", maxValuesPresets)}
+	}
+
+	var presets []*ValuesPreset
+	var errs []error
+	names := make(map[string]struct{}, len(entries))
+	for _, e := range entries {
+		if !valuesPresetNameRE.MatchString(e.Name) {
+			errs = append(errs, fmt.Errorf("invalid values preset name: %q", e.Name))
+			continue
+		}
+		if _, ok := names[e.Name]; ok {
+			errs = append(errs, fmt.Errorf("duplicated values preset: %s", e.Name))
+			continue
+		}
+		names[e.Name] = struct{}{}
+		values := e.Values
+		if e.File != "" {
+			if values != "" {
+				errs = append(errs, fmt.Errorf("values preset %s: values and file are mutually exclusive", e.Name))
+				continue
+			}
+			f := getFile(chrt, e.File)
+			if f == nil {
+				errs = append(errs, fmt.Errorf("values preset %s: file %s not found", e.Name, e.File))
+				continue
+			}
+			values = string(f.Data)
+		}
+		if err := validatePresetValues(chrt, values); err != nil {
+			errs = append(errs, fmt.Errorf("values preset %s: %w", e.Name, err))
+			continue
+		}
+		presets = append(presets, &ValuesPreset{
+			Name:        e.Name,
+			Description: e.Description,
+			Values:      values,
+		})
+	}
+	return presets, errs
+}
+
+// validatePresetValues checks the preset values provided can be parsed and,
+// when the chart has a values schema, that once merged with the chart default
+// values they are valid according to it.
+func validatePresetValues(chrt *chart.Chart, values string) error {
+	if values == "" {
+		return errors.New("values not provided")
+	}
+	presetValues, err := chartutil.ReadValues([]byte(values))
+	if err != nil {
+		return fmt.Errorf("invalid values: %w", err)
+	}
+	if chrt.Schema == nil {
+		return nil
+	}
+	mergedValues := chartutil.CoalesceTables(presetValues, chrt.Values)
+	return chartutil.ValidateAgainstSingleSchema(mergedValues, chrt.Schema)
+}
@@ -0,0 +1,102 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestGetValuesPresets(t *testing.T) {
+	schema := []byte(`{
+		"type": "object",
+		"properties": {
+			"replicaCount": {"type": "integer", "minimum": 1}
+		},
+		"required": ["replicaCount"]
+	}`)
+	newChart := func(schema []byte) *chart.Chart {
+		return &chart.Chart{
+			Metadata: &chart.Metadata{Name: "chart1", Version: "1.0.0"},
+			Values:   map[string]interface{}{"replicaCount": float64(1)},
+			Schema:   schema,
+			Files: []*chart.File{
+				{Name: "presets/ha.yaml", Data: []byte("replicaCount: 3\n")},
+			},
+		}
+	}
+
+	t.Run("invalid annotation", func(t *testing.T) {
+		presets, errs := getValuesPresets(newChart(nil), "invalid")
+		assert.Nil(t, presets)
+		assert.Len(t, errs, 1)
+	})
+
+	t.Run("too many presets", func(t *testing.T) {
+		var v string
+		for i := 0; i <= maxValuesPresets; i++ {
+			v += "- name: preset\n  values: 'key: value'\n"
+		}
+		presets, errs := getValuesPresets(newChart(nil), v)
+		assert.Nil(t, presets)
+		assert.Len(t, errs, 1)
+	})
+
+	t.Run("invalid presets are skipped", func(t *testing.T) {
+		testCases := []struct {
+			name string
+			v    string
+		}{
+			{"invalid name", "- name: 'invalid name'\n  values: 'replicaCount: 2'\n"},
+			{"values not provided", "- name: minimal\n"},
+			{"values and file provided", "- name: ha\n  values: 'replicaCount: 2'\n  file: presets/ha.yaml\n"},
+			{"file not found", "- name: minimal\n  file: presets/minimal.yaml\n"},
+			{"invalid values", "- name: minimal\n  values: '- invalid'\n"},
+			{"values do not match schema", "- name: minimal\n  values: 'replicaCount: 0'\n"},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				presets, errs := getValuesPresets(newChart(schema), tc.v)
+				assert.Nil(t, presets)
+				assert.Len(t, errs, 1)
+			})
+		}
+	})
+
+	t.Run("valid presets", func(t *testing.T) {
+		v := `
+- name: minimal
+  description: Minimal installation
+  values: |
+    replicaCount: 1
+- name: ha
+  description: High availability
+  file: presets/ha.yaml
+- name: ha
+  file: presets/ha.yaml
+`
+		presets, errs := getValuesPresets(newChart(schema), v)
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "duplicated")
+		assert.Equal(t, []*ValuesPreset{
+			{
+				Name:        "minimal",
+				Description: "Minimal installation",
+				Values:      "replicaCount: 1\n",
+			},
+			{
+				Name:        "ha",
+				Description: "High availability",
+				Values:      "replicaCount: 3\n",
+			},
+		}, presets)
+	})
+
+	t.Run("presets are not validated when the chart has no schema", func(t *testing.T) {
+		presets, errs := getValuesPresets(newChart(nil), "- name: minimal\n  values: 'replicaCount: 0'\n")
+		assert.Empty(t, errs)
+		assert.Len(t, presets, 1)
+	})
+}
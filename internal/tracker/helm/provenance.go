@@ -0,0 +1,279 @@
+package helm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/clearsign"
+	"golang.org/x/crypto/openpgp/packet"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// signKeyAnnotation represents the chart annotation used to declare the
+	// url where the public key used to sign the chart version can be fetched
+	// from, optionally along with its fingerprint.
+	signKeyAnnotation = "artifacthub.io/signKey"
+
+	// maxProvenanceFileSize represents the maximum size in bytes of the
+	// provenance files that will be processed.
+	maxProvenanceFileSize = 1024 * 1024
+
+	// maxSignKeySize represents the maximum size in bytes of the public keys
+	// that will be fetched to verify the provenance files signatures.
+	maxSignKeySize = 256 * 1024
+
+	// signaturePacketTag represents the OpenPGP signature packet tag.
+	signaturePacketTag = 2
+
+	// Signature subpackets types used to identify the signing key.
+	issuerSubpacket            = 16
+	issuerFingerprintSubpacket = 33
+)
+
+// fingerprintRE is a regexp used to validate the keys fingerprints declared in
+// the sign key annotation (v4 keys fingerprints, hex encoded).
+var fingerprintRE = regexp.MustCompile(`^[A-F0-9]{40}$`)
+
+// errMalformedSignature indicates that the signature packet found in the
+// provenance file is malformed.
+var errMalformedSignature = errors.New("malformed provenance file signature")
+
+// getProvenanceSignKey returns the details of the key used to sign the
+// provenance file provided, extracted from its PGP signature. The key id is
+// always available, but the fingerprint is only included by some signing
+// tools.
+func getProvenanceSignKey(prov []byte) (*hub.SignKey, error) {
+	block, _ := clearsign.Decode(prov)
+	if block == nil || block.ArmoredSignature == nil {
+		return nil, errors.New("provenance file signature not found")
+	}
+	r := packet.NewOpaqueReader(block.ArmoredSignature.Body)
+	for {
+		op, err := r.Next()
+		if err != nil {
+			if err == io.EOF {
+				return nil, errors.New("provenance file signature packet not found")
+			}
+			return nil, fmt.Errorf("error reading provenance file signature: %w", err)
+		}
+		if op.Tag != signaturePacketTag {
+			continue
+		}
+		keyID, fingerprint, err := parseSignaturePacket(op.Contents)
+		if err != nil {
+			return nil, err
+		}
+		return &hub.SignKey{
+			KeyID:       keyID,
+			Fingerprint: fingerprint,
+		}, nil
+	}
+}
+
+// parseSignaturePacket returns the id and the fingerprint (when available) of
+// the key that issued the signature packet provided (RFC 4880 section 5.2).
+func parseSignaturePacket(contents []byte) (keyID, fingerprint string, err error) {
+	if len(contents) == 0 {
+		return "", "", errMalformedSignature
+	}
+	switch contents[0] {
+	case 3:
+		// Version, hashed material length, signature type, creation time and
+		// key id (8 octets)
+		if len(contents) < 15 {
+			return "", "", errMalformedSignature
+		}
+		keyID = strings.ToUpper(hex.EncodeToString(contents[7:15]))
+	case 4:
+		// Version, signature type, public key and hash algorithms, followed by
+		// the hashed and unhashed subpackets, each prefixed by their length
+		if len(contents) < 6 {
+			return "", "", errMalformedSignature
+		}
+		hashedLength := int(binary.BigEndian.Uint16(contents[4:6]))
+		if len(contents) < 6+hashedLength+2 {
+			return "", "", errMalformedSignature
+		}
+		hashed := contents[6 : 6+hashedLength]
+		unhashedLength := int(binary.BigEndian.Uint16(contents[6+hashedLength:]))
+		if len(contents) < 8+hashedLength+unhashedLength {
+			return "", "", errMalformedSignature
+		}
+		unhashed := contents[8+hashedLength : 8+hashedLength+unhashedLength]
+		for _, subpackets := range [][]byte{hashed, unhashed} {
+			if err := parseSignatureSubpackets(subpackets, &keyID, &fingerprint); err != nil {
+				return "", "", err
+			}
+		}
+		if keyID == "" && len(fingerprint) == 40 {
+			// The id of v4 keys are the low 64 bits of their fingerprint
+			keyID = fingerprint[24:]
+		}
+	default:
+		return "", "", fmt.Errorf("unsupported provenance file signature version: %d", contents[0])
+	}
+	if keyID == "" {
+		return "", "", errors.New("provenance file signature issuer not found")
+	}
+	return keyID, fingerprint, nil
+}
+
+// parseSignatureSubpackets looks for the issuer and issuer fingerprint
+// subpackets in the signature subpackets data provided (RFC 4880 section
+// 5.2.3.1), setting the key id and fingerprint when found.
+func parseSignatureSubpackets(data []byte, keyID, fingerprint *string) error {
+	for len(data) > 0 {
+		var length int
+		switch o := data[0]; {
+		case o < 192:
+			length, data = int(o), data[1:]
+		case o < 255:
+			if len(data) < 2 {
+				return errMalformedSignature
+			}
+			length, data = (int(o)-192)<<8+int(data[1])+192, data[2:]
+		default:
+			if len(data) < 5 {
+				return errMalformedSignature
+			}
+			length, data = int(binary.BigEndian.Uint32(data[1:5])), data[5:]
+		}
+		if length == 0 || length > len(data) {
+			return errMalformedSignature
+		}
+		subpacket := data[:length]
+		data = data[length:]
+
+		// The most significant bit of the subpacket type is the critical flag
+		switch subpacket[0] & 0x7f {
+		case issuerSubpacket:
+			if len(subpacket) == 9 {
+				*keyID = strings.ToUpper(hex.EncodeToString(subpacket[1:]))
+			}
+		case issuerFingerprintSubpacket:
+			// Key version followed by the fingerprint
+			if len(subpacket) > 2 {
+				*fingerprint = strings.ToUpper(hex.EncodeToString(subpacket[2:]))
+			}
+		}
+	}
+	return nil
+}
+
+// applySignKeyAnnotation adds the public key url and fingerprint declared in
+// the sign key annotation provided to the sign key given. The fingerprint
+// declared must match the key that signed the chart version. The sign key is
+// not modified when the annotation is not valid.
+func applySignKeyAnnotation(k *hub.SignKey, v string) error {
+	var a struct {
+		Fingerprint string `yaml:"fingerprint"`
+		URL         string `yaml:"url"`
+	}
+	if err := yaml.Unmarshal([]byte(v), &a); err != nil {
+		return fmt.Errorf("invalid sign key annotation: %w", err)
+	}
+	u, err := url.Parse(a.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("invalid sign key url")
+	}
+	fingerprint := strings.ToUpper(strings.Replace(a.Fingerprint, " ", "", -1))
+	if fingerprint != "" {
+		if !fingerprintRE.MatchString(fingerprint) {
+			return errors.New("invalid sign key fingerprint")
+		}
+		if (k.Fingerprint != "" && k.Fingerprint != fingerprint) || !strings.HasSuffix(fingerprint, k.KeyID) {
+			return errors.New("sign key fingerprint does not match the key used to sign the chart")
+		}
+	}
+	k.URL = a.URL
+	if k.Fingerprint == "" {
+		k.Fingerprint = fingerprint
+	}
+	return nil
+}
+
+// readProvenanceFile reads the provenance file provided, making sure it does
+// not exceed the maximum size allowed.
+func readProvenanceFile(r io.Reader) ([]byte, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, maxProvenanceFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxProvenanceFileSize {
+		return nil, fmt.Errorf("provenance file exceeds the maximum size allowed (%d bytes)", maxProvenanceFileSize)
+	}
+	return data, nil
+}
+
+// verifyProvenanceFile verifies that the provenance file provided has been
+// signed by one of the keys in the keyring given and that the digest of the
+// chart archive provided matches the one listed in it. The entity that signed
+// the provenance file is returned.
+func verifyProvenanceFile(
+	prov []byte,
+	keyring openpgp.EntityList,
+	archiveName string,
+	digest string,
+) (*openpgp.Entity, error) {
+	block, _ := clearsign.Decode(prov)
+	if block == nil || block.ArmoredSignature == nil {
+		return nil, errors.New("provenance file signature not found")
+	}
+	signer, err := openpgp.CheckDetachedSignature(keyring, bytes.NewReader(block.Bytes), block.ArmoredSignature.Body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid provenance file signature: %w", err)
+	}
+	files, err := parseProvenanceFiles(block.Plaintext)
+	if err != nil {
+		return nil, err
+	}
+	provDigest, ok := files[archiveName]
+	if !ok {
+		return nil, fmt.Errorf("chart archive %s not found in provenance file", archiveName)
+	}
+	if normalizeChartDigest(strings.ToLower(provDigest)) != normalizeChartDigest(strings.ToLower(digest)) {
+		return nil, errors.New("chart archive digest does not match the one in the provenance file")
+	}
+	return signer, nil
+}
+
+// parseProvenanceFiles returns the digests of the files listed in the content
+// of the provenance file provided, indexed by file name. The files section
+// follows the chart metadata, separated from it by a yaml document end marker.
+func parseProvenanceFiles(content []byte) (map[string]string, error) {
+	parts := bytes.SplitN(content, []byte("\n...\n"), 2)
+	if len(parts) != 2 {
+		return nil, errors.New("provenance file files section not found")
+	}
+	var s struct {
+		Files map[string]string `yaml:"files"`
+	}
+	if err := yaml.Unmarshal(parts[1], &s); err != nil {
+		return nil, fmt.Errorf("invalid provenance file files section: %w", err)
+	}
+	return s.Files, nil
+}
+
+// readSignKeyring parses the public keyring provided, which can be either
+// armored or binary.
+func readSignKeyring(data []byte) (openpgp.EntityList, error) {
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+	if err != nil {
+		keyring, err = openpgp.ReadKeyRing(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid sign key: %w", err)
+	}
+	return keyring, nil
+}
@@ -0,0 +1,392 @@
+package helm
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	"golang.org/x/crypto/openpgp/clearsign"
+)
+
+func TestGetProvenanceSignKey(t *testing.T) {
+	t.Run("provenance file signed successfully", func(t *testing.T) {
+		// Sign a provenance file using a new key
+		entity, err := openpgp.NewEntity("user1", "", "user1@email.com", nil)
+		require.NoError(t, err)
+		var prov bytes.Buffer
+		w, err := clearsign.Encode(&prov, entity.PrivateKey, nil)
+		require.NoError(t, err)
+		_, err = w.Write([]byte("name: pkg1\nversion: 1.0.0\n\n...\nfiles:\n  pkg1-1.0.0.tgz: sha256:digest\n"))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		// Check the key used is extracted from the signature
+		k, err := getProvenanceSignKey(prov.Bytes())
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("%016X", entity.PrimaryKey.KeyId), k.KeyID)
+		assert.Empty(t, k.URL)
+	})
+
+	t.Run("invalid provenance files", func(t *testing.T) {
+		testCases := []struct {
+			prov []byte
+		}{
+			{nil},
+			{[]byte("")},
+			{[]byte("name: pkg1\nversion: 1.0.0\n")},
+			{[]byte(`-----BEGIN PGP SIGNED MESSAGE-----
+Hash: SHA256
+
+name: pkg1
+-----BEGIN PGP SIGNATURE-----
+
+invalid
+-----END PGP SIGNATURE-----
+`)},
+		}
+		for i, tc := range testCases {
+			tc := tc
+			t.Run(fmt.Sprint(i), func(t *testing.T) {
+				k, err := getProvenanceSignKey(tc.prov)
+				assert.Error(t, err)
+				assert.Nil(t, k)
+			})
+		}
+	})
+}
+
+func TestParseSignaturePacket(t *testing.T) {
+	keyID := []byte{0x34, 0x36, 0x5D, 0x94, 0x72, 0xD7, 0x46, 0x8F}
+	fingerprint := []byte{
+		0xC8, 0x74, 0x01, 0x1F, 0x0A, 0xB4, 0x05, 0x11, 0x0D, 0x02,
+		0x10, 0x55, 0x34, 0x36, 0x5D, 0x94, 0x72, 0xD7, 0x46, 0x8F,
+	}
+	issuer := append([]byte{9, issuerSubpacket}, keyID...)
+	issuerFingerprint := append([]byte{22, issuerFingerprintSubpacket, 4}, fingerprint...)
+	creationTime := []byte{5, 2, 0x5F, 0x00, 0x00, 0x00}
+
+	// v4Signature builds a v4 signature packet with the hashed and unhashed
+	// subpackets provided
+	v4Signature := func(hashed, unhashed []byte) []byte {
+		p := []byte{4, 0x00, 1, 8}
+		p = append(p, byte(len(hashed)>>8), byte(len(hashed)))
+		p = append(p, hashed...)
+		p = append(p, byte(len(unhashed)>>8), byte(len(unhashed)))
+		p = append(p, unhashed...)
+		return append(p, 0xAB, 0xCD)
+	}
+
+	testCases := []struct {
+		contents            []byte
+		expectedKeyID       string
+		expectedFingerprint string
+		expectedError       bool
+	}{
+		{
+			v4Signature(creationTime, issuer),
+			"34365D9472D7468F",
+			"",
+			false,
+		},
+		{
+			v4Signature(append(creationTime, issuerFingerprint...), issuer),
+			"34365D9472D7468F",
+			"C874011F0AB405110D02105534365D9472D7468F",
+			false,
+		},
+		{
+			v4Signature(append(creationTime, issuerFingerprint...), nil),
+			"34365D9472D7468F",
+			"C874011F0AB405110D02105534365D9472D7468F",
+			false,
+		},
+		{
+			append([]byte{3, 5, 0x00, 0x5F, 0x00, 0x00, 0x00}, append(keyID, 1, 8, 0xAB, 0xCD)...),
+			"34365D9472D7468F",
+			"",
+			false,
+		},
+		{
+			v4Signature(creationTime, nil),
+			"",
+			"",
+			true,
+		},
+		{
+			v4Signature([]byte{50, 16, 0x00}, nil),
+			"",
+			"",
+			true,
+		},
+		{
+			[]byte{4, 0x00, 1, 8, 0x00, 0xFF},
+			"",
+			"",
+			true,
+		},
+		{
+			[]byte{5, 0x00, 1, 8},
+			"",
+			"",
+			true,
+		},
+		{
+			nil,
+			"",
+			"",
+			true,
+		},
+	}
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			keyID, fingerprint, err := parseSignaturePacket(tc.contents)
+			if tc.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedKeyID, keyID)
+			assert.Equal(t, tc.expectedFingerprint, fingerprint)
+		})
+	}
+}
+
+func TestApplySignKeyAnnotation(t *testing.T) {
+	testCases := []struct {
+		signKey         *hub.SignKey
+		annotation      string
+		expectedSignKey *hub.SignKey
+		expectedError   string
+	}{
+		{
+			&hub.SignKey{KeyID: "34365D9472D7468F"},
+			"url: https://keybase.io/user1/pgp_keys.asc",
+			&hub.SignKey{
+				KeyID: "34365D9472D7468F",
+				URL:   "https://keybase.io/user1/pgp_keys.asc",
+			},
+			"",
+		},
+		{
+			&hub.SignKey{KeyID: "34365D9472D7468F"},
+			`
+fingerprint: c874 011f 0ab4 0511 0d02 1055 3436 5d94 72d7 468f
+url: https://keybase.io/user1/pgp_keys.asc
+`,
+			&hub.SignKey{
+				KeyID:       "34365D9472D7468F",
+				Fingerprint: "C874011F0AB405110D02105534365D9472D7468F",
+				URL:         "https://keybase.io/user1/pgp_keys.asc",
+			},
+			"",
+		},
+		{
+			&hub.SignKey{
+				KeyID:       "34365D9472D7468F",
+				Fingerprint: "C874011F0AB405110D02105534365D9472D7468F",
+			},
+			`
+fingerprint: C874011F0AB405110D02105534365D9472D7468F
+url: https://keybase.io/user1/pgp_keys.asc
+`,
+			&hub.SignKey{
+				KeyID:       "34365D9472D7468F",
+				Fingerprint: "C874011F0AB405110D02105534365D9472D7468F",
+				URL:         "https://keybase.io/user1/pgp_keys.asc",
+			},
+			"",
+		},
+		{
+			&hub.SignKey{KeyID: "34365D9472D7468F"},
+			"{",
+			&hub.SignKey{KeyID: "34365D9472D7468F"},
+			"invalid sign key annotation",
+		},
+		{
+			&hub.SignKey{KeyID: "34365D9472D7468F"},
+			"fingerprint: C874011F0AB405110D02105534365D9472D7468F",
+			&hub.SignKey{KeyID: "34365D9472D7468F"},
+			"invalid sign key url",
+		},
+		{
+			&hub.SignKey{KeyID: "34365D9472D7468F"},
+			"url: ftp://keybase.io/user1/pgp_keys.asc",
+			&hub.SignKey{KeyID: "34365D9472D7468F"},
+			"invalid sign key url",
+		},
+		{
+			&hub.SignKey{KeyID: "34365D9472D7468F"},
+			`
+fingerprint: C874011F
+url: https://keybase.io/user1/pgp_keys.asc
+`,
+			&hub.SignKey{KeyID: "34365D9472D7468F"},
+			"invalid sign key fingerprint",
+		},
+		{
+			&hub.SignKey{KeyID: "0000000000000001"},
+			`
+fingerprint: C874011F0AB405110D02105534365D9472D7468F
+url: https://keybase.io/user1/pgp_keys.asc
+`,
+			&hub.SignKey{KeyID: "0000000000000001"},
+			"sign key fingerprint does not match the key used to sign the chart",
+		},
+		{
+			&hub.SignKey{
+				KeyID:       "34365D9472D7468F",
+				Fingerprint: "0000000000000000000000000000000000000001",
+			},
+			`
+fingerprint: C874011F0AB405110D02105534365D9472D7468F
+url: https://keybase.io/user1/pgp_keys.asc
+`,
+			&hub.SignKey{
+				KeyID:       "34365D9472D7468F",
+				Fingerprint: "0000000000000000000000000000000000000001",
+			},
+			"sign key fingerprint does not match the key used to sign the chart",
+		},
+	}
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			err := applySignKeyAnnotation(tc.signKey, tc.annotation)
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedSignKey, tc.signKey)
+		})
+	}
+}
+
+func TestVerifyProvenanceFile(t *testing.T) {
+	entity, err := openpgp.NewEntity("user1", "", "user1@email.com", nil)
+	require.NoError(t, err)
+	otherEntity, err := openpgp.NewEntity("user2", "", "user2@email.com", nil)
+	require.NoError(t, err)
+	digest := computeChartDigest([]byte("chart archive data"))
+	prov := newTestProvenanceFile(t, entity, "name: pkg1\nversion: 1.0.0\n\n...\nfiles:\n  pkg1-1.0.0.tgz: sha256:"+digest+"\n")
+
+	t.Run("provenance file verified successfully", func(t *testing.T) {
+		signer, err := verifyProvenanceFile(prov, openpgp.EntityList{otherEntity, entity}, "pkg1-1.0.0.tgz", digest)
+		require.NoError(t, err)
+		assert.Equal(t, entity.PrimaryKey.KeyId, signer.PrimaryKey.KeyId)
+	})
+
+	t.Run("invalid provenance files", func(t *testing.T) {
+		testCases := []struct {
+			description   string
+			prov          []byte
+			keyring       openpgp.EntityList
+			archiveName   string
+			digest        string
+			expectedError string
+		}{
+			{
+				"provenance file not signed",
+				[]byte("name: pkg1\nversion: 1.0.0\n"),
+				openpgp.EntityList{entity},
+				"pkg1-1.0.0.tgz",
+				digest,
+				"provenance file signature not found",
+			},
+			{
+				"signed by a different key",
+				prov,
+				openpgp.EntityList{otherEntity},
+				"pkg1-1.0.0.tgz",
+				digest,
+				"invalid provenance file signature",
+			},
+			{
+				"chart archive not listed",
+				prov,
+				openpgp.EntityList{entity},
+				"pkg2-1.0.0.tgz",
+				digest,
+				"chart archive pkg2-1.0.0.tgz not found in provenance file",
+			},
+			{
+				"chart archive digest mismatch",
+				prov,
+				openpgp.EntityList{entity},
+				"pkg1-1.0.0.tgz",
+				computeChartDigest([]byte("other data")),
+				"chart archive digest does not match the one in the provenance file",
+			},
+			{
+				"files section not found",
+				newTestProvenanceFile(t, entity, "name: pkg1\nversion: 1.0.0\n"),
+				openpgp.EntityList{entity},
+				"pkg1-1.0.0.tgz",
+				digest,
+				"provenance file files section not found",
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.description, func(t *testing.T) {
+				signer, err := verifyProvenanceFile(tc.prov, tc.keyring, tc.archiveName, tc.digest)
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				assert.Nil(t, signer)
+			})
+		}
+	})
+}
+
+func TestReadSignKeyring(t *testing.T) {
+	entity, err := openpgp.NewEntity("user1", "", "user1@email.com", nil)
+	require.NoError(t, err)
+
+	t.Run("armored key", func(t *testing.T) {
+		keyring, err := readSignKeyring(newTestArmoredPublicKey(t, entity))
+		require.NoError(t, err)
+		require.Len(t, keyring, 1)
+		assert.Equal(t, entity.PrimaryKey.Fingerprint, keyring[0].PrimaryKey.Fingerprint)
+	})
+
+	t.Run("binary key", func(t *testing.T) {
+		var key bytes.Buffer
+		require.NoError(t, entity.Serialize(&key))
+		keyring, err := readSignKeyring(key.Bytes())
+		require.NoError(t, err)
+		require.Len(t, keyring, 1)
+		assert.Equal(t, entity.PrimaryKey.Fingerprint, keyring[0].PrimaryKey.Fingerprint)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		keyring, err := readSignKeyring([]byte("invalid"))
+		assert.Error(t, err)
+		assert.Nil(t, keyring)
+	})
+}
+
+func newTestProvenanceFile(t *testing.T, entity *openpgp.Entity, content string) []byte {
+	var prov bytes.Buffer
+	w, err := clearsign.Encode(&prov, entity.PrivateKey, nil)
+	require.NoError(t, err)
+	_, err = w.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return prov.Bytes()
+}
+
+func newTestArmoredPublicKey(t *testing.T, entity *openpgp.Entity) []byte {
+	var key bytes.Buffer
+	w, err := armor.Encode(&key, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+	return key.Bytes()
+}
@@ -0,0 +1,98 @@
+package helm
+
+import (
+	"fmt"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// Checks evaluated on the rendered manifests of charts.
+const (
+	checkRunAsRoot        = "run-as-root"
+	checkNoResourceLimits = "no-resource-limits"
+	checkHostPathVolume   = "host-path-volume"
+)
+
+// Recommendation represents a finding obtained when evaluating the manifests
+// rendered from a chart against the built-in checks.
+type Recommendation struct {
+	Check    string `json:"check"`
+	Template string `json:"template"`
+	Resource string `json:"resource"`
+	Message  string `json:"message"`
+}
+
+// analyzeManifests renders the provided chart using its default values and
+// evaluates the workloads obtained against the built-in checks, returning
+// the recommendations found.
+func analyzeManifests(chrt *chart.Chart) ([]*Recommendation, error) {
+	workloads, err := renderWorkloads(chrt)
+	if err != nil {
+		return nil, err
+	}
+	recommendations := make([]*Recommendation, 0)
+	for _, wl := range workloads {
+		for _, r := range wl.pod().analyze() {
+			r.Template = wl.template
+			r.Resource = wl.id()
+			recommendations = append(recommendations, r)
+		}
+	}
+	return recommendations, nil
+}
+
+// analyze evaluates the pod specification against the built-in checks. The
+// recommendations returned only include the check and the message.
+func (s *podSpec) analyze() []*Recommendation {
+	var recommendations []*Recommendation
+	containers := append(append([]*container{}, s.InitContainers...), s.Containers...)
+	for _, c := range containers {
+		if c == nil {
+			continue
+		}
+		if runsAsRoot(s.SecurityContext, c.SecurityContext) {
+			recommendations = append(recommendations, &Recommendation{
+				Check:   checkRunAsRoot,
+				Message: fmt.Sprintf("container %s may run as root, consider setting runAsNonRoot", c.Name),
+			})
+		}
+		if c.Resources.Limits["cpu"] == nil || c.Resources.Limits["memory"] == nil {
+			recommendations = append(recommendations, &Recommendation{
+				Check:   checkNoResourceLimits,
+				Message: fmt.Sprintf("container %s does not define cpu and memory limits", c.Name),
+			})
+		}
+	}
+	for _, v := range s.Volumes {
+		if v != nil && v.HostPath != nil {
+			recommendations = append(recommendations, &Recommendation{
+				Check:   checkHostPathVolume,
+				Message: fmt.Sprintf("volume %s mounts host path %s", v.Name, v.HostPath.Path),
+			})
+		}
+	}
+	return recommendations
+}
+
+// runsAsRoot checks if a container may run as root given its security context
+// and the one defined at the pod level. Settings in the container security
+// context take precedence.
+func runsAsRoot(podSC, containerSC *securityContext) bool {
+	var runAsUser *int64
+	var runAsNonRoot *bool
+	for _, sc := range []*securityContext{podSC, containerSC} {
+		if sc == nil {
+			continue
+		}
+		if sc.RunAsUser != nil {
+			runAsUser = sc.RunAsUser
+		}
+		if sc.RunAsNonRoot != nil {
+			runAsNonRoot = sc.RunAsNonRoot
+		}
+	}
+	if runAsUser != nil {
+		return *runAsUser == 0
+	}
+	return runAsNonRoot == nil || !*runAsNonRoot
+}
@@ -0,0 +1,139 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeManifests(t *testing.T) {
+	t.Run("error rendering chart", func(t *testing.T) {
+		t.Parallel()
+		recommendations, err := analyzeManifests(newTestChart(map[string]string{
+			"templates/invalid.yaml": "{{ .Values.missing.key }}",
+		}))
+		assert.Error(t, err)
+		assert.Nil(t, recommendations)
+	})
+
+	t.Run("manifests analyzed successfully", func(t *testing.T) {
+		testCases := []struct {
+			description             string
+			templates               map[string]string
+			expectedRecommendations []*Recommendation
+		}{
+			{
+				"no workloads",
+				map[string]string{
+					"templates/service.yaml": "apiVersion: v1\nkind: Service\nmetadata:\n  name: test\n",
+				},
+				[]*Recommendation{},
+			},
+			{
+				"compliant deployment",
+				map[string]string{
+					"templates/deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test
+spec:
+  template:
+    spec:
+      securityContext:
+        runAsNonRoot: true
+      containers:
+        - name: app
+          resources:
+            limits:
+              cpu: 100m
+              memory: 128Mi
+`,
+				},
+				[]*Recommendation{},
+			},
+			{
+				"deployment with findings",
+				map[string]string{
+					"templates/deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test
+spec:
+  template:
+    spec:
+      securityContext:
+        runAsNonRoot: true
+      containers:
+        - name: app
+          securityContext:
+            runAsUser: 0
+      volumes:
+        - name: data
+          hostPath:
+            path: /var/lib/data
+`,
+				},
+				[]*Recommendation{
+					{
+						Check:    checkRunAsRoot,
+						Template: "test/templates/deployment.yaml",
+						Resource: "apps/v1/Deployment test",
+						Message:  "container app may run as root, consider setting runAsNonRoot",
+					},
+					{
+						Check:    checkNoResourceLimits,
+						Template: "test/templates/deployment.yaml",
+						Resource: "apps/v1/Deployment test",
+						Message:  "container app does not define cpu and memory limits",
+					},
+					{
+						Check:    checkHostPathVolume,
+						Template: "test/templates/deployment.yaml",
+						Resource: "apps/v1/Deployment test",
+						Message:  "volume data mounts host path /var/lib/data",
+					},
+				},
+			},
+			{
+				"cronjob without cpu limit",
+				map[string]string{
+					"templates/cronjob.yaml": `apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: test
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: job
+              securityContext:
+                runAsUser: 1000
+              resources:
+                limits:
+                  memory: 128Mi
+`,
+				},
+				[]*Recommendation{
+					{
+						Check:    checkNoResourceLimits,
+						Template: "test/templates/cronjob.yaml",
+						Resource: "batch/v1beta1/CronJob test",
+						Message:  "container job does not define cpu and memory limits",
+					},
+				},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.description, func(t *testing.T) {
+				t.Parallel()
+				recommendations, err := analyzeManifests(newTestChart(tc.templates))
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedRecommendations, recommendations)
+			})
+		}
+	})
+}
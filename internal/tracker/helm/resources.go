@@ -0,0 +1,184 @@
+package helm
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// quantitySuffixes represents the multipliers of the suffixes supported in
+// Kubernetes resources quantities. Binary suffixes are listed first so that
+// they are matched before the decimal ones sharing their initial letter.
+var quantitySuffixes = []struct {
+	suffix     string
+	multiplier float64
+}{
+	{"Ki", 1 << 10},
+	{"Mi", 1 << 20},
+	{"Gi", 1 << 30},
+	{"Ti", 1 << 40},
+	{"Pi", 1 << 50},
+	{"Ei", 1 << 60},
+	{"n", 1e-9},
+	{"u", 1e-6},
+	{"m", 1e-3},
+	{"k", 1e3},
+	{"M", 1e6},
+	{"G", 1e9},
+	{"T", 1e12},
+	{"P", 1e15},
+	{"E", 1e18},
+}
+
+// errInvalidQuantity indicates that the quantity provided is not valid.
+var errInvalidQuantity = errors.New("invalid quantity")
+
+// ResourcesEstimation represents the aggregated compute resources required by
+// the workloads rendered from a chart using its default values.
+type ResourcesEstimation struct {
+	Workloads int               `json:"workloads"`
+	Requests  *ResourcesAmounts `json:"requests"`
+	Limits    *ResourcesAmounts `json:"limits"`
+}
+
+// ResourcesAmounts represents some amounts of cpu and memory.
+type ResourcesAmounts struct {
+	CPUMillicores int64 `json:"cpu_millicores"`
+	MemoryBytes   int64 `json:"memory_bytes"`
+}
+
+// estimateResources renders the provided chart using its default values and
+// aggregates the cpu and memory requests and limits of the workloads obtained,
+// taking into account the number of replicas (DaemonSets are counted once, as
+// the number of nodes is not known).
+func estimateResources(chrt *chart.Chart) (*ResourcesEstimation, error) {
+	workloads, err := renderWorkloads(chrt)
+	if err != nil {
+		return nil, err
+	}
+	e := &ResourcesEstimation{
+		Workloads: len(workloads),
+		Requests:  &ResourcesAmounts{},
+		Limits:    &ResourcesAmounts{},
+	}
+	for _, wl := range workloads {
+		replicas := int64(1)
+		switch wl.Kind {
+		case "Deployment", "ReplicaSet", "ReplicationController", "StatefulSet":
+			if wl.Spec.Replicas != nil {
+				replicas = *wl.Spec.Replicas
+			}
+		}
+		requests, limits, err := wl.pod().resources()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", wl.id(), err)
+		}
+		e.Requests.CPUMillicores += replicas * requests.CPUMillicores
+		e.Requests.MemoryBytes += replicas * requests.MemoryBytes
+		e.Limits.CPUMillicores += replicas * limits.CPUMillicores
+		e.Limits.MemoryBytes += replicas * limits.MemoryBytes
+	}
+	return e, nil
+}
+
+// resources returns the effective cpu and memory requests and limits of the
+// pod. Like Kubernetes does, for each resource the highest value between the
+// sum of all containers and the largest init container is used.
+func (s *podSpec) resources() (*ResourcesAmounts, *ResourcesAmounts, error) {
+	var requests, limits, initRequests, initLimits ResourcesAmounts
+	for _, c := range s.Containers {
+		if c == nil {
+			continue
+		}
+		for _, e := range []struct {
+			src map[string]interface{}
+			dst *ResourcesAmounts
+		}{
+			{c.Resources.Requests, &requests},
+			{c.Resources.Limits, &limits},
+		} {
+			a, err := parseResourcesAmounts(e.src)
+			if err != nil {
+				return nil, nil, fmt.Errorf("container %s: %w", c.Name, err)
+			}
+			e.dst.CPUMillicores += a.CPUMillicores
+			e.dst.MemoryBytes += a.MemoryBytes
+		}
+	}
+	for _, c := range s.InitContainers {
+		if c == nil {
+			continue
+		}
+		for _, e := range []struct {
+			src map[string]interface{}
+			dst *ResourcesAmounts
+		}{
+			{c.Resources.Requests, &initRequests},
+			{c.Resources.Limits, &initLimits},
+		} {
+			a, err := parseResourcesAmounts(e.src)
+			if err != nil {
+				return nil, nil, fmt.Errorf("init container %s: %w", c.Name, err)
+			}
+			e.dst.CPUMillicores = maxInt64(e.dst.CPUMillicores, a.CPUMillicores)
+			e.dst.MemoryBytes = maxInt64(e.dst.MemoryBytes, a.MemoryBytes)
+		}
+	}
+	requests.CPUMillicores = maxInt64(requests.CPUMillicores, initRequests.CPUMillicores)
+	requests.MemoryBytes = maxInt64(requests.MemoryBytes, initRequests.MemoryBytes)
+	limits.CPUMillicores = maxInt64(limits.CPUMillicores, initLimits.CPUMillicores)
+	limits.MemoryBytes = maxInt64(limits.MemoryBytes, initLimits.MemoryBytes)
+	return &requests, &limits, nil
+}
+
+// parseResourcesAmounts parses the cpu and memory quantities of the requests
+// or limits of a container.
+func parseResourcesAmounts(resources map[string]interface{}) (*ResourcesAmounts, error) {
+	a := &ResourcesAmounts{}
+	if v, ok := resources["cpu"]; ok && v != nil {
+		q, err := parseQuantity(fmt.Sprint(v))
+		if err != nil {
+			return nil, fmt.Errorf("cpu: %w", err)
+		}
+		a.CPUMillicores = int64(math.Ceil(q * 1000))
+	}
+	if v, ok := resources["memory"]; ok && v != nil {
+		q, err := parseQuantity(fmt.Sprint(v))
+		if err != nil {
+			return nil, fmt.Errorf("memory: %w", err)
+		}
+		a.MemoryBytes = int64(math.Ceil(q))
+	}
+	return a, nil
+}
+
+// parseQuantity parses the Kubernetes resource quantity provided (i.e. 100m,
+// 1.5, 128Mi, 1e3), returning its value.
+func parseQuantity(s string) (float64, error) {
+	number := strings.TrimSpace(s)
+	multiplier := float64(1)
+	for _, qs := range quantitySuffixes {
+		if strings.HasSuffix(number, qs.suffix) {
+			number = strings.TrimSuffix(number, qs.suffix)
+			multiplier = qs.multiplier
+			break
+		}
+	}
+	v, err := strconv.ParseFloat(number, 64)
+	if err != nil || v < 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+		return 0, fmt.Errorf("%w: %s", errInvalidQuantity, s)
+	}
+	return v * multiplier, nil
+}
+
+// maxInt64 returns the largest of the values provided.
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
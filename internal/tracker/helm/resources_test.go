@@ -0,0 +1,144 @@
+package helm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateResources(t *testing.T) {
+	t.Run("error rendering chart", func(t *testing.T) {
+		t.Parallel()
+		e, err := estimateResources(newTestChart(map[string]string{
+			"templates/invalid.yaml": "{{ .Values.missing.key }}",
+		}))
+		assert.Error(t, err)
+		assert.Nil(t, e)
+	})
+
+	t.Run("invalid quantity", func(t *testing.T) {
+		t.Parallel()
+		e, err := estimateResources(newTestChart(map[string]string{
+			"templates/pod.yaml": `apiVersion: v1
+kind: Pod
+metadata:
+  name: test
+spec:
+  containers:
+    - name: app
+      resources:
+        requests:
+          cpu: invalid
+`,
+		}))
+		assert.True(t, errors.Is(err, errInvalidQuantity))
+		assert.Nil(t, e)
+	})
+
+	t.Run("resources estimated successfully", func(t *testing.T) {
+		t.Parallel()
+		e, err := estimateResources(newTestChart(map[string]string{
+			"templates/deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test
+spec:
+  replicas: 3
+  template:
+    spec:
+      initContainers:
+        - name: init
+          resources:
+            requests:
+              cpu: 1
+              memory: 64Mi
+      containers:
+        - name: app
+          resources:
+            requests:
+              cpu: 250m
+              memory: 128Mi
+            limits:
+              cpu: 500m
+              memory: 256Mi
+        - name: sidecar
+          resources:
+            requests:
+              cpu: 50m
+              memory: 32Mi
+`,
+			"templates/daemonset.yaml": `apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: test
+spec:
+  template:
+    spec:
+      containers:
+        - name: agent
+          resources:
+            requests:
+              cpu: 0.1
+              memory: 100M
+`,
+			"templates/service.yaml": "apiVersion: v1\nkind: Service\nmetadata:\n  name: test\n",
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, &ResourcesEstimation{
+			Workloads: 2,
+			Requests: &ResourcesAmounts{
+				CPUMillicores: 3*1000 + 100,
+				MemoryBytes:   3*160*1024*1024 + 100*1000*1000,
+			},
+			Limits: &ResourcesAmounts{
+				CPUMillicores: 3 * 500,
+				MemoryBytes:   3 * 256 * 1024 * 1024,
+			},
+		}, e)
+	})
+}
+
+func TestParseQuantity(t *testing.T) {
+	t.Run("invalid quantity", func(t *testing.T) {
+		testCases := []string{
+			"",
+			"invalid",
+			"1Xi",
+			"-1",
+			"Inf",
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc, func(t *testing.T) {
+				_, err := parseQuantity(tc)
+				assert.True(t, errors.Is(err, errInvalidQuantity))
+			})
+		}
+	})
+
+	t.Run("valid quantity", func(t *testing.T) {
+		testCases := []struct {
+			q             string
+			expectedValue float64
+		}{
+			{"1", 1},
+			{"1.5", 1.5},
+			{"100m", 0.1},
+			{"1e3", 1000},
+			{"2k", 2000},
+			{"128Mi", 128 * 1024 * 1024},
+			{"1Gi", 1024 * 1024 * 1024},
+			{"1G", 1e9},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.q, func(t *testing.T) {
+				v, err := parseQuantity(tc.q)
+				require.NoError(t, err)
+				assert.InDelta(t, tc.expectedValue, v, 1e-9)
+			})
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package helm
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/spf13/viper"
+)
+
+const (
+	// defaultRetryBaseDelay represents the delay used before retrying a chart
+	// archive download for the first time when none is provided. It doubles
+	// in each retry.
+	defaultRetryBaseDelay = 1 * time.Second
+
+	// maxRetryDelay represents the maximum delay before retrying a chart
+	// archive download, including the ones requested using the Retry-After
+	// header.
+	maxRetryDelay = 30 * time.Second
+)
+
+// getDownloadRetries returns the number of times a chart archive download
+// that failed with a transient error should be retried for the repository
+// provided. The number of retries configured for a specific repository takes
+// precedence over the global one. Retries are disabled by default.
+func getDownloadRetries(cfg *viper.Viper, r *hub.Repository) int {
+	if cfg == nil {
+		return 0
+	}
+	retries := cfg.GetStringMapString("tracker.repositoriesDownloadRetries")
+	if n, err := strconv.Atoi(retries[strings.ToLower(r.Name)]); err == nil && n >= 0 {
+		return n
+	}
+	if n := cfg.GetInt("tracker.downloadRetries"); n > 0 {
+		return n
+	}
+	return 0
+}
+
+// isRetryableStatus checks if the status code provided corresponds to a
+// transient error, so the request can be retried.
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// retryDelay returns the delay before retrying a request that failed in the
+// attempt provided (starting at 0). The delay grows exponentially from the
+// base delay given, with some jitter to avoid retrying in lockstep with the
+// rest of the workers. When the failed response requests a specific delay
+// using the Retry-After header, it is honored instead.
+func retryDelay(base time.Duration, attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			if d > maxRetryDelay {
+				return maxRetryDelay
+			}
+			return d
+		}
+	}
+	d := base << uint(attempt)
+	if d <= 0 || d > maxRetryDelay {
+		d = maxRetryDelay
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which can be a
+// number of seconds or an http date, returning the delay requested.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
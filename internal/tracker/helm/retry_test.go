@@ -0,0 +1,90 @@
+package helm
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDownloadRetries(t *testing.T) {
+	r := &hub.Repository{Name: "Repo1"}
+
+	t.Run("retries disabled by default", func(t *testing.T) {
+		assert.Equal(t, 0, getDownloadRetries(nil, r))
+		assert.Equal(t, 0, getDownloadRetries(viper.New(), r))
+	})
+
+	t.Run("global retries", func(t *testing.T) {
+		cfg := viper.New()
+		cfg.Set("tracker.downloadRetries", 3)
+		cfg.Set("tracker.repositoriesDownloadRetries", map[string]string{"repo2": "5"})
+		assert.Equal(t, 3, getDownloadRetries(cfg, r))
+	})
+
+	t.Run("repository retries take precedence", func(t *testing.T) {
+		cfg := viper.New()
+		cfg.Set("tracker.downloadRetries", 3)
+		cfg.Set("tracker.repositoriesDownloadRetries", map[string]string{"repo1": "0"})
+		assert.Equal(t, 0, getDownloadRetries(cfg, r))
+	})
+}
+
+func TestIsRetryableStatus(t *testing.T) {
+	assert.True(t, isRetryableStatus(http.StatusTooManyRequests))
+	assert.True(t, isRetryableStatus(http.StatusInternalServerError))
+	assert.True(t, isRetryableStatus(http.StatusServiceUnavailable))
+	assert.False(t, isRetryableStatus(http.StatusOK))
+	assert.False(t, isRetryableStatus(http.StatusNotFound))
+}
+
+func TestRetryDelay(t *testing.T) {
+	t.Run("exponential backoff with jitter", func(t *testing.T) {
+		for attempt, max := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+			d := retryDelay(time.Second, attempt, nil)
+			assert.True(t, d >= max/2 && d <= max, "attempt %d delay %s", attempt, d)
+		}
+	})
+
+	t.Run("delay capped", func(t *testing.T) {
+		d := retryDelay(time.Second, 20, nil)
+		assert.True(t, d >= maxRetryDelay/2 && d <= maxRetryDelay)
+		d = retryDelay(time.Second, 100, nil)
+		assert.True(t, d >= maxRetryDelay/2 && d <= maxRetryDelay)
+	})
+
+	t.Run("retry after header honored", func(t *testing.T) {
+		resp := &http.Response{Header: http.Header{"Retry-After": []string{"5"}}}
+		assert.Equal(t, 5*time.Second, retryDelay(time.Second, 0, resp))
+		resp = &http.Response{Header: http.Header{"Retry-After": []string{"3600"}}}
+		assert.Equal(t, maxRetryDelay, retryDelay(time.Second, 0, resp))
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, 6, 16, 10, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		v             string
+		expectedDelay time.Duration
+		expectedOK    bool
+	}{
+		{"", 0, false},
+		{"invalid", 0, false},
+		{"-1", 0, false},
+		{"0", 0, true},
+		{"120", 2 * time.Minute, true},
+		{"Tue, 16 Jun 2020 10:00:10 GMT", 10 * time.Second, true},
+		{"Tue, 16 Jun 2020 09:00:00 GMT", 0, true},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.v, func(t *testing.T) {
+			d, ok := parseRetryAfter(tc.v, now)
+			assert.Equal(t, tc.expectedDelay, d)
+			assert.Equal(t, tc.expectedOK, ok)
+		})
+	}
+}
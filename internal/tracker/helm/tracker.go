@@ -1,22 +1,40 @@
 package helm
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/oci"
 	"github.com/artifacthub/hub/internal/repo"
 	"github.com/artifacthub/hub/internal/tracker"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/rs/zerolog"
-	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
 	"helm.sh/helm/v3/pkg/chart"
 	helmrepo "helm.sh/helm/v3/pkg/repo"
 )
 
-// defaultNumWorkers is the number of workers used when none is provided.
-const defaultNumWorkers = 25
+const (
+	// defaultNumWorkers is the number of workers used when none is provided.
+	defaultNumWorkers = 25
+
+	// defaultMaxUnregisterRatio is the maximum ratio of the registered
+	// packages versions that can be unregistered in a single tracker run
+	// when none is provided.
+	defaultMaxUnregisterRatio = 0.5
+
+	// unregisterSafetyMinVersions is the minimum number of packages versions
+	// to unregister from which the maximum unregister ratio is enforced, so
+	// that small repositories can remove most of their versions.
+	unregisterSafetyMinVersions = 10
+)
 
 // Tracker is in charge of tracking the packages available in a Helm repository,
 // registering and unregistering them as needed.
@@ -26,6 +44,7 @@ type Tracker struct {
 	logger     zerolog.Logger
 	queue      chan *Job
 	numWorkers int
+	ec         *errorsCounter
 }
 
 // NewTracker creates a new Tracker instance.
@@ -34,17 +53,23 @@ func NewTracker(
 	r *hub.Repository,
 	opts ...func(t tracker.Tracker),
 ) tracker.Tracker {
+	// The errors found while tracking the repository are counted, so that
+	// the index file validators are only stored when no errors were found
+	trackerSvc := *svc
+	ec := &errorsCounter{ErrorsCollector: svc.Ec}
+	trackerSvc.Ec = ec
 	t := &Tracker{
-		svc:    svc,
+		svc:    &trackerSvc,
 		r:      r,
-		logger: log.With().Str("repo", r.Name).Str("kind", hub.GetKindName(r.Kind)).Logger(),
+		logger: util.LogWith("tracker").Str("repo", r.Name).Str("kind", hub.GetKindName(r.Kind)).Logger(),
 		queue:  make(chan *Job),
+		ec:     ec,
 	}
 	for _, o := range opts {
 		o(t)
 	}
 	if t.numWorkers == 0 {
-		t.numWorkers = defaultNumWorkers
+		t.numWorkers = getNumWorkers(t.svc.Cfg, r)
 	}
 	if t.svc.Il == nil {
 		t.svc.Il = &repo.HelmIndexLoader{}
@@ -60,6 +85,45 @@ func WithNumWorkers(n int) func(t tracker.Tracker) {
 	}
 }
 
+// getNumWorkers returns the number of workers that should be used to track
+// the repository provided. The number of workers configured for a specific
+// repository takes precedence over the global one.
+func getNumWorkers(cfg *viper.Viper, r *hub.Repository) int {
+	if cfg == nil {
+		return defaultNumWorkers
+	}
+	numWorkers := cfg.GetStringMapString("tracker.repositoriesHelmWorkers")
+	if n, err := strconv.Atoi(numWorkers[strings.ToLower(r.Name)]); err == nil && n > 0 {
+		return n
+	}
+	if n := cfg.GetInt("tracker.helmWorkers"); n > 0 {
+		return n
+	}
+	return defaultNumWorkers
+}
+
+// getMaxUnregisterRatio returns the maximum ratio of the registered packages
+// versions that can be unregistered in a single tracker run.
+func getMaxUnregisterRatio(cfg *viper.Viper) float64 {
+	if cfg == nil {
+		return defaultMaxUnregisterRatio
+	}
+	if ratio := cfg.GetFloat64("tracker.helmMaxUnregisterRatio"); ratio > 0 {
+		return ratio
+	}
+	return defaultMaxUnregisterRatio
+}
+
+// exceedsUnregisterThreshold checks if unregistering the number of packages
+// versions provided out of the ones registered exceeds the maximum ratio
+// allowed.
+func exceedsUnregisterThreshold(toUnregister, registered int, maxRatio float64) bool {
+	if toUnregister < unregisterSafetyMinVersions || registered == 0 {
+		return false
+	}
+	return float64(toUnregister)/float64(registered) > maxRatio
+}
+
 // WithIndexLoader allows providing a specific Helm repository index loader for
 // a Tracker instance.
 func WithIndexLoader(il hub.HelmIndexLoader) func(t tracker.Tracker) {
@@ -74,20 +138,51 @@ func WithIndexLoader(il hub.HelmIndexLoader) func(t tracker.Tracker) {
 func (t *Tracker) Track(wg *sync.WaitGroup) error {
 	defer wg.Done()
 
-	// Launch workers
+	// Launch workers. Once all jobs have been processed, the validators of
+	// the index file are stored if the tracking completed without errors, so
+	// that the repository can be skipped next time if the index file has not
+	// been modified.
 	var workersWg sync.WaitGroup
-	defer workersWg.Wait()
-	defer close(t.queue)
+	var indexValidators *hub.HelmIndexValidators
+	var completed bool
+	defer func() {
+		close(t.queue)
+		workersWg.Wait()
+		if completed && indexValidators != nil && t.ec.count() == 0 {
+			err := t.svc.Rm.SetHelmIndexValidators(t.svc.Ctx, t.r.RepositoryID, indexValidators)
+			if err != nil {
+				t.logger.Error().Err(err).Msg("error setting repository index file validators")
+			}
+		}
+	}()
+	hc, err := repo.NewHTTPClient(t.r, 0)
+	if err != nil {
+		return fmt.Errorf("error setting up repository http client: %w", err)
+	}
 	for i := 0; i < t.numWorkers; i++ {
-		w := NewWorker(t.svc, t.r)
+		w := NewWorker(t.svc, t.r, WithHTTPClient(hc))
 		workersWg.Add(1)
 		go w.Run(&workersWg, t.queue)
 	}
 
-	// Load repository index file
+	// Load repository index file, unless it has not been modified since the
+	// last time it was downloaded (conditional requests are not used when the
+	// digest check is bypassed, as all packages must be processed again)
+	bypassDigestCheck := t.svc.Cfg.GetBool("tracker.bypassDigestCheck")
+	var prevIndexValidators *hub.HelmIndexValidators
+	if !bypassDigestCheck {
+		prevIndexValidators, err = t.svc.Rm.GetHelmIndexValidators(t.svc.Ctx, t.r.RepositoryID)
+		if err != nil {
+			return fmt.Errorf("error getting repository index file validators: %w", err)
+		}
+	}
 	t.logger.Debug().Msg("loading repository index file")
-	indexFile, err := t.svc.Il.LoadIndex(t.r)
+	indexFile, indexValidators, err := t.svc.Il.LoadIndexIfModified(t.r, prevIndexValidators)
 	if err != nil {
+		if errors.Is(err, repo.ErrIndexNotModified) {
+			t.logger.Debug().Msg("repository index file not modified, skipping")
+			return nil
+		}
 		return fmt.Errorf("error loading repository index file: %w", err)
 	}
 
@@ -97,29 +192,53 @@ func (t *Tracker) Track(wg *sync.WaitGroup) error {
 		return fmt.Errorf("error getting registered packages digest: %w", err)
 	}
 
-	// Generate jobs to register available packages when needed
-	bypassDigestCheck := t.svc.Cfg.GetBool("tracker.bypassDigestCheck")
+	// Generate jobs to register available packages when needed. Duplicated
+	// jobs (same package version and digest) are only dispatched once.
 	packagesAvailable := make(map[string]struct{})
+	dd := newJobsDeduplicator()
 	for _, charts := range indexFile.Entries {
-		for i, chartVersion := range charts {
+		logoStored := false
+		for _, chartVersion := range charts {
 			md := chartVersion.Metadata
 			select {
 			case <-t.svc.Ctx.Done():
 				return nil
 			default:
 			}
-			var storeLogo bool
-			if i == 0 {
-				storeLogo = true
-			}
 			sv, err := semver.NewVersion(md.Version)
 			if err != nil {
-				t.warn(fmt.Errorf("invalid package %s version (%s): %w", md.Name, md.Version, err))
+				t.warn(tracker.NewError(
+					tracker.ErrCodeInvalidVersion,
+					fmt.Errorf("invalid package %s version (%s): %w", md.Name, md.Version, err),
+				))
+				continue
+			}
+			if skipChartVersion(t.r, md, sv) {
+				t.logger.Debug().Str("name", md.Name).Str("version", md.Version).Msg("chart version skipped")
 				continue
 			}
+			var storeLogo bool
+			if !logoStored {
+				storeLogo = true
+				logoStored = true
+			}
 			key := fmt.Sprintf("%s@%s", md.Name, sv.String())
 			packagesAvailable[key] = struct{}{}
+			if err := checkChartVersionURLs(chartVersion, t.r.URL); err != nil {
+				t.warn(err)
+				continue
+			}
 			if bypassDigestCheck || chartVersion.Digest != packagesRegistered[key] {
+				k := jobKey{
+					repositoryID: t.r.RepositoryID,
+					name:         md.Name,
+					version:      sv.String(),
+					digest:       chartVersion.Digest,
+				}
+				if dd.isDuplicate(k) {
+					t.logger.Debug().Str("name", md.Name).Str("version", md.Version).Msg("duplicated register job skipped")
+					continue
+				}
 				t.queue <- &Job{
 					Kind:         Register,
 					ChartVersion: chartVersion,
@@ -129,32 +248,67 @@ func (t *Tracker) Track(wg *sync.WaitGroup) error {
 		}
 	}
 
-	// Generate jobs to unregister packages not available anymore
+	// Generate jobs to unregister packages not available anymore. When too
+	// many of the versions registered have disappeared from the index file,
+	// it's likely that it's temporarily broken, so nothing is unregistered.
+	// The error reported prevents the index file validators from being
+	// stored, so the repository will be checked again in the next run.
+	var packagesToUnregister []string
 	for key := range packagesRegistered {
+		if _, ok := packagesAvailable[key]; !ok {
+			packagesToUnregister = append(packagesToUnregister, key)
+		}
+	}
+	maxUnregisterRatio := getMaxUnregisterRatio(t.svc.Cfg)
+	if exceedsUnregisterThreshold(len(packagesToUnregister), len(packagesRegistered), maxUnregisterRatio) {
+		t.warn(tracker.NewError(
+			tracker.ErrCodeUnregisterThresholdExceeded,
+			fmt.Errorf(
+				"%d of %d packages versions registered are not available in the index file anymore (max ratio %.2f), skipping unregistration",
+				len(packagesToUnregister), len(packagesRegistered), maxUnregisterRatio,
+			),
+		))
+		packagesToUnregister = nil
+	}
+	for _, key := range packagesToUnregister {
 		select {
 		case <-t.svc.Ctx.Done():
 			return nil
 		default:
 		}
-		if _, ok := packagesAvailable[key]; !ok {
-			p := strings.Split(key, "@")
-			name := p[0]
-			version := p[1]
-			t.queue <- &Job{
-				Kind: Unregister,
-				ChartVersion: &helmrepo.ChartVersion{
-					Metadata: &chart.Metadata{
-						Name:    name,
-						Version: version,
-					},
+		p := strings.Split(key, "@")
+		name := p[0]
+		version := p[1]
+		t.queue <- &Job{
+			Kind: Unregister,
+			ChartVersion: &helmrepo.ChartVersion{
+				Metadata: &chart.Metadata{
+					Name:    name,
+					Version: version,
 				},
-			}
+			},
 		}
 	}
 
+	completed = true
 	return nil
 }
 
+// skipChartVersion checks if the chart version provided must be skipped
+// according to the repository settings. Pre-release and deprecated versions
+// are registered by default (the latter flagged as deprecated), but the
+// repository can be configured to skip them. Versions skipped that were
+// registered previously are unregistered.
+func skipChartVersion(r *hub.Repository, md *chart.Metadata, sv *semver.Version) bool {
+	if r.SkipPrereleases && sv.Prerelease() != "" {
+		return true
+	}
+	if r.SkipDeprecated && md.Deprecated {
+		return true
+	}
+	return false
+}
+
 // warn is a helper that sends the error provided to the errors collector and
 // logs it as a warning.
 func (t *Tracker) warn(err error) {
@@ -162,6 +316,77 @@ func (t *Tracker) warn(err error) {
 	t.logger.Warn().Err(err).Send()
 }
 
+// errorsCounter is an ErrorsCollector wrapper that counts the errors appended
+// to it before passing them to the underlying errors collector.
+type errorsCounter struct {
+	n int64
+	tracker.ErrorsCollector
+}
+
+// Append implements the ErrorsCollector interface.
+func (c *errorsCounter) Append(repositoryID string, err error) {
+	atomic.AddInt64(&c.n, 1)
+	c.ErrorsCollector.Append(repositoryID, err)
+}
+
+// count returns the number of errors appended so far.
+func (c *errorsCounter) count() int64 {
+	return atomic.LoadInt64(&c.n)
+}
+
+// checkChartVersionURLs checks that the chart version provided has at least
+// one valid url to download it from. When none of them is valid, the error
+// corresponding to the first url is returned.
+func checkChartVersionURLs(cv *helmrepo.ChartVersion, repoURL string) error {
+	if len(cv.URLs) == 0 {
+		return tracker.NewError(
+			tracker.ErrCodeMissingURL,
+			fmt.Errorf("package %s version %s has no urls", cv.Name, cv.Version),
+		)
+	}
+	var firstErr error
+	for _, rawURL := range cv.URLs {
+		err := checkChartVersionURL(cv, rawURL, repoURL)
+		if err == nil {
+			return nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// checkChartVersionURL checks that the chart version url provided is valid.
+// Relative urls are accepted, as they will be resolved against the repository
+// url. Absolute file urls are only accepted for the repositories located in
+// the local file system.
+func checkChartVersionURL(cv *helmrepo.ChartVersion, rawURL, repoURL string) error {
+	if rawURL == "" {
+		return tracker.NewError(
+			tracker.ErrCodeMissingURL,
+			fmt.Errorf("package %s version %s has no urls", cv.Name, cv.Version),
+		)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return tracker.NewError(
+			tracker.ErrCodeInvalidURL,
+			fmt.Errorf("package %s version %s has an invalid url: %w", cv.Name, cv.Version, err),
+		)
+	}
+	if u.IsAbs() && u.Scheme == repo.FileScheme && repo.IsLocal(repoURL) {
+		return nil
+	}
+	if u.IsAbs() && u.Scheme != "http" && u.Scheme != "https" && u.Scheme != oci.Scheme {
+		return tracker.NewError(
+			tracker.ErrCodeUnsupportedURLScheme,
+			fmt.Errorf("package %s version %s url scheme not supported: %s", cv.Name, cv.Version, u.Scheme),
+		)
+	}
+	return nil
+}
+
 // JobKind represents the kind of a job, which can be register or unregister.
 type JobKind int
 
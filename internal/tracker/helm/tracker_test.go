@@ -14,11 +14,16 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"helm.sh/helm/v3/pkg/chart"
 	helmrepo "helm.sh/helm/v3/pkg/repo"
 )
 
-var errFake = errors.New("fake error for tests")
+var (
+	errFake            = errors.New("fake error for tests")
+	indexValidators    = &hub.HelmIndexValidators{ETag: `"etag1"`}
+	newIndexValidators = &hub.HelmIndexValidators{ETag: `"etag2"`}
+)
 
 func TestMain(m *testing.M) {
 	zerolog.SetGlobalLevel(zerolog.Disabled)
@@ -26,11 +31,50 @@ func TestMain(m *testing.M) {
 }
 
 func TestTracker(t *testing.T) {
+	t.Run("error getting repository index file validators", func(t *testing.T) {
+		// Setup tracker and expectations
+		r := &hub.Repository{RepositoryID: "repo1"}
+		tw := newTrackerWrapper(r)
+		tw.rm.On("GetHelmIndexValidators", tw.ctx, r.RepositoryID).Return(nil, errFake)
+
+		// Run tracker and check expectations
+		err := tw.t.Track(tw.wg)
+		assert.Error(t, err)
+		tw.assertExpectations(t, nil)
+	})
+
 	t.Run("error loading repository index file", func(t *testing.T) {
 		// Setup tracker and expectations
 		r := &hub.Repository{RepositoryID: "repo1"}
 		tw := newTrackerWrapper(r)
-		tw.il.On("LoadIndex", r).Return(nil, errFake)
+		tw.rm.On("GetHelmIndexValidators", tw.ctx, r.RepositoryID).Return(indexValidators, nil)
+		tw.il.On("LoadIndexIfModified", r, indexValidators).Return(nil, nil, errFake)
+
+		// Run tracker and check expectations
+		err := tw.t.Track(tw.wg)
+		assert.Error(t, err)
+		tw.assertExpectations(t, nil)
+	})
+
+	t.Run("repository index file not modified", func(t *testing.T) {
+		// Setup tracker and expectations
+		r := &hub.Repository{RepositoryID: "repo1"}
+		tw := newTrackerWrapper(r)
+		tw.rm.On("GetHelmIndexValidators", tw.ctx, r.RepositoryID).Return(indexValidators, nil)
+		tw.il.On("LoadIndexIfModified", r, indexValidators).Return(nil, nil, repo.ErrIndexNotModified)
+
+		// Run tracker and check expectations
+		err := tw.t.Track(tw.wg)
+		assert.NoError(t, err)
+		tw.assertExpectations(t, nil)
+	})
+
+	t.Run("digest check bypassed, conditional request not used", func(t *testing.T) {
+		// Setup tracker and expectations
+		r := &hub.Repository{RepositoryID: "repo1"}
+		tw := newTrackerWrapper(r)
+		tw.cfg.Set("tracker.bypassDigestCheck", true)
+		tw.il.On("LoadIndexIfModified", r, (*hub.HelmIndexValidators)(nil)).Return(nil, nil, errFake)
 
 		// Run tracker and check expectations
 		err := tw.t.Track(tw.wg)
@@ -42,7 +86,8 @@ func TestTracker(t *testing.T) {
 		// Setup tracker and expectations
 		r := &hub.Repository{RepositoryID: "repo1"}
 		tw := newTrackerWrapper(r)
-		tw.il.On("LoadIndex", r).Return(nil, nil)
+		tw.rm.On("GetHelmIndexValidators", tw.ctx, r.RepositoryID).Return(indexValidators, nil)
+		tw.il.On("LoadIndexIfModified", r, indexValidators).Return(nil, newIndexValidators, nil)
 		tw.rm.On("GetPackagesDigest", tw.ctx, r.RepositoryID).Return(nil, errFake)
 
 		// Run tracker and check expectations
@@ -51,6 +96,210 @@ func TestTracker(t *testing.T) {
 		tw.assertExpectations(t, nil)
 	})
 
+	t.Run("chart versions with invalid urls are skipped", func(t *testing.T) {
+		testCases := []struct {
+			urls []string
+		}{
+			{nil},
+			{[]string{""}},
+			{[]string{"ftp://repo1.com/pkg1-1.0.0.tgz"}},
+			{[]string{"https://repo1.com/%zz"}},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(fmt.Sprintf("%v", tc.urls), func(t *testing.T) {
+				// Setup tracker and expectations
+				r := &hub.Repository{RepositoryID: "repo1"}
+				tw := newTrackerWrapper(r)
+				tw.rm.On("GetHelmIndexValidators", tw.ctx, r.RepositoryID).Return(indexValidators, nil)
+				tw.il.On("LoadIndexIfModified", r, indexValidators).Return(&helmrepo.IndexFile{
+					Entries: map[string]helmrepo.ChartVersions{
+						"pkg1": []*helmrepo.ChartVersion{
+							{
+								Metadata: &chart.Metadata{
+									Name:    "pkg1",
+									Version: "1.0.0",
+								},
+								URLs: tc.urls,
+							},
+						},
+					},
+				}, newIndexValidators, nil)
+				tw.rm.On("GetPackagesDigest", tw.ctx, r.RepositoryID).Return(nil, nil)
+				tw.ec.On("Append", r.RepositoryID, mock.Anything).Return()
+
+				// Run tracker and check expectations
+				err := tw.t.Track(tw.wg)
+				assert.NoError(t, err)
+				tw.assertExpectations(t, nil)
+			})
+		}
+	})
+
+	t.Run("unregister threshold exceeded, nothing unregistered", func(t *testing.T) {
+		// Setup tracker and expectations
+		r := &hub.Repository{RepositoryID: "repo1"}
+		tw := newTrackerWrapper(r)
+		tw.rm.On("GetHelmIndexValidators", tw.ctx, r.RepositoryID).Return(indexValidators, nil)
+		tw.il.On("LoadIndexIfModified", r, indexValidators).Return(&helmrepo.IndexFile{
+			Entries: map[string]helmrepo.ChartVersions{
+				"pkg1": []*helmrepo.ChartVersion{
+					{
+						Metadata: &chart.Metadata{
+							Name:    "pkg1",
+							Version: "1.0.0",
+						},
+						Digest: "pkg1-1.0.0",
+						URLs:   []string{"https://repo1.com/pkg1-1.0.0.tgz"},
+					},
+				},
+			},
+		}, newIndexValidators, nil)
+		packagesDigest := map[string]string{
+			"pkg1@1.0.0": "pkg1-1.0.0",
+		}
+		for i := 0; i < unregisterSafetyMinVersions; i++ {
+			packagesDigest[fmt.Sprintf("pkg2@%d.0.0", i)] = fmt.Sprintf("pkg2-%d.0.0", i)
+		}
+		tw.rm.On("GetPackagesDigest", tw.ctx, r.RepositoryID).Return(packagesDigest, nil)
+		tw.ec.On("Append", r.RepositoryID, mock.MatchedBy(func(err error) bool {
+			var e *tracker.Error
+			return errors.As(err, &e) && e.Code == tracker.ErrCodeUnregisterThresholdExceeded
+		})).Return()
+
+		// Run tracker and check expectations
+		err := tw.t.Track(tw.wg)
+		assert.NoError(t, err)
+		tw.assertExpectations(t, nil)
+	})
+
+	t.Run("duplicated chart versions are only registered once", func(t *testing.T) {
+		// Setup tracker and expectations
+		r := &hub.Repository{RepositoryID: "repo1"}
+		pkg1V1 := &helmrepo.ChartVersion{
+			Metadata: &chart.Metadata{
+				Name:    "pkg1",
+				Version: "1.0.0",
+			},
+			Digest: "pkg1-1.0.0",
+			URLs:   []string{"https://repo1.com/pkg1-1.0.0.tgz"},
+		}
+		pkg1V1Prefixed := &helmrepo.ChartVersion{
+			Metadata: &chart.Metadata{
+				Name:    "pkg1",
+				Version: "v1.0.0",
+			},
+			Digest: "pkg1-1.0.0",
+			URLs:   []string{"https://repo1.com/pkg1-1.0.0.tgz"},
+		}
+		tw := newTrackerWrapper(r)
+		tw.rm.On("GetHelmIndexValidators", tw.ctx, r.RepositoryID).Return(indexValidators, nil)
+		tw.il.On("LoadIndexIfModified", r, indexValidators).Return(&helmrepo.IndexFile{
+			Entries: map[string]helmrepo.ChartVersions{
+				"pkg1": []*helmrepo.ChartVersion{pkg1V1, pkg1V1, pkg1V1Prefixed},
+			},
+		}, newIndexValidators, nil)
+		tw.rm.On("GetPackagesDigest", tw.ctx, r.RepositoryID).Return(nil, nil)
+		tw.rm.On("SetHelmIndexValidators", tw.ctx, r.RepositoryID, newIndexValidators).Return(nil)
+
+		// Run tracker and check expectations
+		err := tw.t.Track(tw.wg)
+		assert.NoError(t, err)
+		tw.assertExpectations(t, []*Job{
+			{
+				Kind:         Register,
+				ChartVersion: pkg1V1,
+				StoreLogo:    true,
+			},
+		})
+	})
+
+	t.Run("prerelease and deprecated versions are skipped when configured", func(t *testing.T) {
+		// Setup tracker and expectations
+		r := &hub.Repository{
+			RepositoryID:    "repo1",
+			SkipPrereleases: true,
+			SkipDeprecated:  true,
+		}
+		pkg1V2Beta := &helmrepo.ChartVersion{
+			Metadata: &chart.Metadata{
+				Name:    "pkg1",
+				Version: "2.0.0-beta.1",
+			},
+			Digest: "pkg1-2.0.0-beta.1",
+			URLs:   []string{"https://repo1.com/pkg1-2.0.0-beta.1.tgz"},
+		}
+		pkg1V1 := &helmrepo.ChartVersion{
+			Metadata: &chart.Metadata{
+				Name:    "pkg1",
+				Version: "1.0.0",
+			},
+			Digest: "pkg1-1.0.0",
+			URLs:   []string{"https://repo1.com/pkg1-1.0.0.tgz"},
+		}
+		pkg2V1 := &helmrepo.ChartVersion{
+			Metadata: &chart.Metadata{
+				Name:       "pkg2",
+				Version:    "1.0.0",
+				Deprecated: true,
+			},
+			Digest: "pkg2-1.0.0",
+			URLs:   []string{"https://repo1.com/pkg2-1.0.0.tgz"},
+		}
+		tw := newTrackerWrapper(r)
+		tw.rm.On("GetHelmIndexValidators", tw.ctx, r.RepositoryID).Return(indexValidators, nil)
+		tw.il.On("LoadIndexIfModified", r, indexValidators).Return(&helmrepo.IndexFile{
+			Entries: map[string]helmrepo.ChartVersions{
+				"pkg1": []*helmrepo.ChartVersion{pkg1V2Beta, pkg1V1},
+				"pkg2": []*helmrepo.ChartVersion{pkg2V1},
+			},
+		}, newIndexValidators, nil)
+		tw.rm.On("GetPackagesDigest", tw.ctx, r.RepositoryID).Return(map[string]string{
+			"pkg1@2.0.0-beta.1": "pkg1-2.0.0-beta.1",
+			"pkg2@1.0.0":        "pkg2-1.0.0",
+			"pkg3@1.0.0":        "pkg3-1.0.0",
+		}, nil)
+		tw.rm.On("SetHelmIndexValidators", tw.ctx, r.RepositoryID, newIndexValidators).Return(nil)
+
+		// Run tracker and check expectations
+		err := tw.t.Track(tw.wg)
+		assert.NoError(t, err)
+		tw.assertExpectations(t, []*Job{
+			{
+				Kind:         Register,
+				ChartVersion: pkg1V1,
+				StoreLogo:    true,
+			},
+			{
+				Kind: Unregister,
+				ChartVersion: &helmrepo.ChartVersion{
+					Metadata: &chart.Metadata{
+						Name:    "pkg1",
+						Version: "2.0.0-beta.1",
+					},
+				},
+			},
+			{
+				Kind: Unregister,
+				ChartVersion: &helmrepo.ChartVersion{
+					Metadata: &chart.Metadata{
+						Name:    "pkg2",
+						Version: "1.0.0",
+					},
+				},
+			},
+			{
+				Kind: Unregister,
+				ChartVersion: &helmrepo.ChartVersion{
+					Metadata: &chart.Metadata{
+						Name:    "pkg3",
+						Version: "1.0.0",
+					},
+				},
+			},
+		})
+	})
+
 	t.Run("tracker completed successfully", func(t *testing.T) {
 		repo1 := &hub.Repository{
 			RepositoryID: "repo1",
@@ -61,6 +310,7 @@ func TestTracker(t *testing.T) {
 				Version: "1.0.0",
 			},
 			Digest: "pkg1-1.0.0",
+			URLs:   []string{"https://repo1.com/pkg1-1.0.0.tgz"},
 		}
 		pkg1V2 := &helmrepo.ChartVersion{
 			Metadata: &chart.Metadata{
@@ -68,6 +318,7 @@ func TestTracker(t *testing.T) {
 				Version: "2.0.0",
 			},
 			Digest: "pkg1-2.0.0",
+			URLs:   []string{"https://repo1.com/pkg1-2.0.0.tgz"},
 		}
 		pkg2V1 := &helmrepo.ChartVersion{
 			Metadata: &chart.Metadata{
@@ -75,6 +326,7 @@ func TestTracker(t *testing.T) {
 				Version: "1.0.0",
 			},
 			Digest: "pkg2-1.0.0",
+			URLs:   []string{"https://repo1.com/pkg2-1.0.0.tgz"},
 		}
 
 		testCases := []struct {
@@ -301,9 +553,12 @@ func TestTracker(t *testing.T) {
 			t.Run(fmt.Sprintf("Test case %d", tc.n), func(t *testing.T) {
 				// Setup tracker and expectations
 				tw := newTrackerWrapper(tc.r)
-				tw.il.On("LoadIndex", tc.r).Return(tc.indexFile[tc.r.RepositoryID], nil)
+				tw.rm.On("GetHelmIndexValidators", tw.ctx, tc.r.RepositoryID).Return(indexValidators, nil)
+				tw.il.On("LoadIndexIfModified", tc.r, indexValidators).
+					Return(tc.indexFile[tc.r.RepositoryID], newIndexValidators, nil)
 				tw.rm.On("GetPackagesDigest", tw.ctx, tc.r.RepositoryID).
 					Return(tc.packagesDigest[tc.r.RepositoryID], nil)
+				tw.rm.On("SetHelmIndexValidators", tw.ctx, tc.r.RepositoryID, newIndexValidators).Return(nil)
 
 				// Run tracker and check expectations
 				err := tw.t.Track(tw.wg)
@@ -379,3 +634,75 @@ func (tw *trackerWrapper) assertExpectations(t *testing.T, expectedJobs []*Job)
 		assert.ElementsMatch(t, *tw.queuedJobs, expectedJobs)
 	}
 }
+
+func TestGetMaxUnregisterRatio(t *testing.T) {
+	cfg := viper.New()
+	cfg.Set("tracker.helmMaxUnregisterRatio", 0.8)
+	invalidCfg := viper.New()
+	invalidCfg.Set("tracker.helmMaxUnregisterRatio", -1)
+
+	testCases := []struct {
+		cfg           *viper.Viper
+		expectedRatio float64
+	}{
+		{nil, defaultMaxUnregisterRatio},
+		{viper.New(), defaultMaxUnregisterRatio},
+		{invalidCfg, defaultMaxUnregisterRatio},
+		{cfg, 0.8},
+	}
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			assert.Equal(t, tc.expectedRatio, getMaxUnregisterRatio(tc.cfg))
+		})
+	}
+}
+
+func TestExceedsUnregisterThreshold(t *testing.T) {
+	testCases := []struct {
+		toUnregister int
+		registered   int
+		maxRatio     float64
+		expected     bool
+	}{
+		{0, 0, 0.5, false},
+		{5, 5, 0.5, false},
+		{10, 20, 0.5, false},
+		{11, 20, 0.5, true},
+		{20, 20, 0.5, true},
+		{20, 20, 1, false},
+	}
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			assert.Equal(t, tc.expected, exceedsUnregisterThreshold(tc.toUnregister, tc.registered, tc.maxRatio))
+		})
+	}
+}
+
+func TestGetNumWorkers(t *testing.T) {
+	cfg := viper.New()
+	cfg.Set("tracker.helmWorkers", 50)
+	cfg.Set("tracker.repositoriesHelmWorkers", map[string]string{
+		"repo2": "100",
+		"repo3": "invalid",
+	})
+
+	testCases := []struct {
+		cfg                *viper.Viper
+		r                  *hub.Repository
+		expectedNumWorkers int
+	}{
+		{nil, &hub.Repository{Name: "repo1"}, defaultNumWorkers},
+		{viper.New(), &hub.Repository{Name: "repo1"}, defaultNumWorkers},
+		{cfg, &hub.Repository{Name: "repo1"}, 50},
+		{cfg, &hub.Repository{Name: "repo2"}, 100},
+		{cfg, &hub.Repository{Name: "repo3"}, 50},
+	}
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			assert.Equal(t, tc.expectedNumWorkers, getNumWorkers(tc.cfg, tc.r))
+		})
+	}
+}
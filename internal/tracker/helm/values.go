@@ -0,0 +1,49 @@
+package helm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// maxValuesFileSize represents the maximum size of the values file or the
+// values schema file that will be stored for a chart version.
+const maxValuesFileSize = 512 * 1024
+
+// errValuesFileTooBig indicates that the values file, the values schema file
+// or the notes exceed the maximum size allowed.
+var errValuesFileTooBig = errors.New("file exceeds maximum size allowed")
+
+// getDefaultValues returns the raw content of the values file of the chart
+// provided, preserving the comments that usually document each value.
+func getDefaultValues(chrt *chart.Chart) (string, error) {
+	for _, file := range chrt.Raw {
+		if file.Name != chartutil.ValuesfileName {
+			continue
+		}
+		if len(file.Data) > maxValuesFileSize {
+			return "", fmt.Errorf("%s: %w", chartutil.ValuesfileName, errValuesFileTooBig)
+		}
+		return string(file.Data), nil
+	}
+	return "", nil
+}
+
+// getValuesSchema returns the values JSON schema of the chart provided, if
+// available.
+func getValuesSchema(chrt *chart.Chart) (map[string]interface{}, error) {
+	if len(chrt.Schema) == 0 {
+		return nil, nil
+	}
+	if len(chrt.Schema) > maxValuesFileSize {
+		return nil, fmt.Errorf("%s: %w", chartutil.SchemafileName, errValuesFileTooBig)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(chrt.Schema, &schema); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", chartutil.SchemafileName, err)
+	}
+	return schema, nil
+}
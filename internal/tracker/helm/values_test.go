@@ -0,0 +1,71 @@
+package helm
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestGetDefaultValues(t *testing.T) {
+	t.Run("values file not available", func(t *testing.T) {
+		values, err := getDefaultValues(&chart.Chart{})
+		require.NoError(t, err)
+		assert.Equal(t, "", values)
+	})
+
+	t.Run("values file too big", func(t *testing.T) {
+		chrt := &chart.Chart{
+			Raw: []*chart.File{
+				{Name: "values.yaml", Data: bytes.Repeat([]byte("a"), maxValuesFileSize+1)},
+			},
+		}
+		_, err := getDefaultValues(chrt)
+		assert.True(t, errors.Is(err, errValuesFileTooBig))
+	})
+
+	t.Run("values file returned", func(t *testing.T) {
+		chrt := &chart.Chart{
+			Raw: []*chart.File{
+				{Name: "Chart.yaml", Data: []byte("name: chart1\n")},
+				{Name: "values.yaml", Data: []byte("# Number of replicas\nreplicaCount: 1\n")},
+			},
+		}
+		values, err := getDefaultValues(chrt)
+		require.NoError(t, err)
+		assert.Equal(t, "# Number of replicas\nreplicaCount: 1\n", values)
+	})
+}
+
+func TestGetValuesSchema(t *testing.T) {
+	t.Run("values schema not available", func(t *testing.T) {
+		schema, err := getValuesSchema(&chart.Chart{})
+		require.NoError(t, err)
+		assert.Nil(t, schema)
+	})
+
+	t.Run("values schema too big", func(t *testing.T) {
+		chrt := &chart.Chart{Schema: bytes.Repeat([]byte("a"), maxValuesFileSize+1)}
+		_, err := getValuesSchema(chrt)
+		assert.True(t, errors.Is(err, errValuesFileTooBig))
+	})
+
+	t.Run("invalid values schema", func(t *testing.T) {
+		chrt := &chart.Chart{Schema: []byte("{")}
+		_, err := getValuesSchema(chrt)
+		assert.Error(t, err)
+	})
+
+	t.Run("values schema returned", func(t *testing.T) {
+		chrt := &chart.Chart{Schema: []byte(`{"type": "object", "required": ["replicaCount"]}`)}
+		schema, err := getValuesSchema(chrt)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"type":     "object",
+			"required": []interface{}{"replicaCount"},
+		}, schema)
+	})
+}
@@ -1,39 +1,90 @@
 package helm
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
 	"errors"
 	"fmt"
 	"image"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"os"
 	"path"
+	"runtime/debug"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/img"
 	"github.com/artifacthub/hub/internal/license"
+	"github.com/artifacthub/hub/internal/oci"
+	"github.com/artifacthub/hub/internal/repo"
 	"github.com/artifacthub/hub/internal/tracker"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/rs/zerolog"
-	"github.com/rs/zerolog/log"
 	"github.com/vincent-petithory/dataurl"
-	"golang.org/x/time/rate"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/yaml.v2"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
+	helmrepo "helm.sh/helm/v3/pkg/repo"
 )
 
-// githubRL represents a rate limiter used when loading charts from Github, to
-// avoid some rate limiting issues were are experiencing.
-var githubRL = rate.NewLimiter(2, 1)
+const (
+	// defaultMaxArchiveSize represents the maximum size in bytes of the chart
+	// archives that will be processed when none is provided.
+	defaultMaxArchiveSize = 10 * 1024 * 1024
+
+	// maxArchiveExpansionRatio represents how many times larger than the
+	// maximum archive size the uncompressed content of a chart archive can be.
+	maxArchiveExpansionRatio = 10
+
+	// maintenanceAnnotation represents the chart annotation used to declare
+	// the versions supported and the end of life date of a chart version.
+	maintenanceAnnotation = "artifacthub.io/maintenance"
+
+	// operatorAnnotation represents the chart annotation used to declare
+	// explicitly whether the chart is an operator or not.
+	operatorAnnotation = "artifacthub.io/operator"
+
+	// operatorCapabilitiesAnnotation represents the chart annotation used to
+	// declare the capability level of the operator (i.e. Basic Install).
+	operatorCapabilitiesAnnotation = "artifacthub.io/operatorCapabilities"
+)
+
+// defaultRequestTimeout represents the timeout used for the http requests
+// performed by the worker when none is provided in the configuration.
+const defaultRequestTimeout = 10 * time.Second
+
+// defaultJobTimeout represents the maximum amount of time a worker can spend
+// handling a single job when none is provided in the configuration.
+const defaultJobTimeout = 5 * time.Minute
 
 // Worker is in charge of handling Helm packages register and unregister jobs
 // generated by the tracker.
 type Worker struct {
-	svc    *tracker.Services
-	r      *hub.Repository
-	hg     HTTPGetter
-	logger zerolog.Logger
+	svc            *tracker.Services
+	r              *hub.Repository
+	ctx            context.Context
+	hc             tracker.HTTPClient
+	oc             *oci.Client
+	cache          *chartsCache
+	requestTimeout time.Duration
+	jobTimeout     time.Duration
+	maxArchiveSize int64
+	maxImageSize   int
+	retries        int
+	retryDelay     time.Duration
+	resolvedDeps   map[string]string
+	signKeyrings   map[string]openpgp.EntityList
+	logger         zerolog.Logger
 }
 
 // NewWorker creates a new worker instance.
@@ -43,19 +94,69 @@ func NewWorker(
 	opts ...func(w *Worker),
 ) *Worker {
 	w := &Worker{
-		svc:    svc,
-		r:      r,
-		logger: log.With().Str("repo", r.Name).Str("kind", hub.GetKindName(r.Kind)).Logger(),
+		svc:          svc,
+		r:            r,
+		ctx:          svc.Ctx,
+		resolvedDeps: make(map[string]string),
+		signKeyrings: make(map[string]openpgp.EntityList),
+		logger:       util.LogWith("tracker").Str("repo", r.Name).Str("kind", hub.GetKindName(r.Kind)).Logger(),
 	}
 	for _, o := range opts {
 		o(w)
 	}
-	if w.hg == nil {
-		w.hg = &http.Client{Timeout: 10 * time.Second}
+	if w.hc == nil {
+		w.hc = &http.Client{}
+	}
+	w.hc = w.svc.Rl.LimitClient(w.svc.Hl.LimitClient(w.hc))
+	w.oc = oci.NewClient(w.hc)
+	if w.requestTimeout == 0 && w.svc.Cfg != nil {
+		w.requestTimeout = w.svc.Cfg.GetDuration("tracker.requestTimeout")
+	}
+	if w.requestTimeout == 0 {
+		w.requestTimeout = defaultRequestTimeout
+	}
+	if w.jobTimeout == 0 && w.svc.Cfg != nil {
+		w.jobTimeout = w.svc.Cfg.GetDuration("tracker.helmJobTimeout")
+	}
+	if w.jobTimeout <= 0 {
+		w.jobTimeout = defaultJobTimeout
+	}
+	if w.cache == nil && w.svc.Cfg != nil {
+		if dir := w.svc.Cfg.GetString("tracker.chartsCache.path"); dir != "" {
+			w.cache = newChartsCache(dir)
+		}
+	}
+	if w.maxArchiveSize == 0 && w.svc.Cfg != nil {
+		w.maxArchiveSize = w.svc.Cfg.GetInt64("tracker.helmMaxArchiveSize")
+	}
+	if w.maxArchiveSize <= 0 {
+		w.maxArchiveSize = defaultMaxArchiveSize
+	}
+	w.maxImageSize = img.DefaultLimits().MaxSize
+	if w.svc.Cfg != nil {
+		if limits, err := util.SetupImageLimits(w.svc.Cfg); err == nil {
+			w.maxImageSize = limits.MaxSize
+		}
+	}
+	w.retries = getDownloadRetries(w.svc.Cfg, r)
+	if w.svc.Cfg != nil {
+		w.retryDelay = w.svc.Cfg.GetDuration("tracker.downloadRetryDelay")
+	}
+	if w.retryDelay <= 0 {
+		w.retryDelay = defaultRetryBaseDelay
 	}
 	return w
 }
 
+// WithHTTPClient allows providing a specific http client for a Worker
+// instance. Trackers use it to share among their workers a client configured
+// with the repository's custom tls settings.
+func WithHTTPClient(hc tracker.HTTPClient) func(w *Worker) {
+	return func(w *Worker) {
+		w.hc = hc
+	}
+}
+
 // Run instructs the worker to start handling jobs. It will keep running until
 // the jobs queue is empty or the context is done.
 func (w *Worker) Run(wg *sync.WaitGroup, queue chan *Job) {
@@ -66,62 +167,115 @@ func (w *Worker) Run(wg *sync.WaitGroup, queue chan *Job) {
 			if !ok {
 				return
 			}
-			switch j.Kind {
-			case Register:
-				w.handleRegisterJob(j)
-			case Unregister:
-				w.handleUnregisterJob(j)
-			}
+			w.handleJob(j)
 		case <-w.svc.Ctx.Done():
 			return
 		}
 	}
 }
 
+// handleJob handles the job provided, bounding the time spent on it with the
+// job timeout. Panics are recovered and reported as errors, so that a single
+// pathological chart cannot take the worker down and leave the remaining jobs
+// in the queue unhandled.
+func (w *Worker) handleJob(j *Job) {
+	ctx, cancel := context.WithTimeout(w.svc.Ctx, w.jobTimeout)
+	defer cancel()
+	w.ctx = ctx
+	defer func() {
+		w.ctx = w.svc.Ctx
+		if r := recover(); r != nil {
+			w.logger.Error().Str("stack", string(debug.Stack())).Msg("panic handling job")
+			w.warn(fmt.Errorf("panic handling chart %s version %s: %v", j.ChartVersion.Name, j.ChartVersion.Version, r))
+		}
+	}()
+
+	switch j.Kind {
+	case Register:
+		w.handleRegisterJob(j)
+	case Unregister:
+		w.handleUnregisterJob(j)
+	}
+}
+
 // handleRegisterJob handles the provided Helm package registration job. This
 // involves downloading the chart archive, extracting its contents and register
-// the corresponding package.
+// the corresponding package. When the chart version provides several urls,
+// they are tried in order until the chart archive is loaded successfully.
 func (w *Worker) handleRegisterJob(j *Job) {
-	// Prepare chart archive url
-	u := j.ChartVersion.URLs[0]
-	if _, err := url.ParseRequestURI(u); err != nil {
-		tmp, err := url.Parse(w.r.URL)
-		if err != nil {
-			w.warn(fmt.Errorf("invalid chart url: %w", err))
+	// Prepare chart archive urls
+	urls, err := w.resolveChartVersionURLs(j.ChartVersion)
+	if err != nil {
+		w.warn(err)
+		return
+	}
+
+	// Get the chart version provenance file, if available, while the chart
+	// is being loaded and processed (not supported for charts in OCI
+	// registries). The provenance file and the logo are fetched by the tasks
+	// of a group, which returns the error checking the provenance file (if
+	// any) once all of them are done.
+	var g *errgroup.Group
+	defer func() { _ = g.Wait() }()
+	var hasProvenanceFile bool
+	var provenanceFile []byte
+	var provenanceURL string
+	checkProvenanceFile := func(u string) {
+		g, _ = errgroup.WithContext(w.ctx)
+		provenanceURL = u
+		if oci.IsOCI(u) {
 			return
 		}
-		tmp.Path = path.Join(tmp.Path, u)
-		u = tmp.String()
+		g.Go(func() error {
+			defer w.recoverPanic("getting provenance file " + u)
+			var err error
+			provenanceFile, hasProvenanceFile, err = w.getProvenanceFile(u)
+			return err
+		})
 	}
+	checkProvenanceFile(urls[0])
 
 	// Load chart from remote archive
-	chart, err := w.loadChart(u)
+	chart, u, err := w.loadChartFromURLs(urls, j.ChartVersion.Digest)
 	if err != nil {
 		w.warn(fmt.Errorf("error loading chart: %w", err))
 		return
 	}
+	if u != provenanceURL {
+		// The chart was loaded from a fallback url, so the provenance file
+		// check must be done again for that url
+		_ = g.Wait()
+		provenanceFile, hasProvenanceFile = nil, false
+		checkProvenanceFile(u)
+	}
 	md := chart.Metadata
 
-	// Store logo when available if requested
+	// Store logo when available if requested (concurrently as well). When a
+	// logos queue is available, the logo will be fetched asynchronously once
+	// the package has been registered.
 	var logoURL, logoImageID string
+	var enqueueLogo bool
 	if j.StoreLogo && md.Icon != "" {
-		logoURL = md.Icon
-		data, err := w.getImage(md.Icon)
-		if err != nil {
-			w.warn(fmt.Errorf("error getting image %s: %w", md.Icon, err))
-		} else {
-			logoImageID, err = w.svc.Is.SaveImage(w.svc.Ctx, data)
-			if err != nil && !errors.Is(err, image.ErrFormat) {
-				w.warn(fmt.Errorf("error saving image %s: %w", md.Icon, err))
+		if w.svc.Lq != nil {
+			var err error
+			logoURL, err = w.resolveLogoURL(md.Icon)
+			if err != nil {
+				w.warn(err)
+			} else {
+				enqueueLogo = true
 			}
+		} else {
+			g.Go(func() error {
+				defer w.recoverPanic("storing logo " + md.Icon)
+				logoURL, logoImageID = w.storeLogo(md.Icon)
+				return nil
+			})
 		}
 	}
 
 	// Prepare package to be registered
 	p := &hub.Package{
 		Name:        md.Name,
-		LogoURL:     logoURL,
-		LogoImageID: logoImageID,
 		Description: md.Description,
 		Keywords:    md.Keywords,
 		HomeURL:     md.Home,
@@ -130,22 +284,18 @@ func (w *Worker) handleRegisterJob(j *Job) {
 		Digest:      j.ChartVersion.Digest,
 		Deprecated:  md.Deprecated,
 		ContentURL:  u,
-		CreatedAt:   j.ChartVersion.Created.Unix(),
 		Repository:  w.r,
 	}
-	readme := getFile(chart, "README.md")
-	if readme != nil {
-		p.Readme = string(readme.Data)
-	}
-	licenseFile := getFile(chart, "LICENSE")
-	if licenseFile != nil {
-		p.License = license.Detect(licenseFile.Data)
+	if !j.ChartVersion.Created.IsZero() {
+		p.CreatedAt = j.ChartVersion.Created.Unix()
 	}
-	hasProvenanceFile, err := w.chartVersionHasProvenanceFile(u)
-	if err == nil {
-		p.Signed = hasProvenanceFile
-	} else {
-		w.logger.Warn().Err(err).Msg("error checking provenance file")
+	for _, sourceURL := range md.Sources {
+		if sourceURL != "" {
+			p.Links = append(p.Links, &hub.Link{
+				Name: "source",
+				URL:  sourceURL,
+			})
+		}
 	}
 	var maintainers []*hub.Maintainer
 	for _, entry := range md.Maintainers {
@@ -159,28 +309,272 @@ func (w *Worker) handleRegisterJob(j *Job) {
 	if len(maintainers) > 0 {
 		p.Maintainers = maintainers
 	}
-	if strings.Contains(strings.ToLower(md.Name), "operator") {
-		p.IsOperator = true
+	isOperator, capabilities, err := parseOperatorAnnotations(md)
+	if err != nil {
+		w.warn(fmt.Errorf("invalid operator annotations in chart %s version %s: %w", md.Name, md.Version, err))
+	}
+	p.IsOperator = isOperator
+	p.Capabilities = capabilities
+	kubeVersion, err := normalizeKubeVersion(md.KubeVersion)
+	if err != nil {
+		w.warn(fmt.Errorf("invalid kubeVersion in chart %s version %s: %w", md.Name, md.Version, err))
+	}
+	p.KubeVersion = kubeVersion
+	p.ChartType = "application"
+	if md.Type == "library" {
+		p.ChartType = "library"
 	}
 	dependencies := make([]map[string]string, 0, len(md.Dependencies))
 	for _, dependency := range md.Dependencies {
-		dependencies = append(dependencies, map[string]string{
+		d := map[string]string{
 			"name":       dependency.Name,
 			"version":    dependency.Version,
 			"repository": dependency.Repository,
-		})
+		}
+		if packageID := w.resolveDependency(dependency); packageID != "" {
+			d["package_id"] = packageID
+		}
+		dependencies = append(dependencies, d)
 	}
 	if len(dependencies) > 0 {
 		p.Data = map[string]interface{}{
 			"dependencies": dependencies,
 		}
 	}
+	if v, ok := md.Annotations[maintenanceAnnotation]; ok {
+		maintenance, err := parseMaintenanceAnnotation(v)
+		if err != nil {
+			w.warn(fmt.Errorf("invalid maintenance annotation in chart %s version %s: %w", md.Name, md.Version, err))
+		} else {
+			p.Maintenance = maintenance
+		}
+	}
+	p.Install = getInstallInstructions(w.r, md)
+	e := w.getChartExtraction(chart, u, j.ChartVersion.Digest)
+	p.Readme = e.Readme
+	p.Changelog = e.Changelog
+	p.License = e.License
+	if len(e.ValuesPresets) > 0 {
+		if p.Data == nil {
+			p.Data = make(map[string]interface{})
+		}
+		p.Data["values_presets"] = e.ValuesPresets
+	}
+	if e.DefaultValues != "" {
+		if p.Data == nil {
+			p.Data = make(map[string]interface{})
+		}
+		p.Data["default_values"] = e.DefaultValues
+	}
+	if e.ValuesSchema != nil {
+		if p.Data == nil {
+			p.Data = make(map[string]interface{})
+		}
+		p.Data["values_schema"] = e.ValuesSchema
+	}
+	if md.Type != "library" {
+		if p.Data == nil {
+			p.Data = make(map[string]interface{})
+		}
+		p.Data["api_availability"] = e.APIAvailability
+		if e.Resources != nil {
+			p.Data["resources"] = e.Resources
+		}
+		p.ContainersImages = e.ContainersImages
+		if e.Notes != "" {
+			p.Data["notes"] = e.Notes
+		}
+		if len(e.CRDs) > 0 {
+			p.Data["crds"] = e.CRDs
+		}
+		if e.Analyzed {
+			p.Data["recommendations"] = e.Recommendations
+		}
+	}
+	if err := applyMetadataAnnotations(p, md); err != nil {
+		w.warn(fmt.Errorf("invalid annotations in chart %s version %s: %w", md.Name, md.Version, err))
+	}
+
+	// Wait for the provenance file check and the logo to be ready. When the
+	// chart version is signed, the details of the key used are extracted from
+	// the provenance file and the sign key annotation. The signature is
+	// verified when the publisher declares where the public key can be
+	// fetched from and the index file provides the chart archive digest.
+	if err := g.Wait(); err != nil {
+		w.logger.Warn().Err(err).Msg("error checking provenance file")
+	} else {
+		p.Signed = hasProvenanceFile
+	}
+	if p.Signed {
+		signKey, err := getProvenanceSignKey(provenanceFile)
+		if err != nil {
+			w.logger.Warn().Err(err).Msg("error extracting sign key from provenance file")
+		} else {
+			if v, ok := md.Annotations[signKeyAnnotation]; ok {
+				if err := applySignKeyAnnotation(signKey, v); err != nil {
+					w.warn(fmt.Errorf("invalid sign key annotation in chart %s version %s: %w", md.Name, md.Version, err))
+				}
+			}
+			p.SignKey = signKey
+			if signKey.URL != "" && j.ChartVersion.Digest != "" {
+				err := w.verifyProvenanceFile(provenanceFile, signKey, u, j.ChartVersion.Digest)
+				if err != nil {
+					w.warn(fmt.Errorf("error verifying chart %s version %s provenance file: %w", md.Name, md.Version, err))
+				} else {
+					p.SignatureVerified = true
+				}
+			}
+		}
+	}
+	p.LogoURL = logoURL
+	p.LogoImageID = logoImageID
+
+	// Validate package if a validator is available
+	if w.svc.Pv != nil {
+		if err := w.svc.Pv.Validate(p); err != nil {
+			w.warn(fmt.Errorf("error validating package %s version %s: %w", md.Name, md.Version, err))
+			return
+		}
+	}
 
 	// Register package
 	w.logger.Debug().Str("name", md.Name).Str("v", md.Version).Msg("registering package")
-	if err := w.svc.Pm.Register(w.svc.Ctx, p); err != nil {
+	if err := w.svc.Pm.Register(w.ctx, p); err != nil {
 		w.warn(fmt.Errorf("error registering package %s version %s: %w", md.Name, md.Version, err))
+		return
+	}
+
+	// Enqueue logo to be fetched if needed
+	if enqueueLogo {
+		w.svc.Lq.Enqueue(&tracker.LogoJob{
+			Package:  p,
+			GetImage: func() ([]byte, error) { return w.getImage(logoURL) },
+		})
+	}
+}
+
+// getChartExtraction returns the information extracted from the chart
+// provided, loaded from the url given. When a charts cache is configured, the
+// extraction is cached by the digest of the chart archive, so that it is
+// reused when the same archive is tracked again. Archives without a digest or
+// pulled from OCI registries are not cached, as their digest is not verified.
+// Cached extractions are ignored when the digest check is bypassed, to force
+// charts to be processed again.
+func (w *Worker) getChartExtraction(chrt *chart.Chart, u, digest string) *chartExtraction {
+	var analyze, bypassCache bool
+	if w.svc.Cfg != nil {
+		analyze = w.svc.Cfg.GetBool("tracker.analyzeManifests")
+		bypassCache = w.svc.Cfg.GetBool("tracker.bypassDigestCheck")
+	}
+	cacheable := w.cache != nil && digest != "" && !oci.IsOCI(u)
+
+	// Use cached extraction if available
+	if cacheable && !bypassCache {
+		if e, ok := w.cache.getExtraction(digest); ok && (e.Analyzed || !analyze) {
+			if !analyze {
+				e.Analyzed, e.Recommendations = false, nil
+			}
+			w.logger.Debug().Str("name", chrt.Metadata.Name).Str("v", chrt.Metadata.Version).Msg("using cached chart extraction")
+			return e
+		}
 	}
+
+	// Extract information from chart and cache it when no errors were found
+	e, complete := w.extractChart(chrt, analyze)
+	if cacheable && complete {
+		if err := w.cache.putExtraction(digest, e); err != nil {
+			w.logger.Warn().Err(err).Str("url", u).Msg("error caching chart extraction")
+		}
+	}
+	return e
+}
+
+// extractChart extracts from the chart provided the information that is
+// expensive to compute, like the default values or the manifests validation
+// report. The extraction is only complete when no errors were found, as the
+// errors must be reported every time the chart is tracked.
+func (w *Worker) extractChart(chrt *chart.Chart, analyze bool) (*chartExtraction, bool) {
+	md := chrt.Metadata
+	e := &chartExtraction{Version: chartExtractionVersion}
+	complete := true
+	warn := func(err error) {
+		w.warn(err)
+		complete = false
+	}
+
+	readme := getFile(chrt, "README.md")
+	if readme != nil {
+		e.Readme = string(readme.Data)
+	}
+	changelog := getFile(chrt, "CHANGELOG.md")
+	if changelog != nil {
+		e.Changelog = string(changelog.Data)
+	}
+	for _, name := range license.FileNames {
+		if licenseFile := getFile(chrt, name); licenseFile != nil {
+			e.License = license.Detect(licenseFile.Data)
+			break
+		}
+	}
+	if v, ok := md.Annotations[valuesPresetsAnnotation]; ok {
+		presets, errs := getValuesPresets(chrt, v)
+		for _, err := range errs {
+			warn(fmt.Errorf("invalid values preset in chart %s version %s: %w", md.Name, md.Version, err))
+		}
+		e.ValuesPresets = presets
+	}
+	defaultValues, err := getDefaultValues(chrt)
+	if err != nil {
+		warn(fmt.Errorf("error getting chart %s version %s default values: %w", md.Name, md.Version, err))
+	} else {
+		e.DefaultValues = defaultValues
+	}
+	valuesSchema, err := getValuesSchema(chrt)
+	if err != nil {
+		warn(fmt.Errorf("error getting chart %s version %s values schema: %w", md.Name, md.Version, err))
+	} else {
+		e.ValuesSchema = valuesSchema
+	}
+	if md.Type != "library" {
+		e.APIAvailability = checkAPIAvailability(chrt)
+		resources, err := estimateResources(chrt)
+		if err != nil {
+			w.logger.Debug().Err(err).Str("name", md.Name).Str("v", md.Version).Msg("error estimating resources")
+		} else {
+			e.Resources = resources
+		}
+		containersImages, err := extractContainersImages(chrt)
+		if err != nil {
+			w.logger.Debug().Err(err).Str("name", md.Name).Str("v", md.Version).Msg("error extracting containers images")
+		} else {
+			e.ContainersImages = containersImages
+		}
+		notes, err := getNotes(chrt)
+		if err != nil {
+			warn(fmt.Errorf("error getting chart %s version %s notes: %w", md.Name, md.Version, err))
+		} else {
+			e.Notes = notes
+		}
+		crds, err := getCRDs(chrt)
+		if err != nil {
+			warn(fmt.Errorf("error getting chart %s version %s crds: %w", md.Name, md.Version, err))
+		} else {
+			e.CRDs = crds
+		}
+		if analyze {
+			recommendations, err := analyzeManifests(chrt)
+			if err != nil {
+				warn(fmt.Errorf("error analyzing chart %s version %s manifests: %w", md.Name, md.Version, err))
+			} else {
+				e.Analyzed = true
+				e.Recommendations = recommendations
+			}
+		}
+	} else {
+		// Library charts have no manifests to analyze
+		e.Analyzed = analyze
+	}
+	return e, complete
 }
 
 // handleUnregisterJob handles the provided Helm package unregistration job.
@@ -194,49 +588,348 @@ func (w *Worker) handleUnregisterJob(j *Job) {
 		Repository: w.r,
 	}
 	w.logger.Debug().Str("name", p.Name).Str("v", p.Version).Msg("unregistering package")
-	if err := w.svc.Pm.Unregister(w.svc.Ctx, p); err != nil {
+	if err := w.svc.Pm.Unregister(w.ctx, p); err != nil {
 		w.warn(fmt.Errorf("error unregistering package %s version %s: %w", p.Name, p.Version, err))
 	}
 }
 
+// resolveDependency returns the id of the package in the hub the chart
+// dependency provided refers to, if any. Dependencies are matched by their
+// repository url and name. Resolutions are cached by the worker, as the same
+// dependencies are usually found in many versions of a chart.
+func (w *Worker) resolveDependency(dep *chart.Dependency) string {
+	if !strings.HasPrefix(dep.Repository, "http://") &&
+		!strings.HasPrefix(dep.Repository, "https://") &&
+		!oci.IsOCI(dep.Repository) {
+		return ""
+	}
+	key := dep.Repository + "#" + dep.Name
+	if packageID, ok := w.resolvedDeps[key]; ok {
+		return packageID
+	}
+	kind := hub.Helm
+	packages, err := w.svc.Pm.Resolve(w.ctx, &hub.ResolvePackageInput{
+		RepositoryKind: &kind,
+		RepositoryURL:  dep.Repository,
+		PackageName:    dep.Name,
+	})
+	if err != nil {
+		w.logger.Debug().Err(err).Str("name", dep.Name).Str("repo", dep.Repository).Msg("error resolving dependency")
+		return ""
+	}
+	var packageID string
+	if len(packages) == 1 {
+		packageID = packages[0].PackageID
+	}
+	w.resolvedDeps[key] = packageID
+	return packageID
+}
+
+// storeLogo gets the logo image located at the url provided and stores it in
+// the image store. Relative urls are resolved against the repository url. The
+// logo url used and the id of the image stored are returned.
+func (w *Worker) storeLogo(u string) (string, string) {
+	logoURL, err := w.resolveLogoURL(u)
+	if err != nil {
+		w.warn(err)
+		return u, ""
+	}
+	data, err := w.getImage(logoURL)
+	if err != nil {
+		w.warn(fmt.Errorf("error getting image %s: %w", logoURL, err))
+		return logoURL, ""
+	}
+	logoImageID, err := w.svc.Is.SaveImage(w.ctx, data)
+	if err != nil && !errors.Is(err, image.ErrFormat) {
+		w.warn(fmt.Errorf("error saving image %s: %w", logoURL, err))
+	}
+	return logoURL, logoImageID
+}
+
+// resolveLogoURL resolves the logo url provided against the repository url
+// when needed. Data urls are returned as is.
+func (w *Worker) resolveLogoURL(u string) (string, error) {
+	if strings.HasPrefix(u, "data:") {
+		return u, nil
+	}
+	logoURL, err := resolveURL(w.r.URL, u)
+	if err != nil {
+		return "", fmt.Errorf("invalid image url %s: %w", u, err)
+	}
+	return logoURL, nil
+}
+
+// resolveChartVersionURLs returns the urls of the chart version provided that
+// can be used to load its archive, resolved against the repository url and in
+// the same order they were listed in the index file. An error is returned when
+// none of them is valid.
+func (w *Worker) resolveChartVersionURLs(cv *helmrepo.ChartVersion) ([]string, error) {
+	urls := make([]string, 0, len(cv.URLs))
+	var firstErr error
+	for _, rawURL := range cv.URLs {
+		if err := checkChartVersionURL(cv, rawURL, w.r.URL); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		u, err := resolveURL(w.r.URL, rawURL)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = tracker.NewError(tracker.ErrCodeInvalidURL, fmt.Errorf("invalid chart url: %w", err))
+			}
+			continue
+		}
+		urls = append(urls, u)
+	}
+	if len(urls) == 0 {
+		if firstErr == nil {
+			firstErr = tracker.NewError(
+				tracker.ErrCodeMissingURL,
+				fmt.Errorf("package %s version %s has no urls", cv.Name, cv.Version),
+			)
+		}
+		return nil, firstErr
+	}
+	return urls, nil
+}
+
+// loadChartFromURLs loads a chart trying the urls provided in order, falling
+// back to the next one when the chart cannot be loaded from the current one.
+// The url the chart was loaded from is returned along with the chart. When
+// the chart cannot be loaded from any of them, the last error is returned.
+func (w *Worker) loadChartFromURLs(urls []string, digest string) (*chart.Chart, string, error) {
+	var err error
+	for i, u := range urls {
+		var c *chart.Chart
+		c, err = w.loadChart(u, digest)
+		if err == nil {
+			return c, u, nil
+		}
+		if w.ctx.Err() != nil {
+			break
+		}
+		if i < len(urls)-1 {
+			w.logger.Debug().Err(err).Str("url", u).Msg("error loading chart, trying next url")
+		}
+	}
+	return nil, "", err
+}
+
 // loadChart loads a chart from a remote archive located at the url provided.
-func (w *Worker) loadChart(u string) (*chart.Chart, error) {
-	// Rate limit requests to Github to avoid them being rejected
-	if strings.HasPrefix(u, "https://github.com") {
-		_ = githubRL.Wait(w.svc.Ctx)
+// When a digest is provided, the archive's content must match it, so that
+// tampered or corrupted archives are not registered. When a charts cache is
+// configured, archives downloaded over http are cached by the digest
+// provided, so that they are read from the cache in subsequent runs instead
+// of being downloaded again.
+func (w *Worker) loadChart(u, digest string) (*chart.Chart, error) {
+	if oci.IsOCI(u) {
+		return w.loadChartFromOCIRegistry(u)
+	}
+	if repo.IsLocal(u) {
+		f, err := w.openLocalFile(u)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, tracker.NewError(tracker.ErrCodeArchiveNotFound, fmt.Errorf("chart archive not found: %s", u))
+			}
+			return nil, err
+		}
+		defer f.Close()
+		data, err := w.readChartArchive(u, f)
+		if err != nil {
+			return nil, err
+		}
+		if err := verifyChartDigest(u, data, digest); err != nil {
+			return nil, err
+		}
+		return w.loadChartArchive(u, data)
+	}
+
+	if data, ok := w.cache.get(digest); ok {
+		return w.loadChartArchive(u, data)
 	}
 
-	resp, err := w.hg.Get(u)
+	resp, err := w.getWithRetries(u)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
-	if resp.StatusCode == http.StatusOK {
-		chart, err := loader.LoadArchive(resp.Body)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if resp.ContentLength > w.maxArchiveSize {
+			return nil, w.archiveTooLargeError(u)
+		}
+		data, err := w.readChartArchive(u, resp.Body)
 		if err != nil {
 			return nil, err
 		}
-		return chart, nil
+		if err := verifyChartDigest(u, data, digest); err != nil {
+			return nil, err
+		}
+		if w.cache != nil && digest != "" {
+			if err := w.cache.put(digest, data); err != nil {
+				w.logger.Warn().Err(err).Str("url", u).Msg("error caching chart archive")
+			}
+		}
+		return w.loadChartArchive(u, data)
+	case http.StatusNotFound:
+		return nil, tracker.NewError(tracker.ErrCodeArchiveNotFound, fmt.Errorf("chart archive not found: %s", u))
+	default:
+		return nil, fmt.Errorf("unexpected status code received: %d", resp.StatusCode)
+	}
+}
+
+// loadChartFromOCIRegistry loads a chart from the OCI reference provided,
+// pulling the layer that contains the chart archive.
+func (w *Worker) loadChartFromOCIRegistry(u string) (*chart.Chart, error) {
+	ref, err := oci.ParseReference(u)
+	if err != nil {
+		return nil, tracker.NewError(tracker.ErrCodeInvalidURL, err)
+	}
+	ctx, cancel := context.WithTimeout(w.ctx, w.requestTimeout)
+	defer cancel()
+	data, _, err := w.oc.PullChart(ctx, ref)
+	if err != nil {
+		if errors.Is(err, oci.ErrNotFound) || errors.Is(err, oci.ErrChartLayerNotFound) {
+			return nil, tracker.NewError(tracker.ErrCodeArchiveNotFound, fmt.Errorf("chart archive not found: %s", u))
+		}
+		return nil, err
+	}
+	if int64(len(data)) > w.maxArchiveSize {
+		return nil, w.archiveTooLargeError(u)
+	}
+	return w.loadChartArchive(u, data)
+}
+
+// readChartArchive reads the chart archive provided, making sure it does not
+// exceed the maximum archive size allowed.
+func (w *Worker) readChartArchive(u string, r io.Reader) ([]byte, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, w.maxArchiveSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > w.maxArchiveSize {
+		return nil, w.archiveTooLargeError(u)
+	}
+	return data, nil
+}
+
+// loadChartArchive loads the chart from the archive data provided. The Helm
+// loader extracts the whole archive in memory, so the archive is decompressed
+// first as a stream to check that its content does not exceed the maximum
+// size allowed.
+func (w *Worker) loadChartArchive(u string, data []byte) (*chart.Chart, error) {
+	gzr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gzr.Close()
+	maxSize := w.maxArchiveSize * maxArchiveExpansionRatio
+	n, err := io.Copy(ioutil.Discard, io.LimitReader(gzr, maxSize+1))
+	if err != nil {
+		return nil, err
 	}
-	return nil, fmt.Errorf("unexpected status code received: %d", resp.StatusCode)
+	if n > maxSize {
+		return nil, tracker.NewError(
+			tracker.ErrCodeArchiveTooLarge,
+			fmt.Errorf("chart archive content too large (max %d bytes uncompressed): %s", maxSize, u),
+		)
+	}
+	return loader.LoadArchive(bytes.NewReader(data))
+}
+
+// archiveTooLargeError returns the error used when the chart archive located
+// at the url provided exceeds the maximum archive size allowed.
+func (w *Worker) archiveTooLargeError(u string) error {
+	return tracker.NewError(
+		tracker.ErrCodeArchiveTooLarge,
+		fmt.Errorf("chart archive too large (max %d bytes): %s", w.maxArchiveSize, u),
+	)
 }
 
-// chartVersionHasProvenanceFile checks if a chart version has a provenance
-// file checking if a .prov file exists for the chart version url provided.
-func (w *Worker) chartVersionHasProvenanceFile(u string) (bool, error) {
-	resp, err := w.hg.Get(u + ".prov")
+// getProvenanceFile returns the content of the provenance file (.prov) of the
+// chart version url provided, if it exists.
+func (w *Worker) getProvenanceFile(u string) ([]byte, bool, error) {
+	if repo.IsLocal(u) {
+		f, err := w.openLocalFile(u + ".prov")
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, false, nil
+			}
+			return nil, false, err
+		}
+		defer f.Close()
+		data, err := readProvenanceFile(f)
+		return data, err == nil, err
+	}
+	resp, err := w.get(u + ".prov")
 	if err != nil {
-		return false, err
+		return nil, false, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode == http.StatusOK {
-		return true, nil
+		data, err := readProvenanceFile(resp.Body)
+		return data, err == nil, err
+	}
+	return nil, false, nil
+}
+
+// verifyProvenanceFile verifies the signature of the provenance file provided
+// using the public key located at the sign key url, checking as well that it
+// includes the digest of the chart archive located at the url given. When the
+// sign key fingerprint is not known yet, it's set from the verified key.
+func (w *Worker) verifyProvenanceFile(prov []byte, k *hub.SignKey, u, digest string) error {
+	keyring, err := w.getSignKeyring(k.URL)
+	if err != nil {
+		return err
+	}
+	archiveName := path.Base(u)
+	if parsedURL, err := url.Parse(u); err == nil {
+		archiveName = path.Base(parsedURL.Path)
+	}
+	signer, err := verifyProvenanceFile(prov, keyring, archiveName, digest)
+	if err != nil {
+		return err
+	}
+	if k.Fingerprint == "" && signer.PrimaryKey.KeyIdString() == k.KeyID {
+		k.Fingerprint = fmt.Sprintf("%X", signer.PrimaryKey.Fingerprint)
+	}
+	return nil
+}
+
+// getSignKeyring returns the public keyring located at the url provided. The
+// keyrings are cached by the worker, as all the versions of a chart are
+// usually signed with the same key.
+func (w *Worker) getSignKeyring(u string) (openpgp.EntityList, error) {
+	if keyring, ok := w.signKeyrings[u]; ok {
+		return keyring, nil
+	}
+	resp, err := w.get(u)
+	if err != nil {
+		return nil, fmt.Errorf("error getting sign key: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code received getting sign key: %d", resp.StatusCode)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxSignKeySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading sign key: %w", err)
 	}
-	return false, nil
+	if len(data) > maxSignKeySize {
+		return nil, fmt.Errorf("sign key exceeds the maximum size allowed (%d bytes)", maxSignKeySize)
+	}
+	keyring, err := readSignKeyring(data)
+	if err != nil {
+		return nil, err
+	}
+	w.signKeyrings[u] = keyring
+	return keyring, nil
 }
 
 // getImage gets the image located at the url provided. If it's a data url the
-// image is extracted from it. Otherwise it's downloaded using the url.
+// image is extracted from it. Otherwise it's downloaded using the url. The
+// image is validated by the image store when it's saved.
 func (w *Worker) getImage(u string) ([]byte, error) {
 	// Image in data url
 	if strings.HasPrefix(u, "data:") {
@@ -248,15 +941,79 @@ func (w *Worker) getImage(u string) ([]byte, error) {
 	}
 
 	// Download image using url provided
-	resp, err := w.hg.Get(u)
+	return w.downloadImage(u)
+}
+
+// downloadImage downloads the image located at the url provided, making sure
+// it does not exceed the maximum image size allowed (images.maxSize). Images
+// in local repositories (file:// urls) are read from the file system.
+func (w *Worker) downloadImage(u string) ([]byte, error) {
+	if repo.IsLocal(u) {
+		f, err := w.openLocalFile(u)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return w.readImage(f)
+	}
+
+	resp, err := w.get(u)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
-	if resp.StatusCode == http.StatusOK {
-		return ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code received: %d", resp.StatusCode)
+	}
+	if w.maxImageSize > 0 && resp.ContentLength > int64(w.maxImageSize) {
+		return nil, fmt.Errorf("image too large (max %d bytes)", w.maxImageSize)
+	}
+	return w.readImage(resp.Body)
+}
+
+// readImage reads the image data from the reader provided, stopping as soon
+// as the maximum image size allowed is exceeded.
+func (w *Worker) readImage(r io.Reader) ([]byte, error) {
+	if w.maxImageSize <= 0 {
+		return ioutil.ReadAll(r)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(r, int64(w.maxImageSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > w.maxImageSize {
+		return nil, fmt.Errorf("image too large (max %d bytes)", w.maxImageSize)
+	}
+	return data, nil
+}
+
+// openLocalFile opens the file the file url provided points to. Only files
+// located within the directory of the repository processed by the worker are
+// allowed, and only when it is a repository located in the local file system.
+func (w *Worker) openLocalFile(u string) (*os.File, error) {
+	repoPath, err := repo.LocalPath(w.r.URL)
+	if err != nil {
+		return nil, fmt.Errorf("local files not allowed in non local repositories: %s", u)
+	}
+	p, err := repo.LocalPath(u)
+	if err != nil {
+		return nil, err
+	}
+	if !repo.IsPathWithin(p, repoPath) {
+		return nil, fmt.Errorf("file located outside of the local repository: %s", u)
+	}
+	return os.Open(p)
+}
+
+// recoverPanic recovers from a panic in one of the goroutines launched while
+// handling a job, reporting it as an error. It must be deferred at the top of
+// the goroutine, as panics there are not recovered by the job handler and
+// would take the whole tracker down otherwise.
+func (w *Worker) recoverPanic(action string) {
+	if r := recover(); r != nil {
+		w.logger.Error().Str("stack", string(debug.Stack())).Msg("panic " + action)
+		w.warn(fmt.Errorf("panic %s: %v", action, r))
 	}
-	return nil, fmt.Errorf("unexpected status code received: %d", resp.StatusCode)
 }
 
 // warn is a helper that sends the error provided to the errors collector and
@@ -266,9 +1023,174 @@ func (w *Worker) warn(err error) {
 	w.logger.Warn().Err(err).Send()
 }
 
-// HTTPGetter defines the methods an HTTPGetter implementation must provide.
-type HTTPGetter interface {
-	Get(url string) (*http.Response, error)
+// get performs an http GET request to the url provided. Requests are bound to
+// the context of the job being handled, so they'll be cancelled when it's done
+// or times out, and they'll time out once the configured request timeout
+// expires. The User-Agent and GitHub token configured are added to the request
+// when applicable.
+func (w *Worker) get(u string) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(w.ctx, w.requestTimeout)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	tracker.SetupRequest(req, w.svc.Cfg, w.r)
+	resp, err := w.hc.Do(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnCloseReader{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// getWithRetries performs an http GET request to the url provided, retrying
+// it when it fails with a transient error (i.e. timeouts, 429 or 5xx status
+// codes) as many times as configured for the repository. The response of the
+// last attempt is returned.
+func (w *Worker) getWithRetries(u string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := w.get(u)
+		if attempt >= w.retries || w.ctx.Err() != nil {
+			return resp, err
+		}
+		if err == nil && !isRetryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+		delay := retryDelay(w.retryDelay, attempt, resp)
+		if err == nil {
+			resp.Body.Close()
+		}
+		w.logger.Debug().Err(err).Str("url", u).Dur("delay", delay).Msg("retrying chart archive download")
+		select {
+		case <-time.After(delay):
+		case <-w.ctx.Done():
+			return nil, w.ctx.Err()
+		}
+	}
+}
+
+// cancelOnCloseReader is a wrapper around an http response body that cancels
+// the request context once the body is closed.
+type cancelOnCloseReader struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close implements the io.Closer interface.
+func (r *cancelOnCloseReader) Close() error {
+	err := r.ReadCloser.Close()
+	r.cancel()
+	return err
+}
+
+// resolveURL returns the url provided when it is absolute. Otherwise it is
+// considered a path relative to the base url provided (usually the repository
+// url) and resolved against it.
+func resolveURL(baseURL, u string) (string, error) {
+	if tmp, err := url.Parse(u); err == nil && tmp.IsAbs() {
+		return u, nil
+	}
+	tmp, err := url.Parse(baseURL)
+	if err != nil {
+		return "", err
+	}
+	tmp.Path = path.Join(tmp.Path, u)
+	return tmp.String(), nil
+}
+
+// verifyChartDigest checks that the sha256 digest of the chart archive data
+// provided matches the digest announced in the repository index. Archives of
+// chart versions that don't announce any digest are not verified.
+func verifyChartDigest(u string, data []byte, digest string) error {
+	if digest == "" {
+		return nil
+	}
+	if computeChartDigest(data) != normalizeChartDigest(strings.ToLower(digest)) {
+		return tracker.NewError(
+			tracker.ErrCodeDigestMismatch,
+			fmt.Errorf("chart archive digest does not match the one in the index file (%s): %s", digest, u),
+		)
+	}
+	return nil
+}
+
+// parseMaintenanceAnnotation parses the value of the maintenance annotation
+// provided, which is expected to be a yaml document with the supportedVersions
+// (i.e. ">=2.0.0, <3.0.0") and eol (i.e. 2021-06-30) keys. An error is
+// returned when the supported versions are not a valid semver constraint or
+// the eol is not a valid date.
+func parseMaintenanceAnnotation(v string) (*hub.Maintenance, error) {
+	var m struct {
+		SupportedVersions string `yaml:"supportedVersions"`
+		EOL               string `yaml:"eol"`
+	}
+	if err := yaml.Unmarshal([]byte(v), &m); err != nil {
+		return nil, err
+	}
+	if m.SupportedVersions == "" && m.EOL == "" {
+		return nil, nil
+	}
+	if m.SupportedVersions != "" {
+		if _, err := semver.NewConstraint(m.SupportedVersions); err != nil {
+			return nil, fmt.Errorf("invalid supported versions %s: %w", m.SupportedVersions, err)
+		}
+	}
+	if m.EOL != "" {
+		if _, err := time.Parse("2006-01-02", m.EOL); err != nil {
+			return nil, fmt.Errorf("invalid eol %s: %w", m.EOL, err)
+		}
+	}
+	return &hub.Maintenance{
+		SupportedVersions: m.SupportedVersions,
+		EOL:               m.EOL,
+	}, nil
+}
+
+// parseOperatorAnnotations returns whether the chart provided is an operator
+// and the capability level it declares. Charts declaring a valid capability
+// level are considered operators. When the operator annotation is not
+// provided, charts whose name contains "operator" are considered operators as
+// well. Invalid annotations are ignored, returning an error describing them.
+func parseOperatorAnnotations(md *chart.Metadata) (bool, string, error) {
+	var isOperator bool
+	var capabilities string
+	var errs []string
+
+	// Capabilities
+	if v := strings.TrimSpace(md.Annotations[operatorCapabilitiesAnnotation]); v != "" {
+		for _, c := range hub.OperatorCapabilities {
+			if strings.EqualFold(v, c) {
+				capabilities = c
+				isOperator = true
+				break
+			}
+		}
+		if capabilities == "" {
+			errs = append(errs, fmt.Sprintf("invalid capabilities: %s", v))
+		}
+	}
+
+	// Operator
+	if v, ok := md.Annotations[operatorAnnotation]; ok {
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("invalid operator value: %s", v))
+		} else {
+			isOperator = b
+			if !b {
+				capabilities = ""
+			}
+		}
+	} else if strings.Contains(strings.ToLower(md.Name), "operator") {
+		isOperator = true
+	}
+
+	if len(errs) > 0 {
+		return isOperator, capabilities, errors.New(strings.Join(errs, ", "))
+	}
+	return isOperator, capabilities, nil
 }
 
 // getFile returns the file requested from the provided chart.
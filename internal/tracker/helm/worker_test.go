@@ -1,26 +1,37 @@
 package helm
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"image"
 	"io/ioutil"
 	"net/http"
 	"os"
 	"path"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/img"
 	"github.com/artifacthub/hub/internal/pkg"
 	"github.com/artifacthub/hub/internal/tracker"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/clearsign"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/repo"
 )
 
 func TestWorker(t *testing.T) {
 	logoImageURL := "http://icon.url"
+	logoImageData, _ := ioutil.ReadFile("testdata/red-dot.png")
 
 	t.Run("handle register job", func(t *testing.T) {
 		pkg1V1 := &repo.ChartVersion{
@@ -52,7 +63,11 @@ func TestWorker(t *testing.T) {
 			ww := newWorkerWrapper(context.Background())
 			ww.queue <- job
 			close(ww.queue)
-			ww.hg.On("Get", job.ChartVersion.URLs[0]).Return(nil, errFake)
+			ww.hc.On("Do", job.ChartVersion.URLs[0]).Return(nil, errFake)
+			ww.hc.On("Do", job.ChartVersion.URLs[0]+".prov").Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusNotFound,
+			}, nil)
 			ww.ec.On("Append", ww.w.r.RepositoryID, mock.Anything).Return()
 
 			// Run worker and check expectations
@@ -65,7 +80,11 @@ func TestWorker(t *testing.T) {
 			ww := newWorkerWrapper(context.Background())
 			ww.queue <- job
 			close(ww.queue)
-			ww.hg.On("Get", job.ChartVersion.URLs[0]).Return(&http.Response{
+			ww.hc.On("Do", job.ChartVersion.URLs[0]).Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusNotFound,
+			}, nil)
+			ww.hc.On("Do", job.ChartVersion.URLs[0]+".prov").Return(&http.Response{
 				Body:       ioutil.NopCloser(strings.NewReader("")),
 				StatusCode: http.StatusNotFound,
 			}, nil)
@@ -82,12 +101,12 @@ func TestWorker(t *testing.T) {
 			ww.queue <- job
 			close(ww.queue)
 			f, _ := os.Open("testdata/" + path.Base(job.ChartVersion.URLs[0]))
-			ww.hg.On("Get", job.ChartVersion.URLs[0]).Return(&http.Response{
+			ww.hc.On("Do", job.ChartVersion.URLs[0]).Return(&http.Response{
 				Body:       f,
 				StatusCode: http.StatusOK,
 			}, nil)
-			ww.hg.On("Get", logoImageURL).Return(nil, errFake)
-			ww.hg.On("Get", job.ChartVersion.URLs[0]+".prov").Return(&http.Response{
+			ww.hc.On("Do", logoImageURL).Return(nil, errFake)
+			ww.hc.On("Do", job.ChartVersion.URLs[0]+".prov").Return(&http.Response{
 				Body:       ioutil.NopCloser(strings.NewReader("")),
 				StatusCode: http.StatusNotFound,
 			}, nil)
@@ -105,15 +124,67 @@ func TestWorker(t *testing.T) {
 			ww.queue <- job
 			close(ww.queue)
 			f, _ := os.Open("testdata/" + path.Base(job.ChartVersion.URLs[0]))
-			ww.hg.On("Get", job.ChartVersion.URLs[0]).Return(&http.Response{
+			ww.hc.On("Do", job.ChartVersion.URLs[0]).Return(&http.Response{
 				Body:       f,
 				StatusCode: http.StatusOK,
 			}, nil)
-			ww.hg.On("Get", logoImageURL).Return(&http.Response{
+			ww.hc.On("Do", logoImageURL).Return(&http.Response{
 				Body:       ioutil.NopCloser(strings.NewReader("")),
 				StatusCode: http.StatusUnauthorized,
 			}, nil)
-			ww.hg.On("Get", job.ChartVersion.URLs[0]+".prov").Return(&http.Response{
+			ww.hc.On("Do", job.ChartVersion.URLs[0]+".prov").Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusNotFound,
+			}, nil)
+			ww.ec.On("Append", ww.w.r.RepositoryID, mock.Anything).Return()
+			ww.pm.On("Register", mock.Anything, mock.Anything).Return(nil)
+
+			// Run worker and check expectations
+			ww.w.Run(ww.wg, ww.queue)
+			ww.assertExpectations(t)
+		})
+
+		t.Run("invalid logo image", func(t *testing.T) {
+			// Setup worker and expectations
+			ww := newWorkerWrapper(context.Background())
+			ww.queue <- job
+			close(ww.queue)
+			f, _ := os.Open("testdata/" + path.Base(job.ChartVersion.URLs[0]))
+			ww.hc.On("Do", job.ChartVersion.URLs[0]).Return(&http.Response{
+				Body:       f,
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.hc.On("Do", logoImageURL).Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("<html><body>Not found</body></html>")),
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.hc.On("Do", job.ChartVersion.URLs[0]+".prov").Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusNotFound,
+			}, nil)
+			ww.is.On("SaveImage", mock.Anything, []byte("<html><body>Not found</body></html>")).Return("", image.ErrFormat)
+			ww.pm.On("Register", mock.Anything, mock.Anything).Return(nil)
+
+			// Run worker and check expectations
+			ww.w.Run(ww.wg, ww.queue)
+			ww.assertExpectations(t)
+		})
+
+		t.Run("logo image too large", func(t *testing.T) {
+			// Setup worker and expectations
+			ww := newWorkerWrapper(context.Background())
+			ww.queue <- job
+			close(ww.queue)
+			f, _ := os.Open("testdata/" + path.Base(job.ChartVersion.URLs[0]))
+			ww.hc.On("Do", job.ChartVersion.URLs[0]).Return(&http.Response{
+				Body:       f,
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.hc.On("Do", logoImageURL).Return(&http.Response{
+				Body:       ioutil.NopCloser(bytes.NewReader(make([]byte, img.DefaultLimits().MaxSize+1))),
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.hc.On("Do", job.ChartVersion.URLs[0]+".prov").Return(&http.Response{
 				Body:       ioutil.NopCloser(strings.NewReader("")),
 				StatusCode: http.StatusNotFound,
 			}, nil)
@@ -131,20 +202,52 @@ func TestWorker(t *testing.T) {
 			ww.queue <- job
 			close(ww.queue)
 			f, _ := os.Open("testdata/" + path.Base(job.ChartVersion.URLs[0]))
-			ww.hg.On("Get", job.ChartVersion.URLs[0]).Return(&http.Response{
+			ww.hc.On("Do", job.ChartVersion.URLs[0]).Return(&http.Response{
 				Body:       f,
 				StatusCode: http.StatusOK,
 			}, nil)
-			ww.hg.On("Get", logoImageURL).Return(&http.Response{
-				Body:       ioutil.NopCloser(strings.NewReader("imageData")),
+			ww.hc.On("Do", logoImageURL).Return(&http.Response{
+				Body:       ioutil.NopCloser(bytes.NewReader(logoImageData)),
 				StatusCode: http.StatusOK,
 			}, nil)
 			ww.ec.On("Append", ww.w.r.RepositoryID, mock.Anything).Return()
-			ww.hg.On("Get", job.ChartVersion.URLs[0]+".prov").Return(&http.Response{
+			ww.hc.On("Do", job.ChartVersion.URLs[0]+".prov").Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusNotFound,
+			}, nil)
+			ww.is.On("SaveImage", mock.Anything, logoImageData).Return("", errFake)
+			ww.pm.On("Register", mock.Anything, mock.Anything).Return(nil)
+
+			// Run worker and check expectations
+			ww.w.Run(ww.wg, ww.queue)
+			ww.assertExpectations(t)
+		})
+
+		t.Run("panic storing logo image recovered, package registered", func(t *testing.T) {
+			// Setup worker and expectations
+			ww := newWorkerWrapper(context.Background())
+			ww.queue <- job
+			close(ww.queue)
+			f, _ := os.Open("testdata/" + path.Base(job.ChartVersion.URLs[0]))
+			ww.hc.On("Do", job.ChartVersion.URLs[0]).Return(&http.Response{
+				Body:       f,
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.hc.On("Do", logoImageURL).Return(&http.Response{
+				Body:       ioutil.NopCloser(bytes.NewReader(logoImageData)),
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.hc.On("Do", job.ChartVersion.URLs[0]+".prov").Return(&http.Response{
 				Body:       ioutil.NopCloser(strings.NewReader("")),
 				StatusCode: http.StatusNotFound,
 			}, nil)
-			ww.is.On("SaveImage", mock.Anything, []byte("imageData")).Return("", errFake)
+			ww.is.On("SaveImage", mock.Anything, logoImageData).Run(func(args mock.Arguments) {
+				panic("fake panic")
+			})
+			ww.ec.On("Append", ww.w.r.RepositoryID, mock.MatchedBy(func(err error) bool {
+				return strings.Contains(err.Error(), "panic storing logo") &&
+					strings.Contains(err.Error(), "fake panic")
+			})).Return()
 			ww.pm.On("Register", mock.Anything, mock.Anything).Return(nil)
 
 			// Run worker and check expectations
@@ -158,19 +261,19 @@ func TestWorker(t *testing.T) {
 			ww.queue <- job
 			close(ww.queue)
 			f, _ := os.Open("testdata/" + path.Base(job.ChartVersion.URLs[0]))
-			ww.hg.On("Get", job.ChartVersion.URLs[0]).Return(&http.Response{
+			ww.hc.On("Do", job.ChartVersion.URLs[0]).Return(&http.Response{
 				Body:       f,
 				StatusCode: http.StatusOK,
 			}, nil)
-			ww.hg.On("Get", logoImageURL).Return(&http.Response{
-				Body:       ioutil.NopCloser(strings.NewReader("imageData")),
+			ww.hc.On("Do", logoImageURL).Return(&http.Response{
+				Body:       ioutil.NopCloser(bytes.NewReader(logoImageData)),
 				StatusCode: http.StatusOK,
 			}, nil)
-			ww.hg.On("Get", job.ChartVersion.URLs[0]+".prov").Return(&http.Response{
+			ww.hc.On("Do", job.ChartVersion.URLs[0]+".prov").Return(&http.Response{
 				Body:       ioutil.NopCloser(strings.NewReader("")),
 				StatusCode: http.StatusNotFound,
 			}, nil)
-			ww.is.On("SaveImage", mock.Anything, []byte("imageData")).Return("imageID", nil)
+			ww.is.On("SaveImage", mock.Anything, logoImageData).Return("imageID", nil)
 			ww.pm.On("Register", mock.Anything, mock.Anything).Return(errFake)
 			ww.ec.On("Append", ww.w.r.RepositoryID, mock.Anything).Return()
 
@@ -179,25 +282,55 @@ func TestWorker(t *testing.T) {
 			ww.assertExpectations(t)
 		})
 
+		t.Run("package rejected by validator", func(t *testing.T) {
+			// Setup worker and expectations
+			ww := newWorkerWrapper(context.Background())
+			pv := &tracker.PackageValidatorMock{}
+			ww.w.svc.Pv = pv
+			ww.queue <- job
+			close(ww.queue)
+			f, _ := os.Open("testdata/" + path.Base(job.ChartVersion.URLs[0]))
+			ww.hc.On("Do", job.ChartVersion.URLs[0]).Return(&http.Response{
+				Body:       f,
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.hc.On("Do", logoImageURL).Return(&http.Response{
+				Body:       ioutil.NopCloser(bytes.NewReader(logoImageData)),
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.hc.On("Do", job.ChartVersion.URLs[0]+".prov").Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusNotFound,
+			}, nil)
+			ww.is.On("SaveImage", mock.Anything, logoImageData).Return("imageID", nil)
+			pv.On("Validate", mock.Anything).Return(errFake)
+			ww.ec.On("Append", ww.w.r.RepositoryID, mock.Anything).Return()
+
+			// Run worker and check expectations
+			ww.w.Run(ww.wg, ww.queue)
+			ww.assertExpectations(t)
+			pv.AssertExpectations(t)
+		})
+
 		t.Run("package registered successfully", func(t *testing.T) {
 			// Setup worker and expectations
 			ww := newWorkerWrapper(context.Background())
 			ww.queue <- job
 			close(ww.queue)
 			f, _ := os.Open("testdata/" + path.Base(job.ChartVersion.URLs[0]))
-			ww.hg.On("Get", job.ChartVersion.URLs[0]).Return(&http.Response{
+			ww.hc.On("Do", job.ChartVersion.URLs[0]).Return(&http.Response{
 				Body:       f,
 				StatusCode: http.StatusOK,
 			}, nil)
-			ww.hg.On("Get", logoImageURL).Return(&http.Response{
-				Body:       ioutil.NopCloser(strings.NewReader("imageData")),
+			ww.hc.On("Do", logoImageURL).Return(&http.Response{
+				Body:       ioutil.NopCloser(bytes.NewReader(logoImageData)),
 				StatusCode: http.StatusOK,
 			}, nil)
-			ww.hg.On("Get", job.ChartVersion.URLs[0]+".prov").Return(&http.Response{
+			ww.hc.On("Do", job.ChartVersion.URLs[0]+".prov").Return(&http.Response{
 				Body:       ioutil.NopCloser(strings.NewReader("")),
 				StatusCode: http.StatusNotFound,
 			}, nil)
-			ww.is.On("SaveImage", mock.Anything, []byte("imageData")).Return("imageID", nil)
+			ww.is.On("SaveImage", mock.Anything, logoImageData).Return("imageID", nil)
 			ww.pm.On("Register", mock.Anything, mock.Anything).Return(nil)
 
 			// Run worker and check expectations
@@ -205,6 +338,169 @@ func TestWorker(t *testing.T) {
 			ww.assertExpectations(t)
 		})
 
+		t.Run("package registered successfully with install instructions", func(t *testing.T) {
+			// Setup worker and expectations
+			ww := newWorkerWrapper(context.Background())
+			ww.w.r.Name = "repo1"
+			ww.w.r.URL = "https://repo1.url"
+			ww.queue <- job
+			close(ww.queue)
+			f, _ := os.Open("testdata/" + path.Base(job.ChartVersion.URLs[0]))
+			ww.hc.On("Do", job.ChartVersion.URLs[0]).Return(&http.Response{
+				Body:       f,
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.hc.On("Do", logoImageURL).Return(&http.Response{
+				Body:       ioutil.NopCloser(bytes.NewReader(logoImageData)),
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.hc.On("Do", job.ChartVersion.URLs[0]+".prov").Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusNotFound,
+			}, nil)
+			ww.is.On("SaveImage", mock.Anything, logoImageData).Return("imageID", nil)
+			ww.pm.On("Register", mock.Anything, mock.MatchedBy(func(p *hub.Package) bool {
+				return strings.Contains(p.Install, "helm repo add repo1 https://repo1.url") &&
+					strings.Contains(p.Install, "helm install my-pkg1 repo1/pkg1 --version 1.0.0")
+			})).Return(nil)
+
+			// Run worker and check expectations
+			ww.w.Run(ww.wg, ww.queue)
+			ww.assertExpectations(t)
+		})
+
+		t.Run("signed package registered successfully", func(t *testing.T) {
+			// Setup worker and expectations
+			ww := newWorkerWrapper(context.Background())
+			ww.queue <- job
+			close(ww.queue)
+			entity, _ := openpgp.NewEntity("user1", "", "user1@email.com", nil)
+			var prov bytes.Buffer
+			pw, _ := clearsign.Encode(&prov, entity.PrivateKey, nil)
+			_, _ = pw.Write([]byte("name: pkg1\nversion: 1.0.0\n"))
+			_ = pw.Close()
+			f, _ := os.Open("testdata/" + path.Base(job.ChartVersion.URLs[0]))
+			ww.hc.On("Do", job.ChartVersion.URLs[0]).Return(&http.Response{
+				Body:       f,
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.hc.On("Do", logoImageURL).Return(&http.Response{
+				Body:       ioutil.NopCloser(bytes.NewReader(logoImageData)),
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.hc.On("Do", job.ChartVersion.URLs[0]+".prov").Return(&http.Response{
+				Body:       ioutil.NopCloser(bytes.NewReader(prov.Bytes())),
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.is.On("SaveImage", mock.Anything, logoImageData).Return("imageID", nil)
+			ww.pm.On("Register", mock.Anything, mock.MatchedBy(func(p *hub.Package) bool {
+				return p.Signed &&
+					p.SignKey != nil &&
+					p.SignKey.KeyID == fmt.Sprintf("%016X", entity.PrimaryKey.KeyId)
+			})).Return(nil)
+
+			// Run worker and check expectations
+			ww.w.Run(ww.wg, ww.queue)
+			ww.assertExpectations(t)
+		})
+
+		t.Run("provenance file and logo fetched concurrently", func(t *testing.T) {
+			// Setup worker and expectations
+			ww := newWorkerWrapper(context.Background())
+			ww.queue <- job
+			close(ww.queue)
+			var fetching sync.WaitGroup
+			fetching.Add(2)
+			waitForOtherFetch := func(mock.Arguments) {
+				fetching.Done()
+				done := make(chan struct{})
+				go func() {
+					fetching.Wait()
+					close(done)
+				}()
+				select {
+				case <-done:
+				case <-time.After(5 * time.Second):
+					t.Error("provenance file and logo not fetched concurrently")
+				}
+			}
+			f, _ := os.Open("testdata/" + path.Base(job.ChartVersion.URLs[0]))
+			ww.hc.On("Do", job.ChartVersion.URLs[0]).Return(&http.Response{
+				Body:       f,
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.hc.On("Do", logoImageURL).Run(waitForOtherFetch).Return(&http.Response{
+				Body:       ioutil.NopCloser(bytes.NewReader(logoImageData)),
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.hc.On("Do", job.ChartVersion.URLs[0]+".prov").Run(waitForOtherFetch).Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusNotFound,
+			}, nil)
+			ww.is.On("SaveImage", mock.Anything, logoImageData).Return("imageID", nil)
+			ww.pm.On("Register", mock.Anything, mock.MatchedBy(func(p *hub.Package) bool {
+				return !p.Signed && p.LogoImageID == "imageID"
+			})).Return(nil)
+
+			// Run worker and check expectations
+			ww.w.Run(ww.wg, ww.queue)
+			ww.assertExpectations(t)
+		})
+
+		t.Run("error getting provenance file, package registered with logo", func(t *testing.T) {
+			// Setup worker and expectations
+			ww := newWorkerWrapper(context.Background())
+			ww.queue <- job
+			close(ww.queue)
+			f, _ := os.Open("testdata/" + path.Base(job.ChartVersion.URLs[0]))
+			ww.hc.On("Do", job.ChartVersion.URLs[0]).Return(&http.Response{
+				Body:       f,
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.hc.On("Do", logoImageURL).Return(&http.Response{
+				Body:       ioutil.NopCloser(bytes.NewReader(logoImageData)),
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.hc.On("Do", job.ChartVersion.URLs[0]+".prov").Return(nil, errFake)
+			ww.is.On("SaveImage", mock.Anything, logoImageData).Return("imageID", nil)
+			ww.pm.On("Register", mock.Anything, mock.MatchedBy(func(p *hub.Package) bool {
+				return !p.Signed && p.SignKey == nil && p.LogoImageID == "imageID"
+			})).Return(nil)
+
+			// Run worker and check expectations
+			ww.w.Run(ww.wg, ww.queue)
+			ww.assertExpectations(t)
+		})
+
+		t.Run("package registered successfully and logo enqueued", func(t *testing.T) {
+			// Setup worker and expectations
+			ww := newWorkerWrapper(context.Background())
+			lq := &tracker.LogosQueueMock{}
+			ww.w.svc.Lq = lq
+			ww.queue <- job
+			close(ww.queue)
+			f, _ := os.Open("testdata/" + path.Base(job.ChartVersion.URLs[0]))
+			ww.hc.On("Do", job.ChartVersion.URLs[0]).Return(&http.Response{
+				Body:       f,
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.hc.On("Do", job.ChartVersion.URLs[0]+".prov").Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusNotFound,
+			}, nil)
+			ww.pm.On("Register", mock.Anything, mock.MatchedBy(func(p *hub.Package) bool {
+				return p.LogoURL == logoImageURL && p.LogoImageID == ""
+			})).Return(nil)
+			lq.On("Enqueue", mock.MatchedBy(func(j *tracker.LogoJob) bool {
+				return j.Package.Name == "pkg1" && j.Package.LogoURL == logoImageURL
+			})).Return()
+
+			// Run worker and check expectations
+			ww.w.Run(ww.wg, ww.queue)
+			ww.assertExpectations(t)
+			lq.AssertExpectations(t)
+		})
+
 		t.Run("package with logo in data url registered successfully", func(t *testing.T) {
 			// Setup worker and expectations
 			ww := newWorkerWrapper(context.Background())
@@ -216,11 +512,11 @@ func TestWorker(t *testing.T) {
 			ww.queue <- job
 			close(ww.queue)
 			f, _ := os.Open("testdata/" + path.Base(job.ChartVersion.URLs[0]))
-			ww.hg.On("Get", job.ChartVersion.URLs[0]).Return(&http.Response{
+			ww.hc.On("Do", job.ChartVersion.URLs[0]).Return(&http.Response{
 				Body:       f,
 				StatusCode: http.StatusOK,
 			}, nil)
-			ww.hg.On("Get", job.ChartVersion.URLs[0]+".prov").Return(&http.Response{
+			ww.hc.On("Do", job.ChartVersion.URLs[0]+".prov").Return(&http.Response{
 				Body:       ioutil.NopCloser(strings.NewReader("")),
 				StatusCode: http.StatusNotFound,
 			}, nil)
@@ -232,6 +528,384 @@ func TestWorker(t *testing.T) {
 			ww.w.Run(ww.wg, ww.queue)
 			ww.assertExpectations(t)
 		})
+
+		t.Run("package registered successfully using cached chart archive", func(t *testing.T) {
+			// Setup charts cache
+			cacheDir, _ := ioutil.TempDir("", "artifact-hub-test")
+			defer os.RemoveAll(cacheDir)
+			chartData, _ := ioutil.ReadFile("testdata/pkg1-1.0.0.tgz")
+			digest := computeChartDigest(chartData)
+			cache := newChartsCache(cacheDir)
+			_ = cache.put(digest, chartData)
+
+			// Setup worker and expectations
+			ww := newWorkerWrapper(context.Background())
+			ww.w.cache = cache
+			job := &Job{
+				Kind: Register,
+				ChartVersion: &repo.ChartVersion{
+					Metadata: pkg1V1.Metadata,
+					URLs:     pkg1V1.URLs,
+					Digest:   digest,
+				},
+			}
+			ww.queue <- job
+			close(ww.queue)
+			ww.hc.On("Do", job.ChartVersion.URLs[0]+".prov").Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusNotFound,
+			}, nil)
+			ww.pm.On("Register", mock.Anything, mock.MatchedBy(func(p *hub.Package) bool {
+				return p.Name == "pkg1" && p.Digest == digest
+			})).Return(nil)
+
+			// Run worker and check expectations
+			ww.w.Run(ww.wg, ww.queue)
+			ww.assertExpectations(t)
+		})
+
+		t.Run("chart extraction cached after registering package", func(t *testing.T) {
+			// Setup charts cache
+			cacheDir, _ := ioutil.TempDir("", "artifact-hub-test")
+			defer os.RemoveAll(cacheDir)
+			chartData, _ := ioutil.ReadFile("testdata/pkg1-1.0.0.tgz")
+			digest := computeChartDigest(chartData)
+			cache := newChartsCache(cacheDir)
+
+			// Setup worker and expectations
+			ww := newWorkerWrapper(context.Background())
+			ww.w.cache = cache
+			job := &Job{
+				Kind: Register,
+				ChartVersion: &repo.ChartVersion{
+					Metadata: pkg1V1.Metadata,
+					URLs:     pkg1V1.URLs,
+					Digest:   digest,
+				},
+			}
+			ww.queue <- job
+			close(ww.queue)
+			ww.hc.On("Do", job.ChartVersion.URLs[0]).Return(&http.Response{
+				Body:       ioutil.NopCloser(bytes.NewReader(chartData)),
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.hc.On("Do", job.ChartVersion.URLs[0]+".prov").Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusNotFound,
+			}, nil)
+			var registeredPkg *hub.Package
+			ww.pm.On("Register", mock.Anything, mock.MatchedBy(func(p *hub.Package) bool {
+				registeredPkg = p
+				return p.Name == "pkg1"
+			})).Return(nil)
+
+			// Run worker and check expectations
+			ww.w.Run(ww.wg, ww.queue)
+			ww.assertExpectations(t)
+			e, ok := cache.getExtraction(digest)
+			assert.True(t, ok)
+			assert.Equal(t, registeredPkg.Readme, e.Readme)
+			assert.Equal(t, registeredPkg.ContainersImages, e.ContainersImages)
+		})
+
+		t.Run("package registered successfully using cached chart extraction", func(t *testing.T) {
+			// Setup charts cache
+			cacheDir, _ := ioutil.TempDir("", "artifact-hub-test")
+			defer os.RemoveAll(cacheDir)
+			chartData, _ := ioutil.ReadFile("testdata/pkg1-1.0.0.tgz")
+			digest := computeChartDigest(chartData)
+			cache := newChartsCache(cacheDir)
+			_ = cache.put(digest, chartData)
+			_ = cache.putExtraction(digest, &chartExtraction{
+				Version:       chartExtractionVersion,
+				Readme:        "cached readme",
+				Changelog:     "cached changelog",
+				DefaultValues: "cached: true\n",
+			})
+
+			// Setup worker and expectations
+			ww := newWorkerWrapper(context.Background())
+			ww.w.cache = cache
+			job := &Job{
+				Kind: Register,
+				ChartVersion: &repo.ChartVersion{
+					Metadata: pkg1V1.Metadata,
+					URLs:     pkg1V1.URLs,
+					Digest:   digest,
+				},
+			}
+			ww.queue <- job
+			close(ww.queue)
+			ww.hc.On("Do", job.ChartVersion.URLs[0]+".prov").Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusNotFound,
+			}, nil)
+			ww.pm.On("Register", mock.Anything, mock.MatchedBy(func(p *hub.Package) bool {
+				return p.Name == "pkg1" &&
+					p.Readme == "cached readme" &&
+					p.Changelog == "cached changelog" &&
+					p.Data["default_values"] == "cached: true\n"
+			})).Return(nil)
+
+			// Run worker and check expectations
+			ww.w.Run(ww.wg, ww.queue)
+			ww.assertExpectations(t)
+		})
+
+		t.Run("package registered successfully using fallback url", func(t *testing.T) {
+			// Setup worker and expectations
+			ww := newWorkerWrapper(context.Background())
+			mirrorURL := "http://mirror/pkg1-1.0.0.tgz"
+			job := &Job{
+				Kind: Register,
+				ChartVersion: &repo.ChartVersion{
+					Metadata: pkg1V1.Metadata,
+					URLs:     []string{"ftp://tests/pkg1-1.0.0.tgz", pkg1V1.URLs[0], mirrorURL},
+				},
+			}
+			ww.queue <- job
+			close(ww.queue)
+			ww.hc.On("Do", pkg1V1.URLs[0]).Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusInternalServerError,
+			}, nil)
+			ww.hc.On("Do", pkg1V1.URLs[0]+".prov").Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusNotFound,
+			}, nil)
+			f, _ := os.Open("testdata/pkg1-1.0.0.tgz")
+			ww.hc.On("Do", mirrorURL).Return(&http.Response{
+				Body:       f,
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.hc.On("Do", mirrorURL+".prov").Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.pm.On("Register", mock.Anything, mock.MatchedBy(func(p *hub.Package) bool {
+				return p.Name == "pkg1" && p.ContentURL == mirrorURL && p.Signed
+			})).Return(nil)
+
+			// Run worker and check expectations
+			ww.w.Run(ww.wg, ww.queue)
+			ww.assertExpectations(t)
+		})
+
+		t.Run("error downloading chart from all urls", func(t *testing.T) {
+			// Setup worker and expectations
+			ww := newWorkerWrapper(context.Background())
+			mirrorURL := "http://mirror/pkg1-1.0.0.tgz"
+			job := &Job{
+				Kind: Register,
+				ChartVersion: &repo.ChartVersion{
+					Metadata: pkg1V1.Metadata,
+					URLs:     []string{pkg1V1.URLs[0], mirrorURL},
+				},
+			}
+			ww.queue <- job
+			close(ww.queue)
+			ww.hc.On("Do", pkg1V1.URLs[0]).Return(nil, errFake)
+			ww.hc.On("Do", pkg1V1.URLs[0]+".prov").Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusNotFound,
+			}, nil)
+			ww.hc.On("Do", mirrorURL).Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusNotFound,
+			}, nil)
+			ww.ec.On("Append", ww.w.r.RepositoryID, mock.MatchedBy(func(err error) bool {
+				var e *tracker.Error
+				return errors.As(err, &e) && e.Code == tracker.ErrCodeArchiveNotFound
+			})).Return()
+
+			// Run worker and check expectations
+			ww.w.Run(ww.wg, ww.queue)
+			ww.assertExpectations(t)
+		})
+
+		t.Run("chart archive digest mismatch", func(t *testing.T) {
+			// Setup worker and expectations
+			ww := newWorkerWrapper(context.Background())
+			job := &Job{
+				Kind: Register,
+				ChartVersion: &repo.ChartVersion{
+					Metadata: pkg1V1.Metadata,
+					URLs:     pkg1V1.URLs,
+					Digest:   computeChartDigest([]byte("tampered")),
+				},
+			}
+			ww.queue <- job
+			close(ww.queue)
+			f, _ := os.Open("testdata/pkg1-1.0.0.tgz")
+			ww.hc.On("Do", pkg1V1.URLs[0]).Return(&http.Response{
+				Body:       f,
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.hc.On("Do", pkg1V1.URLs[0]+".prov").Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusNotFound,
+			}, nil)
+			ww.ec.On("Append", ww.w.r.RepositoryID, mock.MatchedBy(func(err error) bool {
+				var e *tracker.Error
+				return errors.As(err, &e) && e.Code == tracker.ErrCodeDigestMismatch
+			})).Return()
+
+			// Run worker and check expectations
+			ww.w.Run(ww.wg, ww.queue)
+			ww.assertExpectations(t)
+		})
+
+		t.Run("chart archive too large", func(t *testing.T) {
+			testCases := []struct {
+				desc           string
+				maxArchiveSize int64
+				contentLength  int64
+			}{
+				{"content length exceeds max archive size", 1024 * 1024, 1024*1024 + 1},
+				{"archive exceeds max archive size", 10, -1},
+				{"uncompressed content exceeds max size", 500, -1},
+			}
+			for _, tc := range testCases {
+				tc := tc
+				t.Run(tc.desc, func(t *testing.T) {
+					// Setup worker and expectations
+					ww := newWorkerWrapper(context.Background())
+					ww.w.maxArchiveSize = tc.maxArchiveSize
+					ww.queue <- job
+					close(ww.queue)
+					f, _ := os.Open("testdata/pkg1-1.0.0.tgz")
+					ww.hc.On("Do", pkg1V1.URLs[0]).Return(&http.Response{
+						Body:          f,
+						ContentLength: tc.contentLength,
+						StatusCode:    http.StatusOK,
+					}, nil)
+					ww.hc.On("Do", pkg1V1.URLs[0]+".prov").Return(&http.Response{
+						Body:       ioutil.NopCloser(strings.NewReader("")),
+						StatusCode: http.StatusNotFound,
+					}, nil)
+					ww.ec.On("Append", ww.w.r.RepositoryID, mock.MatchedBy(func(err error) bool {
+						var e *tracker.Error
+						return errors.As(err, &e) && e.Code == tracker.ErrCodeArchiveTooLarge
+					})).Return()
+
+					// Run worker and check expectations
+					ww.w.Run(ww.wg, ww.queue)
+					ww.assertExpectations(t)
+				})
+			}
+		})
+
+		t.Run("package registered successfully after verifying digest", func(t *testing.T) {
+			// Setup worker and expectations
+			ww := newWorkerWrapper(context.Background())
+			chartData, _ := ioutil.ReadFile("testdata/pkg1-1.0.0.tgz")
+			digest := "sha256:" + computeChartDigest(chartData)
+			job := &Job{
+				Kind: Register,
+				ChartVersion: &repo.ChartVersion{
+					Metadata: pkg1V1.Metadata,
+					URLs:     pkg1V1.URLs,
+					Digest:   digest,
+				},
+			}
+			ww.queue <- job
+			close(ww.queue)
+			ww.hc.On("Do", pkg1V1.URLs[0]).Return(&http.Response{
+				Body:       ioutil.NopCloser(bytes.NewReader(chartData)),
+				StatusCode: http.StatusOK,
+			}, nil)
+			ww.hc.On("Do", pkg1V1.URLs[0]+".prov").Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusNotFound,
+			}, nil)
+			ww.pm.On("Register", mock.Anything, mock.MatchedBy(func(p *hub.Package) bool {
+				return p.Name == "pkg1" && p.Digest == digest
+			})).Return(nil)
+
+			// Run worker and check expectations
+			ww.w.Run(ww.wg, ww.queue)
+			ww.assertExpectations(t)
+		})
+
+		t.Run("package in local repository registered successfully", func(t *testing.T) {
+			// Setup worker and expectations
+			ww := newWorkerWrapper(context.Background())
+			testdataPath, _ := filepath.Abs("testdata")
+			ww.w.r.URL = "file://" + testdataPath
+			job := &Job{
+				Kind: Register,
+				ChartVersion: &repo.ChartVersion{
+					Metadata: pkg2V1.Metadata,
+					URLs:     []string{"pkg2-1.0.0.tgz"},
+				},
+				StoreLogo: true,
+			}
+			ww.queue <- job
+			close(ww.queue)
+			expectedLogoData, _ := ioutil.ReadFile("testdata/red-dot.png")
+			ww.is.On("SaveImage", mock.Anything, expectedLogoData).Return("imageID", nil)
+			ww.pm.On("Register", mock.Anything, mock.MatchedBy(func(p *hub.Package) bool {
+				return p.Name == "pkg2" && !p.Signed
+			})).Return(nil)
+
+			// Run worker and check expectations
+			ww.w.Run(ww.wg, ww.queue)
+			ww.assertExpectations(t)
+		})
+
+		t.Run("chart archive outside of local repository", func(t *testing.T) {
+			// Setup worker and expectations
+			ww := newWorkerWrapper(context.Background())
+			testdataPath, _ := filepath.Abs("testdata")
+			ww.w.r.URL = "file://" + testdataPath
+			job := &Job{
+				Kind: Register,
+				ChartVersion: &repo.ChartVersion{
+					Metadata: pkg1V1.Metadata,
+					URLs:     []string{"file:///etc/pkg1-1.0.0.tgz"},
+				},
+			}
+			ww.queue <- job
+			close(ww.queue)
+			ww.ec.On("Append", ww.w.r.RepositoryID, mock.Anything).Return()
+
+			// Run worker and check expectations
+			ww.w.Run(ww.wg, ww.queue)
+			ww.assertExpectations(t)
+		})
+
+		t.Run("chart download retried after transient error", func(t *testing.T) {
+			// Setup worker and expectations
+			ww := newWorkerWrapper(context.Background())
+			ww.w.retries = 2
+			ww.w.retryDelay = time.Millisecond
+			ww.queue <- &Job{
+				Kind:         Register,
+				ChartVersion: pkg1V1,
+			}
+			close(ww.queue)
+			ww.hc.On("Do", pkg1V1.URLs[0]).Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusServiceUnavailable,
+			}, nil).Once()
+			ww.hc.On("Do", pkg1V1.URLs[0]).Return(nil, errFake).Once()
+			f, _ := os.Open("testdata/pkg1-1.0.0.tgz")
+			ww.hc.On("Do", pkg1V1.URLs[0]).Return(&http.Response{
+				Body:       f,
+				StatusCode: http.StatusOK,
+			}, nil).Once()
+			ww.hc.On("Do", pkg1V1.URLs[0]+".prov").Return(&http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+				StatusCode: http.StatusNotFound,
+			}, nil)
+			ww.pm.On("Register", mock.Anything, mock.MatchedBy(func(p *hub.Package) bool {
+				return p.Name == "pkg1" && p.ContentURL == pkg1V1.URLs[0]
+			})).Return(nil)
+
+			// Run worker and check expectations
+			ww.w.Run(ww.wg, ww.queue)
+			ww.assertExpectations(t)
+		})
 	})
 
 	t.Run("handle unregister job", func(t *testing.T) {
@@ -269,13 +943,26 @@ func TestWorker(t *testing.T) {
 			ww.w.Run(ww.wg, ww.queue)
 			ww.assertExpectations(t)
 		})
-	})
-}
 
-func withHTTPGetter(hg HTTPGetter) func(w *Worker) {
-	return func(w *Worker) {
-		w.hg = hg
-	}
+		t.Run("panic handling job recovered, next job handled", func(t *testing.T) {
+			// Setup worker and expectations
+			ww := newWorkerWrapper(context.Background())
+			ww.queue <- job
+			ww.queue <- job
+			close(ww.queue)
+			ww.pm.On("Unregister", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				panic("fake panic")
+			}).Once()
+			ww.pm.On("Unregister", mock.Anything, mock.Anything).Return(nil).Once()
+			ww.ec.On("Append", ww.w.r.RepositoryID, mock.MatchedBy(func(err error) bool {
+				return strings.Contains(err.Error(), "fake panic")
+			})).Return()
+
+			// Run worker and check expectations
+			ww.w.Run(ww.wg, ww.queue)
+			ww.assertExpectations(t)
+		})
+	})
 }
 
 type workerWrapper struct {
@@ -283,7 +970,7 @@ type workerWrapper struct {
 	pm    *pkg.ManagerMock
 	is    *img.StoreMock
 	ec    *tracker.ErrorsCollectorMock
-	hg    *httpGetterMock
+	hc    *httpClientMock
 	w     *Worker
 	queue chan *Job
 }
@@ -293,7 +980,7 @@ func newWorkerWrapper(ctx context.Context) *workerWrapper {
 	pm := &pkg.ManagerMock{}
 	is := &img.StoreMock{}
 	ec := &tracker.ErrorsCollectorMock{}
-	hg := &httpGetterMock{}
+	hc := &httpClientMock{}
 	r := &hub.Repository{RepositoryID: "repo1"}
 	svc := &tracker.Services{
 		Ctx: ctx,
@@ -301,7 +988,7 @@ func newWorkerWrapper(ctx context.Context) *workerWrapper {
 		Is:  is,
 		Ec:  ec,
 	}
-	w := NewWorker(svc, r, withHTTPGetter(hg))
+	w := NewWorker(svc, r, WithHTTPClient(hc))
 	queue := make(chan *Job, 100)
 
 	// Wait group used for Worker.Run()
@@ -313,7 +1000,7 @@ func newWorkerWrapper(ctx context.Context) *workerWrapper {
 		pm:    pm,
 		is:    is,
 		ec:    ec,
-		hg:    hg,
+		hc:    hc,
 		w:     w,
 		queue: queue,
 	}
@@ -325,15 +1012,302 @@ func (ww *workerWrapper) assertExpectations(t *testing.T) {
 	ww.pm.AssertExpectations(t)
 	ww.is.AssertExpectations(t)
 	ww.ec.AssertExpectations(t)
-	ww.hg.AssertExpectations(t)
+	ww.hc.AssertExpectations(t)
 }
 
-type httpGetterMock struct {
+type httpClientMock struct {
 	mock.Mock
 }
 
-func (m *httpGetterMock) Get(url string) (*http.Response, error) {
-	args := m.Called(url)
+func (m *httpClientMock) Do(req *http.Request) (*http.Response, error) {
+	args := m.Called(req.URL.String())
 	resp, _ := args.Get(0).(*http.Response)
 	return resp, args.Error(1)
 }
+
+func TestResolveURL(t *testing.T) {
+	testCases := []struct {
+		baseURL     string
+		u           string
+		expectedURL string
+	}{
+		{
+			"https://repo.url",
+			"https://repo.url/pkg1-1.0.0.tgz",
+			"https://repo.url/pkg1-1.0.0.tgz",
+		},
+		{
+			"https://repo.url",
+			"http://icon.url",
+			"http://icon.url",
+		},
+		{
+			"https://repo.url",
+			"pkg1-1.0.0.tgz",
+			"https://repo.url/pkg1-1.0.0.tgz",
+		},
+		{
+			"https://repo.url/charts/",
+			"icons/pkg1.png",
+			"https://repo.url/charts/icons/pkg1.png",
+		},
+		{
+			"https://repo.url/charts",
+			"/icons/pkg1.png",
+			"https://repo.url/charts/icons/pkg1.png",
+		},
+		{
+			"file:///mnt/charts",
+			"pkg1-1.0.0.tgz",
+			"file:///mnt/charts/pkg1-1.0.0.tgz",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.u, func(t *testing.T) {
+			u, err := resolveURL(tc.baseURL, tc.u)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedURL, u)
+		})
+	}
+}
+
+func TestResolveDependency(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("dependency repository not supported", func(t *testing.T) {
+		ww := newWorkerWrapper(ctx)
+		dep := &chart.Dependency{Name: "dep1", Repository: "file://../dep1"}
+		assert.Equal(t, "", ww.w.resolveDependency(dep))
+		ww.pm.AssertExpectations(t)
+	})
+
+	t.Run("error resolving dependency", func(t *testing.T) {
+		ww := newWorkerWrapper(ctx)
+		ww.pm.On("Resolve", ctx, mock.Anything).Return(nil, errFake).Twice()
+		dep := &chart.Dependency{Name: "dep1", Repository: "https://repo.url"}
+		assert.Equal(t, "", ww.w.resolveDependency(dep))
+		assert.Equal(t, "", ww.w.resolveDependency(dep))
+		ww.pm.AssertExpectations(t)
+	})
+
+	t.Run("dependency matching several packages", func(t *testing.T) {
+		ww := newWorkerWrapper(ctx)
+		ww.pm.On("Resolve", ctx, mock.Anything).Return([]*hub.Package{
+			{PackageID: "pkg1"},
+			{PackageID: "pkg2"},
+		}, nil).Once()
+		dep := &chart.Dependency{Name: "dep1", Repository: "https://repo.url"}
+		assert.Equal(t, "", ww.w.resolveDependency(dep))
+		ww.pm.AssertExpectations(t)
+	})
+
+	t.Run("dependency resolved successfully", func(t *testing.T) {
+		ww := newWorkerWrapper(ctx)
+		kind := hub.Helm
+		ww.pm.On("Resolve", ctx, &hub.ResolvePackageInput{
+			RepositoryKind: &kind,
+			RepositoryURL:  "https://repo.url",
+			PackageName:    "dep1",
+		}).Return([]*hub.Package{{PackageID: "pkg1"}}, nil).Once()
+		dep := &chart.Dependency{Name: "dep1", Repository: "https://repo.url"}
+		assert.Equal(t, "pkg1", ww.w.resolveDependency(dep))
+		assert.Equal(t, "pkg1", ww.w.resolveDependency(dep))
+		ww.pm.AssertExpectations(t)
+	})
+}
+
+func TestWorkerVerifyProvenanceFile(t *testing.T) {
+	ctx := context.Background()
+	entity, _ := openpgp.NewEntity("user1", "", "user1@email.com", nil)
+	keyID := fmt.Sprintf("%016X", entity.PrimaryKey.KeyId)
+	keyURL := "https://keybase.io/user1/pgp_keys.asc"
+	chartURL := "http://tests/pkg1-1.0.0.tgz"
+	digest := computeChartDigest([]byte("chart archive data"))
+	prov := newTestProvenanceFile(t, entity, "name: pkg1\nversion: 1.0.0\n\n...\nfiles:\n  pkg1-1.0.0.tgz: sha256:"+digest+"\n")
+
+	t.Run("error getting sign key", func(t *testing.T) {
+		ww := newWorkerWrapper(ctx)
+		ww.hc.On("Do", keyURL).Return(&http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+			StatusCode: http.StatusNotFound,
+		}, nil)
+		k := &hub.SignKey{KeyID: keyID, URL: keyURL}
+		assert.Error(t, ww.w.verifyProvenanceFile(prov, k, chartURL, digest))
+		ww.hc.AssertExpectations(t)
+	})
+
+	t.Run("invalid sign key", func(t *testing.T) {
+		ww := newWorkerWrapper(ctx)
+		ww.hc.On("Do", keyURL).Return(&http.Response{
+			Body:       ioutil.NopCloser(strings.NewReader("invalid")),
+			StatusCode: http.StatusOK,
+		}, nil)
+		k := &hub.SignKey{KeyID: keyID, URL: keyURL}
+		assert.Error(t, ww.w.verifyProvenanceFile(prov, k, chartURL, digest))
+		ww.hc.AssertExpectations(t)
+	})
+
+	t.Run("chart archive digest mismatch", func(t *testing.T) {
+		ww := newWorkerWrapper(ctx)
+		ww.hc.On("Do", keyURL).Return(&http.Response{
+			Body:       ioutil.NopCloser(bytes.NewReader(newTestArmoredPublicKey(t, entity))),
+			StatusCode: http.StatusOK,
+		}, nil)
+		k := &hub.SignKey{KeyID: keyID, URL: keyURL}
+		otherDigest := computeChartDigest([]byte("other data"))
+		assert.Error(t, ww.w.verifyProvenanceFile(prov, k, chartURL, otherDigest))
+		ww.hc.AssertExpectations(t)
+	})
+
+	t.Run("provenance file verified successfully", func(t *testing.T) {
+		ww := newWorkerWrapper(ctx)
+		ww.hc.On("Do", keyURL).Return(&http.Response{
+			Body:       ioutil.NopCloser(bytes.NewReader(newTestArmoredPublicKey(t, entity))),
+			StatusCode: http.StatusOK,
+		}, nil).Once()
+		k1 := &hub.SignKey{KeyID: keyID, URL: keyURL}
+		assert.NoError(t, ww.w.verifyProvenanceFile(prov, k1, chartURL, digest))
+		assert.Equal(t, fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint), k1.Fingerprint)
+
+		// The sign key is only fetched once
+		k2 := &hub.SignKey{KeyID: keyID, URL: keyURL}
+		assert.NoError(t, ww.w.verifyProvenanceFile(prov, k2, chartURL+"?raw=true", "sha256:"+digest))
+		ww.hc.AssertExpectations(t)
+	})
+}
+
+func TestVerifyChartDigest(t *testing.T) {
+	data := []byte("chart archive")
+	digest := computeChartDigest(data)
+
+	testCases := []struct {
+		digest      string
+		expectedErr bool
+	}{
+		{"", false},
+		{digest, false},
+		{"sha256:" + digest, false},
+		{strings.ToUpper(digest), false},
+		{computeChartDigest([]byte("tampered")), true},
+		{"invalid", true},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.digest, func(t *testing.T) {
+			err := verifyChartDigest("http://tests/pkg1-1.0.0.tgz", data, tc.digest)
+			if tc.expectedErr {
+				var e *tracker.Error
+				assert.True(t, errors.As(err, &e))
+				assert.Equal(t, tracker.ErrCodeDigestMismatch, e.Code)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseOperatorAnnotations(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		annotations          map[string]string
+		expectedIsOperator   bool
+		expectedCapabilities string
+		expectedErr          bool
+	}{
+		{"pkg1", nil, false, "", false},
+		{"pkg1-operator", nil, true, "", false},
+		{"pkg1", map[string]string{operatorAnnotation: "true"}, true, "", false},
+		{"pkg1-operator", map[string]string{operatorAnnotation: "false"}, false, "", false},
+		{"pkg1", map[string]string{operatorAnnotation: "invalid"}, false, "", true},
+		{"pkg1", map[string]string{operatorCapabilitiesAnnotation: "seamless upgrades"}, true, "Seamless Upgrades", false},
+		{"pkg1", map[string]string{operatorCapabilitiesAnnotation: "Unknown"}, false, "", true},
+		{
+			"pkg1",
+			map[string]string{
+				operatorAnnotation:             "true",
+				operatorCapabilitiesAnnotation: "Auto Pilot",
+			},
+			true,
+			"Auto Pilot",
+			false,
+		},
+		{
+			"pkg1",
+			map[string]string{
+				operatorAnnotation:             "false",
+				operatorCapabilitiesAnnotation: "Auto Pilot",
+			},
+			false,
+			"",
+			false,
+		},
+	}
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			md := &chart.Metadata{Name: tc.name, Annotations: tc.annotations}
+			isOperator, capabilities, err := parseOperatorAnnotations(md)
+			assert.Equal(t, tc.expectedIsOperator, isOperator)
+			assert.Equal(t, tc.expectedCapabilities, capabilities)
+			if tc.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseMaintenanceAnnotation(t *testing.T) {
+	t.Run("invalid annotation", func(t *testing.T) {
+		testCases := []string{
+			"- invalid",
+			`supportedVersions: "invalid"`,
+			`supportedVersions: ">=2.0.0"` + "\neol: 30/06/2021\n",
+			"eol: 2021-13-01",
+			"eol: tomorrow",
+		}
+		for _, v := range testCases {
+			v := v
+			t.Run(v, func(t *testing.T) {
+				m, err := parseMaintenanceAnnotation(v)
+				assert.Error(t, err)
+				assert.Nil(t, m)
+			})
+		}
+	})
+
+	t.Run("valid annotation", func(t *testing.T) {
+		testCases := []struct {
+			v                   string
+			expectedMaintenance *hub.Maintenance
+		}{
+			{
+				"",
+				nil,
+			},
+			{
+				`supportedVersions: ">=2.0.0, <3.0.0"`,
+				&hub.Maintenance{
+					SupportedVersions: ">=2.0.0, <3.0.0",
+				},
+			},
+			{
+				"supportedVersions: \">=2.0.0\"\neol: 2021-06-30\n",
+				&hub.Maintenance{
+					SupportedVersions: ">=2.0.0",
+					EOL:               "2021-06-30",
+				},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.v, func(t *testing.T) {
+				m, err := parseMaintenanceAnnotation(tc.v)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedMaintenance, m)
+			})
+		}
+	})
+}
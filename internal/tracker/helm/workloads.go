@@ -0,0 +1,127 @@
+package helm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// workload represents the fields of a Kubernetes workload resource manifest
+// used during its analysis.
+type workload struct {
+	template   string
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name string `yaml:"name"`
+	} `yaml:"metadata"`
+	Spec struct {
+		podSpec  `yaml:",inline"`
+		Replicas *int64 `yaml:"replicas"`
+		Template struct {
+			Spec podSpec `yaml:"spec"`
+		} `yaml:"template"`
+		JobTemplate struct {
+			Spec struct {
+				Template struct {
+					Spec podSpec `yaml:"spec"`
+				} `yaml:"template"`
+			} `yaml:"spec"`
+		} `yaml:"jobTemplate"`
+	} `yaml:"spec"`
+}
+
+// podSpec represents the fields of a pod specification used during the
+// workloads analysis.
+type podSpec struct {
+	SecurityContext *securityContext `yaml:"securityContext"`
+	InitContainers  []*container     `yaml:"initContainers"`
+	Containers      []*container     `yaml:"containers"`
+	Volumes         []*volume        `yaml:"volumes"`
+}
+
+// container represents the fields of a container specification used during
+// the workloads analysis.
+type container struct {
+	Name            string           `yaml:"name"`
+	Image           string           `yaml:"image"`
+	SecurityContext *securityContext `yaml:"securityContext"`
+	Resources       struct {
+		Requests map[string]interface{} `yaml:"requests"`
+		Limits   map[string]interface{} `yaml:"limits"`
+	} `yaml:"resources"`
+}
+
+// securityContext represents the fields of a pod or container security
+// context used during the workloads analysis.
+type securityContext struct {
+	RunAsUser    *int64 `yaml:"runAsUser"`
+	RunAsNonRoot *bool  `yaml:"runAsNonRoot"`
+}
+
+// volume represents the fields of a pod volume used during the workloads
+// analysis.
+type volume struct {
+	Name     string `yaml:"name"`
+	HostPath *struct {
+		Path string `yaml:"path"`
+	} `yaml:"hostPath"`
+}
+
+// renderWorkloads renders the provided chart using its default values for the
+// latest target Kubernetes version, returning the workloads obtained.
+func renderWorkloads(chrt *chart.Chart) ([]*workload, error) {
+	if err := chartutil.ProcessDependencies(chrt, chrt.Values); err != nil {
+		return nil, fmt.Errorf("error processing dependencies: %w", err)
+	}
+	kubeVersion := targetKubernetesVersions[len(targetKubernetesVersions)-1]
+	manifests, err := renderManifests(chrt, kubeVersion)
+	if err != nil {
+		return nil, fmt.Errorf("error rendering chart: %w", err)
+	}
+	var workloads []*workload
+	for _, name := range sortedKeys(manifests) {
+		dec := yaml.NewDecoder(strings.NewReader(manifests[name]))
+		for {
+			var wl *workload
+			if err := dec.Decode(&wl); err != nil {
+				if !errors.Is(err, io.EOF) {
+					return nil, fmt.Errorf("invalid yaml in %s: %w", name, err)
+				}
+				break
+			}
+			if wl == nil || wl.pod() == nil {
+				continue
+			}
+			wl.template = name
+			workloads = append(workloads, wl)
+		}
+	}
+	return workloads, nil
+}
+
+// id returns the identifier of the workload, built from its api version,
+// kind and name.
+func (wl *workload) id() string {
+	return fmt.Sprintf("%s/%s %s", wl.APIVersion, wl.Kind, wl.Metadata.Name)
+}
+
+// pod returns the pod specification of the workload based on its kind. When
+// the resource is not a workload, nil is returned.
+func (wl *workload) pod() *podSpec {
+	switch wl.Kind {
+	case "Pod":
+		return &wl.Spec.podSpec
+	case "DaemonSet", "Deployment", "Job", "ReplicaSet", "ReplicationController", "StatefulSet":
+		return &wl.Spec.Template.Spec
+	case "CronJob":
+		return &wl.Spec.JobTemplate.Spec.Template.Spec
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,105 @@
+package tracker
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// HostsLimiter caps the number of concurrent http requests sent to each
+// host, so that trackers running many workers don't hammer a single origin.
+// A nil HostsLimiter, or one created with a non positive limit, doesn't
+// limit requests at all.
+type HostsLimiter struct {
+	limit int
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+}
+
+// NewHostsLimiter creates a new HostsLimiter instance that allows up to limit
+// concurrent requests per host.
+func NewHostsLimiter(limit int) *HostsLimiter {
+	return &HostsLimiter{
+		limit: limit,
+		slots: make(map[string]chan struct{}),
+	}
+}
+
+// Acquire blocks until a request to the host provided can be sent or the
+// context is done. The release function returned must be called once the
+// request has completed.
+func (l *HostsLimiter) Acquire(ctx context.Context, host string) (func(), error) {
+	if l == nil || l.limit <= 0 {
+		return func() {}, nil
+	}
+	host = strings.ToLower(host)
+	l.mu.Lock()
+	slots, ok := l.slots[host]
+	if !ok {
+		slots = make(chan struct{}, l.limit)
+		l.slots[host] = slots
+	}
+	l.mu.Unlock()
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-slots })
+	}, nil
+}
+
+// HTTPClient defines the methods an HTTPClient implementation must provide.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// LimitClient returns an HTTPClient that sends the requests using the client
+// provided, waiting for a slot of the host targeted before sending them. The
+// slot is held until the response body is closed.
+func (l *HostsLimiter) LimitClient(hc HTTPClient) HTTPClient {
+	if l == nil || l.limit <= 0 {
+		return hc
+	}
+	return &hostsLimitedClient{hc: hc, l: l}
+}
+
+// hostsLimitedClient is an HTTPClient wrapper that limits the number of
+// concurrent requests sent to each host using a HostsLimiter.
+type hostsLimitedClient struct {
+	hc HTTPClient
+	l  *HostsLimiter
+}
+
+// Do implements the HTTPClient interface.
+func (c *hostsLimitedClient) Do(req *http.Request) (*http.Response, error) {
+	release, err := c.l.Acquire(req.Context(), req.URL.Host)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releaseOnCloseReader{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releaseOnCloseReader is a wrapper around an http response body that
+// releases the host slot held by the request once the body is closed.
+type releaseOnCloseReader struct {
+	io.ReadCloser
+	release func()
+}
+
+// Close implements the io.Closer interface.
+func (r *releaseOnCloseReader) Close() error {
+	err := r.ReadCloser.Close()
+	r.release()
+	return err
+}
@@ -0,0 +1,75 @@
+package tracker
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostsLimiter(t *testing.T) {
+	t.Run("no limit", func(t *testing.T) {
+		var l *HostsLimiter
+		release, err := l.Acquire(context.Background(), "host1")
+		require.NoError(t, err)
+		release()
+
+		hc := &http.Client{}
+		assert.Equal(t, hc, NewHostsLimiter(0).LimitClient(hc))
+	})
+
+	t.Run("limit reached for host", func(t *testing.T) {
+		l := NewHostsLimiter(1)
+		release, err := l.Acquire(context.Background(), "host1")
+		require.NoError(t, err)
+
+		// Other hosts are not affected
+		release2, err := l.Acquire(context.Background(), "host2")
+		require.NoError(t, err)
+		release2()
+
+		// Same host (case insensitive) blocks until the slot is released
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = l.Acquire(ctx, "HOST1")
+		assert.Equal(t, context.DeadlineExceeded, err)
+
+		release()
+		release() // Releasing twice is a no-op
+		release3, err := l.Acquire(context.Background(), "host1")
+		require.NoError(t, err)
+		release3()
+	})
+
+	t.Run("slot held until response body is closed", func(t *testing.T) {
+		l := NewHostsLimiter(1)
+		hc := l.LimitClient(&fakeHTTPClient{})
+		req, _ := http.NewRequest("GET", "http://host1/path", nil)
+		resp, err := hc.Do(req)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = l.Acquire(ctx, "host1")
+		assert.Equal(t, context.DeadlineExceeded, err)
+
+		resp.Body.Close()
+		release, err := l.Acquire(context.Background(), "host1")
+		require.NoError(t, err)
+		release()
+	})
+}
+
+type fakeHTTPClient struct{}
+
+func (c *fakeHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+		StatusCode: http.StatusOK,
+	}, nil
+}
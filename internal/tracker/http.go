@@ -0,0 +1,80 @@
+package tracker
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/repo"
+	"github.com/spf13/viper"
+)
+
+// DefaultUserAgent represents the User-Agent sent on the http requests
+// performed by the trackers when none is provided in the configuration.
+const DefaultUserAgent = "artifacthub-tracker"
+
+// githubHosts represents the hosts considered part of GitHub, to which the
+// GitHub token configured will be sent.
+var githubHosts = []string{
+	"github.com",
+	"api.github.com",
+	"raw.githubusercontent.com",
+}
+
+// SetupRequest prepares the http request provided to be sent by a tracker. It
+// sets the User-Agent configured and, when the request targets GitHub over
+// https, the GitHub token to use for the repository provided (if any). The
+// credentials of the repository, if any, are added to the requests sent to
+// the repository's host, taking precedence over the GitHub token.
+func SetupRequest(req *http.Request, cfg *viper.Viper, r *hub.Repository) {
+	userAgent := DefaultUserAgent
+	if cfg != nil && cfg.GetString("tracker.userAgent") != "" {
+		userAgent = cfg.GetString("tracker.userAgent")
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	if req.URL.Scheme == "https" && isGithubHost(req.URL.Hostname()) {
+		if token := GithubToken(cfg, r); token != "" {
+			req.Header.Set("Authorization", "token "+token)
+		}
+	}
+	repo.SetupAuth(req, r)
+}
+
+// GithubToken returns the GitHub token that should be used when downloading
+// content from GitHub for the repository provided. Tokens configured for a
+// specific repository take precedence over the global one.
+func GithubToken(cfg *viper.Viper, r *hub.Repository) string {
+	if cfg == nil {
+		return ""
+	}
+	if r != nil {
+		tokens := cfg.GetStringMapString("tracker.repositoriesGithubTokens")
+		if token, ok := tokens[strings.ToLower(r.Name)]; ok && token != "" {
+			return token
+		}
+	}
+	return cfg.GetString("tracker.githubToken")
+}
+
+// isGithubHost checks if the host provided belongs to GitHub.
+func isGithubHost(host string) bool {
+	host = strings.ToLower(host)
+	for _, h := range githubHosts {
+		if host == h {
+			return true
+		}
+	}
+	return false
+}
+
+// ProxyURL returns the url of the proxy configured for the repository
+// provided, if any. Repositories without a specific proxy use the proxies
+// defined in the environment.
+func ProxyURL(cfg *viper.Viper, r *hub.Repository) string {
+	if cfg == nil || r == nil {
+		return ""
+	}
+	proxies := cfg.GetStringMapString("tracker.repositoriesProxies")
+	return proxies[strings.ToLower(r.Name)]
+}
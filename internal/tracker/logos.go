@@ -0,0 +1,116 @@
+package tracker
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"sync"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/rs/zerolog"
+)
+
+// defaultLogosWorkers represents the number of workers used by the logos
+// fetcher when none is provided.
+const defaultLogosWorkers = 10
+
+// LogoJob represents a job to fetch and store the logo of a package that has
+// already been registered.
+type LogoJob struct {
+	// Package represents the package the logo belongs to. Its name, logo url
+	// and repository must be set.
+	Package *hub.Package
+
+	// GetImage is the function used to get the logo image data.
+	GetImage func() ([]byte, error)
+}
+
+// LogosQueue defines the methods a logos queue implementation must provide.
+type LogosQueue interface {
+	Enqueue(j *LogoJob)
+}
+
+// LogosFetcher is a LogosQueue implementation that fetches the logos enqueued
+// asynchronously, so that slow logos hosts don't slow down the packages
+// registration. Once a logo has been fetched and stored, the package logo
+// image id is updated.
+type LogosFetcher struct {
+	svc    *Services
+	queue  chan *LogoJob
+	wg     sync.WaitGroup
+	logger zerolog.Logger
+}
+
+// NewLogosFetcher creates a new LogosFetcher instance and launches the
+// number of workers provided to handle the jobs enqueued.
+func NewLogosFetcher(svc *Services, numWorkers int) *LogosFetcher {
+	if numWorkers <= 0 {
+		numWorkers = defaultLogosWorkers
+	}
+	f := &LogosFetcher{
+		svc:    svc,
+		queue:  make(chan *LogoJob, numWorkers),
+		logger: util.LogWith("tracker").Str("worker", "logos").Logger(),
+	}
+	for i := 0; i < numWorkers; i++ {
+		f.wg.Add(1)
+		go f.run()
+	}
+	return f
+}
+
+// Enqueue implements the LogosQueue interface.
+func (f *LogosFetcher) Enqueue(j *LogoJob) {
+	select {
+	case f.queue <- j:
+	case <-f.svc.Ctx.Done():
+	}
+}
+
+// Wait stops accepting new jobs and waits for the pending ones to complete.
+func (f *LogosFetcher) Wait() {
+	close(f.queue)
+	f.wg.Wait()
+}
+
+// run handles the jobs enqueued until the queue is closed or the context is
+// done.
+func (f *LogosFetcher) run() {
+	defer f.wg.Done()
+	for {
+		select {
+		case j, ok := <-f.queue:
+			if !ok {
+				return
+			}
+			if err := f.handleJob(j); err != nil {
+				f.svc.Ec.Append(j.Package.Repository.RepositoryID, err)
+				f.logger.Warn().Err(err).Str("repo", j.Package.Repository.Name).Send()
+			}
+		case <-f.svc.Ctx.Done():
+			return
+		}
+	}
+}
+
+// handleJob fetches and stores the logo of the job provided, updating the
+// package logo image id afterwards.
+func (f *LogosFetcher) handleJob(j *LogoJob) error {
+	p := j.Package
+	data, err := j.GetImage()
+	if err != nil {
+		return fmt.Errorf("error getting package %s image %s: %w", p.Name, p.LogoURL, err)
+	}
+	logoImageID, err := f.svc.Is.SaveImage(f.svc.Ctx, data)
+	if err != nil {
+		if errors.Is(err, image.ErrFormat) {
+			return nil
+		}
+		return fmt.Errorf("error saving package %s image %s: %w", p.Name, p.LogoURL, err)
+	}
+	if err := f.svc.Pm.UpdateLogoImage(f.svc.Ctx, p, logoImageID); err != nil {
+		return fmt.Errorf("error updating package %s logo image: %w", p.Name, err)
+	}
+	return nil
+}
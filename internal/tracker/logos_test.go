@@ -0,0 +1,81 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"image"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/img"
+	"github.com/artifacthub/hub/internal/pkg"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLogosFetcher(t *testing.T) {
+	errFake := errors.New("fake error for tests")
+	logoData := []byte("logoData")
+	p := &hub.Package{
+		Name:    "pkg1",
+		LogoURL: "https://logo.url",
+		Repository: &hub.Repository{
+			RepositoryID: "00000000-0000-0000-0000-000000000001",
+			Name:         "repo1",
+		},
+	}
+
+	testCases := []struct {
+		name         string
+		getImageErr  error
+		saveImageErr error
+		updateErr    error
+		expectUpdate bool
+		expectError  bool
+	}{
+		{"error getting image", errFake, nil, nil, false, true},
+		{"error saving image", nil, errFake, nil, false, true},
+		{"invalid image format", nil, image.ErrFormat, nil, false, false},
+		{"error updating logo image", nil, nil, errFake, true, true},
+		{"logo image updated successfully", nil, nil, nil, true, false},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup fetcher and expectations
+			pm := &pkg.ManagerMock{}
+			is := &img.StoreMock{}
+			ec := &ErrorsCollectorMock{}
+			svc := &Services{
+				Ctx: context.Background(),
+				Pm:  pm,
+				Is:  is,
+				Ec:  ec,
+			}
+			if tc.getImageErr == nil {
+				is.On("SaveImage", svc.Ctx, logoData).Return("imageID", tc.saveImageErr)
+			}
+			if tc.expectUpdate {
+				pm.On("UpdateLogoImage", svc.Ctx, p, "imageID").Return(tc.updateErr)
+			}
+			if tc.expectError {
+				ec.On("Append", p.Repository.RepositoryID, mock.Anything).Return()
+			}
+			f := NewLogosFetcher(svc, 1)
+
+			// Enqueue job, wait for it to complete and check expectations
+			f.Enqueue(&LogoJob{
+				Package: p,
+				GetImage: func() ([]byte, error) {
+					if tc.getImageErr != nil {
+						return nil, tc.getImageErr
+					}
+					return logoData, nil
+				},
+			})
+			f.Wait()
+			pm.AssertExpectations(t)
+			is.AssertExpectations(t)
+			ec.AssertExpectations(t)
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package tracker
 
-import "github.com/stretchr/testify/mock"
+import (
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/mock"
+)
 
 // ErrorsCollectorMock is mock ErrorsCollector implementation.
 type ErrorsCollectorMock struct {
@@ -16,3 +19,24 @@ func (m *ErrorsCollectorMock) Append(repositoryID string, err error) {
 func (m *ErrorsCollectorMock) Flush() {
 	m.Called()
 }
+
+// LogosQueueMock is mock LogosQueue implementation.
+type LogosQueueMock struct {
+	mock.Mock
+}
+
+// Enqueue implements the LogosQueue interface.
+func (m *LogosQueueMock) Enqueue(j *LogoJob) {
+	m.Called(j)
+}
+
+// PackageValidatorMock is mock PackageValidator implementation.
+type PackageValidatorMock struct {
+	mock.Mock
+}
+
+// Validate implements the PackageValidator interface.
+func (m *PackageValidatorMock) Validate(p *hub.Package) error {
+	args := m.Called(p)
+	return args.Error(0)
+}
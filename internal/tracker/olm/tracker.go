@@ -16,12 +16,12 @@ import (
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/repo"
 	"github.com/artifacthub/hub/internal/tracker"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/ghodss/yaml"
 	"github.com/operator-framework/api/pkg/manifests"
 	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/operator-framework/api/pkg/validation"
 	"github.com/rs/zerolog"
-	"github.com/rs/zerolog/log"
 )
 
 var (
@@ -47,7 +47,7 @@ func NewTracker(
 	t := &Tracker{
 		svc:    svc,
 		r:      r,
-		logger: log.With().Str("repo", r.Name).Str("kind", hub.GetKindName(r.Kind)).Logger(),
+		logger: util.LogWith("tracker").Str("repo", r.Name).Str("kind", hub.GetKindName(r.Kind)).Logger(),
 	}
 	for _, o := range opts {
 		o(t)
@@ -80,7 +80,7 @@ func (t *Tracker) Track(wg *sync.WaitGroup) error {
 	// Register available packages when needed
 	bypassDigestCheck := t.svc.Cfg.GetBool("tracker.bypassDigestCheck")
 	packagesAvailable := make(map[string]struct{})
-	basePath := filepath.Join(tmpDir, packagesPath)
+	basePath := filepath.Join(tmpDir, filepath.FromSlash(packagesPath))
 	err = filepath.Walk(basePath, func(pkgPath string, info os.FileInfo, err error) error {
 		if err != nil {
 			return fmt.Errorf("error reading packages: %w", err)
@@ -284,6 +284,7 @@ func (t *Tracker) registerPackage(
 		DefaultChannel: manifest.DefaultChannelName,
 		ContainerImage: csv.Annotations["containerImage"],
 		Provider:       csv.Spec.Provider.Name,
+		Capabilities:   getCapabilities(csv),
 		Repository:     t.r,
 	}
 	createdAt, err := time.Parse(time.RFC3339, csv.Annotations["createdAt"])
@@ -350,12 +351,18 @@ func (t *Tracker) registerPackage(
 		})
 	}
 	p.Data = map[string]interface{}{
-		"capabilities":                       csv.Annotations["capabilities"],
 		"isGlobalOperator":                   isGlobalOperator,
 		"customResourcesDefinitions":         crds,
 		"customResourcesDefinitionsExamples": csv.Annotations["alm-examples"],
 	}
 
+	// Validate package if a validator is available
+	if t.svc.Pv != nil {
+		if err := t.svc.Pv.Validate(p); err != nil {
+			return err
+		}
+	}
+
 	// Register package
 	return t.svc.Pm.Register(t.svc.Ctx, p)
 }
@@ -382,3 +389,16 @@ func (t *Tracker) warn(err error) {
 func getPackageVersion(csv *operatorsv1alpha1.ClusterServiceVersion) string {
 	return csv.Spec.Version.String()
 }
+
+// getCapabilities returns the operator capability level from the cluster
+// service version provided, normalized to one of the levels supported. An
+// empty string is returned when the capabilities annotation is not valid.
+func getCapabilities(csv *operatorsv1alpha1.ClusterServiceVersion) string {
+	capabilities := strings.TrimSpace(csv.Annotations["capabilities"])
+	for _, c := range hub.OperatorCapabilities {
+		if strings.EqualFold(capabilities, c) {
+			return c
+		}
+	}
+	return ""
+}
@@ -140,6 +140,7 @@ func TestTracker(t *testing.T) {
 			IsOperator:     true,
 			ContainerImage: "repo.url:latest",
 			Provider:       "Test",
+			Capabilities:   "Basic Install",
 			CreatedAt:      1561735380,
 			Repository:     r,
 			Channels: []*hub.Channel{
@@ -166,7 +167,6 @@ func TestTracker(t *testing.T) {
 				},
 			},
 			Data: map[string]interface{}{
-				"capabilities": "Basic Install",
 				"customResourcesDefinitions": []map[string]string{
 					{
 						"description": "Test CRD",
@@ -14,8 +14,8 @@ import (
 	"github.com/artifacthub/hub/internal/pkg"
 	"github.com/artifacthub/hub/internal/repo"
 	"github.com/artifacthub/hub/internal/tracker"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/rs/zerolog"
-	"github.com/rs/zerolog/log"
 	ignore "github.com/sabhiram/go-gitignore"
 	"gopkg.in/yaml.v2"
 )
@@ -37,7 +37,7 @@ func NewTracker(
 	t := &Tracker{
 		svc:    svc,
 		r:      r,
-		logger: log.With().Str("repo", r.Name).Str("kind", hub.GetKindName(r.Kind)).Logger(),
+		logger: util.LogWith("tracker").Str("repo", r.Name).Str("kind", hub.GetKindName(r.Kind)).Logger(),
 	}
 	for _, o := range opts {
 		o(t)
@@ -69,7 +69,7 @@ func (t *Tracker) Track(wg *sync.WaitGroup) error {
 	// Register available packages when needed
 	bypassDigestCheck := t.svc.Cfg.GetBool("tracker.bypassDigestCheck")
 	packagesAvailable := make(map[string]struct{})
-	basePath := filepath.Join(tmpDir, packagesPath)
+	basePath := filepath.Join(tmpDir, filepath.FromSlash(packagesPath))
 	err = filepath.Walk(basePath, func(pkgPath string, info os.FileInfo, err error) error {
 		if err != nil {
 			return fmt.Errorf("error reading packages: %w", err)
@@ -110,7 +110,7 @@ func (t *Tracker) Track(wg *sync.WaitGroup) error {
 		// Read logo image when available
 		var logoImageID string
 		if md.LogoPath != "" {
-			data, err := ioutil.ReadFile(filepath.Join(pkgPath, md.LogoPath))
+			data, err := ioutil.ReadFile(filepath.Join(pkgPath, filepath.FromSlash(md.LogoPath)))
 			if err != nil {
 				t.warn(fmt.Errorf("error reading package %s version %s logo: %w", md.Name, md.Version, err))
 				return nil
@@ -180,7 +180,12 @@ func (t *Tracker) registerPackage(pkgPath string, md *hub.PackageMetadata, logoI
 		if info.IsDir() {
 			return nil
 		}
-		policyKey := strings.TrimPrefix(path, pkgPath+"/")
+		// Policies keys always use forward slashes, regardless of the os
+		rel, err := filepath.Rel(pkgPath, path)
+		if err != nil {
+			return fmt.Errorf("error reading policy files: %w", err)
+		}
+		policyKey := filepath.ToSlash(rel)
 		if ignorer.MatchesPath(policyKey) {
 			return nil
 		}
@@ -204,6 +209,13 @@ func (t *Tracker) registerPackage(pkgPath string, md *hub.PackageMetadata, logoI
 		"policies": policies,
 	}
 
+	// Validate package if a validator is available
+	if t.svc.Pv != nil {
+		if err := t.svc.Pv.Validate(p); err != nil {
+			return err
+		}
+	}
+
 	// Register package
 	return t.svc.Pm.Register(t.svc.Ctx, p)
 }
@@ -0,0 +1,111 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang.org/x/time/rate"
+)
+
+// defaultRateLimits represents the rate limits applied to some hosts when
+// none is provided for them in the configuration.
+var defaultRateLimits = map[string]string{
+	"github.com": "2:1",
+}
+
+// RateLimiter limits the rate at which http requests are sent to each host,
+// so that the trackers don't get rejected by origins enforcing rate limits.
+// It's safe to share it between all the workers. A nil RateLimiter doesn't
+// limit requests at all.
+type RateLimiter struct {
+	limiters map[string]*rate.Limiter
+}
+
+// NewRateLimiter creates a new RateLimiter instance using the limits provided
+// by host, which take precedence over the default ones. Each limit has the
+// format <rate>[:<burst>], where rate is the number of requests per second
+// allowed (0 = unlimited) and burst the maximum number of requests that can
+// be sent at once (1 by default).
+func NewRateLimiter(limits map[string]string) (*RateLimiter, error) {
+	merged := make(map[string]string, len(defaultRateLimits)+len(limits))
+	for host, limit := range defaultRateLimits {
+		merged[host] = limit
+	}
+	for host, limit := range limits {
+		merged[strings.ToLower(host)] = limit
+	}
+	l := &RateLimiter{
+		limiters: make(map[string]*rate.Limiter, len(merged)),
+	}
+	for host, limit := range merged {
+		r, burst, err := parseRateLimit(limit)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit for host %s: %w", host, err)
+		}
+		if r == rate.Inf {
+			continue
+		}
+		l.limiters[host] = rate.NewLimiter(r, burst)
+	}
+	return l, nil
+}
+
+// parseRateLimit parses a rate limit in the <rate>[:<burst>] format.
+func parseRateLimit(limit string) (rate.Limit, int, error) {
+	parts := strings.SplitN(strings.TrimSpace(limit), ":", 2)
+	r, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil || r < 0 {
+		return 0, 0, fmt.Errorf("invalid rate: %s", parts[0])
+	}
+	burst := 1
+	if len(parts) == 2 {
+		burst, err = strconv.Atoi(parts[1])
+		if err != nil || burst < 1 {
+			return 0, 0, fmt.Errorf("invalid burst: %s", parts[1])
+		}
+	}
+	if r == 0 {
+		return rate.Inf, burst, nil
+	}
+	return rate.Limit(r), burst, nil
+}
+
+// Wait blocks until a request to the host provided can be sent or the context
+// is done.
+func (l *RateLimiter) Wait(ctx context.Context, host string) error {
+	if l == nil {
+		return nil
+	}
+	rl, ok := l.limiters[strings.ToLower(host)]
+	if !ok {
+		return nil
+	}
+	return rl.Wait(ctx)
+}
+
+// LimitClient returns an HTTPClient that sends the requests using the client
+// provided, waiting until the rate limit of the host targeted allows it.
+func (l *RateLimiter) LimitClient(hc HTTPClient) HTTPClient {
+	if l == nil || len(l.limiters) == 0 {
+		return hc
+	}
+	return &rateLimitedClient{hc: hc, l: l}
+}
+
+// rateLimitedClient is an HTTPClient wrapper that limits the rate at which
+// requests are sent to each host using a RateLimiter.
+type rateLimitedClient struct {
+	hc HTTPClient
+	l  *RateLimiter
+}
+
+// Do implements the HTTPClient interface.
+func (c *rateLimitedClient) Do(req *http.Request) (*http.Response, error) {
+	if err := c.l.Wait(req.Context(), req.URL.Hostname()); err != nil {
+		return nil, err
+	}
+	return c.hc.Do(req)
+}
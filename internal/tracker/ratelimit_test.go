@@ -0,0 +1,86 @@
+package tracker
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestNewRateLimiter(t *testing.T) {
+	t.Run("default limits", func(t *testing.T) {
+		l, err := NewRateLimiter(nil)
+		require.NoError(t, err)
+		require.Contains(t, l.limiters, "github.com")
+		assert.Equal(t, rate.Limit(2), l.limiters["github.com"].Limit())
+		assert.Equal(t, 1, l.limiters["github.com"].Burst())
+	})
+
+	t.Run("configured limits take precedence over default ones", func(t *testing.T) {
+		l, err := NewRateLimiter(map[string]string{
+			"github.com":       "0",
+			"GitLab.com":       "5:10",
+			"s3.amazonaws.com": "0.5",
+		})
+		require.NoError(t, err)
+		assert.NotContains(t, l.limiters, "github.com")
+		require.Contains(t, l.limiters, "gitlab.com")
+		assert.Equal(t, rate.Limit(5), l.limiters["gitlab.com"].Limit())
+		assert.Equal(t, 10, l.limiters["gitlab.com"].Burst())
+		require.Contains(t, l.limiters, "s3.amazonaws.com")
+		assert.Equal(t, rate.Limit(0.5), l.limiters["s3.amazonaws.com"].Limit())
+		assert.Equal(t, 1, l.limiters["s3.amazonaws.com"].Burst())
+	})
+
+	t.Run("invalid limits", func(t *testing.T) {
+		for _, limit := range []string{"", "invalid", "-1", "1:", "1:0", "1:invalid"} {
+			_, err := NewRateLimiter(map[string]string{"host1": limit})
+			assert.Error(t, err, limit)
+		}
+	})
+}
+
+func TestRateLimiter(t *testing.T) {
+	t.Run("no limit", func(t *testing.T) {
+		var l *RateLimiter
+		assert.NoError(t, l.Wait(context.Background(), "host1"))
+
+		l, _ = NewRateLimiter(map[string]string{"github.com": "0"})
+		hc := &http.Client{}
+		assert.Equal(t, hc, l.LimitClient(hc))
+	})
+
+	t.Run("limit reached for host", func(t *testing.T) {
+		l, err := NewRateLimiter(map[string]string{"host1": "0.001"})
+		require.NoError(t, err)
+		require.NoError(t, l.Wait(context.Background(), "host1"))
+
+		// Other hosts are not affected
+		require.NoError(t, l.Wait(context.Background(), "host2"))
+
+		// Same host (case insensitive) blocks until the rate allows it
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.Error(t, l.Wait(ctx, "HOST1"))
+	})
+
+	t.Run("client waits for the limit of the host targeted", func(t *testing.T) {
+		l, err := NewRateLimiter(map[string]string{"host1": "0.001"})
+		require.NoError(t, err)
+		hc := l.LimitClient(&fakeHTTPClient{})
+
+		req, _ := http.NewRequest("GET", "http://host1:8080/path", nil)
+		resp, err := hc.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = hc.Do(req.WithContext(ctx))
+		assert.Error(t, err)
+	})
+}
@@ -3,6 +3,7 @@ package tracker
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/img"
@@ -32,4 +33,34 @@ type Services struct {
 	Il  hub.HelmIndexLoader
 	Is  img.Store
 	Ec  ErrorsCollector
+	Lq  LogosQueue
+	Pv  PackageValidator
+	Hl  *HostsLimiter
+	Rl  *RateLimiter
+	Sc  hub.SecretsCipher
+}
+
+// IsDue checks if the repository provided is due to be tracked at the time
+// provided. Repositories with a tracking interval (in minutes) set won't be
+// tracked again until that interval has elapsed since the last tracking, and
+// the ones with quiet hours set won't be tracked during them. Tracking requests
+// received after the last tracking take precedence over both settings.
+func IsDue(r *hub.Repository, now time.Time) bool {
+	if IsRequested(r) {
+		return true
+	}
+	if r.QuietHours != nil && r.QuietHours.Contains(now) {
+		return false
+	}
+	if r.TrackingInterval <= 0 || r.LastTrackingTS == 0 {
+		return true
+	}
+	interval := time.Duration(r.TrackingInterval) * time.Minute
+	return !now.Before(time.Unix(r.LastTrackingTS, 0).Add(interval))
+}
+
+// IsRequested checks if a tracking request has been received for the
+// repository provided that hasn't been processed yet.
+func IsRequested(r *hub.Repository) bool {
+	return r.TrackingRequestedTS > 0 && r.TrackingRequestedTS >= r.LastTrackingTS
 }
@@ -0,0 +1,261 @@
+package tracker
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsDue(t *testing.T) {
+	now := time.Unix(1592299234, 0)
+
+	testCases := []struct {
+		r             *hub.Repository
+		expectedIsDue bool
+	}{
+		{
+			&hub.Repository{},
+			true,
+		},
+		{
+			&hub.Repository{
+				LastTrackingTS: now.Add(-1 * time.Minute).Unix(),
+			},
+			true,
+		},
+		{
+			&hub.Repository{
+				TrackingInterval: 60,
+			},
+			true,
+		},
+		{
+			&hub.Repository{
+				TrackingInterval: 60,
+				LastTrackingTS:   now.Add(-30 * time.Minute).Unix(),
+			},
+			false,
+		},
+		{
+			&hub.Repository{
+				TrackingInterval: 60,
+				LastTrackingTS:   now.Add(-60 * time.Minute).Unix(),
+			},
+			true,
+		},
+		{
+			&hub.Repository{
+				TrackingInterval: 1440,
+				LastTrackingTS:   now.Add(-2 * time.Hour).Unix(),
+			},
+			false,
+		},
+		{
+			&hub.Repository{
+				TrackingInterval:    1440,
+				LastTrackingTS:      now.Add(-2 * time.Hour).Unix(),
+				TrackingRequestedTS: now.Add(-3 * time.Hour).Unix(),
+			},
+			false,
+		},
+		{
+			&hub.Repository{
+				TrackingInterval:    1440,
+				LastTrackingTS:      now.Add(-2 * time.Hour).Unix(),
+				TrackingRequestedTS: now.Add(-1 * time.Minute).Unix(),
+			},
+			true,
+		},
+		{
+			&hub.Repository{
+				QuietHours: &hub.QuietHours{Start: 8, End: 10},
+			},
+			false,
+		},
+		{
+			&hub.Repository{
+				QuietHours: &hub.QuietHours{Start: 10, End: 8},
+			},
+			true,
+		},
+		{
+			&hub.Repository{
+				QuietHours: &hub.QuietHours{Start: 22, End: 10},
+			},
+			false,
+		},
+		{
+			&hub.Repository{
+				TrackingInterval: 60,
+				LastTrackingTS:   now.Add(-2 * time.Hour).Unix(),
+				QuietHours:       &hub.QuietHours{Start: 9, End: 10},
+			},
+			false,
+		},
+		{
+			&hub.Repository{
+				LastTrackingTS:      now.Add(-2 * time.Hour).Unix(),
+				TrackingRequestedTS: now.Add(-1 * time.Minute).Unix(),
+				QuietHours:          &hub.QuietHours{Start: 9, End: 10},
+			},
+			true,
+		},
+	}
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("Test case %d", i+1), func(t *testing.T) {
+			assert.Equal(t, tc.expectedIsDue, IsDue(tc.r, now))
+		})
+	}
+}
+
+func TestIsRequested(t *testing.T) {
+	now := time.Unix(1592299234, 0)
+
+	testCases := []struct {
+		r                   *hub.Repository
+		expectedIsRequested bool
+	}{
+		{
+			&hub.Repository{},
+			false,
+		},
+		{
+			&hub.Repository{
+				LastTrackingTS: now.Add(-1 * time.Minute).Unix(),
+			},
+			false,
+		},
+		{
+			&hub.Repository{
+				LastTrackingTS:      now.Add(-1 * time.Minute).Unix(),
+				TrackingRequestedTS: now.Add(-2 * time.Minute).Unix(),
+			},
+			false,
+		},
+		{
+			&hub.Repository{
+				LastTrackingTS:      now.Add(-2 * time.Minute).Unix(),
+				TrackingRequestedTS: now.Add(-1 * time.Minute).Unix(),
+			},
+			true,
+		},
+		{
+			&hub.Repository{
+				TrackingRequestedTS: now.Add(-1 * time.Minute).Unix(),
+			},
+			true,
+		},
+	}
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("Test case %d", i+1), func(t *testing.T) {
+			assert.Equal(t, tc.expectedIsRequested, IsRequested(tc.r))
+		})
+	}
+}
+
+func TestProxyURL(t *testing.T) {
+	cfg := viper.New()
+	cfg.Set("tracker.repositoriesProxies", map[string]string{
+		"repo2": "http://proxy.corp:3128",
+	})
+
+	testCases := []struct {
+		cfg              *viper.Viper
+		r                *hub.Repository
+		expectedProxyURL string
+	}{
+		{nil, &hub.Repository{Name: "repo2"}, ""},
+		{cfg, &hub.Repository{Name: "repo1"}, ""},
+		{cfg, &hub.Repository{Name: "repo2"}, "http://proxy.corp:3128"},
+		{cfg, &hub.Repository{Name: "Repo2"}, "http://proxy.corp:3128"},
+	}
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			assert.Equal(t, tc.expectedProxyURL, ProxyURL(tc.cfg, tc.r))
+		})
+	}
+}
+
+func TestSetupRequest(t *testing.T) {
+	cfg := viper.New()
+	cfg.Set("tracker.userAgent", "test-agent")
+	cfg.Set("tracker.githubToken", "globalToken")
+	cfg.Set("tracker.repositoriesGithubTokens", map[string]string{
+		"repo2": "repo2Token",
+	})
+
+	testCases := []struct {
+		cfg                   *viper.Viper
+		r                     *hub.Repository
+		u                     string
+		expectedUserAgent     string
+		expectedAuthorization string
+	}{
+		{
+			nil,
+			&hub.Repository{Name: "repo1"},
+			"https://github.com/org1/repo1",
+			DefaultUserAgent,
+			"",
+		},
+		{
+			cfg,
+			&hub.Repository{Name: "repo1"},
+			"https://repo1.com/icon.png",
+			"test-agent",
+			"",
+		},
+		{
+			cfg,
+			&hub.Repository{Name: "repo1"},
+			"http://raw.githubusercontent.com/org1/repo1/master/icon.png",
+			"test-agent",
+			"",
+		},
+		{
+			cfg,
+			&hub.Repository{Name: "repo1"},
+			"https://raw.githubusercontent.com/org1/repo1/master/icon.png",
+			"test-agent",
+			"token globalToken",
+		},
+		{
+			cfg,
+			&hub.Repository{Name: "repo2"},
+			"https://github.com/org1/repo2/releases/download/pkg-1.0.0.tgz",
+			"test-agent",
+			"token repo2Token",
+		},
+		{
+			cfg,
+			&hub.Repository{Name: "repo3", URL: "https://repo3.com/charts", AuthPass: "repo3Token"},
+			"https://repo3.com/charts/pkg-1.0.0.tgz",
+			"test-agent",
+			"Bearer repo3Token",
+		},
+		{
+			cfg,
+			&hub.Repository{Name: "repo3", URL: "https://repo3.com/charts", AuthPass: "repo3Token"},
+			"https://raw.githubusercontent.com/org1/repo3/master/icon.png",
+			"test-agent",
+			"token globalToken",
+		},
+	}
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("Test case %d", i), func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, tc.u, nil)
+			SetupRequest(req, tc.cfg, tc.r)
+			assert.Equal(t, tc.expectedUserAgent, req.Header.Get("User-Agent"))
+			assert.Equal(t, tc.expectedAuthorization, req.Header.Get("Authorization"))
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package tracker
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	// ErrCodePackageRejected indicates that a package version has been
+	// rejected by the validation webhook of the organization owning the
+	// repository, so it has not been registered.
+	ErrCodePackageRejected ErrorCode = "package_rejected"
+
+	// ErrCodePackageFlagged indicates that a package version has been flagged
+	// by the validation webhook of the organization owning the repository. The
+	// package version is registered anyway.
+	ErrCodePackageFlagged ErrorCode = "package_flagged"
+)
+
+// Actions that a validation webhook can request for a package version.
+const (
+	validationActionAccept = "accept"
+	validationActionFlag   = "flag"
+	validationActionReject = "reject"
+)
+
+// maxValidationResponseSize represents the maximum size of the responses
+// read from the validation webhooks.
+const maxValidationResponseSize = 64 * 1024
+
+// getValidationWebhookDBQ represents the query used to get the validation
+// webhook of an organization.
+const getValidationWebhookDBQ = `
+select validation_webhook_url, coalesce(validation_webhook_secret, '')
+from organization
+where organization_id = $1
+and validation_webhook_url is not null
+`
+
+// PackageValidator defines the methods a package validator implementation
+// must provide. Package versions are validated right before being registered,
+// and the ones that don't pass the validation must not be registered.
+type PackageValidator interface {
+	Validate(p *hub.Package) error
+}
+
+// WebhookPackageValidator is a PackageValidator implementation that validates
+// the packages using the validation webhook the organization owning the
+// repository may have set up. Packages in repositories that don't belong to
+// an organization, or whose organization hasn't set up a validation webhook,
+// are always accepted.
+type WebhookPackageValidator struct {
+	svc *Services
+	db  hub.DB
+	hc  HTTPClient
+
+	mu       sync.Mutex
+	webhooks map[string]*hub.ValidationWebhook // K: organization id
+}
+
+// NewWebhookPackageValidator creates a new WebhookPackageValidator instance.
+func NewWebhookPackageValidator(svc *Services, db hub.DB, hc HTTPClient) *WebhookPackageValidator {
+	return &WebhookPackageValidator{
+		svc:      svc,
+		db:       db,
+		hc:       hc,
+		webhooks: make(map[string]*hub.ValidationWebhook),
+	}
+}
+
+// validationPayload represents the payload sent to the validation webhooks.
+type validationPayload struct {
+	Repository *validationPayloadRepository `json:"repository"`
+	Package    *hub.Package                 `json:"package"`
+}
+
+// validationPayloadRepository represents the details of the repository
+// included in the validation payload.
+type validationPayloadRepository struct {
+	Name             string `json:"name"`
+	Kind             string `json:"kind"`
+	URL              string `json:"url"`
+	OrganizationName string `json:"organization_name"`
+}
+
+// validationResponse represents the response expected from the validation
+// webhooks.
+type validationResponse struct {
+	Action string `json:"action"`
+	Reason string `json:"reason"`
+}
+
+// Validate implements the PackageValidator interface.
+func (v *WebhookPackageValidator) Validate(p *hub.Package) error {
+	r := p.Repository
+	if r == nil || r.OrganizationID == "" {
+		return nil
+	}
+	vw, err := v.getValidationWebhook(r.OrganizationID)
+	if err != nil {
+		return fmt.Errorf("error getting validation webhook: %w", err)
+	}
+	if vw == nil {
+		return nil
+	}
+
+	// Call validation webhook. The repository details are sent separately so
+	// that its credentials, if any, are never included in the payload.
+	pCopy := *p
+	pCopy.Repository = nil
+	payload, _ := json.Marshal(&validationPayload{
+		Repository: &validationPayloadRepository{
+			Name:             r.Name,
+			Kind:             hub.GetKindName(r.Kind),
+			URL:              r.URL,
+			OrganizationName: r.OrganizationName,
+		},
+		Package: &pCopy,
+	})
+	req, _ := http.NewRequest("POST", vw.URL, bytes.NewReader(payload))
+	req = req.WithContext(v.svc.Ctx)
+	req.Header.Set("Content-Type", "application/json")
+	if vw.Secret != "" {
+		req.Header.Set("X-ArtifactHub-Secret", vw.Secret)
+	}
+	resp, err := v.hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling validation webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("validation webhook returned an unexpected status code: %d", resp.StatusCode)
+	}
+	var vr *validationResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxValidationResponseSize)).Decode(&vr); err != nil {
+		return fmt.Errorf("invalid validation webhook response: %w", err)
+	}
+
+	// Apply action requested
+	switch vr.Action {
+	case validationActionAccept:
+		return nil
+	case validationActionFlag:
+		v.svc.Ec.Append(r.RepositoryID, NewError(ErrCodePackageFlagged, fmt.Errorf(
+			"package %s version %s flagged by validation webhook: %s", p.Name, p.Version, vr.Reason,
+		)))
+		return nil
+	case validationActionReject:
+		return NewError(ErrCodePackageRejected, fmt.Errorf("rejected by validation webhook: %s", vr.Reason))
+	default:
+		return fmt.Errorf("invalid validation webhook response action: %s", vr.Action)
+	}
+}
+
+// getValidationWebhook returns the validation webhook of the organization
+// provided, if any, decrypting its secret when a cipher is set. Webhooks are
+// cached, so that the database is queried only once per organization.
+func (v *WebhookPackageValidator) getValidationWebhook(orgID string) (*hub.ValidationWebhook, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if vw, ok := v.webhooks[orgID]; ok {
+		return vw, nil
+	}
+	vw := &hub.ValidationWebhook{}
+	err := v.db.QueryRow(v.svc.Ctx, getValidationWebhookDBQ, orgID).Scan(&vw.URL, &vw.Secret)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		vw = nil
+	}
+	if vw != nil && vw.Secret != "" && v.svc.Sc != nil {
+		vw.Secret, err = v.svc.Sc.Decrypt(v.svc.Ctx, vw.Secret)
+		if err != nil {
+			return nil, err
+		}
+	}
+	v.webhooks[orgID] = vw
+	return vw, nil
+}
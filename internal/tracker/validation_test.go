@@ -0,0 +1,206 @@
+package tracker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/secrets"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookPackageValidator(t *testing.T) {
+	ctx := context.Background()
+	newPackage := func() *hub.Package {
+		return &hub.Package{
+			Name:    "pkg1",
+			Version: "1.0.0",
+			Repository: &hub.Repository{
+				RepositoryID:     "00000000-0000-0000-0000-000000000001",
+				Name:             "repo1",
+				Kind:             hub.Helm,
+				URL:              "https://repo1.url",
+				AuthPass:         "pass",
+				OrganizationID:   "00000000-0000-0000-0000-000000000002",
+				OrganizationName: "org1",
+			},
+		}
+	}
+
+	t.Run("repository does not belong to an organization", func(t *testing.T) {
+		db := &tests.DBMock{}
+		v := NewWebhookPackageValidator(&Services{Ctx: ctx}, db, http.DefaultClient)
+
+		p := newPackage()
+		p.Repository.OrganizationID = ""
+		err := v.Validate(p)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("error getting validation webhook", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getValidationWebhookDBQ, mock.Anything).Return(nil, tests.ErrFakeDatabaseFailure)
+		v := NewWebhookPackageValidator(&Services{Ctx: ctx}, db, http.DefaultClient)
+
+		err := v.Validate(newPackage())
+		assert.True(t, errors.Is(err, tests.ErrFakeDatabaseFailure))
+		db.AssertExpectations(t)
+	})
+
+	t.Run("organization has not set up a validation webhook", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getValidationWebhookDBQ, mock.Anything).Return(nil, pgx.ErrNoRows).Once()
+		v := NewWebhookPackageValidator(&Services{Ctx: ctx}, db, http.DefaultClient)
+
+		// The validation webhook is only fetched from the database once
+		assert.NoError(t, v.Validate(newPackage()))
+		assert.NoError(t, v.Validate(newPackage()))
+		db.AssertExpectations(t)
+	})
+
+	t.Run("validation webhook returned an unexpected status code", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getValidationWebhookDBQ, mock.Anything).Return([]interface{}{ts.URL, ""}, nil)
+		v := NewWebhookPackageValidator(&Services{Ctx: ctx}, db, ts.Client())
+
+		err := v.Validate(newPackage())
+		assert.Error(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("validation webhook returned an invalid response", func(t *testing.T) {
+		testCases := []string{
+			"-",
+			`{"action": "unknown"}`,
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc, func(t *testing.T) {
+				ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte(tc))
+				}))
+				defer ts.Close()
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, getValidationWebhookDBQ, mock.Anything).Return([]interface{}{ts.URL, ""}, nil)
+				v := NewWebhookPackageValidator(&Services{Ctx: ctx}, db, ts.Client())
+
+				err := v.Validate(newPackage())
+				assert.Error(t, err)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("package accepted", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.Equal(t, "secret", r.Header.Get("X-ArtifactHub-Secret"))
+			var payload map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			assert.Equal(t, map[string]interface{}{
+				"name":              "repo1",
+				"kind":              "helm",
+				"url":               "https://repo1.url",
+				"organization_name": "org1",
+			}, payload["repository"])
+			pkg := payload["package"].(map[string]interface{})
+			assert.Equal(t, "pkg1", pkg["name"])
+			assert.Nil(t, pkg["repository"])
+			_, _ = w.Write([]byte(`{"action": "accept"}`))
+		}))
+		defer ts.Close()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getValidationWebhookDBQ, mock.Anything).Return([]interface{}{ts.URL, "secret"}, nil)
+		v := NewWebhookPackageValidator(&Services{Ctx: ctx}, db, ts.Client())
+
+		p := newPackage()
+		err := v.Validate(p)
+		assert.NoError(t, err)
+		assert.NotNil(t, p.Repository)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("validation webhook secret decrypted", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "secret", r.Header.Get("X-ArtifactHub-Secret"))
+			_, _ = w.Write([]byte(`{"action": "accept"}`))
+		}))
+		defer ts.Close()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getValidationWebhookDBQ, mock.Anything).Return([]interface{}{ts.URL, "encryptedSecret"}, nil)
+		sc := &secrets.CipherMock{}
+		sc.On("Decrypt", ctx, "encryptedSecret").Return("secret", nil).Once()
+		v := NewWebhookPackageValidator(&Services{Ctx: ctx, Sc: sc}, db, ts.Client())
+
+		// The secret is only decrypted once, when the webhook is cached
+		assert.NoError(t, v.Validate(newPackage()))
+		assert.NoError(t, v.Validate(newPackage()))
+		db.AssertExpectations(t)
+		sc.AssertExpectations(t)
+	})
+
+	t.Run("error decrypting validation webhook secret", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getValidationWebhookDBQ, mock.Anything).Return([]interface{}{"https://validation.url", "encryptedSecret"}, nil)
+		sc := &secrets.CipherMock{}
+		errFake := errors.New("fake error for tests")
+		sc.On("Decrypt", ctx, "encryptedSecret").Return("", errFake)
+		v := NewWebhookPackageValidator(&Services{Ctx: ctx, Sc: sc}, db, http.DefaultClient)
+
+		err := v.Validate(newPackage())
+		assert.True(t, errors.Is(err, errFake))
+		db.AssertExpectations(t)
+		sc.AssertExpectations(t)
+	})
+
+	t.Run("package flagged", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"action": "flag", "reason": "missing maintainers"}`))
+		}))
+		defer ts.Close()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getValidationWebhookDBQ, mock.Anything).Return([]interface{}{ts.URL, ""}, nil)
+		ec := &ErrorsCollectorMock{}
+		ec.On("Append", "00000000-0000-0000-0000-000000000001", mock.MatchedBy(func(err error) bool {
+			var e *Error
+			return errors.As(err, &e) && e.Code == ErrCodePackageFlagged
+		})).Return()
+		v := NewWebhookPackageValidator(&Services{Ctx: ctx, Ec: ec}, db, ts.Client())
+
+		err := v.Validate(newPackage())
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+		ec.AssertExpectations(t)
+	})
+
+	t.Run("package rejected", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"action": "reject", "reason": "missing license"}`))
+		}))
+		defer ts.Close()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getValidationWebhookDBQ, mock.Anything).Return([]interface{}{ts.URL, ""}, nil)
+		v := NewWebhookPackageValidator(&Services{Ctx: ctx}, db, ts.Client())
+
+		err := v.Validate(newPackage())
+		var e *Error
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, ErrCodePackageRejected, e.Code)
+		assert.Contains(t, err.Error(), "missing license")
+		db.AssertExpectations(t)
+	})
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/artifacthub/hub/internal/email"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/i18n"
 	"github.com/jackc/pgx/v4"
 	"github.com/satori/uuid"
 	"golang.org/x/crypto/bcrypt"
@@ -26,15 +27,30 @@ var (
 
 // Manager provides an API to manage users.
 type Manager struct {
-	db hub.DB
-	es hub.EmailSender
+	db         hub.DB
+	es         hub.EmailSender
+	bcryptCost int
 }
 
 // NewManager creates a new Manager instance.
-func NewManager(db hub.DB, es hub.EmailSender) *Manager {
-	return &Manager{
-		db: db,
-		es: es,
+func NewManager(db hub.DB, es hub.EmailSender, opts ...func(m *Manager)) *Manager {
+	m := &Manager{
+		db:         db,
+		es:         es,
+		bcryptCost: bcrypt.DefaultCost,
+	}
+	for _, o := range opts {
+		o(m)
+	}
+	return m
+}
+
+// WithBcryptCost allows providing the bcrypt cost used to hash the users
+// passwords. Passwords hashed with a different cost are hashed again using
+// this one the next time their owners log in.
+func WithBcryptCost(cost int) func(m *Manager) {
+	return func(m *Manager) {
+		m.bcryptCost = cost
 	}
 }
 
@@ -45,10 +61,10 @@ func (m *Manager) CheckAPIKey(ctx context.Context, key []byte) (*hub.CheckAPIKey
 		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "key not provided")
 	}
 
-	// Get key's user id from database
-	var userID string
-	query := `select user_id from api_key where key = $1`
-	err := m.db.QueryRow(ctx, query, key).Scan(&userID)
+	// Get key's id, user id and repository (when scoped) from database
+	var apiKeyID, userID, repositoryID string
+	query := `select api_key_id, user_id, coalesce(repository_id::text, '') from api_key where key = $1`
+	err := m.db.QueryRow(ctx, query, key).Scan(&apiKeyID, &userID, &repositoryID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return &hub.CheckAPIKeyOutput{Valid: false}, nil
@@ -56,8 +72,10 @@ func (m *Manager) CheckAPIKey(ctx context.Context, key []byte) (*hub.CheckAPIKey
 		return nil, err
 	}
 	return &hub.CheckAPIKeyOutput{
-		Valid:  true,
-		UserID: userID,
+		Valid:        true,
+		APIKeyID:     apiKeyID,
+		UserID:       userID,
+		RepositoryID: repositoryID,
 	}, nil
 }
 
@@ -127,6 +145,16 @@ func (m *Manager) CheckCredentials(
 		return &hub.CheckCredentialsOutput{Valid: false}, nil
 	}
 
+	// Upgrade the password hash if it was generated using legacy parameters.
+	// Errors are ignored as the upgrade will be tried again on next login.
+	if cost, err := bcrypt.Cost([]byte(hashedPassword)); err == nil && cost != m.bcryptCost {
+		newHashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), m.bcryptCost)
+		if err == nil {
+			query := `update "user" set password = $1 where user_id = $2 and password = $3`
+			_, _ = m.db.Exec(ctx, query, string(newHashedPassword), userID, hashedPassword)
+		}
+	}
+
 	return &hub.CheckCredentialsOutput{
 		Valid:  true,
 		UserID: userID,
@@ -173,6 +201,20 @@ func (m *Manager) CheckSession(
 	}, nil
 }
 
+// CountLegacyPasswords returns the number of users passwords that were hashed
+// using parameters other than the current ones, and will be upgraded the next
+// time their owners log in.
+func (m *Manager) CountLegacyPasswords(ctx context.Context) (int64, error) {
+	var n int64
+	query := `
+	select count(*) from "user"
+	where password is not null
+	and substring(password from '^\$2[aby]?\$([0-9]{2})\$') is distinct from lpad($1::text, 2, '0')
+	`
+	err := m.db.QueryRow(ctx, query, m.bcryptCost).Scan(&n)
+	return n, err
+}
+
 // DeleteSession deletes a user session from the database.
 func (m *Manager) DeleteSession(ctx context.Context, sessionID []byte) error {
 	// Validate input
@@ -253,10 +295,16 @@ func (m *Manager) RegisterUser(ctx context.Context, user *hub.User, baseURL stri
 			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid profile image id")
 		}
 	}
+	if user.Locale != "" && !i18n.IsSupported(user.Locale) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid locale")
+	}
+	if user.Timezone != "" && !isValidTimezone(user.Timezone) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid timezone")
+	}
 
 	// Hash password
 	if user.Password != "" {
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), m.bcryptCost)
 		if err != nil {
 			return err
 		}
@@ -277,12 +325,12 @@ func (m *Manager) RegisterUser(ctx context.Context, user *hub.User, baseURL stri
 			"link": fmt.Sprintf("%s/verify-email?code=%s", baseURL, code),
 		}
 		var emailBody bytes.Buffer
-		if err := emailVerificationTmpl.Execute(&emailBody, templateData); err != nil {
+		if err := emailVerificationTmpl.Execute(&emailBody, user.Locale, templateData); err != nil {
 			return err
 		}
 		emailData := &email.Data{
 			To:      user.Email,
-			Subject: "Verify your email address",
+			Subject: i18n.T(user.Locale, "Verify your email address"),
 			Body:    emailBody.Bytes(),
 		}
 		if err := m.es.SendEmail(emailData); err != nil {
@@ -318,7 +366,7 @@ func (m *Manager) UpdatePassword(ctx context.Context, old, new string) error {
 	}
 
 	// Hash new password
-	newHashed, err := bcrypt.GenerateFromPassword([]byte(new), bcrypt.DefaultCost)
+	newHashed, err := bcrypt.GenerateFromPassword([]byte(new), m.bcryptCost)
 	if err != nil {
 		return err
 	}
@@ -342,6 +390,12 @@ func (m *Manager) UpdateProfile(ctx context.Context, user *hub.User) error {
 			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid profile image id")
 		}
 	}
+	if user.Locale != "" && !i18n.IsSupported(user.Locale) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid locale")
+	}
+	if user.Timezone != "" && !isValidTimezone(user.Timezone) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid timezone")
+	}
 
 	// Update user profile in database
 	query := "select update_user_profile($1::uuid, $2::jsonb)"
@@ -364,3 +418,13 @@ func (m *Manager) VerifyEmail(ctx context.Context, code string) (bool, error) {
 	err := m.db.QueryRow(ctx, "select verify_email($1::uuid)", code).Scan(&verified)
 	return verified, err
 }
+
+// isValidTimezone checks if the timezone provided is a valid IANA time zone
+// name, as used to schedule the emails sent to the user at their local time.
+func isValidTimezone(tz string) bool {
+	if tz == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(tz)
+	return err == nil
+}
@@ -17,7 +17,7 @@ import (
 )
 
 func TestCheckAPIKey(t *testing.T) {
-	dbQuery := `select user_id from api_key where key = $1`
+	dbQuery := `select api_key_id, user_id, coalesce(repository_id::text, '') from api_key where key = $1`
 	ctx := context.Background()
 
 	t.Run("invalid input", func(t *testing.T) {
@@ -70,13 +70,28 @@ func TestCheckAPIKey(t *testing.T) {
 
 	t.Run("valid key", func(t *testing.T) {
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, dbQuery, []byte("key")).Return("userID", nil)
+		db.On("QueryRow", ctx, dbQuery, []byte("key")).Return([]interface{}{"apiKeyID", "userID", ""}, nil)
 		m := NewManager(db, nil)
 
 		output, err := m.CheckAPIKey(ctx, []byte("key"))
 		assert.NoError(t, err)
 		assert.True(t, output.Valid)
+		assert.Equal(t, "apiKeyID", output.APIKeyID)
 		assert.Equal(t, "userID", output.UserID)
+		assert.Empty(t, output.RepositoryID)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("valid repository scoped key", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, []byte("key")).Return([]interface{}{"apiKeyID", "userID", "repositoryID"}, nil)
+		m := NewManager(db, nil)
+
+		output, err := m.CheckAPIKey(ctx, []byte("key"))
+		assert.NoError(t, err)
+		assert.True(t, output.Valid)
+		assert.Equal(t, "userID", output.UserID)
+		assert.Equal(t, "repositoryID", output.RepositoryID)
 		db.AssertExpectations(t)
 	})
 }
@@ -233,6 +248,41 @@ func TestCheckCredentials(t *testing.T) {
 		assert.Equal(t, "userID", output.UserID)
 		db.AssertExpectations(t)
 	})
+
+	t.Run("valid credentials provided, legacy password hash upgraded", func(t *testing.T) {
+		pw, _ := bcrypt.GenerateFromPassword([]byte("pass"), bcrypt.MinCost)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "email").Return([]interface{}{"userID", string(pw)}, nil)
+		db.On("Exec", ctx, `update "user" set password = $1 where user_id = $2 and password = $3`,
+			mock.MatchedBy(func(newPW string) bool {
+				cost, _ := bcrypt.Cost([]byte(newPW))
+				return cost == bcrypt.MinCost+1 && bcrypt.CompareHashAndPassword([]byte(newPW), []byte("pass")) == nil
+			}),
+			"userID",
+			string(pw),
+		).Return(nil)
+		m := NewManager(db, nil, WithBcryptCost(bcrypt.MinCost+1))
+
+		output, err := m.CheckCredentials(ctx, "email", "pass")
+		assert.NoError(t, err)
+		assert.True(t, output.Valid)
+		assert.Equal(t, "userID", output.UserID)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("valid credentials provided, error upgrading legacy password hash", func(t *testing.T) {
+		pw, _ := bcrypt.GenerateFromPassword([]byte("pass"), bcrypt.MinCost)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "email").Return([]interface{}{"userID", string(pw)}, nil)
+		db.On("Exec", ctx, mock.Anything, mock.Anything, "userID", string(pw)).Return(tests.ErrFakeDatabaseFailure)
+		m := NewManager(db, nil, WithBcryptCost(bcrypt.MinCost+1))
+
+		output, err := m.CheckCredentials(ctx, "email", "pass")
+		assert.NoError(t, err)
+		assert.True(t, output.Valid)
+		assert.Equal(t, "userID", output.UserID)
+		db.AssertExpectations(t)
+	})
 }
 
 func TestCheckSession(t *testing.T) {
@@ -326,6 +376,36 @@ func TestCheckSession(t *testing.T) {
 	})
 }
 
+func TestCountLegacyPasswords(t *testing.T) {
+	dbQuery := `
+	select count(*) from "user"
+	where password is not null
+	and substring(password from '^\$2[aby]?\$([0-9]{2})\$') is distinct from lpad($1::text, 2, '0')
+	`
+	ctx := context.Background()
+
+	t.Run("database error", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, bcrypt.DefaultCost).Return(nil, tests.ErrFakeDatabaseFailure)
+		m := NewManager(db, nil)
+
+		_, err := m.CountLegacyPasswords(ctx)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("legacy passwords counted successfully", func(t *testing.T) {
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, 12).Return(int64(3), nil)
+		m := NewManager(db, nil, WithBcryptCost(12))
+
+		n, err := m.CountLegacyPasswords(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), n)
+		db.AssertExpectations(t)
+	})
+}
+
 func TestDeleteSession(t *testing.T) {
 	dbQuery := "delete from session where session_id = $1"
 	ctx := context.Background()
@@ -543,6 +623,16 @@ func TestRegisterUser(t *testing.T) {
 				&hub.User{Alias: "user1", Email: "email", ProfileImageID: "invalid"},
 				"http://baseurl.com",
 			},
+			{
+				"invalid locale",
+				&hub.User{Alias: "user1", Email: "email", Locale: "invalid"},
+				"http://baseurl.com",
+			},
+			{
+				"invalid timezone",
+				&hub.User{Alias: "user1", Email: "email", Timezone: "Invalid/Timezone"},
+				"http://baseurl.com",
+			},
 		}
 		for _, tc := range testCases {
 			tc := tc
@@ -722,6 +812,14 @@ func TestUpdateProfile(t *testing.T) {
 				"invalid profile image id",
 				&hub.User{Alias: "user1", Email: "email", ProfileImageID: "invalid"},
 			},
+			{
+				"invalid locale",
+				&hub.User{Alias: "user1", Email: "email", Locale: "invalid"},
+			},
+			{
+				"invalid timezone",
+				&hub.User{Alias: "user1", Email: "email", Timezone: "Local"},
+			},
 		}
 		for _, tc := range testCases {
 			tc := tc
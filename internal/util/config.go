@@ -1,8 +1,10 @@
 package util
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/artifacthub/hub/internal/hub"
 	"github.com/spf13/viper"
 )
 
@@ -27,3 +29,33 @@ func SetupConfig(cmd string) (*viper.Viper, error) {
 
 	return cfg, nil
 }
+
+// SetupLimits returns the global limits that will be enforced by the
+// managers, applying the overrides provided in the server.limits section of
+// the configuration to the default ones.
+func SetupLimits(cfg *viper.Viper) (*hub.Limits, error) {
+	l := hub.DefaultLimits()
+	overrides := []struct {
+		key   string
+		value *int
+	}{
+		{"server.limits.searchMaxPageSize", &l.SearchMaxPageSize},
+		{"server.limits.readmeMaxBytes", &l.ReadmeMaxBytes},
+		{"server.limits.webhooksPerUser", &l.WebhooksPerUser},
+		{"server.limits.repositoriesPerOrg", &l.RepositoriesPerOrg},
+	}
+	for _, o := range overrides {
+		if !cfg.IsSet(o.key) {
+			continue
+		}
+		v := cfg.GetInt(o.key)
+		if v < 0 {
+			return nil, fmt.Errorf("invalid %s: %d", o.key, v)
+		}
+		*o.value = v
+	}
+	if l.SearchMaxPageSize == 0 {
+		return nil, fmt.Errorf("invalid server.limits.searchMaxPageSize: 0")
+	}
+	return l, nil
+}
@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -43,3 +45,46 @@ func TestSetupConfig(t *testing.T) {
 	assert.Equal(t, "value3", cfg.GetString("key3.extra"))
 	assert.Equal(t, "value4", cfg.GetString("key4-extra"))
 }
+
+func TestSetupLimits(t *testing.T) {
+	t.Run("no overrides provided, default limits returned", func(t *testing.T) {
+		l, err := SetupLimits(viper.New())
+		require.NoError(t, err)
+		assert.Equal(t, hub.DefaultLimits(), l)
+	})
+
+	t.Run("overrides provided, they are applied to the default limits", func(t *testing.T) {
+		cfg := viper.New()
+		cfg.Set("server.limits.searchMaxPageSize", 20)
+		cfg.Set("server.limits.webhooksPerUser", 5)
+		l, err := SetupLimits(cfg)
+		require.NoError(t, err)
+		assert.Equal(t, &hub.Limits{
+			SearchMaxPageSize: 20,
+			WebhooksPerUser:   5,
+		}, l)
+	})
+
+	t.Run("invalid overrides provided", func(t *testing.T) {
+		testCases := []struct {
+			key   string
+			value int
+		}{
+			{"server.limits.searchMaxPageSize", 0},
+			{"server.limits.searchMaxPageSize", -1},
+			{"server.limits.readmeMaxBytes", -1},
+			{"server.limits.webhooksPerUser", -1},
+			{"server.limits.repositoriesPerOrg", -1},
+		}
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.key, func(t *testing.T) {
+				cfg := viper.New()
+				cfg.Set(tc.key, tc.value)
+				l, err := SetupLimits(cfg)
+				assert.Error(t, err)
+				assert.Nil(t, l)
+			})
+		}
+	})
+}
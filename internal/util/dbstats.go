@@ -0,0 +1,118 @@
+package util
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type dbStatsKey struct{}
+
+// DBStats represents some statistics about the database queries executed
+// while processing a request.
+type DBStats struct {
+	mu                   sync.Mutex
+	queries              int
+	duration             time.Duration
+	slowestQuery         string
+	slowestQueryDuration time.Duration
+}
+
+// WithDBStats returns a copy of the context provided that carries a new
+// DBStats instance, which will be updated by the TimedDB queries run using it.
+func WithDBStats(ctx context.Context) (context.Context, *DBStats) {
+	s := &DBStats{}
+	return context.WithValue(ctx, dbStatsKey{}, s), s
+}
+
+// Queries returns the number of queries executed.
+func (s *DBStats) Queries() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queries
+}
+
+// Duration returns the total time spent executing queries.
+func (s *DBStats) Duration() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.duration
+}
+
+// SlowestQuery returns the slowest query executed and its duration.
+func (s *DBStats) SlowestQuery() (string, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.slowestQuery, s.slowestQueryDuration
+}
+
+// record records the execution of the query provided.
+func (s *DBStats) record(sql string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queries++
+	s.duration += d
+	if s.queries == 1 || d > s.slowestQueryDuration {
+		s.slowestQuery = sql
+		s.slowestQueryDuration = d
+	}
+}
+
+// TimedDB is a hub.DB wrapper that records how long the queries take in the
+// DBStats instance found in their context, if any. Queries run within
+// transactions are not recorded.
+type TimedDB struct {
+	hub.DB
+}
+
+// NewTimedDB creates a new TimedDB instance that wraps the database provided.
+func NewTimedDB(db hub.DB) *TimedDB {
+	return &TimedDB{
+		DB: db,
+	}
+}
+
+// Exec implements the hub.DB interface.
+func (db *TimedDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	s, ok := ctx.Value(dbStatsKey{}).(*DBStats)
+	if !ok {
+		return db.DB.Exec(ctx, sql, args...)
+	}
+	start := time.Now()
+	defer func() { s.record(sql, time.Since(start)) }()
+	return db.DB.Exec(ctx, sql, args...)
+}
+
+// QueryRow implements the hub.DB interface.
+func (db *TimedDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	s, ok := ctx.Value(dbStatsKey{}).(*DBStats)
+	if !ok {
+		return db.DB.QueryRow(ctx, sql, args...)
+	}
+	start := time.Now()
+	return &timedRow{
+		Row:   db.DB.QueryRow(ctx, sql, args...),
+		stats: s,
+		sql:   sql,
+		start: start,
+	}
+}
+
+// timedRow is a pgx.Row wrapper that records the query duration once its
+// results have been read.
+type timedRow struct {
+	pgx.Row
+	stats *DBStats
+	sql   string
+	start time.Time
+}
+
+// Scan implements the pgx.Row interface.
+func (r *timedRow) Scan(dest ...interface{}) error {
+	defer func() { r.stats.record(r.sql, time.Since(r.start)) }()
+	return r.Row.Scan(dest...)
+}
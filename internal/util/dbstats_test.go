@@ -0,0 +1,46 @@
+package util
+
+import (
+	"context"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimedDB(t *testing.T) {
+	t.Run("queries without stats in context are not recorded", func(t *testing.T) {
+		ctx := context.Background()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, "query1").Return(nil)
+		tdb := NewTimedDB(db)
+
+		_, err := tdb.Exec(ctx, "query1")
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("queries with stats in context are recorded", func(t *testing.T) {
+		ctx, s := WithDBStats(context.Background())
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, "query1").Return(nil)
+		db.On("QueryRow", ctx, "query2").Return("value", nil)
+		db.On("QueryRow", ctx, "query3").Return(nil, tests.ErrFakeDatabaseFailure)
+		tdb := NewTimedDB(db)
+
+		_, err := tdb.Exec(ctx, "query1")
+		assert.NoError(t, err)
+		var value string
+		err = tdb.QueryRow(ctx, "query2").Scan(&value)
+		assert.NoError(t, err)
+		assert.Equal(t, "value", value)
+		err = tdb.QueryRow(ctx, "query3").Scan(&value)
+		assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+
+		assert.Equal(t, 3, s.Queries())
+		slowestQuery, slowestQueryDuration := s.SlowestQuery()
+		assert.Contains(t, []string{"query1", "query2", "query3"}, slowestQuery)
+		assert.True(t, slowestQueryDuration <= s.Duration())
+		db.AssertExpectations(t)
+	})
+}
@@ -2,6 +2,7 @@ package util
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/artifacthub/hub/internal/img"
 	"github.com/artifacthub/hub/internal/img/pg"
@@ -10,11 +11,40 @@ import (
 
 // SetupImageStore creates a new image store based on the configuration provided.
 func SetupImageStore(cfg *viper.Viper, db pg.DB) (img.Store, error) {
+	limits, err := SetupImageLimits(cfg)
+	if err != nil {
+		return nil, err
+	}
 	imageStore := cfg.GetString("tracker.imageStore")
 	switch imageStore {
 	case "pg":
-		return pg.NewImageStore(db), nil
+		return pg.NewImageStore(db, pg.WithLimits(limits)), nil
 	default:
 		return nil, errors.New("invalid image store")
 	}
 }
+
+// SetupImageLimits returns the limits that will be enforced on the images
+// before storing them, applying the overrides provided in the images section
+// of the configuration to the default ones.
+func SetupImageLimits(cfg *viper.Viper) (*img.Limits, error) {
+	l := img.DefaultLimits()
+	overrides := []struct {
+		key   string
+		value *int
+	}{
+		{"images.maxSize", &l.MaxSize},
+		{"images.maxDimension", &l.MaxDimension},
+	}
+	for _, o := range overrides {
+		if !cfg.IsSet(o.key) {
+			continue
+		}
+		v := cfg.GetInt(o.key)
+		if v < 0 {
+			return nil, fmt.Errorf("invalid %s: %d", o.key, v)
+		}
+		*o.value = v
+	}
+	return l, nil
+}
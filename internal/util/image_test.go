@@ -3,6 +3,7 @@ package util
 import (
 	"testing"
 
+	"github.com/artifacthub/hub/internal/img"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
 )
@@ -22,3 +23,25 @@ func TestSetupImageStore(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, imageStore)
 }
+
+func TestSetupImageLimits(t *testing.T) {
+	// Check default limits are used when no overrides are provided
+	l, err := SetupImageLimits(viper.New())
+	require.NoError(t, err)
+	require.Equal(t, img.DefaultLimits(), l)
+
+	// Check overrides are applied
+	cfg := viper.New()
+	cfg.Set("images.maxSize", 1024)
+	cfg.Set("images.maxDimension", 0)
+	l, err = SetupImageLimits(cfg)
+	require.NoError(t, err)
+	require.Equal(t, &img.Limits{MaxSize: 1024, MaxDimension: 0}, l)
+
+	// Check negative values are rejected
+	cfg = viper.New()
+	cfg.Set("images.maxSize", -1)
+	l, err = SetupImageLimits(cfg)
+	require.Error(t, err)
+	require.Nil(t, l)
+}
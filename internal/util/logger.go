@@ -1,29 +1,210 @@
 package util
 
 import (
+	"fmt"
 	"os"
+	"sync"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
 )
 
+// DefaultLogComponent represents the component the log level of the loggers
+// not associated to any specific component belongs to. Components without a
+// log level of their own use the default one.
+const DefaultLogComponent = "default"
+
+var (
+	// rootLogger represents the logger from which the components loggers are
+	// derived. It's the global logger without the default component level
+	// filter.
+	rootLogger = log.Logger
+
+	// logLevels represents the log levels of the components.
+	logLevels = &componentsLogLevels{
+		known:  make(map[string]struct{}),
+		levels: map[string]zerolog.Level{DefaultLogComponent: zerolog.InfoLevel},
+	}
+)
+
 // SetupLogger configures the global logger using the configuration provided.
 func SetupLogger(cfg *viper.Viper, fields map[string]interface{}) error {
+	// Setup output fields names
+	switch format := cfg.GetString("log.format"); format {
+	case "":
+	case "ecs":
+		zerolog.TimestampFieldName = "@timestamp"
+		zerolog.LevelFieldName = "log.level"
+		zerolog.MessageFieldName = "message"
+		zerolog.ErrorFieldName = "error.message"
+		zerolog.TimeFieldFormat = time.RFC3339Nano
+		fields = copyFields(fields)
+		fields["ecs.version"] = "1.6.0"
+	default:
+		return fmt.Errorf("invalid log format: %s", format)
+	}
+	for field, name := range cfg.GetStringMapString("log.fieldsNames") {
+		if name == "" {
+			continue
+		}
+		switch field {
+		case "timestamp":
+			zerolog.TimestampFieldName = name
+		case "level":
+			zerolog.LevelFieldName = name
+		case "message":
+			zerolog.MessageFieldName = name
+		case "error":
+			zerolog.ErrorFieldName = name
+		default:
+			return fmt.Errorf("invalid log field: %s", field)
+		}
+	}
+
 	// Add some context to global logger
-	log.Logger = log.With().Fields(fields).Logger()
+	logger := log.With().Fields(fields).Logger()
 
 	// Set log level
 	level, err := zerolog.ParseLevel(cfg.GetString("log.level"))
 	if err != nil {
 		return err
 	}
-	zerolog.SetGlobalLevel(level)
 
 	// Enable pretty logging (not JSON) if requested
 	if cfg.GetBool("log.pretty") {
-		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+		logger = logger.Output(zerolog.ConsoleWriter{Out: os.Stderr})
 	}
 
+	// Sample debug logs if requested
+	if burst := cfg.GetInt("log.sampling.debug.burst"); burst > 0 {
+		period := cfg.GetDuration("log.sampling.debug.period")
+		if period <= 0 {
+			period = 1 * time.Second
+		}
+		logger = logger.Sample(&zerolog.LevelSampler{
+			DebugSampler: &zerolog.BurstSampler{
+				Burst:  uint32(burst),
+				Period: period,
+			},
+		})
+	}
+
+	rootLogger = logger
+	log.Logger = logger.Hook(levelFilter(DefaultLogComponent))
+	logLevels.reset(level)
+
 	return nil
 }
+
+// LogWith returns a context that can be used to create a logger for the
+// component provided, whose log level can be changed independently using
+// SetLogLevel.
+func LogWith(component string) zerolog.Context {
+	logLevels.register(component)
+	return rootLogger.Hook(levelFilter(component)).With().Str("component", component)
+}
+
+// GetLogLevels returns the current log level of each of the components.
+func GetLogLevels() map[string]string {
+	return logLevels.getAll()
+}
+
+// SetLogLevel sets the log level of the component provided. Setting the level
+// of the default component also changes the level of the components that don't
+// have a level of their own.
+func SetLogLevel(component string, level zerolog.Level) error {
+	return logLevels.set(component, level)
+}
+
+// levelFilter is a zerolog hook that discards the events below the log level
+// of a given component.
+type levelFilter string
+
+// Run implements the zerolog.Hook interface.
+func (c levelFilter) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	if level < logLevels.get(string(c)) {
+		e.Discard()
+	}
+}
+
+// componentsLogLevels keeps track of the log levels of the components. The
+// global log level is kept in sync so that it matches the lowest of them.
+type componentsLogLevels struct {
+	mu     sync.RWMutex
+	known  map[string]struct{}
+	levels map[string]zerolog.Level
+}
+
+// get returns the log level of the component provided.
+func (l *componentsLogLevels) get(component string) zerolog.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if level, ok := l.levels[component]; ok {
+		return level
+	}
+	return l.levels[DefaultLogComponent]
+}
+
+// getAll returns the log level of all the known components.
+func (l *componentsLogLevels) getAll() map[string]string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	levels := map[string]string{
+		DefaultLogComponent: l.levels[DefaultLogComponent].String(),
+	}
+	for component := range l.known {
+		level, ok := l.levels[component]
+		if !ok {
+			level = l.levels[DefaultLogComponent]
+		}
+		levels[component] = level.String()
+	}
+	return levels
+}
+
+// register registers the component provided as a known component.
+func (l *componentsLogLevels) register(component string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.known[component] = struct{}{}
+}
+
+// reset sets the default log level provided, removing the levels previously
+// set for the components.
+func (l *componentsLogLevels) reset(level zerolog.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.levels = map[string]zerolog.Level{DefaultLogComponent: level}
+	zerolog.SetGlobalLevel(level)
+}
+
+// set sets the log level of the component provided.
+func (l *componentsLogLevels) set(component string, level zerolog.Level) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.known[component]; !ok && component != DefaultLogComponent {
+		return fmt.Errorf("unknown log component: %s", component)
+	}
+	l.levels[component] = level
+
+	// The global level must allow the events of the most verbose component
+	minLevel := level
+	for _, lvl := range l.levels {
+		if lvl < minLevel {
+			minLevel = lvl
+		}
+	}
+	zerolog.SetGlobalLevel(minLevel)
+	return nil
+}
+
+// copyFields returns a copy of the fields provided.
+func copyFields(fields map[string]interface{}) map[string]interface{} {
+	fieldsCopy := make(map[string]interface{}, len(fields)+1)
+	for k, v := range fields {
+		fieldsCopy[k] = v
+	}
+	return fieldsCopy
+}
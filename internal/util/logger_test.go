@@ -1,7 +1,9 @@
 package util
 
 import (
+	"bytes"
 	"testing"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/spf13/viper"
@@ -28,3 +30,79 @@ func TestSetupLogger(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, zerolog.DebugLevel, zerolog.GlobalLevel())
 }
+
+func TestSetupLoggerFormat(t *testing.T) {
+	defer func() {
+		zerolog.TimestampFieldName = "time"
+		zerolog.LevelFieldName = "level"
+		zerolog.MessageFieldName = "message"
+		zerolog.ErrorFieldName = "error"
+		zerolog.TimeFieldFormat = time.RFC3339
+	}()
+
+	t.Run("invalid format", func(t *testing.T) {
+		cfg := viper.New()
+		cfg.Set("log.level", "info")
+		cfg.Set("log.format", "invalid")
+		err := SetupLogger(cfg, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid field name", func(t *testing.T) {
+		cfg := viper.New()
+		cfg.Set("log.level", "info")
+		cfg.Set("log.fieldsNames", map[string]string{"invalid": "name"})
+		err := SetupLogger(cfg, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("ecs format and custom field names", func(t *testing.T) {
+		cfg := viper.New()
+		cfg.Set("log.level", "info")
+		cfg.Set("log.format", "ecs")
+		cfg.Set("log.fieldsNames", map[string]string{"message": "msg"})
+		err := SetupLogger(cfg, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "@timestamp", zerolog.TimestampFieldName)
+		assert.Equal(t, "log.level", zerolog.LevelFieldName)
+		assert.Equal(t, "msg", zerolog.MessageFieldName)
+		assert.Equal(t, "error.message", zerolog.ErrorFieldName)
+	})
+}
+
+func TestLogLevels(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := viper.New()
+	cfg.Set("log.level", "info")
+	err := SetupLogger(cfg, nil)
+	require.NoError(t, err)
+	rootLogger = rootLogger.Output(&buf)
+	logger := LogWith("component1").Logger()
+
+	// Debug events are discarded by default
+	logger.Debug().Msg("msg1")
+	assert.Empty(t, buf.String())
+	logger.Info().Msg("msg2")
+	assert.Contains(t, buf.String(), "msg2")
+	assert.Equal(t, "info", GetLogLevels()["component1"])
+
+	// Debug events are allowed once the component level is changed
+	buf.Reset()
+	err = SetLogLevel("component1", zerolog.DebugLevel)
+	require.NoError(t, err)
+	logger.Debug().Msg("msg3")
+	assert.Contains(t, buf.String(), "msg3")
+	assert.Contains(t, buf.String(), `"component":"component1"`)
+	assert.Equal(t, zerolog.DebugLevel, zerolog.GlobalLevel())
+
+	// The other components are not affected
+	buf.Reset()
+	logger2 := LogWith("component2").Logger()
+	logger2.Debug().Msg("msg4")
+	assert.Empty(t, buf.String())
+	assert.Equal(t, "info", GetLogLevels()["component2"])
+
+	// Unknown components can't be set
+	err = SetLogLevel("unknown", zerolog.DebugLevel)
+	assert.Error(t, err)
+}
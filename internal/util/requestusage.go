@@ -0,0 +1,41 @@
+package util
+
+import (
+	"context"
+	"sync"
+)
+
+type requestUsageKey struct{}
+
+// RequestUsage holds the api key used to authenticate a request, if any.
+type RequestUsage struct {
+	mu       sync.Mutex
+	apiKeyID string
+}
+
+// WithRequestUsage returns a copy of the context provided that carries a new
+// RequestUsage instance, which will be updated by the middleware in charge of
+// authenticating the request.
+func WithRequestUsage(ctx context.Context) (context.Context, *RequestUsage) {
+	u := &RequestUsage{}
+	return context.WithValue(ctx, requestUsageKey{}, u), u
+}
+
+// SetRequestAPIKey sets the api key used to authenticate the request in the
+// RequestUsage instance found in the context provided, if any.
+func SetRequestAPIKey(ctx context.Context, apiKeyID string) {
+	u, ok := ctx.Value(requestUsageKey{}).(*RequestUsage)
+	if !ok {
+		return
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.apiKeyID = apiKeyID
+}
+
+// APIKeyID returns the id of the api key used to authenticate the request.
+func (u *RequestUsage) APIKeyID() string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.apiKeyID
+}
@@ -0,0 +1,23 @@
+package util
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestUsage(t *testing.T) {
+	t.Run("no request usage in context", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			SetRequestAPIKey(context.Background(), "apiKeyID")
+		})
+	})
+
+	t.Run("api key set in request usage", func(t *testing.T) {
+		ctx, u := WithRequestUsage(context.Background())
+		assert.Equal(t, "", u.APIKeyID())
+		SetRequestAPIKey(ctx, "apiKeyID")
+		assert.Equal(t, "apiKeyID", u.APIKeyID())
+	})
+}
@@ -14,13 +14,36 @@ import (
 
 // Manager provides an API to manage webhooks.
 type Manager struct {
-	db hub.DB
+	db     hub.DB
+	sc     hub.SecretsCipher
+	limits *hub.Limits
 }
 
 // NewManager creates a new Manager instance.
-func NewManager(db hub.DB) *Manager {
-	return &Manager{
-		db: db,
+func NewManager(db hub.DB, opts ...func(m *Manager)) *Manager {
+	m := &Manager{
+		db:     db,
+		limits: hub.DefaultLimits(),
+	}
+	for _, o := range opts {
+		o(m)
+	}
+	return m
+}
+
+// WithSecretsCipher sets the cipher used to encrypt the webhooks secrets
+// before storing them in the database in a Manager instance.
+func WithSecretsCipher(sc hub.SecretsCipher) func(m *Manager) {
+	return func(m *Manager) {
+		m.sc = sc
+	}
+}
+
+// WithLimits allows overriding the default limits enforced by a Manager
+// instance, like the maximum number of webhooks a user can own.
+func WithLimits(l *hub.Limits) func(m *Manager) {
+	return func(m *Manager) {
+		m.limits = l
 	}
 }
 
@@ -54,9 +77,24 @@ func (m *Manager) Add(ctx context.Context, orgName string, wh *hub.Webhook) erro
 		}
 	}
 
+	// Check the user has not reached the maximum number of webhooks allowed
+	if orgName == "" && m.limits.WebhooksPerUser > 0 {
+		var count int64
+		query := "select count_user_webhooks($1::uuid)"
+		if err := m.db.QueryRow(ctx, query, userID).Scan(&count); err != nil {
+			return err
+		}
+		if count >= int64(m.limits.WebhooksPerUser) {
+			return &hub.LimitExceededError{Limit: "webhooks per user", Max: m.limits.WebhooksPerUser}
+		}
+	}
+
 	// Add webhook to the database
+	whJSON, err := m.marshalWebhook(ctx, wh)
+	if err != nil {
+		return err
+	}
 	query := "select add_webhook($1::uuid, $2::text, $3::jsonb)"
-	whJSON, _ := json.Marshal(wh)
 	_, err = m.db.Exec(ctx, query, userID, orgName, whJSON)
 	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
 		return hub.ErrInsufficientPrivilege
@@ -100,7 +138,17 @@ func (m *Manager) GetJSON(ctx context.Context, webhookID string) ([]byte, error)
 		}
 		return nil, err
 	}
-	return dataJSON, nil
+	if m.sc == nil {
+		return dataJSON, nil
+	}
+	var wh map[string]interface{}
+	if err := json.Unmarshal(dataJSON, &wh); err != nil {
+		return nil, err
+	}
+	if err := m.decryptSecret(ctx, wh); err != nil {
+		return nil, err
+	}
+	return json.Marshal(wh)
 }
 
 // GetOwnedByOrgJSON returns the webhooks belonging to the provided organization
@@ -115,7 +163,11 @@ func (m *Manager) GetOwnedByOrgJSON(ctx context.Context, orgName string) ([]byte
 
 	// Get webhooks from database
 	query := "select get_org_webhooks($1::uuid, $2::text)"
-	return m.dbQueryJSON(ctx, query, userID, orgName)
+	dataJSON, err := m.dbQueryJSON(ctx, query, userID, orgName)
+	if err != nil {
+		return nil, err
+	}
+	return m.decryptSecrets(ctx, dataJSON)
 }
 
 // GetOwnedByUserJSON returns the webhooks belonging to the requesting user as
@@ -125,7 +177,11 @@ func (m *Manager) GetOwnedByUserJSON(ctx context.Context) ([]byte, error) {
 
 	// Get webhooks from database
 	query := "select get_user_webhooks($1::uuid)"
-	return m.dbQueryJSON(ctx, query, userID)
+	dataJSON, err := m.dbQueryJSON(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	return m.decryptSecrets(ctx, dataJSON)
 }
 
 // GetSubscribedTo returns the webhooks subscribed to the event kind and
@@ -136,7 +192,7 @@ func (m *Manager) GetSubscribedTo(
 	packageID string,
 ) ([]*hub.Webhook, error) {
 	// Validate input
-	if eventKind != hub.NewRelease {
+	if !isValidEventKind(eventKind) {
 		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid event kind")
 	}
 	if _, err := uuid.FromString(packageID); err != nil {
@@ -190,8 +246,11 @@ func (m *Manager) Update(ctx context.Context, wh *hub.Webhook) error {
 	}
 
 	// Update webhook in database
+	whJSON, err := m.marshalWebhook(ctx, wh)
+	if err != nil {
+		return err
+	}
 	query := "select update_webhook($1::uuid, $2::jsonb)"
-	whJSON, _ := json.Marshal(wh)
 	_, err = m.db.Exec(ctx, query, userID, whJSON)
 	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
 		return hub.ErrInsufficientPrivilege
@@ -199,6 +258,53 @@ func (m *Manager) Update(ctx context.Context, wh *hub.Webhook) error {
 	return err
 }
 
+// marshalWebhook returns the json representation of the webhook provided to
+// be stored in the database, encrypting its secret when a cipher is set.
+func (m *Manager) marshalWebhook(ctx context.Context, wh *hub.Webhook) ([]byte, error) {
+	if m.sc == nil || wh.Secret == "" {
+		return json.Marshal(wh)
+	}
+	secret, err := m.sc.Encrypt(ctx, wh.Secret)
+	if err != nil {
+		return nil, err
+	}
+	whCopy := *wh
+	whCopy.Secret = secret
+	return json.Marshal(whCopy)
+}
+
+// decryptSecrets decrypts the secrets of the webhooks in the json array
+// provided when a cipher is set.
+func (m *Manager) decryptSecrets(ctx context.Context, dataJSON []byte) ([]byte, error) {
+	if m.sc == nil {
+		return dataJSON, nil
+	}
+	var webhooks []map[string]interface{}
+	if err := json.Unmarshal(dataJSON, &webhooks); err != nil {
+		return nil, err
+	}
+	for _, wh := range webhooks {
+		if err := m.decryptSecret(ctx, wh); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(webhooks)
+}
+
+// decryptSecret decrypts the secret of the webhook provided, if any.
+func (m *Manager) decryptSecret(ctx context.Context, wh map[string]interface{}) error {
+	secret, ok := wh["secret"].(string)
+	if !ok || secret == "" {
+		return nil
+	}
+	secret, err := m.sc.Decrypt(ctx, secret)
+	if err != nil {
+		return err
+	}
+	wh["secret"] = secret
+	return nil
+}
+
 // dbQueryJSON is a helper that executes the query provided and returns a bytes
 // slice containing the json data returned from the database.
 func (m *Manager) dbQueryJSON(ctx context.Context, query string, args ...interface{}) ([]byte, error) {
@@ -208,3 +314,13 @@ func (m *Manager) dbQueryJSON(ctx context.Context, query string, args ...interfa
 	}
 	return dataJSON, nil
 }
+
+// isValidEventKind checks if the provided event kind can be subscribed to.
+func isValidEventKind(eventKind hub.EventKind) bool {
+	for _, validKind := range hub.SubscribableEventKinds {
+		if eventKind == validKind {
+			return true
+		}
+	}
+	return false
+}
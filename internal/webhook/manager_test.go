@@ -2,10 +2,12 @@ package webhook
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/secrets"
 	"github.com/artifacthub/hub/internal/tests"
 	"github.com/artifacthub/hub/internal/util"
 	"github.com/stretchr/testify/assert"
@@ -15,6 +17,8 @@ import (
 
 const validUUID = "00000000-0000-0000-0000-000000000001"
 
+var errFake = errors.New("fake error for tests")
+
 func TestAdd(t *testing.T) {
 	dbQuery := "select add_webhook($1::uuid, $2::text, $3::jsonb)"
 	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
@@ -152,6 +156,85 @@ func TestAdd(t *testing.T) {
 		assert.NoError(t, err)
 		db.AssertExpectations(t)
 	})
+
+	t.Run("add webhook with encrypted secret succeeded", func(t *testing.T) {
+		whWithSecret := *wh
+		whWithSecret.Secret = "secret"
+		sc := &secrets.CipherMock{}
+		sc.On("Encrypt", ctx, "secret").Return("encryptedSecret", nil)
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, dbQuery, "userID", "orgName", mock.MatchedBy(func(whJSON []byte) bool {
+			var wh *hub.Webhook
+			_ = json.Unmarshal(whJSON, &wh)
+			return wh.Secret == "encryptedSecret"
+		})).Return(nil)
+		m := NewManager(db, WithSecretsCipher(sc))
+
+		err := m.Add(ctx, "orgName", &whWithSecret)
+		assert.NoError(t, err)
+		assert.Equal(t, "secret", whWithSecret.Secret)
+		db.AssertExpectations(t)
+		sc.AssertExpectations(t)
+	})
+
+	t.Run("error encrypting secret", func(t *testing.T) {
+		whWithSecret := *wh
+		whWithSecret.Secret = "secret"
+		sc := &secrets.CipherMock{}
+		sc.On("Encrypt", ctx, "secret").Return("", errFake)
+		m := NewManager(nil, WithSecretsCipher(sc))
+
+		err := m.Add(ctx, "orgName", &whWithSecret)
+		assert.Equal(t, errFake, err)
+		sc.AssertExpectations(t)
+	})
+
+	t.Run("webhooks per user limit", func(t *testing.T) {
+		countQuery := "select count_user_webhooks($1::uuid)"
+		limits := &hub.Limits{WebhooksPerUser: 2}
+
+		t.Run("limit not reached, webhook added", func(t *testing.T) {
+			db := &tests.DBMock{}
+			db.On("QueryRow", ctx, countQuery, "userID").Return(int64(1), nil)
+			db.On("Exec", ctx, dbQuery, "userID", "", mock.Anything).Return(nil)
+			m := NewManager(db, WithLimits(limits))
+
+			err := m.Add(ctx, "", wh)
+			assert.NoError(t, err)
+			db.AssertExpectations(t)
+		})
+
+		t.Run("limit reached, webhook not added", func(t *testing.T) {
+			db := &tests.DBMock{}
+			db.On("QueryRow", ctx, countQuery, "userID").Return(int64(2), nil)
+			m := NewManager(db, WithLimits(limits))
+
+			err := m.Add(ctx, "", wh)
+			assert.True(t, errors.Is(err, hub.ErrLimitExceeded))
+			assert.Equal(t, &hub.LimitExceededError{Limit: "webhooks per user", Max: 2}, err)
+			db.AssertExpectations(t)
+		})
+
+		t.Run("error counting user webhooks", func(t *testing.T) {
+			db := &tests.DBMock{}
+			db.On("QueryRow", ctx, countQuery, "userID").Return(nil, tests.ErrFakeDatabaseFailure)
+			m := NewManager(db, WithLimits(limits))
+
+			err := m.Add(ctx, "", wh)
+			assert.Equal(t, tests.ErrFakeDatabaseFailure, err)
+			db.AssertExpectations(t)
+		})
+
+		t.Run("limit not applied to organizations webhooks", func(t *testing.T) {
+			db := &tests.DBMock{}
+			db.On("Exec", ctx, dbQuery, "userID", "orgName", mock.Anything).Return(nil)
+			m := NewManager(db, WithLimits(limits))
+
+			err := m.Add(ctx, "orgName", wh)
+			assert.NoError(t, err)
+			db.AssertExpectations(t)
+		})
+	})
 }
 
 func TestDelete(t *testing.T) {
@@ -266,6 +349,21 @@ func TestGetJSON(t *testing.T) {
 		assert.Equal(t, []byte("dataJSON"), dataJSON)
 		db.AssertExpectations(t)
 	})
+
+	t.Run("webhook data with decrypted secret returned successfully", func(t *testing.T) {
+		sc := &secrets.CipherMock{}
+		sc.On("Decrypt", ctx, "encryptedSecret").Return("secret", nil)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "userID", validUUID).
+			Return([]byte(`{"name": "webhook1", "secret": "encryptedSecret"}`), nil)
+		m := NewManager(db, WithSecretsCipher(sc))
+
+		dataJSON, err := m.GetJSON(ctx, validUUID)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"name": "webhook1", "secret": "secret"}`, string(dataJSON))
+		db.AssertExpectations(t)
+		sc.AssertExpectations(t)
+	})
 }
 
 func TestGetOwnedByOrgJSON(t *testing.T) {
@@ -340,6 +438,36 @@ func TestGetOwnedByUserJSON(t *testing.T) {
 		assert.Equal(t, []byte("dataJSON"), dataJSON)
 		db.AssertExpectations(t)
 	})
+
+	t.Run("user webhooks data with decrypted secrets returned successfully", func(t *testing.T) {
+		sc := &secrets.CipherMock{}
+		sc.On("Decrypt", ctx, "encryptedSecret").Return("secret", nil)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "userID").
+			Return([]byte(`[{"name": "webhook1", "secret": "encryptedSecret"}, {"name": "webhook2"}]`), nil)
+		m := NewManager(db, WithSecretsCipher(sc))
+
+		dataJSON, err := m.GetOwnedByUserJSON(ctx)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `[{"name": "webhook1", "secret": "secret"}, {"name": "webhook2"}]`, string(dataJSON))
+		db.AssertExpectations(t)
+		sc.AssertExpectations(t)
+	})
+
+	t.Run("error decrypting secrets", func(t *testing.T) {
+		sc := &secrets.CipherMock{}
+		sc.On("Decrypt", ctx, "encryptedSecret").Return("", errFake)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, dbQuery, "userID").
+			Return([]byte(`[{"name": "webhook1", "secret": "encryptedSecret"}]`), nil)
+		m := NewManager(db, WithSecretsCipher(sc))
+
+		dataJSON, err := m.GetOwnedByUserJSON(ctx)
+		assert.Equal(t, errFake, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+		sc.AssertExpectations(t)
+	})
 }
 
 func TestGetSubscribedTo(t *testing.T) {
@@ -56,7 +56,7 @@ interface FetchOptions {
   body?: any;
 }
 
-const EXCEPTIONS = ['policies'];
+const EXCEPTIONS = ['policies', 'metadata'];
 
 export const toCamelCase = (r: any): Result => {
   if (isArray(r)) {
@@ -106,6 +106,9 @@ const RepositoryModal = (props: Props) => {
           url: formData.get('url') as string,
           displayName: formData.get('displayName') as string,
         };
+        if (!isUndefined(props.repository) && !isUndefined(props.repository.metadata)) {
+          repository.metadata = props.repository.metadata;
+        }
       }
       setIsValidated(true);
       return { isValid, repository };
@@ -270,7 +273,7 @@ const RepositoryModal = (props: Props) => {
       case RepositoryKind.Helm:
         return undefined;
       default:
-        return '(https://(github|gitlab).com/[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+)/?(.*)';
+        return '(https://(github|gitlab).com/[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+)/?(.*)|file:///.+';
     }
   };
 
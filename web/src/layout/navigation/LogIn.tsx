@@ -9,6 +9,7 @@ import { API } from '../../api';
 import { AppCtx, refreshUserProfile, signOut } from '../../context/AppCtx';
 import { ErrorKind, RefInputField, UserLogin } from '../../types';
 import compoundErrorMessage from '../../utils/compoundErrorMessage';
+import isInternalCatalog from '../../utils/isInternalCatalog';
 import InputField from '../common/InputField';
 import Modal from '../common/Modal';
 import styles from './LogIn.module.css';
@@ -133,66 +134,68 @@ const LogIn = (props: Props) => {
       error={apiError}
       cleanError={cleanApiError}
     >
-      <>
-        <form
-          ref={loginForm}
-          data-testid="loginForm"
-          className={classnames('w-100', { 'needs-validation': !isValidated }, { 'was-validated': isValidated })}
-          onFocus={cleanApiError}
-          autoComplete="on"
-          noValidate
-        >
-          <InputField
-            ref={emailInput}
-            type="email"
-            label="Email"
-            name="email"
-            value=""
-            invalidText={{
-              default: 'This field is required',
-              typeMismatch: 'Please enter a valid email address',
-            }}
-            autoComplete="email"
-            onChange={onEmailChange}
-            validateOnBlur={email !== ''}
-            required
-          />
-
-          <InputField
-            ref={passwordInput}
-            type="password"
-            label="Password"
-            name="password"
-            value=""
-            invalidText={{
-              default: 'This field is required',
-            }}
-            validateOnBlur
-            onKeyDown={handleOnReturnKeyDown}
-            autoComplete="current-password"
-            required
-          />
-
-          <div className="text-right">
-            <button
-              data-testid="logInBtn"
-              className="btn btn-secondary"
-              type="button"
-              disabled={isLoading.status}
-              onClick={submitForm}
-            >
-              {!isUndefined(isLoading.type) && isLoading.type === 'log' ? (
-                <>
-                  <span className="spinner-grow spinner-grow-sm" role="status" aria-hidden="true" />
-                  <span className="ml-2">Singing in...</span>
-                </>
-              ) : (
-                <>Sign in</>
-              )}
-            </button>
-          </div>
-        </form>
-      </>
+      {!isInternalCatalog() && (
+        <>
+          <form
+            ref={loginForm}
+            data-testid="loginForm"
+            className={classnames('w-100', { 'needs-validation': !isValidated }, { 'was-validated': isValidated })}
+            onFocus={cleanApiError}
+            autoComplete="on"
+            noValidate
+          >
+            <InputField
+              ref={emailInput}
+              type="email"
+              label="Email"
+              name="email"
+              value=""
+              invalidText={{
+                default: 'This field is required',
+                typeMismatch: 'Please enter a valid email address',
+              }}
+              autoComplete="email"
+              onChange={onEmailChange}
+              validateOnBlur={email !== ''}
+              required
+            />
+
+            <InputField
+              ref={passwordInput}
+              type="password"
+              label="Password"
+              name="password"
+              value=""
+              invalidText={{
+                default: 'This field is required',
+              }}
+              validateOnBlur
+              onKeyDown={handleOnReturnKeyDown}
+              autoComplete="current-password"
+              required
+            />
+
+            <div className="text-right">
+              <button
+                data-testid="logInBtn"
+                className="btn btn-secondary"
+                type="button"
+                disabled={isLoading.status}
+                onClick={submitForm}
+              >
+                {!isUndefined(isLoading.type) && isLoading.type === 'log' ? (
+                  <>
+                    <span className="spinner-grow spinner-grow-sm" role="status" aria-hidden="true" />
+                    <span className="ml-2">Singing in...</span>
+                  </>
+                ) : (
+                  <>Sign in</>
+                )}
+              </button>
+            </div>
+          </form>
+        </>
+      )}
 
       <OAuth isLoading={isLoading} setIsLoading={setIsLoading} />
     </Modal>
@@ -7,6 +7,7 @@ import { GoThreeBars } from 'react-icons/go';
 import { Link } from 'react-router-dom';
 
 import { AppCtx } from '../../context/AppCtx';
+import isInternalCatalog from '../../utils/isInternalCatalog';
 import Image from '../common/Image';
 import Sidebar from '../common/Sidebar';
 import LogOut from './LogOut';
@@ -134,15 +135,17 @@ const MobileSettings = (props: Props) => {
                       Sign in
                     </button>
 
-                    <button
-                      className="dropdown-item my-2"
-                      onClick={() => {
-                        setOpenSideBarStatus(false);
-                        props.setOpenSignUp(true);
-                      }}
-                    >
-                      Sign up
-                    </button>
+                    {!isInternalCatalog() && (
+                      <button
+                        className="dropdown-item my-2"
+                        onClick={() => {
+                          setOpenSideBarStatus(false);
+                          props.setOpenSignUp(true);
+                        }}
+                      >
+                        Sign up
+                      </button>
+                    )}
                   </>
                 )}
               </>
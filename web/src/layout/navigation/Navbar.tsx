@@ -6,6 +6,7 @@ import { FiHexagon } from 'react-icons/fi';
 import { Link } from 'react-router-dom';
 
 import { AppCtx } from '../../context/AppCtx';
+import isInternalCatalog from '../../utils/isInternalCatalog';
 import SearchBar from '../common/SearchBar';
 import GuestDropdown from './GuestDropdown';
 import LogIn from './LogIn';
@@ -82,18 +83,20 @@ const Navbar = (props: Props) => {
               <>
                 {isNull(ctx.user) ? (
                   <>
-                    <li className="nav-item position-relative ml-4">
-                      <button
-                        type="button"
-                        className={classnames(
-                          'btn navbarBtn pl-0 pr-0 font-weight-bold text-uppercase position-relative text-nowrap',
-                          styles.button
-                        )}
-                        onClick={() => setOpenSignUp(true)}
-                      >
-                        Sign up
-                      </button>
-                    </li>
+                    {!isInternalCatalog() && (
+                      <li className="nav-item position-relative ml-4">
+                        <button
+                          type="button"
+                          className={classnames(
+                            'btn navbarBtn pl-0 pr-0 font-weight-bold text-uppercase position-relative text-nowrap',
+                            styles.button
+                          )}
+                          onClick={() => setOpenSignUp(true)}
+                        >
+                          Sign up
+                        </button>
+                      </li>
+                    )}
 
                     <li className="nav-item ml-4 position-relative">
                       <button
@@ -1,4 +1,4 @@
-import { render } from '@testing-library/react';
+import { fireEvent, render } from '@testing-library/react';
 import React from 'react';
 
 import { Repository } from '../../types';
@@ -35,6 +35,24 @@ describe('HelmInstall', () => {
       expect(helmLink).toHaveProperty('href', 'https://helm.sh/docs/intro/quickstart/');
     });
 
+    it('renders values presets', () => {
+      const { getByLabelText, getByText, queryByText } = render(
+        <HelmInstall
+          {...defaultProps}
+          valuesPresets={[{ name: 'ha', description: 'High availability', values: 'replicaCount: 3' }]}
+        />
+      );
+
+      expect(queryByText('replicaCount: 3')).toBeNull();
+      fireEvent.change(getByLabelText('Values preset'), { target: { value: 'ha' } });
+
+      expect(getByText('values-ha.yaml')).toBeInTheDocument();
+      expect(getByText('replicaCount: 3')).toBeInTheDocument();
+      expect(
+        getByText(`helm install ${repo.name}/${defaultProps.name} --version ${defaultProps.version} -f values-ha.yaml`)
+      );
+    });
+
     it('does not render content when version is undefined', () => {
       const { container } = render(<HelmInstall {...defaultProps} version={undefined} />);
       expect(container).toBeEmpty();
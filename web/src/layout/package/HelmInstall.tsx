@@ -4,7 +4,7 @@ import React, { useState } from 'react';
 import SyntaxHighlighter from 'react-syntax-highlighter';
 import { docco } from 'react-syntax-highlighter/dist/cjs/styles/hljs';
 
-import { Repository } from '../../types';
+import { Repository, ValuesPreset } from '../../types';
 import ButtonCopyToClipboard from '../common/ButtonCopyToClipboard';
 import ExternalLink from '../common/ExternalLink';
 import NoData from '../common/NoData';
@@ -14,6 +14,7 @@ interface Props {
   name: string;
   version?: string;
   repository: Repository;
+  valuesPresets?: ValuesPreset[];
 }
 
 interface Tab {
@@ -31,6 +32,7 @@ const ACTIVE_TAB: string = 'cli';
 
 const HelmInstall = (props: Props) => {
   const [activeTab, setActiveTab] = useState(ACTIVE_TAB);
+  const [activePreset, setActivePreset] = useState<string>('');
 
   if (isUndefined(props.version)) return null;
 
@@ -56,7 +58,11 @@ const HelmInstall = (props: Props) => {
           switch (activeTab) {
             case 'cli':
               const block1 = `helm repo add ${props.repository.name} ${props.repository.url}`;
-              const block2 = `helm install ${props.repository.name}/${props.name} --version ${props.version}`;
+              const preset = (props.valuesPresets || []).find((p: ValuesPreset) => p.name === activePreset);
+              const presetFile = preset ? `values-${preset.name}.yaml` : '';
+              const block2 = `helm install ${props.repository.name}/${props.name} --version ${props.version}${
+                preset ? ` -f ${presetFile}` : ''
+              }`;
 
               return (
                 <div className="tab-pane fade show active">
@@ -77,6 +83,51 @@ const HelmInstall = (props: Props) => {
                     {block1}
                   </SyntaxHighlighter>
 
+                  {props.valuesPresets && props.valuesPresets.length > 0 && (
+                    <>
+                      <div className="d-flex align-items-center justify-content-between mt-2 mb-2">
+                        <small className="text-muted mt-2 mb-1">Values preset</small>
+                      </div>
+
+                      <select
+                        className="custom-select custom-select-sm"
+                        aria-label="Values preset"
+                        value={activePreset}
+                        onChange={(e: React.ChangeEvent<HTMLSelectElement>) => setActivePreset(e.target.value)}
+                      >
+                        <option value="">Default values</option>
+                        {props.valuesPresets.map((p: ValuesPreset) => (
+                          <option key={p.name} value={p.name}>
+                            {p.description ? `${p.name} - ${p.description}` : p.name}
+                          </option>
+                        ))}
+                      </select>
+
+                      {preset && (
+                        <>
+                          <div className="d-flex align-items-center justify-content-between mt-2 mb-2">
+                            <small className="text-muted mt-2 mb-1">
+                              Save preset values to <code>{presetFile}</code>
+                            </small>
+                            <div>
+                              <ButtonCopyToClipboard text={preset.values} />
+                            </div>
+                          </div>
+
+                          <SyntaxHighlighter
+                            language="yaml"
+                            style={docco}
+                            customStyle={{
+                              backgroundColor: 'var(--color-1-10)',
+                            }}
+                          >
+                            {preset.values}
+                          </SyntaxHighlighter>
+                        </>
+                      )}
+                    </>
+                  )}
+
                   <div className="d-flex align-items-center justify-content-between mt-2 mb-2">
                     <small className="text-muted mt-2 mb-1">Install chart</small>
                     <div>
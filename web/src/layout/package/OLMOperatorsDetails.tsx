@@ -24,7 +24,9 @@ interface Props {
 const OLMOperatorsDetails = (props: Props) => {
   const getCapabilityLevel = (): string | undefined => {
     let level: string | undefined;
-    if (
+    if (props.package.capabilities) {
+      level = props.package.capabilities;
+    } else if (
       !isNull(props.package.data) &&
       !isUndefined(props.package.data) &&
       !isUndefined(props.package.data.capabilities)
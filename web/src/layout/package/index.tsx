@@ -175,7 +175,14 @@ const PackageView = (props: Props) => {
           {(() => {
             switch (detail!.repository.kind) {
               case RepositoryKind.Helm:
-                return <HelmInstall name={detail.name} version={detail.version} repository={detail.repository} />;
+                return (
+                  <HelmInstall
+                    name={detail.name}
+                    version={detail.version}
+                    repository={detail.repository}
+                    valuesPresets={detail.data ? detail.data.valuesPresets : undefined}
+                  />
+                );
               case RepositoryKind.Falco:
                 return <FalcoInstall normalizedName={detail.normalizedName!} />;
               case RepositoryKind.OPA:
@@ -357,6 +364,11 @@ const PackageView = (props: Props) => {
                               Deprecated
                             </div>
                           )}
+                          {detail.seekingMaintainers && (
+                            <div className={`badge badge-pill text-uppercase ml-3 mt-1 ${styles.seekingMaintainersBadge}`}>
+                              Seeking maintainers
+                            </div>
+                          )}
                           <SignedBadge
                             repositoryKind={detail.repository.kind}
                             signed={detail.signed}
@@ -16,6 +16,7 @@ export interface Repository {
   kind: RepositoryKind;
   lastTrackingTs?: number | null;
   lastTrackingErrors?: string | null;
+  metadata?: { [key: string]: string } | null;
 }
 
 export interface Maintainer {
@@ -51,6 +52,7 @@ export interface Package {
   keywords?: string[];
   maintainers?: Maintainer[];
   deprecated: boolean | null;
+  seekingMaintainers?: boolean;
   isOperator?: boolean | null;
   signed: boolean | null;
   links?: PackageLink[];
@@ -62,6 +64,7 @@ export interface Package {
   channels?: Channel[] | null;
   provider?: string | null;
   containerImage?: string | null;
+  capabilities?: string | null;
 }
 
 export interface Version {
@@ -102,6 +105,13 @@ export interface PackageData {
   customResourcesDefinitionsExamples?: string;
   customResourcesDefinitions?: CustomResourcesDefinition[];
   isGlobalOperator?: boolean;
+  valuesPresets?: ValuesPreset[];
+}
+
+export interface ValuesPreset {
+  name: string;
+  description?: string;
+  values: string;
 }
 
 export interface OPAPolicies {
@@ -0,0 +1,22 @@
+import isInternalCatalog from './isInternalCatalog';
+
+const tests = [
+  { hubConfig: undefined, result: false },
+  { hubConfig: { internalCatalog: '{{ .internalCatalog }}' }, result: false },
+  { hubConfig: { internalCatalog: 'false' }, result: false },
+  { hubConfig: { internalCatalog: 'true' }, result: true },
+];
+
+describe('isInternalCatalog', () => {
+  afterEach(() => {
+    delete (window as any).hubConfig;
+  });
+
+  for (let i = 0; i < tests.length; i++) {
+    it('returns correct value', () => {
+      (window as any).hubConfig = tests[i].hubConfig;
+      const actual = isInternalCatalog();
+      expect(actual).toBe(tests[i].result);
+    });
+  }
+});
@@ -0,0 +1,8 @@
+import isUndefined from 'lodash/isUndefined';
+
+// Returns true when the hub runs in the internal catalog mode, where signup is
+// disabled and users can only sign in using the configured oauth providers.
+export default (): boolean => {
+  const hubConfig = (window as any).hubConfig;
+  return !isUndefined(hubConfig) && hubConfig.internalCatalog === 'true';
+};